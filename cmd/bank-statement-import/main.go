@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/config"
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres"
+	"github.com/yaroslavnayug/go-payment-system/internal/reconciliation"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+// Imports ISO 20022 camt.053 or SWIFT MT940 bank statements file and auto-matches its entries to movements
+func main() {
+	filePath := flag.String("file", "", "path to statements file")
+	format := flag.String("format", "", "file format: camt053 or mt940, camt053 for .xml files and mt940 otherwise")
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic(fmt.Sprintf("unable to create logger: %s", err.Error()))
+	}
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	if *format == "" {
+		*format = string(reconciliation.FormatMT940)
+		if strings.ToLower(filepath.Ext(*filePath)) == ".xml" {
+			*format = string(reconciliation.FormatCamt053)
+		}
+	}
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("unable to open statements file: %s", err.Error()))
+	}
+	defer file.Close()
+
+	statements, err := reconciliation.ParseStatements(reconciliation.Format(*format), file)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("unable to parse statements file: %s", err.Error()))
+	}
+
+	cfg := config.Read()
+	postgresConnection := postgres.MustConnect(cfg, logger)
+	defer postgresConnection.Close()
+
+	reconciliationUseCase := usecase.NewReconciliationUseCase(
+		postgres.NewReconciliationRepository(postgresConnection),
+		reconciliation.NewMatcher(reconciliation.DefaultDateTolerance),
+		cfg.PayoutConfig.DebtorIBAN,
+	)
+	result, err := reconciliationUseCase.Import(statements)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("unable to import statements: %s", err.Error()))
+	}
+	if len(result.Duplicates) > 0 {
+		logger.Warn(fmt.Sprintf("skipped statements imported before: %s", strings.Join(result.Duplicates, ", ")))
+	}
+	logger.Info(fmt.Sprintf("imported %d statements, matched %d entries", len(statements), result.Matched))
+}
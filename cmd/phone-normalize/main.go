@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/config"
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres"
+	"github.com/yaroslavnayug/go-payment-system/internal/screening"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+// Rewrites phones of existing customers in E.164 format, customers with invalid or duplicated phones are
+// reported to be fixed manually before creating unique index on customer phone
+func main() {
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic(fmt.Sprintf("unable to create logger: %s", err.Error()))
+	}
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	postgresConnection := postgres.MustConnect(config.Read(), logger)
+	defer postgresConnection.Close()
+
+	customerUseCase := usecase.NewCustomerUseCase(
+		postgres.NewCustomerRepository(postgresConnection),
+		postgres.NewSanctionRepository(postgresConnection),
+		screening.NewMatcher(screening.DefaultThreshold),
+	)
+	report, err := customerUseCase.NormalizePhones()
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("unable to normalize phones: %s", err.Error()))
+	}
+	logger.Info(fmt.Sprintf("normalized phones of %d customers", report.Updated))
+	if len(report.Invalid) > 0 {
+		logger.Warn(fmt.Sprintf("customers with invalid phones: %s", strings.Join(report.Invalid, ", ")))
+	}
+	if len(report.Duplicated) > 0 {
+		logger.Warn(fmt.Sprintf("customers with duplicated phones: %s", strings.Join(report.Duplicated, ", ")))
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/config"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres"
+	"github.com/yaroslavnayug/go-payment-system/internal/screening"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+// Imports sanctions or PEP list from CSV or XML file replacing previously imported list with the same name
+func main() {
+	filePath := flag.String("file", "", "path to list file")
+	format := flag.String("format", "", "list format: csv or xml, detected by file extension when empty")
+	listName := flag.String("list", "", "list name, e.g. rosfinmonitoring")
+	listType := flag.String("type", string(domain.SanctionListTypeSanctions), "list type: sanctions or pep")
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		panic(fmt.Sprintf("unable to create logger: %s", err.Error()))
+	}
+	defer func() {
+		_ = logger.Sync()
+	}()
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*filePath)), ".")
+	}
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("unable to open list file: %s", err.Error()))
+	}
+	defer file.Close()
+
+	var entries []domain.SanctionEntry
+	switch *format {
+	case "csv":
+		entries, err = screening.ParseCSV(file)
+	case "xml":
+		entries, err = screening.ParseXML(file)
+	default:
+		logger.Fatal(fmt.Sprintf("unsupported list format: %s", *format))
+	}
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("unable to parse list file: %s", err.Error()))
+	}
+
+	postgresConnection := postgres.MustConnect(config.Read(), logger)
+	defer postgresConnection.Close()
+
+	screeningUseCase := usecase.NewScreeningUseCase(
+		postgres.NewCustomerRepository(postgresConnection),
+		postgres.NewSanctionRepository(postgresConnection),
+	)
+	err = screeningUseCase.ImportList(*listName, domain.SanctionListType(*listType), entries)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("unable to import list: %s", err.Error()))
+	}
+	logger.Info(fmt.Sprintf("imported %d entries of list %s", len(entries), *listName))
+}
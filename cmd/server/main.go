@@ -1,22 +1,36 @@
 package main
 
 import (
-	"context"
+	"encoding/hex"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/buaazp/fasthttprouter"
-	"github.com/jackc/pgx/v4"
-	"github.com/jackc/pgx/v4/log/zapadapter"
-	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/beneficiary"
+	"github.com/yaroslavnayug/go-payment-system/internal/billing"
+	"github.com/yaroslavnayug/go-payment-system/internal/blob"
 	"github.com/yaroslavnayug/go-payment-system/internal/config"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
 	"github.com/yaroslavnayug/go-payment-system/internal/handler/v1.0"
+	"github.com/yaroslavnayug/go-payment-system/internal/iso20022"
+	"github.com/yaroslavnayug/go-payment-system/internal/iso8583"
+	"github.com/yaroslavnayug/go-payment-system/internal/issuing"
+	"github.com/yaroslavnayug/go-payment-system/internal/kyc"
+	"github.com/yaroslavnayug/go-payment-system/internal/monitoring"
+	"github.com/yaroslavnayug/go-payment-system/internal/p2p"
 	"github.com/yaroslavnayug/go-payment-system/internal/postgres"
+	"github.com/yaroslavnayug/go-payment-system/internal/reconciliation"
+	"github.com/yaroslavnayug/go-payment-system/internal/risk"
+	"github.com/yaroslavnayug/go-payment-system/internal/scheduler"
+	"github.com/yaroslavnayug/go-payment-system/internal/screening"
 	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"github.com/yaroslavnayug/go-payment-system/internal/vault"
 	"go.uber.org/zap"
 )
 
@@ -29,7 +43,7 @@ func main() {
 	}()
 	logger.Info(fmt.Sprintf("starting service with config %+v", cfg))
 
-	postgresConnection := MustPostgres(cfg, logger)
+	postgresConnection := postgres.MustConnect(cfg, logger)
 	defer func() {
 		if r := recover(); r != nil {
 			logger.Error(fmt.Sprintf("app crashed & recovered with: %+v", r))
@@ -39,20 +53,454 @@ func main() {
 		postgresConnection.Close()
 	}()
 
-	repository := postgres.NewCustomerRepository(postgresConnection)
-	customerUseCase := usecase.NewCustomerUseCase(repository)
+	customerRepository := postgres.NewCustomerRepository(postgresConnection)
+	beneficiaryRepository := postgres.NewBeneficiaryRepository(postgresConnection)
+	sanctionRepository := postgres.NewSanctionRepository(postgresConnection)
+	customerUseCase := usecase.NewCustomerUseCase(
+		customerRepository,
+		sanctionRepository,
+		screening.NewMatcher(screening.DefaultThreshold),
+	)
 	customerHandler := v1.NewCustomerHandlerV1(
 		logger.With(zap.String("handler", "customerV1")),
 		customerUseCase,
 		v1.NewJSONResponseWriter(logger),
 	)
 
+	verificationRepository := postgres.NewVerificationRepository(postgresConnection)
+	verificationUseCase := usecase.NewVerificationUseCase(
+		verificationRepository,
+		customerRepository,
+		kyc.DefaultChecks(),
+	)
+	verificationHandler := v1.NewVerificationHandlerV1(
+		logger.With(zap.String("handler", "verificationV1")),
+		verificationUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	screeningUseCase := usecase.NewScreeningUseCase(customerRepository, sanctionRepository)
+	screeningHandler := v1.NewScreeningHandlerV1(
+		logger.With(zap.String("handler", "screeningV1")),
+		screeningUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	ruleSet := MustRuleSet(cfg, logger)
+	stopWatchingRules := make(chan struct{})
+	go ruleSet.Watch(stopWatchingRules, 10*time.Second, func(err error) {
+		if err != nil {
+			logger.Error(fmt.Sprintf("unable to reload monitoring rules, keep previous ones: %s", err.Error()))
+			return
+		}
+		logger.Info("monitoring rules reloaded")
+	})
+	defer close(stopWatchingRules)
+
+	monitoringRepository := postgres.NewMonitoringRepository(postgresConnection)
+	monitoringUseCase := usecase.NewMonitoringUseCase(
+		monitoringRepository,
+		customerRepository,
+		ruleSet,
+		logger.With(zap.String("usecase", "monitoring")),
+	)
+	monitoringHandler := v1.NewMonitoringHandlerV1(
+		logger.With(zap.String("handler", "monitoringV1")),
+		monitoringUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	riskRepository := postgres.NewRiskRepository(postgresConnection)
+	riskUseCase := usecase.NewRiskUseCase(
+		riskRepository,
+		customerRepository,
+		risk.NewScorer(riskThresholds(cfg)),
+	)
+	riskHandler := v1.NewRiskHandlerV1(
+		logger.With(zap.String("handler", "riskV1")),
+		riskUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	limitRepository := postgres.NewLimitRepository(postgresConnection)
+	limitUseCase := usecase.NewLimitUseCase(limitRepository, verificationRepository, customerRepository)
+	limitHandler := v1.NewLimitHandlerV1(
+		logger.With(zap.String("handler", "limitV1")),
+		limitUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	feeUseCase := usecase.NewFeeUseCase(postgres.NewFeeRepository(postgresConnection))
+	feeHandler := v1.NewFeeHandlerV1(
+		logger.With(zap.String("handler", "feeV1")),
+		feeUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	ledgerUseCase := usecase.NewLedgerUseCase(
+		postgres.NewLedgerRepository(postgresConnection),
+		verificationUseCase,
+		limitUseCase,
+		feeUseCase,
+		monitoringUseCase,
+	)
+
+	paymentScheduleUseCase := usecase.NewPaymentScheduleUseCase(
+		postgres.NewPaymentScheduleRepository(postgresConnection),
+		customerRepository,
+		ledgerUseCase,
+	)
+	paymentScheduleHandler := v1.NewPaymentScheduleHandlerV1(
+		logger.With(zap.String("handler", "paymentScheduleV1")),
+		paymentScheduleUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	subscriptionUseCase := usecase.NewSubscriptionUseCase(
+		postgres.NewSubscriptionRepository(postgresConnection),
+		customerRepository,
+		ledgerUseCase,
+		billing.DefaultDunningPolicy,
+	)
+	subscriptionHandler := v1.NewSubscriptionHandlerV1(
+		logger.With(zap.String("handler", "subscriptionV1")),
+		subscriptionUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	invoiceUseCase := usecase.NewInvoiceUseCase(
+		postgres.NewInvoiceRepository(postgresConnection),
+		customerRepository,
+		ledgerUseCase,
+		riskUseCase,
+	)
+	invoiceHandler := v1.NewInvoiceHandlerV1(
+		logger.With(zap.String("handler", "invoiceV1")),
+		invoiceUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	statementUseCase := usecase.NewStatementUseCase(
+		postgres.NewPostingRepository(postgresConnection),
+		customerRepository,
+	)
+	statementHandler := v1.NewStatementHandlerV1(
+		logger.With(zap.String("handler", "statementV1")),
+		statementUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	reconciliationUseCase := usecase.NewReconciliationUseCase(
+		postgres.NewReconciliationRepository(postgresConnection),
+		reconciliation.NewMatcher(reconciliation.DefaultDateTolerance),
+		cfg.PayoutConfig.DebtorIBAN,
+	)
+	reconciliationHandler := v1.NewReconciliationHandlerV1(
+		logger.With(zap.String("handler", "reconciliationV1")),
+		reconciliationUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	payoutUseCase := usecase.NewPayoutUseCase(
+		postgres.NewPayoutRepository(postgresConnection),
+		customerRepository,
+		beneficiaryRepository,
+		ledgerUseCase,
+		riskUseCase,
+		beneficiary.DefaultCoolingOffPolicy,
+		iso20022.Party{
+			Name: cfg.PayoutConfig.DebtorName,
+			IBAN: cfg.PayoutConfig.DebtorIBAN,
+			BIC:  cfg.PayoutConfig.DebtorBIC,
+		},
+	)
+	payoutHandler := v1.NewPayoutHandlerV1(
+		logger.With(zap.String("handler", "payoutV1")),
+		payoutUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	qrPaymentUseCase := usecase.NewQRPaymentUseCase(
+		postgres.NewQRPaymentRepository(postgresConnection),
+		customerRepository,
+		ledgerUseCase,
+		riskUseCase,
+	)
+	qrPaymentHandler := v1.NewQRPaymentHandlerV1(
+		logger.With(zap.String("handler", "qrPaymentV1")),
+		qrPaymentUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	p2pUseCase := usecase.NewP2PUseCase(
+		postgres.NewP2PRepository(postgresConnection),
+		customerRepository,
+		beneficiaryRepository,
+		ledgerUseCase,
+		riskUseCase,
+		p2p.DefaultLookupPolicy,
+		beneficiary.DefaultCoolingOffPolicy,
+	)
+	p2pHandler := v1.NewP2PHandlerV1(
+		logger.With(zap.String("handler", "p2pV1")),
+		p2pUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	beneficiaryHandler := v1.NewBeneficiaryHandlerV1(
+		logger.With(zap.String("handler", "beneficiaryV1")),
+		usecase.NewBeneficiaryUseCase(beneficiaryRepository, customerRepository, beneficiary.DefaultCoolingOffPolicy),
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	escrowUseCase := usecase.NewEscrowUseCase(
+		postgres.NewEscrowRepository(postgresConnection),
+		customerRepository,
+		ledgerUseCase,
+		riskUseCase,
+	)
+	escrowHandler := v1.NewEscrowHandlerV1(
+		logger.With(zap.String("handler", "escrowV1")),
+		escrowUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	splitPaymentHandler := v1.NewSplitPaymentHandlerV1(
+		logger.With(zap.String("handler", "splitPaymentV1")),
+		usecase.NewSplitPaymentUseCase(
+			postgres.NewSplitPaymentRepository(postgresConnection),
+			customerRepository,
+			ledgerUseCase,
+			riskUseCase,
+		),
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	blobStore := MustBlobStore(cfg, logger)
+
+	disputeUseCase := usecase.NewDisputeUseCase(
+		postgres.NewDisputeRepository(postgresConnection),
+		postgres.NewQRPaymentRepository(postgresConnection),
+		postgres.NewPostingRepository(postgresConnection),
+		blobStore,
+	)
+	disputeHandler := v1.NewDisputeHandlerV1(
+		logger.With(zap.String("handler", "disputeV1")),
+		disputeUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	depositUseCase := usecase.NewDepositUseCase(
+		postgres.NewDepositRepository(postgresConnection),
+		customerRepository,
+		verificationUseCase,
+	)
+	depositHandler := v1.NewDepositHandlerV1(
+		logger.With(zap.String("handler", "depositV1")),
+		depositUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	loanUseCase := usecase.NewLoanUseCase(
+		postgres.NewLoanRepository(postgresConnection),
+		customerRepository,
+		verificationUseCase,
+	)
+	loanHandler := v1.NewLoanHandlerV1(
+		logger.With(zap.String("handler", "loanV1")),
+		loanUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	creditLineUseCase := usecase.NewCreditLineUseCase(
+		postgres.NewCreditLineRepository(postgresConnection),
+		customerRepository,
+	)
+	creditLineHandler := v1.NewCreditLineHandlerV1(
+		logger.With(zap.String("handler", "creditLineV1")),
+		creditLineUseCase,
+		v1.NewJSONResponseWriter(logger),
+	)
+
+	jobs := append(
+		scheduler.PaymentScheduleJobs(paymentScheduleUseCase),
+		scheduler.SubscriptionJobs(subscriptionUseCase)...,
+	)
+	jobs = append(jobs, scheduler.EscrowJobs(escrowUseCase)...)
+	jobs = append(jobs, scheduler.DisputeJobs(disputeUseCase)...)
+	jobs = append(jobs, scheduler.DepositJobs(depositUseCase)...)
+	jobs = append(jobs, scheduler.LoanJobs(loanUseCase)...)
+	jobs = append(jobs, scheduler.CreditLineJobs(creditLineUseCase)...)
+	if cfg.PayoutConfig.DebtorIBAN != "" {
+		jobs = append(jobs, scheduler.PayoutJobs(payoutUseCase)...)
+	} else {
+		logger.Warn("payout debtor account is not configured, pending payouts are not submitted")
+	}
+	worker := scheduler.NewWorker(logger.With(zap.String("worker", "scheduler")), time.Minute, jobs...)
+	stopWorker := make(chan struct{})
+	go worker.Run(stopWorker)
+	defer close(stopWorker)
+
 	// Assign handlers
 	router := fasthttprouter.New()
 	router.POST("/customer", customerHandler.Create)
 	router.GET("/customer/:id", customerHandler.Find)
 	router.PUT("/customer/:id", customerHandler.Update)
 	router.DELETE("/customer/:id", customerHandler.Delete)
+	router.POST("/customer/:id/verification", verificationHandler.Submit)
+	router.GET("/customer/:id/verification", verificationHandler.Find)
+	router.PUT("/verification/:id", verificationHandler.Review)
+	router.GET("/customer/:id/screening", screeningHandler.Matches)
+	router.PUT("/customer/:id/screening", screeningHandler.Resolve)
+	router.GET("/screening/queue", screeningHandler.Queue)
+	router.GET("/monitoring/alerts", monitoringHandler.FindAlerts)
+	router.GET("/monitoring/alerts/:id", monitoringHandler.FindAlert)
+	router.PUT("/monitoring/alerts/:id", monitoringHandler.UpdateAlert)
+	router.POST("/customer/:id/risk", riskHandler.Assess)
+	router.GET("/risk/:id", riskHandler.Find)
+	router.GET("/customer/:id/limits", limitHandler.Find)
+	router.PUT("/customer/:id/limits", limitHandler.SetOverride)
+	router.DELETE("/customer/:id/limits", limitHandler.DeleteOverride)
+	router.POST("/fees/quote", feeHandler.Quote)
+	router.POST("/fees/schedules", feeHandler.CreateSchedule)
+	router.GET("/fees/schedules/:version", feeHandler.FindSchedule)
+	router.POST("/customer/:id/schedules", paymentScheduleHandler.Create)
+	router.GET("/customer/:id/schedules", paymentScheduleHandler.FindByCustomer)
+	router.DELETE("/schedules/:id", paymentScheduleHandler.Cancel)
+	router.GET("/schedules/:id/executions", paymentScheduleHandler.FindExecutions)
+	router.POST("/plans", subscriptionHandler.CreatePlan)
+	router.GET("/plans", subscriptionHandler.FindPlans)
+	router.GET("/plans/:id", subscriptionHandler.FindPlan)
+	router.POST("/customer/:id/subscriptions", subscriptionHandler.Subscribe)
+	router.GET("/customer/:id/subscriptions", subscriptionHandler.FindByCustomer)
+	router.GET("/subscriptions/:id", subscriptionHandler.Find)
+	router.PUT("/subscriptions/:id/plan", subscriptionHandler.ChangePlan)
+	router.DELETE("/subscriptions/:id", subscriptionHandler.Cancel)
+	router.GET("/subscriptions/:id/invoices", subscriptionHandler.FindInvoices)
+	router.POST("/invoices", invoiceHandler.Create)
+	router.GET("/invoices/:id", invoiceHandler.Find)
+	router.PUT("/invoices/:id", invoiceHandler.Update)
+	router.POST("/invoices/:id/finalize", invoiceHandler.Finalize)
+	router.POST("/invoices/:id/void", invoiceHandler.Void)
+	router.POST("/invoices/:id/pay", invoiceHandler.Pay)
+	router.GET("/customer/:id/invoices", invoiceHandler.FindByCustomer)
+	router.GET("/customer/:id/statement", statementHandler.Find)
+	router.POST("/reconciliation/statements", reconciliationHandler.Import)
+	router.GET("/reconciliation/report", reconciliationHandler.Report)
+	router.POST("/reconciliation/entries/:id/match", reconciliationHandler.Match)
+	router.POST("/customer/:id/payouts", payoutHandler.Create)
+	router.GET("/customer/:id/payouts", payoutHandler.FindByCustomer)
+	router.GET("/payouts/:id", payoutHandler.Find)
+	router.POST("/payouts/status-reports", payoutHandler.ApplyStatusReport)
+	router.GET("/payout-batches/:id", payoutHandler.FindBatch)
+	router.POST("/customer/:id/qr-payment-requests", qrPaymentHandler.CreateRequest)
+	router.GET("/customer/:id/qr-payment-requests", qrPaymentHandler.FindRequestsByMerchant)
+	router.GET("/qr-payment-requests/:id", qrPaymentHandler.FindRequest)
+	router.GET("/qr-payment-requests/:id/payments", qrPaymentHandler.FindPayments)
+	router.POST("/customer/:id/qr-payments", qrPaymentHandler.Pay)
+	router.GET("/customer/:id/p2p/recipient", p2pHandler.FindRecipient)
+	router.POST("/customer/:id/p2p-transfers", p2pHandler.Transfer)
+	router.GET("/p2p-transfers/:id", p2pHandler.FindTransfer)
+	router.POST("/customer/:id/escrows", escrowHandler.Create)
+	router.GET("/customer/:id/escrows", escrowHandler.FindByMerchant)
+	router.GET("/escrows/:id", escrowHandler.Find)
+	router.POST("/escrows/:id/release", escrowHandler.Release)
+	router.POST("/escrows/:id/refund", escrowHandler.Refund)
+	router.POST("/escrows/:id/dispute", escrowHandler.Dispute)
+	router.POST("/escrows/:id/resolve", escrowHandler.Resolve)
+	router.POST("/customer/:id/split-payments", splitPaymentHandler.Create)
+	router.GET("/split-payments/:id", splitPaymentHandler.Find)
+	router.GET("/customer/:id/received-splits", splitPaymentHandler.FindReceived)
+	router.POST("/customer/:id/disputes", disputeHandler.Open)
+	router.GET("/customer/:id/disputes", disputeHandler.FindByMerchant)
+	router.GET("/disputes/:id", disputeHandler.Find)
+	router.POST("/disputes/:id/evidence", disputeHandler.AddEvidence)
+	router.GET("/disputes/:id/evidence", disputeHandler.FindEvidence)
+	router.GET("/disputes/:id/evidence/:evidence_id", disputeHandler.DownloadEvidence)
+	router.POST("/disputes/:id/submit", disputeHandler.Submit)
+	router.POST("/disputes/:id/accept", disputeHandler.Accept)
+	router.POST("/disputes/:id/resolve", disputeHandler.Resolve)
+
+	router.POST("/deposit-products", depositHandler.CreateProduct)
+	router.GET("/deposit-products", depositHandler.FindProducts)
+	router.GET("/deposit-products/:id/projection", depositHandler.ProjectProduct)
+	router.POST("/customer/:id/deposits", depositHandler.Open)
+	router.GET("/customer/:id/deposits", depositHandler.FindByCustomer)
+	router.GET("/deposits/:id", depositHandler.Find)
+	router.GET("/deposits/:id/interest", depositHandler.FindInterest)
+	router.GET("/deposits/:id/projection", depositHandler.Project)
+	router.POST("/deposits/:id/close", depositHandler.Close)
+
+	router.POST("/loan-products", loanHandler.CreateProduct)
+	router.GET("/loan-products", loanHandler.FindProducts)
+	router.POST("/customer/:id/loan-applications", loanHandler.Apply)
+	router.GET("/customer/:id/loan-applications", loanHandler.FindApplicationsByCustomer)
+	router.GET("/loan-applications/:id", loanHandler.FindApplication)
+	router.POST("/loan-applications/:id/disburse", loanHandler.Disburse)
+	router.GET("/customer/:id/loans", loanHandler.FindByCustomer)
+	router.GET("/loans/:id", loanHandler.Find)
+	router.GET("/loans/:id/repayments", loanHandler.FindRepayments)
+	router.POST("/loans/:id/repay", loanHandler.Repay)
+
+	router.GET("/customer/:id/credit-lines", creditLineHandler.FindByCustomer)
+	router.GET("/customer/:id/credit-lines/:currency", creditLineHandler.Find)
+	router.PUT("/customer/:id/credit-lines/:currency", creditLineHandler.Approve)
+
+	router.POST("/customer/:id/beneficiaries", beneficiaryHandler.Create)
+	router.GET("/customer/:id/beneficiaries", beneficiaryHandler.FindByCustomer)
+	router.GET("/customer/:id/beneficiaries/:beneficiary_id", beneficiaryHandler.Find)
+	router.PUT("/customer/:id/beneficiaries/:beneficiary_id/nickname", beneficiaryHandler.Rename)
+	router.DELETE("/customer/:id/beneficiaries/:beneficiary_id", beneficiaryHandler.Delete)
+
+	if cfg.CardConfig.BIN != "" {
+		cardUseCase := usecase.NewCardUseCase(
+			postgres.NewCardRepository(postgresConnection),
+			customerRepository,
+			MustCardVault(cfg, blobStore, logger),
+			verificationUseCase,
+			limitUseCase,
+			riskUseCase,
+			cfg.CardConfig.BIN,
+		)
+		cardHandler := v1.NewCardHandlerV1(
+			logger.With(zap.String("handler", "cardV1")),
+			cardUseCase,
+			v1.NewJSONResponseWriter(logger),
+		)
+		router.POST("/customer/:id/cards", cardHandler.Issue)
+		router.GET("/customer/:id/cards", cardHandler.FindByCustomer)
+		router.GET("/cards/:id", cardHandler.Find)
+		router.GET("/cards/:id/secrets", cardHandler.FindSecrets)
+		router.GET("/cards/:id/authorizations", cardHandler.FindAuthorizations)
+		router.PUT("/cards/:id/status", cardHandler.SetStatus)
+		router.PUT("/cards/:id/controls", cardHandler.SetControls)
+		router.POST("/card-network/authorizations", cardHandler.Authorize)
+		router.POST("/card-network/authorizations/:id/capture", cardHandler.Capture)
+		router.POST("/card-network/authorizations/:id/reversal", cardHandler.Reverse)
+
+		if cfg.ISO8583Config.Address != "" {
+			listener, err := net.Listen("tcp", cfg.ISO8583Config.Address)
+			if err != nil {
+				logger.Fatal(fmt.Sprintf("unable to listen card network: %s", err.Error()))
+			}
+			defer listener.Close()
+			networkServer := iso8583.NewServer(
+				logger.With(zap.String("server", "iso8583")),
+				MustISO8583Codec(cfg, logger),
+				cardUseCase,
+			)
+			go func() {
+				logger.Info(fmt.Sprintf("start card network server on %s", cfg.ISO8583Config.Address))
+				if err := networkServer.Serve(listener); err != nil {
+					logger.Warn(fmt.Sprintf("card network server stopped: %s", err.Error()))
+				}
+			}()
+		} else {
+			logger.Warn("ISO 8583 address is not configured, card network server is not started")
+		}
+	} else {
+		logger.Warn("card BIN is not configured, cards are not issued")
+	}
 
 	// Start server
 	server := &fasthttp.Server{
@@ -98,20 +546,53 @@ func MustLogger() *zap.Logger {
 	return logger
 }
 
-func MustPostgres(config config.Config, logger *zap.Logger) *pgxpool.Pool {
-	pgxCfg, _ := pgx.ParseConfig(config.PostgresConfig.HostString)
-	pgxCfg.Logger = zapadapter.NewLogger(logger)
-	pgxCfg.LogLevel = config.PostgresConfig.LogLevel
-	pgxCfg.PreferSimpleProtocol = true
+func MustRuleSet(cfg config.Config, logger *zap.Logger) *monitoring.RuleSet {
+	ruleSet, err := monitoring.LoadRuleSet(cfg.MonitoringConfig.RulesPath)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("unable to load monitoring rules: %s", err.Error()))
+	}
+	return ruleSet
+}
+
+func MustBlobStore(cfg config.Config, logger *zap.Logger) *blob.FileSystemStore {
+	store, err := blob.NewFileSystemStore(cfg.BlobStoreConfig.Path)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("unable to open blob store: %s", err.Error()))
+	}
+	return store
+}
 
-	pgxPoolCfg, _ := pgxpool.ParseConfig("")
-	pgxPoolCfg.ConnConfig = pgxCfg
-	pgxPoolCfg.MaxConns = config.PostgresConfig.MaxConnections
-	pgxPoolCfg.MinConns = config.PostgresConfig.MinConnections
+func MustCardVault(cfg config.Config, store domain.BlobStore, logger *zap.Logger) *vault.Vault {
+	err := issuing.ValidateBIN(cfg.CardConfig.BIN)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("wrong card BIN: %s", err.Error()))
+	}
+	key, err := hex.DecodeString(cfg.CardConfig.VaultKey)
+	if err != nil {
+		logger.Fatal("card vault key should be hex encoded")
+	}
+	cardVault, err := vault.NewVault(store, key)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("unable to open card vault: %s", err.Error()))
+	}
+	return cardVault
+}
 
-	connection, err := pgxpool.ConnectConfig(context.Background(), pgxPoolCfg)
+func MustISO8583Codec(cfg config.Config, logger *zap.Logger) *iso8583.Codec {
+	spec, err := iso8583.LoadSpec(cfg.ISO8583Config.SpecPath)
 	if err != nil {
-		logger.Fatal(fmt.Sprintf("unable to connect to database: %s", err.Error()))
+		logger.Fatal(fmt.Sprintf("unable to load ISO 8583 spec: %s", err.Error()))
+	}
+	return iso8583.NewCodec(spec)
+}
+
+func riskThresholds(cfg config.Config) risk.Thresholds {
+	thresholds := risk.DefaultThresholds
+	if cfg.RiskConfig.ReviewScore > 0 {
+		thresholds.Review = cfg.RiskConfig.ReviewScore
+	}
+	if cfg.RiskConfig.DeclineScore > 0 {
+		thresholds.Decline = cfg.RiskConfig.DeclineScore
 	}
-	return connection
+	return thresholds
 }
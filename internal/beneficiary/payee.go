@@ -0,0 +1,22 @@
+package beneficiary
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// PayeeKey identifies payee details of beneficiary regardless of nickname. Bank account is identified
+// by IBAN alone, as BIC is optional and the same for every account of bank. Details should be normalized.
+func PayeeKey(beneficiary *domain.Beneficiary) (string, error) {
+	switch beneficiary.Type {
+	case domain.BeneficiaryTypeCustomer:
+		return string(beneficiary.Type) + ":" + beneficiary.RecipientID, nil
+	case domain.BeneficiaryTypePhone:
+		return string(beneficiary.Type) + ":" + beneficiary.Phone, nil
+	case domain.BeneficiaryTypeBankAccount:
+		return string(beneficiary.Type) + ":" + strings.ToUpper(beneficiary.IBAN), nil
+	}
+	return "", fmt.Errorf("unknown beneficiary type %q", beneficiary.Type)
+}
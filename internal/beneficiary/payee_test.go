@@ -0,0 +1,34 @@
+package beneficiary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestPayeeKey(t *testing.T) {
+	t.Parallel()
+
+	for expected, beneficiary := range map[string]*domain.Beneficiary{
+		"customer:09b843b24f5c966771ce2029a173c9ad": {
+			Type:        domain.BeneficiaryTypeCustomer,
+			RecipientID: "09b843b24f5c966771ce2029a173c9ad",
+			Nickname:    "Mom",
+		},
+		"phone:+79931234567": {Type: domain.BeneficiaryTypePhone, Phone: "+79931234567"},
+		"bank_account:DE89370400440532013000": {
+			Type:        domain.BeneficiaryTypeBankAccount,
+			AccountName: "Bruce Wayne",
+			IBAN:        "de89370400440532013000",
+			BIC:         "COBADEFFXXX",
+		},
+	} {
+		key, err := PayeeKey(beneficiary)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, key)
+	}
+
+	_, err := PayeeKey(&domain.Beneficiary{Type: "card"})
+	assert.EqualError(t, err, `unknown beneficiary type "card"`)
+}
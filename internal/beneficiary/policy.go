@@ -0,0 +1,70 @@
+package beneficiary
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// CoolingOffPolicy limits transfers to newly added beneficiaries, so that payee added with stolen credentials
+// could not be paid the whole balance at once. Within Period after beneficiary is added transfers above limit
+// of currency are refused. Currencies without limit could not be transferred until the period is over.
+type CoolingOffPolicy struct {
+	Period time.Duration
+	// Limits are maximum amounts of a single transfer in minor currency units by currency
+	Limits map[string]int64
+}
+
+var DefaultCoolingOffPolicy = CoolingOffPolicy{
+	Period: 24 * time.Hour,
+	Limits: map[string]int64{
+		"RUB": 1500000,
+		"USD": 20000,
+		"EUR": 20000,
+	},
+}
+
+// CheckPayee refuses transfer above limit to payee details which are not saved as beneficiary or saved recently.
+// Saved is beneficiary of customer with the same details, nil when customer has not saved them.
+func (p CoolingOffPolicy) CheckPayee(saved *domain.Beneficiary, amount int64, currency string, now time.Time) error {
+	if saved != nil {
+		return p.CheckTransfer(saved, amount, currency, now)
+	}
+	limit := p.Limits[currency]
+	if amount <= limit {
+		return nil
+	}
+	return fmt.Errorf(
+		"payee is not saved as beneficiary, transfers above %d %s are allowed to trusted beneficiaries",
+		limit,
+		currency,
+	)
+}
+
+// TrustedAt is the end of cooling-off period of beneficiary added at given time
+func (p CoolingOffPolicy) TrustedAt(createdAt time.Time) time.Time {
+	return createdAt.Add(p.Period)
+}
+
+// CheckTransfer refuses transfer above limit to beneficiary which cooling-off period is not over
+func (p CoolingOffPolicy) CheckTransfer(
+	beneficiary *domain.Beneficiary,
+	amount int64,
+	currency string,
+	now time.Time,
+) error {
+	if !now.Before(beneficiary.TrustedAt) {
+		return nil
+	}
+	limit := p.Limits[currency]
+	if amount <= limit {
+		return nil
+	}
+	return fmt.Errorf(
+		"beneficiary is added recently, transfers above %d %s are allowed after %s",
+		limit,
+		currency,
+		beneficiary.TrustedAt.Format(domain.DateTimeFormat),
+	)
+}
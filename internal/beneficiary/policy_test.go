@@ -0,0 +1,48 @@
+package beneficiary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestCoolingOffPolicy_CheckTransfer(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	beneficiary := &domain.Beneficiary{TrustedAt: DefaultCoolingOffPolicy.TrustedAt(createdAt)}
+	assert.Equal(t, time.Date(2021, 1, 2, 12, 0, 0, 0, time.UTC), beneficiary.TrustedAt)
+
+	now := createdAt.Add(time.Hour)
+	assert.NoError(t, DefaultCoolingOffPolicy.CheckTransfer(beneficiary, 1500000, "RUB", now))
+	assert.EqualError(
+		t,
+		DefaultCoolingOffPolicy.CheckTransfer(beneficiary, 1500001, "RUB", now),
+		"beneficiary is added recently, transfers above 1500000 RUB are allowed after 02-01-2021 12:00:00",
+	)
+	assert.Error(t, DefaultCoolingOffPolicy.CheckTransfer(beneficiary, 1, "JPY", now))
+
+	now = beneficiary.TrustedAt
+	assert.NoError(t, DefaultCoolingOffPolicy.CheckTransfer(beneficiary, 100000000, "RUB", now))
+	assert.NoError(t, DefaultCoolingOffPolicy.CheckTransfer(beneficiary, 1, "JPY", now))
+}
+
+func TestCoolingOffPolicy_CheckPayee(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.NoError(t, DefaultCoolingOffPolicy.CheckPayee(nil, 1500000, "RUB", now))
+	assert.EqualError(
+		t,
+		DefaultCoolingOffPolicy.CheckPayee(nil, 1500001, "RUB", now),
+		"payee is not saved as beneficiary, transfers above 1500000 RUB are allowed to trusted beneficiaries",
+	)
+	assert.Error(t, DefaultCoolingOffPolicy.CheckPayee(nil, 1, "JPY", now))
+
+	trusted := &domain.Beneficiary{TrustedAt: now.Add(-time.Hour)}
+	assert.NoError(t, DefaultCoolingOffPolicy.CheckPayee(trusted, 100000000, "RUB", now))
+	recent := &domain.Beneficiary{TrustedAt: now.Add(time.Hour)}
+	assert.Error(t, DefaultCoolingOffPolicy.CheckPayee(recent, 1500001, "RUB", now))
+}
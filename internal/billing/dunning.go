@@ -0,0 +1,34 @@
+package billing
+
+import "time"
+
+// DunningPolicy retries failed invoice charge after RetryDelays, repeating the last delay,
+// until GracePeriod after invoice due date passes. The last retry happens at the end of grace period.
+type DunningPolicy struct {
+	RetryDelays []time.Duration
+	GracePeriod time.Duration
+}
+
+var DefaultDunningPolicy = DunningPolicy{
+	RetryDelays: []time.Duration{24 * time.Hour, 3 * 24 * time.Hour, 5 * 24 * time.Hour},
+	GracePeriod: 14 * 24 * time.Hour,
+}
+
+// NextAttempt returns time of the next charge attempt after attempts failed ones, the last at failedAt.
+// Returns false when grace period is over and invoice should not be retried anymore.
+func (p DunningPolicy) NextAttempt(dueAt time.Time, failedAt time.Time, attempts int) (time.Time, bool) {
+	deadline := dueAt.Add(p.GracePeriod)
+	if len(p.RetryDelays) == 0 || !failedAt.Before(deadline) {
+		return time.Time{}, false
+	}
+
+	delay := p.RetryDelays[len(p.RetryDelays)-1]
+	if attempts >= 1 && attempts <= len(p.RetryDelays) {
+		delay = p.RetryDelays[attempts-1]
+	}
+	next := failedAt.Add(delay)
+	if next.After(deadline) {
+		next = deadline
+	}
+	return next, true
+}
@@ -0,0 +1,39 @@
+package billing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDunningPolicy_NextAttempt(t *testing.T) {
+	t.Parallel()
+
+	day := 24 * time.Hour
+	dueAt := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+	policy := DunningPolicy{RetryDelays: []time.Duration{day, 3 * day}, GracePeriod: 10 * day}
+
+	testCases := []struct {
+		name          string
+		failedAt      time.Time
+		attempts      int
+		expectedRetry bool
+		expected      time.Time
+	}{
+		{"FirstFailure", dueAt, 1, true, dueAt.Add(day)},
+		{"SecondFailure", dueAt.Add(day), 2, true, dueAt.Add(4 * day)},
+		{"LastDelayRepeated", dueAt.Add(4 * day), 3, true, dueAt.Add(7 * day)},
+		{"LimitedByGracePeriod", dueAt.Add(8 * day), 4, true, dueAt.Add(10 * day)},
+		{"GracePeriodIsOver", dueAt.Add(10 * day), 5, false, time.Time{}},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			next, retry := policy.NextAttempt(dueAt, test.failedAt, test.attempts)
+
+			assert.Equal(t, test.expectedRetry, retry)
+			assert.Equal(t, test.expected, next)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package billing
+
+import (
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/recurrence"
+)
+
+// PeriodEnd returns the end of billing period which starts at periodStart.
+// Periods are counted from anchor, so days of month missing in short months fall on their last day
+// and next periods return to the anchor day.
+func PeriodEnd(plan *domain.Plan, anchor time.Time, periodStart time.Time) time.Time {
+	return planRecurrence(plan).Next(anchor, periodStart)
+}
+
+func planRecurrence(plan *domain.Plan) recurrence.Rule {
+	count := plan.IntervalCount
+	if count < 1 {
+		count = 1
+	}
+	switch plan.Interval {
+	case domain.PlanIntervalDay:
+		return recurrence.Rule{Frequency: recurrence.FrequencyDaily, Interval: count}
+	case domain.PlanIntervalWeek:
+		return recurrence.Rule{Frequency: recurrence.FrequencyWeekly, Interval: count}
+	case domain.PlanIntervalYear:
+		return recurrence.Rule{Frequency: recurrence.FrequencyMonthly, Interval: 12 * count}
+	default:
+		return recurrence.Rule{Frequency: recurrence.FrequencyMonthly, Interval: count}
+	}
+}
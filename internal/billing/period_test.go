@@ -0,0 +1,43 @@
+package billing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestPeriodEnd(t *testing.T) {
+	t.Parallel()
+
+	anchor := time.Date(2020, 1, 31, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		interval      domain.PlanInterval
+		intervalCount int
+		periodStart   time.Time
+		expected      time.Time
+	}{
+		{"Day", domain.PlanIntervalDay, 1, anchor, time.Date(2020, 2, 1, 10, 0, 0, 0, time.UTC)},
+		{"TwoWeeks", domain.PlanIntervalWeek, 2, anchor, time.Date(2020, 2, 14, 10, 0, 0, 0, time.UTC)},
+		{"MonthToShortMonth", domain.PlanIntervalMonth, 1, anchor, time.Date(2020, 2, 29, 10, 0, 0, 0, time.UTC)},
+		{
+			"MonthBackToAnchorDay",
+			domain.PlanIntervalMonth,
+			1,
+			time.Date(2020, 2, 29, 10, 0, 0, 0, time.UTC),
+			time.Date(2020, 3, 31, 10, 0, 0, 0, time.UTC),
+		},
+		{"Quarter", domain.PlanIntervalMonth, 3, anchor, time.Date(2020, 4, 30, 10, 0, 0, 0, time.UTC)},
+		{"Year", domain.PlanIntervalYear, 1, anchor, time.Date(2021, 1, 31, 10, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			plan := &domain.Plan{Interval: test.interval, IntervalCount: test.intervalCount}
+			assert.Equal(t, test.expected, PeriodEnd(plan, anchor, test.periodStart))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package billing
+
+import (
+	"math/big"
+	"time"
+)
+
+// Prorate returns amount to charge for switching from oldAmount to newAmount price at the given moment
+// of period. Unused part of old price is credited and remaining part of new price is charged,
+// so downgrade gives negative amount. Amount is rounded half away from zero to a minor unit.
+func Prorate(oldAmount, newAmount int64, periodStart, periodEnd, at time.Time) int64 {
+	if !at.Before(periodEnd) || !periodEnd.After(periodStart) {
+		return 0
+	}
+	if at.Before(periodStart) {
+		at = periodStart
+	}
+
+	remaining := big.NewInt(int64(periodEnd.Sub(at) / time.Second))
+	total := big.NewInt(int64(periodEnd.Sub(periodStart) / time.Second))
+	if total.Sign() == 0 {
+		return 0
+	}
+
+	// (difference * remaining * 2 + sign * total) / (total * 2) rounds half away from zero
+	difference := big.NewInt(newAmount - oldAmount)
+	numerator := new(big.Int).Mul(difference, remaining)
+	numerator.Mul(numerator, big.NewInt(2))
+	numerator.Add(numerator, new(big.Int).Mul(big.NewInt(int64(difference.Sign())), total))
+	denominator := new(big.Int).Mul(total, big.NewInt(2))
+	return new(big.Int).Quo(numerator, denominator).Int64()
+}
@@ -0,0 +1,38 @@
+package billing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProrate(t *testing.T) {
+	t.Parallel()
+
+	periodStart := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		oldAmount int64
+		newAmount int64
+		at        time.Time
+		expected  int64
+	}{
+		{"UpgradeInTheMiddle", 1000, 3000, time.Date(2020, 4, 16, 0, 0, 0, 0, time.UTC), 1000},
+		{"DowngradeInTheMiddle", 3000, 1000, time.Date(2020, 4, 16, 0, 0, 0, 0, time.UTC), -1000},
+		{"RoundedHalfUp", 0, 1, time.Date(2020, 4, 16, 0, 0, 0, 0, time.UTC), 1},
+		{"RoundedHalfDownForCredit", 1, 0, time.Date(2020, 4, 16, 0, 0, 0, 0, time.UTC), -1},
+		{"TenDaysLeft", 0, 999, time.Date(2020, 4, 21, 0, 0, 0, 0, time.UTC), 333},
+		{"AtPeriodStart", 1000, 3000, periodStart, 2000},
+		{"AtPeriodEnd", 1000, 3000, periodEnd, 0},
+		{"SamePrice", 1000, 1000, time.Date(2020, 4, 16, 0, 0, 0, 0, time.UTC), 0},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Prorate(test.oldAmount, test.newAmount, periodStart, periodEnd, test.at))
+		})
+	}
+}
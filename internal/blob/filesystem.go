@@ -0,0 +1,86 @@
+package blob
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidKey is returned for empty keys and keys leading out of store root
+var ErrInvalidKey = errors.New("blob key should be a relative path inside store")
+
+// FileSystemStore keeps every blob in a file under root directory, key is a path of file relative to root
+type FileSystemStore struct {
+	root string
+}
+
+// NewFileSystemStore creates root directory when it does not exist
+func NewFileSystemStore(root string) (*FileSystemStore, error) {
+	err := os.MkdirAll(root, 0750)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSystemStore{root: root}, nil
+}
+
+// Put writes content to temporary file and renames it, so that readers never see partly written blob
+func (s *FileSystemStore) Put(key string, content []byte) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(filePath), 0750)
+	if err != nil {
+		return err
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(filePath), ".upload-")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	err = os.Rename(file.Name(), filePath)
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+	return nil
+}
+
+func (s *FileSystemStore) Get(key string) ([]byte, error) {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(filePath)
+}
+
+func (s *FileSystemStore) Delete(key string) error {
+	filePath, err := s.filePath(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *FileSystemStore) filePath(key string) (string, error) {
+	cleaned := path.Clean(key)
+	if key == "" || path.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
@@ -0,0 +1,38 @@
+package blob
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSystemStore_PutGet(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "blob")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	store, err := NewFileSystemStore(dir)
+	assert.NoError(t, err)
+
+	assert.NoError(t, store.Put("disputes/dispute/receipt", []byte("first")))
+	assert.NoError(t, store.Put("disputes/dispute/receipt", []byte("second")))
+	content, err := store.Get("disputes/dispute/receipt")
+	assert.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+
+	_, err = store.Get("disputes/dispute/missing")
+	assert.True(t, os.IsNotExist(err))
+
+	assert.NoError(t, store.Delete("disputes/dispute/receipt"))
+	assert.NoError(t, store.Delete("disputes/dispute/receipt"))
+	_, err = store.Get("disputes/dispute/receipt")
+	assert.True(t, os.IsNotExist(err))
+
+	for _, key := range []string{"", ".", "../outside", "disputes/../../outside", "/etc/passwd"} {
+		assert.Equal(t, ErrInvalidKey, store.Put(key, []byte("content")), key)
+		assert.Equal(t, ErrInvalidKey, store.Delete(key), key)
+	}
+}
@@ -0,0 +1,21 @@
+// Package businessday defines business days of the bank. Limits, card controls, deposit and loan accruals
+// and statements share them, so amounts spent, accrued or stated for a day are counted over the same hours.
+package businessday
+
+import "time"
+
+// Location is Moscow time. Business days start at Moscow midnight.
+// Moscow has no daylight saving time, so fixed zone does not depend on time zone database.
+var Location = time.FixedZone("MSK", 3*60*60)
+
+// Day is a midnight of business day of t
+func Day(t time.Time) time.Time {
+	t = t.In(Location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, Location)
+}
+
+// Month is a midnight of first business day of month of t
+func Month(t time.Time) time.Time {
+	t = t.In(Location)
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, Location)
+}
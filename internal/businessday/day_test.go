@@ -0,0 +1,20 @@
+package businessday
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDay(t *testing.T) {
+	t.Parallel()
+
+	// late evening of the last day of month in UTC is the next month in Moscow
+	at := time.Date(2021, time.January, 31, 22, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2021, time.February, 1, 0, 0, 0, 0, Location), Day(at))
+	assert.Equal(t, time.Date(2021, time.February, 1, 0, 0, 0, 0, Location), Month(at))
+	assert.Equal(t, time.Date(2021, time.January, 31, 0, 0, 0, 0, Location), Day(at.Add(-2*time.Hour)))
+	assert.Equal(t, time.Date(2021, time.January, 1, 0, 0, 0, 0, Location), Month(at.Add(-2*time.Hour)))
+}
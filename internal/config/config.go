@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/jackc/pgx/v4"
 )
@@ -13,8 +15,45 @@ type Config struct {
 		MinConnections int32
 		LogLevel       pgx.LogLevel
 	}
+	MonitoringConfig struct {
+		RulesPath string
+	}
+	// RiskConfig overrides default risk decision thresholds when scores are set
+	RiskConfig struct {
+		ReviewScore  int
+		DeclineScore int
+	}
+	// PayoutConfig is a settlement account payouts are paid from and bank statements are reconciled with,
+	// payouts are not submitted and statements are not matched when IBAN is not set
+	PayoutConfig struct {
+		DebtorName string
+		DebtorIBAN string
+		DebtorBIC  string
+	}
+	// BlobStoreConfig is a directory uploaded files like dispute evidence are kept in
+	BlobStoreConfig struct {
+		Path string
+	}
+	// CardConfig is a BIN cards are issued from and hex encoded AES-256 key of card vault,
+	// cards are not issued when BIN is not set
+	CardConfig struct {
+		BIN      string
+		VaultKey string
+	}
+	// ISO8583Config is a TCP address card network processor connects to and spec of messages it sends,
+	// card network server is not started when address is not set
+	ISO8583Config struct {
+		Address  string
+		SpecPath string
+	}
 }
 
+const (
+	defaultMonitoringRulesPath = "configs/monitoring_rules.yaml"
+	defaultBlobStorePath       = "data/blobs"
+	defaultISO8583SpecPath     = "configs/iso8583_1987.yaml"
+)
+
 func Read() Config {
 	postgresConnectionString := os.Getenv("POSTGRESQL_URL")
 	if postgresConnectionString == "" {
@@ -29,5 +68,43 @@ func Read() Config {
 	config.PostgresConfig.MinConnections = 1
 	config.PostgresConfig.LogLevel = pgx.LogLevelError
 
+	config.MonitoringConfig.RulesPath = os.Getenv("MONITORING_RULES_PATH")
+	if config.MonitoringConfig.RulesPath == "" {
+		config.MonitoringConfig.RulesPath = defaultMonitoringRulesPath
+	}
+
+	config.RiskConfig.ReviewScore = intFromEnv("RISK_REVIEW_SCORE")
+	config.RiskConfig.DeclineScore = intFromEnv("RISK_DECLINE_SCORE")
+
+	config.PayoutConfig.DebtorName = os.Getenv("PAYOUT_DEBTOR_NAME")
+	config.PayoutConfig.DebtorIBAN = os.Getenv("PAYOUT_DEBTOR_IBAN")
+	config.PayoutConfig.DebtorBIC = os.Getenv("PAYOUT_DEBTOR_BIC")
+
+	config.BlobStoreConfig.Path = os.Getenv("BLOB_STORE_PATH")
+	if config.BlobStoreConfig.Path == "" {
+		config.BlobStoreConfig.Path = defaultBlobStorePath
+	}
+
+	config.CardConfig.BIN = os.Getenv("CARD_BIN")
+	config.CardConfig.VaultKey = os.Getenv("CARD_VAULT_KEY")
+
+	config.ISO8583Config.Address = os.Getenv("ISO8583_ADDRESS")
+	config.ISO8583Config.SpecPath = os.Getenv("ISO8583_SPEC_PATH")
+	if config.ISO8583Config.SpecPath == "" {
+		config.ISO8583Config.SpecPath = defaultISO8583SpecPath
+	}
+
 	return config
 }
+
+func intFromEnv(name string) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Sprintf("env %s should be a number", name))
+	}
+	return number
+}
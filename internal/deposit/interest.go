@@ -0,0 +1,260 @@
+package deposit
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// MaxTermMonths limits term of term deposits to 10 years
+const MaxTermMonths = 120
+
+// rateRegexp allows decimal percents with up to 6 fraction digits, like 7, 7.5 or 0.01
+var rateRegexp = regexp.MustCompile(`^\d{1,3}(\.\d{1,6})?$`)
+
+var hundred = big.NewRat(100, 1)
+
+// ParseRate parses decimal percent between 0 and 100
+func ParseRate(value string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(value)
+	if !rateRegexp.MatchString(value) || !ok || rate.Cmp(hundred) > 0 {
+		return nil, fmt.Errorf("%q is not a decimal percent between 0 and 100", value)
+	}
+	return rate, nil
+}
+
+// ValidateProduct checks that product could be offered
+func ValidateProduct(product *domain.DepositProduct) error {
+	switch product.Type {
+	case domain.DepositTypeTerm:
+		if product.TermMonths < 1 || product.TermMonths > MaxTermMonths {
+			return fmt.Errorf("term deposit should have term of 1 to %d months", MaxTermMonths)
+		}
+	case domain.DepositTypeOnDemand:
+		if product.TermMonths != 0 {
+			return fmt.Errorf("on demand deposit could not have term")
+		}
+		if product.Capitalisation == domain.DepositCapitalisationAtMaturity {
+			return fmt.Errorf("on demand deposit does not mature, its interest is capitalised monthly")
+		}
+		if product.EarlyWithdrawalPenalty != "" {
+			return fmt.Errorf("on demand deposit has no early withdrawal penalty")
+		}
+	default:
+		return fmt.Errorf("type should be one of term, on_demand")
+	}
+	switch product.Capitalisation {
+	case domain.DepositCapitalisationMonthly, domain.DepositCapitalisationAtMaturity:
+	default:
+		return fmt.Errorf("capitalisation should be one of monthly, at_maturity")
+	}
+	switch product.DayCount {
+	case domain.DayCountACT365, domain.DayCountACT360, domain.DayCountACTACT:
+	default:
+		return fmt.Errorf("day count should be one of ACT/365, ACT/360, ACT/ACT")
+	}
+	_, err := ParseRate(product.AnnualRate)
+	if err != nil {
+		return fmt.Errorf("annual rate: %s", err.Error())
+	}
+	if product.EarlyWithdrawalPenalty != "" {
+		_, err = ParseRate(product.EarlyWithdrawalPenalty)
+		if err != nil {
+			return fmt.Errorf("early withdrawal penalty: %s", err.Error())
+		}
+	}
+	if product.MinAmount < 0 {
+		return fmt.Errorf("min amount should not be negative")
+	}
+	return nil
+}
+
+// Open starts deposit of product on a given day, interest is accrued starting from this day
+func Open(deposit *domain.Deposit, product *domain.DepositProduct, day time.Time) error {
+	if deposit.Principal <= 0 {
+		return fmt.Errorf("amount should be positive")
+	}
+	if deposit.Principal < product.MinAmount {
+		return fmt.Errorf("amount should be %d at least", product.MinAmount)
+	}
+	day = businessday.Day(day)
+	deposit.ProductID = product.GeneratedID
+	deposit.Currency = product.Currency
+	deposit.AccruedInterest = 0
+	deposit.CapitalisedInterest = 0
+	deposit.Status = domain.DepositStatusOpen
+	deposit.AccrualStart = day
+	deposit.AccruedUntil = day
+	deposit.MaturesAt = time.Time{}
+	if product.Type == domain.DepositTypeTerm {
+		deposit.MaturesAt = day.AddDate(0, product.TermMonths, 0)
+	}
+	return nil
+}
+
+// Accrue accrues interest of open deposit for every day before until and capitalises it on the first
+// day of month or at maturity, as product defines. Interest of accrual period is calculated on principal
+// as a whole and rounded down to a minor unit, so daily rounding does not add up. Term deposit stops
+// accruing at maturity, its interest is capitalised and it becomes matured.
+func Accrue(
+	deposit *domain.Deposit,
+	product *domain.DepositProduct,
+	until time.Time,
+) ([]*domain.DepositInterestEntry, error) {
+	if deposit.Status != domain.DepositStatusOpen {
+		return nil, nil
+	}
+	rate, err := ParseRate(product.AnnualRate)
+	if err != nil {
+		return nil, err
+	}
+	// days are read from database in any time zone, capitalisation days are the first days of month in Moscow
+	deposit.AccrualStart = businessday.Day(deposit.AccrualStart)
+	deposit.AccruedUntil = businessday.Day(deposit.AccruedUntil)
+	if !deposit.MaturesAt.IsZero() {
+		deposit.MaturesAt = businessday.Day(deposit.MaturesAt)
+	}
+	until = businessday.Day(until)
+
+	var entries []*domain.DepositInterestEntry
+	for day := deposit.AccruedUntil; day.Before(until); day = day.AddDate(0, 0, 1) {
+		if !deposit.MaturesAt.IsZero() && !day.Before(deposit.MaturesAt) {
+			break
+		}
+		if product.Capitalisation == domain.DepositCapitalisationMonthly && day.Day() == 1 &&
+			day.After(deposit.AccrualStart) {
+			entries = append(entries, capitalise(deposit, day)...)
+		}
+
+		next := day.AddDate(0, 0, 1)
+		accrued := interest(deposit.Principal, rate, product.DayCount, deposit.AccrualStart, next)
+		if accrued > deposit.AccruedInterest {
+			entries = append(entries, &domain.DepositInterestEntry{
+				DepositID: deposit.GeneratedID,
+				Type:      domain.DepositInterestEntryTypeAccrual,
+				Amount:    accrued - deposit.AccruedInterest,
+				Day:       day,
+			})
+			deposit.AccruedInterest = accrued
+		}
+		deposit.AccruedUntil = next
+	}
+
+	if !deposit.MaturesAt.IsZero() && !deposit.AccruedUntil.Before(deposit.MaturesAt) {
+		entries = append(entries, capitalise(deposit, deposit.MaturesAt)...)
+		deposit.Status = domain.DepositStatusMatured
+	}
+	return entries, nil
+}
+
+// Penalty is a part of earned interest forfeited when term deposit is closed before maturity
+func Penalty(deposit *domain.Deposit, product *domain.DepositProduct) (int64, error) {
+	if product.Type != domain.DepositTypeTerm || deposit.Status != domain.DepositStatusOpen ||
+		product.EarlyWithdrawalPenalty == "" {
+		return 0, nil
+	}
+	percent, err := ParseRate(product.EarlyWithdrawalPenalty)
+	if err != nil {
+		return 0, err
+	}
+	penalty := new(big.Rat).Mul(big.NewRat(deposit.EarnedInterest(), 1), percent)
+	return floor(penalty.Quo(penalty, hundred)), nil
+}
+
+// Project accrues copy of deposit till to and reports interest earned since opening of deposit
+func Project(
+	deposit domain.Deposit,
+	product *domain.DepositProduct,
+	to time.Time,
+) (*domain.DepositProjection, error) {
+	to = businessday.Day(to)
+	if !deposit.MaturesAt.IsZero() && to.After(deposit.MaturesAt) {
+		to = deposit.MaturesAt
+	}
+	_, err := Accrue(&deposit, product, to)
+	if err != nil {
+		return nil, err
+	}
+
+	principal := deposit.Principal - deposit.CapitalisedInterest
+	projection := &domain.DepositProjection{
+		Principal:     principal,
+		Interest:      deposit.EarnedInterest(),
+		From:          businessday.Day(deposit.OpenedAt),
+		To:            to,
+		EffectiveRate: "0.00",
+	}
+	days := daysBetween(projection.From, projection.To)
+	if days > 0 && principal > 0 {
+		growth := float64(principal+projection.Interest) / float64(principal)
+		projection.EffectiveRate = fmt.Sprintf("%.2f", (math.Pow(growth, 365/float64(days))-1)*100)
+	}
+	return projection, nil
+}
+
+// capitalise adds accrued interest to principal and starts a new accrual period on day
+func capitalise(deposit *domain.Deposit, day time.Time) []*domain.DepositInterestEntry {
+	accrued := deposit.AccruedInterest
+	deposit.Principal += accrued
+	deposit.CapitalisedInterest += accrued
+	deposit.AccruedInterest = 0
+	deposit.AccrualStart = day
+	if accrued == 0 {
+		return nil
+	}
+	return []*domain.DepositInterestEntry{{
+		DepositID: deposit.GeneratedID,
+		Type:      domain.DepositInterestEntryTypeCapitalisation,
+		Amount:    accrued,
+		Day:       day,
+	}}
+}
+
+// interest on principal for days in [from, to) rounded down to a minor unit
+func interest(principal int64, rate *big.Rat, convention domain.DayCountConvention, from, to time.Time) int64 {
+	amount := new(big.Rat).Mul(big.NewRat(principal, 1), rate)
+	amount.Quo(amount, hundred)
+	return floor(amount.Mul(amount, yearFraction(convention, from, to)))
+}
+
+// yearFraction is a share of year which days in [from, to) make by convention
+func yearFraction(convention domain.DayCountConvention, from, to time.Time) *big.Rat {
+	switch convention {
+	case domain.DayCountACT360:
+		return big.NewRat(int64(daysBetween(from, to)), 360)
+	case domain.DayCountACTACT:
+		fraction := new(big.Rat)
+		for start := from; start.Before(to); {
+			nextYear := time.Date(start.Year()+1, time.January, 1, 0, 0, 0, 0, start.Location())
+			end := to
+			if nextYear.Before(end) {
+				end = nextYear
+			}
+			fraction.Add(fraction, big.NewRat(int64(daysBetween(start, end)), int64(daysInYear(start.Year()))))
+			start = end
+		}
+		return fraction
+	default:
+		return big.NewRat(int64(daysBetween(from, to)), 365)
+	}
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func daysInYear(year int) int {
+	if time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay() == 366 {
+		return 366
+	}
+	return 365
+}
+
+func floor(amount *big.Rat) int64 {
+	return new(big.Int).Quo(amount.Num(), amount.Denom()).Int64()
+}
@@ -0,0 +1,206 @@
+package deposit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func day(year int, month time.Month, dayOfMonth int) time.Time {
+	return time.Date(year, month, dayOfMonth, 0, 0, 0, 0, businessday.Location)
+}
+
+func TestAccrue(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                        string
+		product                     *domain.DepositProduct
+		openedAt                    time.Time
+		until                       time.Time
+		expectedPrincipal           int64
+		expectedAccruedInterest     int64
+		expectedCapitalisedInterest int64
+		expectedStatus              domain.DepositStatus
+	}{
+		{
+			name: "OnDemandMonthlyCapitalisation",
+			product: &domain.DepositProduct{
+				Type:           domain.DepositTypeOnDemand,
+				AnnualRate:     "10",
+				Capitalisation: domain.DepositCapitalisationMonthly,
+				DayCount:       domain.DayCountACT365,
+			},
+			openedAt:                    day(2020, time.January, 15),
+			until:                       day(2020, time.March, 1),
+			expectedPrincipal:           100465753,
+			expectedAccruedInterest:     798221,
+			expectedCapitalisedInterest: 465753,
+			expectedStatus:              domain.DepositStatusOpen,
+		},
+		{
+			name: "TermMaturedOverLeapYear",
+			product: &domain.DepositProduct{
+				Type:           domain.DepositTypeTerm,
+				AnnualRate:     "10",
+				Capitalisation: domain.DepositCapitalisationAtMaturity,
+				DayCount:       domain.DayCountACTACT,
+				TermMonths:     1,
+			},
+			openedAt:                    day(2019, time.December, 20),
+			until:                       day(2020, time.February, 1),
+			expectedPrincipal:           100847892,
+			expectedCapitalisedInterest: 847892,
+			expectedStatus:              domain.DepositStatusMatured,
+		},
+		{
+			name: "ACT360",
+			product: &domain.DepositProduct{
+				Type:           domain.DepositTypeOnDemand,
+				AnnualRate:     "7.2",
+				Capitalisation: domain.DepositCapitalisationMonthly,
+				DayCount:       domain.DayCountACT360,
+			},
+			openedAt:                day(2020, time.March, 10),
+			until:                   day(2020, time.March, 20),
+			expectedPrincipal:       100000000,
+			expectedAccruedInterest: 200000,
+			expectedStatus:          domain.DepositStatusOpen,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			deposit := &domain.Deposit{GeneratedID: "deposit", Principal: 100000000, OpenedAt: test.openedAt}
+			assert.NoError(t, Open(deposit, test.product, test.openedAt))
+
+			entries, err := Accrue(deposit, test.product, test.until)
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedPrincipal, deposit.Principal)
+			assert.Equal(t, test.expectedAccruedInterest, deposit.AccruedInterest)
+			assert.Equal(t, test.expectedCapitalisedInterest, deposit.CapitalisedInterest)
+			assert.Equal(t, test.expectedStatus, deposit.Status)
+			var accrued int64
+			for _, entry := range entries {
+				if entry.Type == domain.DepositInterestEntryTypeAccrual {
+					accrued += entry.Amount
+				}
+			}
+			assert.Equal(t, deposit.EarnedInterest(), accrued)
+		})
+	}
+}
+
+func TestPenalty(t *testing.T) {
+	t.Parallel()
+
+	product := &domain.DepositProduct{
+		Type:                   domain.DepositTypeTerm,
+		AnnualRate:             "8",
+		Capitalisation:         domain.DepositCapitalisationMonthly,
+		DayCount:               domain.DayCountACT365,
+		TermMonths:             6,
+		EarlyWithdrawalPenalty: "50",
+	}
+	deposit := &domain.Deposit{Principal: 100000000}
+	assert.NoError(t, Open(deposit, product, day(2020, time.March, 10)))
+	_, err := Accrue(deposit, product, day(2020, time.March, 20))
+	assert.NoError(t, err)
+
+	penalty, err := Penalty(deposit, product)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(219178), deposit.AccruedInterest)
+	assert.Equal(t, int64(109589), penalty)
+
+	deposit.Status = domain.DepositStatusMatured
+	penalty, err = Penalty(deposit, product)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), penalty)
+}
+
+func TestProject(t *testing.T) {
+	t.Parallel()
+
+	product := &domain.DepositProduct{
+		Type:           domain.DepositTypeTerm,
+		AnnualRate:     "12",
+		Capitalisation: domain.DepositCapitalisationMonthly,
+		DayCount:       domain.DayCountACT365,
+		TermMonths:     12,
+	}
+	deposit := domain.Deposit{Principal: 100000000, OpenedAt: day(2021, time.January, 1)}
+	assert.NoError(t, Open(&deposit, product, deposit.OpenedAt))
+
+	projection, err := Project(deposit, product, day(2030, time.January, 1))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100000000), projection.Principal)
+	assert.Equal(t, int64(12682443), projection.Interest)
+	assert.Equal(t, day(2022, time.January, 1), projection.To)
+	assert.Equal(t, "12.68", projection.EffectiveRate)
+	assert.Equal(t, int64(0), deposit.CapitalisedInterest)
+}
+
+func TestValidateProduct(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		product       domain.DepositProduct
+		expectedError string
+	}{
+		{
+			name: "Valid",
+			product: domain.DepositProduct{
+				Type: domain.DepositTypeTerm, AnnualRate: "7.5", TermMonths: 12,
+				Capitalisation: domain.DepositCapitalisationAtMaturity, DayCount: domain.DayCountACT365,
+			},
+		},
+		{
+			name: "TermWithoutMonths",
+			product: domain.DepositProduct{
+				Type: domain.DepositTypeTerm, AnnualRate: "7.5",
+				Capitalisation: domain.DepositCapitalisationMonthly, DayCount: domain.DayCountACT365,
+			},
+			expectedError: "term deposit should have term of 1 to 120 months",
+		},
+		{
+			name: "OnDemandAtMaturity",
+			product: domain.DepositProduct{
+				Type: domain.DepositTypeOnDemand, AnnualRate: "7.5",
+				Capitalisation: domain.DepositCapitalisationAtMaturity, DayCount: domain.DayCountACT365,
+			},
+			expectedError: "on demand deposit does not mature, its interest is capitalised monthly",
+		},
+		{
+			name: "WrongRate",
+			product: domain.DepositProduct{
+				Type: domain.DepositTypeOnDemand, AnnualRate: "7,5",
+				Capitalisation: domain.DepositCapitalisationMonthly, DayCount: domain.DayCountACT365,
+			},
+			expectedError: `annual rate: "7,5" is not a decimal percent between 0 and 100`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateProduct(&test.product)
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/beneficiary_repository_mock.go -package=mocks . BeneficiaryRepository
+
+type BeneficiaryRepository interface {
+	// Create saves beneficiary unless customer already has beneficiary with the same payee key,
+	// returns whether beneficiary is saved
+	Create(beneficiary *Beneficiary) (bool, error)
+	FindByID(beneficiaryID string) (beneficiary *Beneficiary, err error)
+	FindByCustomerID(customerID string) (beneficiaries []*Beneficiary, err error)
+	FindByPayeeKey(customerID string, payeeKey string) (beneficiary *Beneficiary, err error)
+	UpdateNickname(beneficiaryID string, nickname string, updatedAt time.Time) error
+	Delete(beneficiaryID string) error
+}
+
+type BeneficiaryType string
+
+const (
+	// BeneficiaryTypeCustomer is paid by p2p transfer to the current phone of customer
+	BeneficiaryTypeCustomer BeneficiaryType = "customer"
+	// BeneficiaryTypePhone is paid by p2p transfer to customer found by phone at the moment of transfer
+	BeneficiaryTypePhone BeneficiaryType = "phone"
+	// BeneficiaryTypeBankAccount is paid by payout to external bank account
+	BeneficiaryTypeBankAccount BeneficiaryType = "bank_account"
+)
+
+// Beneficiary is a payee saved by customer. Payee details are set according to type and could not be changed,
+// a new beneficiary is added for new details.
+type Beneficiary struct {
+	GeneratedID string
+	CustomerID  string
+	Type        BeneficiaryType
+	Nickname    string
+	RecipientID string
+	Phone       string
+	AccountName string
+	IBAN        string
+	BIC         string
+	// PayeeKey identifies payee details, customer could not save the same details twice
+	PayeeKey string
+	// TrustedAt is the end of cooling-off period, large transfers to beneficiary are refused before it
+	TrustedAt time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,11 @@
+package domain
+
+// BlobStore keeps files like dispute evidence outside of database. Key is a slash separated relative path.
+type BlobStore interface {
+	// Put saves content under key, replacing content saved before
+	Put(key string, content []byte) error
+	// Get reads content saved under key
+	Get(key string) ([]byte, error)
+	// Delete removes content saved under key, deleting missing content is not an error
+	Delete(key string) error
+}
@@ -0,0 +1,156 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/card_repository_mock.go -package=mocks . CardRepository
+
+type CardRepository interface {
+	// Create saves card, returns false and saves nothing when card with the same PAN fingerprint exists
+	Create(card *Card) (bool, error)
+	FindByID(cardID string) (card *Card, err error)
+	FindByFingerprint(fingerprint string) (card *Card, err error)
+	FindByCustomerID(customerID string) (cards []*Card, err error)
+	// Update locks card, passes it to update and saves it when update returns no error.
+	// Returns nil card when there is no card with such id.
+	Update(cardID string, update func(card *Card) error) (*Card, error)
+	// Authorize locks card customer and passes card to authorize with available balance of customer in card currency,
+	// amount of authorizations of card approved since dayStart and limit usage of customer in card currency at now.
+	// Authorization returned by authorize is saved in the same transaction, approved authorization places hold
+	// of its amount on customer balance and is added to limit usage of customer.
+	// Returns nil authorization when there is no card with such id.
+	Authorize(
+		cardID string,
+		now time.Time,
+		dayStart time.Time,
+		authorize func(card *Card, balance *AvailableBalance, spent int64, used LimitUsage) (*CardAuthorization, error),
+	) (*CardAuthorization, error)
+	FindAuthorizationByID(authorizationID string) (authorization *CardAuthorization, err error)
+	FindAuthorizationByNetworkReference(reference string) (authorization *CardAuthorization, err error)
+	FindAuthorizationsByCardID(cardID string) (authorizations []*CardAuthorization, err error)
+	// UpdateAuthorization locks authorization, passes it to update and saves it with postings returned by update
+	// in the same transaction. Hold of authorization is released once authorization is not approved anymore,
+	// customer debited by postings is locked like in any other debit.
+	// Returns nil authorization when there is no authorization with such id.
+	UpdateAuthorization(
+		authorizationID string,
+		update func(authorization *CardAuthorization) ([]*Posting, error),
+	) (*CardAuthorization, error)
+}
+
+// CardVault keeps secrets of cards encrypted, card records keep only masked PAN and PAN fingerprint
+type CardVault interface {
+	Put(cardID string, secrets *CardSecrets) error
+	// Get returns nil secrets when secrets of card are deleted or were never put
+	Get(cardID string) (*CardSecrets, error)
+	Delete(cardID string) error
+	// Fingerprint is a keyed hash of PAN, the same for the same PAN, which card is found by
+	Fingerprint(pan string) string
+}
+
+// CardSecrets are card data which are never saved in card records
+type CardSecrets struct {
+	PAN string
+	CVV string
+}
+
+type CardStatus string
+
+const (
+	CardStatusActive CardStatus = "active"
+	// CardStatusFrozen declines all authorizations until card is active again
+	CardStatusFrozen CardStatus = "frozen"
+	// CardStatusTerminated is final, secrets of terminated card are deleted from vault
+	CardStatusTerminated CardStatus = "terminated"
+)
+
+// Card is a virtual card of customer spending from customer balance in card currency.
+// Card is valid till the end of its expiry month.
+type Card struct {
+	GeneratedID    string
+	CustomerID     string
+	Currency       string
+	MaskedPAN      string
+	PANFingerprint string
+	ExpiryMonth    int
+	ExpiryYear     int
+	Status         CardStatus
+	Controls       CardControls
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// CardControls limit spending of card. Amounts are in minor currency units, zero amount is not limited.
+type CardControls struct {
+	// BlockedMCCs are merchant category codes of merchants card is declined at
+	BlockedMCCs    []string
+	PerTransaction int64
+	// Daily limits amount of authorizations approved during a day in statement time zone
+	Daily int64
+}
+
+// CardAuthorizationRequest is an authorization request of card network. CVV is optional, it is checked when sent.
+// NetworkReference identifies request in card network, request with the same reference is authorized once.
+type CardAuthorizationRequest struct {
+	PAN              string
+	ExpiryMonth      int
+	ExpiryYear       int
+	CVV              string
+	Amount           int64
+	Currency         string
+	MCC              string
+	MerchantName     string
+	NetworkReference string
+}
+
+type CardAuthorizationStatus string
+
+const (
+	// CardAuthorizationStatusApproved holds amount on customer balance until authorization is captured or reversed
+	CardAuthorizationStatusApproved CardAuthorizationStatus = "approved"
+	CardAuthorizationStatusDeclined CardAuthorizationStatus = "declined"
+	// CardAuthorizationStatusCaptured debited customer balance
+	CardAuthorizationStatusCaptured CardAuthorizationStatus = "captured"
+	// CardAuthorizationStatusReversed released hold of approved authorization or refunded captured one
+	CardAuthorizationStatusReversed CardAuthorizationStatus = "reversed"
+)
+
+type CardDeclineReason string
+
+const (
+	CardDeclineReasonCardFrozen          CardDeclineReason = "card_frozen"
+	CardDeclineReasonCardTerminated      CardDeclineReason = "card_terminated"
+	CardDeclineReasonCardExpired         CardDeclineReason = "card_expired"
+	CardDeclineReasonInvalidExpiry       CardDeclineReason = "invalid_expiry"
+	CardDeclineReasonInvalidCVV          CardDeclineReason = "invalid_cvv"
+	CardDeclineReasonCurrencyMismatch    CardDeclineReason = "currency_mismatch"
+	CardDeclineReasonMCCBlocked          CardDeclineReason = "mcc_blocked"
+	CardDeclineReasonPerTransactionLimit CardDeclineReason = "per_transaction_limit"
+	CardDeclineReasonDailyLimit          CardDeclineReason = "daily_limit"
+	// CardDeclineReasonCustomerLimit means that authorization breaches spending limits of customer
+	CardDeclineReasonCustomerLimit CardDeclineReason = "customer_limit"
+	// CardDeclineReasonCustomerRestricted means that customer is not active or not verified to move money
+	CardDeclineReasonCustomerRestricted CardDeclineReason = "customer_restricted"
+	// CardDeclineReasonRisk means that risk assessment of authorization declined it
+	CardDeclineReasonRisk              CardDeclineReason = "risk_declined"
+	CardDeclineReasonInsufficientFunds CardDeclineReason = "insufficient_funds"
+)
+
+// CardAuthorization is a decision on authorization request of card. Amounts are in minor currency units.
+type CardAuthorization struct {
+	GeneratedID  string
+	CardID       string
+	CustomerID   string
+	Amount       int64
+	Currency     string
+	MCC          string
+	MerchantName string
+	Status       CardAuthorizationStatus
+	// DeclineReason is set for declined authorizations
+	DeclineReason    CardDeclineReason
+	NetworkReference string
+	// RiskAssessmentID refers to assessment scored before authorization
+	RiskAssessmentID string
+	RiskDecision     RiskDecision
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
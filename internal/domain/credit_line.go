@@ -0,0 +1,78 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/credit_line_repository_mock.go -package=mocks . CreditLineRepository
+
+type CreditLineRepository interface {
+	// Save creates credit line or updates limit and rate of existing credit line of the same customer and currency.
+	// Customer is locked as debits lock it, so that a debit sees either the old or the new limit.
+	Save(line *CreditLine) error
+	Find(customerID string, currency string) (line *CreditLine, err error)
+	FindByCustomerID(customerID string) (lines []*CreditLine, err error)
+	// FindAvailableBalance returns posted balance of customer in currency with credit limit and active holds
+	FindAvailableBalance(customerID string, currency string) (balance *AvailableBalance, err error)
+	// ClaimDue locks up to limit credit lines with interest accrued until a day before today with their customers,
+	// skipping credit lines or customers locked by other instances, and passes each to accrue with closing balances
+	// of days since AccruedUntil till today. Credit lines are saved with postings returned by accrue in the same
+	// transaction.
+	ClaimDue(
+		today time.Time,
+		limit int,
+		accrue func(line *CreditLine, closing []*ClosingBalance) ([]*Posting, error),
+	) (int, error)
+}
+
+// CreditLine allows customer to go negative in its currency down to approved limit. Interest is accrued daily
+// on negative closing balance and charged on the first day of every month. Amounts are in minor currency units,
+// rate is a decimal annual percent, like 24.9. Dates are days in statement time zone.
+type CreditLine struct {
+	GeneratedID string
+	CustomerID  string
+	Currency    string
+	Limit       int64
+	AnnualRate  string
+	// AccruedInterest is accrued since the last charge
+	AccruedInterest int64
+	ChargedInterest int64
+	// Charges counts interest charges
+	Charges int
+	// AccruedUntil is the first day which interest is not accrued for yet
+	AccruedUntil time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ClosingBalance is posted balance of customer at the end of day
+type ClosingBalance struct {
+	Day     time.Time
+	Balance int64
+}
+
+// AvailableBalance is posted balance of customer in currency with credit limit of customer and holds which reserve
+// balance for operations not posted yet. Every debit should be within available amount.
+type AvailableBalance struct {
+	Balance int64
+	Limit   int64
+	Holds   int64
+}
+
+// Available is an amount which could be debited, it is negative when balance is overdrawn beyond limit
+func (b *AvailableBalance) Available() int64 {
+	return b.Balance + b.Limit - b.Holds
+}
+
+// Funds are posted balance of customer less holds, credit limit is not included
+func (b *AvailableBalance) Funds() int64 {
+	return b.Balance - b.Holds
+}
+
+// CreditLineUtilisation reports how much of credit limit customer uses
+type CreditLineUtilisation struct {
+	Line    *CreditLine
+	Balance *AvailableBalance
+	// Used is a part of limit taken by negative balance and holds which own funds do not cover
+	Used int64
+	// Percent is Used as a percent of limit with two fraction digits
+	Percent string
+}
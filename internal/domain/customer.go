@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 //go:generate mockgen -destination=../postgres/mocks/customer_repository_mock.go -package=mocks . CustomerRepository
 
@@ -8,18 +11,54 @@ type CustomerRepository interface {
 	Create(customer *Customer) error
 	FindByID(customerID string) (customer *Customer, err error)
 	FindByPassportNumber(passportNumber string) (customer *Customer, err error)
+	// FindByOGRN finds legal entity customer by state registration number
+	FindByOGRN(ogrn string) (customer *Customer, err error)
+	// FindByPhone finds customer by phone in E.164 format
+	FindByPhone(phone string) (customer *Customer, err error)
+	FindByStatus(status CustomerStatus) (customers []*Customer, err error)
 	Update(customer *Customer) error
+	UpdatePhone(customerID string, phone string) error
 	Delete(customerID string) error
 }
 
+type CustomerStatus string
+
+const (
+	CustomerStatusActive CustomerStatus = "active"
+	// CustomerStatusPendingReview is set when customer matched sanctions or PEP list and waits for manual review
+	CustomerStatusPendingReview CustomerStatus = "pending_review"
+	CustomerStatusBlocked       CustomerStatus = "blocked"
+)
+
+type CustomerType string
+
+const (
+	CustomerTypeIndividual  CustomerType = "individual"
+	CustomerTypeLegalEntity CustomerType = "legal_entity"
+)
+
+// Customer has unique Phone in E.164 format. Individual has names and Passport, legal entity has Company
+// and Address is its registered address.
 type Customer struct {
 	GeneratedID string
+	Type        CustomerType
+	Status      CustomerStatus
 	FirstName   string
 	LastName    string
 	Email       string
 	Phone       string
 	Address     Address
 	Passport    Passport
+	Company     Company
+	CreatedAt   time.Time
+}
+
+// Name is full name of individual or name of company
+func (c *Customer) Name() string {
+	if c.Type == CustomerTypeLegalEntity {
+		return c.Company.Name
+	}
+	return strings.TrimSpace(c.FirstName + " " + c.LastName)
 }
 
 type Address struct {
@@ -37,3 +76,26 @@ type Passport struct {
 	BirthDate  time.Time
 	BirthPlace string
 }
+
+// Company is a legal entity registered in Russia. Beneficial owners and representatives are individual customers.
+type Company struct {
+	Name string
+	INN  string
+	KPP  string
+	OGRN string
+	// BeneficialOwners own shares of company directly or indirectly
+	BeneficialOwners []BeneficialOwner
+	// Representatives act on behalf of company, like CEO or holder of power of attorney
+	Representatives []Representative
+}
+
+type BeneficialOwner struct {
+	CustomerID string
+	// Share is in hundredths of a percent, 2550 is 25.5%
+	Share int
+}
+
+type Representative struct {
+	CustomerID string
+	Position   string
+}
@@ -1,3 +1,5 @@
 package domain
 
 const DateFormat = "02-01-2006" // DD-MM-YYYY
+
+const DateTimeFormat = "02-01-2006 15:04:05" // DD-MM-YYYY hh:mm:ss
@@ -0,0 +1,143 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/deposit_repository_mock.go -package=mocks . DepositRepository
+
+type DepositRepository interface {
+	CreateProduct(product *DepositProduct) error
+	FindProductByID(productID string) (product *DepositProduct, err error)
+	FindProducts() (products []*DepositProduct, err error)
+	// Create saves deposit with postings which move principal from customer balance in the same transaction.
+	// Returns false and saves nothing when available balance of customer is less than deposit principal.
+	Create(deposit *Deposit, postings []*Posting) (bool, error)
+	FindByID(depositID string) (deposit *Deposit, err error)
+	FindByCustomerID(customerID string) (deposits []*Deposit, err error)
+	// Update locks deposit, passes it to update and saves it with interest entries and postings returned
+	// by update in the same transaction when update returns no error.
+	// Returns nil deposit when there is no deposit with such id.
+	Update(
+		depositID string,
+		update func(deposit *Deposit) ([]*DepositInterestEntry, []*Posting, error),
+	) (*Deposit, error)
+	// ClaimDue locks up to limit open deposits with interest accrued until a day before today, skipping deposits
+	// locked by other instances, and passes each to accrue. Deposits are saved with interest entries and postings
+	// returned by accrue in the same transaction.
+	ClaimDue(
+		today time.Time,
+		limit int,
+		accrue func(deposit *Deposit) ([]*DepositInterestEntry, []*Posting, error),
+	) (int, error)
+	FindInterestEntries(depositID string) (entries []*DepositInterestEntry, err error)
+}
+
+type DepositType string
+
+const (
+	// DepositTypeTerm is kept till maturity, interest is partly forfeited when it is closed earlier
+	DepositTypeTerm DepositType = "term"
+	// DepositTypeOnDemand is closed at any time without penalty
+	DepositTypeOnDemand DepositType = "on_demand"
+)
+
+type DepositCapitalisation string
+
+const (
+	// DepositCapitalisationMonthly adds accrued interest to principal on the first day of every month
+	DepositCapitalisationMonthly DepositCapitalisation = "monthly"
+	// DepositCapitalisationAtMaturity adds accrued interest to principal at maturity only
+	DepositCapitalisationAtMaturity DepositCapitalisation = "at_maturity"
+)
+
+// DayCountConvention tells how a daily share of annual rate is calculated
+type DayCountConvention string
+
+const (
+	DayCountACT365 DayCountConvention = "ACT/365"
+	DayCountACT360 DayCountConvention = "ACT/360"
+	// DayCountACTACT divides by 366 days of leap years and 365 days of other years
+	DayCountACTACT DayCountConvention = "ACT/ACT"
+)
+
+// DepositProduct is an offer of deposits. Rates are decimal percents, like 7.5.
+type DepositProduct struct {
+	GeneratedID    string
+	Name           string
+	Type           DepositType
+	Currency       string
+	AnnualRate     string
+	Capitalisation DepositCapitalisation
+	DayCount       DayCountConvention
+	// TermMonths is a term of term deposits and is zero for on demand deposits
+	TermMonths int
+	// EarlyWithdrawalPenalty is a percent of earned interest forfeited when term deposit is closed before maturity
+	EarlyWithdrawalPenalty string
+	// MinAmount of principal in minor currency units
+	MinAmount int64
+	CreatedAt time.Time
+}
+
+type DepositStatus string
+
+const (
+	DepositStatusOpen DepositStatus = "open"
+	// DepositStatusMatured is a term deposit which does not accrue interest after maturity and waits to be closed
+	DepositStatusMatured DepositStatus = "matured"
+	DepositStatusClosed  DepositStatus = "closed"
+)
+
+// Deposit of customer. Amounts are in minor currency units. Principal includes capitalised interest,
+// AccruedInterest is accrued since the last capitalisation. Dates are days in statement time zone.
+type Deposit struct {
+	GeneratedID         string
+	ProductID           string
+	CustomerID          string
+	Currency            string
+	Principal           int64
+	AccruedInterest     int64
+	CapitalisedInterest int64
+	Status              DepositStatus
+	// AccrualStart is a day when the current accrual period started, on opening or on the last capitalisation
+	AccrualStart time.Time
+	// AccruedUntil is the first day which interest is not accrued for yet
+	AccruedUntil time.Time
+	OpenedAt     time.Time
+	// MaturesAt is zero for on demand deposits
+	MaturesAt time.Time
+	// PaidOut to customer on closing, it is less than principal with interest by early withdrawal penalty
+	PaidOut   int64
+	ClosedAt  time.Time
+	UpdatedAt time.Time
+}
+
+// EarnedInterest sums capitalised and accrued interest
+func (d *Deposit) EarnedInterest() int64 {
+	return d.CapitalisedInterest + d.AccruedInterest
+}
+
+type DepositInterestEntryType string
+
+const (
+	DepositInterestEntryTypeAccrual        DepositInterestEntryType = "accrual"
+	DepositInterestEntryTypeCapitalisation DepositInterestEntryType = "capitalisation"
+	DepositInterestEntryTypePenalty        DepositInterestEntryType = "penalty"
+)
+
+// DepositInterestEntry is an entry of accrued interest account of deposit. Accrual credits the account,
+// capitalisation moves accrued interest to principal and penalty is interest forfeited on early withdrawal.
+type DepositInterestEntry struct {
+	DepositID string
+	Type      DepositInterestEntryType
+	Amount    int64
+	Day       time.Time
+}
+
+// DepositProjection is interest expected for deposit when it is kept from From till To
+type DepositProjection struct {
+	Principal int64
+	Interest  int64
+	From      time.Time
+	To        time.Time
+	// EffectiveRate is an annual percent yield with capitalisation taken into account
+	EffectiveRate string
+}
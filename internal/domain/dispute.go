@@ -0,0 +1,113 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/dispute_repository_mock.go -package=mocks . DisputeRepository
+
+type DisputeRepository interface {
+	// Create saves dispute with its provisional postings in the same transaction. Merchant is debited
+	// regardless of its available balance, but it is locked like in any other debit.
+	Create(dispute *Dispute, postings []*Posting) error
+	FindByID(disputeID string) (dispute *Dispute, err error)
+	FindByPaymentID(paymentID string) (dispute *Dispute, err error)
+	FindByMerchantID(merchantID string) (disputes []*Dispute, err error)
+	// Update locks dispute, passes it to update and saves it with postings returned by update in the same
+	// transaction when update returns no error. Returns nil dispute when there is no dispute with such id.
+	Update(disputeID string, update func(dispute *Dispute) ([]*Posting, error)) (*Dispute, error)
+	// ClaimOverdue locks up to limit open disputes with RespondBy not later than now, skipping disputes
+	// locked by other instances, and passes each to settle. Disputes are saved with postings returned by settle
+	// in the same transaction.
+	ClaimOverdue(now time.Time, limit int, settle func(dispute *Dispute) ([]*Posting, error)) (int, error)
+	AddEvidence(evidence *DisputeEvidence) error
+	FindEvidence(disputeID string) (evidence []*DisputeEvidence, err error)
+}
+
+type DisputeStatus string
+
+const (
+	// DisputeStatusOpen waits for merchant to submit evidence or to accept dispute till RespondBy
+	DisputeStatusOpen DisputeStatus = "open"
+	// DisputeStatusUnderReview has evidence of merchant submitted and waits for outcome
+	DisputeStatusUnderReview DisputeStatus = "under_review"
+	// DisputeStatusWon means that merchant keeps payment
+	DisputeStatusWon DisputeStatus = "won"
+	// DisputeStatusLost means that payment is returned to payer
+	DisputeStatusLost DisputeStatus = "lost"
+	// DisputeStatusAccepted means that merchant agreed to return payment to payer
+	DisputeStatusAccepted DisputeStatus = "accepted"
+)
+
+type DisputeReasonCode string
+
+const (
+	DisputeReasonCodeFraudulent          DisputeReasonCode = "fraudulent"
+	DisputeReasonCodeProductNotReceived  DisputeReasonCode = "product_not_received"
+	DisputeReasonCodeProductUnacceptable DisputeReasonCode = "product_unacceptable"
+	DisputeReasonCodeDuplicate           DisputeReasonCode = "duplicate"
+	DisputeReasonCodeCreditNotProcessed  DisputeReasonCode = "credit_not_processed"
+	DisputeReasonCodeGeneral             DisputeReasonCode = "general"
+)
+
+// IsKnown tells whether reason code is one of the codes above
+func (c DisputeReasonCode) IsKnown() bool {
+	switch c {
+	case DisputeReasonCodeFraudulent,
+		DisputeReasonCodeProductNotReceived,
+		DisputeReasonCodeProductUnacceptable,
+		DisputeReasonCodeDuplicate,
+		DisputeReasonCodeCreditNotProcessed,
+		DisputeReasonCodeGeneral:
+		return true
+	default:
+		return false
+	}
+}
+
+// Dispute is a chargeback of payer against captured QR payment to merchant. Amount in minor currency units
+// is provisionally moved from merchant to disputes ledger account when dispute is opened, it is released back
+// to merchant when dispute is won and to payer otherwise.
+type Dispute struct {
+	GeneratedID string
+	PaymentID   string
+	MerchantID  string
+	PayerID     string
+	Amount      int64
+	Currency    string
+	ReasonCode  DisputeReasonCode
+	Description string
+	Status      DisputeStatus
+	// RespondBy is a deadline of merchant, open dispute is lost after it
+	RespondBy  time.Time
+	ResolvedAt time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// CanTransitionTo allows merchant to respond to open dispute and any outcome of open or reviewed dispute
+func (d *Dispute) CanTransitionTo(status DisputeStatus) bool {
+	switch d.Status {
+	case DisputeStatusOpen:
+		return status != DisputeStatusOpen
+	case DisputeStatusUnderReview:
+		return status == DisputeStatusWon || status == DisputeStatusLost
+	default:
+		return false
+	}
+}
+
+// IsResolved tells whether dispute has an outcome
+func (d *Dispute) IsResolved() bool {
+	return d.Status == DisputeStatusWon || d.Status == DisputeStatusLost || d.Status == DisputeStatusAccepted
+}
+
+// DisputeEvidence is a file uploaded by party of dispute, content is saved in BlobStore under BlobKey
+type DisputeEvidence struct {
+	GeneratedID string
+	DisputeID   string
+	CustomerID  string
+	FileName    string
+	ContentType string
+	Size        int
+	BlobKey     string
+	CreatedAt   time.Time
+}
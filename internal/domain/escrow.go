@@ -0,0 +1,101 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/escrow_repository_mock.go -package=mocks . EscrowRepository
+
+type EscrowRepository interface {
+	// Create saves escrow and postings of debit which funds escrow account in one transaction.
+	// Debit fails with ErrInsufficientFunds when it exceeds available balance of buyer.
+	Create(escrow *Escrow, debit *Debit) error
+	FindByID(escrowID string) (escrow *Escrow, err error)
+	FindByOrderID(merchantID string, orderID string) (escrow *Escrow, err error)
+	FindByMerchantID(merchantID string) (escrows []*Escrow, err error)
+	// Update locks escrow, passes it to update and saves it with debits returned by update when update
+	// returns no error. Returns nil escrow when there is no escrow with such id.
+	Update(escrowID string, update func(escrow *Escrow) ([]*Debit, error)) (*Escrow, error)
+	// ClaimExpired locks up to limit held escrows with ExpiresAt not later than now, skipping escrows
+	// locked by other instances, and passes each to settle. Escrow changes and debits returned by settle
+	// are saved in the same transaction.
+	ClaimExpired(now time.Time, limit int, settle func(escrow *Escrow) ([]*Debit, error)) (int, error)
+}
+
+type EscrowStatus string
+
+const (
+	EscrowStatusHeld EscrowStatus = "held"
+	// EscrowStatusDisputed is held until merchant resolves dispute, disputed escrow does not expire
+	EscrowStatusDisputed EscrowStatus = "disputed"
+	EscrowStatusReleased EscrowStatus = "released"
+	EscrowStatusRefunded EscrowStatus = "refunded"
+	// EscrowStatusSplit means that part of amount was released to seller and the rest was refunded to buyer
+	EscrowStatusSplit EscrowStatus = "split"
+)
+
+// EscrowReleaseCondition names a party which confirms order, so that escrow is released to seller
+type EscrowReleaseCondition string
+
+const (
+	EscrowReleaseConditionBuyerConfirmation    EscrowReleaseCondition = "buyer_confirmation"
+	EscrowReleaseConditionMerchantConfirmation EscrowReleaseCondition = "merchant_confirmation"
+)
+
+// EscrowExpiryAction is applied to escrow which is still held at ExpiresAt
+type EscrowExpiryAction string
+
+const (
+	EscrowExpiryActionRelease EscrowExpiryAction = "release"
+	EscrowExpiryActionRefund  EscrowExpiryAction = "refund"
+)
+
+// Escrow holds funds of buyer for order of marketplace merchant until they are released to seller,
+// refunded to buyer or split between them. Amounts are in minor currency units.
+type Escrow struct {
+	GeneratedID      string
+	MerchantID       string
+	OrderID          string
+	BuyerID          string
+	SellerID         string
+	Amount           int64
+	Currency         string
+	ReleaseCondition EscrowReleaseCondition
+	ExpiryAction     EscrowExpiryAction
+	Status           EscrowStatus
+	// SellerAmount is paid to seller and the rest of Amount is paid to buyer when escrow is settled
+	SellerAmount  int64
+	DisputeReason string
+	// DeviceID and IP of buyer are scored by risk assessment of escrow, they are kept with assessment
+	DeviceID string
+	IP       string
+	// RiskAssessmentID refers to assessment scored before buyer funds escrow, escrow is funded unless it is declined
+	RiskAssessmentID string
+	RiskDecision     RiskDecision
+	ExpiresAt        time.Time
+	SettledAt        time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// CanTransitionTo allows held -> disputed and settlement of held or disputed escrow
+func (e *Escrow) CanTransitionTo(status EscrowStatus) bool {
+	switch e.Status {
+	case EscrowStatusHeld:
+		return status != EscrowStatusHeld
+	case EscrowStatusDisputed:
+		return status == EscrowStatusReleased || status == EscrowStatusRefunded || status == EscrowStatusSplit
+	default:
+		return false
+	}
+}
+
+// SettlementStatus is a final status of escrow which pays sellerAmount to seller and the rest to buyer
+func (e *Escrow) SettlementStatus(sellerAmount int64) EscrowStatus {
+	switch sellerAmount {
+	case e.Amount:
+		return EscrowStatusReleased
+	case 0:
+		return EscrowStatusRefunded
+	default:
+		return EscrowStatusSplit
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/fee_repository_mock.go -package=mocks . FeeRepository
+
+type FeeRepository interface {
+	CreateSchedule(schedule *FeeSchedule) error
+	FindScheduleByVersion(version int) (schedule *FeeSchedule, err error)
+	// FindEffectiveSchedule returns the latest schedule which became effective not later than at
+	FindEffectiveSchedule(at time.Time) (schedule *FeeSchedule, err error)
+	FindLatestSchedule() (schedule *FeeSchedule, err error)
+}
+
+// FeeSchedule is never changed after creation, new fees are introduced by a new version.
+// Money movements keep the version they were charged under.
+type FeeSchedule struct {
+	Version       int
+	EffectiveFrom time.Time
+	Rules         []FeeRule
+	CreatedAt     time.Time
+}
+
+// FeeRule sets fee of operation in currency, rule with empty currency applies to all other currencies.
+// Fee is Fixed plus BasisPoints of amount (1 bp = 0.01%) limited by Min and Max, zero Max means no cap.
+// When Tiers are set, the tier with the greatest From not exceeding amount replaces Fixed and BasisPoints.
+type FeeRule struct {
+	Operation   MovementType
+	Currency    string
+	Fixed       int64
+	BasisPoints int64
+	Tiers       []FeeTier
+	Min         int64
+	Max         int64
+}
+
+type FeeTier struct {
+	From        int64
+	Fixed       int64
+	BasisPoints int64
+}
+
+// FeeQuote is a fee for operation calculated by schedule of given version. Amounts are in minor currency units.
+type FeeQuote struct {
+	ScheduleVersion int
+	Operation       MovementType
+	Currency        string
+	Amount          int64
+	Fee             int64
+}
@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/invoice_repository_mock.go -package=mocks . InvoiceRepository
+
+type InvoiceRepository interface {
+	Create(invoice *Invoice) error
+	FindByID(invoiceID string) (invoice *Invoice, err error)
+	FindByCustomerID(customerID string) (invoices []*Invoice, err error)
+	// Update saves invoice only when it is still in expectedStatus, returns false when status was changed meanwhile
+	Update(invoice *Invoice, expectedStatus InvoiceStatus) (bool, error)
+	// Pay saves paid invoice and postings of debit in one transaction, returns false when invoice is not open
+	// anymore. Debit fails with ErrInsufficientFunds when it exceeds available balance of customer.
+	Pay(invoice *Invoice, debit *Debit) (bool, error)
+	// Finalize takes the next number of invoice tenant sequence and opens draft invoice in the same transaction,
+	// so numbers have no gaps. Returns false when invoice is not a draft anymore.
+	Finalize(invoice *Invoice) (bool, error)
+}
+
+type InvoiceStatus string
+
+const (
+	InvoiceStatusDraft InvoiceStatus = "draft"
+	InvoiceStatusOpen  InvoiceStatus = "open"
+	InvoiceStatusPaid  InvoiceStatus = "paid"
+	InvoiceStatusVoid  InvoiceStatus = "void"
+)
+
+// Invoice is issued by tenant to customer. Number is assigned from tenant sequence when draft is finalized.
+// Amounts are in minor currency units.
+type Invoice struct {
+	GeneratedID string
+	TenantID    string
+	CustomerID  string
+	Number      int64
+	Status      InvoiceStatus
+	Currency    string
+	Lines       []InvoiceLine
+	Subtotal    int64
+	VATTotal    int64
+	Total       int64
+	DueDate     time.Time
+	IssuedAt    time.Time
+	PaidAt      time.Time
+	VoidedAt    time.Time
+	// RiskAssessmentID refers to assessment scored before invoice was paid, invoice is paid unless it is declined
+	RiskAssessmentID string
+	RiskDecision     RiskDecision
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// InvoiceLine has Quantity and VATRate as decimal strings, VATRate is in percent.
+// Net, VAT and Total are calculated from them.
+type InvoiceLine struct {
+	Description string
+	Quantity    string
+	UnitPrice   int64
+	VATRate     string
+	Net         int64
+	VAT         int64
+	Total       int64
+}
+
+// InvoiceVATAmount sums lines of invoice with the same VAT rate
+type InvoiceVATAmount struct {
+	VATRate string
+	Net     int64
+	VAT     int64
+}
+
+// CanTransitionTo allows draft -> open -> paid, draft -> void and open -> void
+func (i *Invoice) CanTransitionTo(status InvoiceStatus) bool {
+	switch i.Status {
+	case InvoiceStatusDraft:
+		return status == InvoiceStatusOpen || status == InvoiceStatusVoid
+	case InvoiceStatusOpen:
+		return status == InvoiceStatusPaid || status == InvoiceStatusVoid
+	default:
+		return false
+	}
+}
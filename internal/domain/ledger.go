@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+//go:generate mockgen -destination=../postgres/mocks/ledger_repository_mock.go -package=mocks . LedgerRepository
+
+// LedgerRepository posts debits which are not saved as a part of other operation
+type LedgerRepository interface {
+	// Post saves postings of debit in one transaction. Customer payer is locked and debit fails with
+	// ErrInsufficientFunds when amount with fee exceeds available balance of payer or with LimitExceededError
+	// when it breaches limits of payer. Debit with the same reference is posted once, false is returned when it
+	// was posted before.
+	Post(debit *Debit) (posted bool, err error)
+}
+
+// DebitFlow is the balance debit flow of the service
+type DebitFlow interface {
+	// Prepare builds postings of debit, which are saved by repository of operation in its transaction
+	Prepare(debit *Debit) error
+	// Transfer prepares debit and posts it. Transfer with the same reference is applied once.
+	Transfer(debit *Debit) error
+	// Monitor reports committed debit to transaction monitoring
+	Monitor(debit *Debit)
+}
+
+// ErrInsufficientFunds is returned when debit exceeds available balance of payer
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// Ledger accounts of the service are counterparts of customers in postings. They are not customers,
+// so their balance is neither locked nor checked on debit.
+const (
+	LedgerAccountEscrow = "ledger:escrow"
+	// LedgerAccountSubscriptions is credited with charges of subscription invoices
+	LedgerAccountSubscriptions = "ledger:subscriptions"
+	// LedgerAccountPayouts holds withdrawn amounts of payouts till bank settles or rejects them
+	LedgerAccountPayouts = "ledger:payouts"
+	// LedgerAccountFees is a revenue account credited with fees of money movements
+	LedgerAccountFees = "ledger:fees"
+	// LedgerAccountDeposits holds principal of open deposits of customers including capitalised interest
+	LedgerAccountDeposits = "ledger:deposits"
+	// LedgerAccountAccruedInterest holds interest accrued on deposits since their last capitalisation
+	LedgerAccountAccruedInterest = "ledger:accrued-interest"
+	// LedgerAccountInterestExpense is debited with interest accrued on deposits and credited with penalties
+	LedgerAccountInterestExpense = "ledger:interest-expense"
+	// LedgerAccountLoans is a loan book which holds outstanding principal of loans disbursed to customers
+	LedgerAccountLoans = "ledger:loans"
+	// LedgerAccountInterestIncome is a revenue account credited with interest and penalties charged to customers
+	LedgerAccountInterestIncome = "ledger:interest-income"
+	// LedgerAccountDisputes holds amounts of open disputes debited from merchants till disputes are resolved
+	LedgerAccountDisputes = "ledger:disputes"
+	// LedgerAccountCardSettlement holds captured card authorizations till card network settles them with the bank
+	LedgerAccountCardSettlement = "ledger:card-settlement"
+
+	ledgerAccountPrefix       = "ledger:"
+	tenantLedgerAccountPrefix = "ledger:tenant:"
+)
+
+// TenantLedgerAccount is an account which invoices of tenant are paid to
+func TenantLedgerAccount(tenantID string) string {
+	return tenantLedgerAccountPrefix + tenantID
+}
+
+// IsLedgerAccount tells whether account is a ledger account of the service rather than a customer
+func IsLedgerAccount(accountID string) bool {
+	return strings.HasPrefix(accountID, ledgerAccountPrefix)
+}
+
+// Debit moves Amount from payer to payee. Payer and payee are customers or ledger accounts, Postings
+// of both legs are built by debit flow and saved in one transaction. Amount is in minor currency units.
+type Debit struct {
+	PayerID string
+	PayeeID string
+	// Credits split Amount among several payees instead of PayeeID, their amounts sum to Amount
+	Credits     []DebitCredit
+	Amount      int64
+	Currency    string
+	Description string
+	// Reference identifies operation which made debit, like invoice:<id>
+	Reference string
+	// Limit is charged when payer is a customer, debit fails with LimitExceededError when it breaches limits
+	Limit *LimitCharge
+	// Operation prices Fee of debit by fee schedule, debit without operation is not charged a fee
+	Operation MovementType
+	// Fee is debited from payer on top of Amount and credited to LedgerAccountFees,
+	// FeeScheduleVersion is a version of fee schedule which priced it
+	Fee                int64
+	FeeScheduleVersion int
+	Postings           []*Posting
+	PostedAt           time.Time
+}
+
+// DebitCredit is a share of debit amount credited to payee
+type DebitCredit struct {
+	PayeeID string
+	Amount  int64
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+//go:generate mockgen -destination=../postgres/mocks/limit_repository_mock.go -package=mocks . LimitRepository
+
+type LimitRepository interface {
+	FindTierLimits(level VerificationLevel, currency string) (limits *TransactionLimits, err error)
+	FindCustomerLimits(customerID string, currency string) (limits *TransactionLimits, err error)
+	SaveCustomerLimits(customerID string, currency string, limits TransactionLimits) error
+	DeleteCustomerLimits(customerID string, currency string) error
+	// FindUsage returns usage of customer in currency for the day and the month of at
+	FindUsage(customerID string, currency string, at time.Time) (usage LimitUsage, err error)
+}
+
+// TransactionLimits are amounts in minor currency units and numbers of transactions,
+// zero means money movement is not allowed
+type TransactionLimits struct {
+	PerTransaction int64
+	Daily          int64
+	Monthly        int64
+	DailyCount     int64
+	MonthlyCount   int64
+}
+
+// LimitCharge is a debit of customer which is checked against Limits and recorded in usage of customer
+// in currency by repository of debit, in transaction of debit
+type LimitCharge struct {
+	CustomerID string
+	Currency   string
+	Amount     int64
+	Limits     TransactionLimits
+	ChargedAt  time.Time
+}
+
+// LimitUsage is an amount and a number of transactions made by customer during current day and month
+type LimitUsage struct {
+	DailyAmount   int64
+	MonthlyAmount int64
+	DailyCount    int64
+	MonthlyCount  int64
+}
+
+// Check returns LimitExceededError when transaction of amount on top of usage would breach one of limits
+func (l TransactionLimits) Check(usage LimitUsage, amount int64) error {
+	switch {
+	case amount > l.PerTransaction:
+		return &LimitExceededError{Limit: "per_transaction", Allowed: l.PerTransaction, Requested: amount}
+	case usage.DailyAmount+amount > l.Daily:
+		return &LimitExceededError{Limit: "daily", Allowed: l.Daily, Used: usage.DailyAmount, Requested: amount}
+	case usage.MonthlyAmount+amount > l.Monthly:
+		return &LimitExceededError{Limit: "monthly", Allowed: l.Monthly, Used: usage.MonthlyAmount, Requested: amount}
+	case usage.DailyCount+1 > l.DailyCount:
+		return &LimitExceededError{Limit: "daily_count", Allowed: l.DailyCount, Used: usage.DailyCount, Requested: 1}
+	case usage.MonthlyCount+1 > l.MonthlyCount:
+		return &LimitExceededError{
+			Limit: "monthly_count", Allowed: l.MonthlyCount, Used: usage.MonthlyCount, Requested: 1,
+		}
+	}
+	return nil
+}
+
+// Remaining returns allowance left after usage, never negative
+func (l TransactionLimits) Remaining(usage LimitUsage) LimitUsage {
+	return LimitUsage{
+		DailyAmount:   nonNegative(minInt64(l.Daily-usage.DailyAmount, l.Monthly-usage.MonthlyAmount)),
+		MonthlyAmount: nonNegative(l.Monthly - usage.MonthlyAmount),
+		DailyCount:    nonNegative(minInt64(l.DailyCount-usage.DailyCount, l.MonthlyCount-usage.MonthlyCount)),
+		MonthlyCount:  nonNegative(l.MonthlyCount - usage.MonthlyCount),
+	}
+}
+
+type LimitExceededError struct {
+	Limit     string
+	Allowed   int64
+	Used      int64
+	Requested int64
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s limit exceeded", e.Limit)
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func nonNegative(value int64) int64 {
+	if value < 0 {
+		return 0
+	}
+	return value
+}
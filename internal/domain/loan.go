@@ -0,0 +1,187 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/loan_repository_mock.go -package=mocks . LoanRepository
+
+type LoanRepository interface {
+	CreateProduct(product *LoanProduct) error
+	FindProductByID(productID string) (product *LoanProduct, err error)
+	FindProducts() (products []*LoanProduct, err error)
+	CreateApplication(application *LoanApplication) error
+	FindApplicationByID(applicationID string) (application *LoanApplication, err error)
+	FindApplicationsByCustomerID(customerID string) (applications []*LoanApplication, err error)
+	// Disburse locks application and passes it to disburse, then saves application with loan, its schedule
+	// and postings returned by disburse in the same transaction when disburse returns no error.
+	// Returns nil loan when there is no application with such id.
+	Disburse(
+		applicationID string,
+		disburse func(application *LoanApplication) (*Loan, []*Posting, error),
+	) (*Loan, error)
+	// FindByID finds loan with its schedule
+	FindByID(loanID string) (loan *Loan, err error)
+	FindByCustomerID(customerID string) (loans []*Loan, err error)
+	// Update locks loan and balance of its customer in loan currency and passes loan with funds of customer
+	// to update, loans are not repaid from credit limit.
+	// Loan is saved with its schedule, repayment and postings returned by update in the same transaction
+	// when update returns no error. Returns nil loan when there is no loan with such id.
+	Update(
+		loanID string,
+		update func(loan *Loan, balance int64) (*LoanRepayment, []*Posting, error),
+	) (*Loan, error)
+	// ClaimDue locks up to limit active and overdue loans which next service day is before or at today,
+	// skipping loans locked by other instances, and passes each to service with funds of its customer.
+	// Loans are saved as Update saves them.
+	ClaimDue(
+		today time.Time,
+		limit int,
+		service func(loan *Loan, balance int64) (*LoanRepayment, []*Posting, error),
+	) (int, error)
+	FindRepayments(loanID string) (repayments []*LoanRepayment, err error)
+}
+
+// LoanProduct is an offer of consumer loans. Rates are decimal annual percents, like 19.9.
+type LoanProduct struct {
+	GeneratedID string
+	Name        string
+	Currency    string
+	AnnualRate  string
+	// PenaltyRate is charged on overdue installments for every day of delay
+	PenaltyRate string
+	// MinAmount and MaxAmount limit principal in minor currency units
+	MinAmount     int64
+	MaxAmount     int64
+	MinTermMonths int
+	MaxTermMonths int
+	CreatedAt     time.Time
+}
+
+// AmortisationType tells how principal is repaid by monthly installments
+type AmortisationType string
+
+const (
+	// AmortisationAnnuity repays loan with equal installments
+	AmortisationAnnuity AmortisationType = "annuity"
+	// AmortisationDifferentiated repays equal parts of principal with interest on outstanding principal
+	AmortisationDifferentiated AmortisationType = "differentiated"
+)
+
+type LoanApplicationStatus string
+
+const (
+	LoanApplicationStatusApproved  LoanApplicationStatus = "approved"
+	LoanApplicationStatusRejected  LoanApplicationStatus = "rejected"
+	LoanApplicationStatusDisbursed LoanApplicationStatus = "disbursed"
+)
+
+// LoanApplication of customer. Application is decided when it is made, approved application waits to be disbursed.
+type LoanApplication struct {
+	GeneratedID  string
+	ProductID    string
+	CustomerID   string
+	Amount       int64
+	Currency     string
+	TermMonths   int
+	Amortisation AmortisationType
+	Status       LoanApplicationStatus
+	// RejectionReason is set for rejected applications
+	RejectionReason string
+	// LoanID is set for disbursed applications
+	LoanID    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type LoanStatus string
+
+const (
+	LoanStatusActive LoanStatus = "active"
+	// LoanStatusOverdue has installments which are not repaid after due day, penalty is accrued on them daily
+	LoanStatusOverdue LoanStatus = "overdue"
+	LoanStatusRepaid  LoanStatus = "repaid"
+)
+
+// Loan of customer. Amounts are in minor currency units. Rates are copied from product on disbursement.
+// Dates are days in statement time zone.
+type Loan struct {
+	GeneratedID   string
+	ApplicationID string
+	ProductID     string
+	CustomerID    string
+	Currency      string
+	Principal     int64
+	// Outstanding is principal which is not repaid yet
+	Outstanding  int64
+	AnnualRate   string
+	PenaltyRate  string
+	Amortisation AmortisationType
+	TermMonths   int
+	Status       LoanStatus
+	// Penalty is accrued on overdue installments and is not repaid yet
+	Penalty int64
+	// PenaltyAccruedUntil is the first day which penalty is not accrued for yet
+	PenaltyAccruedUntil time.Time
+	// InterestPaidUntil is a day which interest of the current month is paid until by early repayment
+	InterestPaidUntil time.Time
+	// NextServiceDay is a day when loan is serviced next, it is due day of the next installment for active loans
+	// and every day for overdue loans
+	NextServiceDay time.Time
+	// Repayments counts repayments of loan
+	Repayments   int
+	Installments []*LoanInstallment
+	DisbursedAt  time.Time
+	RepaidAt     time.Time
+	UpdatedAt    time.Time
+}
+
+type LoanInstallmentStatus string
+
+const (
+	LoanInstallmentStatusPending LoanInstallmentStatus = "pending"
+	LoanInstallmentStatusOverdue LoanInstallmentStatus = "overdue"
+	LoanInstallmentStatusPaid    LoanInstallmentStatus = "paid"
+	// LoanInstallmentStatusCancelled is not due anymore because loan is repaid early
+	LoanInstallmentStatusCancelled LoanInstallmentStatus = "cancelled"
+)
+
+// LoanInstallment is a monthly payment of loan schedule
+type LoanInstallment struct {
+	LoanID        string
+	Number        int
+	DueDay        time.Time
+	Principal     int64
+	Interest      int64
+	PrincipalPaid int64
+	InterestPaid  int64
+	Status        LoanInstallmentStatus
+	PaidAt        time.Time
+}
+
+// Amount of installment
+func (i *LoanInstallment) Amount() int64 {
+	return i.Principal + i.Interest
+}
+
+// Unpaid part of installment
+func (i *LoanInstallment) Unpaid() int64 {
+	return i.Principal + i.Interest - i.PrincipalPaid - i.InterestPaid
+}
+
+type LoanRepaymentType string
+
+const (
+	LoanRepaymentTypeScheduled LoanRepaymentType = "scheduled"
+	LoanRepaymentTypeEarly     LoanRepaymentType = "early"
+)
+
+// LoanRepayment is a payment of customer split between penalty, interest and principal of loan
+type LoanRepayment struct {
+	LoanID    string
+	Number    int
+	Type      LoanRepaymentType
+	Amount    int64
+	Penalty   int64
+	Interest  int64
+	Principal int64
+	PaidAt    time.Time
+}
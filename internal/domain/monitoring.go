@@ -0,0 +1,80 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/monitoring_repository_mock.go -package=mocks . MonitoringRepository
+
+type MonitoringRepository interface {
+	CreateMovement(movement *Movement) error
+	// FindMovements returns movements of customer created after since
+	FindMovements(customerID string, since time.Time) (movements []*Movement, err error)
+	CreateAlerts(alerts []*Alert) error
+	FindAlertByID(alertID string) (alert *Alert, err error)
+	FindAlertsByStatus(status AlertStatus) (alerts []*Alert, err error)
+	UpdateAlert(alert *Alert) error
+}
+
+type MovementType string
+
+const (
+	MovementTypeDeposit    MovementType = "deposit"
+	MovementTypeWithdrawal MovementType = "withdrawal"
+	MovementTypeTransfer   MovementType = "transfer"
+	MovementTypeConversion MovementType = "conversion"
+)
+
+// Movement is a money movement reported to transaction monitoring.
+// Amount is in minor currency units.
+type Movement struct {
+	GeneratedID string
+	CustomerID  string
+	Type        MovementType
+	Amount      int64
+	Currency    string
+	CreatedAt   time.Time
+}
+
+type AlertStatus string
+
+const (
+	AlertStatusOpen          AlertStatus = "open"
+	AlertStatusInvestigating AlertStatus = "investigating"
+	AlertStatusClosed        AlertStatus = "closed"
+)
+
+type AlertResolution string
+
+const (
+	AlertResolutionNone          AlertResolution = ""
+	AlertResolutionFalsePositive AlertResolution = "false_positive"
+	// AlertResolutionReported means analyst reported the movement to the financial intelligence unit
+	AlertResolutionReported AlertResolution = "reported"
+)
+
+type Alert struct {
+	GeneratedID string
+	CustomerID  string
+	MovementID  string
+	RuleName    string
+	RuleType    string
+	Severity    string
+	Details     string
+	Status      AlertStatus
+	Resolution  AlertResolution
+	Assignee    string
+	Comment     string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CanTransitionTo allows open -> investigating -> closed and open -> closed
+func (a *Alert) CanTransitionTo(status AlertStatus) bool {
+	switch a.Status {
+	case AlertStatusOpen:
+		return status == AlertStatusInvestigating || status == AlertStatusClosed
+	case AlertStatusInvestigating:
+		return status == AlertStatusClosed
+	default:
+		return false
+	}
+}
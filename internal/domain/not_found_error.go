@@ -0,0 +1,13 @@
+package domain
+
+type NotFoundError struct {
+	errStr string
+}
+
+func NewNotFoundError(text string) error {
+	return &NotFoundError{text}
+}
+
+func (e *NotFoundError) Error() string {
+	return e.errStr
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/p2p_repository_mock.go -package=mocks . P2PRepository
+
+type P2PRepository interface {
+	CreatePhoneLookup(lookup *PhoneLookup) error
+	// CountLookedUpPhones counts distinct phones other than exceptPhone looked up by customer since given time
+	CountLookedUpPhones(customerID string, exceptPhone string, since time.Time) (int, error)
+	// CreateTransfer saves transfer and postings of debit in one transaction.
+	// Debit fails with ErrInsufficientFunds when it exceeds available balance of sender.
+	CreateTransfer(transfer *P2PTransfer, debit *Debit) error
+	FindTransferByID(transferID string) (transfer *P2PTransfer, err error)
+}
+
+// PhoneLookup is a search of customer by phone, lookups are rate limited against phone enumeration
+type PhoneLookup struct {
+	CustomerID string
+	Phone      string
+	Found      bool
+	CreatedAt  time.Time
+}
+
+// P2PRecipient is shown to sender for confirmation, name is masked like Bruce W.
+type P2PRecipient struct {
+	Phone      string
+	MaskedName string
+}
+
+// P2PTransfer is a transfer of customer to another customer addressed by phone. Amount is in minor currency units.
+type P2PTransfer struct {
+	GeneratedID         string
+	SenderID            string
+	RecipientID         string
+	RecipientPhone      string
+	RecipientMaskedName string
+	Amount              int64
+	Currency            string
+	Comment             string
+	// BeneficiaryID is set when transfer is paid to saved beneficiary
+	BeneficiaryID string
+	// DeviceID and IP of sender are scored by risk assessment of transfer, they are kept with assessment
+	DeviceID string
+	IP       string
+	// RiskAssessmentID refers to assessment scored before transfer, transfer is made unless it is declined
+	RiskAssessmentID string
+	RiskDecision     RiskDecision
+	CreatedAt        time.Time
+}
@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/payment_schedule_repository_mock.go -package=mocks . PaymentScheduleRepository
+
+type PaymentScheduleRepository interface {
+	Create(schedule *PaymentSchedule) error
+	FindByID(scheduleID string) (schedule *PaymentSchedule, err error)
+	FindByCustomerID(customerID string) (schedules []*PaymentSchedule, err error)
+	Update(schedule *PaymentSchedule) error
+	// ClaimDueSchedules locks up to limit active schedules with NextRunAt not later than now,
+	// skipping schedules locked by other instances, and passes each to plan. Schedule changes
+	// and executions returned by plan are saved in the same transaction.
+	ClaimDueSchedules(now time.Time, limit int, plan func(schedule *PaymentSchedule) *ScheduleExecution) (int, error)
+	// ClaimExecutions takes up to limit pending executions with NextAttemptAt not later than now,
+	// skipping executions locked by other instances, and postpones their NextAttemptAt till leaseUntil
+	// so that execution is retried by other instance if this one dies.
+	ClaimExecutions(now time.Time, leaseUntil time.Time, limit int) (executions []*ScheduleExecution, err error)
+	UpdateExecution(execution *ScheduleExecution) error
+	FindExecutions(scheduleID string) (executions []*ScheduleExecution, err error)
+}
+
+type PaymentScheduleStatus string
+
+const (
+	PaymentScheduleStatusActive    PaymentScheduleStatus = "active"
+	PaymentScheduleStatusFinished  PaymentScheduleStatus = "finished"
+	PaymentScheduleStatusCancelled PaymentScheduleStatus = "cancelled"
+)
+
+// PaymentSchedule is a standing order of payer to pay Amount to payee by Recurrence rule.
+// Recurrence is evaluated in Timezone starting at StartAt; schedule finishes after EndAt when it is set.
+type PaymentSchedule struct {
+	GeneratedID string
+	CustomerID  string
+	PayeeID     string
+	Amount      int64
+	Currency    string
+	Recurrence  string
+	Timezone    string
+	StartAt     time.Time
+	EndAt       time.Time
+	NextRunAt   time.Time
+	Status      PaymentScheduleStatus
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type ScheduleExecutionStatus string
+
+const (
+	ScheduleExecutionStatusPending   ScheduleExecutionStatus = "pending"
+	ScheduleExecutionStatusSucceeded ScheduleExecutionStatus = "succeeded"
+	// ScheduleExecutionStatusFailed is final, execution failed on every attempt
+	ScheduleExecutionStatusFailed ScheduleExecutionStatus = "failed"
+)
+
+type ScheduleExecution struct {
+	GeneratedID   string
+	ScheduleID    string
+	DueAt         time.Time
+	Status        ScheduleExecutionStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,90 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/payout_repository_mock.go -package=mocks . PayoutRepository
+
+type PayoutRepository interface {
+	// Create saves payout and postings of debit which withdraws payout amount from customer in one transaction.
+	// Debit fails with ErrInsufficientFunds when it exceeds available balance of customer.
+	Create(payout *Payout, debit *Debit) error
+	FindByID(payoutID string) (payout *Payout, err error)
+	FindByCustomerID(customerID string) (payouts []*Payout, err error)
+	// SubmitPending locks up to limit oldest pending payouts, skipping payouts locked by other instances,
+	// and passes them to build. Batch returned by build is saved and payout changes made by build
+	// are saved in the same transaction. Returns number of submitted payouts.
+	SubmitPending(limit int, build func(payouts []*Payout) (*PayoutBatch, error)) (int, error)
+	FindBatchByID(batchID string) (batch *PayoutBatch, err error)
+	// UpdateSubmitted changes status of submitted payouts selected by update, returns number of changed payouts.
+	// Every rejected payout is passed to refund and debit returned by refund is saved in the same transaction.
+	UpdateSubmitted(
+		update *PayoutStatusUpdate,
+		updatedAt time.Time,
+		refund func(payout *Payout) (*Debit, error),
+	) (int, error)
+}
+
+type PayoutStatus string
+
+const (
+	PayoutStatusPending PayoutStatus = "pending"
+	// PayoutStatusSubmitted means payout is sent to bank in credit transfer initiation file
+	PayoutStatusSubmitted PayoutStatus = "submitted"
+	PayoutStatusSettled   PayoutStatus = "settled"
+	PayoutStatusRejected  PayoutStatus = "rejected"
+)
+
+// Payout is a withdrawal of customer to external bank account. Amount is in minor currency units.
+type Payout struct {
+	GeneratedID    string
+	CustomerID     string
+	Amount         int64
+	Currency       string
+	CreditorName   string
+	CreditorIBAN   string
+	CreditorBIC    string
+	RemittanceInfo string
+	Status         PayoutStatus
+	// BatchID is an id of file payout is submitted in, it is a message id of the file as well
+	BatchID string
+	// PaymentInfoID is an id of payment information block of the file, payouts are grouped by currency
+	PaymentInfoID string
+	RejectReason  string
+	// BeneficiaryID is set when payout is paid to saved beneficiary
+	BeneficiaryID string
+	// DeviceID and IP of customer are scored by risk assessment of payout, they are kept with assessment
+	DeviceID string
+	IP       string
+	// RiskAssessmentID refers to assessment scored before payout, payout is made unless it is declined
+	RiskAssessmentID string
+	RiskDecision     RiskDecision
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// PayoutBatch is a pain.001 credit transfer initiation file sent to bank.
+// ControlSum is a sum of payout amounts in minor units irrespective of currencies.
+type PayoutBatch struct {
+	GeneratedID          string
+	NumberOfTransactions int
+	ControlSum           int64
+	Document             []byte
+	CreatedAt            time.Time
+}
+
+// PayoutStatusUpdate is a final status reported by bank for all payouts of batch, payouts of payment information
+// block when PaymentInfoID is set or a single payout when PayoutID is set
+type PayoutStatusUpdate struct {
+	BatchID       string
+	PaymentInfoID string
+	PayoutID      string
+	Status        PayoutStatus
+	Reason        string
+}
+
+// PayoutStatusReport is a result of pain.002 payment status report ingestion
+type PayoutStatusReport struct {
+	BatchID  string
+	Settled  int
+	Rejected int
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/posting_repository_mock.go -package=mocks . PostingRepository
+
+type PostingRepository interface {
+	Create(posting *Posting) error
+	// FindBalance sums postings of customer in currency posted before given time
+	FindBalance(customerID string, currency string, before time.Time) (balance int64, err error)
+	// StreamPostings passes postings of customer in currency posted in [from, to) to handle one by one
+	// in order of posting, without loading all of them into memory. Error of handle stops streaming.
+	StreamPostings(customerID string, currency string, from, to time.Time, handle func(posting *Posting) error) error
+	// FindByReference finds postings of operation with reference, like qr_payment:<id>
+	FindByReference(reference string) (postings []*Posting, err error)
+}
+
+// Posting is an entry of customer account, Amount in minor currency units is positive for credit
+// and negative for debit
+type Posting struct {
+	GeneratedID string
+	CustomerID  string
+	Amount      int64
+	Currency    string
+	Description string
+	// Reference identifies operation which made posting, like invoice:<id>
+	Reference string
+	// FeeScheduleVersion is a version of fee schedule which priced fee posting, zero for other postings
+	FeeScheduleVersion int
+	PostedAt           time.Time
+}
+
+// Statement lists postings of customer in currency between From and To dates inclusive
+type Statement struct {
+	CustomerID     string
+	CustomerName   string
+	Currency       string
+	From           time.Time
+	To             time.Time
+	OpeningBalance int64
+}
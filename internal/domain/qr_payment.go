@@ -0,0 +1,77 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/qr_payment_repository_mock.go -package=mocks . QRPaymentRepository
+
+type QRPaymentRepository interface {
+	CreateRequest(request *QRPaymentRequest) error
+	FindRequestByID(requestID string) (request *QRPaymentRequest, err error)
+	FindRequestsByMerchantID(merchantID string) (requests []*QRPaymentRequest, err error)
+	// AddPayment saves payment of request, postings of debit and changes request status to requestStatus
+	// in the same transaction. Returns false and saves nothing when request is not active or is expired
+	// at PaidAt of payment. Debit fails with ErrInsufficientFunds when it exceeds available balance of payer.
+	AddPayment(payment *QRPayment, requestStatus QRPaymentRequestStatus, debit *Debit) (bool, error)
+	FindPayments(requestID string) (payments []*QRPayment, err error)
+	FindPaymentByID(paymentID string) (payment *QRPayment, err error)
+}
+
+type QRPaymentRequestType string
+
+const (
+	// QRPaymentRequestTypeStatic is printed once and is paid any number of times, amount is optional
+	QRPaymentRequestTypeStatic QRPaymentRequestType = "static"
+	// QRPaymentRequestTypeDynamic is made for a single purchase and is paid once
+	QRPaymentRequestTypeDynamic QRPaymentRequestType = "dynamic"
+)
+
+type QRPaymentRequestStatus string
+
+const (
+	QRPaymentRequestStatusActive QRPaymentRequestStatus = "active"
+	QRPaymentRequestStatusPaid   QRPaymentRequestStatus = "paid"
+	// QRPaymentRequestStatusExpired is not saved, active request is reported expired after ExpiresAt
+	QRPaymentRequestStatusExpired QRPaymentRequestStatus = "expired"
+)
+
+// QRPaymentRequest is a request of merchant to pay by scanning QR code. Amount is in minor currency units,
+// zero amount of static request means that payer enters amount. Request never expires when ExpiresAt is zero.
+// PaidAt is a time of the last payment.
+type QRPaymentRequest struct {
+	GeneratedID string
+	MerchantID  string
+	Type        QRPaymentRequestType
+	Amount      int64
+	Currency    string
+	Purpose     string
+	Status      QRPaymentRequestStatus
+	ExpiresAt   time.Time
+	PaidAt      time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// StatusAt is a status of request at given time
+func (r *QRPaymentRequest) StatusAt(now time.Time) QRPaymentRequestStatus {
+	if r.Status == QRPaymentRequestStatusActive && !r.ExpiresAt.IsZero() && !now.Before(r.ExpiresAt) {
+		return QRPaymentRequestStatusExpired
+	}
+	return r.Status
+}
+
+// QRPayment is a payment of customer by QR payment request. Amount is in minor currency units.
+type QRPayment struct {
+	GeneratedID string
+	RequestID   string
+	MerchantID  string
+	PayerID     string
+	Amount      int64
+	Currency    string
+	// DeviceID and IP of payer are scored by risk assessment of payment, they are kept with assessment
+	DeviceID string
+	IP       string
+	// RiskAssessmentID refers to assessment scored before payment, payment is made unless it is declined
+	RiskAssessmentID string
+	RiskDecision     RiskDecision
+	PaidAt           time.Time
+}
@@ -0,0 +1,97 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/reconciliation_repository_mock.go -package=mocks . ReconciliationRepository
+
+type ReconciliationRepository interface {
+	// CreateStatement saves statement with its entries, returns false when statement was imported before
+	CreateStatement(statement *BankStatement) (bool, error)
+	FindEntryByID(entryID string) (entry *BankEntry, err error)
+	// FindEntries returns bank entries of account booked between from and to dates inclusive
+	FindEntries(account BankAccount, from, to time.Time) (entries []*BankEntry, err error)
+	FindUnmatchedEntries(account BankAccount, from, to time.Time) (entries []*BankEntry, err error)
+	// FindMovementByID returns payout or card settlement posting moving money through settlement bank account
+	FindMovementByID(movementID string) (movement *Movement, err error)
+	// FindUnmatchedMovements returns payouts submitted to bank and postings of LedgerAccountCardSettlement
+	// in currency created in [from, to) which are not matched to any bank entry
+	FindUnmatchedMovements(currency string, from, to time.Time) (movements []*Movement, err error)
+	// Match links bank entry to movement when neither of them is matched yet, returns false otherwise
+	Match(entryID string, movementID string, matchType BankEntryMatchType, matchedAt time.Time) (bool, error)
+}
+
+type BankEntryDirection string
+
+const (
+	BankEntryDirectionCredit BankEntryDirection = "credit"
+	BankEntryDirectionDebit  BankEntryDirection = "debit"
+)
+
+type BankEntryMatchType string
+
+const (
+	BankEntryMatchTypeNone BankEntryMatchType = ""
+	// BankEntryMatchTypeReference means bank entry references movement id
+	BankEntryMatchTypeReference BankEntryMatchType = "reference"
+	// BankEntryMatchTypeAmountDate means bank entry is the only one with amount of movement booked near its date
+	BankEntryMatchTypeAmountDate BankEntryMatchType = "amount_date"
+	BankEntryMatchTypeManual     BankEntryMatchType = "manual"
+)
+
+// BankAccount is a bank account of the service in one currency, statements are reconciled per account
+type BankAccount struct {
+	IBAN     string
+	Currency string
+}
+
+// BankStatement is an end of day statement of settlement account sent by bank.
+// Balances are in minor currency units, negative balance is a debit one.
+type BankStatement struct {
+	GeneratedID string
+	// BankStatementID is an id given by bank, unique for account
+	BankStatementID string
+	AccountIBAN     string
+	Currency        string
+	OpeningBalance  int64
+	ClosingBalance  int64
+	Entries         []*BankEntry
+	ImportedAt      time.Time
+}
+
+// BankEntry is a booked entry of bank statement. Amount is in minor currency units and is always positive,
+// Direction tells whether it is paid to or from settlement account.
+type BankEntry struct {
+	GeneratedID string
+	StatementID string
+	Direction   BankEntryDirection
+	Amount      int64
+	Currency    string
+	BookingDate time.Time
+	ValueDate   time.Time
+	// Reference is end to end id of transaction set by initiator
+	Reference string
+	// AccountServicerReference is an id of entry given by bank
+	AccountServicerReference string
+	RemittanceInfo           string
+	MovementID               string
+	MatchType                BankEntryMatchType
+	MatchedAt                time.Time
+	CreatedAt                time.Time
+}
+
+// ReconciliationReport lists bank entries and movements of period, unmatched ones need manual review
+type ReconciliationReport struct {
+	From              time.Time
+	To                time.Time
+	Matched           []*BankEntry
+	UnmatchedBank     []*BankEntry
+	UnmatchedInternal []*Movement
+}
+
+// BankStatementImport is a result of import of bank statements document
+type BankStatementImport struct {
+	Statements []*BankStatement
+	// Duplicates are ids of statements which were imported before, their entries are skipped
+	Duplicates []string
+	Matched    int
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/risk_repository_mock.go -package=mocks . RiskRepository
+
+type RiskRepository interface {
+	CreateAssessment(assessment *RiskAssessment) error
+	FindAssessmentByID(assessmentID string) (assessment *RiskAssessment, err error)
+	// FindAssessments returns assessments of customer created after since
+	FindAssessments(customerID string, since time.Time) (assessments []*RiskAssessment, err error)
+	FindDevices(customerID string) (devices []RiskDevice, err error)
+	// SaveDevice remembers device and IP address of customer or updates last seen time of known ones
+	SaveDevice(device RiskDevice) error
+}
+
+type RiskDecision string
+
+const (
+	RiskDecisionApprove RiskDecision = "approve"
+	RiskDecisionReview  RiskDecision = "review"
+	RiskDecisionDecline RiskDecision = "decline"
+)
+
+// RiskAssessment is a fraud risk score of a payment made before it is authorized.
+// Amount is in minor currency units.
+type RiskAssessment struct {
+	GeneratedID string
+	CustomerID  string
+	Amount      int64
+	Currency    string
+	DeviceID    string
+	IP          string
+	Score       int
+	Decision    RiskDecision
+	Features    []RiskFeature
+	CreatedAt   time.Time
+}
+
+// RiskFeature is a contribution of one feature to assessment score
+type RiskFeature struct {
+	Name  string
+	Value string
+	Score int
+}
+
+type RiskDevice struct {
+	CustomerID string
+	DeviceID   string
+	IP         string
+	LastSeenAt time.Time
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/sanction_repository_mock.go -package=mocks . SanctionRepository
+
+type SanctionRepository interface {
+	// ReplaceList atomically replaces all entries of list with given name
+	ReplaceList(listName string, entries []SanctionEntry) error
+	// FindCandidates returns entries with given birth date or without birth date
+	FindCandidates(birthDate time.Time) (entries []SanctionEntry, err error)
+	CreateMatches(matches []SanctionMatch) error
+	FindMatchesByCustomerID(customerID string) (matches []SanctionMatch, err error)
+	ResolveMatches(customerID string, decision SanctionDecision, reviewer string, comment string) error
+}
+
+type SanctionListType string
+
+const (
+	SanctionListTypeSanctions SanctionListType = "sanctions"
+	// SanctionListTypePEP is a list of politically exposed persons
+	SanctionListTypePEP SanctionListType = "pep"
+)
+
+type SanctionEntry struct {
+	ListName string
+	ListType SanctionListType
+	FullName string
+	// BirthDate is zero when list does not provide it
+	BirthDate time.Time
+}
+
+type SanctionDecision string
+
+const (
+	SanctionDecisionNone SanctionDecision = ""
+	// SanctionDecisionCleared means reviewer confirmed that customer is not the listed person
+	SanctionDecisionCleared SanctionDecision = "cleared"
+	// SanctionDecisionConfirmed means reviewer confirmed that customer is the listed person
+	SanctionDecisionConfirmed SanctionDecision = "confirmed"
+)
+
+// SanctionMatch keeps a copy of matched entry so auditors can see it after the list is reimported
+type SanctionMatch struct {
+	CustomerID     string
+	ListName       string
+	ListType       SanctionListType
+	EntryName      string
+	EntryBirthDate time.Time
+	MatchedName    string
+	Score          float64
+	Decision       SanctionDecision
+	Reviewer       string
+	Comment        string
+	CreatedAt      time.Time
+	ReviewedAt     time.Time
+}
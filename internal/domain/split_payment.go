@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/split_payment_repository_mock.go -package=mocks . SplitPaymentRepository
+
+type SplitPaymentRepository interface {
+	// Create saves payment with its legs and postings of debit in one transaction.
+	// Returns ErrInsufficientFunds or LimitExceededError when debit is rejected.
+	Create(payment *SplitPayment, debit *Debit) error
+	FindByID(paymentID string) (payment *SplitPayment, err error)
+	// FindReceivedLegs lists legs received by recipient in [from, to), the latest first
+	FindReceivedLegs(recipientID string, from, to time.Time) (legs []*SplitLeg, err error)
+}
+
+// SplitPayment is paid by payer and split among recipients, like seller, platform commission and delivery
+// partner. Amounts are in minor currency units, Amount of payment is a sum of leg amounts.
+type SplitPayment struct {
+	GeneratedID string
+	PayerID     string
+	Amount      int64
+	Currency    string
+	Description string
+	// Fee is debited from payer on top of Amount, it is saved with postings of payment only
+	Fee  int64
+	Legs []SplitLeg
+	// DeviceID and IP of payer are scored by risk assessment of payment, they are kept with assessment
+	DeviceID string
+	IP       string
+	// RiskAssessmentID refers to assessment scored before payment, payment is made unless it is declined
+	RiskAssessmentID string
+	RiskDecision     RiskDecision
+	CreatedAt        time.Time
+}
+
+// SplitLeg is a share of split payment received by recipient. Share is either a fixed Amount or Percent
+// of payment amount, Amount of percent share is calculated when payment is split.
+type SplitLeg struct {
+	PaymentID   string
+	RecipientID string
+	// Role describes recipient, like seller, platform or delivery
+	Role string
+	// Percent is a decimal string like 12.5, empty for fixed amount
+	Percent   string
+	Amount    int64
+	Currency  string
+	CreatedAt time.Time
+}
+
+// SplitTotal sums legs received by recipient in currency with the same role
+type SplitTotal struct {
+	Currency string
+	Role     string
+	Amount   int64
+	Count    int
+}
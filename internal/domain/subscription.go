@@ -0,0 +1,119 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/subscription_repository_mock.go -package=mocks . SubscriptionRepository
+
+type SubscriptionRepository interface {
+	CreatePlan(plan *Plan) error
+	FindPlanByID(planID string) (plan *Plan, err error)
+	FindPlans() (plans []*Plan, err error)
+	// CreateSubscription saves subscription together with invoice for its first period, invoice is nil on trial
+	CreateSubscription(subscription *Subscription, invoice *SubscriptionInvoice) error
+	FindSubscriptionByID(subscriptionID string) (subscription *Subscription, err error)
+	FindSubscriptionsByCustomerID(customerID string) (subscriptions []*Subscription, err error)
+	// UpdateSubscription locks subscription, passes it to update and saves it when update returns no error.
+	// Returns nil subscription when there is no subscription with such id.
+	UpdateSubscription(subscriptionID string, update func(subscription *Subscription) error) (*Subscription, error)
+	// ClaimDueSubscriptions locks up to limit not cancelled subscriptions with CurrentPeriodEnd not later
+	// than now, skipping subscriptions locked by other instances, and passes each to renew.
+	// Subscription changes and invoices returned by renew are saved in the same transaction.
+	ClaimDueSubscriptions(
+		now time.Time,
+		limit int,
+		renew func(subscription *Subscription) (*SubscriptionInvoice, error),
+	) (int, error)
+	// ClaimInvoices takes up to limit open invoices with NextAttemptAt not later than now,
+	// skipping invoices locked by other instances, and postpones their NextAttemptAt till leaseUntil
+	// so that charge is retried by other instance if this one dies.
+	ClaimInvoices(now time.Time, leaseUntil time.Time, limit int) (invoices []*SubscriptionInvoice, err error)
+	// UpdateInvoice saves invoice together with status of its subscription unless subscription is cancelled
+	UpdateInvoice(invoice *SubscriptionInvoice, subscription *Subscription) error
+	FindInvoices(subscriptionID string) (invoices []*SubscriptionInvoice, err error)
+}
+
+type PlanInterval string
+
+const (
+	PlanIntervalDay   PlanInterval = "day"
+	PlanIntervalWeek  PlanInterval = "week"
+	PlanIntervalMonth PlanInterval = "month"
+	PlanIntervalYear  PlanInterval = "year"
+)
+
+// Plan bills Amount every IntervalCount intervals after TrialDays of free trial
+type Plan struct {
+	GeneratedID   string
+	Name          string
+	Amount        int64
+	Currency      string
+	Interval      PlanInterval
+	IntervalCount int
+	TrialDays     int
+	CreatedAt     time.Time
+}
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusTrialing SubscriptionStatus = "trialing"
+	SubscriptionStatusActive   SubscriptionStatus = "active"
+	// SubscriptionStatusPastDue means invoice charge failed and is being retried by dunning policy
+	SubscriptionStatusPastDue   SubscriptionStatus = "past_due"
+	SubscriptionStatusCancelled SubscriptionStatus = "cancelled"
+)
+
+// Subscription periods are counted from BillingAnchor by plan interval, so monthly subscription
+// started on 31st is billed on the last day of shorter months and on 31st again afterwards.
+type Subscription struct {
+	GeneratedID        string
+	CustomerID         string
+	PlanID             string
+	PaymentMethod      string
+	Status             SubscriptionStatus
+	BillingAnchor      time.Time
+	CurrentPeriodStart time.Time
+	CurrentPeriodEnd   time.Time
+	TrialEnd           time.Time
+	CancelAtPeriodEnd  bool
+	// PendingProration is added to the next invoice, negative amount is a credit
+	PendingProration int64
+	CancelledAt      time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+type SubscriptionInvoiceStatus string
+
+const (
+	SubscriptionInvoiceStatusOpen SubscriptionInvoiceStatus = "open"
+	SubscriptionInvoiceStatusPaid SubscriptionInvoiceStatus = "paid"
+	// SubscriptionInvoiceStatusUncollectible is final, charge failed until the end of grace period
+	SubscriptionInvoiceStatusUncollectible SubscriptionInvoiceStatus = "uncollectible"
+	// SubscriptionInvoiceStatusVoid is final, subscription was cancelled before invoice was paid
+	SubscriptionInvoiceStatusVoid SubscriptionInvoiceStatus = "void"
+)
+
+type SubscriptionInvoiceItem struct {
+	Description string
+	Amount      int64
+}
+
+// SubscriptionInvoice bills one period of subscription, Amount is a sum of Items
+type SubscriptionInvoice struct {
+	GeneratedID    string
+	SubscriptionID string
+	CustomerID     string
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+	Items          []SubscriptionInvoiceItem
+	Amount         int64
+	Currency       string
+	Status         SubscriptionInvoiceStatus
+	Attempts       int
+	LastError      string
+	DueAt          time.Time
+	NextAttemptAt  time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
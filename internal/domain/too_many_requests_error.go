@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// TooManyRequestsError is returned when customer exceeds rate limit, request could be repeated after RetryAfter
+type TooManyRequestsError struct {
+	errStr     string
+	RetryAfter time.Duration
+}
+
+func NewTooManyRequestsError(text string, retryAfter time.Duration) error {
+	return &TooManyRequestsError{errStr: text, RetryAfter: retryAfter}
+}
+
+func (e *TooManyRequestsError) Error() string {
+	return e.errStr
+}
@@ -0,0 +1,91 @@
+package domain
+
+import "time"
+
+//go:generate mockgen -destination=../postgres/mocks/verification_repository_mock.go -package=mocks . VerificationRepository
+
+type VerificationRepository interface {
+	Create(verification *Verification) error
+	FindByID(verificationID string) (verification *Verification, err error)
+	FindLastByCustomerID(customerID string) (verification *Verification, err error)
+	Update(verification *Verification) error
+}
+
+type VerificationStatus string
+
+const (
+	VerificationStatusSubmitted     VerificationStatus = "submitted"
+	VerificationStatusInReview      VerificationStatus = "in_review"
+	VerificationStatusApproved      VerificationStatus = "approved"
+	VerificationStatusRejected      VerificationStatus = "rejected"
+	VerificationStatusNeedsMoreInfo VerificationStatus = "needs_more_info"
+)
+
+// verificationTransitions lists statuses reachable from each status.
+// Approved and rejected cases are final.
+var verificationTransitions = map[VerificationStatus][]VerificationStatus{
+	VerificationStatusSubmitted: {
+		VerificationStatusInReview,
+		VerificationStatusNeedsMoreInfo,
+	},
+	VerificationStatusInReview: {
+		VerificationStatusApproved,
+		VerificationStatusRejected,
+		VerificationStatusNeedsMoreInfo,
+	},
+	VerificationStatusNeedsMoreInfo: {
+		VerificationStatusSubmitted,
+	},
+}
+
+type VerificationLevel string
+
+const (
+	VerificationLevelNone  VerificationLevel = "none"
+	VerificationLevelBasic VerificationLevel = "basic"
+	VerificationLevelFull  VerificationLevel = "full"
+)
+
+// verificationLevelRanks orders levels from the lowest one
+var verificationLevelRanks = map[VerificationLevel]int{
+	VerificationLevelNone:  0,
+	VerificationLevelBasic: 1,
+	VerificationLevelFull:  2,
+}
+
+// Exceeds tells whether level grants more than other level
+func (l VerificationLevel) Exceeds(other VerificationLevel) bool {
+	return verificationLevelRanks[l] > verificationLevelRanks[other]
+}
+
+type Verification struct {
+	GeneratedID string
+	CustomerID  string
+	Status      VerificationStatus
+	Level       VerificationLevel
+	Checks      []VerificationCheckResult
+	Reviewer    string
+	Comment     string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (v *Verification) CanTransitionTo(status VerificationStatus) bool {
+	for _, allowed := range verificationTransitions[v.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+// VerificationCheck is an automated check of customer data which runs on every submission
+type VerificationCheck interface {
+	Check(customer *Customer) VerificationCheckResult
+}
+
+type VerificationCheckResult struct {
+	Name   string
+	Passed bool
+	Reason string
+}
@@ -0,0 +1,107 @@
+package fee
+
+import (
+	"fmt"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const basisPointsInWhole = 10000
+
+// Quote calculates fee of operation by schedule. Operations without a rule are free of charge.
+func Quote(
+	schedule *domain.FeeSchedule,
+	operation domain.MovementType,
+	amount int64,
+	currency string,
+) domain.FeeQuote {
+	quote := domain.FeeQuote{
+		ScheduleVersion: schedule.Version,
+		Operation:       operation,
+		Currency:        currency,
+		Amount:          amount,
+	}
+	rule := findRule(schedule.Rules, operation, currency)
+	if rule != nil {
+		quote.Fee = calculate(*rule, amount)
+	}
+	return quote
+}
+
+// Validate checks rules of a new schedule
+func Validate(rules []domain.FeeRule) error {
+	seen := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		switch rule.Operation {
+		case domain.MovementTypeDeposit,
+			domain.MovementTypeWithdrawal,
+			domain.MovementTypeTransfer,
+			domain.MovementTypeConversion:
+		default:
+			return fmt.Errorf("unknown operation %s", rule.Operation)
+		}
+
+		// rule for all currencies is shown as operation/*
+		currency := rule.Currency
+		if currency == "" {
+			currency = "*"
+		}
+		key := string(rule.Operation) + "/" + currency
+		if seen[key] {
+			return fmt.Errorf("rule %s is declared twice", key)
+		}
+		seen[key] = true
+
+		if rule.Fixed < 0 || rule.BasisPoints < 0 || rule.Min < 0 || rule.Max < 0 {
+			return fmt.Errorf("%s fee should not be negative", key)
+		}
+		if rule.Max > 0 && rule.Min > rule.Max {
+			return fmt.Errorf("%s min fee should not be greater than max fee", key)
+		}
+		for i, tier := range rule.Tiers {
+			if tier.From < 0 || tier.Fixed < 0 || tier.BasisPoints < 0 {
+				return fmt.Errorf("%s tier fee should not be negative", key)
+			}
+			if i > 0 && tier.From <= rule.Tiers[i-1].From {
+				return fmt.Errorf("%s tiers should be sorted by amount", key)
+			}
+		}
+	}
+	return nil
+}
+
+// findRule prefers rule of the currency over rule for all currencies
+func findRule(rules []domain.FeeRule, operation domain.MovementType, currency string) *domain.FeeRule {
+	var defaultRule *domain.FeeRule
+	for i := range rules {
+		if rules[i].Operation != operation {
+			continue
+		}
+		if rules[i].Currency == currency {
+			return &rules[i]
+		}
+		if rules[i].Currency == "" {
+			defaultRule = &rules[i]
+		}
+	}
+	return defaultRule
+}
+
+func calculate(rule domain.FeeRule, amount int64) int64 {
+	fixed, basisPoints := rule.Fixed, rule.BasisPoints
+	for _, tier := range rule.Tiers {
+		if amount >= tier.From {
+			fixed, basisPoints = tier.Fixed, tier.BasisPoints
+		}
+	}
+
+	// percentage is rounded half up to a minor unit
+	fee := fixed + (amount*basisPoints+basisPointsInWhole/2)/basisPointsInWhole
+	if fee < rule.Min {
+		fee = rule.Min
+	}
+	if rule.Max > 0 && fee > rule.Max {
+		fee = rule.Max
+	}
+	return fee
+}
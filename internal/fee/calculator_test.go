@@ -0,0 +1,112 @@
+package fee
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestQuote(t *testing.T) {
+	t.Parallel()
+
+	schedule := &domain.FeeSchedule{
+		Version: 3,
+		Rules: []domain.FeeRule{
+			{Operation: domain.MovementTypeDeposit},
+			{Operation: domain.MovementTypeWithdrawal, Fixed: 5000},
+			{Operation: domain.MovementTypeTransfer, BasisPoints: 100, Min: 3000, Max: 100000},
+			{Operation: domain.MovementTypeTransfer, Currency: "USD", Fixed: 100, BasisPoints: 50},
+			{
+				Operation: domain.MovementTypeConversion,
+				Tiers: []domain.FeeTier{
+					{From: 0, BasisPoints: 200},
+					{From: 10000000, BasisPoints: 100},
+					{From: 100000000, Fixed: 50000},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		operation   domain.MovementType
+		amount      int64
+		currency    string
+		expectedFee int64
+	}{
+		{"FreeDeposit", domain.MovementTypeDeposit, 100000, "RUB", 0},
+		{"FixedWithdrawal", domain.MovementTypeWithdrawal, 100000, "RUB", 5000},
+		{"PercentageTransfer", domain.MovementTypeTransfer, 1000000, "RUB", 10000},
+		{"PercentageTransferRounded", domain.MovementTypeTransfer, 1000050, "RUB", 10001},
+		{"MinCap", domain.MovementTypeTransfer, 10000, "RUB", 3000},
+		{"MaxCap", domain.MovementTypeTransfer, 100000000, "RUB", 100000},
+		{"CurrencyRule", domain.MovementTypeTransfer, 10000, "USD", 150},
+		{"FirstTier", domain.MovementTypeConversion, 5000000, "RUB", 100000},
+		{"SecondTier", domain.MovementTypeConversion, 10000000, "RUB", 100000},
+		{"LastTier", domain.MovementTypeConversion, 200000000, "RUB", 50000},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			quote := Quote(schedule, test.operation, test.amount, test.currency)
+
+			assert.Equal(t, test.expectedFee, quote.Fee)
+			assert.Equal(t, 3, quote.ScheduleVersion)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		rules         []domain.FeeRule
+		expectedError string
+	}{
+		{
+			"Valid",
+			[]domain.FeeRule{
+				{Operation: domain.MovementTypeTransfer, BasisPoints: 100},
+				{Operation: domain.MovementTypeTransfer, Currency: "USD", Fixed: 100},
+			},
+			"",
+		},
+		{
+			"UnknownOperation",
+			[]domain.FeeRule{{Operation: "refund"}},
+			"unknown operation refund",
+		},
+		{
+			"Duplicate",
+			[]domain.FeeRule{{Operation: domain.MovementTypeTransfer}, {Operation: domain.MovementTypeTransfer}},
+			"rule transfer/* is declared twice",
+		},
+		{
+			"MinGreaterThanMax",
+			[]domain.FeeRule{{Operation: domain.MovementTypeTransfer, Currency: "RUB", Min: 10, Max: 5}},
+			"transfer/RUB min fee should not be greater than max fee",
+		},
+		{
+			"UnsortedTiers",
+			[]domain.FeeRule{{
+				Operation: domain.MovementTypeConversion,
+				Tiers:     []domain.FeeTier{{From: 100}, {From: 0}},
+			}},
+			"conversion/* tiers should be sorted by amount",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(test.rules)
+
+			if test.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
@@ -1,11 +1,18 @@
 package handler
 
-import "github.com/valyala/fasthttp"
+import (
+	"io"
+
+	"github.com/valyala/fasthttp"
+)
 
 type ResponseWriterInterface interface {
 	WriteSuccessPOST(ctx *fasthttp.RequestCtx, responseBody interface{})
 	WriteSuccessGET(ctx *fasthttp.RequestCtx, responseBody interface{})
 	WriteSuccessPUT(ctx *fasthttp.RequestCtx)
 	WriteSuccessDELETE(ctx *fasthttp.RequestCtx)
+	WriteSuccessFile(ctx *fasthttp.RequestCtx, contentType string, fileName string, body []byte)
+	WriteSuccessStream(ctx *fasthttp.RequestCtx, contentType string, fileName string, write func(w io.Writer) error)
 	WriteError(ctx *fasthttp.RequestCtx, message string, code int)
+	WriteErrorWithDetails(ctx *fasthttp.RequestCtx, message string, code int, errorCode string, details interface{})
 }
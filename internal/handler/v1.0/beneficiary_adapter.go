@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/iso20022"
+	"github.com/yaroslavnayug/go-payment-system/internal/phone"
+)
+
+// maxBeneficiaryNicknameLength is a length limit of nickname shown in list of beneficiaries
+const maxBeneficiaryNicknameLength = 64
+
+func beneficiaryFromRequest(customerID string, request *BeneficiaryRequestBody) (*domain.Beneficiary, error) {
+	nickname, err := beneficiaryNickname(request.Nickname)
+	if err != nil {
+		return nil, err
+	}
+	beneficiary := &domain.Beneficiary{
+		CustomerID: customerID,
+		Type:       domain.BeneficiaryType(request.Type),
+		Nickname:   nickname,
+	}
+
+	switch beneficiary.Type {
+	case domain.BeneficiaryTypeCustomer:
+		if request.RecipientID == "" {
+			return nil, domain.NewValidationError("recipient_id is mandatory field of customer beneficiary")
+		}
+		beneficiary.RecipientID = request.RecipientID
+	case domain.BeneficiaryTypePhone:
+		beneficiary.Phone, err = phone.Normalize(request.Phone)
+		if err != nil {
+			return nil, domain.NewValidationError(err.Error())
+		}
+	case domain.BeneficiaryTypeBankAccount:
+		beneficiary.AccountName = strings.TrimSpace(request.AccountName)
+		if beneficiary.AccountName == "" || utf8.RuneCountInString(beneficiary.AccountName) > maxPayoutTextLength {
+			return nil, domain.NewValidationError("account_name is mandatory field of 140 characters at most")
+		}
+		beneficiary.IBAN = strings.ToUpper(strings.Replace(request.IBAN, " ", "", -1))
+		if !iso20022.ValidIBAN(beneficiary.IBAN) {
+			return nil, domain.NewValidationError("iban should be valid IBAN")
+		}
+		beneficiary.BIC = strings.ToUpper(request.BIC)
+		if beneficiary.BIC != "" && !iso20022.ValidBIC(beneficiary.BIC) {
+			return nil, domain.NewValidationError("bic should be 8 or 11 characters long BIC")
+		}
+	default:
+		return nil, domain.NewValidationError("type should be one of customer, phone, bank_account")
+	}
+	return beneficiary, nil
+}
+
+func beneficiaryNickname(nickname string) (string, error) {
+	nickname = strings.TrimSpace(nickname)
+	if nickname == "" || utf8.RuneCountInString(nickname) > maxBeneficiaryNicknameLength {
+		return "", domain.NewValidationError("nickname is mandatory field of 64 characters at most")
+	}
+	return nickname, nil
+}
+
+func responseFromBeneficiary(beneficiary *domain.Beneficiary) *BeneficiaryBody {
+	return &BeneficiaryBody{
+		BeneficiaryID: beneficiary.GeneratedID,
+		CustomerID:    beneficiary.CustomerID,
+		Type:          string(beneficiary.Type),
+		Nickname:      beneficiary.Nickname,
+		RecipientID:   beneficiary.RecipientID,
+		Phone:         beneficiary.Phone,
+		AccountName:   beneficiary.AccountName,
+		IBAN:          beneficiary.IBAN,
+		BIC:           beneficiary.BIC,
+		TrustedAt:     beneficiary.TrustedAt.Format(domain.DateTimeFormat),
+		CreatedAt:     beneficiary.CreatedAt.Format(domain.DateTimeFormat),
+		UpdatedAt:     beneficiary.UpdatedAt.Format(domain.DateTimeFormat),
+	}
+}
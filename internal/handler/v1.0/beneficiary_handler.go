@@ -0,0 +1,243 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const BeneficiaryIdUrlPath = "beneficiary_id"
+
+type BeneficiaryHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.BeneficiaryUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewBeneficiaryHandlerV1(
+	logger *zap.Logger,
+	beneficiaryService *usecase.BeneficiaryUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *BeneficiaryHandlerV1 {
+	return &BeneficiaryHandlerV1{logger: logger, useCase: beneficiaryService, responseWriter: responseWriter}
+}
+
+// swagger:parameters CreateBeneficiary
+type BeneficiaryRequestBody struct {
+	// customer, phone or bank_account, only payee details of the type are set
+	// in:body
+	Type string `json:"type"`
+	// in:body
+	Nickname string `json:"nickname"`
+	// id of customer paid by p2p transfer
+	// in:body
+	RecipientID string `json:"recipient_id"`
+	// phone of customer paid by p2p transfer
+	// in:body
+	Phone string `json:"phone"`
+	// name of external bank account holder
+	// in:body
+	AccountName string `json:"account_name"`
+	// in:body
+	IBAN string `json:"iban"`
+	// BIC of bank of account, optional
+	// in:body
+	BIC string `json:"bic"`
+}
+
+// swagger:parameters RenameBeneficiary
+type BeneficiaryNicknameRequestBody struct {
+	// in:body
+	Nickname string `json:"nickname"`
+}
+
+type BeneficiariesBody struct {
+	Beneficiaries []*BeneficiaryBody `json:"beneficiaries"`
+}
+
+type BeneficiaryBody struct {
+	BeneficiaryID string `json:"beneficiary_id"`
+	CustomerID    string `json:"customer_id"`
+	Type          string `json:"type"`
+	Nickname      string `json:"nickname"`
+	RecipientID   string `json:"recipient_id,omitempty"`
+	Phone         string `json:"phone,omitempty"`
+	AccountName   string `json:"account_name,omitempty"`
+	IBAN          string `json:"iban,omitempty"`
+	BIC           string `json:"bic,omitempty"`
+	// large transfers to beneficiary are refused until cooling-off period is over
+	TrustedAt string `json:"trusted_at"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// swagger:route POST /customer/{id}/beneficiaries beneficiaries CreateBeneficiary
+// Saves payee of customer: another customer, phone or external bank account. Large transfers to new beneficiary
+// are refused within cooling-off period, the same payee details could not be saved twice.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *BeneficiaryHandlerV1) Create(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &BeneficiaryRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	beneficiary, err := beneficiaryFromRequest(customerID.(string), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Create(beneficiary)
+	if err != nil {
+		h.writeBeneficiaryError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromBeneficiary(beneficiary))
+}
+
+// swagger:route GET /customer/{id}/beneficiaries beneficiaries FindCustomerBeneficiaries
+// Lists beneficiaries of customer by nickname.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *BeneficiaryHandlerV1) FindByCustomer(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	beneficiaries, err := h.useCase.FindByCustomer(customerID.(string))
+	if err != nil {
+		h.writeBeneficiaryError(ctx, err)
+		return
+	}
+	response := &BeneficiariesBody{Beneficiaries: make([]*BeneficiaryBody, 0, len(beneficiaries))}
+	for _, beneficiary := range beneficiaries {
+		response.Beneficiaries = append(response.Beneficiaries, responseFromBeneficiary(beneficiary))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route GET /customer/{id}/beneficiaries/{beneficiary_id} beneficiaries FindBeneficiary
+// Shows beneficiary of customer.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *BeneficiaryHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	beneficiaryID := ctx.UserValue(BeneficiaryIdUrlPath)
+	_, customerOk := customerID.(string)
+	_, beneficiaryOk := beneficiaryID.(string)
+	if !customerOk || !beneficiaryOk {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	beneficiary, err := h.useCase.Find(customerID.(string), beneficiaryID.(string))
+	if err != nil {
+		h.writeBeneficiaryError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromBeneficiary(beneficiary))
+}
+
+// swagger:route PUT /customer/{id}/beneficiaries/{beneficiary_id}/nickname beneficiaries RenameBeneficiary
+// Changes nickname of beneficiary, payee details could not be changed.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *BeneficiaryHandlerV1) Rename(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	beneficiaryID := ctx.UserValue(BeneficiaryIdUrlPath)
+	_, customerOk := customerID.(string)
+	_, beneficiaryOk := beneficiaryID.(string)
+	if !customerOk || !beneficiaryOk {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &BeneficiaryNicknameRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	nickname, err := beneficiaryNickname(request.Nickname)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	_, err = h.useCase.Rename(customerID.(string), beneficiaryID.(string), nickname)
+	if err != nil {
+		h.writeBeneficiaryError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPUT(ctx)
+}
+
+// swagger:route DELETE /customer/{id}/beneficiaries/{beneficiary_id} beneficiaries DeleteBeneficiary
+// Deletes beneficiary, transfers already paid to it keep its id.
+// responses:
+//  204:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *BeneficiaryHandlerV1) Delete(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	beneficiaryID := ctx.UserValue(BeneficiaryIdUrlPath)
+	_, customerOk := customerID.(string)
+	_, beneficiaryOk := beneficiaryID.(string)
+	if !customerOk || !beneficiaryOk {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err := h.useCase.Delete(customerID.(string), beneficiaryID.(string))
+	if err != nil {
+		h.writeBeneficiaryError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessDELETE(ctx)
+}
+
+func (h *BeneficiaryHandlerV1) writeBeneficiaryError(ctx *fasthttp.RequestCtx, err error) {
+	switch err.(type) {
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process beneficiary. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"net"
+	"testing"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/beneficiary"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestCreateBeneficiary_Success(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("customer").Return(&domain.Customer{
+		GeneratedID: "customer",
+		Phone:       "+79931234567",
+		Status:      domain.CustomerStatusActive,
+	}, nil)
+	beneficiaryRepositoryMock := mocks.NewMockBeneficiaryRepository(ctrl)
+	beneficiaryRepositoryMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(payee *domain.Beneficiary) (bool, error) {
+		assert.Equal(t, "DE89370400440532013000", payee.IBAN)
+		assert.Equal(t, "bank_account:DE89370400440532013000", payee.PayeeKey)
+		assert.Equal(t, payee.CreatedAt.Add(beneficiary.DefaultCoolingOffPolicy.Period), payee.TrustedAt)
+		return true, nil
+	})
+
+	useCase := usecase.NewBeneficiaryUseCase(
+		beneficiaryRepositoryMock,
+		customerRepositoryMock,
+		beneficiary.DefaultCoolingOffPolicy,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewBeneficiaryHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/beneficiaries", handlerV1.Create)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/customer/beneficiaries")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{
+		"type": "bank_account",
+		"nickname": " Rent ",
+		"account_name": "Bruce Wayne",
+		"iban": "de89 3704 0044 0532 0130 00"
+	}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	assert.Contains(t, string(response.Body()), `"nickname":"Rent"`)
+	assert.Contains(t, string(response.Body()), `"iban":"DE89370400440532013000"`)
+}
+
+func TestCreateBeneficiary_Duplicate(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("customer").Return(&domain.Customer{
+		GeneratedID: "customer",
+		Phone:       "+79931234567",
+		Status:      domain.CustomerStatusActive,
+	}, nil)
+	beneficiaryRepositoryMock := mocks.NewMockBeneficiaryRepository(ctrl)
+	beneficiaryRepositoryMock.EXPECT().Create(gomock.Any()).Return(false, nil)
+	beneficiaryRepositoryMock.EXPECT().FindByPayeeKey("customer", "phone:+79931234568").Return(&domain.Beneficiary{
+		GeneratedID: "existing",
+		CustomerID:  "customer",
+		Type:        domain.BeneficiaryTypePhone,
+		Phone:       "+79931234568",
+		PayeeKey:    "phone:+79931234568",
+	}, nil)
+
+	useCase := usecase.NewBeneficiaryUseCase(
+		beneficiaryRepositoryMock,
+		customerRepositoryMock,
+		beneficiary.DefaultCoolingOffPolicy,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewBeneficiaryHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/beneficiaries", handlerV1.Create)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/customer/beneficiaries")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"type": "phone", "nickname": "Bruce", "phone": "8 993 123-45-68"}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.JSONEq(
+		t,
+		`{"error": {"status": 409, "message": "beneficiary with the same details is already saved as existing"}}`,
+		string(response.Body()),
+	)
+}
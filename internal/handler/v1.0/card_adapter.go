@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/issuing"
+)
+
+func cardFromRequest(customerID string, request *CardRequestBody) (*domain.Card, error) {
+	if !currencyRegexp.MatchString(request.Currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	return &domain.Card{
+		CustomerID: customerID,
+		Currency:   request.Currency,
+		Controls:   cardControlsFromRequest(&request.Controls),
+	}, nil
+}
+
+func cardControlsFromRequest(request *CardControlsBody) domain.CardControls {
+	return domain.CardControls{
+		BlockedMCCs:    request.BlockedMCCs,
+		PerTransaction: request.PerTransactionLimit,
+		Daily:          request.DailyLimit,
+	}
+}
+
+func cardAuthorizationFromRequest(request *CardAuthorizationRequestBody) (*domain.CardAuthorizationRequest, error) {
+	if !issuing.LuhnValid(request.PAN) {
+		return nil, domain.NewValidationError("pan should be digits with valid check digit")
+	}
+	if request.ExpiryMonth < 1 || request.ExpiryMonth > 12 || request.ExpiryYear <= 0 {
+		return nil, domain.NewValidationError("expiry_month and expiry_year are mandatory fields")
+	}
+	if request.Amount <= 0 {
+		return nil, domain.NewValidationError("amount should be positive")
+	}
+	if !currencyRegexp.MatchString(request.Currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	err := issuing.ValidateMCC(request.MCC)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+	return &domain.CardAuthorizationRequest{
+		PAN:          request.PAN,
+		ExpiryMonth:  request.ExpiryMonth,
+		ExpiryYear:   request.ExpiryYear,
+		CVV:          request.CVV,
+		Amount:       request.Amount,
+		Currency:     request.Currency,
+		MCC:          request.MCC,
+		MerchantName: request.MerchantName,
+	}, nil
+}
+
+func responseFromCard(card *domain.Card) *CardBody {
+	blockedMCCs := card.Controls.BlockedMCCs
+	if blockedMCCs == nil {
+		blockedMCCs = []string{}
+	}
+	return &CardBody{
+		CardID:      card.GeneratedID,
+		CustomerID:  card.CustomerID,
+		Currency:    card.Currency,
+		MaskedPAN:   card.MaskedPAN,
+		ExpiryMonth: card.ExpiryMonth,
+		ExpiryYear:  card.ExpiryYear,
+		Status:      string(card.Status),
+		Controls: CardControlsBody{
+			BlockedMCCs:         blockedMCCs,
+			PerTransactionLimit: card.Controls.PerTransaction,
+			DailyLimit:          card.Controls.Daily,
+		},
+		CreatedAt: card.CreatedAt.Format(domain.DateTimeFormat),
+		UpdatedAt: card.UpdatedAt.Format(domain.DateTimeFormat),
+	}
+}
+
+func responseFromCardSecrets(card *domain.Card, secrets *domain.CardSecrets) *CardSecretsBody {
+	return &CardSecretsBody{
+		CardID:      card.GeneratedID,
+		PAN:         secrets.PAN,
+		CVV:         secrets.CVV,
+		ExpiryMonth: card.ExpiryMonth,
+		ExpiryYear:  card.ExpiryYear,
+	}
+}
+
+func responseFromCardAuthorization(authorization *domain.CardAuthorization) *CardAuthorizationBody {
+	return &CardAuthorizationBody{
+		AuthorizationID:  authorization.GeneratedID,
+		CardID:           authorization.CardID,
+		Amount:           authorization.Amount,
+		Currency:         authorization.Currency,
+		MCC:              authorization.MCC,
+		MerchantName:     authorization.MerchantName,
+		Status:           string(authorization.Status),
+		DeclineReason:    string(authorization.DeclineReason),
+		ResponseCode:     issuing.ResponseCode(authorization),
+		RiskAssessmentID: authorization.RiskAssessmentID,
+		RiskDecision:     string(authorization.RiskDecision),
+		CreatedAt:        authorization.CreatedAt.Format(domain.DateTimeFormat),
+		UpdatedAt:        authorization.UpdatedAt.Format(domain.DateTimeFormat),
+	}
+}
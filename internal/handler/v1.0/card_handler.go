@@ -0,0 +1,418 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const (
+	CardIdUrlPath              = "id"
+	CardAuthorizationIdUrlPath = "id"
+)
+
+type CardHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.CardUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewCardHandlerV1(
+	logger *zap.Logger,
+	cardService *usecase.CardUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *CardHandlerV1 {
+	return &CardHandlerV1{logger: logger, useCase: cardService, responseWriter: responseWriter}
+}
+
+// swagger:parameters IssueCard
+type CardRequestBody struct {
+	// card spends customer balance in this currency
+	// in:body
+	Currency string `json:"currency"`
+	// in:body
+	Controls CardControlsBody `json:"controls"`
+}
+
+// swagger:parameters SetCardControls
+type CardControlsBody struct {
+	// merchant category codes card is declined at
+	// in:body
+	BlockedMCCs []string `json:"blocked_mccs"`
+	// in minor currency units, zero is not limited
+	// in:body
+	PerTransactionLimit int64 `json:"per_transaction_limit"`
+	// amount of authorizations approved during a day in minor currency units, zero is not limited
+	// in:body
+	DailyLimit int64 `json:"daily_limit"`
+}
+
+// swagger:parameters SetCardStatus
+type CardStatusRequestBody struct {
+	// active, frozen or terminated, terminated card could not be reactivated
+	// in:body
+	Status string `json:"status"`
+}
+
+// swagger:parameters AuthorizeCard
+type CardAuthorizationRequestBody struct {
+	// in:body
+	PAN string `json:"pan"`
+	// in:body
+	ExpiryMonth int `json:"expiry_month"`
+	// in:body
+	ExpiryYear int `json:"expiry_year"`
+	// optional, checked when sent
+	// in:body
+	CVV string `json:"cvv"`
+	// amount in minor currency units
+	// in:body
+	Amount int64 `json:"amount"`
+	// in:body
+	Currency string `json:"currency"`
+	// merchant category code
+	// in:body
+	MCC string `json:"mcc"`
+	// in:body
+	MerchantName string `json:"merchant_name"`
+}
+
+type CardsBody struct {
+	Cards []*CardBody `json:"cards"`
+}
+
+type CardBody struct {
+	CardID      string           `json:"card_id"`
+	CustomerID  string           `json:"customer_id"`
+	Currency    string           `json:"currency"`
+	MaskedPAN   string           `json:"masked_pan"`
+	ExpiryMonth int              `json:"expiry_month"`
+	ExpiryYear  int              `json:"expiry_year"`
+	Status      string           `json:"status"`
+	Controls    CardControlsBody `json:"controls"`
+	CreatedAt   string           `json:"created_at"`
+	UpdatedAt   string           `json:"updated_at"`
+}
+
+type CardSecretsBody struct {
+	CardID      string `json:"card_id"`
+	PAN         string `json:"pan"`
+	CVV         string `json:"cvv"`
+	ExpiryMonth int    `json:"expiry_month"`
+	ExpiryYear  int    `json:"expiry_year"`
+}
+
+type CardAuthorizationsBody struct {
+	Authorizations []*CardAuthorizationBody `json:"authorizations"`
+}
+
+type CardAuthorizationBody struct {
+	AuthorizationID string `json:"authorization_id"`
+	CardID          string `json:"card_id"`
+	Amount          int64  `json:"amount"`
+	Currency        string `json:"currency"`
+	MCC             string `json:"mcc"`
+	MerchantName    string `json:"merchant_name"`
+	Status          string `json:"status"`
+	DeclineReason   string `json:"decline_reason,omitempty"`
+	// ISO 8583 response code, 00 for approved authorization
+	ResponseCode string `json:"response_code"`
+	// risk assessment made before decision, approved authorization under review keeps review decision
+	RiskAssessmentID string `json:"risk_assessment_id"`
+	RiskDecision     string `json:"risk_decision"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+}
+
+// swagger:route POST /customer/{id}/cards cards IssueCard
+// Issues virtual card of customer with PAN of configured BIN, CVV and expiry in 3 years.
+// PAN and CVV are kept in vault and are shown by card secrets only.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *CardHandlerV1) Issue(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &CardRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	card, err := cardFromRequest(customerID.(string), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Issue(card)
+	if err != nil {
+		h.writeCardError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromCard(card))
+}
+
+// swagger:route GET /customer/{id}/cards cards FindCustomerCards
+// Lists cards of customer, the latest first.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *CardHandlerV1) FindByCustomer(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	cards, err := h.useCase.FindByCustomer(customerID.(string))
+	if err != nil {
+		h.writeCardError(ctx, err)
+		return
+	}
+	response := &CardsBody{Cards: make([]*CardBody, 0, len(cards))}
+	for _, card := range cards {
+		response.Cards = append(response.Cards, responseFromCard(card))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route GET /cards/{id} cards FindCard
+// Shows card with masked PAN.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *CardHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	cardID := ctx.UserValue(CardIdUrlPath)
+	if _, ok := cardID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	card, err := h.useCase.Find(cardID.(string))
+	if err != nil {
+		h.writeCardError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromCard(card))
+}
+
+// swagger:route GET /cards/{id}/secrets cards FindCardSecrets
+// Shows PAN and CVV of card from vault, secrets of terminated card are deleted.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *CardHandlerV1) FindSecrets(ctx *fasthttp.RequestCtx) {
+	cardID := ctx.UserValue(CardIdUrlPath)
+	if _, ok := cardID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	card, secrets, err := h.useCase.FindSecrets(cardID.(string))
+	if err != nil {
+		h.writeCardError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromCardSecrets(card, secrets))
+}
+
+// swagger:route PUT /cards/{id}/status cards SetCardStatus
+// Freezes active card or activates frozen one. Termination is final and deletes card secrets.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *CardHandlerV1) SetStatus(ctx *fasthttp.RequestCtx) {
+	cardID := ctx.UserValue(CardIdUrlPath)
+	if _, ok := cardID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &CardStatusRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	if request.Status == "" {
+		h.responseWriter.WriteError(ctx, "status is mandatory field", fasthttp.StatusBadRequest)
+		return
+	}
+
+	_, err = h.useCase.SetStatus(cardID.(string), domain.CardStatus(request.Status))
+	if err != nil {
+		h.writeCardError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPUT(ctx)
+}
+
+// swagger:route PUT /cards/{id}/controls cards SetCardControls
+// Replaces spending controls of card: blocked merchant categories, per transaction and daily limits.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *CardHandlerV1) SetControls(ctx *fasthttp.RequestCtx) {
+	cardID := ctx.UserValue(CardIdUrlPath)
+	if _, ok := cardID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &CardControlsBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	_, err = h.useCase.SetControls(cardID.(string), cardControlsFromRequest(request))
+	if err != nil {
+		h.writeCardError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPUT(ctx)
+}
+
+// swagger:route GET /cards/{id}/authorizations cards FindCardAuthorizations
+// Lists authorizations of card, the latest first.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *CardHandlerV1) FindAuthorizations(ctx *fasthttp.RequestCtx) {
+	cardID := ctx.UserValue(CardIdUrlPath)
+	if _, ok := cardID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	authorizations, err := h.useCase.FindAuthorizations(cardID.(string))
+	if err != nil {
+		h.writeCardError(ctx, err)
+		return
+	}
+	response := &CardAuthorizationsBody{Authorizations: make([]*CardAuthorizationBody, 0, len(authorizations))}
+	for _, authorization := range authorizations {
+		response.Authorizations = append(response.Authorizations, responseFromCardAuthorization(authorization))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route POST /card-network/authorizations card-network AuthorizeCard
+// Simulates authorization request of card network. Declined authorization is created as well,
+// response code tells network decision. Approved authorization holds amount on customer balance.
+// Authorization is scored by fraud risk assessment, declined assessment declines authorization.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *CardHandlerV1) Authorize(ctx *fasthttp.RequestCtx) {
+	request := &CardAuthorizationRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	authorizationRequest, err := cardAuthorizationFromRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	authorization, err := h.useCase.Authorize(authorizationRequest)
+	if err != nil {
+		h.writeCardError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromCardAuthorization(authorization))
+}
+
+// swagger:route POST /card-network/authorizations/{id}/capture card-network CaptureCardAuthorization
+// Simulates clearing of approved authorization, customer balance is debited and hold is released.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *CardHandlerV1) Capture(ctx *fasthttp.RequestCtx) {
+	h.authorizationAction(ctx, h.useCase.Capture)
+}
+
+// swagger:route POST /card-network/authorizations/{id}/reversal card-network ReverseCardAuthorization
+// Simulates reversal of authorization: hold of approved authorization is released, captured one is refunded.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *CardHandlerV1) Reverse(ctx *fasthttp.RequestCtx) {
+	h.authorizationAction(ctx, h.useCase.Reverse)
+}
+
+func (h *CardHandlerV1) authorizationAction(
+	ctx *fasthttp.RequestCtx,
+	action func(authorizationID string) (*domain.CardAuthorization, error),
+) {
+	authorizationID := ctx.UserValue(CardAuthorizationIdUrlPath)
+	if _, ok := authorizationID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	authorization, err := action(authorizationID.(string))
+	if err != nil {
+		h.writeCardError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromCardAuthorization(authorization))
+}
+
+func (h *CardHandlerV1) writeCardError(ctx *fasthttp.RequestCtx, err error) {
+	switch err.(type) {
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process card. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,439 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/blob"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/issuing"
+	"github.com/yaroslavnayug/go-payment-system/internal/risk"
+	"github.com/yaroslavnayug/go-payment-system/internal/vault"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestIssueCard_Success(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "card")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	store, err := blob.NewFileSystemStore(dir)
+	assert.NoError(t, err)
+	cardVault, err := vault.NewVault(store, bytes.Repeat([]byte{1}, vault.KeyLength))
+	assert.NoError(t, err)
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("customer").Return(&domain.Customer{
+		GeneratedID: "customer",
+		Status:      domain.CustomerStatusActive,
+	}, nil)
+	var created *domain.Card
+	repositoryMock := mocks.NewMockCardRepository(ctrl)
+	repositoryMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(card *domain.Card) (bool, error) {
+		created = card
+		return true, nil
+	})
+
+	useCase := usecase.NewCardUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		cardVault,
+		newVerificationUseCase(ctrl, approvedVerification),
+		newLimitUseCase(ctrl, highLimits),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+		"220012",
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewCardHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/cards", handlerV1.Issue)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/customer/cards")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"currency": "RUB", "controls": {"blocked_mccs": ["7995"], "daily_limit": 100000}}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	body := &CardBody{}
+	err = json.Unmarshal(response.Body(), body)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, created.GeneratedID, body.CardID)
+	assert.Equal(t, "active", body.Status)
+	assert.Regexp(t, `^220012\*{6}\d{4}$`, body.MaskedPAN)
+	assert.Equal(t, []string{"7995"}, body.Controls.BlockedMCCs)
+	assert.Equal(t, int64(100000), body.Controls.DailyLimit)
+
+	secrets, err := cardVault.Get(created.GeneratedID)
+	assert.NoError(t, err)
+	assert.True(t, issuing.LuhnValid(secrets.PAN))
+	assert.Equal(t, created.MaskedPAN, issuing.Mask(secrets.PAN))
+	assert.Equal(t, cardVault.Fingerprint(secrets.PAN), created.PANFingerprint)
+	assert.Len(t, secrets.CVV, issuing.CVVLength)
+}
+
+func TestAuthorizeCard_InsufficientFunds(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cardVault, err := vault.NewVault(nil, bytes.Repeat([]byte{1}, vault.KeyLength))
+	assert.NoError(t, err)
+	card := &domain.Card{
+		GeneratedID:    "card",
+		CustomerID:     "customer",
+		Currency:       "RUB",
+		PANFingerprint: cardVault.Fingerprint("2200120000001230"),
+		ExpiryMonth:    int(time.Now().Month()),
+		ExpiryYear:     time.Now().Year() + 1,
+		Status:         domain.CardStatusActive,
+	}
+	repositoryMock := mocks.NewMockCardRepository(ctrl)
+	repositoryMock.EXPECT().FindByFingerprint(card.PANFingerprint).Return(card, nil)
+	repositoryMock.EXPECT().Authorize("card", gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(
+			cardID string,
+			now time.Time,
+			dayStart time.Time,
+			authorize func(
+				*domain.Card,
+				*domain.AvailableBalance,
+				int64,
+				domain.LimitUsage,
+			) (*domain.CardAuthorization, error),
+		) (*domain.CardAuthorization, error) {
+			return authorize(card, &domain.AvailableBalance{Balance: 10000, Holds: 2000}, 0, domain.LimitUsage{})
+		},
+	)
+
+	useCase := usecase.NewCardUseCase(
+		repositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		cardVault,
+		newVerificationUseCase(ctrl, approvedVerification),
+		newLimitUseCase(ctrl, highLimits),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+		"220012",
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewCardHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/card-network/authorizations", handlerV1.Authorize)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/card-network/authorizations")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	requestBody, _ := json.Marshal(&CardAuthorizationRequestBody{
+		PAN:          "2200120000001230",
+		ExpiryMonth:  card.ExpiryMonth,
+		ExpiryYear:   card.ExpiryYear,
+		Amount:       8001,
+		Currency:     "RUB",
+		MCC:          "5411",
+		MerchantName: "Grocery",
+	})
+	request.SetBody(requestBody)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	body := &CardAuthorizationBody{}
+	err = json.Unmarshal(response.Body(), body)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, "card", body.CardID)
+	assert.Equal(t, "declined", body.Status)
+	assert.Equal(t, "insufficient_funds", body.DeclineReason)
+	assert.Equal(t, "51", body.ResponseCode)
+}
+
+func TestAuthorizeCard_CustomerLimit(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cardVault, err := vault.NewVault(nil, bytes.Repeat([]byte{1}, vault.KeyLength))
+	assert.NoError(t, err)
+	card := &domain.Card{
+		GeneratedID:    "card",
+		CustomerID:     "customer",
+		Currency:       "RUB",
+		PANFingerprint: cardVault.Fingerprint("2200120000001230"),
+		ExpiryMonth:    int(time.Now().Month()),
+		ExpiryYear:     time.Now().Year() + 1,
+		Status:         domain.CardStatusActive,
+	}
+	repositoryMock := mocks.NewMockCardRepository(ctrl)
+	repositoryMock.EXPECT().FindByFingerprint(card.PANFingerprint).Return(card, nil)
+	repositoryMock.EXPECT().Authorize("card", gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(
+			cardID string,
+			now time.Time,
+			dayStart time.Time,
+			authorize func(
+				*domain.Card,
+				*domain.AvailableBalance,
+				int64,
+				domain.LimitUsage,
+			) (*domain.CardAuthorization, error),
+		) (*domain.CardAuthorization, error) {
+			return authorize(card, &domain.AvailableBalance{Balance: 10000, Holds: 2000}, 0, domain.LimitUsage{
+				DailyAmount:   95000,
+				MonthlyAmount: 95000,
+				DailyCount:    3,
+				MonthlyCount:  3,
+			})
+		},
+	)
+
+	useCase := usecase.NewCardUseCase(
+		repositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		cardVault,
+		newVerificationUseCase(ctrl, approvedVerification),
+		newLimitUseCase(ctrl, domain.TransactionLimits{
+			PerTransaction: 100000, Daily: 100000, Monthly: 1000000, DailyCount: 10, MonthlyCount: 100,
+		}),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+		"220012",
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewCardHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/card-network/authorizations", handlerV1.Authorize)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/card-network/authorizations")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	requestBody, _ := json.Marshal(&CardAuthorizationRequestBody{
+		PAN:          "2200120000001230",
+		ExpiryMonth:  card.ExpiryMonth,
+		ExpiryYear:   card.ExpiryYear,
+		Amount:       6000,
+		Currency:     "RUB",
+		MCC:          "5411",
+		MerchantName: "Grocery",
+	})
+	request.SetBody(requestBody)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	body := &CardAuthorizationBody{}
+	err = json.Unmarshal(response.Body(), body)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, "card", body.CardID)
+	assert.Equal(t, "declined", body.Status)
+	assert.Equal(t, "customer_limit", body.DeclineReason)
+	assert.Equal(t, "61", body.ResponseCode)
+}
+
+func TestAuthorizeCard_RiskDeclined(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	cardVault, err := vault.NewVault(nil, bytes.Repeat([]byte{1}, vault.KeyLength))
+	assert.NoError(t, err)
+	card := &domain.Card{
+		GeneratedID:    "card",
+		CustomerID:     "customer",
+		Currency:       "RUB",
+		PANFingerprint: cardVault.Fingerprint("2200120000001230"),
+		ExpiryMonth:    int(time.Now().Month()),
+		ExpiryYear:     time.Now().Year() + 1,
+		Status:         domain.CardStatusActive,
+	}
+	repositoryMock := mocks.NewMockCardRepository(ctrl)
+	repositoryMock.EXPECT().FindByFingerprint(card.PANFingerprint).Return(card, nil)
+	repositoryMock.EXPECT().Authorize("card", gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(
+			cardID string,
+			now time.Time,
+			dayStart time.Time,
+			authorize func(
+				*domain.Card,
+				*domain.AvailableBalance,
+				int64,
+				domain.LimitUsage,
+			) (*domain.CardAuthorization, error),
+		) (*domain.CardAuthorization, error) {
+			return authorize(card, &domain.AvailableBalance{Balance: 10000, Holds: 2000}, 0, domain.LimitUsage{})
+		},
+	)
+
+	useCase := usecase.NewCardUseCase(
+		repositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		cardVault,
+		newVerificationUseCase(ctrl, approvedVerification),
+		newLimitUseCase(ctrl, domain.TransactionLimits{
+			PerTransaction: 100000, Daily: 100000, Monthly: 1000000, DailyCount: 10, MonthlyCount: 100,
+		}),
+		newRiskUseCase(ctrl, risk.Thresholds{Review: 0, Decline: 0}),
+		"220012",
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewCardHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/card-network/authorizations", handlerV1.Authorize)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/card-network/authorizations")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	requestBody, _ := json.Marshal(&CardAuthorizationRequestBody{
+		PAN:          "2200120000001230",
+		ExpiryMonth:  card.ExpiryMonth,
+		ExpiryYear:   card.ExpiryYear,
+		Amount:       6000,
+		Currency:     "RUB",
+		MCC:          "5411",
+		MerchantName: "Grocery",
+	})
+	request.SetBody(requestBody)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	body := &CardAuthorizationBody{}
+	err = json.Unmarshal(response.Body(), body)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, "card", body.CardID)
+	assert.Equal(t, "declined", body.Status)
+	assert.Equal(t, "risk_declined", body.DeclineReason)
+	assert.Equal(t, "59", body.ResponseCode)
+	assert.NotEmpty(t, body.RiskAssessmentID)
+	assert.Equal(t, "decline", body.RiskDecision)
+}
@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func creditLineFromRequest(
+	customerID string,
+	currency string,
+	request *CreditLineRequestBody,
+) (*domain.CreditLine, error) {
+	if !currencyRegexp.MatchString(currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	return &domain.CreditLine{
+		CustomerID: customerID,
+		Currency:   currency,
+		Limit:      request.Limit,
+		AnnualRate: request.AnnualRate,
+	}, nil
+}
+
+func responseFromCreditLine(utilisation *domain.CreditLineUtilisation) *CreditLineBody {
+	line := utilisation.Line
+	return &CreditLineBody{
+		CreditLineID:    line.GeneratedID,
+		CustomerID:      line.CustomerID,
+		Currency:        line.Currency,
+		Limit:           line.Limit,
+		AnnualRate:      line.AnnualRate,
+		Balance:         utilisation.Balance.Balance,
+		Holds:           utilisation.Balance.Holds,
+		Available:       utilisation.Balance.Available(),
+		Used:            utilisation.Used,
+		Utilisation:     utilisation.Percent,
+		AccruedInterest: line.AccruedInterest,
+		ChargedInterest: line.ChargedInterest,
+		AccruedUntil:    line.AccruedUntil.In(businessday.Location).Format(domain.DateFormat),
+		UpdatedAt:       line.UpdatedAt.Format(domain.DateTimeFormat),
+	}
+}
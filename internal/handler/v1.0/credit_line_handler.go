@@ -0,0 +1,170 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const CreditLineCurrencyUrlPath = "currency"
+
+type CreditLineHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.CreditLineUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewCreditLineHandlerV1(
+	logger *zap.Logger,
+	creditLineService *usecase.CreditLineUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *CreditLineHandlerV1 {
+	return &CreditLineHandlerV1{logger: logger, useCase: creditLineService, responseWriter: responseWriter}
+}
+
+// swagger:parameters ApproveCreditLine
+type CreditLineRequestBody struct {
+	// in minor currency units, customer could go negative down to minus limit
+	// in:body
+	Limit int64 `json:"limit"`
+	// decimal annual percent like 24.9 accrued daily on negative balance
+	// in:body
+	AnnualRate string `json:"annual_rate"`
+}
+
+type CreditLinesBody struct {
+	CreditLines []*CreditLineBody `json:"credit_lines"`
+}
+
+type CreditLineBody struct {
+	CreditLineID    string `json:"credit_line_id"`
+	CustomerID      string `json:"customer_id"`
+	Currency        string `json:"currency"`
+	Limit           int64  `json:"limit"`
+	AnnualRate      string `json:"annual_rate"`
+	Balance         int64  `json:"balance"`
+	Holds           int64  `json:"holds"`
+	Available       int64  `json:"available"`
+	Used            int64  `json:"used"`
+	Utilisation     string `json:"utilisation"`
+	AccruedInterest int64  `json:"accrued_interest"`
+	ChargedInterest int64  `json:"charged_interest"`
+	AccruedUntil    string `json:"accrued_until"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+// swagger:route PUT /customer/{id}/credit-lines/{currency} credit-lines ApproveCreditLine
+// Approves credit limit and interest rate of customer in currency. Limit could be lowered below the one in use,
+// customer could not debit anything until balance is back within new limit.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *CreditLineHandlerV1) Approve(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	currency := ctx.UserValue(CreditLineCurrencyUrlPath)
+	if _, ok := currency.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &CreditLineRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	line, err := creditLineFromRequest(customerID.(string), currency.(string), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Approve(line)
+	if err != nil {
+		h.writeCreditLineError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPUT(ctx)
+}
+
+// swagger:route GET /customer/{id}/credit-lines/{currency} credit-lines FindCreditLine
+// Reports utilisation of credit line of customer in currency: balance, holds, available amount and used part of limit.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *CreditLineHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	currency := ctx.UserValue(CreditLineCurrencyUrlPath)
+	if _, ok := currency.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	utilisation, err := h.useCase.Find(customerID.(string), currency.(string))
+	if err != nil {
+		h.writeCreditLineError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromCreditLine(utilisation))
+}
+
+// swagger:route GET /customer/{id}/credit-lines credit-lines FindCustomerCreditLines
+// Reports utilisation of all credit lines of customer.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *CreditLineHandlerV1) FindByCustomer(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	utilisations, err := h.useCase.FindByCustomer(customerID.(string))
+	if err != nil {
+		h.writeCreditLineError(ctx, err)
+		return
+	}
+	response := &CreditLinesBody{CreditLines: make([]*CreditLineBody, 0, len(utilisations))}
+	for _, utilisation := range utilisations {
+		response.CreditLines = append(response.CreditLines, responseFromCreditLine(utilisation))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+func (h *CreditLineHandlerV1) writeCreditLineError(ctx *fasthttp.RequestCtx, err error) {
+	switch err.(type) {
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process credit line. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,159 @@
+package v1
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestApproveCreditLine_Success(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("customer").Return(&domain.Customer{
+		GeneratedID: "customer",
+		Status:      domain.CustomerStatusActive,
+	}, nil)
+	var saved *domain.CreditLine
+	repositoryMock := mocks.NewMockCreditLineRepository(ctrl)
+	repositoryMock.EXPECT().Save(gomock.Any()).DoAndReturn(func(line *domain.CreditLine) error {
+		saved = line
+		return nil
+	})
+
+	useCase := usecase.NewCreditLineUseCase(repositoryMock, customerRepositoryMock)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewCreditLineHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.PUT("/customer/:id/credit-lines/:currency", handlerV1.Approve)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/customer/credit-lines/RUB")
+	request.Header.SetMethod(fasthttp.MethodPut)
+	request.SetBodyString(`{"limit": 100000, "annual_rate": "24.9"}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusOK, response.Header.StatusCode())
+	assert.Equal(t, "customer", saved.CustomerID)
+	assert.Equal(t, "RUB", saved.Currency)
+	assert.Equal(t, int64(100000), saved.Limit)
+	assert.Equal(t, "24.9", saved.AnnualRate)
+	assert.NotEmpty(t, saved.GeneratedID)
+}
+
+func TestFindCreditLine_Utilisation(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repositoryMock := mocks.NewMockCreditLineRepository(ctrl)
+	repositoryMock.EXPECT().Find("customer", "RUB").Return(&domain.CreditLine{
+		GeneratedID:     "line",
+		CustomerID:      "customer",
+		Currency:        "RUB",
+		Limit:           100000,
+		AnnualRate:      "24.9",
+		AccruedInterest: 120,
+		AccruedUntil:    time.Now(),
+		UpdatedAt:       time.Now(),
+	}, nil)
+	repositoryMock.EXPECT().FindAvailableBalance("customer", "RUB").Return(&domain.AvailableBalance{
+		Balance: -30000,
+		Limit:   100000,
+		Holds:   5000,
+	}, nil)
+
+	useCase := usecase.NewCreditLineUseCase(repositoryMock, mocks.NewMockCustomerRepository(ctrl))
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewCreditLineHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.GET("/customer/:id/credit-lines/:currency", handlerV1.Find)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/customer/credit-lines/RUB")
+	request.Header.SetMethod(fasthttp.MethodGet)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusOK, response.Header.StatusCode())
+	body := &CreditLineBody{}
+	err := json.Unmarshal(response.Body(), body)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, int64(-30000), body.Balance)
+	assert.Equal(t, int64(5000), body.Holds)
+	assert.Equal(t, int64(65000), body.Available)
+	assert.Equal(t, int64(35000), body.Used)
+	assert.Equal(t, "35.00", body.Utilisation)
+	assert.Equal(t, int64(120), body.AccruedInterest)
+}
@@ -1,12 +1,44 @@
 package v1
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
 	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/legalentity"
+	"github.com/yaroslavnayug/go-payment-system/internal/phone"
 )
 
+// customerFromBody reads individual or legal entity depending on type of request
+func customerFromBody(body []byte) (*domain.Customer, error) {
+	customerType := &CustomerTypeBody{}
+	err := json.Unmarshal(body, customerType)
+	if err != nil {
+		return nil, domain.NewValidationError(http.StatusText(http.StatusBadRequest))
+	}
+	switch domain.CustomerType(customerType.Type) {
+	case "", domain.CustomerTypeIndividual:
+		request := &CustomerBody{}
+		err = json.Unmarshal(body, request)
+		if err != nil {
+			return nil, domain.NewValidationError(http.StatusText(http.StatusBadRequest))
+		}
+		return customerFromRequest(request)
+	case domain.CustomerTypeLegalEntity:
+		request := &LegalEntityBody{}
+		err = json.Unmarshal(body, request)
+		if err != nil {
+			return nil, domain.NewValidationError(http.StatusText(http.StatusBadRequest))
+		}
+		return legalEntityFromRequest(request)
+	default:
+		return nil, domain.NewValidationError("type should be individual or legal_entity")
+	}
+}
+
 func customerFromRequest(request *CustomerBody) (*domain.Customer, error) {
 	if request.FirstName == "" {
 		return nil, domain.NewValidationError("first_name is mandatory field")
@@ -17,20 +49,13 @@ func customerFromRequest(request *CustomerBody) (*domain.Customer, error) {
 	if request.Phone == "" {
 		return nil, domain.NewValidationError("phone is mandatory field")
 	}
-	if request.Address.Country == "" {
-		return nil, domain.NewValidationError("address.country is mandatory field")
-	}
-	if request.Address.Region == "" {
-		return nil, domain.NewValidationError("address.region is mandatory field")
-	}
-	if request.Address.City == "" {
-		return nil, domain.NewValidationError("address.city is mandatory field")
-	}
-	if request.Address.Street == "" {
-		return nil, domain.NewValidationError("address.street is mandatory field")
+	phoneNumber, err := phone.Normalize(request.Phone)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
 	}
-	if request.Address.Building == "" {
-		return nil, domain.NewValidationError("address.building is mandatory field")
+	address, err := addressFromRequest(request.Address)
+	if err != nil {
+		return nil, err
 	}
 	passportNumber := strings.Replace(request.Passport.Number, " ", "", -1)
 	if len(passportNumber) != 10 {
@@ -58,17 +83,12 @@ func customerFromRequest(request *CustomerBody) (*domain.Customer, error) {
 	}
 
 	customer := &domain.Customer{
+		Type:      domain.CustomerTypeIndividual,
 		FirstName: request.FirstName,
 		LastName:  request.LastName,
 		Email:     request.Email,
-		Phone:     request.Phone,
-		Address: domain.Address{
-			Country:  request.Address.Country,
-			Region:   request.Address.Region,
-			City:     request.Address.City,
-			Street:   request.Address.Street,
-			Building: request.Address.Building,
-		},
+		Phone:     phoneNumber,
+		Address:   address,
 		Passport: domain.Passport{
 			Number:     request.Passport.Number,
 			IssueDate:  issueDate,
@@ -80,11 +100,99 @@ func customerFromRequest(request *CustomerBody) (*domain.Customer, error) {
 	return customer, nil
 }
 
+func legalEntityFromRequest(request *LegalEntityBody) (*domain.Customer, error) {
+	if request.Phone == "" {
+		return nil, domain.NewValidationError("phone is mandatory field")
+	}
+	phoneNumber, err := phone.Normalize(request.Phone)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+	address, err := addressFromRequest(request.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	company := domain.Company{
+		Name:             strings.TrimSpace(request.CompanyName),
+		INN:              request.INN,
+		KPP:              strings.ToUpper(request.KPP),
+		OGRN:             request.OGRN,
+		BeneficialOwners: make([]domain.BeneficialOwner, 0, len(request.BeneficialOwners)),
+		Representatives:  make([]domain.Representative, 0, len(request.Representatives)),
+	}
+	for _, owner := range request.BeneficialOwners {
+		share, err := legalentity.ParseShare(owner.Share)
+		if err != nil {
+			return nil, domain.NewValidationError(fmt.Sprintf("beneficial_owners.share: %s", err.Error()))
+		}
+		company.BeneficialOwners = append(company.BeneficialOwners, domain.BeneficialOwner{
+			CustomerID: owner.CustomerID,
+			Share:      share,
+		})
+	}
+	for _, representative := range request.Representatives {
+		company.Representatives = append(company.Representatives, domain.Representative{
+			CustomerID: representative.CustomerID,
+			Position:   strings.TrimSpace(representative.Position),
+		})
+	}
+	err = legalentity.ValidateCompany(company)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+
+	customer := &domain.Customer{
+		Type:    domain.CustomerTypeLegalEntity,
+		Email:   request.Email,
+		Phone:   phoneNumber,
+		Address: address,
+		Company: company,
+	}
+	return customer, nil
+}
+
+func addressFromRequest(request AddressBody) (domain.Address, error) {
+	if request.Country == "" {
+		return domain.Address{}, domain.NewValidationError("address.country is mandatory field")
+	}
+	if request.Region == "" {
+		return domain.Address{}, domain.NewValidationError("address.region is mandatory field")
+	}
+	if request.City == "" {
+		return domain.Address{}, domain.NewValidationError("address.city is mandatory field")
+	}
+	if request.Street == "" {
+		return domain.Address{}, domain.NewValidationError("address.street is mandatory field")
+	}
+	if request.Building == "" {
+		return domain.Address{}, domain.NewValidationError("address.building is mandatory field")
+	}
+	address := domain.Address{
+		Country:  request.Country,
+		Region:   request.Region,
+		City:     request.City,
+		Street:   request.Street,
+		Building: request.Building,
+	}
+	return address, nil
+}
+
+// customerResponse is CustomerBody for individual and LegalEntityBody for legal entity
+func customerResponse(customer *domain.Customer) interface{} {
+	if customer.Type == domain.CustomerTypeLegalEntity {
+		return responseFromLegalEntity(customer)
+	}
+	return responseFromCustomer(customer)
+}
+
 func responseFromCustomer(customer *domain.Customer) *CustomerBody {
 	issueDate := customer.Passport.IssueDate.Format(domain.DateFormat)
 	birthDate := customer.Passport.BirthDate.Format(domain.DateFormat)
 	return &CustomerBody{
 		CustomerID: customer.GeneratedID,
+		Type:       string(customer.Type),
+		Status:     string(customer.Status),
 		FirstName:  customer.FirstName,
 		LastName:   customer.LastName,
 		Email:      customer.Email,
@@ -117,3 +225,39 @@ func responseFromCustomer(customer *domain.Customer) *CustomerBody {
 		},
 	}
 }
+
+func responseFromLegalEntity(customer *domain.Customer) *LegalEntityBody {
+	response := &LegalEntityBody{
+		CustomerID:  customer.GeneratedID,
+		Type:        string(customer.Type),
+		Status:      string(customer.Status),
+		CompanyName: customer.Company.Name,
+		INN:         customer.Company.INN,
+		KPP:         customer.Company.KPP,
+		OGRN:        customer.Company.OGRN,
+		Email:       customer.Email,
+		Phone:       customer.Phone,
+		Address: AddressBody{
+			Country:  customer.Address.Country,
+			Region:   customer.Address.Region,
+			City:     customer.Address.City,
+			Street:   customer.Address.Street,
+			Building: customer.Address.Building,
+		},
+		BeneficialOwners: make([]BeneficialOwnerBody, 0, len(customer.Company.BeneficialOwners)),
+		Representatives:  make([]RepresentativeBody, 0, len(customer.Company.Representatives)),
+	}
+	for _, owner := range customer.Company.BeneficialOwners {
+		response.BeneficialOwners = append(response.BeneficialOwners, BeneficialOwnerBody{
+			CustomerID: owner.CustomerID,
+			Share:      legalentity.FormatShare(owner.Share),
+		})
+	}
+	for _, representative := range customer.Company.Representatives {
+		response.Representatives = append(response.Representatives, RepresentativeBody{
+			CustomerID: representative.CustomerID,
+			Position:   representative.Position,
+		})
+	}
+	return response
+}
@@ -326,3 +326,68 @@ func TestCustomerFromRequest_AllMandatoryFields(t *testing.T) {
 	assert.Equal(t, "01-01-2010", customer.Passport.IssueDate.Format(domain.DateFormat))
 	assert.Equal(t, "Gov", customer.Passport.Issuer)
 }
+
+func TestLegalEntityFromRequest_ValidationError(t *testing.T) {
+	validRequest := func() *LegalEntityBody {
+		return &LegalEntityBody{
+			CompanyName: "Wayne Enterprises LLC",
+			INN:         "7707083893",
+			KPP:         "773601001",
+			OGRN:        "1027700132195",
+			Phone:       "+74952223344",
+			Address:     AddressBody{Country: "Russia", Region: "Sakha", City: "Yakutsk", Street: "Marks", Building: "1"},
+			BeneficialOwners: []BeneficialOwnerBody{
+				{CustomerID: "bruce", Share: "75.5"},
+			},
+			Representatives: []RepresentativeBody{{CustomerID: "bruce", Position: "CEO"}},
+		}
+	}
+
+	customer, err := legalEntityFromRequest(validRequest())
+	assert.Nil(t, err)
+	assert.Equal(t, domain.CustomerTypeLegalEntity, customer.Type)
+	assert.Equal(t, 7550, customer.Company.BeneficialOwners[0].Share)
+	assert.Equal(t, "Yakutsk", customer.Address.City)
+
+	testCases := []struct {
+		name   string
+		change func(request *LegalEntityBody)
+		result string
+	}{
+		{"NoPhone", func(request *LegalEntityBody) { request.Phone = "" }, "phone is mandatory field"},
+		{
+			"NoAddress",
+			func(request *LegalEntityBody) { request.Address.Building = "" },
+			"address.building is mandatory field",
+		},
+		{
+			"WrongINNChecksum",
+			func(request *LegalEntityBody) { request.INN = "7707083894" },
+			"inn should be 10 digits with valid check digit",
+		},
+		{
+			"WrongShare",
+			func(request *LegalEntityBody) { request.BeneficialOwners[0].Share = "75.555" },
+			"beneficial_owners.share: share should have 2 decimals at most",
+		},
+		{
+			"NoRepresentatives",
+			func(request *LegalEntityBody) { request.Representatives = nil },
+			"company should have a representative",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			request := validRequest()
+			test.change(request)
+			_, err := legalEntityFromRequest(request)
+			assert.Equal(t, test.result, err.Error())
+		})
+	}
+}
+
+func TestCustomerFromBody_UnknownType(t *testing.T) {
+	_, err := customerFromBody([]byte(`{"type": "trust"}`))
+	assert.Equal(t, "type should be individual or legal_entity", err.Error())
+}
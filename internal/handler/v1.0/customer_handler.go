@@ -1,7 +1,6 @@
 package v1
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -28,10 +27,19 @@ func NewCustomerHandlerV1(
 	return &CustomerHandlerV1{logger: logger, useCase: customerService, responseWriter: responseWriter}
 }
 
+// CustomerTypeBody is read first to choose between CustomerBody and LegalEntityBody
+type CustomerTypeBody struct {
+	Type string `json:"type"`
+}
+
 // swagger:parameters CreateCustomer UpdateCustomer
 type CustomerBody struct {
 	// in:body
 	CustomerID string `json:"customer_id"`
+	// Type is individual or empty
+	Type string `json:"type,omitempty"`
+	// Status is set by service and ignored in requests
+	Status string `json:"status,omitempty"`
 	// in:body
 	FirstName string `json:"first_name"`
 	// in:body
@@ -58,22 +66,55 @@ type CustomerBody struct {
 	} `json:"passport"`
 }
 
+// LegalEntityBody is sent to customer endpoints with type legal_entity instead of CustomerBody.
+// Beneficial owners and representatives should be individual customers.
+// swagger:model
+type LegalEntityBody struct {
+	CustomerID string `json:"customer_id"`
+	// Type is legal_entity
+	Type string `json:"type"`
+	// Status is set by service and ignored in requests
+	Status      string `json:"status,omitempty"`
+	CompanyName string `json:"company_name"`
+	INN         string `json:"inn"`
+	KPP         string `json:"kpp"`
+	OGRN        string `json:"ogrn"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	// Address is registered address of company
+	Address          AddressBody           `json:"address"`
+	BeneficialOwners []BeneficialOwnerBody `json:"beneficial_owners"`
+	Representatives  []RepresentativeBody  `json:"representatives"`
+}
+
+type AddressBody struct {
+	Country  string `json:"country"`
+	Region   string `json:"region"`
+	City     string `json:"city"`
+	Street   string `json:"street"`
+	Building string `json:"building"`
+}
+
+type BeneficialOwnerBody struct {
+	CustomerID string `json:"customer_id"`
+	// Share is percent with up to 2 decimal places, like "25.5"
+	Share string `json:"share"`
+}
+
+type RepresentativeBody struct {
+	CustomerID string `json:"customer_id"`
+	Position   string `json:"position"`
+}
+
 // swagger:route POST /customer customers CreateCustomer
-// Creates a new customer.
+// Creates a new customer, individual or legal entity depending on type.
 // responses:
 //  200:
 //  400: ErrorResponse
 //  409: ErrorResponse
 //  500: ErrorResponse
 func (h *CustomerHandlerV1) Create(ctx *fasthttp.RequestCtx) {
-	request := &CustomerBody{}
-	err := json.Unmarshal(ctx.PostBody(), request)
-	if err != nil {
-		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
-		return
-	}
-
-	customer, err := customerFromRequest(request)
+	customer, err := customerFromBody(ctx.PostBody())
 	if err != nil {
 		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
 		return
@@ -94,7 +135,7 @@ func (h *CustomerHandlerV1) Create(ctx *fasthttp.RequestCtx) {
 			return
 		}
 	}
-	h.responseWriter.WriteSuccessPOST(ctx, responseFromCustomer(customer))
+	h.responseWriter.WriteSuccessPOST(ctx, customerResponse(customer))
 }
 
 // swagger:route GET /customer/{id} customers FindCustomer
@@ -124,7 +165,7 @@ func (h *CustomerHandlerV1) Find(ctx *fasthttp.RequestCtx) {
 		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusNotFound), fasthttp.StatusNotFound)
 		return
 	}
-	h.responseWriter.WriteSuccessGET(ctx, responseFromCustomer(customer))
+	h.responseWriter.WriteSuccessGET(ctx, customerResponse(customer))
 }
 
 // swagger:route PUT /customer/{id} customers UpdateCustomer
@@ -141,14 +182,7 @@ func (h *CustomerHandlerV1) Update(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	request := &CustomerBody{}
-	err := json.Unmarshal(ctx.PostBody(), request)
-	if err != nil {
-		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
-		return
-	}
-
-	customer, err := customerFromRequest(request)
+	customer, err := customerFromBody(ctx.PostBody())
 	if err != nil {
 		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
 		return
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"encoding/json"
 	"net"
 	"net/http"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/fasthttputil"
 	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/screening"
 	"go.uber.org/zap"
 
 	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
@@ -28,8 +30,15 @@ func TestCreate_Success(t *testing.T) {
 
 	repositoryMock := mocks.NewMockCustomerRepository(ctrl)
 	repositoryMock.EXPECT().FindByPassportNumber(gomock.Any()).Return(nil, nil)
+	repositoryMock.EXPECT().FindByPhone(gomock.Any()).Return(nil, nil)
 	repositoryMock.EXPECT().Create(gomock.Any()).Return(nil)
-	useCase := usecase.NewCustomerUseCase(repositoryMock)
+	sanctionRepositoryMock := mocks.NewMockSanctionRepository(ctrl)
+	sanctionRepositoryMock.EXPECT().FindCandidates(gomock.Any()).Return(nil, nil)
+	useCase := usecase.NewCustomerUseCase(
+		repositoryMock,
+		sanctionRepositoryMock,
+		screening.NewMatcher(screening.DefaultThreshold),
+	)
 	logger, _ := zap.NewDevelopment()
 	writer := NewJSONResponseWriter(logger)
 	handlerV1 := NewCustomerHandlerV1(logger, useCase, writer)
@@ -62,7 +71,7 @@ func TestCreate_Success(t *testing.T) {
 	var requestBody = []byte(`{
 		"first_name": "foo",
 		"last_name": "too",
-		"phone": "+7993",
+		"phone": "+79931234567",
 		"address": {
 			"country": "R",
 			"region": "R",
@@ -90,6 +99,276 @@ func TestCreate_Success(t *testing.T) {
 	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
 }
 
+func TestCreate_SanctionsMatch(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	repositoryMock.EXPECT().FindByPassportNumber(gomock.Any()).Return(nil, nil)
+	repositoryMock.EXPECT().FindByPhone(gomock.Any()).Return(nil, nil)
+	repositoryMock.EXPECT().Create(gomock.Any()).Return(nil)
+	sanctionRepositoryMock := mocks.NewMockSanctionRepository(ctrl)
+	sanctionRepositoryMock.EXPECT().FindCandidates(gomock.Any()).Return([]domain.SanctionEntry{
+		{ListName: "local", ListType: domain.SanctionListTypeSanctions, FullName: "ИВАНОВ Иван Иванович"},
+	}, nil)
+	sanctionRepositoryMock.EXPECT().FindMatchesByCustomerID(gomock.Any()).Return(nil, nil)
+	sanctionRepositoryMock.EXPECT().CreateMatches(gomock.Len(1)).Return(nil)
+	useCase := usecase.NewCustomerUseCase(
+		repositoryMock,
+		sanctionRepositoryMock,
+		screening.NewMatcher(screening.DefaultThreshold),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewCustomerHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer", handlerV1.Create)
+
+	ln := fasthttputil.NewInmemoryListener()
+
+	s := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = s.Serve(ln)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return ln.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	var requestBody = []byte(`{
+		"first_name": "Ivan",
+		"last_name": "Ivanov",
+		"phone": "+79931234567",
+		"address": {
+			"country": "R",
+			"region": "R",
+			"city": "R",
+			"street": "R",
+			"building": "105"
+		},
+		"passport": {
+			"number": "1234567890",
+			"birth_date": "01-01-2000",
+			"birth_place": "R",
+			"issuer": "MMM",
+			"issue_date": "01-01-2000"
+		}
+	}`)
+
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBody(requestBody)
+	request.SetRequestURI("/customer")
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	responseJSON := CustomerBody{}
+	_ = json.Unmarshal(response.Body(), &responseJSON)
+
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	assert.Equal(t, "pending_review", responseJSON.Status)
+}
+
+func TestCreate_LegalEntity(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	repositoryMock.EXPECT().FindByOGRN("1027700132195").Return(nil, nil)
+	repositoryMock.EXPECT().FindByPhone("+74952223344").Return(nil, nil)
+	repositoryMock.EXPECT().FindByID("bruce").Return(
+		&domain.Customer{GeneratedID: "bruce", Type: domain.CustomerTypeIndividual},
+		nil,
+	)
+	repositoryMock.EXPECT().FindByID("alfred").Return(
+		&domain.Customer{GeneratedID: "alfred", Type: domain.CustomerTypeIndividual},
+		nil,
+	)
+	var createdCustomer *domain.Customer
+	repositoryMock.EXPECT().Create(gomock.Any()).DoAndReturn(func(customer *domain.Customer) error {
+		createdCustomer = customer
+		return nil
+	})
+	sanctionRepositoryMock := mocks.NewMockSanctionRepository(ctrl)
+	sanctionRepositoryMock.EXPECT().FindCandidates(time.Time{}).Return(nil, nil)
+	useCase := usecase.NewCustomerUseCase(
+		repositoryMock,
+		sanctionRepositoryMock,
+		screening.NewMatcher(screening.DefaultThreshold),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewCustomerHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer", handlerV1.Create)
+
+	ln := fasthttputil.NewInmemoryListener()
+
+	s := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = s.Serve(ln)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return ln.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	var requestBody = []byte(`{
+		"type": "legal_entity",
+		"company_name": "Wayne Enterprises LLC",
+		"inn": "7707083893",
+		"kpp": "773601001",
+		"ogrn": "1027700132195",
+		"phone": "+7 495 222-33-44",
+		"address": {
+			"country": "R",
+			"region": "R",
+			"city": "R",
+			"street": "R",
+			"building": "1"
+		},
+		"beneficial_owners": [
+			{"customer_id": "bruce", "share": "75"},
+			{"customer_id": "alfred", "share": "25"}
+		],
+		"representatives": [
+			{"customer_id": "bruce", "position": "CEO"}
+		]
+	}`)
+
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBody(requestBody)
+	request.SetRequestURI("/customer")
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	responseJSON := LegalEntityBody{}
+	_ = json.Unmarshal(response.Body(), &responseJSON)
+
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	assert.Equal(t, "legal_entity", responseJSON.Type)
+	assert.Equal(t, "active", responseJSON.Status)
+	assert.Equal(t, "Wayne Enterprises LLC", responseJSON.CompanyName)
+	assert.Equal(t, []BeneficialOwnerBody{
+		{CustomerID: "bruce", Share: "75.00"},
+		{CustomerID: "alfred", Share: "25.00"},
+	}, responseJSON.BeneficialOwners)
+	assert.Equal(t, []RepresentativeBody{{CustomerID: "bruce", Position: "CEO"}}, responseJSON.Representatives)
+	assert.Equal(t, 7500, createdCustomer.Company.BeneficialOwners[0].Share)
+}
+
+func TestCreate_LegalEntityOfficerIsNotIndividual(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	repositoryMock.EXPECT().FindByOGRN(gomock.Any()).Return(nil, nil)
+	repositoryMock.EXPECT().FindByPhone(gomock.Any()).Return(nil, nil)
+	repositoryMock.EXPECT().FindByID("holding").Return(
+		&domain.Customer{GeneratedID: "holding", Type: domain.CustomerTypeLegalEntity},
+		nil,
+	)
+	useCase := usecase.NewCustomerUseCase(
+		repositoryMock,
+		mocks.NewMockSanctionRepository(ctrl),
+		screening.NewMatcher(screening.DefaultThreshold),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewCustomerHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer", handlerV1.Create)
+
+	ln := fasthttputil.NewInmemoryListener()
+
+	s := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = s.Serve(ln)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return ln.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	var requestBody = []byte(`{
+		"type": "legal_entity",
+		"company_name": "Wayne Enterprises LLC",
+		"inn": "7707083893",
+		"kpp": "773601001",
+		"ogrn": "1027700132195",
+		"phone": "+74952223344",
+		"address": {
+			"country": "R",
+			"region": "R",
+			"city": "R",
+			"street": "R",
+			"building": "1"
+		},
+		"representatives": [
+			{"customer_id": "holding", "position": "Managing company"}
+		]
+	}`)
+
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBody(requestBody)
+	request.SetRequestURI("/customer")
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.Contains(t, string(response.Body()), "officer holding should be individual customer")
+}
+
 func TestCreate_ValidationError(t *testing.T) {
 	t.Parallel()
 
@@ -112,7 +391,7 @@ func TestCreate_ValidationError(t *testing.T) {
 			[]byte(`{
 		"first_name": "foo",
 		"last_name": "too",
-		"phone": "+7993",
+		"phone": "+79931234567",
 		"address": {
 			"country": "R",
 			"region": "R",
@@ -142,7 +421,12 @@ func TestCreate_ValidationError(t *testing.T) {
 
 			repositoryMock := mocks.NewMockCustomerRepository(ctrl)
 			repositoryMock.EXPECT().FindByPassportNumber(gomock.Any()).AnyTimes().Return(&domain.Customer{}, nil)
-			useCase := usecase.NewCustomerUseCase(repositoryMock)
+			sanctionRepositoryMock := mocks.NewMockSanctionRepository(ctrl)
+			useCase := usecase.NewCustomerUseCase(
+				repositoryMock,
+				sanctionRepositoryMock,
+				screening.NewMatcher(screening.DefaultThreshold),
+			)
 			logger, _ := zap.NewDevelopment()
 			writer := NewJSONResponseWriter(logger)
 			handlerV1 := NewCustomerHandlerV1(logger, useCase, writer)
@@ -208,8 +492,13 @@ func TestFind_CustomerFound(t *testing.T) {
 
 	repositoryMock := mocks.NewMockCustomerRepository(ctrl)
 	repositoryMock.EXPECT().FindByID(gomock.Any()).Return(&customer, nil)
+	sanctionRepositoryMock := mocks.NewMockSanctionRepository(ctrl)
 
-	useCase := usecase.NewCustomerUseCase(repositoryMock)
+	useCase := usecase.NewCustomerUseCase(
+		repositoryMock,
+		sanctionRepositoryMock,
+		screening.NewMatcher(screening.DefaultThreshold),
+	)
 	logger, _ := zap.NewDevelopment()
 	writer := NewJSONResponseWriter(logger)
 	handlerV1 := NewCustomerHandlerV1(logger, useCase, writer)
@@ -279,8 +568,13 @@ func TestFind_CustomerNotFound(t *testing.T) {
 
 	repositoryMock := mocks.NewMockCustomerRepository(ctrl)
 	repositoryMock.EXPECT().FindByID(gomock.Any()).Return(nil, nil)
+	sanctionRepositoryMock := mocks.NewMockSanctionRepository(ctrl)
 
-	useCase := usecase.NewCustomerUseCase(repositoryMock)
+	useCase := usecase.NewCustomerUseCase(
+		repositoryMock,
+		sanctionRepositoryMock,
+		screening.NewMatcher(screening.DefaultThreshold),
+	)
 	logger, _ := zap.NewDevelopment()
 	writer := NewJSONResponseWriter(logger)
 	handlerV1 := NewCustomerHandlerV1(logger, useCase, writer)
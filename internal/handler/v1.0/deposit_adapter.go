@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/deposit"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func depositProductFromRequest(request *DepositProductRequestBody) (*domain.DepositProduct, error) {
+	if request.Name == "" {
+		return nil, domain.NewValidationError("name is mandatory field")
+	}
+	if !currencyRegexp.MatchString(request.Currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	return &domain.DepositProduct{
+		Name:                   request.Name,
+		Type:                   domain.DepositType(request.Type),
+		Currency:               request.Currency,
+		AnnualRate:             request.AnnualRate,
+		Capitalisation:         domain.DepositCapitalisation(request.Capitalisation),
+		DayCount:               domain.DayCountConvention(request.DayCount),
+		TermMonths:             request.TermMonths,
+		EarlyWithdrawalPenalty: request.EarlyWithdrawalPenalty,
+		MinAmount:              request.MinAmount,
+	}, nil
+}
+
+// depositProjectionFromRequest reads optional amount and months of projection
+func depositProjectionFromRequest(args *fasthttp.Args) (int64, int, error) {
+	var amount int64
+	if args.Has("amount") {
+		var err error
+		amount, err = strconv.ParseInt(string(args.Peek("amount")), 10, 64)
+		if err != nil || amount <= 0 {
+			return 0, 0, domain.NewValidationError("amount should be a positive number")
+		}
+	}
+	var months int
+	if args.Has("months") {
+		var err error
+		months, err = strconv.Atoi(string(args.Peek("months")))
+		if err != nil || months <= 0 || months > deposit.MaxTermMonths {
+			return 0, 0, domain.NewValidationError("months should be a number from 1 to 120")
+		}
+	}
+	return amount, months, nil
+}
+
+func responseFromDepositProduct(product *domain.DepositProduct) *DepositProductBody {
+	return &DepositProductBody{
+		ProductID:              product.GeneratedID,
+		Name:                   product.Name,
+		Type:                   string(product.Type),
+		Currency:               product.Currency,
+		AnnualRate:             product.AnnualRate,
+		Capitalisation:         string(product.Capitalisation),
+		DayCount:               string(product.DayCount),
+		TermMonths:             product.TermMonths,
+		EarlyWithdrawalPenalty: product.EarlyWithdrawalPenalty,
+		MinAmount:              product.MinAmount,
+	}
+}
+
+func responseFromDeposit(deposit *domain.Deposit) *DepositBody {
+	response := &DepositBody{
+		DepositID:           deposit.GeneratedID,
+		ProductID:           deposit.ProductID,
+		CustomerID:          deposit.CustomerID,
+		Currency:            deposit.Currency,
+		Principal:           deposit.Principal,
+		AccruedInterest:     deposit.AccruedInterest,
+		CapitalisedInterest: deposit.CapitalisedInterest,
+		Status:              string(deposit.Status),
+		AccruedUntil:        deposit.AccruedUntil.In(businessday.Location).Format(domain.DateFormat),
+		PaidOut:             deposit.PaidOut,
+		OpenedAt:            deposit.OpenedAt.Format(domain.DateTimeFormat),
+	}
+	if !deposit.MaturesAt.IsZero() {
+		response.MaturesAt = deposit.MaturesAt.In(businessday.Location).Format(domain.DateFormat)
+	}
+	if !deposit.ClosedAt.IsZero() {
+		response.ClosedAt = deposit.ClosedAt.Format(domain.DateTimeFormat)
+	}
+	return response
+}
+
+func responseFromDepositInterestEntries(entries []*domain.DepositInterestEntry) *DepositInterestEntriesBody {
+	response := &DepositInterestEntriesBody{Entries: make([]*DepositInterestEntryBody, 0, len(entries))}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, &DepositInterestEntryBody{
+			Type:   string(entry.Type),
+			Amount: entry.Amount,
+			Day:    entry.Day.In(businessday.Location).Format(domain.DateFormat),
+		})
+	}
+	return response
+}
+
+func responseFromDepositProjection(projection *domain.DepositProjection) *DepositProjectionBody {
+	return &DepositProjectionBody{
+		Principal:     projection.Principal,
+		Interest:      projection.Interest,
+		FinalAmount:   projection.Principal + projection.Interest,
+		From:          projection.From.In(businessday.Location).Format(domain.DateFormat),
+		To:            projection.To.In(businessday.Location).Format(domain.DateFormat),
+		EffectiveRate: projection.EffectiveRate,
+	}
+}
@@ -0,0 +1,403 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const (
+	DepositIdUrlPath        = "id"
+	DepositProductIdUrlPath = "id"
+)
+
+type DepositHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.DepositUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewDepositHandlerV1(
+	logger *zap.Logger,
+	depositService *usecase.DepositUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *DepositHandlerV1 {
+	return &DepositHandlerV1{logger: logger, useCase: depositService, responseWriter: responseWriter}
+}
+
+// swagger:parameters CreateDepositProduct
+type DepositProductRequestBody struct {
+	// in:body
+	Name string `json:"name"`
+	// term or on_demand
+	// in:body
+	Type string `json:"type"`
+	// in:body
+	Currency string `json:"currency"`
+	// decimal percent like 7.5
+	// in:body
+	AnnualRate string `json:"annual_rate"`
+	// monthly or at_maturity, at_maturity is available for term deposits only
+	// in:body
+	Capitalisation string `json:"capitalisation"`
+	// ACT/365, ACT/360 or ACT/ACT
+	// in:body
+	DayCount string `json:"day_count"`
+	// term of term deposits
+	// in:body
+	TermMonths int `json:"term_months"`
+	// decimal percent of earned interest forfeited when term deposit is closed before maturity
+	// in:body
+	EarlyWithdrawalPenalty string `json:"early_withdrawal_penalty"`
+	// in minor currency units
+	// in:body
+	MinAmount int64 `json:"min_amount"`
+}
+
+// swagger:parameters OpenDeposit
+type DepositRequestBody struct {
+	// in:body
+	ProductID string `json:"product_id"`
+	// principal in minor currency units, it is debited from customer balance
+	// in:body
+	Amount int64 `json:"amount"`
+}
+
+// swagger:parameters CloseDeposit
+type DepositActionRequestBody struct {
+	// in:body
+	CustomerID string `json:"customer_id"`
+}
+
+// swagger:parameters ProjectDeposit ProjectDepositProduct
+type DepositProjectionQuery struct {
+	// principal in minor currency units, for product projection only
+	// in:query
+	Amount int64 `json:"amount"`
+	// months since opening, term of term deposit or 12 months of on demand deposit by default
+	// in:query
+	Months int `json:"months"`
+}
+
+type DepositProductsBody struct {
+	Products []*DepositProductBody `json:"products"`
+}
+
+type DepositProductBody struct {
+	ProductID              string `json:"product_id"`
+	Name                   string `json:"name"`
+	Type                   string `json:"type"`
+	Currency               string `json:"currency"`
+	AnnualRate             string `json:"annual_rate"`
+	Capitalisation         string `json:"capitalisation"`
+	DayCount               string `json:"day_count"`
+	TermMonths             int    `json:"term_months,omitempty"`
+	EarlyWithdrawalPenalty string `json:"early_withdrawal_penalty,omitempty"`
+	MinAmount              int64  `json:"min_amount"`
+}
+
+type DepositsBody struct {
+	Deposits []*DepositBody `json:"deposits"`
+}
+
+type DepositBody struct {
+	DepositID           string `json:"deposit_id"`
+	ProductID           string `json:"product_id"`
+	CustomerID          string `json:"customer_id"`
+	Currency            string `json:"currency"`
+	Principal           int64  `json:"principal"`
+	AccruedInterest     int64  `json:"accrued_interest"`
+	CapitalisedInterest int64  `json:"capitalised_interest"`
+	Status              string `json:"status"`
+	AccruedUntil        string `json:"accrued_until"`
+	MaturesAt           string `json:"matures_at,omitempty"`
+	PaidOut             int64  `json:"paid_out,omitempty"`
+	OpenedAt            string `json:"opened_at"`
+	ClosedAt            string `json:"closed_at,omitempty"`
+}
+
+type DepositInterestEntriesBody struct {
+	Entries []*DepositInterestEntryBody `json:"entries"`
+}
+
+type DepositInterestEntryBody struct {
+	Type   string `json:"type"`
+	Amount int64  `json:"amount"`
+	Day    string `json:"day"`
+}
+
+type DepositProjectionBody struct {
+	Principal     int64  `json:"principal"`
+	Interest      int64  `json:"interest"`
+	FinalAmount   int64  `json:"final_amount"`
+	From          string `json:"from"`
+	To            string `json:"to"`
+	EffectiveRate string `json:"effective_rate"`
+}
+
+// swagger:route POST /deposit-products deposits CreateDepositProduct
+// Creates deposit product offered to customers.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *DepositHandlerV1) CreateProduct(ctx *fasthttp.RequestCtx) {
+	request := &DepositProductRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	product, err := depositProductFromRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.CreateProduct(product)
+	if err != nil {
+		h.writeDepositError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromDepositProduct(product))
+}
+
+// swagger:route GET /deposit-products deposits FindDepositProducts
+// Lists deposit products.
+// responses:
+//  200:
+//  500: ErrorResponse
+func (h *DepositHandlerV1) FindProducts(ctx *fasthttp.RequestCtx) {
+	products, err := h.useCase.FindProducts()
+	if err != nil {
+		h.writeDepositError(ctx, err)
+		return
+	}
+	response := &DepositProductsBody{Products: make([]*DepositProductBody, 0, len(products))}
+	for _, product := range products {
+		response.Products = append(response.Products, responseFromDepositProduct(product))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route GET /deposit-products/{id}/projection deposits ProjectDepositProduct
+// Shows interest expected for deposit of product with amount opened today.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *DepositHandlerV1) ProjectProduct(ctx *fasthttp.RequestCtx) {
+	productID := ctx.UserValue(DepositProductIdUrlPath)
+	if _, ok := productID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	amount, months, err := depositProjectionFromRequest(ctx.QueryArgs())
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	projection, err := h.useCase.ProjectProduct(productID.(string), amount, months)
+	if err != nil {
+		h.writeDepositError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromDepositProjection(projection))
+}
+
+// swagger:route POST /customer/{id}/deposits deposits OpenDeposit
+// Opens deposit moving amount from customer balance, interest is accrued daily starting from today.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *DepositHandlerV1) Open(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &DepositRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	if request.ProductID == "" {
+		h.responseWriter.WriteError(ctx, "product_id is mandatory field", fasthttp.StatusBadRequest)
+		return
+	}
+
+	deposit := &domain.Deposit{
+		ProductID:  request.ProductID,
+		CustomerID: customerID.(string),
+		Principal:  request.Amount,
+	}
+	err = h.useCase.Open(deposit)
+	if err != nil {
+		h.writeDepositError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromDeposit(deposit))
+}
+
+// swagger:route GET /customer/{id}/deposits deposits FindCustomerDeposits
+// Lists deposits of customer, the latest first.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *DepositHandlerV1) FindByCustomer(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	deposits, err := h.useCase.FindByCustomer(customerID.(string))
+	if err != nil {
+		h.writeDepositError(ctx, err)
+		return
+	}
+	response := &DepositsBody{Deposits: make([]*DepositBody, 0, len(deposits))}
+	for _, deposit := range deposits {
+		response.Deposits = append(response.Deposits, responseFromDeposit(deposit))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route GET /deposits/{id} deposits FindDeposit
+// Shows deposit with interest accrued till yesterday.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *DepositHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	depositID := ctx.UserValue(DepositIdUrlPath)
+	if _, ok := depositID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	deposit, err := h.useCase.Find(depositID.(string))
+	if err != nil {
+		h.writeDepositError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromDeposit(deposit))
+}
+
+// swagger:route GET /deposits/{id}/interest deposits FindDepositInterest
+// Lists daily accruals, capitalisations and penalties of deposit interest.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *DepositHandlerV1) FindInterest(ctx *fasthttp.RequestCtx) {
+	depositID := ctx.UserValue(DepositIdUrlPath)
+	if _, ok := depositID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	entries, err := h.useCase.FindInterestEntries(depositID.(string))
+	if err != nil {
+		h.writeDepositError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromDepositInterestEntries(entries))
+}
+
+// swagger:route GET /deposits/{id}/projection deposits ProjectDeposit
+// Shows interest expected for deposit since opening till maturity or for months since opening.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *DepositHandlerV1) Project(ctx *fasthttp.RequestCtx) {
+	depositID := ctx.UserValue(DepositIdUrlPath)
+	if _, ok := depositID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	_, months, err := depositProjectionFromRequest(ctx.QueryArgs())
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	projection, err := h.useCase.Project(depositID.(string), months)
+	if err != nil {
+		h.writeDepositError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromDepositProjection(projection))
+}
+
+// swagger:route POST /deposits/{id}/close deposits CloseDeposit
+// Pays principal with accrued interest to customer balance. Term deposit closed before maturity
+// forfeits early withdrawal penalty.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *DepositHandlerV1) Close(ctx *fasthttp.RequestCtx) {
+	depositID := ctx.UserValue(DepositIdUrlPath)
+	if _, ok := depositID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &DepositActionRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	if request.CustomerID == "" {
+		h.responseWriter.WriteError(ctx, "customer_id is mandatory field", fasthttp.StatusBadRequest)
+		return
+	}
+
+	deposit, err := h.useCase.Close(depositID.(string), request.CustomerID)
+	if err != nil {
+		h.writeDepositError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromDeposit(deposit))
+}
+
+func (h *DepositHandlerV1) writeDepositError(ctx *fasthttp.RequestCtx, err error) {
+	switch err.(type) {
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process deposit. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
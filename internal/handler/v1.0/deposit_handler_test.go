@@ -0,0 +1,237 @@
+package v1
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+var testDepositProduct = &domain.DepositProduct{
+	GeneratedID:            "product",
+	Name:                   "Savings 12",
+	Type:                   domain.DepositTypeTerm,
+	Currency:               "RUB",
+	AnnualRate:             "8",
+	Capitalisation:         domain.DepositCapitalisationAtMaturity,
+	DayCount:               domain.DayCountACT365,
+	TermMonths:             12,
+	EarlyWithdrawalPenalty: "50",
+	MinAmount:              100000,
+}
+
+func TestOpenDeposit_Success(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().
+		FindByID("customer").
+		Return(&domain.Customer{GeneratedID: "customer", Status: domain.CustomerStatusActive}, nil)
+	repositoryMock := mocks.NewMockDepositRepository(ctrl)
+	repositoryMock.EXPECT().FindProductByID("product").Return(testDepositProduct, nil)
+	repositoryMock.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(deposit *domain.Deposit, postings []*domain.Posting) (bool, error) {
+			if assert.Len(t, postings, 2) {
+				assert.Equal(t, "customer", postings[0].CustomerID)
+				assert.Equal(t, int64(-500000), postings[0].Amount)
+				assert.Equal(t, domain.LedgerAccountDeposits, postings[1].CustomerID)
+				assert.Equal(t, int64(500000), postings[1].Amount)
+				assert.Equal(t, "RUB", postings[1].Currency)
+				assert.Equal(t, "deposit:"+deposit.GeneratedID, postings[1].Reference)
+			}
+			return true, nil
+		})
+
+	useCase := usecase.NewDepositUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		newVerificationUseCase(ctrl, approvedVerification),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewDepositHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/deposits", handlerV1.Open)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/customer/deposits")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"product_id": "product", "amount": 500000}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	body := &DepositBody{}
+	err := json.Unmarshal(response.Body(), body)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, "product", body.ProductID)
+	assert.Equal(t, int64(500000), body.Principal)
+	assert.Equal(t, int64(0), body.AccruedInterest)
+	assert.Equal(t, "RUB", body.Currency)
+	assert.Equal(t, "open", body.Status)
+	assert.NotEmpty(t, body.MaturesAt)
+}
+
+func TestOpenDeposit_CustomerNotVerified(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().
+		FindByID("customer").
+		Return(&domain.Customer{GeneratedID: "customer", Status: domain.CustomerStatusActive}, nil)
+
+	useCase := usecase.NewDepositUseCase(
+		mocks.NewMockDepositRepository(ctrl),
+		customerRepositoryMock,
+		newVerificationUseCase(ctrl, &domain.Verification{Status: domain.VerificationStatusInReview}),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewDepositHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/deposits", handlerV1.Open)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/customer/deposits")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"product_id": "product", "amount": 500000}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.JSONEq(
+		t,
+		`{"error": {"status": 409, "message": "customer is not verified"}}`,
+		string(response.Body()),
+	)
+}
+
+func TestOpenDeposit_InsufficientFunds(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().
+		FindByID("customer").
+		Return(&domain.Customer{GeneratedID: "customer", Status: domain.CustomerStatusActive}, nil)
+	repositoryMock := mocks.NewMockDepositRepository(ctrl)
+	repositoryMock.EXPECT().FindProductByID("product").Return(testDepositProduct, nil)
+	repositoryMock.EXPECT().Create(gomock.Any(), gomock.Any()).Return(false, nil)
+
+	useCase := usecase.NewDepositUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		newVerificationUseCase(ctrl, approvedVerification),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewDepositHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/deposits", handlerV1.Open)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/customer/deposits")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"product_id": "product", "amount": 500000}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.Contains(t, string(response.Body()), "insufficient funds on customer balance")
+}
@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"path"
+	"unicode/utf8"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	// maxDisputeDescriptionLength is a length limit of dispute description
+	maxDisputeDescriptionLength = 500
+	// maxEvidenceFileNameLength is a length limit of evidence file name
+	maxEvidenceFileNameLength = 255
+	// defaultEvidenceContentType is saved for evidence uploaded without Content-Type
+	defaultEvidenceContentType = "application/octet-stream"
+)
+
+func disputeFromRequest(payerID string, request *DisputeRequestBody) (*domain.Dispute, error) {
+	if request.PaymentID == "" {
+		return nil, domain.NewValidationError("payment_id is mandatory field")
+	}
+	reasonCode := domain.DisputeReasonCode(request.ReasonCode)
+	if !reasonCode.IsKnown() {
+		return nil, domain.NewValidationError(
+			"reason_code should be one of fraudulent, product_not_received, product_unacceptable, " +
+				"duplicate, credit_not_processed, general",
+		)
+	}
+	if utf8.RuneCountInString(request.Description) > maxDisputeDescriptionLength {
+		return nil, domain.NewValidationError("description should be 500 characters at most")
+	}
+	if request.Amount < 0 {
+		return nil, domain.NewValidationError("amount should be positive")
+	}
+
+	return &domain.Dispute{
+		PaymentID:   request.PaymentID,
+		PayerID:     payerID,
+		Amount:      request.Amount,
+		ReasonCode:  reasonCode,
+		Description: request.Description,
+	}, nil
+}
+
+// disputeEvidenceFromRequest reads uploader and file name from query, only base name of file is kept
+func disputeEvidenceFromRequest(
+	disputeID string,
+	args *fasthttp.Args,
+	contentType []byte,
+) (*domain.DisputeEvidence, error) {
+	customerID := string(args.Peek("customer_id"))
+	if customerID == "" {
+		return nil, domain.NewValidationError("customer_id is mandatory field")
+	}
+	fileName := path.Base(string(args.Peek("file_name")))
+	if fileName == "." || fileName == "/" {
+		return nil, domain.NewValidationError("file_name is mandatory field")
+	}
+	if utf8.RuneCountInString(fileName) > maxEvidenceFileNameLength {
+		return nil, domain.NewValidationError("file_name should be 255 characters at most")
+	}
+
+	evidence := &domain.DisputeEvidence{
+		DisputeID:   disputeID,
+		CustomerID:  customerID,
+		FileName:    fileName,
+		ContentType: string(contentType),
+	}
+	if evidence.ContentType == "" {
+		evidence.ContentType = defaultEvidenceContentType
+	}
+	return evidence, nil
+}
+
+func responseFromDisputes(disputes []*domain.Dispute) *DisputesBody {
+	response := &DisputesBody{Disputes: make([]*DisputeBody, 0, len(disputes))}
+	for _, dispute := range disputes {
+		response.Disputes = append(response.Disputes, responseFromDispute(dispute))
+	}
+	return response
+}
+
+func responseFromDispute(dispute *domain.Dispute) *DisputeBody {
+	response := &DisputeBody{
+		DisputeID:   dispute.GeneratedID,
+		PaymentID:   dispute.PaymentID,
+		MerchantID:  dispute.MerchantID,
+		PayerID:     dispute.PayerID,
+		Amount:      dispute.Amount,
+		Currency:    dispute.Currency,
+		ReasonCode:  string(dispute.ReasonCode),
+		Description: dispute.Description,
+		Status:      string(dispute.Status),
+		RespondBy:   dispute.RespondBy.Format(domain.DateTimeFormat),
+		CreatedAt:   dispute.CreatedAt.Format(domain.DateTimeFormat),
+	}
+	if !dispute.ResolvedAt.IsZero() {
+		response.ResolvedAt = dispute.ResolvedAt.Format(domain.DateTimeFormat)
+	}
+	return response
+}
+
+func responseFromDisputeEvidence(evidence *domain.DisputeEvidence) *DisputeEvidenceBody {
+	return &DisputeEvidenceBody{
+		EvidenceID:  evidence.GeneratedID,
+		DisputeID:   evidence.DisputeID,
+		CustomerID:  evidence.CustomerID,
+		FileName:    evidence.FileName,
+		ContentType: evidence.ContentType,
+		Size:        evidence.Size,
+		CreatedAt:   evidence.CreatedAt.Format(domain.DateTimeFormat),
+	}
+}
@@ -0,0 +1,367 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const (
+	DisputeIdUrlPath         = "id"
+	DisputeEvidenceIdUrlPath = "evidence_id"
+)
+
+type DisputeHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.DisputeUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewDisputeHandlerV1(
+	logger *zap.Logger,
+	disputeService *usecase.DisputeUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *DisputeHandlerV1 {
+	return &DisputeHandlerV1{logger: logger, useCase: disputeService, responseWriter: responseWriter}
+}
+
+// swagger:parameters OpenDispute
+type DisputeRequestBody struct {
+	// QR payment of customer
+	// in:body
+	PaymentID string `json:"payment_id"`
+	// one of fraudulent, product_not_received, product_unacceptable, duplicate, credit_not_processed, general
+	// in:body
+	ReasonCode string `json:"reason_code"`
+	// in:body
+	Description string `json:"description"`
+	// disputed amount in minor currency units, the whole payment by default
+	// in:body
+	Amount int64 `json:"amount"`
+}
+
+// swagger:parameters SubmitDispute AcceptDispute
+type DisputeActionRequestBody struct {
+	// merchant of disputed payment
+	// in:body
+	CustomerID string `json:"customer_id"`
+}
+
+// swagger:parameters ResolveDispute
+type DisputeResolutionRequestBody struct {
+	// won keeps payment with merchant, lost returns it to payer
+	// in:body
+	Outcome string `json:"outcome"`
+}
+
+// swagger:parameters AddDisputeEvidence
+type DisputeEvidenceQuery struct {
+	// payer or merchant who uploads file
+	// in:query
+	CustomerID string `json:"customer_id"`
+	// in:query
+	FileName string `json:"file_name"`
+}
+
+type DisputesBody struct {
+	Disputes []*DisputeBody `json:"disputes"`
+}
+
+type DisputeBody struct {
+	DisputeID   string `json:"dispute_id"`
+	PaymentID   string `json:"payment_id"`
+	MerchantID  string `json:"merchant_id"`
+	PayerID     string `json:"payer_id"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	ReasonCode  string `json:"reason_code"`
+	Description string `json:"description,omitempty"`
+	Status      string `json:"status"`
+	RespondBy   string `json:"respond_by"`
+	ResolvedAt  string `json:"resolved_at,omitempty"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type DisputeEvidenceListBody struct {
+	Evidence []*DisputeEvidenceBody `json:"evidence"`
+}
+
+type DisputeEvidenceBody struct {
+	EvidenceID  string `json:"evidence_id"`
+	DisputeID   string `json:"dispute_id"`
+	CustomerID  string `json:"customer_id"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int    `json:"size"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// swagger:route POST /customer/{id}/disputes disputes OpenDispute
+// Opens dispute of customer against QR payment within 120 days after payment.
+// Disputed amount is provisionally debited from merchant, who has 7 days to respond.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *DisputeHandlerV1) Open(ctx *fasthttp.RequestCtx) {
+	payerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := payerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &DisputeRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	dispute, err := disputeFromRequest(payerID.(string), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Open(dispute)
+	if err != nil {
+		h.writeDisputeError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromDispute(dispute))
+}
+
+// swagger:route GET /customer/{id}/disputes disputes FindMerchantDisputes
+// Lists disputes against payments to merchant, the latest first.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *DisputeHandlerV1) FindByMerchant(ctx *fasthttp.RequestCtx) {
+	merchantID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := merchantID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	disputes, err := h.useCase.FindByMerchant(merchantID.(string))
+	if err != nil {
+		h.writeDisputeError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromDisputes(disputes))
+}
+
+// swagger:route GET /disputes/{id} disputes FindDispute
+// Shows dispute.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *DisputeHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	disputeID := ctx.UserValue(DisputeIdUrlPath)
+	if _, ok := disputeID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	dispute, err := h.useCase.Find(disputeID.(string))
+	if err != nil {
+		h.writeDisputeError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromDispute(dispute))
+}
+
+// swagger:route POST /disputes/{id}/evidence disputes AddDisputeEvidence
+// Uploads evidence file sent as request body with its Content-Type, 4 MB at most.
+// Evidence is accepted until dispute is resolved, merchant uploads it before response deadline.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *DisputeHandlerV1) AddEvidence(ctx *fasthttp.RequestCtx) {
+	disputeID := ctx.UserValue(DisputeIdUrlPath)
+	if _, ok := disputeID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	evidence, err := disputeEvidenceFromRequest(disputeID.(string), ctx.QueryArgs(), ctx.Request.Header.ContentType())
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.AddEvidence(evidence, ctx.PostBody())
+	if err != nil {
+		h.writeDisputeError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromDisputeEvidence(evidence))
+}
+
+// swagger:route GET /disputes/{id}/evidence disputes FindDisputeEvidence
+// Lists evidence files of dispute in order of upload.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *DisputeHandlerV1) FindEvidence(ctx *fasthttp.RequestCtx) {
+	disputeID := ctx.UserValue(DisputeIdUrlPath)
+	if _, ok := disputeID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	evidence, err := h.useCase.FindEvidence(disputeID.(string))
+	if err != nil {
+		h.writeDisputeError(ctx, err)
+		return
+	}
+	response := &DisputeEvidenceListBody{Evidence: make([]*DisputeEvidenceBody, 0, len(evidence))}
+	for _, file := range evidence {
+		response.Evidence = append(response.Evidence, responseFromDisputeEvidence(file))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route GET /disputes/{id}/evidence/{evidence_id} disputes DownloadDisputeEvidence
+// Downloads evidence file.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *DisputeHandlerV1) DownloadEvidence(ctx *fasthttp.RequestCtx) {
+	disputeID := ctx.UserValue(DisputeIdUrlPath)
+	evidenceID := ctx.UserValue(DisputeEvidenceIdUrlPath)
+	_, disputeOk := disputeID.(string)
+	_, evidenceOk := evidenceID.(string)
+	if !disputeOk || !evidenceOk {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	evidence, content, err := h.useCase.DownloadEvidence(disputeID.(string), evidenceID.(string))
+	if err != nil {
+		h.writeDisputeError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessFile(ctx, evidence.ContentType, evidence.FileName, content)
+}
+
+// swagger:route POST /disputes/{id}/submit disputes SubmitDispute
+// Sends evidence of merchant for review, at least one file of merchant should be uploaded.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *DisputeHandlerV1) Submit(ctx *fasthttp.RequestCtx) {
+	h.action(ctx, func(disputeID string, request *DisputeActionRequestBody) (*domain.Dispute, error) {
+		return h.useCase.Submit(disputeID, request.CustomerID)
+	})
+}
+
+// swagger:route POST /disputes/{id}/accept disputes AcceptDispute
+// Merchant accepts open dispute, disputed amount is credited to payer.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *DisputeHandlerV1) Accept(ctx *fasthttp.RequestCtx) {
+	h.action(ctx, func(disputeID string, request *DisputeActionRequestBody) (*domain.Dispute, error) {
+		return h.useCase.Accept(disputeID, request.CustomerID)
+	})
+}
+
+// swagger:route POST /disputes/{id}/resolve disputes ResolveDispute
+// Records outcome of dispute review. Won amount is credited back to merchant, lost amount is credited to payer.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *DisputeHandlerV1) Resolve(ctx *fasthttp.RequestCtx) {
+	disputeID := ctx.UserValue(DisputeIdUrlPath)
+	if _, ok := disputeID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &DisputeResolutionRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	dispute, err := h.useCase.Resolve(disputeID.(string), domain.DisputeStatus(request.Outcome))
+	if err != nil {
+		h.writeDisputeError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromDispute(dispute))
+}
+
+func (h *DisputeHandlerV1) action(
+	ctx *fasthttp.RequestCtx,
+	action func(disputeID string, request *DisputeActionRequestBody) (*domain.Dispute, error),
+) {
+	disputeID := ctx.UserValue(DisputeIdUrlPath)
+	if _, ok := disputeID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &DisputeActionRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	if request.CustomerID == "" {
+		h.responseWriter.WriteError(ctx, "customer_id is mandatory field", fasthttp.StatusBadRequest)
+		return
+	}
+
+	dispute, err := action(disputeID.(string), request)
+	if err != nil {
+		h.writeDisputeError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromDispute(dispute))
+}
+
+func (h *DisputeHandlerV1) writeDisputeError(ctx *fasthttp.RequestCtx, err error) {
+	switch err.(type) {
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process dispute. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
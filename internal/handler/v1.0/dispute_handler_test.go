@@ -0,0 +1,274 @@
+package v1
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/blob"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestOpenDispute_Success(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	paymentRepositoryMock := mocks.NewMockQRPaymentRepository(ctrl)
+	paymentRepositoryMock.EXPECT().FindPaymentByID("payment").Return(&domain.QRPayment{
+		GeneratedID: "payment",
+		RequestID:   "request",
+		MerchantID:  "merchant",
+		PayerID:     "payer",
+		Amount:      10000,
+		Currency:    "RUB",
+		PaidAt:      time.Now().Add(-24 * time.Hour),
+	}, nil)
+	postingRepositoryMock := mocks.NewMockPostingRepository(ctrl)
+	postingRepositoryMock.EXPECT().FindByReference("qr_payment:payment").Return([]*domain.Posting{
+		{CustomerID: "payer", Amount: -10000, Currency: "RUB", Reference: "qr_payment:payment"},
+		{CustomerID: "merchant", Amount: 10000, Currency: "RUB", Reference: "qr_payment:payment"},
+	}, nil)
+	repositoryMock := mocks.NewMockDisputeRepository(ctrl)
+	repositoryMock.EXPECT().FindByPaymentID("payment").Return(nil, nil)
+	repositoryMock.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(dispute *domain.Dispute, postings []*domain.Posting) error {
+			if assert.Len(t, postings, 2) {
+				assert.Equal(t, "merchant", postings[0].CustomerID)
+				assert.Equal(t, int64(-10000), postings[0].Amount)
+				assert.Equal(t, domain.LedgerAccountDisputes, postings[1].CustomerID)
+				assert.Equal(t, int64(10000), postings[1].Amount)
+				assert.Equal(t, "dispute:"+dispute.GeneratedID, postings[1].Reference)
+			}
+			return nil
+		})
+
+	useCase := usecase.NewDisputeUseCase(repositoryMock, paymentRepositoryMock, postingRepositoryMock, nil)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewDisputeHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/disputes", handlerV1.Open)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/payer/disputes")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"payment_id": "payment", "reason_code": "product_not_received"}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	body := &DisputeBody{}
+	err := json.Unmarshal(response.Body(), body)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, "merchant", body.MerchantID)
+	assert.Equal(t, int64(10000), body.Amount)
+	assert.Equal(t, "RUB", body.Currency)
+	assert.Equal(t, "open", body.Status)
+}
+
+func TestOpenDispute_UnpostedPayment(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	paymentRepositoryMock := mocks.NewMockQRPaymentRepository(ctrl)
+	paymentRepositoryMock.EXPECT().FindPaymentByID("payment").Return(&domain.QRPayment{
+		GeneratedID: "payment",
+		RequestID:   "request",
+		MerchantID:  "merchant",
+		PayerID:     "payer",
+		Amount:      10000,
+		Currency:    "RUB",
+		PaidAt:      time.Now().Add(-24 * time.Hour),
+	}, nil)
+	postingRepositoryMock := mocks.NewMockPostingRepository(ctrl)
+	postingRepositoryMock.EXPECT().FindByReference("qr_payment:payment").Return(nil, nil)
+
+	useCase := usecase.NewDisputeUseCase(
+		mocks.NewMockDisputeRepository(ctrl),
+		paymentRepositoryMock,
+		postingRepositoryMock,
+		nil,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewDisputeHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/disputes", handlerV1.Open)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/payer/disputes")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"payment_id": "payment", "reason_code": "product_not_received"}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.Contains(t, string(response.Body()), "payment has no ledger postings to dispute")
+}
+
+func TestAddDisputeEvidence_Download(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dir, err := ioutil.TempDir("", "dispute")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	store, err := blob.NewFileSystemStore(dir)
+	assert.NoError(t, err)
+
+	var evidence []*domain.DisputeEvidence
+	repositoryMock := mocks.NewMockDisputeRepository(ctrl)
+	repositoryMock.EXPECT().FindByID("dispute").Times(2).Return(&domain.Dispute{
+		GeneratedID: "dispute",
+		MerchantID:  "merchant",
+		PayerID:     "payer",
+		Status:      domain.DisputeStatusOpen,
+		RespondBy:   time.Now().Add(time.Hour),
+	}, nil)
+	repositoryMock.EXPECT().AddEvidence(gomock.Any()).DoAndReturn(func(file *domain.DisputeEvidence) error {
+		evidence = append(evidence, file)
+		return nil
+	})
+	repositoryMock.EXPECT().FindEvidence("dispute").DoAndReturn(func(string) ([]*domain.DisputeEvidence, error) {
+		return evidence, nil
+	})
+
+	useCase := usecase.NewDisputeUseCase(
+		repositoryMock,
+		mocks.NewMockQRPaymentRepository(ctrl),
+		mocks.NewMockPostingRepository(ctrl),
+		store,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewDisputeHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/disputes/:id/evidence", handlerV1.AddEvidence)
+	router.GET("/disputes/:id/evidence/:evidence_id", handlerV1.DownloadEvidence)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/disputes/dispute/evidence?customer_id=merchant&file_name=receipts/delivery.pdf")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.Header.SetContentType("application/pdf")
+	request.SetBodyString("%PDF-1.4 delivery receipt")
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+	uploadStatus := response.Header.StatusCode()
+	uploaded := &DisputeEvidenceBody{}
+	err = json.Unmarshal(response.Body(), uploaded)
+	if err != nil {
+		t.Error(err)
+	}
+
+	request.Reset()
+	response.Reset()
+	request.SetRequestURI("/disputes/dispute/evidence/" + uploaded.EvidenceID)
+	request.Header.SetMethod(fasthttp.MethodGet)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, uploadStatus)
+	assert.Equal(t, "delivery.pdf", uploaded.FileName)
+	assert.Equal(t, 25, uploaded.Size)
+	assert.Equal(t, fasthttp.StatusOK, response.Header.StatusCode())
+	assert.Equal(t, "application/pdf", string(response.Header.ContentType()))
+	assert.Equal(t, `attachment; filename="delivery.pdf"`, string(response.Header.Peek("Content-Disposition")))
+	assert.Equal(t, "%PDF-1.4 delivery receipt", string(response.Body()))
+}
@@ -0,0 +1,95 @@
+package v1
+
+import (
+	"net"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func escrowFromRequest(merchantID string, request *EscrowRequestBody) (*domain.Escrow, error) {
+	if request.OrderID == "" {
+		return nil, domain.NewValidationError("order_id is mandatory field")
+	}
+	if request.BuyerID == "" || request.SellerID == "" {
+		return nil, domain.NewValidationError("buyer_id and seller_id are mandatory fields")
+	}
+	if request.BuyerID == request.SellerID {
+		return nil, domain.NewValidationError("buyer_id and seller_id should be different customers")
+	}
+	if request.Amount <= 0 {
+		return nil, domain.NewValidationError("amount should be positive")
+	}
+	if !currencyRegexp.MatchString(request.Currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	if request.IP != "" && net.ParseIP(request.IP) == nil {
+		return nil, domain.NewValidationError("ip is not valid IP address")
+	}
+
+	releaseCondition := domain.EscrowReleaseCondition(request.ReleaseCondition)
+	switch releaseCondition {
+	case "":
+		releaseCondition = domain.EscrowReleaseConditionBuyerConfirmation
+	case domain.EscrowReleaseConditionBuyerConfirmation, domain.EscrowReleaseConditionMerchantConfirmation:
+	default:
+		return nil, domain.NewValidationError(
+			"release_condition should be one of buyer_confirmation, merchant_confirmation",
+		)
+	}
+	expiryAction := domain.EscrowExpiryAction(request.ExpiryAction)
+	switch expiryAction {
+	case "":
+		expiryAction = domain.EscrowExpiryActionRelease
+	case domain.EscrowExpiryActionRelease, domain.EscrowExpiryActionRefund:
+	default:
+		return nil, domain.NewValidationError("expiry_action should be one of release, refund")
+	}
+
+	escrow := &domain.Escrow{
+		MerchantID:       merchantID,
+		OrderID:          request.OrderID,
+		BuyerID:          request.BuyerID,
+		SellerID:         request.SellerID,
+		Amount:           request.Amount,
+		Currency:         request.Currency,
+		ReleaseCondition: releaseCondition,
+		ExpiryAction:     expiryAction,
+		DeviceID:         request.DeviceID,
+		IP:               request.IP,
+	}
+	if request.ExpiresAt != "" {
+		var err error
+		escrow.ExpiresAt, err = time.Parse(domain.DateTimeFormat, request.ExpiresAt)
+		if err != nil {
+			return nil, domain.NewValidationError("wrong expires_at format")
+		}
+	}
+	return escrow, nil
+}
+
+func responseFromEscrow(escrow *domain.Escrow) *EscrowBody {
+	response := &EscrowBody{
+		EscrowID:         escrow.GeneratedID,
+		MerchantID:       escrow.MerchantID,
+		OrderID:          escrow.OrderID,
+		BuyerID:          escrow.BuyerID,
+		SellerID:         escrow.SellerID,
+		Amount:           escrow.Amount,
+		Currency:         escrow.Currency,
+		ReleaseCondition: string(escrow.ReleaseCondition),
+		ExpiryAction:     string(escrow.ExpiryAction),
+		Status:           string(escrow.Status),
+		DisputeReason:    escrow.DisputeReason,
+		RiskAssessmentID: escrow.RiskAssessmentID,
+		RiskDecision:     string(escrow.RiskDecision),
+		ExpiresAt:        escrow.ExpiresAt.Format(domain.DateTimeFormat),
+		CreatedAt:        escrow.CreatedAt.Format(domain.DateTimeFormat),
+	}
+	if !escrow.SettledAt.IsZero() {
+		response.SellerAmount = escrow.SellerAmount
+		response.BuyerAmount = escrow.Amount - escrow.SellerAmount
+		response.SettledAt = escrow.SettledAt.Format(domain.DateTimeFormat)
+	}
+	return response
+}
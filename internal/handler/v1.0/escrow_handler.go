@@ -0,0 +1,287 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const EscrowIdUrlPath = "id"
+
+type EscrowHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.EscrowUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewEscrowHandlerV1(
+	logger *zap.Logger,
+	escrowService *usecase.EscrowUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *EscrowHandlerV1 {
+	return &EscrowHandlerV1{logger: logger, useCase: escrowService, responseWriter: responseWriter}
+}
+
+// swagger:parameters CreateEscrow
+type EscrowRequestBody struct {
+	// order of merchant, order could have one escrow only
+	// in:body
+	OrderID string `json:"order_id"`
+	// in:body
+	BuyerID string `json:"buyer_id"`
+	// in:body
+	SellerID string `json:"seller_id"`
+	// amount in minor currency units
+	// in:body
+	Amount int64 `json:"amount"`
+	// in:body
+	Currency string `json:"currency"`
+	// buyer_confirmation by default or merchant_confirmation
+	// in:body
+	ReleaseCondition string `json:"release_condition"`
+	// release by default or refund
+	// in:body
+	ExpiryAction string `json:"expiry_action"`
+	// format: 02-01-2006 15:04:05 in UTC, escrow expires in 14 days by default
+	// in:body
+	ExpiresAt string `json:"expires_at"`
+	// device of buyer scored by risk assessment
+	// in:body
+	DeviceID string `json:"device_id"`
+	// IP address of buyer scored by risk assessment
+	// in:body
+	IP string `json:"ip"`
+}
+
+// swagger:parameters ReleaseEscrow RefundEscrow DisputeEscrow ResolveEscrow
+type EscrowActionRequestBody struct {
+	// customer who takes action: buyer, seller or merchant
+	// in:body
+	CustomerID string `json:"customer_id"`
+	// reason of dispute
+	// in:body
+	Reason string `json:"reason"`
+	// amount paid to seller on dispute resolution, the rest is refunded to buyer
+	// in:body
+	SellerAmount int64 `json:"seller_amount"`
+}
+
+type EscrowsBody struct {
+	Escrows []*EscrowBody `json:"escrows"`
+}
+
+type EscrowBody struct {
+	EscrowID         string `json:"escrow_id"`
+	MerchantID       string `json:"merchant_id"`
+	OrderID          string `json:"order_id"`
+	BuyerID          string `json:"buyer_id"`
+	SellerID         string `json:"seller_id"`
+	Amount           int64  `json:"amount"`
+	Currency         string `json:"currency"`
+	ReleaseCondition string `json:"release_condition"`
+	ExpiryAction     string `json:"expiry_action"`
+	Status           string `json:"status"`
+	SellerAmount     int64  `json:"seller_amount"`
+	BuyerAmount      int64  `json:"buyer_amount"`
+	DisputeReason    string `json:"dispute_reason,omitempty"`
+	RiskAssessmentID string `json:"risk_assessment_id,omitempty"`
+	// risk decision, approve or review
+	RiskDecision string `json:"risk_decision,omitempty"`
+	ExpiresAt    string `json:"expires_at"`
+	SettledAt    string `json:"settled_at,omitempty"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// swagger:route POST /customer/{id}/escrows escrows CreateEscrow
+// Holds funds of buyer for order of marketplace merchant until they are released to seller,
+// refunded to buyer or split between them.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  422: ErrorResponse
+//  500: ErrorResponse
+func (h *EscrowHandlerV1) Create(ctx *fasthttp.RequestCtx) {
+	merchantID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := merchantID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &EscrowRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	escrow, err := escrowFromRequest(merchantID.(string), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Create(escrow)
+	if err != nil {
+		h.writeEscrowError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromEscrow(escrow))
+}
+
+// swagger:route GET /customer/{id}/escrows escrows FindMerchantEscrows
+// Lists escrows of merchant, the latest first.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *EscrowHandlerV1) FindByMerchant(ctx *fasthttp.RequestCtx) {
+	merchantID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := merchantID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	escrows, err := h.useCase.FindByMerchant(merchantID.(string))
+	if err != nil {
+		h.writeEscrowError(ctx, err)
+		return
+	}
+	response := &EscrowsBody{Escrows: make([]*EscrowBody, 0, len(escrows))}
+	for _, escrow := range escrows {
+		response.Escrows = append(response.Escrows, responseFromEscrow(escrow))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route GET /escrows/{id} escrows FindEscrow
+// Shows escrow.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *EscrowHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	escrowID := ctx.UserValue(EscrowIdUrlPath)
+	if _, ok := escrowID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	escrow, err := h.useCase.Find(escrowID.(string))
+	if err != nil {
+		h.writeEscrowError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromEscrow(escrow))
+}
+
+// swagger:route POST /escrows/{id}/release escrows ReleaseEscrow
+// Releases held escrow to seller. Escrow is released by buyer or by merchant depending on release condition.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *EscrowHandlerV1) Release(ctx *fasthttp.RequestCtx) {
+	h.action(ctx, func(escrowID string, request *EscrowActionRequestBody) (*domain.Escrow, error) {
+		return h.useCase.Release(escrowID, request.CustomerID)
+	})
+}
+
+// swagger:route POST /escrows/{id}/refund escrows RefundEscrow
+// Refunds held escrow to buyer on request of seller or merchant.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *EscrowHandlerV1) Refund(ctx *fasthttp.RequestCtx) {
+	h.action(ctx, func(escrowID string, request *EscrowActionRequestBody) (*domain.Escrow, error) {
+		return h.useCase.Refund(escrowID, request.CustomerID)
+	})
+}
+
+// swagger:route POST /escrows/{id}/dispute escrows DisputeEscrow
+// Opens dispute of buyer, disputed escrow does not expire until merchant resolves it.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *EscrowHandlerV1) Dispute(ctx *fasthttp.RequestCtx) {
+	h.action(ctx, func(escrowID string, request *EscrowActionRequestBody) (*domain.Escrow, error) {
+		return h.useCase.Dispute(escrowID, request.CustomerID, request.Reason)
+	})
+}
+
+// swagger:route POST /escrows/{id}/resolve escrows ResolveEscrow
+// Settles held or disputed escrow by merchant, seller amount is paid to seller and the rest is refunded to buyer.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *EscrowHandlerV1) Resolve(ctx *fasthttp.RequestCtx) {
+	h.action(ctx, func(escrowID string, request *EscrowActionRequestBody) (*domain.Escrow, error) {
+		return h.useCase.Resolve(escrowID, request.CustomerID, request.SellerAmount)
+	})
+}
+
+func (h *EscrowHandlerV1) action(
+	ctx *fasthttp.RequestCtx,
+	action func(escrowID string, request *EscrowActionRequestBody) (*domain.Escrow, error),
+) {
+	escrowID := ctx.UserValue(EscrowIdUrlPath)
+	if _, ok := escrowID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &EscrowActionRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	if request.CustomerID == "" {
+		h.responseWriter.WriteError(ctx, "customer_id is mandatory field", fasthttp.StatusBadRequest)
+		return
+	}
+
+	escrow, err := action(escrowID.(string), request)
+	if err != nil {
+		h.writeEscrowError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromEscrow(escrow))
+}
+
+func (h *EscrowHandlerV1) writeEscrowError(ctx *fasthttp.RequestCtx, err error) {
+	switch typedErr := err.(type) {
+	case *domain.LimitExceededError:
+		writeLimitExceeded(h.responseWriter, ctx, typedErr)
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process escrow. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,266 @@
+package v1
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/risk"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func disputedEscrow() *domain.Escrow {
+	return &domain.Escrow{
+		GeneratedID:      "escrow",
+		MerchantID:       "merchant",
+		OrderID:          "order_15",
+		BuyerID:          "buyer",
+		SellerID:         "seller",
+		Amount:           10000,
+		Currency:         "RUB",
+		ReleaseCondition: domain.EscrowReleaseConditionBuyerConfirmation,
+		ExpiryAction:     domain.EscrowExpiryActionRelease,
+		Status:           domain.EscrowStatusDisputed,
+		DisputeReason:    "item damaged",
+		ExpiresAt:        time.Date(2020, 8, 18, 10, 0, 0, 0, time.UTC),
+		CreatedAt:        time.Date(2020, 8, 4, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestResolveEscrow_Split(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repositoryMock := mocks.NewMockEscrowRepository(ctrl)
+	repositoryMock.EXPECT().
+		Update("escrow", gomock.Any()).
+		DoAndReturn(func(
+			_ string,
+			update func(escrow *domain.Escrow) ([]*domain.Debit, error),
+		) (*domain.Escrow, error) {
+			escrow := disputedEscrow()
+			debits, err := update(escrow)
+			if err != nil {
+				return nil, err
+			}
+			assert.Len(t, debits, 2)
+			escrow.SettledAt = time.Date(2020, 8, 10, 10, 0, 0, 0, time.UTC)
+			return escrow, nil
+		})
+
+	useCase := usecase.NewEscrowUseCase(
+		repositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewEscrowHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/escrows/:id/resolve", handlerV1.Resolve)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/escrows/escrow/resolve")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"customer_id": "merchant", "seller_amount": 7000}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusOK, response.Header.StatusCode())
+	assert.JSONEq(t, `{
+		"escrow_id": "escrow",
+		"merchant_id": "merchant",
+		"order_id": "order_15",
+		"buyer_id": "buyer",
+		"seller_id": "seller",
+		"amount": 10000,
+		"currency": "RUB",
+		"release_condition": "buyer_confirmation",
+		"expiry_action": "release",
+		"status": "split",
+		"seller_amount": 7000,
+		"buyer_amount": 3000,
+		"dispute_reason": "item damaged",
+		"expires_at": "18-08-2020 10:00:00",
+		"settled_at": "10-08-2020 10:00:00",
+		"created_at": "04-08-2020 10:00:00"
+	}`, string(response.Body()))
+}
+
+func TestReleaseEscrow_NotConfirmingParty(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repositoryMock := mocks.NewMockEscrowRepository(ctrl)
+	repositoryMock.EXPECT().
+		Update("escrow", gomock.Any()).
+		DoAndReturn(func(
+			_ string,
+			update func(escrow *domain.Escrow) ([]*domain.Debit, error),
+		) (*domain.Escrow, error) {
+			_, err := update(disputedEscrow())
+			return nil, err
+		})
+
+	useCase := usecase.NewEscrowUseCase(
+		repositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewEscrowHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/escrows/:id/release", handlerV1.Release)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/escrows/escrow/release")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"customer_id": "seller"}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.JSONEq(
+		t,
+		`{"error": {"status": 409, "message": "escrow is released on buyer_confirmation"}}`,
+		string(response.Body()),
+	)
+}
+
+func TestCreateEscrow_DeclinedByRisk(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().
+		FindByID(gomock.Any()).
+		DoAndReturn(func(customerID string) (*domain.Customer, error) {
+			return &domain.Customer{GeneratedID: customerID, Status: domain.CustomerStatusActive}, nil
+		}).
+		Times(3)
+	repositoryMock := mocks.NewMockEscrowRepository(ctrl)
+	repositoryMock.EXPECT().FindByOrderID("merchant", "order_15").Return(nil, nil)
+
+	useCase := usecase.NewEscrowUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.Thresholds{Review: 0, Decline: 0}),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewEscrowHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/escrows", handlerV1.Create)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/merchant/escrows")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{
+		"order_id": "order_15",
+		"buyer_id": "buyer",
+		"seller_id": "seller",
+		"amount": 10000,
+		"currency": "RUB",
+		"device_id": "device",
+		"ip": "10.0.0.1"
+	}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.Contains(t, string(response.Body()), "payment is declined by risk assessment")
+}
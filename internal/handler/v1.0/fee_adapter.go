@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func operationFromRequest(operation string) (domain.MovementType, error) {
+	switch movementType := domain.MovementType(operation); movementType {
+	case domain.MovementTypeDeposit,
+		domain.MovementTypeWithdrawal,
+		domain.MovementTypeTransfer,
+		domain.MovementTypeConversion:
+		return movementType, nil
+	default:
+		return "", domain.NewValidationError("operation should be one of deposit, withdrawal, transfer, conversion")
+	}
+}
+
+func validateFeeQuoteRequest(request *FeeQuoteRequestBody) (domain.MovementType, error) {
+	operation, err := operationFromRequest(request.Operation)
+	if err != nil {
+		return "", err
+	}
+	if request.Amount <= 0 {
+		return "", domain.NewValidationError("amount should be positive")
+	}
+	if !currencyRegexp.MatchString(request.Currency) {
+		return "", domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	if request.ScheduleVersion < 0 {
+		return "", domain.NewValidationError("schedule_version should be positive")
+	}
+	return operation, nil
+}
+
+func feeScheduleFromRequest(request *FeeScheduleBody) (time.Time, []domain.FeeRule, error) {
+	var effectiveFrom time.Time
+	if request.EffectiveFrom != "" {
+		var err error
+		effectiveFrom, err = time.Parse(domain.DateTimeFormat, request.EffectiveFrom)
+		if err != nil {
+			return time.Time{}, nil, domain.NewValidationError("wrong effective_from format")
+		}
+	}
+	if len(request.Rules) == 0 {
+		return time.Time{}, nil, domain.NewValidationError("rules are mandatory")
+	}
+
+	rules := make([]domain.FeeRule, 0, len(request.Rules))
+	for _, ruleBody := range request.Rules {
+		operation, err := operationFromRequest(ruleBody.Operation)
+		if err != nil {
+			return time.Time{}, nil, err
+		}
+		if ruleBody.Currency != "" && !currencyRegexp.MatchString(ruleBody.Currency) {
+			return time.Time{}, nil, domain.NewValidationError("currency should be ISO 4217 code")
+		}
+		rule := domain.FeeRule{
+			Operation:   operation,
+			Currency:    ruleBody.Currency,
+			Fixed:       ruleBody.Fixed,
+			BasisPoints: ruleBody.BasisPoints,
+			Min:         ruleBody.Min,
+			Max:         ruleBody.Max,
+		}
+		for _, tier := range ruleBody.Tiers {
+			rule.Tiers = append(rule.Tiers, domain.FeeTier{
+				From:        tier.From,
+				Fixed:       tier.Fixed,
+				BasisPoints: tier.BasisPoints,
+			})
+		}
+		rules = append(rules, rule)
+	}
+	return effectiveFrom, rules, nil
+}
+
+func responseFromFeeSchedule(schedule *domain.FeeSchedule) *FeeScheduleBody {
+	response := &FeeScheduleBody{
+		Version:       schedule.Version,
+		EffectiveFrom: schedule.EffectiveFrom.Format(domain.DateTimeFormat),
+		Rules:         make([]FeeRuleBody, 0, len(schedule.Rules)),
+	}
+	for _, rule := range schedule.Rules {
+		ruleBody := FeeRuleBody{
+			Operation:   string(rule.Operation),
+			Currency:    rule.Currency,
+			Fixed:       rule.Fixed,
+			BasisPoints: rule.BasisPoints,
+			Min:         rule.Min,
+			Max:         rule.Max,
+		}
+		for _, tier := range rule.Tiers {
+			ruleBody.Tiers = append(ruleBody.Tiers, FeeTierBody{
+				From:        tier.From,
+				Fixed:       tier.Fixed,
+				BasisPoints: tier.BasisPoints,
+			})
+		}
+		response.Rules = append(response.Rules, ruleBody)
+	}
+	return response
+}
+
+func responseFromFeeQuote(quote *domain.FeeQuote) *FeeQuoteBody {
+	return &FeeQuoteBody{
+		ScheduleVersion: quote.ScheduleVersion,
+		Operation:       string(quote.Operation),
+		Currency:        quote.Currency,
+		Amount:          quote.Amount,
+		Fee:             quote.Fee,
+		Total:           quote.Amount + quote.Fee,
+	}
+}
@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const FeeScheduleVersionUrlPath = "version"
+
+type FeeHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.FeeUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewFeeHandlerV1(
+	logger *zap.Logger,
+	feeService *usecase.FeeUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *FeeHandlerV1 {
+	return &FeeHandlerV1{logger: logger, useCase: feeService, responseWriter: responseWriter}
+}
+
+// swagger:parameters CreateFeeSchedule
+type FeeScheduleBody struct {
+	Version int `json:"version"`
+	// in:body
+	EffectiveFrom string `json:"effective_from"`
+	// in:body
+	Rules []FeeRuleBody `json:"rules"`
+}
+
+type FeeRuleBody struct {
+	Operation   string        `json:"operation"`
+	Currency    string        `json:"currency"`
+	Fixed       int64         `json:"fixed"`
+	BasisPoints int64         `json:"basis_points"`
+	Tiers       []FeeTierBody `json:"tiers,omitempty"`
+	Min         int64         `json:"min"`
+	Max         int64         `json:"max"`
+}
+
+type FeeTierBody struct {
+	From        int64 `json:"from"`
+	Fixed       int64 `json:"fixed"`
+	BasisPoints int64 `json:"basis_points"`
+}
+
+// swagger:parameters QuoteFee
+type FeeQuoteRequestBody struct {
+	// in:body
+	Operation string `json:"operation"`
+	// in:body
+	Amount int64 `json:"amount"`
+	// in:body
+	Currency string `json:"currency"`
+	// in:body
+	ScheduleVersion int `json:"schedule_version"`
+}
+
+type FeeQuoteBody struct {
+	ScheduleVersion int    `json:"schedule_version"`
+	Operation       string `json:"operation"`
+	Currency        string `json:"currency"`
+	Amount          int64  `json:"amount"`
+	Fee             int64  `json:"fee"`
+	Total           int64  `json:"total"`
+}
+
+// swagger:route POST /fees/quote fees QuoteFee
+// Previews fee of operation by the schedule effective now or by given schedule version.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *FeeHandlerV1) Quote(ctx *fasthttp.RequestCtx) {
+	request := &FeeQuoteRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	operation, err := validateFeeQuoteRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	quote, err := h.useCase.Quote(operation, request.Amount, request.Currency, request.ScheduleVersion)
+	if err != nil {
+		switch err.(type) {
+		case *domain.NotFoundError:
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+		default:
+			h.logger.Error(fmt.Sprintf("error while quote fee. request: %s, error: %s", ctx.PostBody(), err.Error()))
+			h.responseWriter.WriteError(
+				ctx,
+				http.StatusText(fasthttp.StatusInternalServerError),
+				fasthttp.StatusInternalServerError,
+			)
+		}
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromFeeQuote(quote))
+}
+
+// swagger:route POST /fees/schedules fees CreateFeeSchedule
+// Creates next version of fee schedule.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *FeeHandlerV1) CreateSchedule(ctx *fasthttp.RequestCtx) {
+	request := &FeeScheduleBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	effectiveFrom, rules, err := feeScheduleFromRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	schedule, err := h.useCase.CreateSchedule(effectiveFrom, rules)
+	if err != nil {
+		switch err.(type) {
+		case *domain.ValidationError:
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+		default:
+			h.logger.Error(
+				fmt.Sprintf("error while create fee schedule. request: %s, error: %s", ctx.PostBody(), err.Error()),
+			)
+			h.responseWriter.WriteError(
+				ctx,
+				http.StatusText(fasthttp.StatusInternalServerError),
+				fasthttp.StatusInternalServerError,
+			)
+		}
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromFeeSchedule(schedule))
+}
+
+// swagger:route GET /fees/schedules/{version} fees FindFeeSchedule
+// Finds fee schedule of given version.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *FeeHandlerV1) FindSchedule(ctx *fasthttp.RequestCtx) {
+	versionValue, ok := ctx.UserValue(FeeScheduleVersionUrlPath).(string)
+	if !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	version, err := strconv.Atoi(versionValue)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	schedule, err := h.useCase.FindSchedule(version)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("error while find fee schedule. version: %d, error: %s", version, err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			fasthttp.StatusMessage(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+		return
+	}
+	if schedule == nil {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusNotFound), fasthttp.StatusNotFound)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromFeeSchedule(schedule))
+}
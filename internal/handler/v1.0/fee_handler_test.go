@@ -0,0 +1,135 @@
+package v1
+
+import (
+	"net"
+	"testing"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestQuoteFee(t *testing.T) {
+	t.Parallel()
+
+	currentSchedule := &domain.FeeSchedule{
+		Version: 2,
+		Rules:   []domain.FeeRule{{Operation: domain.MovementTypeTransfer, BasisPoints: 100, Min: 3000}},
+	}
+	previousSchedule := &domain.FeeSchedule{
+		Version: 1,
+		Rules:   []domain.FeeRule{{Operation: domain.MovementTypeTransfer, Fixed: 1000}},
+	}
+
+	testCases := []struct {
+		name           string
+		input          []byte
+		expectedStatus int
+		expectedResult string
+	}{
+		{
+			"EffectiveSchedule",
+			[]byte(`{"operation": "transfer", "amount": 1000000, "currency": "RUB"}`),
+			fasthttp.StatusOK,
+			`{"schedule_version":2,"operation":"transfer","currency":"RUB","amount":1000000,"fee":10000,"total":1010000}`,
+		},
+		{
+			"ScheduleVersion",
+			[]byte(`{"operation": "transfer", "amount": 1000000, "currency": "RUB", "schedule_version": 1}`),
+			fasthttp.StatusOK,
+			`{"schedule_version":1,"operation":"transfer","currency":"RUB","amount":1000000,"fee":1000,"total":1001000}`,
+		},
+		{
+			"UnknownScheduleVersion",
+			[]byte(`{"operation": "transfer", "amount": 1000000, "currency": "RUB", "schedule_version": 5}`),
+			fasthttp.StatusNotFound,
+			`{"error":{"status":404,"message":"fee schedule not found"}}`,
+		},
+		{
+			"UnknownOperation",
+			[]byte(`{"operation": "refund", "amount": 1000000, "currency": "RUB"}`),
+			fasthttp.StatusBadRequest,
+			`{"error":{"status":400,"message":"operation should be one of deposit, withdrawal, transfer, conversion"}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			feeRepositoryMock := mocks.NewMockFeeRepository(ctrl)
+			feeRepositoryMock.EXPECT().FindEffectiveSchedule(gomock.Any()).AnyTimes().Return(currentSchedule, nil)
+			feeRepositoryMock.EXPECT().FindScheduleByVersion(1).AnyTimes().Return(previousSchedule, nil)
+			feeRepositoryMock.EXPECT().FindScheduleByVersion(5).AnyTimes().Return(nil, nil)
+
+			useCase := usecase.NewFeeUseCase(feeRepositoryMock)
+			logger, _ := zap.NewDevelopment()
+			writer := NewJSONResponseWriter(logger)
+			handlerV1 := NewFeeHandlerV1(logger, useCase, writer)
+
+			// arrange fake server
+			router := fasthttprouter.New()
+			router.POST("/fees/quote", handlerV1.Quote)
+
+			listener := fasthttputil.NewInmemoryListener()
+
+			server := &fasthttp.Server{
+				Handler: router.Handler,
+			}
+			go func() {
+				_ = server.Serve(listener)
+			}()
+
+			client := fasthttp.Client{
+				Dial: func(addr string) (net.Conn, error) {
+					return listener.Dial()
+				},
+			}
+			request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+			defer func() {
+				fasthttp.ReleaseRequest(request)
+				fasthttp.ReleaseResponse(response)
+			}()
+
+			// act
+			request.Header.SetMethod(fasthttp.MethodPost)
+			request.SetBody(test.input)
+			request.SetRequestURI("/fees/quote")
+			request.SetHost("localhost")
+
+			_ = client.Do(request, response)
+
+			// assert
+			assert.Equal(t, test.expectedStatus, response.Header.StatusCode())
+			assert.Equal(t, test.expectedResult, string(response.Body()))
+		})
+	}
+}
+
+// newFeeUseCase builds fee use case where schedule is effective at any time, nil schedule means no fees
+func newFeeUseCase(ctrl *gomock.Controller, schedule *domain.FeeSchedule) *usecase.FeeUseCase {
+	feeRepositoryMock := mocks.NewMockFeeRepository(ctrl)
+	feeRepositoryMock.EXPECT().FindEffectiveSchedule(gomock.Any()).Return(schedule, nil).AnyTimes()
+	return usecase.NewFeeUseCase(feeRepositoryMock)
+}
+
+// newLedgerUseCase builds ledger use case which does not charge fees and limits of which are not reached by tests,
+// debits are monitored by default rules
+func newLedgerUseCase(ctrl *gomock.Controller, repo domain.LedgerRepository) *usecase.LedgerUseCase {
+	return usecase.NewLedgerUseCase(
+		repo,
+		newVerificationUseCase(ctrl, approvedVerification),
+		newLimitUseCase(ctrl, highLimits),
+		newFeeUseCase(ctrl, nil),
+		newMonitoringUseCase(ctrl, nil),
+	)
+}
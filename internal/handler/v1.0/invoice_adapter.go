@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/invoicing"
+)
+
+func invoiceFromRequest(request *InvoiceRequestBody) (*domain.Invoice, error) {
+	if request.TenantID == "" {
+		return nil, domain.NewValidationError("tenant_id is mandatory field")
+	}
+	if request.CustomerID == "" {
+		return nil, domain.NewValidationError("customer_id is mandatory field")
+	}
+	invoice, err := invoiceChangesFromRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	invoice.TenantID = request.TenantID
+	invoice.CustomerID = request.CustomerID
+	return invoice, nil
+}
+
+// invoiceChangesFromRequest reads fields which could be changed in draft invoice
+func invoiceChangesFromRequest(request *InvoiceRequestBody) (*domain.Invoice, error) {
+	if !currencyRegexp.MatchString(request.Currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	dueDate, err := time.Parse(domain.DateFormat, request.DueDate)
+	if err != nil {
+		return nil, domain.NewValidationError("wrong due_date format")
+	}
+	if len(request.Lines) == 0 {
+		return nil, domain.NewValidationError("lines are mandatory field")
+	}
+
+	invoice := &domain.Invoice{Currency: request.Currency, DueDate: dueDate}
+	for i, line := range request.Lines {
+		if line.Description == "" {
+			return nil, domain.NewValidationError(fmt.Sprintf("lines[%d].description is mandatory field", i))
+		}
+		if line.Quantity == "" {
+			line.Quantity = "1"
+		}
+		if line.VATRate == "" {
+			line.VATRate = "0"
+		}
+		invoice.Lines = append(invoice.Lines, domain.InvoiceLine{
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			VATRate:     line.VATRate,
+		})
+	}
+	return invoice, nil
+}
+
+func responseFromInvoice(invoice *domain.Invoice) *InvoiceBody {
+	body := &InvoiceBody{
+		InvoiceID:        invoice.GeneratedID,
+		TenantID:         invoice.TenantID,
+		CustomerID:       invoice.CustomerID,
+		Number:           invoice.Number,
+		Status:           string(invoice.Status),
+		Currency:         invoice.Currency,
+		Lines:            make([]InvoiceLineBody, 0, len(invoice.Lines)),
+		VAT:              make([]InvoiceVATBody, 0),
+		Subtotal:         invoice.Subtotal,
+		VATTotal:         invoice.VATTotal,
+		Total:            invoice.Total,
+		DueDate:          invoice.DueDate.Format(domain.DateFormat),
+		CreatedAt:        invoice.CreatedAt.Format(domain.DateTimeFormat),
+		UpdatedAt:        invoice.UpdatedAt.Format(domain.DateTimeFormat),
+		RiskAssessmentID: invoice.RiskAssessmentID,
+		RiskDecision:     string(invoice.RiskDecision),
+	}
+	for _, line := range invoice.Lines {
+		body.Lines = append(body.Lines, InvoiceLineBody{
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			VATRate:     line.VATRate,
+			Net:         line.Net,
+			VAT:         line.VAT,
+			Total:       line.Total,
+		})
+	}
+	for _, amount := range invoicing.VATBreakdown(invoice) {
+		body.VAT = append(body.VAT, InvoiceVATBody{VATRate: amount.VATRate, Net: amount.Net, VAT: amount.VAT})
+	}
+	if !invoice.IssuedAt.IsZero() {
+		body.IssuedAt = invoice.IssuedAt.Format(domain.DateTimeFormat)
+	}
+	if !invoice.PaidAt.IsZero() {
+		body.PaidAt = invoice.PaidAt.Format(domain.DateTimeFormat)
+	}
+	if !invoice.VoidedAt.IsZero() {
+		body.VoidedAt = invoice.VoidedAt.Format(domain.DateTimeFormat)
+	}
+	return body
+}
@@ -0,0 +1,330 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const (
+	InvoiceIdUrlPath = "id"
+	ContentTypePDF   = "application/pdf"
+	// pdfSuffix of invoice id in url asks for PDF document instead of JSON
+	pdfSuffix = ".pdf"
+)
+
+type InvoiceHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.InvoiceUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewInvoiceHandlerV1(
+	logger *zap.Logger,
+	invoiceService *usecase.InvoiceUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *InvoiceHandlerV1 {
+	return &InvoiceHandlerV1{logger: logger, useCase: invoiceService, responseWriter: responseWriter}
+}
+
+// swagger:parameters CreateInvoice UpdateInvoice
+type InvoiceRequestBody struct {
+	// issuer of invoice, invoices are numbered per tenant. Ignored on update.
+	// in:body
+	TenantID string `json:"tenant_id"`
+	// Ignored on update.
+	// in:body
+	CustomerID string `json:"customer_id"`
+	// in:body
+	Currency string `json:"currency"`
+	// in:body
+	DueDate string `json:"due_date"`
+	// in:body
+	Lines []InvoiceLineRequestBody `json:"lines"`
+}
+
+type InvoiceLineRequestBody struct {
+	Description string `json:"description"`
+	// decimal like 1.5, 1 by default
+	Quantity string `json:"quantity"`
+	// price in minor currency units without VAT
+	UnitPrice int64 `json:"unit_price"`
+	// decimal percent like 20 or 10, 0 by default
+	VATRate string `json:"vat_rate"`
+}
+
+type InvoicesBody struct {
+	Invoices []*InvoiceBody `json:"invoices"`
+}
+
+type InvoiceBody struct {
+	InvoiceID  string            `json:"invoice_id"`
+	TenantID   string            `json:"tenant_id"`
+	CustomerID string            `json:"customer_id"`
+	Number     int64             `json:"number,omitempty"`
+	Status     string            `json:"status"`
+	Currency   string            `json:"currency"`
+	Lines      []InvoiceLineBody `json:"lines"`
+	VAT        []InvoiceVATBody  `json:"vat"`
+	Subtotal   int64             `json:"subtotal"`
+	VATTotal   int64             `json:"vat_total"`
+	Total      int64             `json:"total"`
+	DueDate    string            `json:"due_date"`
+	IssuedAt   string            `json:"issued_at,omitempty"`
+	PaidAt     string            `json:"paid_at,omitempty"`
+	VoidedAt   string            `json:"voided_at,omitempty"`
+	// assessment scored before invoice was paid
+	RiskAssessmentID string `json:"risk_assessment_id,omitempty"`
+	// risk decision, approve or review
+	RiskDecision string `json:"risk_decision,omitempty"`
+	CreatedAt    string `json:"created_at"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+type InvoiceLineBody struct {
+	Description string `json:"description"`
+	Quantity    string `json:"quantity"`
+	UnitPrice   int64  `json:"unit_price"`
+	VATRate     string `json:"vat_rate"`
+	Net         int64  `json:"net"`
+	VAT         int64  `json:"vat"`
+	Total       int64  `json:"total"`
+}
+
+type InvoiceVATBody struct {
+	VATRate string `json:"vat_rate"`
+	Net     int64  `json:"net"`
+	VAT     int64  `json:"vat"`
+}
+
+// swagger:route POST /invoices invoices CreateInvoice
+// Creates draft invoice, totals and VAT are calculated from lines.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *InvoiceHandlerV1) Create(ctx *fasthttp.RequestCtx) {
+	request := &InvoiceRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	invoice, err := invoiceFromRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Create(invoice)
+	if err != nil {
+		h.writeInvoiceError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromInvoice(invoice))
+}
+
+// swagger:route GET /invoices/{id} invoices FindInvoice
+// Shows invoice. Invoice id with .pdf suffix like /invoices/{id}.pdf downloads invoice as PDF document.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *InvoiceHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	invoiceID := ctx.UserValue(InvoiceIdUrlPath)
+	if _, ok := invoiceID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	if strings.HasSuffix(invoiceID.(string), pdfSuffix) {
+		invoiceID = strings.TrimSuffix(invoiceID.(string), pdfSuffix)
+		document, err := h.useCase.RenderPDF(invoiceID.(string))
+		if err != nil {
+			h.writeInvoiceError(ctx, err)
+			return
+		}
+		h.responseWriter.WriteSuccessFile(ctx, ContentTypePDF, invoiceID.(string)+pdfSuffix, document)
+		return
+	}
+
+	invoice, err := h.useCase.Find(invoiceID.(string))
+	if err != nil {
+		h.writeInvoiceError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromInvoice(invoice))
+}
+
+// swagger:route PUT /invoices/{id} invoices UpdateInvoice
+// Replaces currency, due date and lines of draft invoice.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *InvoiceHandlerV1) Update(ctx *fasthttp.RequestCtx) {
+	invoiceID := ctx.UserValue(InvoiceIdUrlPath)
+	if _, ok := invoiceID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &InvoiceRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	changes, err := invoiceChangesFromRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	invoice, err := h.useCase.UpdateDraft(invoiceID.(string), changes)
+	if err != nil {
+		h.writeInvoiceError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromInvoice(invoice))
+}
+
+// swagger:route POST /invoices/{id}/finalize invoices FinalizeInvoice
+// Issues draft invoice with the next number of its tenant.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *InvoiceHandlerV1) Finalize(ctx *fasthttp.RequestCtx) {
+	h.transition(ctx, h.useCase.Finalize)
+}
+
+// swagger:route POST /invoices/{id}/void invoices VoidInvoice
+// Voids draft or open invoice.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *InvoiceHandlerV1) Void(ctx *fasthttp.RequestCtx) {
+	h.transition(ctx, h.useCase.Void)
+}
+
+// swagger:parameters PayInvoice
+type InvoicePayRequestBody struct {
+	// device of customer scored by risk assessment
+	// in:body
+	DeviceID string `json:"device_id"`
+	// IP address of customer scored by risk assessment
+	// in:body
+	IP string `json:"ip"`
+}
+
+// swagger:route POST /invoices/{id}/pay invoices PayInvoice
+// Pays open invoice from customer balance. Payment declined by risk assessment is refused.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  422: ErrorResponse
+//  500: ErrorResponse
+func (h *InvoiceHandlerV1) Pay(ctx *fasthttp.RequestCtx) {
+	request := &InvoicePayRequestBody{}
+	if len(ctx.PostBody()) > 0 {
+		err := json.Unmarshal(ctx.PostBody(), request)
+		if err != nil {
+			h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+			return
+		}
+	}
+	if request.IP != "" && net.ParseIP(request.IP) == nil {
+		h.responseWriter.WriteError(ctx, "ip is not valid IP address", fasthttp.StatusBadRequest)
+		return
+	}
+
+	h.transition(ctx, func(invoiceID string) (*domain.Invoice, error) {
+		return h.useCase.Pay(invoiceID, request.DeviceID, request.IP)
+	})
+}
+
+// swagger:route GET /customer/{id}/invoices invoices FindCustomerInvoices
+// Lists invoices of customer, the latest first.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *InvoiceHandlerV1) FindByCustomer(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	invoices, err := h.useCase.FindByCustomer(customerID.(string))
+	if err != nil {
+		h.writeInvoiceError(ctx, err)
+		return
+	}
+
+	response := &InvoicesBody{Invoices: make([]*InvoiceBody, 0, len(invoices))}
+	for _, invoice := range invoices {
+		response.Invoices = append(response.Invoices, responseFromInvoice(invoice))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// transition moves invoice from url to another status and responds with it
+func (h *InvoiceHandlerV1) transition(
+	ctx *fasthttp.RequestCtx,
+	transition func(invoiceID string) (*domain.Invoice, error),
+) {
+	invoiceID := ctx.UserValue(InvoiceIdUrlPath)
+	if _, ok := invoiceID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	invoice, err := transition(invoiceID.(string))
+	if err != nil {
+		h.writeInvoiceError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromInvoice(invoice))
+}
+
+func (h *InvoiceHandlerV1) writeInvoiceError(ctx *fasthttp.RequestCtx, err error) {
+	switch typedErr := err.(type) {
+	case *domain.LimitExceededError:
+		writeLimitExceeded(h.responseWriter, ctx, typedErr)
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process invoice. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,241 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/invoicing"
+	"github.com/yaroslavnayug/go-payment-system/internal/risk"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestCreateInvoice(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("foobar").Return(&domain.Customer{GeneratedID: "foobar"}, nil)
+	repositoryMock := mocks.NewMockInvoiceRepository(ctrl)
+	repositoryMock.EXPECT().Create(gomock.Any()).Return(nil)
+
+	useCase := usecase.NewInvoiceUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewInvoiceHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/invoices", handlerV1.Create)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/invoices")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBody([]byte(`{
+		"tenant_id": "tenant", "customer_id": "foobar", "currency": "RUB", "due_date": "01-09-2020",
+		"lines": [
+			{"description": "Hosting", "quantity": "3", "unit_price": 3333, "vat_rate": "20"},
+			{"description": "Book", "quantity": "1.5", "unit_price": 1000, "vat_rate": "10"}
+		]
+	}`))
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	body := &InvoiceBody{}
+	err := json.Unmarshal(response.Body(), body)
+	assert.NoError(t, err)
+	assert.Equal(t, "draft", body.Status)
+	assert.Equal(t, int64(0), body.Number)
+	assert.Equal(t, int64(11499), body.Subtotal)
+	assert.Equal(t, int64(2150), body.VATTotal)
+	assert.Equal(t, int64(13649), body.Total)
+	assert.Equal(
+		t,
+		[]InvoiceVATBody{{VATRate: "20", Net: 9999, VAT: 2000}, {VATRate: "10", Net: 1500, VAT: 150}},
+		body.VAT,
+	)
+}
+
+func TestFindInvoice_PDF(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	invoice := &domain.Invoice{
+		GeneratedID: "invoice",
+		TenantID:    "tenant",
+		CustomerID:  "foobar",
+		Number:      7,
+		Status:      domain.InvoiceStatusOpen,
+		Currency:    "RUB",
+		Lines:       []domain.InvoiceLine{{Description: "Hosting", Quantity: "1", UnitPrice: 10000, VATRate: "20"}},
+	}
+	_ = invoicing.Calculate(invoice)
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().
+		FindByID("foobar").
+		Return(&domain.Customer{GeneratedID: "foobar", FirstName: "Misha", LastName: "Ivanov"}, nil)
+	repositoryMock := mocks.NewMockInvoiceRepository(ctrl)
+	repositoryMock.EXPECT().FindByID("invoice").Return(invoice, nil)
+
+	useCase := usecase.NewInvoiceUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewInvoiceHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.GET("/invoices/:id", handlerV1.Find)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/invoices/invoice.pdf")
+	request.Header.SetMethod(fasthttp.MethodGet)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusOK, response.Header.StatusCode())
+	assert.Equal(t, ContentTypePDF, string(response.Header.ContentType()))
+	assert.Equal(t, `attachment; filename="invoice.pdf"`, string(response.Header.Peek("Content-Disposition")))
+	assert.True(t, bytes.HasPrefix(response.Body(), []byte("%PDF-1.4")))
+}
+
+func TestPayInvoice_InsufficientFunds(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repositoryMock := mocks.NewMockInvoiceRepository(ctrl)
+	repositoryMock.EXPECT().
+		FindByID("invoice").
+		Return(&domain.Invoice{
+			GeneratedID: "invoice",
+			CustomerID:  "customer",
+			Status:      domain.InvoiceStatusOpen,
+			Total:       12000,
+		}, nil)
+	repositoryMock.EXPECT().
+		Pay(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(invoice *domain.Invoice, _ *domain.Debit) (bool, error) {
+			assert.NotEmpty(t, invoice.RiskAssessmentID)
+			assert.NotEmpty(t, invoice.RiskDecision)
+			return false, domain.ErrInsufficientFunds
+		})
+
+	useCase := usecase.NewInvoiceUseCase(
+		repositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewInvoiceHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/invoices/:id/pay", handlerV1.Pay)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/invoices/invoice/pay")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"device_id": "device", "ip": "10.0.0.1"}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.Contains(t, string(response.Body()), "insufficient funds")
+}
@@ -0,0 +1,100 @@
+package v1
+
+import (
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+// LimitErrorCodeExceeded is returned with 422 status when debit would breach one of customer limits
+const LimitErrorCodeExceeded = "limit_exceeded"
+
+func limitCurrencyFromRequest(args *fasthttp.Args) (string, error) {
+	currency := string(args.Peek("currency"))
+	if !currencyRegexp.MatchString(currency) {
+		return "", domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	return currency, nil
+}
+
+func limitsFromRequest(request *LimitsRequestBody) (domain.TransactionLimits, error) {
+	if !currencyRegexp.MatchString(request.Currency) {
+		return domain.TransactionLimits{}, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	if request.PerTransaction < 0 || request.Daily < 0 || request.Monthly < 0 ||
+		request.DailyCount < 0 || request.MonthlyCount < 0 {
+		return domain.TransactionLimits{}, domain.NewValidationError("limits should not be negative")
+	}
+	return domain.TransactionLimits{
+		PerTransaction: request.PerTransaction,
+		Daily:          request.Daily,
+		Monthly:        request.Monthly,
+		DailyCount:     request.DailyCount,
+		MonthlyCount:   request.MonthlyCount,
+	}, nil
+}
+
+func responseFromLimits(currency string, customerLimits *usecase.CustomerLimits) *CustomerLimitsBody {
+	limits := customerLimits.Limits
+	remaining := limits.Remaining(customerLimits.Used)
+	return &CustomerLimitsBody{
+		Currency: currency,
+		Source:   customerLimits.Source,
+		Limits: LimitsBody{
+			PerTransaction: limits.PerTransaction,
+			Daily:          limits.Daily,
+			Monthly:        limits.Monthly,
+			DailyCount:     limits.DailyCount,
+			MonthlyCount:   limits.MonthlyCount,
+		},
+		Used: responseFromLimitUsage(customerLimits.Used),
+		Remaining: LimitUsageBody{
+			PerTransaction: minAllowance(limits.PerTransaction, remaining.DailyAmount),
+			Daily:          remaining.DailyAmount,
+			Monthly:        remaining.MonthlyAmount,
+			DailyCount:     remaining.DailyCount,
+			MonthlyCount:   remaining.MonthlyCount,
+		},
+	}
+}
+
+func responseFromLimitUsage(usage domain.LimitUsage) LimitUsageBody {
+	return LimitUsageBody{
+		Daily:        usage.DailyAmount,
+		Monthly:      usage.MonthlyAmount,
+		DailyCount:   usage.DailyCount,
+		MonthlyCount: usage.MonthlyCount,
+	}
+}
+
+func responseFromLimitExceeded(err *domain.LimitExceededError) *LimitExceededBody {
+	return &LimitExceededBody{
+		Limit:     err.Limit,
+		Allowed:   err.Allowed,
+		Used:      err.Used,
+		Requested: err.Requested,
+	}
+}
+
+// writeLimitExceeded answers debit which would breach one of customer limits
+func writeLimitExceeded(
+	responseWriter handler.ResponseWriterInterface,
+	ctx *fasthttp.RequestCtx,
+	err *domain.LimitExceededError,
+) {
+	responseWriter.WriteErrorWithDetails(
+		ctx,
+		err.Error(),
+		fasthttp.StatusUnprocessableEntity,
+		LimitErrorCodeExceeded,
+		responseFromLimitExceeded(err),
+	)
+}
+
+func minAllowance(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,186 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+type LimitHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.LimitUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewLimitHandlerV1(
+	logger *zap.Logger,
+	limitService *usecase.LimitUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *LimitHandlerV1 {
+	return &LimitHandlerV1{logger: logger, useCase: limitService, responseWriter: responseWriter}
+}
+
+// swagger:parameters SetLimits
+type LimitsRequestBody struct {
+	// in:body
+	Currency string `json:"currency"`
+	LimitsBody
+}
+
+type LimitsBody struct {
+	// in:body
+	PerTransaction int64 `json:"per_transaction"`
+	// in:body
+	Daily int64 `json:"daily"`
+	// in:body
+	Monthly int64 `json:"monthly"`
+	// in:body
+	DailyCount int64 `json:"daily_count"`
+	// in:body
+	MonthlyCount int64 `json:"monthly_count"`
+}
+
+type LimitUsageBody struct {
+	PerTransaction int64 `json:"per_transaction,omitempty"`
+	Daily          int64 `json:"daily"`
+	Monthly        int64 `json:"monthly"`
+	DailyCount     int64 `json:"daily_count"`
+	MonthlyCount   int64 `json:"monthly_count"`
+}
+
+type CustomerLimitsBody struct {
+	Currency  string         `json:"currency"`
+	Source    string         `json:"source"`
+	Limits    LimitsBody     `json:"limits"`
+	Used      LimitUsageBody `json:"used"`
+	Remaining LimitUsageBody `json:"remaining"`
+}
+
+type LimitExceededBody struct {
+	Limit     string `json:"limit"`
+	Allowed   int64  `json:"allowed"`
+	Used      int64  `json:"used"`
+	Requested int64  `json:"requested"`
+}
+
+// swagger:route GET /customer/{id}/limits limits FindLimits
+// Shows limits applied to customer in currency, their usage and remaining allowance for current day and month.
+// responses:
+//
+//	200:
+//	400: ErrorResponse
+//	404: ErrorResponse
+//	500: ErrorResponse
+func (h *LimitHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	currency, err := limitCurrencyFromRequest(ctx.QueryArgs())
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	customerLimits, err := h.useCase.Limits(customerID.(string), currency)
+	if err != nil {
+		switch err.(type) {
+		case *domain.NotFoundError:
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+		default:
+			h.logger.Error(fmt.Sprintf("error while find limits. customerID: %s, error: %s", customerID, err.Error()))
+			h.responseWriter.WriteError(
+				ctx,
+				fasthttp.StatusMessage(fasthttp.StatusInternalServerError),
+				fasthttp.StatusInternalServerError,
+			)
+		}
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromLimits(currency, customerLimits))
+}
+
+// swagger:route PUT /customer/{id}/limits limits SetLimits
+// Overrides tier limits of customer in currency.
+// responses:
+//
+//	200:
+//	400: ErrorResponse
+//	404: ErrorResponse
+//	500: ErrorResponse
+func (h *LimitHandlerV1) SetOverride(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &LimitsRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	limits, err := limitsFromRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.SetOverride(customerID.(string), request.Currency, limits)
+	if err != nil {
+		h.writeLimitError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPUT(ctx)
+}
+
+// swagger:route DELETE /customer/{id}/limits limits DeleteLimits
+// Removes limits override in currency, customer gets limits of the tier again.
+// responses:
+//
+//	204:
+//	400: ErrorResponse
+//	500: ErrorResponse
+func (h *LimitHandlerV1) DeleteOverride(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	currency, err := limitCurrencyFromRequest(ctx.QueryArgs())
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	err = h.useCase.DeleteOverride(customerID.(string), currency)
+	if err != nil {
+		h.writeLimitError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessDELETE(ctx)
+}
+
+func (h *LimitHandlerV1) writeLimitError(ctx *fasthttp.RequestCtx, err error) {
+	switch err.(type) {
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process limits. request: %s, error: %s", ctx.PostBody(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
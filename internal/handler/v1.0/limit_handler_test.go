@@ -0,0 +1,206 @@
+package v1
+
+import (
+	"net"
+	"testing"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/risk"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestFindLimits(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("foobar").Return(&domain.Customer{GeneratedID: "foobar"}, nil)
+	verificationRepositoryMock := mocks.NewMockVerificationRepository(ctrl)
+	verificationRepositoryMock.EXPECT().
+		FindLastByCustomerID("foobar").
+		Return(&domain.Verification{Status: domain.VerificationStatusApproved, Level: domain.VerificationLevelBasic}, nil)
+	limitRepositoryMock := mocks.NewMockLimitRepository(ctrl)
+	limitRepositoryMock.EXPECT().FindCustomerLimits("foobar", "RUB").Return(nil, nil)
+	limitRepositoryMock.EXPECT().
+		FindTierLimits(domain.VerificationLevelBasic, "RUB").
+		Return(&domain.TransactionLimits{
+			PerTransaction: 1500000, Daily: 1500000, Monthly: 4000000, DailyCount: 50, MonthlyCount: 300,
+		}, nil)
+	limitRepositoryMock.EXPECT().
+		FindUsage("foobar", "RUB", gomock.Any()).
+		Return(domain.LimitUsage{DailyAmount: 1000000, MonthlyAmount: 3800000, DailyCount: 2, MonthlyCount: 20}, nil)
+
+	useCase := usecase.NewLimitUseCase(limitRepositoryMock, verificationRepositoryMock, customerRepositoryMock)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewLimitHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.GET("/customer/:id/limits", handlerV1.Find)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/foobar/limits?currency=RUB")
+	request.Header.SetMethod(fasthttp.MethodGet)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusOK, response.Header.StatusCode())
+	assert.JSONEq(
+		t,
+		`{
+			"currency": "RUB",
+			"source": "basic",
+			"limits": {"per_transaction": 1500000, "daily": 1500000, "monthly": 4000000,
+				"daily_count": 50, "monthly_count": 300},
+			"used": {"daily": 1000000, "monthly": 3800000, "daily_count": 2, "monthly_count": 20},
+			"remaining": {"per_transaction": 200000, "daily": 200000, "monthly": 200000,
+				"daily_count": 48, "monthly_count": 280}
+		}`,
+		string(response.Body()),
+	)
+}
+
+func TestCreateSplitPayment_LimitExceeded(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	for _, customerID := range []string{"foobar", "seller"} {
+		customerRepositoryMock.EXPECT().FindByID(customerID).Return(&domain.Customer{
+			GeneratedID: customerID,
+			Status:      domain.CustomerStatusActive,
+		}, nil)
+	}
+	limits := domain.TransactionLimits{
+		PerTransaction: 100000, Daily: 100000, Monthly: 1000000, DailyCount: 10, MonthlyCount: 100,
+	}
+	repositoryMock := mocks.NewMockSplitPaymentRepository(ctrl)
+	repositoryMock.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ *domain.SplitPayment, debit *domain.Debit) error {
+			limit := debit.Limit
+			assert.Equal(t, "RUB", limit.Currency)
+			assert.Equal(t, limits, limit.Limits)
+			return limit.Limits.Check(
+				domain.LimitUsage{DailyAmount: 70000, MonthlyAmount: 70000, DailyCount: 1, MonthlyCount: 1},
+				limit.Amount,
+			)
+		})
+
+	useCase := usecase.NewSplitPaymentUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		usecase.NewLedgerUseCase(
+			mocks.NewMockLedgerRepository(ctrl),
+			newVerificationUseCase(ctrl, approvedVerification),
+			newLimitUseCase(ctrl, limits),
+			newFeeUseCase(ctrl, nil),
+			newMonitoringUseCase(ctrl, nil),
+		),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewSplitPaymentHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/split-payments", handlerV1.Create)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/foobar/split-payments")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{
+		"amount": 50000,
+		"currency": "RUB",
+		"splits": [{"recipient_id": "seller", "role": "seller", "amount": 50000}]
+	}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusUnprocessableEntity, response.Header.StatusCode())
+	assert.Equal(
+		t,
+		`{"error":{"status":422,"message":"daily limit exceeded","code":"limit_exceeded",`+
+			`"details":{"limit":"daily","allowed":100000,"used":70000,"requested":50000}}}`,
+		string(response.Body()),
+	)
+}
+
+// highLimits are limits which are not reached by tests of debits
+var highLimits = domain.TransactionLimits{
+	PerTransaction: 1 << 40, Daily: 1 << 40, Monthly: 1 << 40, DailyCount: 1 << 20, MonthlyCount: 1 << 20,
+}
+
+// newLimitUseCase builds limits use case where every customer has limits override in every currency
+func newLimitUseCase(ctrl *gomock.Controller, limits domain.TransactionLimits) *usecase.LimitUseCase {
+	limitRepositoryMock := mocks.NewMockLimitRepository(ctrl)
+	limitRepositoryMock.EXPECT().FindCustomerLimits(gomock.Any(), gomock.Any()).Return(&limits, nil).AnyTimes()
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().
+		FindByID(gomock.Any()).
+		DoAndReturn(func(customerID string) (*domain.Customer, error) {
+			return &domain.Customer{GeneratedID: customerID, Status: domain.CustomerStatusActive}, nil
+		}).
+		AnyTimes()
+	return usecase.NewLimitUseCase(limitRepositoryMock, mocks.NewMockVerificationRepository(ctrl), customerRepositoryMock)
+}
@@ -0,0 +1,110 @@
+package v1
+
+import (
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func loanProductFromRequest(request *LoanProductRequestBody) (*domain.LoanProduct, error) {
+	if request.Name == "" {
+		return nil, domain.NewValidationError("name is mandatory field")
+	}
+	if !currencyRegexp.MatchString(request.Currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	return &domain.LoanProduct{
+		Name:          request.Name,
+		Currency:      request.Currency,
+		AnnualRate:    request.AnnualRate,
+		PenaltyRate:   request.PenaltyRate,
+		MinAmount:     request.MinAmount,
+		MaxAmount:     request.MaxAmount,
+		MinTermMonths: request.MinTermMonths,
+		MaxTermMonths: request.MaxTermMonths,
+	}, nil
+}
+
+func responseFromLoanProduct(product *domain.LoanProduct) *LoanProductBody {
+	return &LoanProductBody{
+		ProductID:     product.GeneratedID,
+		Name:          product.Name,
+		Currency:      product.Currency,
+		AnnualRate:    product.AnnualRate,
+		PenaltyRate:   product.PenaltyRate,
+		MinAmount:     product.MinAmount,
+		MaxAmount:     product.MaxAmount,
+		MinTermMonths: product.MinTermMonths,
+		MaxTermMonths: product.MaxTermMonths,
+	}
+}
+
+func responseFromLoanApplication(application *domain.LoanApplication) *LoanApplicationBody {
+	return &LoanApplicationBody{
+		ApplicationID:   application.GeneratedID,
+		ProductID:       application.ProductID,
+		CustomerID:      application.CustomerID,
+		Amount:          application.Amount,
+		Currency:        application.Currency,
+		TermMonths:      application.TermMonths,
+		Amortisation:    string(application.Amortisation),
+		Status:          string(application.Status),
+		RejectionReason: application.RejectionReason,
+		LoanID:          application.LoanID,
+		CreatedAt:       application.CreatedAt.Format(domain.DateTimeFormat),
+	}
+}
+
+func responseFromLoan(loan *domain.Loan) *LoanBody {
+	response := &LoanBody{
+		LoanID:        loan.GeneratedID,
+		ApplicationID: loan.ApplicationID,
+		ProductID:     loan.ProductID,
+		CustomerID:    loan.CustomerID,
+		Currency:      loan.Currency,
+		Principal:     loan.Principal,
+		Outstanding:   loan.Outstanding,
+		AnnualRate:    loan.AnnualRate,
+		PenaltyRate:   loan.PenaltyRate,
+		Amortisation:  string(loan.Amortisation),
+		TermMonths:    loan.TermMonths,
+		Status:        string(loan.Status),
+		Penalty:       loan.Penalty,
+		DisbursedAt:   loan.DisbursedAt.Format(domain.DateTimeFormat),
+	}
+	for _, installment := range loan.Installments {
+		body := &LoanInstallmentBody{
+			Number:        installment.Number,
+			DueDay:        installment.DueDay.In(businessday.Location).Format(domain.DateFormat),
+			Amount:        installment.Amount(),
+			Principal:     installment.Principal,
+			Interest:      installment.Interest,
+			PrincipalPaid: installment.PrincipalPaid,
+			InterestPaid:  installment.InterestPaid,
+			Status:        string(installment.Status),
+		}
+		if !installment.PaidAt.IsZero() {
+			body.PaidAt = installment.PaidAt.Format(domain.DateTimeFormat)
+		}
+		response.Schedule = append(response.Schedule, body)
+	}
+	if !loan.RepaidAt.IsZero() {
+		response.RepaidAt = loan.RepaidAt.Format(domain.DateTimeFormat)
+	}
+	return response
+}
+
+func responseFromLoanRepayments(repayments []*domain.LoanRepayment) *LoanRepaymentsBody {
+	response := &LoanRepaymentsBody{Repayments: make([]*LoanRepaymentBody, 0, len(repayments))}
+	for _, repayment := range repayments {
+		response.Repayments = append(response.Repayments, &LoanRepaymentBody{
+			Number:    repayment.Number,
+			Type:      string(repayment.Type),
+			Amount:    repayment.Amount,
+			Penalty:   repayment.Penalty,
+			Interest:  repayment.Interest,
+			Principal: repayment.Principal,
+			PaidAt:    repayment.PaidAt.Format(domain.DateTimeFormat),
+		})
+	}
+	return response
+}
@@ -0,0 +1,445 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const (
+	LoanIdUrlPath            = "id"
+	LoanApplicationIdUrlPath = "id"
+)
+
+type LoanHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.LoanUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewLoanHandlerV1(
+	logger *zap.Logger,
+	loanService *usecase.LoanUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *LoanHandlerV1 {
+	return &LoanHandlerV1{logger: logger, useCase: loanService, responseWriter: responseWriter}
+}
+
+// swagger:parameters CreateLoanProduct
+type LoanProductRequestBody struct {
+	// in:body
+	Name string `json:"name"`
+	// in:body
+	Currency string `json:"currency"`
+	// decimal annual percent like 19.9
+	// in:body
+	AnnualRate string `json:"annual_rate"`
+	// decimal annual percent charged on overdue installments for every day of delay
+	// in:body
+	PenaltyRate string `json:"penalty_rate"`
+	// in minor currency units
+	// in:body
+	MinAmount int64 `json:"min_amount"`
+	// in minor currency units
+	// in:body
+	MaxAmount int64 `json:"max_amount"`
+	// in:body
+	MinTermMonths int `json:"min_term_months"`
+	// in:body
+	MaxTermMonths int `json:"max_term_months"`
+}
+
+// swagger:parameters ApplyForLoan
+type LoanApplicationRequestBody struct {
+	// in:body
+	ProductID string `json:"product_id"`
+	// in minor currency units
+	// in:body
+	Amount int64 `json:"amount"`
+	// in:body
+	TermMonths int `json:"term_months"`
+	// annuity or differentiated, annuity by default
+	// in:body
+	Amortisation string `json:"amortisation"`
+}
+
+// swagger:parameters RepayLoan
+type LoanRepaymentRequestBody struct {
+	// in:body
+	CustomerID string `json:"customer_id"`
+	// in minor currency units, amount more than loan costs today repays loan in full
+	// in:body
+	Amount int64 `json:"amount"`
+}
+
+type LoanProductsBody struct {
+	Products []*LoanProductBody `json:"products"`
+}
+
+type LoanProductBody struct {
+	ProductID     string `json:"product_id"`
+	Name          string `json:"name"`
+	Currency      string `json:"currency"`
+	AnnualRate    string `json:"annual_rate"`
+	PenaltyRate   string `json:"penalty_rate"`
+	MinAmount     int64  `json:"min_amount"`
+	MaxAmount     int64  `json:"max_amount"`
+	MinTermMonths int    `json:"min_term_months"`
+	MaxTermMonths int    `json:"max_term_months"`
+}
+
+type LoanApplicationsBody struct {
+	Applications []*LoanApplicationBody `json:"applications"`
+}
+
+type LoanApplicationBody struct {
+	ApplicationID   string `json:"application_id"`
+	ProductID       string `json:"product_id"`
+	CustomerID      string `json:"customer_id"`
+	Amount          int64  `json:"amount"`
+	Currency        string `json:"currency"`
+	TermMonths      int    `json:"term_months"`
+	Amortisation    string `json:"amortisation"`
+	Status          string `json:"status"`
+	RejectionReason string `json:"rejection_reason,omitempty"`
+	LoanID          string `json:"loan_id,omitempty"`
+	CreatedAt       string `json:"created_at"`
+}
+
+type LoansBody struct {
+	Loans []*LoanBody `json:"loans"`
+}
+
+type LoanBody struct {
+	LoanID        string                 `json:"loan_id"`
+	ApplicationID string                 `json:"application_id"`
+	ProductID     string                 `json:"product_id"`
+	CustomerID    string                 `json:"customer_id"`
+	Currency      string                 `json:"currency"`
+	Principal     int64                  `json:"principal"`
+	Outstanding   int64                  `json:"outstanding"`
+	AnnualRate    string                 `json:"annual_rate"`
+	PenaltyRate   string                 `json:"penalty_rate"`
+	Amortisation  string                 `json:"amortisation"`
+	TermMonths    int                    `json:"term_months"`
+	Status        string                 `json:"status"`
+	Penalty       int64                  `json:"penalty"`
+	Schedule      []*LoanInstallmentBody `json:"schedule,omitempty"`
+	DisbursedAt   string                 `json:"disbursed_at"`
+	RepaidAt      string                 `json:"repaid_at,omitempty"`
+}
+
+type LoanInstallmentBody struct {
+	Number        int    `json:"number"`
+	DueDay        string `json:"due_day"`
+	Amount        int64  `json:"amount"`
+	Principal     int64  `json:"principal"`
+	Interest      int64  `json:"interest"`
+	PrincipalPaid int64  `json:"principal_paid"`
+	InterestPaid  int64  `json:"interest_paid"`
+	Status        string `json:"status"`
+	PaidAt        string `json:"paid_at,omitempty"`
+}
+
+type LoanRepaymentsBody struct {
+	Repayments []*LoanRepaymentBody `json:"repayments"`
+}
+
+type LoanRepaymentBody struct {
+	Number    int    `json:"number"`
+	Type      string `json:"type"`
+	Amount    int64  `json:"amount"`
+	Penalty   int64  `json:"penalty"`
+	Interest  int64  `json:"interest"`
+	Principal int64  `json:"principal"`
+	PaidAt    string `json:"paid_at"`
+}
+
+// swagger:route POST /loan-products loans CreateLoanProduct
+// Creates loan product offered to customers.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *LoanHandlerV1) CreateProduct(ctx *fasthttp.RequestCtx) {
+	request := &LoanProductRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	product, err := loanProductFromRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.CreateProduct(product)
+	if err != nil {
+		h.writeLoanError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromLoanProduct(product))
+}
+
+// swagger:route GET /loan-products loans FindLoanProducts
+// Lists loan products.
+// responses:
+//  200:
+//  500: ErrorResponse
+func (h *LoanHandlerV1) FindProducts(ctx *fasthttp.RequestCtx) {
+	products, err := h.useCase.FindProducts()
+	if err != nil {
+		h.writeLoanError(ctx, err)
+		return
+	}
+	response := &LoanProductsBody{Products: make([]*LoanProductBody, 0, len(products))}
+	for _, product := range products {
+		response.Products = append(response.Products, responseFromLoanProduct(product))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route POST /customer/{id}/loan-applications loans ApplyForLoan
+// Applies for loan of product. Application is decided at once, application of customer younger than 18 years
+// by passport birth date is rejected.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *LoanHandlerV1) Apply(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &LoanApplicationRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	if request.ProductID == "" {
+		h.responseWriter.WriteError(ctx, "product_id is mandatory field", fasthttp.StatusBadRequest)
+		return
+	}
+
+	application := &domain.LoanApplication{
+		ProductID:    request.ProductID,
+		CustomerID:   customerID.(string),
+		Amount:       request.Amount,
+		TermMonths:   request.TermMonths,
+		Amortisation: domain.AmortisationType(request.Amortisation),
+	}
+	err = h.useCase.Apply(application)
+	if err != nil {
+		h.writeLoanError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromLoanApplication(application))
+}
+
+// swagger:route GET /customer/{id}/loan-applications loans FindCustomerLoanApplications
+// Lists loan applications of customer, the latest first.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *LoanHandlerV1) FindApplicationsByCustomer(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	applications, err := h.useCase.FindApplicationsByCustomer(customerID.(string))
+	if err != nil {
+		h.writeLoanError(ctx, err)
+		return
+	}
+	response := &LoanApplicationsBody{Applications: make([]*LoanApplicationBody, 0, len(applications))}
+	for _, application := range applications {
+		response.Applications = append(response.Applications, responseFromLoanApplication(application))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route GET /loan-applications/{id} loans FindLoanApplication
+// Shows loan application with decision on it.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *LoanHandlerV1) FindApplication(ctx *fasthttp.RequestCtx) {
+	applicationID := ctx.UserValue(LoanApplicationIdUrlPath)
+	if _, ok := applicationID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	application, err := h.useCase.FindApplication(applicationID.(string))
+	if err != nil {
+		h.writeLoanError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromLoanApplication(application))
+}
+
+// swagger:route POST /loan-applications/{id}/disburse loans DisburseLoan
+// Credits amount of approved application to customer balance and starts loan with monthly repayment schedule.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *LoanHandlerV1) Disburse(ctx *fasthttp.RequestCtx) {
+	applicationID := ctx.UserValue(LoanApplicationIdUrlPath)
+	if _, ok := applicationID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	loan, err := h.useCase.Disburse(applicationID.(string))
+	if err != nil {
+		h.writeLoanError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromLoan(loan))
+}
+
+// swagger:route GET /customer/{id}/loans loans FindCustomerLoans
+// Lists loans of customer without schedules, the latest first.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *LoanHandlerV1) FindByCustomer(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	loans, err := h.useCase.FindByCustomer(customerID.(string))
+	if err != nil {
+		h.writeLoanError(ctx, err)
+		return
+	}
+	response := &LoansBody{Loans: make([]*LoanBody, 0, len(loans))}
+	for _, loan := range loans {
+		response.Loans = append(response.Loans, responseFromLoan(loan))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route GET /loans/{id} loans FindLoan
+// Shows loan with its repayment schedule.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *LoanHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	loanID := ctx.UserValue(LoanIdUrlPath)
+	if _, ok := loanID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	loan, err := h.useCase.Find(loanID.(string))
+	if err != nil {
+		h.writeLoanError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromLoan(loan))
+}
+
+// swagger:route GET /loans/{id}/repayments loans FindLoanRepayments
+// Lists repayments of loan split between penalty, interest and principal.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *LoanHandlerV1) FindRepayments(ctx *fasthttp.RequestCtx) {
+	loanID := ctx.UserValue(LoanIdUrlPath)
+	if _, ok := loanID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	repayments, err := h.useCase.FindRepayments(loanID.(string))
+	if err != nil {
+		h.writeLoanError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromLoanRepayments(repayments))
+}
+
+// swagger:route POST /loans/{id}/repay loans RepayLoan
+// Repays loan early from customer balance. Amount repays penalty, due installments and interest accrued
+// for the current month, the rest repays principal and the following installments are recalculated.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *LoanHandlerV1) Repay(ctx *fasthttp.RequestCtx) {
+	loanID := ctx.UserValue(LoanIdUrlPath)
+	if _, ok := loanID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &LoanRepaymentRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	if request.CustomerID == "" {
+		h.responseWriter.WriteError(ctx, "customer_id is mandatory field", fasthttp.StatusBadRequest)
+		return
+	}
+	if request.Amount <= 0 {
+		h.responseWriter.WriteError(ctx, "amount should be positive", fasthttp.StatusBadRequest)
+		return
+	}
+
+	loan, err := h.useCase.Repay(loanID.(string), request.CustomerID, request.Amount)
+	if err != nil {
+		h.writeLoanError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromLoan(loan))
+}
+
+func (h *LoanHandlerV1) writeLoanError(ctx *fasthttp.RequestCtx, err error) {
+	switch err.(type) {
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process loan. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,196 @@
+package v1
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+var testLoanProduct = &domain.LoanProduct{
+	GeneratedID:   "product",
+	Name:          "Consumer 24",
+	Currency:      "RUB",
+	AnnualRate:    "12",
+	PenaltyRate:   "20",
+	MinAmount:     10000,
+	MaxAmount:     1000000,
+	MinTermMonths: 3,
+	MaxTermMonths: 24,
+}
+
+func TestApplyForLoan_RejectedUnderage(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("customer").Return(&domain.Customer{
+		GeneratedID: "customer",
+		Status:      domain.CustomerStatusActive,
+		Passport:    domain.Passport{BirthDate: time.Now().AddDate(-17, 0, 0)},
+	}, nil)
+	repositoryMock := mocks.NewMockLoanRepository(ctrl)
+	repositoryMock.EXPECT().FindProductByID("product").Return(testLoanProduct, nil)
+	repositoryMock.EXPECT().CreateApplication(gomock.Any()).Return(nil)
+
+	useCase := usecase.NewLoanUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		newVerificationUseCase(ctrl, approvedVerification),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewLoanHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/loan-applications", handlerV1.Apply)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/customer/loan-applications")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"product_id": "product", "amount": 100000, "term_months": 12}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	body := &LoanApplicationBody{}
+	err := json.Unmarshal(response.Body(), body)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, "rejected", body.Status)
+	assert.Equal(t, "customer should be 18 years old at least", body.RejectionReason)
+	assert.Equal(t, "annuity", body.Amortisation)
+	assert.Equal(t, "RUB", body.Currency)
+}
+
+func TestDisburseLoan_Success(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	application := &domain.LoanApplication{
+		GeneratedID:  "application",
+		ProductID:    "product",
+		CustomerID:   "customer",
+		Amount:       100000,
+		Currency:     "RUB",
+		TermMonths:   12,
+		Amortisation: domain.AmortisationDifferentiated,
+		Status:       domain.LoanApplicationStatusApproved,
+	}
+	repositoryMock := mocks.NewMockLoanRepository(ctrl)
+	repositoryMock.EXPECT().FindApplicationByID("application").Return(application, nil)
+	repositoryMock.EXPECT().FindProductByID("product").Return(testLoanProduct, nil)
+	repositoryMock.EXPECT().
+		Disburse("application", gomock.Any()).
+		DoAndReturn(func(
+			_ string,
+			disburse func(application *domain.LoanApplication) (*domain.Loan, []*domain.Posting, error),
+		) (*domain.Loan, error) {
+			loan, postings, err := disburse(application)
+			assert.NoError(t, err)
+			if assert.Len(t, postings, 2) {
+				assert.Equal(t, domain.LedgerAccountLoans, postings[0].CustomerID)
+				assert.Equal(t, int64(-100000), postings[0].Amount)
+				assert.Equal(t, "customer", postings[1].CustomerID)
+				assert.Equal(t, int64(100000), postings[1].Amount)
+				assert.Equal(t, "loan:"+loan.GeneratedID, postings[1].Reference)
+			}
+			return loan, nil
+		})
+
+	useCase := usecase.NewLoanUseCase(
+		repositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		newVerificationUseCase(ctrl, approvedVerification),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewLoanHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/loan-applications/:id/disburse", handlerV1.Disburse)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/loan-applications/application/disburse")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	body := &LoanBody{}
+	err := json.Unmarshal(response.Body(), body)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, "active", body.Status)
+	assert.Equal(t, int64(100000), body.Outstanding)
+	assert.Len(t, body.Schedule, 12)
+	assert.Equal(t, int64(8333), body.Schedule[0].Principal)
+	assert.Equal(t, int64(1000), body.Schedule[0].Interest)
+	assert.Equal(t, domain.LoanApplicationStatusDisbursed, application.Status)
+	assert.Equal(t, body.LoanID, application.LoanID)
+}
@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func alertStatusFromRequest(status string) (domain.AlertStatus, error) {
+	switch alertStatus := domain.AlertStatus(status); alertStatus {
+	case domain.AlertStatusOpen, domain.AlertStatusInvestigating, domain.AlertStatusClosed:
+		return alertStatus, nil
+	default:
+		return "", domain.NewValidationError("status should be one of open, investigating, closed")
+	}
+}
+
+func alertUpdateFromRequest(request *AlertUpdateBody) (domain.AlertStatus, domain.AlertResolution, error) {
+	status, err := alertStatusFromRequest(request.Status)
+	if err != nil {
+		return "", domain.AlertResolutionNone, err
+	}
+	resolution := domain.AlertResolution(request.Resolution)
+	switch resolution {
+	case domain.AlertResolutionNone, domain.AlertResolutionFalsePositive, domain.AlertResolutionReported:
+	default:
+		return "", domain.AlertResolutionNone, domain.NewValidationError(
+			"resolution should be one of false_positive, reported",
+		)
+	}
+	if request.Assignee == "" {
+		return "", domain.AlertResolutionNone, domain.NewValidationError("assignee is mandatory field")
+	}
+	return status, resolution, nil
+}
+
+func responseFromAlert(alert *domain.Alert) *AlertBody {
+	return &AlertBody{
+		AlertID:    alert.GeneratedID,
+		CustomerID: alert.CustomerID,
+		MovementID: alert.MovementID,
+		RuleName:   alert.RuleName,
+		RuleType:   alert.RuleType,
+		Severity:   alert.Severity,
+		Details:    alert.Details,
+		Status:     string(alert.Status),
+		Resolution: string(alert.Resolution),
+		Assignee:   alert.Assignee,
+		Comment:    alert.Comment,
+		CreatedAt:  alert.CreatedAt.Format(domain.DateTimeFormat),
+		UpdatedAt:  alert.UpdatedAt.Format(domain.DateTimeFormat),
+	}
+}
+
+func responseFromAlerts(alerts []*domain.Alert) *AlertsBody {
+	response := &AlertsBody{Alerts: make([]*AlertBody, 0, len(alerts))}
+	for _, alert := range alerts {
+		response.Alerts = append(response.Alerts, responseFromAlert(alert))
+	}
+	return response
+}
@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const AlertIdUrlPath = "id"
+
+type MonitoringHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.MonitoringUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewMonitoringHandlerV1(
+	logger *zap.Logger,
+	monitoringService *usecase.MonitoringUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *MonitoringHandlerV1 {
+	return &MonitoringHandlerV1{logger: logger, useCase: monitoringService, responseWriter: responseWriter}
+}
+
+type AlertsBody struct {
+	Alerts []*AlertBody `json:"alerts"`
+}
+
+type AlertBody struct {
+	AlertID    string `json:"alert_id"`
+	CustomerID string `json:"customer_id"`
+	MovementID string `json:"movement_id"`
+	RuleName   string `json:"rule_name"`
+	RuleType   string `json:"rule_type"`
+	Severity   string `json:"severity"`
+	Details    string `json:"details"`
+	Status     string `json:"status"`
+	Resolution string `json:"resolution"`
+	Assignee   string `json:"assignee"`
+	Comment    string `json:"comment"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// swagger:parameters UpdateAlert
+type AlertUpdateBody struct {
+	// in:body
+	Status string `json:"status"`
+	// in:body
+	Resolution string `json:"resolution"`
+	// in:body
+	Assignee string `json:"assignee"`
+	// in:body
+	Comment string `json:"comment"`
+}
+
+// swagger:route GET /monitoring/alerts monitoring FindAlerts
+// Lists transaction monitoring alerts in status given by query parameter, open alerts by default.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *MonitoringHandlerV1) FindAlerts(ctx *fasthttp.RequestCtx) {
+	status := domain.AlertStatusOpen
+	if ctx.QueryArgs().Has("status") {
+		var err error
+		status, err = alertStatusFromRequest(string(ctx.QueryArgs().Peek("status")))
+		if err != nil {
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+			return
+		}
+	}
+
+	alerts, err := h.useCase.FindAlerts(status)
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("error while find alerts. status: %s, error: %s", status, err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			fasthttp.StatusMessage(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromAlerts(alerts))
+}
+
+// swagger:route GET /monitoring/alerts/{id} monitoring FindAlert
+// Finds transaction monitoring alert.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *MonitoringHandlerV1) FindAlert(ctx *fasthttp.RequestCtx) {
+	alertID := ctx.UserValue(AlertIdUrlPath)
+	if _, ok := alertID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	alert, err := h.useCase.FindAlert(alertID.(string))
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("error while find alert. alertID: %s, error: %s", alertID, err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			fasthttp.StatusMessage(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+		return
+	}
+	if alert == nil {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusNotFound), fasthttp.StatusNotFound)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromAlert(alert))
+}
+
+// swagger:route PUT /monitoring/alerts/{id} monitoring UpdateAlert
+// Assigns alert to analyst, moves it to investigation or closes it with resolution.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *MonitoringHandlerV1) UpdateAlert(ctx *fasthttp.RequestCtx) {
+	alertID := ctx.UserValue(AlertIdUrlPath)
+	if _, ok := alertID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &AlertUpdateBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	status, resolution, err := alertUpdateFromRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.UpdateAlert(alertID.(string), status, resolution, request.Assignee, request.Comment)
+	if err != nil {
+		switch err.(type) {
+		case *domain.NotFoundError:
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+		case *domain.ValidationError:
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+		default:
+			h.logger.Error(
+				fmt.Sprintf("error while update alert. request: %s, error: %s", ctx.PostBody(), err.Error()),
+			)
+			h.responseWriter.WriteError(
+				ctx,
+				http.StatusText(fasthttp.StatusInternalServerError),
+				fasthttp.StatusInternalServerError,
+			)
+		}
+		return
+	}
+	h.responseWriter.WriteSuccessPUT(ctx)
+}
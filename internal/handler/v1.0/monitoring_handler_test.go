@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"net"
+	"testing"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/monitoring"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestUpdateAlert(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name              string
+		currentStatus     domain.AlertStatus
+		input             []byte
+		expectedAlert     domain.AlertStatus
+		expectedStatus    int
+		expectedResult    string
+		repositoryUpdates int
+	}{
+		{
+			"Investigate",
+			domain.AlertStatusOpen,
+			[]byte(`{"status": "investigating", "assignee": "alfred"}`),
+			domain.AlertStatusInvestigating,
+			fasthttp.StatusOK,
+			"",
+			1,
+		},
+		{
+			"Close",
+			domain.AlertStatusInvestigating,
+			[]byte(`{"status": "closed", "resolution": "false_positive", "assignee": "alfred", "comment": "salary"}`),
+			domain.AlertStatusClosed,
+			fasthttp.StatusOK,
+			"",
+			1,
+		},
+		{
+			"CloseWithoutResolution",
+			domain.AlertStatusInvestigating,
+			[]byte(`{"status": "closed", "assignee": "alfred"}`),
+			domain.AlertStatusInvestigating,
+			fasthttp.StatusConflict,
+			`{"error":{"status":409,"message":"resolution is mandatory for closed alert"}}`,
+			0,
+		},
+		{
+			"Reopen",
+			domain.AlertStatusClosed,
+			[]byte(`{"status": "open", "assignee": "alfred"}`),
+			domain.AlertStatusClosed,
+			fasthttp.StatusConflict,
+			`{"error":{"status":409,"message":"alert could not be moved from closed to open"}}`,
+			0,
+		},
+		{
+			"UnknownStatus",
+			domain.AlertStatusOpen,
+			[]byte(`{"status": "done", "assignee": "alfred"}`),
+			domain.AlertStatusOpen,
+			fasthttp.StatusBadRequest,
+			`{"error":{"status":400,"message":"status should be one of open, investigating, closed"}}`,
+			0,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			alert := &domain.Alert{GeneratedID: "foobar", Status: test.currentStatus}
+			monitoringRepositoryMock := mocks.NewMockMonitoringRepository(ctrl)
+			monitoringRepositoryMock.EXPECT().FindAlertByID("foobar").AnyTimes().Return(alert, nil)
+			monitoringRepositoryMock.EXPECT().UpdateAlert(alert).Times(test.repositoryUpdates).Return(nil)
+			customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+
+			useCase := usecase.NewMonitoringUseCase(monitoringRepositoryMock, customerRepositoryMock, nil, nil)
+			logger, _ := zap.NewDevelopment()
+			writer := NewJSONResponseWriter(logger)
+			handlerV1 := NewMonitoringHandlerV1(logger, useCase, writer)
+
+			// arrange fake server
+			router := fasthttprouter.New()
+			router.PUT("/monitoring/alerts/:id", handlerV1.UpdateAlert)
+
+			listener := fasthttputil.NewInmemoryListener()
+
+			server := &fasthttp.Server{
+				Handler: router.Handler,
+			}
+			go func() {
+				_ = server.Serve(listener)
+			}()
+
+			client := fasthttp.Client{
+				Dial: func(addr string) (net.Conn, error) {
+					return listener.Dial()
+				},
+			}
+			request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+			defer func() {
+				fasthttp.ReleaseRequest(request)
+				fasthttp.ReleaseResponse(response)
+			}()
+
+			// act
+			request.Header.SetMethod(fasthttp.MethodPut)
+			request.SetBody(test.input)
+			request.SetRequestURI("/monitoring/alerts/foobar")
+			request.SetHost("localhost")
+
+			_ = client.Do(request, response)
+
+			// assert
+			assert.Equal(t, test.expectedStatus, response.Header.StatusCode())
+			assert.Equal(t, test.expectedResult, string(response.Body()))
+			assert.Equal(t, test.expectedAlert, alert.Status)
+		})
+	}
+}
+
+// newMonitoringUseCase builds monitoring use case with default rules where every customer is active and has
+// no movements yet, created movements are appended to movements unless it is nil
+func newMonitoringUseCase(ctrl *gomock.Controller, movements *[]*domain.Movement) *usecase.MonitoringUseCase {
+	ruleSet, err := monitoring.LoadRuleSet("../../../configs/monitoring_rules.yaml")
+	if err != nil {
+		ctrl.T.Fatalf("unable to load monitoring rules: %s", err.Error())
+	}
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().
+		FindByID(gomock.Any()).
+		DoAndReturn(func(customerID string) (*domain.Customer, error) {
+			return &domain.Customer{GeneratedID: customerID, Status: domain.CustomerStatusActive}, nil
+		}).
+		AnyTimes()
+	monitoringRepositoryMock := mocks.NewMockMonitoringRepository(ctrl)
+	monitoringRepositoryMock.EXPECT().FindMovements(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	monitoringRepositoryMock.EXPECT().
+		CreateMovement(gomock.Any()).
+		DoAndReturn(func(movement *domain.Movement) error {
+			if movements != nil {
+				*movements = append(*movements, movement)
+			}
+			return nil
+		}).
+		AnyTimes()
+	monitoringRepositoryMock.EXPECT().CreateAlerts(gomock.Any()).Return(nil).AnyTimes()
+	logger, _ := zap.NewDevelopment()
+	return usecase.NewMonitoringUseCase(monitoringRepositoryMock, customerRepositoryMock, ruleSet, logger)
+}
@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"net"
+	"unicode/utf8"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/phone"
+)
+
+// maxP2PCommentLength is a length limit of transfer comment shown to recipient
+const maxP2PCommentLength = 140
+
+func p2pTransferFromRequest(senderID string, request *P2PTransferRequestBody) (*domain.P2PTransfer, error) {
+	var phoneNumber string
+	switch {
+	case request.BeneficiaryID != "" && request.Phone != "":
+		return nil, domain.NewValidationError("either phone or beneficiary_id should be set")
+	case request.BeneficiaryID == "":
+		var err error
+		phoneNumber, err = phone.Normalize(request.Phone)
+		if err != nil {
+			return nil, domain.NewValidationError(err.Error())
+		}
+	}
+	if request.Amount <= 0 {
+		return nil, domain.NewValidationError("amount should be positive")
+	}
+	if !currencyRegexp.MatchString(request.Currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	if utf8.RuneCountInString(request.Comment) > maxP2PCommentLength {
+		return nil, domain.NewValidationError("comment should be 140 characters at most")
+	}
+	if request.IP != "" && net.ParseIP(request.IP) == nil {
+		return nil, domain.NewValidationError("ip is not valid IP address")
+	}
+	return &domain.P2PTransfer{
+		SenderID:       senderID,
+		RecipientPhone: phoneNumber,
+		Amount:         request.Amount,
+		Currency:       request.Currency,
+		Comment:        request.Comment,
+		BeneficiaryID:  request.BeneficiaryID,
+		DeviceID:       request.DeviceID,
+		IP:             request.IP,
+	}, nil
+}
+
+func responseFromP2PTransfer(transfer *domain.P2PTransfer) *P2PTransferBody {
+	return &P2PTransferBody{
+		TransferID:          transfer.GeneratedID,
+		SenderID:            transfer.SenderID,
+		RecipientPhone:      transfer.RecipientPhone,
+		RecipientMaskedName: transfer.RecipientMaskedName,
+		Amount:              transfer.Amount,
+		Currency:            transfer.Currency,
+		Comment:             transfer.Comment,
+		BeneficiaryID:       transfer.BeneficiaryID,
+		RiskAssessmentID:    transfer.RiskAssessmentID,
+		RiskDecision:        string(transfer.RiskDecision),
+		CreatedAt:           transfer.CreatedAt.Format(domain.DateTimeFormat),
+	}
+}
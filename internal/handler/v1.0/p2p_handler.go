@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/phone"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const (
+	P2PTransferIdUrlPath = "id"
+	PhoneQueryArg        = "phone"
+)
+
+type P2PHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.P2PUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewP2PHandlerV1(
+	logger *zap.Logger,
+	p2pService *usecase.P2PUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *P2PHandlerV1 {
+	return &P2PHandlerV1{logger: logger, useCase: p2pService, responseWriter: responseWriter}
+}
+
+// swagger:parameters CreateP2PTransfer
+type P2PTransferRequestBody struct {
+	// phone of recipient, confirmed by sender with masked name of recipient,
+	// large transfers by phone are allowed to recipients saved as trusted beneficiaries only
+	// in:body
+	Phone string `json:"phone"`
+	// saved beneficiary of sender paid instead of phone
+	// in:body
+	BeneficiaryID string `json:"beneficiary_id"`
+	// amount in minor currency units
+	// in:body
+	Amount int64 `json:"amount"`
+	// in:body
+	Currency string `json:"currency"`
+	// in:body
+	Comment string `json:"comment"`
+	// device of sender scored by risk assessment
+	// in:body
+	DeviceID string `json:"device_id"`
+	// IP address of sender scored by risk assessment
+	// in:body
+	IP string `json:"ip"`
+}
+
+type P2PRecipientBody struct {
+	Phone      string `json:"phone"`
+	MaskedName string `json:"masked_name"`
+}
+
+type P2PTransferBody struct {
+	TransferID          string `json:"transfer_id"`
+	SenderID            string `json:"sender_id"`
+	RecipientPhone      string `json:"recipient_phone"`
+	RecipientMaskedName string `json:"recipient_masked_name"`
+	Amount              int64  `json:"amount"`
+	Currency            string `json:"currency"`
+	Comment             string `json:"comment,omitempty"`
+	BeneficiaryID       string `json:"beneficiary_id,omitempty"`
+	RiskAssessmentID    string `json:"risk_assessment_id"`
+	// risk decision, approve or review
+	RiskDecision string `json:"risk_decision"`
+	CreatedAt    string `json:"created_at"`
+}
+
+// swagger:route GET /customer/{id}/p2p/recipient p2p FindP2PRecipient
+// Finds recipient of transfer by phone query argument and shows masked name for confirmation.
+// Customer could look up limited number of distinct phones, so that phones could not be enumerated.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  429: ErrorResponse
+//  500: ErrorResponse
+func (h *P2PHandlerV1) FindRecipient(ctx *fasthttp.RequestCtx) {
+	senderID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := senderID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	phoneNumber, err := phone.Normalize(string(ctx.QueryArgs().Peek(PhoneQueryArg)))
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	recipient, err := h.useCase.FindRecipient(senderID.(string), phoneNumber)
+	if err != nil {
+		h.writeP2PError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, &P2PRecipientBody{Phone: recipient.Phone, MaskedName: recipient.MaskedName})
+}
+
+// swagger:route POST /customer/{id}/p2p-transfers p2p CreateP2PTransfer
+// Sends money of customer to another customer by phone or to saved beneficiary, large transfers to beneficiary
+// are refused within its cooling-off period. Large transfers by phone are refused unless recipient is saved
+// as beneficiary and its cooling-off period is over. Transfer is scored by fraud risk assessment and refused
+// when declined.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  422: ErrorResponse
+//  429: ErrorResponse
+//  500: ErrorResponse
+func (h *P2PHandlerV1) Transfer(ctx *fasthttp.RequestCtx) {
+	senderID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := senderID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &P2PTransferRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	transfer, err := p2pTransferFromRequest(senderID.(string), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Transfer(transfer)
+	if err != nil {
+		h.writeP2PError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromP2PTransfer(transfer))
+}
+
+// swagger:route GET /p2p-transfers/{id} p2p FindP2PTransfer
+// Shows p2p transfer.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *P2PHandlerV1) FindTransfer(ctx *fasthttp.RequestCtx) {
+	transferID := ctx.UserValue(P2PTransferIdUrlPath)
+	if _, ok := transferID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	transfer, err := h.useCase.FindTransfer(transferID.(string))
+	if err != nil {
+		h.writeP2PError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromP2PTransfer(transfer))
+}
+
+func (h *P2PHandlerV1) writeP2PError(ctx *fasthttp.RequestCtx, err error) {
+	switch err := err.(type) {
+	case *domain.LimitExceededError:
+		writeLimitExceeded(h.responseWriter, ctx, err)
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	case *domain.TooManyRequestsError:
+		ctx.Response.Header.Set("Retry-After", strconv.Itoa(int(err.RetryAfter.Seconds())))
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusTooManyRequests)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process p2p transfer. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
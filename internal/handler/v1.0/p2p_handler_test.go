@@ -0,0 +1,396 @@
+package v1
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/beneficiary"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/p2p"
+	"github.com/yaroslavnayug/go-payment-system/internal/risk"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestFindP2PRecipient_Success(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("sender").Return(&domain.Customer{
+		GeneratedID: "sender",
+		Phone:       "+79931234567",
+		Status:      domain.CustomerStatusActive,
+	}, nil)
+	customerRepositoryMock.EXPECT().FindByPhone("+79931234568").Return(&domain.Customer{
+		GeneratedID: "recipient",
+		FirstName:   "Bruce",
+		LastName:    "Wayne",
+		Phone:       "+79931234568",
+		Status:      domain.CustomerStatusActive,
+	}, nil)
+	p2pRepositoryMock := mocks.NewMockP2PRepository(ctrl)
+	p2pRepositoryMock.EXPECT().CountLookedUpPhones("sender", "+79931234568", gomock.Any()).Times(2).Return(3, nil)
+	p2pRepositoryMock.EXPECT().CreatePhoneLookup(gomock.Any()).DoAndReturn(func(lookup *domain.PhoneLookup) error {
+		assert.True(t, lookup.Found)
+		return nil
+	})
+
+	useCase := usecase.NewP2PUseCase(
+		p2pRepositoryMock,
+		customerRepositoryMock,
+		mocks.NewMockBeneficiaryRepository(ctrl),
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+		p2p.DefaultLookupPolicy,
+		beneficiary.DefaultCoolingOffPolicy,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewP2PHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.GET("/customer/:id/p2p/recipient", handlerV1.FindRecipient)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/sender/p2p/recipient?phone=8%20(993)%20123-45-68")
+	request.Header.SetMethod(fasthttp.MethodGet)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusOK, response.Header.StatusCode())
+	assert.JSONEq(t, `{"phone": "+79931234568", "masked_name": "Bruce W."}`, string(response.Body()))
+}
+
+func TestFindP2PRecipient_TooManyLookups(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("sender").Return(&domain.Customer{
+		GeneratedID: "sender",
+		Phone:       "+79931234567",
+		Status:      domain.CustomerStatusActive,
+	}, nil)
+	p2pRepositoryMock := mocks.NewMockP2PRepository(ctrl)
+	p2pRepositoryMock.EXPECT().CountLookedUpPhones("sender", "+79931234568", gomock.Any()).Return(10, nil)
+
+	useCase := usecase.NewP2PUseCase(
+		p2pRepositoryMock,
+		customerRepositoryMock,
+		mocks.NewMockBeneficiaryRepository(ctrl),
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+		p2p.LookupPolicy{Limits: []p2p.LookupLimit{{Window: time.Hour, Phones: 10}}},
+		beneficiary.DefaultCoolingOffPolicy,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewP2PHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.GET("/customer/:id/p2p/recipient", handlerV1.FindRecipient)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/sender/p2p/recipient?phone=%2B79931234568")
+	request.Header.SetMethod(fasthttp.MethodGet)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusTooManyRequests, response.Header.StatusCode())
+	assert.Equal(t, "3600", string(response.Header.Peek("Retry-After")))
+	assert.JSONEq(
+		t,
+		`{"error": {"status": 429, "message": "too many phones looked up, try again later"}}`,
+		string(response.Body()),
+	)
+}
+
+func TestCreateP2PTransfer_WrongPhone(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := usecase.NewP2PUseCase(
+		mocks.NewMockP2PRepository(ctrl),
+		mocks.NewMockCustomerRepository(ctrl),
+		mocks.NewMockBeneficiaryRepository(ctrl),
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+		p2p.DefaultLookupPolicy,
+		beneficiary.DefaultCoolingOffPolicy,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewP2PHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/p2p-transfers", handlerV1.Transfer)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/sender/p2p-transfers")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"phone": "123-45-68", "amount": 10000, "currency": "RUB"}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusBadRequest, response.Header.StatusCode())
+	assert.JSONEq(
+		t,
+		`{"error": {"status": 400, "message": "phone should start with country code"}}`,
+		string(response.Body()),
+	)
+}
+
+func TestCreateP2PTransfer_BeneficiaryCoolingOff(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	beneficiaryRepositoryMock := mocks.NewMockBeneficiaryRepository(ctrl)
+	beneficiaryRepositoryMock.EXPECT().FindByID("beneficiary").Return(&domain.Beneficiary{
+		GeneratedID: "beneficiary",
+		CustomerID:  "sender",
+		Type:        domain.BeneficiaryTypePhone,
+		Nickname:    "Bruce",
+		Phone:       "+79931234568",
+		TrustedAt:   time.Now().Add(time.Hour),
+	}, nil)
+
+	useCase := usecase.NewP2PUseCase(
+		mocks.NewMockP2PRepository(ctrl),
+		mocks.NewMockCustomerRepository(ctrl),
+		beneficiaryRepositoryMock,
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+		p2p.DefaultLookupPolicy,
+		beneficiary.DefaultCoolingOffPolicy,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewP2PHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/p2p-transfers", handlerV1.Transfer)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/sender/p2p-transfers")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"beneficiary_id": "beneficiary", "amount": 2000000, "currency": "RUB"}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.Contains(t, string(response.Body()), "beneficiary is added recently, transfers above 1500000 RUB are allowed")
+}
+
+func TestCreateP2PTransfer_PhoneCoolingOff(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		savedByPhone    *domain.Beneficiary
+		expectedMessage string
+	}{
+		{
+			name:            "NotSaved",
+			expectedMessage: "payee is not saved as beneficiary, transfers above 1500000 RUB are allowed",
+		},
+		{
+			name: "SavedRecently",
+			savedByPhone: &domain.Beneficiary{
+				GeneratedID: "beneficiary",
+				CustomerID:  "sender",
+				Type:        domain.BeneficiaryTypePhone,
+				Phone:       "+79931234568",
+				TrustedAt:   time.Now().Add(time.Hour),
+			},
+			expectedMessage: "beneficiary is added recently, transfers above 1500000 RUB are allowed",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+			customerRepositoryMock.EXPECT().FindByID("sender").Return(&domain.Customer{
+				GeneratedID: "sender",
+				Phone:       "+79931234567",
+				Status:      domain.CustomerStatusActive,
+			}, nil)
+			customerRepositoryMock.EXPECT().FindByPhone("+79931234568").Return(&domain.Customer{
+				GeneratedID: "recipient",
+				Phone:       "+79931234568",
+				Status:      domain.CustomerStatusActive,
+			}, nil)
+			p2pRepositoryMock := mocks.NewMockP2PRepository(ctrl)
+			p2pRepositoryMock.EXPECT().CountLookedUpPhones("sender", "+79931234568", gomock.Any()).Times(2).Return(0, nil)
+			p2pRepositoryMock.EXPECT().CreatePhoneLookup(gomock.Any()).Return(nil)
+			beneficiaryRepositoryMock := mocks.NewMockBeneficiaryRepository(ctrl)
+			beneficiaryRepositoryMock.EXPECT().FindByPayeeKey("sender", "phone:+79931234568").Return(test.savedByPhone, nil)
+			beneficiaryRepositoryMock.EXPECT().FindByPayeeKey("sender", "customer:recipient").Return(nil, nil)
+
+			useCase := usecase.NewP2PUseCase(
+				p2pRepositoryMock,
+				customerRepositoryMock,
+				beneficiaryRepositoryMock,
+				newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+				newRiskUseCase(ctrl, risk.DefaultThresholds),
+				p2p.DefaultLookupPolicy,
+				beneficiary.DefaultCoolingOffPolicy,
+			)
+			logger, _ := zap.NewDevelopment()
+			writer := NewJSONResponseWriter(logger)
+			handlerV1 := NewP2PHandlerV1(logger, useCase, writer)
+
+			// arrange fake server
+			router := fasthttprouter.New()
+			router.POST("/customer/:id/p2p-transfers", handlerV1.Transfer)
+
+			listener := fasthttputil.NewInmemoryListener()
+
+			server := &fasthttp.Server{
+				Handler: router.Handler,
+			}
+			go func() {
+				_ = server.Serve(listener)
+			}()
+
+			client := fasthttp.Client{
+				Dial: func(addr string) (net.Conn, error) {
+					return listener.Dial()
+				},
+			}
+			request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+			defer func() {
+				fasthttp.ReleaseRequest(request)
+				fasthttp.ReleaseResponse(response)
+			}()
+
+			// act
+			request.SetRequestURI("/customer/sender/p2p-transfers")
+			request.Header.SetMethod(fasthttp.MethodPost)
+			request.SetBodyString(`{"phone": "+79931234568", "amount": 2000000, "currency": "RUB"}`)
+			request.SetHost("localhost")
+
+			_ = client.Do(request, response)
+
+			// assert
+			assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+			assert.Contains(t, string(response.Body()), test.expectedMessage)
+		})
+	}
+}
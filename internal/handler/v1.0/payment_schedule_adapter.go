@@ -0,0 +1,102 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func paymentScheduleFromRequest(
+	request *PaymentScheduleRequestBody,
+	customerID string,
+) (*domain.PaymentSchedule, error) {
+	if request.PayeeID == "" {
+		return nil, domain.NewValidationError("payee_id is mandatory field")
+	}
+	if request.Amount <= 0 {
+		return nil, domain.NewValidationError("amount should be positive")
+	}
+	if !currencyRegexp.MatchString(request.Currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	if request.Recurrence == "" {
+		return nil, domain.NewValidationError("recurrence is mandatory field")
+	}
+	if request.Timezone == "" {
+		request.Timezone = "UTC"
+	}
+	location, err := time.LoadLocation(request.Timezone)
+	if err != nil {
+		return nil, domain.NewValidationError("unknown timezone")
+	}
+
+	schedule := &domain.PaymentSchedule{
+		CustomerID: customerID,
+		PayeeID:    request.PayeeID,
+		Amount:     request.Amount,
+		Currency:   request.Currency,
+		Recurrence: request.Recurrence,
+		Timezone:   request.Timezone,
+	}
+	// start and end are wall clock times in schedule timezone
+	if request.StartAt != "" {
+		schedule.StartAt, err = time.ParseInLocation(domain.DateTimeFormat, request.StartAt, location)
+		if err != nil {
+			return nil, domain.NewValidationError("wrong start_at format")
+		}
+	}
+	if request.EndAt != "" {
+		schedule.EndAt, err = time.ParseInLocation(domain.DateTimeFormat, request.EndAt, location)
+		if err != nil {
+			return nil, domain.NewValidationError("wrong end_at format")
+		}
+		if !schedule.StartAt.IsZero() && !schedule.EndAt.After(schedule.StartAt) {
+			return nil, domain.NewValidationError("end_at should be after start_at")
+		}
+	}
+	return schedule, nil
+}
+
+func responseFromPaymentSchedule(schedule *domain.PaymentSchedule) *PaymentScheduleBody {
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	body := &PaymentScheduleBody{
+		ScheduleID: schedule.GeneratedID,
+		CustomerID: schedule.CustomerID,
+		PayeeID:    schedule.PayeeID,
+		Amount:     schedule.Amount,
+		Currency:   schedule.Currency,
+		Recurrence: schedule.Recurrence,
+		Timezone:   schedule.Timezone,
+		StartAt:    schedule.StartAt.In(location).Format(domain.DateTimeFormat),
+		Status:     string(schedule.Status),
+		CreatedAt:  schedule.CreatedAt.Format(domain.DateTimeFormat),
+		UpdatedAt:  schedule.UpdatedAt.Format(domain.DateTimeFormat),
+	}
+	if !schedule.EndAt.IsZero() {
+		body.EndAt = schedule.EndAt.In(location).Format(domain.DateTimeFormat)
+	}
+	if schedule.Status == domain.PaymentScheduleStatusActive {
+		body.NextRunAt = schedule.NextRunAt.In(location).Format(domain.DateTimeFormat)
+	}
+	return body
+}
+
+func responseFromScheduleExecution(execution *domain.ScheduleExecution) *ScheduleExecutionBody {
+	body := &ScheduleExecutionBody{
+		ExecutionID: execution.GeneratedID,
+		ScheduleID:  execution.ScheduleID,
+		DueAt:       execution.DueAt.Format(domain.DateTimeFormat),
+		Status:      string(execution.Status),
+		Attempts:    execution.Attempts,
+		LastError:   execution.LastError,
+		CreatedAt:   execution.CreatedAt.Format(domain.DateTimeFormat),
+		UpdatedAt:   execution.UpdatedAt.Format(domain.DateTimeFormat),
+	}
+	if execution.Status == domain.ScheduleExecutionStatusPending {
+		body.NextAttemptAt = execution.NextAttemptAt.Format(domain.DateTimeFormat)
+	}
+	return body
+}
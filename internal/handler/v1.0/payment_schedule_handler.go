@@ -0,0 +1,213 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const PaymentScheduleIdUrlPath = "id"
+
+type PaymentScheduleHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.PaymentScheduleUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewPaymentScheduleHandlerV1(
+	logger *zap.Logger,
+	paymentScheduleService *usecase.PaymentScheduleUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *PaymentScheduleHandlerV1 {
+	return &PaymentScheduleHandlerV1{logger: logger, useCase: paymentScheduleService, responseWriter: responseWriter}
+}
+
+// swagger:parameters CreatePaymentSchedule
+type PaymentScheduleRequestBody struct {
+	// in:body
+	PayeeID string `json:"payee_id"`
+	// in:body
+	Amount int64 `json:"amount"`
+	// in:body
+	Currency string `json:"currency"`
+	// RRULE subset: FREQ=DAILY|WEEKLY|MONTHLY with optional INTERVAL, BYDAY and BYMONTHDAY
+	// in:body
+	Recurrence string `json:"recurrence"`
+	// IANA timezone name, UTC by default
+	// in:body
+	Timezone string `json:"timezone"`
+	// in:body
+	StartAt string `json:"start_at"`
+	// in:body
+	EndAt string `json:"end_at"`
+}
+
+type PaymentSchedulesBody struct {
+	Schedules []*PaymentScheduleBody `json:"schedules"`
+}
+
+type PaymentScheduleBody struct {
+	ScheduleID string `json:"schedule_id"`
+	CustomerID string `json:"customer_id"`
+	PayeeID    string `json:"payee_id"`
+	Amount     int64  `json:"amount"`
+	Currency   string `json:"currency"`
+	Recurrence string `json:"recurrence"`
+	Timezone   string `json:"timezone"`
+	StartAt    string `json:"start_at"`
+	EndAt      string `json:"end_at,omitempty"`
+	NextRunAt  string `json:"next_run_at,omitempty"`
+	Status     string `json:"status"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+type ScheduleExecutionsBody struct {
+	Executions []*ScheduleExecutionBody `json:"executions"`
+}
+
+type ScheduleExecutionBody struct {
+	ExecutionID   string `json:"execution_id"`
+	ScheduleID    string `json:"schedule_id"`
+	DueAt         string `json:"due_at"`
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	LastError     string `json:"last_error,omitempty"`
+	NextAttemptAt string `json:"next_attempt_at,omitempty"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// swagger:route POST /customer/{id}/schedules schedules CreatePaymentSchedule
+// Creates standing order which pays fixed amount to payee on recurrence in given timezone.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *PaymentScheduleHandlerV1) Create(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &PaymentScheduleRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	schedule, err := paymentScheduleFromRequest(request, customerID.(string))
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Create(schedule)
+	if err != nil {
+		h.writePaymentScheduleError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromPaymentSchedule(schedule))
+}
+
+// swagger:route GET /customer/{id}/schedules schedules FindPaymentSchedules
+// Lists standing orders of customer.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *PaymentScheduleHandlerV1) FindByCustomer(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	schedules, err := h.useCase.FindByCustomer(customerID.(string))
+	if err != nil {
+		h.writePaymentScheduleError(ctx, err)
+		return
+	}
+
+	response := &PaymentSchedulesBody{Schedules: make([]*PaymentScheduleBody, 0, len(schedules))}
+	for _, schedule := range schedules {
+		response.Schedules = append(response.Schedules, responseFromPaymentSchedule(schedule))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route DELETE /schedules/{id} schedules CancelPaymentSchedule
+// Cancels standing order. Pending executions of cancelled schedule are not retried.
+// responses:
+//  204:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *PaymentScheduleHandlerV1) Cancel(ctx *fasthttp.RequestCtx) {
+	scheduleID := ctx.UserValue(PaymentScheduleIdUrlPath)
+	if _, ok := scheduleID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err := h.useCase.Cancel(scheduleID.(string))
+	if err != nil {
+		h.writePaymentScheduleError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessDELETE(ctx)
+}
+
+// swagger:route GET /schedules/{id}/executions schedules FindScheduleExecutions
+// Shows execution history of standing order, the latest first.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *PaymentScheduleHandlerV1) FindExecutions(ctx *fasthttp.RequestCtx) {
+	scheduleID := ctx.UserValue(PaymentScheduleIdUrlPath)
+	if _, ok := scheduleID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	executions, err := h.useCase.Executions(scheduleID.(string))
+	if err != nil {
+		h.writePaymentScheduleError(ctx, err)
+		return
+	}
+
+	response := &ScheduleExecutionsBody{Executions: make([]*ScheduleExecutionBody, 0, len(executions))}
+	for _, execution := range executions {
+		response.Executions = append(response.Executions, responseFromScheduleExecution(execution))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+func (h *PaymentScheduleHandlerV1) writePaymentScheduleError(ctx *fasthttp.RequestCtx, err error) {
+	switch err.(type) {
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process schedule. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
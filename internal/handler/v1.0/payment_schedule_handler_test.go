@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestCreatePaymentSchedule(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("foobar").Return(&domain.Customer{GeneratedID: "foobar"}, nil)
+	customerRepositoryMock.EXPECT().FindByID("payee").Return(&domain.Customer{GeneratedID: "payee"}, nil)
+	repositoryMock := mocks.NewMockPaymentScheduleRepository(ctrl)
+	repositoryMock.EXPECT().Create(gomock.Any()).Return(nil)
+
+	useCase := usecase.NewPaymentScheduleUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewPaymentScheduleHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/schedules", handlerV1.Create)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	startAt := time.Now().AddDate(0, 1, 0)
+	request.SetRequestURI("/customer/foobar/schedules")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBody([]byte(`{
+		"payee_id": "payee",
+		"amount": 150000,
+		"currency": "RUB",
+		"recurrence": "FREQ=MONTHLY;BYMONTHDAY=31",
+		"timezone": "Europe/Moscow",
+		"start_at": "01-` + startAt.Format("01-2006") + ` 09:00:00"
+	}`))
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	body := &PaymentScheduleBody{}
+	err := json.Unmarshal(response.Body(), body)
+	assert.NoError(t, err)
+	assert.Equal(t, "active", body.Status)
+	assert.Equal(t, "01-"+startAt.Format("01-2006")+" 09:00:00", body.StartAt)
+	lastDay := time.Date(startAt.Year(), startAt.Month()+1, 0, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, lastDay.Format(domain.DateTimeFormat), body.NextRunAt)
+}
+
+func TestCancelPaymentSchedule_AlreadyFinished(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	repositoryMock := mocks.NewMockPaymentScheduleRepository(ctrl)
+	repositoryMock.EXPECT().
+		FindByID("schedule").
+		Return(&domain.PaymentSchedule{GeneratedID: "schedule", Status: domain.PaymentScheduleStatusFinished}, nil)
+
+	useCase := usecase.NewPaymentScheduleUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewPaymentScheduleHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.DELETE("/schedules/:id", handlerV1.Cancel)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/schedules/schedule")
+	request.Header.SetMethod(fasthttp.MethodDelete)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.Equal(t, `{"error":{"status":409,"message":"schedule is already finished"}}`, string(response.Body()))
+}
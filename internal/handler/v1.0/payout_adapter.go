@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"net"
+	"unicode/utf8"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/iso20022"
+)
+
+// maxPayoutTextLength is a length limit of creditor name and remittance information in pain.001
+const maxPayoutTextLength = 140
+
+func payoutFromRequest(customerID string, request *PayoutRequestBody) (*domain.Payout, error) {
+	if request.Amount <= 0 {
+		return nil, domain.NewValidationError("amount should be positive")
+	}
+	if !currencyRegexp.MatchString(request.Currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	if request.BeneficiaryID != "" {
+		if request.CreditorName != "" || request.CreditorIBAN != "" || request.CreditorBIC != "" {
+			return nil, domain.NewValidationError("creditor should not be set for payout to beneficiary_id")
+		}
+	} else {
+		if request.CreditorName == "" || utf8.RuneCountInString(request.CreditorName) > maxPayoutTextLength {
+			return nil, domain.NewValidationError("creditor_name is mandatory field of 140 characters at most")
+		}
+		if !iso20022.ValidIBAN(request.CreditorIBAN) {
+			return nil, domain.NewValidationError("creditor_iban should be valid IBAN without spaces")
+		}
+		if request.CreditorBIC != "" && !iso20022.ValidBIC(request.CreditorBIC) {
+			return nil, domain.NewValidationError("creditor_bic should be 8 or 11 characters long BIC")
+		}
+	}
+	if utf8.RuneCountInString(request.RemittanceInfo) > maxPayoutTextLength {
+		return nil, domain.NewValidationError("remittance_info should be 140 characters at most")
+	}
+	if request.IP != "" && net.ParseIP(request.IP) == nil {
+		return nil, domain.NewValidationError("ip is not valid IP address")
+	}
+	return &domain.Payout{
+		CustomerID:     customerID,
+		Amount:         request.Amount,
+		Currency:       request.Currency,
+		CreditorName:   request.CreditorName,
+		CreditorIBAN:   request.CreditorIBAN,
+		CreditorBIC:    request.CreditorBIC,
+		RemittanceInfo: request.RemittanceInfo,
+		BeneficiaryID:  request.BeneficiaryID,
+		DeviceID:       request.DeviceID,
+		IP:             request.IP,
+	}, nil
+}
+
+func responseFromPayout(payout *domain.Payout) *PayoutBody {
+	return &PayoutBody{
+		PayoutID:         payout.GeneratedID,
+		CustomerID:       payout.CustomerID,
+		Amount:           payout.Amount,
+		Currency:         payout.Currency,
+		CreditorName:     payout.CreditorName,
+		CreditorIBAN:     payout.CreditorIBAN,
+		CreditorBIC:      payout.CreditorBIC,
+		RemittanceInfo:   payout.RemittanceInfo,
+		Status:           string(payout.Status),
+		BatchID:          payout.BatchID,
+		RejectReason:     payout.RejectReason,
+		BeneficiaryID:    payout.BeneficiaryID,
+		RiskAssessmentID: payout.RiskAssessmentID,
+		RiskDecision:     string(payout.RiskDecision),
+		CreatedAt:        payout.CreatedAt.Format(domain.DateTimeFormat),
+		UpdatedAt:        payout.UpdatedAt.Format(domain.DateTimeFormat),
+	}
+}
+
+func responseFromPayouts(payouts []*domain.Payout) *PayoutsBody {
+	response := &PayoutsBody{Payouts: make([]*PayoutBody, 0, len(payouts))}
+	for _, payout := range payouts {
+		response.Payouts = append(response.Payouts, responseFromPayout(payout))
+	}
+	return response
+}
+
+func responseFromPayoutStatusReport(report *domain.PayoutStatusReport) *PayoutStatusReportBody {
+	return &PayoutStatusReportBody{BatchID: report.BatchID, Settled: report.Settled, Rejected: report.Rejected}
+}
@@ -0,0 +1,238 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/iso20022"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const (
+	PayoutIdUrlPath      = "id"
+	PayoutBatchIdUrlPath = "id"
+	ContentTypeXML       = "application/xml"
+)
+
+type PayoutHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.PayoutUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewPayoutHandlerV1(
+	logger *zap.Logger,
+	payoutService *usecase.PayoutUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *PayoutHandlerV1 {
+	return &PayoutHandlerV1{logger: logger, useCase: payoutService, responseWriter: responseWriter}
+}
+
+// swagger:parameters CreatePayout
+type PayoutRequestBody struct {
+	// amount in minor currency units
+	// in:body
+	Amount int64 `json:"amount"`
+	// in:body
+	Currency string `json:"currency"`
+	// saved bank account beneficiary of customer paid instead of creditor given in request
+	// in:body
+	BeneficiaryID string `json:"beneficiary_id"`
+	// in:body
+	CreditorName string `json:"creditor_name"`
+	// in:body
+	CreditorIBAN string `json:"creditor_iban"`
+	// BIC of creditor bank, optional
+	// in:body
+	CreditorBIC string `json:"creditor_bic"`
+	// in:body
+	RemittanceInfo string `json:"remittance_info"`
+	// device of customer scored by risk assessment
+	// in:body
+	DeviceID string `json:"device_id"`
+	// IP address of customer scored by risk assessment
+	// in:body
+	IP string `json:"ip"`
+}
+
+type PayoutsBody struct {
+	Payouts []*PayoutBody `json:"payouts"`
+}
+
+type PayoutBody struct {
+	PayoutID       string `json:"payout_id"`
+	CustomerID     string `json:"customer_id"`
+	Amount         int64  `json:"amount"`
+	Currency       string `json:"currency"`
+	CreditorName   string `json:"creditor_name"`
+	CreditorIBAN   string `json:"creditor_iban"`
+	CreditorBIC    string `json:"creditor_bic,omitempty"`
+	RemittanceInfo string `json:"remittance_info,omitempty"`
+	Status         string `json:"status"`
+	BatchID        string `json:"batch_id,omitempty"`
+	RejectReason   string `json:"reject_reason,omitempty"`
+	BeneficiaryID  string `json:"beneficiary_id,omitempty"`
+	// risk assessment made before payout, decision is approve or review
+	RiskAssessmentID string `json:"risk_assessment_id"`
+	RiskDecision     string `json:"risk_decision"`
+	CreatedAt        string `json:"created_at"`
+	UpdatedAt        string `json:"updated_at"`
+}
+
+type PayoutStatusReportBody struct {
+	BatchID  string `json:"batch_id"`
+	Settled  int    `json:"settled"`
+	Rejected int    `json:"rejected"`
+}
+
+// swagger:route POST /customer/{id}/payouts payouts CreatePayout
+// Creates pending payout to external bank account or saved bank account beneficiary, it is submitted to bank
+// with the next pain.001 file. Large payouts to beneficiary are refused within its cooling-off period.
+// Large payouts by creditor details are refused unless the IBAN is saved as beneficiary and its cooling-off
+// period is over. Payout is scored by fraud risk assessment and refused when declined.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  422: ErrorResponse
+//  500: ErrorResponse
+func (h *PayoutHandlerV1) Create(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &PayoutRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	payout, err := payoutFromRequest(customerID.(string), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Create(payout)
+	if err != nil {
+		h.writePayoutError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromPayout(payout))
+}
+
+// swagger:route GET /payouts/{id} payouts FindPayout
+// Finds payout.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *PayoutHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	payoutID := ctx.UserValue(PayoutIdUrlPath)
+	if _, ok := payoutID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	payout, err := h.useCase.Find(payoutID.(string))
+	if err != nil {
+		h.writePayoutError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromPayout(payout))
+}
+
+// swagger:route GET /customer/{id}/payouts payouts FindCustomerPayouts
+// Lists payouts of customer.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *PayoutHandlerV1) FindByCustomer(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	payouts, err := h.useCase.FindByCustomer(customerID.(string))
+	if err != nil {
+		h.writePayoutError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromPayouts(payouts))
+}
+
+// swagger:route GET /payout-batches/{id} payouts FindPayoutBatch
+// Downloads pain.001 credit transfer initiation file of payout batch.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *PayoutHandlerV1) FindBatch(ctx *fasthttp.RequestCtx) {
+	batchID := ctx.UserValue(PayoutBatchIdUrlPath)
+	if _, ok := batchID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	batch, err := h.useCase.FindBatch(batchID.(string))
+	if err != nil {
+		h.writePayoutError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessFile(ctx, ContentTypeXML, "pain001-"+batch.GeneratedID+".xml", batch.Document)
+}
+
+// swagger:route POST /payouts/status-reports payouts ApplyPayoutStatusReport
+// Ingests ISO 20022 pain.002 payment status report sent as request body, settles or rejects submitted payouts
+// of reported batch. Intermediate statuses are ignored.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *PayoutHandlerV1) ApplyStatusReport(ctx *fasthttp.RequestCtx) {
+	batchID, updates, err := iso20022.ParsePain002(bytes.NewReader(ctx.PostBody()))
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	report, err := h.useCase.ApplyStatusReport(batchID, updates)
+	if err != nil {
+		h.writePayoutError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromPayoutStatusReport(report))
+}
+
+func (h *PayoutHandlerV1) writePayoutError(ctx *fasthttp.RequestCtx, err error) {
+	switch typedErr := err.(type) {
+	case *domain.LimitExceededError:
+		writeLimitExceeded(h.responseWriter, ctx, typedErr)
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process payout. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,486 @@
+package v1
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/beneficiary"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/iso20022"
+	"github.com/yaroslavnayug/go-payment-system/internal/risk"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestCreatePayout_WrongIBAN(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := usecase.NewPayoutUseCase(
+		mocks.NewMockPayoutRepository(ctrl),
+		mocks.NewMockCustomerRepository(ctrl),
+		mocks.NewMockBeneficiaryRepository(ctrl),
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+		beneficiary.DefaultCoolingOffPolicy,
+		iso20022.Party{},
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewPayoutHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/payouts", handlerV1.Create)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/foobar/payouts")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{
+		"amount": 10000,
+		"currency": "EUR",
+		"creditor_name": "Ivan Ivanov",
+		"creditor_iban": "DE89370400440532013001"
+	}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusBadRequest, response.Header.StatusCode())
+	assert.JSONEq(
+		t,
+		`{"error": {"status": 400, "message": "creditor_iban should be valid IBAN without spaces"}}`,
+		string(response.Body()),
+	)
+}
+
+func TestCreatePayout_Risk(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		thresholds       risk.Thresholds
+		payoutsCreated   int
+		expectedStatus   int
+		expectedDecision string
+	}{
+		{"Review", risk.Thresholds{Review: 0, Decline: 101}, 1, fasthttp.StatusCreated, "review"},
+		{"Declined", risk.Thresholds{Review: 0, Decline: 0}, 0, fasthttp.StatusConflict, ""},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+			customerRepositoryMock.EXPECT().
+				FindByID("foobar").
+				Return(&domain.Customer{GeneratedID: "foobar", Status: domain.CustomerStatusActive}, nil)
+			payoutRepositoryMock := mocks.NewMockPayoutRepository(ctrl)
+			payoutRepositoryMock.EXPECT().Create(gomock.Any(), gomock.Any()).Times(test.payoutsCreated).Return(nil)
+
+			useCase := usecase.NewPayoutUseCase(
+				payoutRepositoryMock,
+				customerRepositoryMock,
+				mocks.NewMockBeneficiaryRepository(ctrl),
+				newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+				newRiskUseCase(ctrl, test.thresholds),
+				beneficiary.DefaultCoolingOffPolicy,
+				iso20022.Party{},
+			)
+			logger, _ := zap.NewDevelopment()
+			writer := NewJSONResponseWriter(logger)
+			handlerV1 := NewPayoutHandlerV1(logger, useCase, writer)
+
+			// arrange fake server
+			router := fasthttprouter.New()
+			router.POST("/customer/:id/payouts", handlerV1.Create)
+
+			listener := fasthttputil.NewInmemoryListener()
+
+			server := &fasthttp.Server{
+				Handler: router.Handler,
+			}
+			go func() {
+				_ = server.Serve(listener)
+			}()
+
+			client := fasthttp.Client{
+				Dial: func(addr string) (net.Conn, error) {
+					return listener.Dial()
+				},
+			}
+			request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+			defer func() {
+				fasthttp.ReleaseRequest(request)
+				fasthttp.ReleaseResponse(response)
+			}()
+
+			// act
+			request.SetRequestURI("/customer/foobar/payouts")
+			request.Header.SetMethod(fasthttp.MethodPost)
+			request.SetBodyString(`{
+				"amount": 10000,
+				"currency": "EUR",
+				"creditor_name": "Ivan Ivanov",
+				"creditor_iban": "DE89370400440532013000",
+				"device_id": "phone",
+				"ip": "10.0.0.1"
+			}`)
+			request.SetHost("localhost")
+
+			_ = client.Do(request, response)
+
+			// assert
+			assert.Equal(t, test.expectedStatus, response.Header.StatusCode())
+			body := &PayoutBody{}
+			_ = json.Unmarshal(response.Body(), body)
+			assert.Equal(t, test.expectedDecision, body.RiskDecision)
+		})
+	}
+}
+
+func TestCreatePayout_LegalEntity(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	company := &domain.Customer{
+		GeneratedID: "company",
+		Type:        domain.CustomerTypeLegalEntity,
+		Status:      domain.CustomerStatusActive,
+		Company: domain.Company{
+			Name: "OOO Romashka",
+			Representatives: []domain.Representative{
+				{CustomerID: "accountant", Position: "Chief accountant"},
+				{CustomerID: "ceo", Position: "CEO"},
+			},
+		},
+	}
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("company").Return(company, nil).AnyTimes()
+	verificationRepositoryMock := mocks.NewMockVerificationRepository(ctrl)
+	verificationRepositoryMock.EXPECT().FindLastByCustomerID("accountant").Return(nil, nil).AnyTimes()
+	verificationRepositoryMock.EXPECT().
+		FindLastByCustomerID("ceo").
+		Return(&domain.Verification{Status: domain.VerificationStatusApproved, Level: domain.VerificationLevelBasic}, nil).
+		AnyTimes()
+	tierLimits := highLimits
+	limitRepositoryMock := mocks.NewMockLimitRepository(ctrl)
+	limitRepositoryMock.EXPECT().FindCustomerLimits("company", "EUR").Return(nil, nil)
+	limitRepositoryMock.EXPECT().FindTierLimits(domain.VerificationLevelBasic, "EUR").Return(&tierLimits, nil)
+	var debit *domain.Debit
+	payoutRepositoryMock := mocks.NewMockPayoutRepository(ctrl)
+	payoutRepositoryMock.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ *domain.Payout, payoutDebit *domain.Debit) error {
+			debit = payoutDebit
+			return nil
+		})
+
+	useCase := usecase.NewPayoutUseCase(
+		payoutRepositoryMock,
+		customerRepositoryMock,
+		mocks.NewMockBeneficiaryRepository(ctrl),
+		usecase.NewLedgerUseCase(
+			mocks.NewMockLedgerRepository(ctrl),
+			usecase.NewVerificationUseCase(verificationRepositoryMock, customerRepositoryMock, nil),
+			usecase.NewLimitUseCase(limitRepositoryMock, verificationRepositoryMock, customerRepositoryMock),
+			newFeeUseCase(ctrl, nil),
+			newMonitoringUseCase(ctrl, nil),
+		),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+		beneficiary.DefaultCoolingOffPolicy,
+		iso20022.Party{},
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewPayoutHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/payouts", handlerV1.Create)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/company/payouts")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{
+		"amount": 10000,
+		"currency": "EUR",
+		"creditor_name": "Ivan Ivanov",
+		"creditor_iban": "DE89370400440532013000"
+	}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	if assert.NotNil(t, debit) {
+		assert.Equal(t, "company", debit.PayerID)
+		assert.Equal(t, int64(-10000), debit.Postings[0].Amount)
+		assert.Equal(t, highLimits, debit.Limit.Limits)
+	}
+}
+
+func TestApplyPayoutStatusReport(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payoutRepositoryMock := mocks.NewMockPayoutRepository(ctrl)
+	payoutRepositoryMock.EXPECT().FindBatchByID("batch").Return(&domain.PayoutBatch{GeneratedID: "batch"}, nil)
+	var refund *domain.Debit
+	gomock.InOrder(
+		payoutRepositoryMock.EXPECT().
+			UpdateSubmitted(&domain.PayoutStatusUpdate{
+				BatchID:       "batch",
+				PaymentInfoID: "batch-EUR",
+				PayoutID:      "payout_1",
+				Status:        domain.PayoutStatusRejected,
+				Reason:        "AC04",
+			}, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(
+				_ *domain.PayoutStatusUpdate,
+				_ time.Time,
+				refundPayout func(payout *domain.Payout) (*domain.Debit, error),
+			) (int, error) {
+				var err error
+				refund, err = refundPayout(&domain.Payout{
+					GeneratedID: "payout_1",
+					CustomerID:  "customer",
+					Amount:      10000,
+					Currency:    "EUR",
+				})
+				return 1, err
+			}),
+		payoutRepositoryMock.EXPECT().
+			UpdateSubmitted(
+				&domain.PayoutStatusUpdate{BatchID: "batch", Status: domain.PayoutStatusSettled},
+				gomock.Any(),
+				gomock.Any(),
+			).
+			Return(2, nil),
+	)
+
+	useCase := usecase.NewPayoutUseCase(
+		payoutRepositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		mocks.NewMockBeneficiaryRepository(ctrl),
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+		beneficiary.DefaultCoolingOffPolicy,
+		iso20022.Party{},
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewPayoutHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/payouts/status-reports", handlerV1.ApplyStatusReport)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/payouts/status-reports")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.Header.SetContentType(ContentTypeXML)
+	request.SetBodyString(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.002.001.10"><CstmrPmtStsRpt>
+		<OrgnlGrpInfAndSts><OrgnlMsgId>batch</OrgnlMsgId><GrpSts>ACSC</GrpSts></OrgnlGrpInfAndSts>
+		<OrgnlPmtInfAndSts>
+			<OrgnlPmtInfId>batch-EUR</OrgnlPmtInfId>
+			<TxInfAndSts>
+				<OrgnlEndToEndId>payout_1</OrgnlEndToEndId>
+				<TxSts>RJCT</TxSts>
+				<StsRsnInf><Rsn><Cd>AC04</Cd></Rsn></StsRsnInf>
+			</TxInfAndSts>
+		</OrgnlPmtInfAndSts>
+	</CstmrPmtStsRpt></Document>`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusOK, response.Header.StatusCode())
+	assert.JSONEq(t, `{"batch_id": "batch", "settled": 2, "rejected": 1}`, string(response.Body()))
+	assert.Equal(t, domain.LedgerAccountPayouts, refund.PayerID)
+	assert.Equal(t, "customer", refund.PayeeID)
+	assert.Equal(t, int64(10000), refund.Amount)
+	assert.Equal(t, "payout:payout_1:refund", refund.Reference)
+	assert.Len(t, refund.Postings, 2)
+}
+
+func TestCreatePayout_IBANCoolingOff(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		saved           *domain.Beneficiary
+		expectedMessage string
+	}{
+		{
+			name:            "NotSaved",
+			expectedMessage: "payee is not saved as beneficiary, transfers above 20000 EUR are allowed",
+		},
+		{
+			name: "SavedRecently",
+			saved: &domain.Beneficiary{
+				GeneratedID: "beneficiary",
+				CustomerID:  "foobar",
+				Type:        domain.BeneficiaryTypeBankAccount,
+				IBAN:        "DE89370400440532013000",
+				TrustedAt:   time.Now().Add(time.Hour),
+			},
+			expectedMessage: "beneficiary is added recently, transfers above 20000 EUR are allowed",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+			customerRepositoryMock.EXPECT().
+				FindByID("foobar").
+				Return(&domain.Customer{GeneratedID: "foobar", Status: domain.CustomerStatusActive}, nil)
+			beneficiaryRepositoryMock := mocks.NewMockBeneficiaryRepository(ctrl)
+			beneficiaryRepositoryMock.EXPECT().
+				FindByPayeeKey("foobar", "bank_account:DE89370400440532013000").
+				Return(test.saved, nil)
+
+			useCase := usecase.NewPayoutUseCase(
+				mocks.NewMockPayoutRepository(ctrl),
+				customerRepositoryMock,
+				beneficiaryRepositoryMock,
+				newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+				newRiskUseCase(ctrl, risk.DefaultThresholds),
+				beneficiary.DefaultCoolingOffPolicy,
+				iso20022.Party{},
+			)
+			logger, _ := zap.NewDevelopment()
+			writer := NewJSONResponseWriter(logger)
+			handlerV1 := NewPayoutHandlerV1(logger, useCase, writer)
+
+			// arrange fake server
+			router := fasthttprouter.New()
+			router.POST("/customer/:id/payouts", handlerV1.Create)
+
+			listener := fasthttputil.NewInmemoryListener()
+
+			server := &fasthttp.Server{
+				Handler: router.Handler,
+			}
+			go func() {
+				_ = server.Serve(listener)
+			}()
+
+			client := fasthttp.Client{
+				Dial: func(addr string) (net.Conn, error) {
+					return listener.Dial()
+				},
+			}
+			request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+			defer func() {
+				fasthttp.ReleaseRequest(request)
+				fasthttp.ReleaseResponse(response)
+			}()
+
+			// act
+			request.SetRequestURI("/customer/foobar/payouts")
+			request.Header.SetMethod(fasthttp.MethodPost)
+			request.SetBodyString(`{
+				"amount": 50000,
+				"currency": "EUR",
+				"creditor_name": "Ivan Ivanov",
+				"creditor_iban": "DE89370400440532013000"
+			}`)
+			request.SetHost("localhost")
+
+			_ = client.Do(request, response)
+
+			// assert
+			assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+			assert.Contains(t, string(response.Body()), test.expectedMessage)
+		})
+	}
+}
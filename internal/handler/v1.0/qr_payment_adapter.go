@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/sbp"
+)
+
+// qrPaymentCurrency is the only currency of SBP payments
+const qrPaymentCurrency = "RUB"
+
+func qrPaymentRequestFromRequest(
+	merchantID string,
+	request *QRPaymentRequestRequestBody,
+) (*domain.QRPaymentRequest, error) {
+	requestType := domain.QRPaymentRequestType(request.Type)
+	if requestType != domain.QRPaymentRequestTypeStatic && requestType != domain.QRPaymentRequestTypeDynamic {
+		return nil, domain.NewValidationError("type should be one of static, dynamic")
+	}
+	if request.Amount < 0 || requestType == domain.QRPaymentRequestTypeDynamic && request.Amount == 0 {
+		return nil, domain.NewValidationError("amount should be positive")
+	}
+	if request.Currency != qrPaymentCurrency {
+		return nil, domain.NewValidationError("currency should be RUB")
+	}
+	if utf8.RuneCountInString(request.Purpose) > sbp.MaxPurposeLength {
+		return nil, domain.NewValidationError("purpose should be 140 characters at most")
+	}
+
+	paymentRequest := &domain.QRPaymentRequest{
+		MerchantID: merchantID,
+		Type:       requestType,
+		Amount:     request.Amount,
+		Currency:   request.Currency,
+		Purpose:    request.Purpose,
+	}
+	if request.ExpiresAt != "" {
+		var err error
+		paymentRequest.ExpiresAt, err = time.Parse(domain.DateTimeFormat, request.ExpiresAt)
+		if err != nil {
+			return nil, domain.NewValidationError("wrong expires_at format")
+		}
+	}
+	return paymentRequest, nil
+}
+
+func responseFromQRPaymentRequest(request *domain.QRPaymentRequest) *QRPaymentRequestBody {
+	response := &QRPaymentRequestBody{
+		RequestID:  request.GeneratedID,
+		MerchantID: request.MerchantID,
+		Type:       string(request.Type),
+		Amount:     request.Amount,
+		Currency:   request.Currency,
+		Purpose:    request.Purpose,
+		Status:     string(request.Status),
+		Payload:    sbp.FormatPayload(request),
+		CreatedAt:  request.CreatedAt.Format(domain.DateTimeFormat),
+	}
+	if !request.ExpiresAt.IsZero() {
+		response.ExpiresAt = request.ExpiresAt.Format(domain.DateTimeFormat)
+	}
+	if !request.PaidAt.IsZero() {
+		response.PaidAt = request.PaidAt.Format(domain.DateTimeFormat)
+	}
+	return response
+}
+
+func responseFromQRPaymentRequests(requests []*domain.QRPaymentRequest) *QRPaymentRequestsBody {
+	response := &QRPaymentRequestsBody{Requests: make([]*QRPaymentRequestBody, 0, len(requests))}
+	for _, request := range requests {
+		response.Requests = append(response.Requests, responseFromQRPaymentRequest(request))
+	}
+	return response
+}
+
+func responseFromQRPayment(payment *domain.QRPayment) *QRPaymentBody {
+	return &QRPaymentBody{
+		PaymentID:        payment.GeneratedID,
+		RequestID:        payment.RequestID,
+		MerchantID:       payment.MerchantID,
+		PayerID:          payment.PayerID,
+		Amount:           payment.Amount,
+		Currency:         payment.Currency,
+		RiskAssessmentID: payment.RiskAssessmentID,
+		RiskDecision:     string(payment.RiskDecision),
+		PaidAt:           payment.PaidAt.Format(domain.DateTimeFormat),
+	}
+}
+
+func responseFromQRPayments(payments []*domain.QRPayment) *QRPaymentsBody {
+	response := &QRPaymentsBody{Payments: make([]*QRPaymentBody, 0, len(payments))}
+	for _, payment := range payments {
+		response.Payments = append(response.Payments, responseFromQRPayment(payment))
+	}
+	return response
+}
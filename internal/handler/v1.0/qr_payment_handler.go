@@ -0,0 +1,290 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/sbp"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const (
+	QRPaymentRequestIdUrlPath = "id"
+	ContentTypePNG            = "image/png"
+	ContentTypeSVG            = "image/svg+xml"
+	// pngSuffix and svgSuffix of qr payment request id in url ask for QR code image instead of JSON
+	pngSuffix = ".png"
+	svgSuffix = ".svg"
+)
+
+type QRPaymentHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.QRPaymentUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewQRPaymentHandlerV1(
+	logger *zap.Logger,
+	qrPaymentService *usecase.QRPaymentUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *QRPaymentHandlerV1 {
+	return &QRPaymentHandlerV1{logger: logger, useCase: qrPaymentService, responseWriter: responseWriter}
+}
+
+// swagger:parameters CreateQRPaymentRequest
+type QRPaymentRequestRequestBody struct {
+	// static or dynamic
+	// in:body
+	Type string `json:"type"`
+	// amount in minor currency units, optional for static request
+	// in:body
+	Amount int64 `json:"amount"`
+	// only RUB is supported
+	// in:body
+	Currency string `json:"currency"`
+	// in:body
+	Purpose string `json:"purpose"`
+	// format: 02-01-2006 15:04:05 in UTC, dynamic request expires in 72 hours by default
+	// in:body
+	ExpiresAt string `json:"expires_at"`
+}
+
+// swagger:parameters PayQRPaymentRequest
+type QRPayRequestBody struct {
+	// content of scanned QR code
+	// in:body
+	Payload string `json:"payload"`
+	// amount in minor currency units, only for static request without amount
+	// in:body
+	Amount int64 `json:"amount"`
+	// device of payer scored by risk assessment
+	// in:body
+	DeviceID string `json:"device_id"`
+	// IP address of payer scored by risk assessment
+	// in:body
+	IP string `json:"ip"`
+}
+
+type QRPaymentRequestsBody struct {
+	Requests []*QRPaymentRequestBody `json:"requests"`
+}
+
+type QRPaymentRequestBody struct {
+	RequestID  string `json:"request_id"`
+	MerchantID string `json:"merchant_id"`
+	Type       string `json:"type"`
+	Amount     int64  `json:"amount"`
+	Currency   string `json:"currency"`
+	Purpose    string `json:"purpose,omitempty"`
+	Status     string `json:"status"`
+	Payload    string `json:"payload"`
+	ExpiresAt  string `json:"expires_at,omitempty"`
+	PaidAt     string `json:"paid_at,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type QRPaymentsBody struct {
+	Payments []*QRPaymentBody `json:"payments"`
+}
+
+type QRPaymentBody struct {
+	PaymentID        string `json:"payment_id"`
+	RequestID        string `json:"request_id"`
+	MerchantID       string `json:"merchant_id"`
+	PayerID          string `json:"payer_id"`
+	Amount           int64  `json:"amount"`
+	Currency         string `json:"currency"`
+	RiskAssessmentID string `json:"risk_assessment_id"`
+	// risk decision, approve or review
+	RiskDecision string `json:"risk_decision"`
+	PaidAt       string `json:"paid_at"`
+}
+
+// swagger:route POST /customer/{id}/qr-payment-requests qr-payments CreateQRPaymentRequest
+// Creates SBP QR payment request of merchant. Static request is paid any number of times,
+// dynamic request is paid once.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *QRPaymentHandlerV1) CreateRequest(ctx *fasthttp.RequestCtx) {
+	merchantID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := merchantID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &QRPaymentRequestRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	paymentRequest, err := qrPaymentRequestFromRequest(merchantID.(string), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.CreateRequest(paymentRequest)
+	if err != nil {
+		h.writeQRPaymentError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromQRPaymentRequest(paymentRequest))
+}
+
+// swagger:route GET /customer/{id}/qr-payment-requests qr-payments FindMerchantQRPaymentRequests
+// Lists QR payment requests of merchant.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *QRPaymentHandlerV1) FindRequestsByMerchant(ctx *fasthttp.RequestCtx) {
+	merchantID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := merchantID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	requests, err := h.useCase.FindRequestsByMerchant(merchantID.(string))
+	if err != nil {
+		h.writeQRPaymentError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromQRPaymentRequests(requests))
+}
+
+// swagger:route GET /qr-payment-requests/{id} qr-payments FindQRPaymentRequest
+// Shows QR payment request with its payment status. Request id with .png or .svg suffix
+// like /qr-payment-requests/{id}.svg downloads QR code of request payload as image.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *QRPaymentHandlerV1) FindRequest(ctx *fasthttp.RequestCtx) {
+	requestID := ctx.UserValue(QRPaymentRequestIdUrlPath)
+	if _, ok := requestID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	if strings.HasSuffix(requestID.(string), pngSuffix) {
+		requestID = strings.TrimSuffix(requestID.(string), pngSuffix)
+		image, err := h.useCase.RenderPNG(requestID.(string))
+		if err != nil {
+			h.writeQRPaymentError(ctx, err)
+			return
+		}
+		h.responseWriter.WriteSuccessFile(ctx, ContentTypePNG, requestID.(string)+pngSuffix, image)
+		return
+	}
+	if strings.HasSuffix(requestID.(string), svgSuffix) {
+		requestID = strings.TrimSuffix(requestID.(string), svgSuffix)
+		image, err := h.useCase.RenderSVG(requestID.(string))
+		if err != nil {
+			h.writeQRPaymentError(ctx, err)
+			return
+		}
+		h.responseWriter.WriteSuccessFile(ctx, ContentTypeSVG, requestID.(string)+svgSuffix, image)
+		return
+	}
+
+	request, err := h.useCase.FindRequest(requestID.(string))
+	if err != nil {
+		h.writeQRPaymentError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromQRPaymentRequest(request))
+}
+
+// swagger:route GET /qr-payment-requests/{id}/payments qr-payments FindQRPayments
+// Lists payments of QR payment request.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *QRPaymentHandlerV1) FindPayments(ctx *fasthttp.RequestCtx) {
+	requestID := ctx.UserValue(QRPaymentRequestIdUrlPath)
+	if _, ok := requestID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	payments, err := h.useCase.FindPayments(requestID.(string))
+	if err != nil {
+		h.writeQRPaymentError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromQRPayments(payments))
+}
+
+// swagger:route POST /customer/{id}/qr-payments qr-payments PayQRPaymentRequest
+// Pays QR payment request by payload of scanned QR code, customer is a payer.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  422: ErrorResponse
+//  500: ErrorResponse
+func (h *QRPaymentHandlerV1) Pay(ctx *fasthttp.RequestCtx) {
+	payerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := payerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &QRPayRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	payload, err := sbp.ParsePayload(request.Payload)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+	if request.IP != "" && net.ParseIP(request.IP) == nil {
+		h.responseWriter.WriteError(ctx, "ip is not valid IP address", fasthttp.StatusBadRequest)
+		return
+	}
+
+	payment, err := h.useCase.Pay(payerID.(string), payload, request.Amount, request.DeviceID, request.IP)
+	if err != nil {
+		h.writeQRPaymentError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromQRPayment(payment))
+}
+
+func (h *QRPaymentHandlerV1) writeQRPaymentError(ctx *fasthttp.RequestCtx, err error) {
+	switch typedErr := err.(type) {
+	case *domain.LimitExceededError:
+		writeLimitExceeded(h.responseWriter, ctx, typedErr)
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process qr payment. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,293 @@
+package v1
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/risk"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestCreateQRPaymentRequest_DynamicWithoutAmount(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := usecase.NewQRPaymentUseCase(
+		mocks.NewMockQRPaymentRepository(ctrl),
+		mocks.NewMockCustomerRepository(ctrl),
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewQRPaymentHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/qr-payment-requests", handlerV1.CreateRequest)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/merchant/qr-payment-requests")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"type": "dynamic", "currency": "RUB", "purpose": "Order 15"}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusBadRequest, response.Header.StatusCode())
+	assert.JSONEq(t, `{"error": {"status": 400, "message": "amount should be positive"}}`, string(response.Body()))
+}
+
+func TestFindQRPaymentRequest_SVG(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	repositoryMock := mocks.NewMockQRPaymentRepository(ctrl)
+	repositoryMock.EXPECT().
+		FindRequestByID("27771b5def0e30bd2ce5048e17032cab").
+		Return(&domain.QRPaymentRequest{
+			GeneratedID: "27771b5def0e30bd2ce5048e17032cab",
+			MerchantID:  "merchant",
+			Type:        domain.QRPaymentRequestTypeDynamic,
+			Amount:      150050,
+			Currency:    "RUB",
+			Status:      domain.QRPaymentRequestStatusActive,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}, nil)
+
+	useCase := usecase.NewQRPaymentUseCase(
+		repositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewQRPaymentHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.GET("/qr-payment-requests/:id", handlerV1.FindRequest)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/qr-payment-requests/27771b5def0e30bd2ce5048e17032cab.svg")
+	request.Header.SetMethod(fasthttp.MethodGet)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusOK, response.Header.StatusCode())
+	assert.Equal(t, ContentTypeSVG, string(response.Header.ContentType()))
+	assert.Equal(
+		t,
+		`attachment; filename="27771b5def0e30bd2ce5048e17032cab.svg"`,
+		string(response.Header.Peek("Content-Disposition")),
+	)
+	assert.True(t, bytes.HasPrefix(response.Body(), []byte(`<?xml version="1.0" encoding="UTF-8"?>`)))
+}
+
+func TestPayQRPaymentRequest_Expired(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("payer").Return(&domain.Customer{GeneratedID: "payer"}, nil)
+	repositoryMock := mocks.NewMockQRPaymentRepository(ctrl)
+	repositoryMock.EXPECT().
+		FindRequestByID("27771b5def0e30bd2ce5048e17032cab").
+		Return(&domain.QRPaymentRequest{
+			GeneratedID: "27771b5def0e30bd2ce5048e17032cab",
+			MerchantID:  "merchant",
+			Type:        domain.QRPaymentRequestTypeDynamic,
+			Amount:      150050,
+			Currency:    "RUB",
+			Status:      domain.QRPaymentRequestStatusActive,
+			ExpiresAt:   time.Now().Add(-time.Minute),
+		}, nil)
+
+	useCase := usecase.NewQRPaymentUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewQRPaymentHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/qr-payments", handlerV1.Pay)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/payer/qr-payments")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{
+		"payload": "https://qr.nspk.ru/27771B5DEF0E30BD2CE5048E17032CAB?type=02&sum=150050&cur=RUB"
+	}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.JSONEq(
+		t,
+		`{"error": {"status": 409, "message": "expired qr payment request could not be paid"}}`,
+		string(response.Body()),
+	)
+}
+
+func TestPayQRPaymentRequest_DeclinedByRisk(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("payer").Return(&domain.Customer{GeneratedID: "payer"}, nil)
+	repositoryMock := mocks.NewMockQRPaymentRepository(ctrl)
+	repositoryMock.EXPECT().
+		FindRequestByID("27771b5def0e30bd2ce5048e17032cab").
+		Return(&domain.QRPaymentRequest{
+			GeneratedID: "27771b5def0e30bd2ce5048e17032cab",
+			MerchantID:  "merchant",
+			Type:        domain.QRPaymentRequestTypeDynamic,
+			Amount:      150050,
+			Currency:    "RUB",
+			Status:      domain.QRPaymentRequestStatusActive,
+		}, nil)
+
+	useCase := usecase.NewQRPaymentUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.Thresholds{Review: 0, Decline: 0}),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewQRPaymentHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/qr-payments", handlerV1.Pay)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/payer/qr-payments")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{
+		"payload": "https://qr.nspk.ru/27771B5DEF0E30BD2CE5048E17032CAB?type=02&sum=150050&cur=RUB",
+		"device_id": "device",
+		"ip": "10.0.0.1"
+	}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.Contains(t, string(response.Body()), "payment is declined by risk assessment")
+}
@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/reconciliation"
+)
+
+func bankStatementFormatFromRequest(args *fasthttp.Args) (reconciliation.Format, error) {
+	switch format := reconciliation.Format(args.Peek("format")); format {
+	case "":
+		return reconciliation.FormatCamt053, nil
+	case reconciliation.FormatCamt053, reconciliation.FormatMT940:
+		return format, nil
+	default:
+		return "", domain.NewValidationError("format should be one of camt053, mt940")
+	}
+}
+
+// reconciliationPeriodFromRequest reads from and to dates of booking in UTC, to date is included
+func reconciliationPeriodFromRequest(args *fasthttp.Args) (from time.Time, to time.Time, err error) {
+	from, err = time.Parse(domain.DateFormat, string(args.Peek("from")))
+	if err != nil {
+		return from, to, domain.NewValidationError("wrong from format")
+	}
+	to, err = time.Parse(domain.DateFormat, string(args.Peek("to")))
+	if err != nil {
+		return from, to, domain.NewValidationError("wrong to format")
+	}
+	if to.Before(from) {
+		return from, to, domain.NewValidationError("to should not be before from")
+	}
+	return from, to, nil
+}
+
+func responseFromBankStatementImport(result *domain.BankStatementImport) *BankStatementImportBody {
+	response := &BankStatementImportBody{
+		Statements: make([]*BankStatementBody, 0, len(result.Statements)),
+		Duplicates: make([]string, 0, len(result.Duplicates)),
+		Matched:    result.Matched,
+	}
+	for _, statement := range result.Statements {
+		response.Statements = append(response.Statements, &BankStatementBody{
+			StatementID:     statement.GeneratedID,
+			BankStatementID: statement.BankStatementID,
+			AccountIBAN:     statement.AccountIBAN,
+			Currency:        statement.Currency,
+			OpeningBalance:  statement.OpeningBalance,
+			ClosingBalance:  statement.ClosingBalance,
+			Entries:         len(statement.Entries),
+		})
+	}
+	response.Duplicates = append(response.Duplicates, result.Duplicates...)
+	return response
+}
+
+func responseFromBankEntry(entry *domain.BankEntry) *BankEntryBody {
+	response := &BankEntryBody{
+		EntryID:                  entry.GeneratedID,
+		StatementID:              entry.StatementID,
+		Direction:                string(entry.Direction),
+		Amount:                   entry.Amount,
+		Currency:                 entry.Currency,
+		BookingDate:              entry.BookingDate.Format(domain.DateFormat),
+		ValueDate:                entry.ValueDate.Format(domain.DateFormat),
+		Reference:                entry.Reference,
+		AccountServicerReference: entry.AccountServicerReference,
+		RemittanceInfo:           entry.RemittanceInfo,
+		MovementID:               entry.MovementID,
+		MatchType:                string(entry.MatchType),
+	}
+	if !entry.MatchedAt.IsZero() {
+		response.MatchedAt = entry.MatchedAt.Format(domain.DateTimeFormat)
+	}
+	return response
+}
+
+func responseFromBankEntries(entries []*domain.BankEntry) []*BankEntryBody {
+	response := make([]*BankEntryBody, 0, len(entries))
+	for _, entry := range entries {
+		response = append(response, responseFromBankEntry(entry))
+	}
+	return response
+}
+
+func responseFromReconciliationReport(report *domain.ReconciliationReport) *ReconciliationReportBody {
+	response := &ReconciliationReportBody{
+		From:              report.From.Format(domain.DateFormat),
+		To:                report.To.Format(domain.DateFormat),
+		Matched:           responseFromBankEntries(report.Matched),
+		UnmatchedBank:     responseFromBankEntries(report.UnmatchedBank),
+		UnmatchedInternal: make([]*MovementBody, 0, len(report.UnmatchedInternal)),
+	}
+	for _, movement := range report.UnmatchedInternal {
+		response.UnmatchedInternal = append(response.UnmatchedInternal, &MovementBody{
+			MovementID: movement.GeneratedID,
+			CustomerID: movement.CustomerID,
+			Type:       string(movement.Type),
+			Amount:     movement.Amount,
+			Currency:   movement.Currency,
+			CreatedAt:  movement.CreatedAt.Format(domain.DateTimeFormat),
+		})
+	}
+	return response
+}
@@ -0,0 +1,215 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/reconciliation"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const BankEntryIdUrlPath = "id"
+
+type ReconciliationHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.ReconciliationUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewReconciliationHandlerV1(
+	logger *zap.Logger,
+	reconciliationService *usecase.ReconciliationUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *ReconciliationHandlerV1 {
+	return &ReconciliationHandlerV1{logger: logger, useCase: reconciliationService, responseWriter: responseWriter}
+}
+
+type BankStatementImportBody struct {
+	Statements []*BankStatementBody `json:"statements"`
+	// ids of statements imported before, their entries are skipped
+	Duplicates []string `json:"duplicates"`
+	// number of bank entries auto-matched to movements
+	Matched int `json:"matched"`
+}
+
+type BankStatementBody struct {
+	StatementID     string `json:"statement_id"`
+	BankStatementID string `json:"bank_statement_id"`
+	AccountIBAN     string `json:"account_iban"`
+	Currency        string `json:"currency"`
+	OpeningBalance  int64  `json:"opening_balance"`
+	ClosingBalance  int64  `json:"closing_balance"`
+	Entries         int    `json:"entries"`
+}
+
+type BankEntryBody struct {
+	EntryID                  string `json:"entry_id"`
+	StatementID              string `json:"statement_id"`
+	Direction                string `json:"direction"`
+	Amount                   int64  `json:"amount"`
+	Currency                 string `json:"currency"`
+	BookingDate              string `json:"booking_date"`
+	ValueDate                string `json:"value_date"`
+	Reference                string `json:"reference"`
+	AccountServicerReference string `json:"account_servicer_reference"`
+	RemittanceInfo           string `json:"remittance_info"`
+	MovementID               string `json:"movement_id"`
+	MatchType                string `json:"match_type"`
+	MatchedAt                string `json:"matched_at,omitempty"`
+}
+
+type MovementBody struct {
+	MovementID string `json:"movement_id"`
+	CustomerID string `json:"customer_id"`
+	Type       string `json:"type"`
+	Amount     int64  `json:"amount"`
+	Currency   string `json:"currency"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type ReconciliationReportBody struct {
+	From              string           `json:"from"`
+	To                string           `json:"to"`
+	Matched           []*BankEntryBody `json:"matched"`
+	UnmatchedBank     []*BankEntryBody `json:"unmatched_bank"`
+	UnmatchedInternal []*MovementBody  `json:"unmatched_internal"`
+}
+
+// swagger:parameters FindReconciliationReport
+type ReconciliationReportQuery struct {
+	// ISO 4217 code of settlement account currency
+	// in:query
+	Currency string `json:"currency"`
+	// first booking day in DD-MM-YYYY format
+	// in:query
+	From string `json:"from"`
+	// last booking day in DD-MM-YYYY format, included
+	// in:query
+	To string `json:"to"`
+}
+
+// swagger:parameters MatchBankEntry
+type BankEntryMatchBody struct {
+	// in:body
+	MovementID string `json:"movement_id"`
+}
+
+// swagger:parameters ImportBankStatements
+type BankStatementImportQuery struct {
+	// one of camt053, mt940, camt053 by default
+	// in:query
+	Format string `json:"format"`
+}
+
+// swagger:route POST /reconciliation/statements reconciliation ImportBankStatements
+// Imports ISO 20022 camt.053 document or SWIFT MT940 file sent as request body and auto-matches
+// its booked entries to movements. Statement imported before is reported as duplicate.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *ReconciliationHandlerV1) Import(ctx *fasthttp.RequestCtx) {
+	format, err := bankStatementFormatFromRequest(ctx.QueryArgs())
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	statements, err := reconciliation.ParseStatements(format, bytes.NewReader(ctx.PostBody()))
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	result, err := h.useCase.Import(statements)
+	if err != nil {
+		h.writeReconciliationError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromBankStatementImport(result))
+}
+
+// swagger:route GET /reconciliation/report reconciliation FindReconciliationReport
+// Lists matched and unmatched bank entries of settlement account in currency booked in period
+// and movements in currency which are not matched to any entry.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *ReconciliationHandlerV1) Report(ctx *fasthttp.RequestCtx) {
+	currency := string(ctx.QueryArgs().Peek("currency"))
+	if !currencyRegexp.MatchString(currency) {
+		h.responseWriter.WriteError(ctx, "currency should be ISO 4217 code", fasthttp.StatusBadRequest)
+		return
+	}
+	from, to, err := reconciliationPeriodFromRequest(ctx.QueryArgs())
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	report, err := h.useCase.Report(currency, from, to)
+	if err != nil {
+		h.writeReconciliationError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromReconciliationReport(report))
+}
+
+// swagger:route POST /reconciliation/entries/{id}/match reconciliation MatchBankEntry
+// Matches bank entry to movement of the same amount, currency and direction manually.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *ReconciliationHandlerV1) Match(ctx *fasthttp.RequestCtx) {
+	entryID := ctx.UserValue(BankEntryIdUrlPath)
+	if _, ok := entryID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &BankEntryMatchBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	if request.MovementID == "" {
+		h.responseWriter.WriteError(ctx, "movement_id is mandatory field", fasthttp.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.useCase.MatchManually(entryID.(string), request.MovementID)
+	if err != nil {
+		h.writeReconciliationError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromBankEntry(entry))
+}
+
+func (h *ReconciliationHandlerV1) writeReconciliationError(ctx *fasthttp.RequestCtx, err error) {
+	switch err.(type) {
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(
+			fmt.Sprintf("error while process reconciliation. uri: %s, error: %s", ctx.RequestURI(), err.Error()),
+		)
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
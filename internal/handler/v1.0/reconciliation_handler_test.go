@@ -0,0 +1,341 @@
+package v1
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/reconciliation"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+const settlementIBAN = "RU0204452560040702810412345678901"
+
+const camt053Document = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Id>STMT-1</Id>
+      <Acct><Id><IBAN>RU0204452560040702810412345678901</IBAN></Id><Ccy>RUB</Ccy></Acct>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="RUB">0.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Dt><Dt>2020-08-18</Dt></Dt>
+      </Bal>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="RUB">50.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Dt><Dt>2020-08-18</Dt></Dt>
+      </Bal>
+      <Ntry>
+        <Amt Ccy="RUB">50.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts>
+        <BookgDt><Dt>2020-08-18</Dt></BookgDt>
+        <NtryDtls><TxDtls><Refs><EndToEndId>movement_1</EndToEndId></Refs></TxDtls></NtryDtls>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+func TestImportBankStatements(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	day := time.Date(2020, 8, 18, 0, 0, 0, 0, time.UTC)
+	var entries []*domain.BankEntry
+	reconciliationRepositoryMock := mocks.NewMockReconciliationRepository(ctrl)
+	reconciliationRepositoryMock.EXPECT().
+		CreateStatement(gomock.Any()).
+		DoAndReturn(func(statement *domain.BankStatement) (bool, error) {
+			entries = statement.Entries
+			return true, nil
+		})
+	reconciliationRepositoryMock.EXPECT().
+		FindUnmatchedEntries(domain.BankAccount{IBAN: settlementIBAN, Currency: "RUB"}, day, day).
+		DoAndReturn(func(domain.BankAccount, time.Time, time.Time) ([]*domain.BankEntry, error) {
+			return entries, nil
+		})
+	reconciliationRepositoryMock.EXPECT().
+		FindUnmatchedMovements("RUB", day.Add(-reconciliation.DefaultDateTolerance), gomock.Any()).
+		Return([]*domain.Movement{{
+			GeneratedID: "movement_1",
+			Type:        domain.MovementTypeDeposit,
+			Amount:      5000,
+			Currency:    "RUB",
+			CreatedAt:   day.AddDate(0, 0, -1),
+		}}, nil)
+	reconciliationRepositoryMock.EXPECT().
+		Match(gomock.Any(), "movement_1", domain.BankEntryMatchTypeReference, gomock.Any()).
+		Return(true, nil)
+
+	useCase := usecase.NewReconciliationUseCase(
+		reconciliationRepositoryMock,
+		reconciliation.NewMatcher(reconciliation.DefaultDateTolerance),
+		settlementIBAN,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewReconciliationHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/reconciliation/statements", handlerV1.Import)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/reconciliation/statements")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.Header.SetContentType("application/xml")
+	request.SetBodyString(camt053Document)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	assert.JSONEq(t, `{
+		"statements": [{
+			"statement_id": "3fc678e5e0403a13e108188a2bc6d6fb",
+			"bank_statement_id": "STMT-1",
+			"account_iban": "RU0204452560040702810412345678901",
+			"currency": "RUB",
+			"opening_balance": 0,
+			"closing_balance": 5000,
+			"entries": 1
+		}],
+		"duplicates": [],
+		"matched": 1
+	}`, string(response.Body()))
+}
+
+func TestMatchBankEntry_DirectionDiffers(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reconciliationRepositoryMock := mocks.NewMockReconciliationRepository(ctrl)
+	reconciliationRepositoryMock.EXPECT().FindEntryByID("foobar").Return(&domain.BankEntry{
+		GeneratedID: "foobar",
+		Direction:   domain.BankEntryDirectionCredit,
+		Amount:      5000,
+		Currency:    "RUB",
+	}, nil)
+	reconciliationRepositoryMock.EXPECT().FindMovementByID("movement_1").Return(&domain.Movement{
+		GeneratedID: "movement_1",
+		Type:        domain.MovementTypeWithdrawal,
+		Amount:      5000,
+		Currency:    "RUB",
+	}, nil)
+
+	useCase := usecase.NewReconciliationUseCase(
+		reconciliationRepositoryMock,
+		reconciliation.NewMatcher(reconciliation.DefaultDateTolerance),
+		settlementIBAN,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewReconciliationHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/reconciliation/entries/:id/match", handlerV1.Match)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/reconciliation/entries/foobar/match")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{"movement_id": "movement_1"}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+}
+
+func TestImportBankStatements_MT940Duplicate(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	day := time.Date(2020, 8, 18, 0, 0, 0, 0, time.UTC)
+	reconciliationRepositoryMock := mocks.NewMockReconciliationRepository(ctrl)
+	reconciliationRepositoryMock.EXPECT().CreateStatement(gomock.Any()).Return(false, nil)
+	reconciliationRepositoryMock.EXPECT().
+		FindUnmatchedEntries(domain.BankAccount{IBAN: settlementIBAN, Currency: "RUB"}, day, day).
+		Return(nil, nil)
+
+	useCase := usecase.NewReconciliationUseCase(
+		reconciliationRepositoryMock,
+		reconciliation.NewMatcher(reconciliation.DefaultDateTolerance),
+		settlementIBAN,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewReconciliationHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/reconciliation/statements", handlerV1.Import)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/reconciliation/statements?format=mt940")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(strings.Join([]string{
+		":20:STMT-1",
+		":25:RU0204452560040702810412345678901",
+		":28C:1/1",
+		":60F:C200817RUB0,00",
+		":61:2008180818C50,00NTRFmovement_1",
+		":62F:C200818RUB50,00",
+		"-",
+	}, "\r\n"))
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	assert.JSONEq(t, `{
+		"statements": [{
+			"statement_id": "f5be16248ed5fe9434a76f285386702e",
+			"bank_statement_id": "STMT-1/1/1/2020-08-17",
+			"account_iban": "RU0204452560040702810412345678901",
+			"currency": "RUB",
+			"opening_balance": 0,
+			"closing_balance": 5000,
+			"entries": 1
+		}],
+		"duplicates": ["f5be16248ed5fe9434a76f285386702e"],
+		"matched": 0
+	}`, string(response.Body()))
+}
+
+func TestImportBankStatements_OtherAccount(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reconciliationRepositoryMock := mocks.NewMockReconciliationRepository(ctrl)
+	reconciliationRepositoryMock.EXPECT().CreateStatement(gomock.Any()).Return(true, nil)
+
+	useCase := usecase.NewReconciliationUseCase(
+		reconciliationRepositoryMock,
+		reconciliation.NewMatcher(reconciliation.DefaultDateTolerance),
+		"DE89370400440532013000",
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewReconciliationHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/reconciliation/statements", handlerV1.Import)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/reconciliation/statements")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.Header.SetContentType("application/xml")
+	request.SetBodyString(camt053Document)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	assert.Contains(t, string(response.Body()), `"matched":0`)
+}
@@ -1,8 +1,10 @@
 package v1
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
+	"io"
 
 	"github.com/valyala/fasthttp"
 	"go.uber.org/zap"
@@ -18,6 +20,9 @@ type ErrorResponse struct {
 type Error struct {
 	Status  int    `json:"status"`
 	Message string `json:"message"`
+	// Code is a machine readable error code, set only for errors which clients are expected to handle
+	Code    string      `json:"code,omitempty"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 type JSONResponseWriter struct {
@@ -62,11 +67,58 @@ func (w *JSONResponseWriter) WriteSuccessDELETE(ctx *fasthttp.RequestCtx) {
 	ctx.Response.SetStatusCode(fasthttp.StatusNoContent)
 }
 
-func (w *JSONResponseWriter) WriteError(ctx *fasthttp.RequestCtx, message string, code int) {
-	customError := Error{
-		Status:  code,
-		Message: message,
+// WriteSuccessFile responds with file which browser offers to save as fileName
+func (w *JSONResponseWriter) WriteSuccessFile(
+	ctx *fasthttp.RequestCtx,
+	contentType string,
+	fileName string,
+	body []byte,
+) {
+	ctx.Response.Header.SetContentType(contentType)
+	ctx.Response.Header.Set(fasthttp.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+	ctx.Response.SetStatusCode(fasthttp.StatusOK)
+	ctx.Response.SetBody(body)
+}
+
+// WriteSuccessStream responds with body written by write while response is sent, so body is not kept in memory.
+// Status is sent before body is complete, so error of write is only logged and body is cut.
+// Empty fileName shows body in browser instead of saving it.
+func (w *JSONResponseWriter) WriteSuccessStream(
+	ctx *fasthttp.RequestCtx,
+	contentType string,
+	fileName string,
+	write func(w io.Writer) error,
+) {
+	ctx.Response.Header.SetContentType(contentType)
+	if fileName != "" {
+		ctx.Response.Header.Set(fasthttp.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
 	}
+	ctx.Response.SetStatusCode(fasthttp.StatusOK)
+	uri := string(ctx.RequestURI())
+	ctx.SetBodyStreamWriter(func(writer *bufio.Writer) {
+		err := write(writer)
+		if err != nil {
+			w.logger.Error(fmt.Sprintf("error while stream response body. uri: %s, error: %s", uri, err.Error()))
+		}
+	})
+}
+
+func (w *JSONResponseWriter) WriteError(ctx *fasthttp.RequestCtx, message string, code int) {
+	w.writeError(ctx, Error{Status: code, Message: message})
+}
+
+func (w *JSONResponseWriter) WriteErrorWithDetails(
+	ctx *fasthttp.RequestCtx,
+	message string,
+	code int,
+	errorCode string,
+	details interface{},
+) {
+	w.writeError(ctx, Error{Status: code, Message: message, Code: errorCode, Details: details})
+}
+
+func (w *JSONResponseWriter) writeError(ctx *fasthttp.RequestCtx, customError Error) {
+	code := customError.Status
 	responseBody := &ErrorResponse{Error: customError}
 
 	response, err := json.Marshal(responseBody)
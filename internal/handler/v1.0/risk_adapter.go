@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"net"
+	"regexp"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+var currencyRegexp = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func validateRiskAssessmentRequest(request *RiskAssessmentRequestBody) error {
+	if request.Amount <= 0 {
+		return domain.NewValidationError("amount should be positive")
+	}
+	if !currencyRegexp.MatchString(request.Currency) {
+		return domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	if request.IP != "" && net.ParseIP(request.IP) == nil {
+		return domain.NewValidationError("ip is not valid IP address")
+	}
+	return nil
+}
+
+func responseFromRiskAssessment(assessment *domain.RiskAssessment) *RiskAssessmentBody {
+	features := make([]RiskFeatureBody, 0, len(assessment.Features))
+	for _, feature := range assessment.Features {
+		features = append(features, RiskFeatureBody{
+			Name:  feature.Name,
+			Value: feature.Value,
+			Score: feature.Score,
+		})
+	}
+	return &RiskAssessmentBody{
+		AssessmentID: assessment.GeneratedID,
+		CustomerID:   assessment.CustomerID,
+		Amount:       assessment.Amount,
+		Currency:     assessment.Currency,
+		DeviceID:     assessment.DeviceID,
+		IP:           assessment.IP,
+		Score:        assessment.Score,
+		Decision:     string(assessment.Decision),
+		Features:     features,
+		CreatedAt:    assessment.CreatedAt.Format(domain.DateTimeFormat),
+	}
+}
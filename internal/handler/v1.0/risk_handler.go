@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const RiskAssessmentIdUrlPath = "id"
+
+type RiskHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.RiskUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewRiskHandlerV1(
+	logger *zap.Logger,
+	riskService *usecase.RiskUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *RiskHandlerV1 {
+	return &RiskHandlerV1{logger: logger, useCase: riskService, responseWriter: responseWriter}
+}
+
+// swagger:parameters AssessRisk
+type RiskAssessmentRequestBody struct {
+	// in:body
+	Amount int64 `json:"amount"`
+	// in:body
+	Currency string `json:"currency"`
+	// in:body
+	DeviceID string `json:"device_id"`
+	// in:body
+	IP string `json:"ip"`
+}
+
+type RiskAssessmentBody struct {
+	AssessmentID string            `json:"assessment_id"`
+	CustomerID   string            `json:"customer_id"`
+	Amount       int64             `json:"amount"`
+	Currency     string            `json:"currency"`
+	DeviceID     string            `json:"device_id"`
+	IP           string            `json:"ip"`
+	Score        int               `json:"score"`
+	Decision     string            `json:"decision"`
+	Features     []RiskFeatureBody `json:"features"`
+	CreatedAt    string            `json:"created_at"`
+}
+
+type RiskFeatureBody struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Score int    `json:"score"`
+}
+
+// swagger:route POST /customer/{id}/risk risk AssessRisk
+// Scores payment fraud risk before authorization and returns approve, review or decline decision.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *RiskHandlerV1) Assess(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &RiskAssessmentRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = validateRiskAssessmentRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	assessment, err := h.useCase.Assess(
+		customerID.(string),
+		request.Amount,
+		request.Currency,
+		request.DeviceID,
+		request.IP,
+	)
+	if err != nil {
+		switch err.(type) {
+		case *domain.NotFoundError:
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+		default:
+			h.logger.Error(
+				fmt.Sprintf("error while assess risk. request: %s, error: %s", ctx.PostBody(), err.Error()),
+			)
+			h.responseWriter.WriteError(
+				ctx,
+				http.StatusText(fasthttp.StatusInternalServerError),
+				fasthttp.StatusInternalServerError,
+			)
+		}
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromRiskAssessment(assessment))
+}
+
+// swagger:route GET /risk/{id} risk FindRiskAssessment
+// Finds risk assessment with contributing features.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *RiskHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	assessmentID := ctx.UserValue(RiskAssessmentIdUrlPath)
+	if _, ok := assessmentID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	assessment, err := h.useCase.Find(assessmentID.(string))
+	if err != nil {
+		h.logger.Error(
+			fmt.Sprintf("error while find risk assessment. assessmentID: %s, error: %s", assessmentID, err.Error()),
+		)
+		h.responseWriter.WriteError(
+			ctx,
+			fasthttp.StatusMessage(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+		return
+	}
+	if assessment == nil {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusNotFound), fasthttp.StatusNotFound)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromRiskAssessment(assessment))
+}
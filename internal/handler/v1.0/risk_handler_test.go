@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/risk"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestAssessRisk(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		input            []byte
+		devices          []domain.RiskDevice
+		expectedStatus   int
+		expectedDecision string
+		expectedScore    int
+	}{
+		{
+			"KnownDevice",
+			[]byte(`{"amount": 100000, "currency": "RUB", "device_id": "phone", "ip": "10.0.0.1"}`),
+			[]domain.RiskDevice{{CustomerID: "foobar", DeviceID: "phone", IP: "10.0.0.1"}},
+			fasthttp.StatusCreated,
+			"approve",
+			10,
+		},
+		{
+			"NewDevice",
+			[]byte(`{"amount": 100000, "currency": "RUB", "device_id": "laptop", "ip": "10.0.0.2"}`),
+			[]domain.RiskDevice{{CustomerID: "foobar", DeviceID: "phone", IP: "10.0.0.1"}},
+			fasthttp.StatusCreated,
+			"review",
+			40,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			customer := &domain.Customer{
+				GeneratedID: "foobar",
+				Status:      domain.CustomerStatusActive,
+				Passport:    domain.Passport{BirthDate: time.Now().AddDate(-30, 0, 0)},
+				CreatedAt:   time.Now().AddDate(-1, 0, 0),
+			}
+			customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+			customerRepositoryMock.EXPECT().FindByID("foobar").Return(customer, nil)
+			riskRepositoryMock := mocks.NewMockRiskRepository(ctrl)
+			riskRepositoryMock.EXPECT().FindAssessments("foobar", gomock.Any()).Return(nil, nil)
+			riskRepositoryMock.EXPECT().FindDevices("foobar").Return(test.devices, nil)
+			riskRepositoryMock.EXPECT().CreateAssessment(gomock.Any()).Return(nil)
+			riskRepositoryMock.EXPECT().SaveDevice(gomock.Any()).Return(nil)
+
+			useCase := usecase.NewRiskUseCase(
+				riskRepositoryMock,
+				customerRepositoryMock,
+				risk.NewScorer(risk.DefaultThresholds),
+			)
+			logger, _ := zap.NewDevelopment()
+			writer := NewJSONResponseWriter(logger)
+			handlerV1 := NewRiskHandlerV1(logger, useCase, writer)
+
+			// arrange fake server
+			router := fasthttprouter.New()
+			router.POST("/customer/:id/risk", handlerV1.Assess)
+
+			listener := fasthttputil.NewInmemoryListener()
+
+			server := &fasthttp.Server{
+				Handler: router.Handler,
+			}
+			go func() {
+				_ = server.Serve(listener)
+			}()
+
+			client := fasthttp.Client{
+				Dial: func(addr string) (net.Conn, error) {
+					return listener.Dial()
+				},
+			}
+			request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+			defer func() {
+				fasthttp.ReleaseRequest(request)
+				fasthttp.ReleaseResponse(response)
+			}()
+
+			// act
+			request.SetRequestURI("/customer/foobar/risk")
+			request.Header.SetMethod(fasthttp.MethodPost)
+			request.SetBody(test.input)
+			request.SetHost("localhost")
+
+			_ = client.Do(request, response)
+
+			// assert
+			responseJSON := RiskAssessmentBody{}
+			_ = json.Unmarshal(response.Body(), &responseJSON)
+
+			assert.Equal(t, test.expectedStatus, response.Header.StatusCode())
+			assert.Equal(t, test.expectedDecision, responseJSON.Decision)
+			assert.Equal(t, test.expectedScore, responseJSON.Score)
+			assert.Len(t, responseJSON.Features, 7)
+		})
+	}
+}
+
+// newRiskUseCase builds risk use case where every customer is an active adult customer for a year without
+// previous payments and known devices, so payments are scored alike and decided by thresholds
+func newRiskUseCase(ctrl *gomock.Controller, thresholds risk.Thresholds) *usecase.RiskUseCase {
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().
+		FindByID(gomock.Any()).
+		DoAndReturn(func(customerID string) (*domain.Customer, error) {
+			return &domain.Customer{
+				GeneratedID: customerID,
+				Status:      domain.CustomerStatusActive,
+				Passport:    domain.Passport{BirthDate: time.Now().AddDate(-30, 0, 0)},
+				CreatedAt:   time.Now().AddDate(-1, 0, 0),
+			}, nil
+		}).
+		AnyTimes()
+	riskRepositoryMock := mocks.NewMockRiskRepository(ctrl)
+	riskRepositoryMock.EXPECT().FindAssessments(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	riskRepositoryMock.EXPECT().FindDevices(gomock.Any()).Return(nil, nil).AnyTimes()
+	riskRepositoryMock.EXPECT().CreateAssessment(gomock.Any()).Return(nil).AnyTimes()
+	riskRepositoryMock.EXPECT().SaveDevice(gomock.Any()).Return(nil).AnyTimes()
+	return usecase.NewRiskUseCase(riskRepositoryMock, customerRepositoryMock, risk.NewScorer(thresholds))
+}
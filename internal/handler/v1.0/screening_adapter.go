@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func decisionFromRequest(request *ScreeningDecisionBody) (domain.SanctionDecision, error) {
+	decision := domain.SanctionDecision(request.Decision)
+	if decision != domain.SanctionDecisionCleared && decision != domain.SanctionDecisionConfirmed {
+		return domain.SanctionDecisionNone, domain.NewValidationError("decision should be one of cleared, confirmed")
+	}
+	if request.Reviewer == "" {
+		return domain.SanctionDecisionNone, domain.NewValidationError("reviewer is mandatory field")
+	}
+	if request.Comment == "" {
+		return domain.SanctionDecisionNone, domain.NewValidationError("comment is mandatory field")
+	}
+	return decision, nil
+}
+
+func responseFromCustomers(customers []*domain.Customer) *ScreeningQueueBody {
+	response := &ScreeningQueueBody{Customers: make([]interface{}, 0, len(customers))}
+	for _, customer := range customers {
+		response.Customers = append(response.Customers, customerResponse(customer))
+	}
+	return response
+}
+
+func responseFromMatches(matches []domain.SanctionMatch) *SanctionMatchesBody {
+	response := &SanctionMatchesBody{Matches: make([]SanctionMatchBody, 0, len(matches))}
+	for _, match := range matches {
+		matchBody := SanctionMatchBody{
+			ListName:    match.ListName,
+			ListType:    string(match.ListType),
+			EntryName:   match.EntryName,
+			MatchedName: match.MatchedName,
+			Score:       match.Score,
+			Decision:    string(match.Decision),
+			Reviewer:    match.Reviewer,
+			Comment:     match.Comment,
+			CreatedAt:   match.CreatedAt.Format(domain.DateTimeFormat),
+		}
+		if !match.EntryBirthDate.IsZero() {
+			matchBody.EntryBirthDate = match.EntryBirthDate.Format(domain.DateFormat)
+		}
+		if !match.ReviewedAt.IsZero() {
+			matchBody.ReviewedAt = match.ReviewedAt.Format(domain.DateTimeFormat)
+		}
+		response.Matches = append(response.Matches, matchBody)
+	}
+	return response
+}
@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+type ScreeningHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.ScreeningUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewScreeningHandlerV1(
+	logger *zap.Logger,
+	screeningService *usecase.ScreeningUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *ScreeningHandlerV1 {
+	return &ScreeningHandlerV1{logger: logger, useCase: screeningService, responseWriter: responseWriter}
+}
+
+type ScreeningQueueBody struct {
+	// Customers are CustomerBody or LegalEntityBody depending on type
+	Customers []interface{} `json:"customers"`
+}
+
+type SanctionMatchesBody struct {
+	Matches []SanctionMatchBody `json:"matches"`
+}
+
+type SanctionMatchBody struct {
+	ListName       string  `json:"list_name"`
+	ListType       string  `json:"list_type"`
+	EntryName      string  `json:"entry_name"`
+	EntryBirthDate string  `json:"entry_birth_date"`
+	MatchedName    string  `json:"matched_name"`
+	Score          float64 `json:"score"`
+	Decision       string  `json:"decision"`
+	Reviewer       string  `json:"reviewer"`
+	Comment        string  `json:"comment"`
+	CreatedAt      string  `json:"created_at"`
+	ReviewedAt     string  `json:"reviewed_at"`
+}
+
+// swagger:parameters ResolveScreening
+type ScreeningDecisionBody struct {
+	// in:body
+	Decision string `json:"decision"`
+	// in:body
+	Reviewer string `json:"reviewer"`
+	// in:body
+	Comment string `json:"comment"`
+}
+
+// swagger:route GET /screening/queue screening ScreeningQueue
+// Lists customers waiting for manual review of sanctions and PEP matches.
+// responses:
+//  200:
+//  500: ErrorResponse
+func (h *ScreeningHandlerV1) Queue(ctx *fasthttp.RequestCtx) {
+	customers, err := h.useCase.Queue()
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("error while find screening queue. error: %s", err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			fasthttp.StatusMessage(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromCustomers(customers))
+}
+
+// swagger:route GET /customer/{id}/screening screening FindScreeningMatches
+// Finds all sanctions and PEP matches of customer.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *ScreeningHandlerV1) Matches(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	matches, err := h.useCase.Matches(customerID.(string))
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("error while find screening matches. customerID: %s, error: %s", customerID, err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			fasthttp.StatusMessage(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromMatches(matches))
+}
+
+// swagger:route PUT /customer/{id}/screening screening ResolveScreening
+// Clears customer or confirms sanctions and PEP matches.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *ScreeningHandlerV1) Resolve(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &ScreeningDecisionBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	decision, err := decisionFromRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Resolve(customerID.(string), decision, request.Reviewer, request.Comment)
+	if err != nil {
+		switch err.(type) {
+		case *domain.NotFoundError:
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+		case *domain.ValidationError:
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+		default:
+			h.logger.Error(
+				fmt.Sprintf("error while resolve screening. request: %s, error: %s", ctx.PostBody(), err.Error()),
+			)
+			h.responseWriter.WriteError(
+				ctx,
+				http.StatusText(fasthttp.StatusInternalServerError),
+				fasthttp.StatusInternalServerError,
+			)
+		}
+		return
+	}
+	h.responseWriter.WriteSuccessPUT(ctx)
+}
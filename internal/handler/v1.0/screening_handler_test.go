@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"net"
+	"testing"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestResolveScreening(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name              string
+		currentStatus     domain.CustomerStatus
+		input             []byte
+		expectedCustomer  domain.CustomerStatus
+		expectedStatus    int
+		expectedResult    string
+		repositoryUpdates int
+	}{
+		{
+			"Cleared",
+			domain.CustomerStatusPendingReview,
+			[]byte(`{"decision": "cleared", "reviewer": "alfred", "comment": "different birth place"}`),
+			domain.CustomerStatusActive,
+			fasthttp.StatusOK,
+			"",
+			1,
+		},
+		{
+			"Confirmed",
+			domain.CustomerStatusPendingReview,
+			[]byte(`{"decision": "confirmed", "reviewer": "alfred", "comment": "same person"}`),
+			domain.CustomerStatusBlocked,
+			fasthttp.StatusOK,
+			"",
+			1,
+		},
+		{
+			"NotInQueue",
+			domain.CustomerStatusActive,
+			[]byte(`{"decision": "cleared", "reviewer": "alfred", "comment": "different birth place"}`),
+			domain.CustomerStatusActive,
+			fasthttp.StatusConflict,
+			`{"error":{"status":409,"message":"customer is not waiting for review"}}`,
+			0,
+		},
+		{
+			"UnknownDecision",
+			domain.CustomerStatusPendingReview,
+			[]byte(`{"decision": "maybe", "reviewer": "alfred", "comment": "?"}`),
+			domain.CustomerStatusPendingReview,
+			fasthttp.StatusBadRequest,
+			`{"error":{"status":400,"message":"decision should be one of cleared, confirmed"}}`,
+			0,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			customer := &domain.Customer{GeneratedID: "foobar", Status: test.currentStatus}
+			customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+			customerRepositoryMock.EXPECT().FindByID("foobar").AnyTimes().Return(customer, nil)
+			customerRepositoryMock.EXPECT().Update(customer).Times(test.repositoryUpdates).Return(nil)
+			sanctionRepositoryMock := mocks.NewMockSanctionRepository(ctrl)
+			sanctionRepositoryMock.EXPECT().
+				ResolveMatches("foobar", gomock.Any(), "alfred", gomock.Any()).
+				Times(test.repositoryUpdates).
+				Return(nil)
+
+			useCase := usecase.NewScreeningUseCase(customerRepositoryMock, sanctionRepositoryMock)
+			logger, _ := zap.NewDevelopment()
+			writer := NewJSONResponseWriter(logger)
+			handlerV1 := NewScreeningHandlerV1(logger, useCase, writer)
+
+			// arrange fake server
+			router := fasthttprouter.New()
+			router.PUT("/customer/:id/screening", handlerV1.Resolve)
+
+			listener := fasthttputil.NewInmemoryListener()
+
+			server := &fasthttp.Server{
+				Handler: router.Handler,
+			}
+			go func() {
+				_ = server.Serve(listener)
+			}()
+
+			client := fasthttp.Client{
+				Dial: func(addr string) (net.Conn, error) {
+					return listener.Dial()
+				},
+			}
+			request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+			defer func() {
+				fasthttp.ReleaseRequest(request)
+				fasthttp.ReleaseResponse(response)
+			}()
+
+			// act
+			request.Header.SetMethod(fasthttp.MethodPut)
+			request.SetBody(test.input)
+			request.SetRequestURI("/customer/foobar/screening")
+			request.SetHost("localhost")
+
+			_ = client.Do(request, response)
+
+			// assert
+			assert.Equal(t, test.expectedStatus, response.Header.StatusCode())
+			assert.Equal(t, test.expectedResult, string(response.Body()))
+			assert.Equal(t, test.expectedCustomer, customer.Status)
+		})
+	}
+}
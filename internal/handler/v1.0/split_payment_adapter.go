@@ -0,0 +1,122 @@
+package v1
+
+import (
+	"net"
+	"time"
+	"unicode/utf8"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	// maxSplitPaymentDescriptionLength is a length limit of description shown in statements
+	maxSplitPaymentDescriptionLength = 255
+	// maxSplitRoleLength is a length limit of recipient role
+	maxSplitRoleLength = 32
+)
+
+func splitPaymentFromRequest(payerID string, request *SplitPaymentRequestBody) (*domain.SplitPayment, error) {
+	if request.Amount <= 0 {
+		return nil, domain.NewValidationError("amount should be positive")
+	}
+	if !currencyRegexp.MatchString(request.Currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	if utf8.RuneCountInString(request.Description) > maxSplitPaymentDescriptionLength {
+		return nil, domain.NewValidationError("description should be 255 characters at most")
+	}
+	if request.IP != "" && net.ParseIP(request.IP) == nil {
+		return nil, domain.NewValidationError("ip is not valid IP address")
+	}
+
+	payment := &domain.SplitPayment{
+		PayerID:     payerID,
+		Amount:      request.Amount,
+		Currency:    request.Currency,
+		Description: request.Description,
+		DeviceID:    request.DeviceID,
+		IP:          request.IP,
+	}
+	for _, split := range request.Splits {
+		if split.Amount != 0 && split.Percent != "" {
+			return nil, domain.NewValidationError("split should have either amount or percent")
+		}
+		if utf8.RuneCountInString(split.Role) > maxSplitRoleLength {
+			return nil, domain.NewValidationError("split role should be 32 characters at most")
+		}
+		payment.Legs = append(payment.Legs, domain.SplitLeg{
+			RecipientID: split.RecipientID,
+			Role:        split.Role,
+			Percent:     split.Percent,
+			Amount:      split.Amount,
+		})
+	}
+	return payment, nil
+}
+
+// receivedSplitsPeriodFromRequest reads from and to dates of report, the last day is included
+func receivedSplitsPeriodFromRequest(args *fasthttp.Args) (time.Time, time.Time, error) {
+	from, err := time.ParseInLocation(domain.DateFormat, string(args.Peek("from")), businessday.Location)
+	if err != nil {
+		return time.Time{}, time.Time{}, domain.NewValidationError("wrong from format")
+	}
+	to, err := time.ParseInLocation(domain.DateFormat, string(args.Peek("to")), businessday.Location)
+	if err != nil {
+		return time.Time{}, time.Time{}, domain.NewValidationError("wrong to format")
+	}
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, domain.NewValidationError("to should not be before from")
+	}
+	return from, to.AddDate(0, 0, 1), nil
+}
+
+func responseFromSplitPayment(payment *domain.SplitPayment) *SplitPaymentBody {
+	response := &SplitPaymentBody{
+		PaymentID:        payment.GeneratedID,
+		PayerID:          payment.PayerID,
+		Amount:           payment.Amount,
+		Currency:         payment.Currency,
+		Description:      payment.Description,
+		Splits:           make([]*SplitLegBody, 0, len(payment.Legs)),
+		RiskAssessmentID: payment.RiskAssessmentID,
+		RiskDecision:     string(payment.RiskDecision),
+		CreatedAt:        payment.CreatedAt.Format(domain.DateTimeFormat),
+	}
+	for i := range payment.Legs {
+		response.Splits = append(response.Splits, responseFromSplitLeg(&payment.Legs[i]))
+	}
+	return response
+}
+
+func responseFromReceivedSplits(legs []*domain.SplitLeg, totals []domain.SplitTotal) *ReceivedSplitsBody {
+	response := &ReceivedSplitsBody{
+		Splits: make([]*SplitLegBody, 0, len(legs)),
+		Totals: make([]*SplitTotalBody, 0, len(totals)),
+	}
+	for _, leg := range legs {
+		response.Splits = append(response.Splits, responseFromSplitLeg(leg))
+	}
+	for _, total := range totals {
+		response.Totals = append(response.Totals, &SplitTotalBody{
+			Currency: total.Currency,
+			Role:     total.Role,
+			Amount:   total.Amount,
+			Count:    total.Count,
+		})
+	}
+	return response
+}
+
+func responseFromSplitLeg(leg *domain.SplitLeg) *SplitLegBody {
+	return &SplitLegBody{
+		PaymentID:   leg.PaymentID,
+		RecipientID: leg.RecipientID,
+		Role:        leg.Role,
+		Percent:     leg.Percent,
+		Amount:      leg.Amount,
+		Currency:    leg.Currency,
+		CreatedAt:   leg.CreatedAt.Format(domain.DateTimeFormat),
+	}
+}
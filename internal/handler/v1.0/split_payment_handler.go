@@ -0,0 +1,200 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const SplitPaymentIdUrlPath = "id"
+
+type SplitPaymentHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.SplitPaymentUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewSplitPaymentHandlerV1(
+	logger *zap.Logger,
+	splitPaymentService *usecase.SplitPaymentUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *SplitPaymentHandlerV1 {
+	return &SplitPaymentHandlerV1{logger: logger, useCase: splitPaymentService, responseWriter: responseWriter}
+}
+
+// swagger:parameters CreateSplitPayment
+type SplitPaymentRequestBody struct {
+	// amount in minor currency units
+	// in:body
+	Amount int64 `json:"amount"`
+	// in:body
+	Currency string `json:"currency"`
+	// shown in statements of payer and recipients
+	// in:body
+	Description string `json:"description"`
+	// fixed amounts and percents of amount should sum to amount exactly
+	// in:body
+	Splits []SplitRequestBody `json:"splits"`
+	// device of payer scored by risk assessment
+	// in:body
+	DeviceID string `json:"device_id"`
+	// IP address of payer scored by risk assessment
+	// in:body
+	IP string `json:"ip"`
+}
+
+type SplitRequestBody struct {
+	RecipientID string `json:"recipient_id"`
+	// like seller, platform or delivery
+	Role string `json:"role"`
+	// fixed amount in minor currency units
+	Amount int64 `json:"amount"`
+	// decimal percent of payment amount like 12.5, rounded shares sum to payment amount
+	Percent string `json:"percent"`
+}
+
+type SplitPaymentBody struct {
+	PaymentID        string          `json:"payment_id"`
+	PayerID          string          `json:"payer_id"`
+	Amount           int64           `json:"amount"`
+	Currency         string          `json:"currency"`
+	Description      string          `json:"description,omitempty"`
+	Splits           []*SplitLegBody `json:"splits"`
+	RiskAssessmentID string          `json:"risk_assessment_id"`
+	// risk decision, approve or review
+	RiskDecision string `json:"risk_decision"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type SplitLegBody struct {
+	PaymentID   string `json:"payment_id"`
+	RecipientID string `json:"recipient_id"`
+	Role        string `json:"role,omitempty"`
+	Percent     string `json:"percent,omitempty"`
+	Amount      int64  `json:"amount"`
+	Currency    string `json:"currency"`
+	CreatedAt   string `json:"created_at"`
+}
+
+type ReceivedSplitsBody struct {
+	Splits []*SplitLegBody   `json:"splits"`
+	Totals []*SplitTotalBody `json:"totals"`
+}
+
+type SplitTotalBody struct {
+	Currency string `json:"currency"`
+	Role     string `json:"role,omitempty"`
+	Amount   int64  `json:"amount"`
+	Count    int    `json:"count"`
+}
+
+// swagger:route POST /customer/{id}/split-payments split-payments CreateSplitPayment
+// Pays from customer balance to several recipients, like seller, platform commission and delivery partner.
+// Payer debit and recipient credits are posted at once.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  422: ErrorResponse
+//  500: ErrorResponse
+func (h *SplitPaymentHandlerV1) Create(ctx *fasthttp.RequestCtx) {
+	payerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := payerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &SplitPaymentRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	payment, err := splitPaymentFromRequest(payerID.(string), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Create(payment)
+	if err != nil {
+		h.writeSplitPaymentError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromSplitPayment(payment))
+}
+
+// swagger:route GET /split-payments/{id} split-payments FindSplitPayment
+// Shows split payment with amounts received by every recipient.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *SplitPaymentHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	paymentID := ctx.UserValue(SplitPaymentIdUrlPath)
+	if _, ok := paymentID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	payment, err := h.useCase.Find(paymentID.(string))
+	if err != nil {
+		h.writeSplitPaymentError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromSplitPayment(payment))
+}
+
+// swagger:route GET /customer/{id}/received-splits split-payments FindReceivedSplits
+// Reports splits received by customer between from and to dates inclusive, format DD-MM-YYYY,
+// the latest first, with totals by currency and role.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *SplitPaymentHandlerV1) FindReceived(ctx *fasthttp.RequestCtx) {
+	recipientID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := recipientID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	from, to, err := receivedSplitsPeriodFromRequest(ctx.QueryArgs())
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	legs, totals, err := h.useCase.ReceivedSplits(recipientID.(string), from, to)
+	if err != nil {
+		h.writeSplitPaymentError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromReceivedSplits(legs, totals))
+}
+
+func (h *SplitPaymentHandlerV1) writeSplitPaymentError(ctx *fasthttp.RequestCtx, err error) {
+	switch typedErr := err.(type) {
+	case *domain.LimitExceededError:
+		writeLimitExceeded(h.responseWriter, ctx, typedErr)
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process split payment. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,219 @@
+package v1
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/risk"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestCreateSplitPayment_Success(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	for _, customerID := range []string{"buyer", "seller", "delivery", "platform"} {
+		customerRepositoryMock.EXPECT().FindByID(customerID).Return(&domain.Customer{
+			GeneratedID: customerID,
+			Status:      domain.CustomerStatusActive,
+		}, nil)
+	}
+	repositoryMock := mocks.NewMockSplitPaymentRepository(ctrl)
+	repositoryMock.EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(payment *domain.SplitPayment, debit *domain.Debit) error {
+			postings := debit.Postings
+			var sum int64
+			for _, posting := range postings {
+				sum += posting.Amount
+				assert.Equal(t, "split_payment:"+payment.GeneratedID, posting.Reference)
+			}
+			assert.Len(t, postings, 6)
+			assert.Equal(t, int64(-100), postings[0].Amount)
+			assert.Equal(t, int64(0), sum)
+			assert.Equal(t, int64(5), payment.Fee)
+			for i, customerID := range []string{"buyer", domain.LedgerAccountFees} {
+				assert.Equal(t, customerID, postings[4+i].CustomerID)
+				assert.Equal(t, 3, postings[4+i].FeeScheduleVersion)
+			}
+			assert.Equal(t, int64(-5), postings[4].Amount)
+			for i, customerID := range []string{"seller", "delivery", "platform"} {
+				assert.Equal(t, customerID, postings[1+i].CustomerID)
+			}
+			assert.Equal(t, &domain.LimitCharge{
+				CustomerID: "buyer",
+				Currency:   "RUB",
+				Amount:     100,
+				Limits:     highLimits,
+				ChargedAt:  debit.Limit.ChargedAt,
+			}, debit.Limit)
+			return nil
+		})
+
+	var movements []*domain.Movement
+	schedule := &domain.FeeSchedule{
+		Version: 3,
+		Rules:   []domain.FeeRule{{Operation: domain.MovementTypeTransfer, Currency: "RUB", Fixed: 5}},
+	}
+	useCase := usecase.NewSplitPaymentUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		usecase.NewLedgerUseCase(
+			mocks.NewMockLedgerRepository(ctrl),
+			newVerificationUseCase(ctrl, approvedVerification),
+			newLimitUseCase(ctrl, highLimits),
+			newFeeUseCase(ctrl, schedule),
+			newMonitoringUseCase(ctrl, &movements),
+		),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewSplitPaymentHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/split-payments", handlerV1.Create)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/buyer/split-payments")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{
+		"amount": 100,
+		"currency": "RUB",
+		"splits": [
+			{"recipient_id": "seller", "role": "seller", "percent": "33.5"},
+			{"recipient_id": "delivery", "role": "delivery", "percent": "33.5"},
+			{"recipient_id": "platform", "role": "platform", "amount": 33}
+		]
+	}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	body := &SplitPaymentBody{}
+	err := json.Unmarshal(response.Body(), body)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Len(t, body.Splits, 3)
+	assert.NotEmpty(t, body.RiskAssessmentID)
+	assert.Equal(t, "approve", body.RiskDecision)
+	// leftover unit of rounded percents goes to the first of equal shares
+	for i, amount := range []int64{34, 33, 33} {
+		assert.Equal(t, amount, body.Splits[i].Amount)
+	}
+	// payer and every recipient are monitored
+	assert.Len(t, movements, 4)
+	for i, amount := range []int64{100, 34, 33, 33} {
+		assert.Equal(t, amount, movements[i].Amount)
+		assert.Equal(t, domain.MovementTypeTransfer, movements[i].Type)
+	}
+}
+
+func TestCreateSplitPayment_NotSummedToAmount(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("buyer").Return(&domain.Customer{
+		GeneratedID: "buyer",
+		Status:      domain.CustomerStatusActive,
+	}, nil)
+
+	useCase := usecase.NewSplitPaymentUseCase(
+		mocks.NewMockSplitPaymentRepository(ctrl),
+		customerRepositoryMock,
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		newRiskUseCase(ctrl, risk.DefaultThresholds),
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewSplitPaymentHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/split-payments", handlerV1.Create)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/buyer/split-payments")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBodyString(`{
+		"amount": 10000,
+		"currency": "RUB",
+		"splits": [
+			{"recipient_id": "seller", "role": "seller", "percent": "90"},
+			{"recipient_id": "platform", "role": "platform", "amount": 500}
+		]
+	}`)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusConflict, response.Header.StatusCode())
+	assert.JSONEq(
+		t,
+		`{"error": {"status": 409, "message": "splits should sum to payment amount"}}`,
+		string(response.Body()),
+	)
+}
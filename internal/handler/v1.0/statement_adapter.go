@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/statement"
+)
+
+// StatementQuery is read from query arguments of statement request
+type StatementQuery struct {
+	Currency string
+	From     time.Time
+	To       time.Time
+	Format   statement.Format
+}
+
+func statementQueryFromRequest(args *fasthttp.Args) (*StatementQuery, error) {
+	query := &StatementQuery{
+		Currency: string(args.Peek("currency")),
+		Format:   statement.Format(args.Peek("format")),
+	}
+	if !currencyRegexp.MatchString(query.Currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	switch query.Format {
+	case "":
+		query.Format = statement.FormatJSON
+	case statement.FormatCSV, statement.FormatPDF, statement.FormatJSON:
+	default:
+		return nil, domain.NewValidationError("format should be one of csv, pdf, json")
+	}
+
+	var err error
+	query.From, err = time.ParseInLocation(domain.DateFormat, string(args.Peek("from")), businessday.Location)
+	if err != nil {
+		return nil, domain.NewValidationError("wrong from format")
+	}
+	query.To, err = time.ParseInLocation(domain.DateFormat, string(args.Peek("to")), businessday.Location)
+	if err != nil {
+		return nil, domain.NewValidationError("wrong to format")
+	}
+	if query.To.Before(query.From) {
+		return nil, domain.NewValidationError("to should not be before from")
+	}
+	return query, nil
+}
+
+// statementFileName is empty for JSON, so JSON statement is shown as any other response
+func statementFileName(accountStatement *domain.Statement, format statement.Format) string {
+	if format == statement.FormatJSON {
+		return ""
+	}
+	return "statement-" + accountStatement.CustomerID + "-" +
+		accountStatement.From.Format(domain.DateFormat) + "-" +
+		accountStatement.To.Format(domain.DateFormat) + "." + string(format)
+}
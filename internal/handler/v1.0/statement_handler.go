@@ -0,0 +1,100 @@
+package v1
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/statement"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+type StatementHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.StatementUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewStatementHandlerV1(
+	logger *zap.Logger,
+	statementService *usecase.StatementUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *StatementHandlerV1 {
+	return &StatementHandlerV1{logger: logger, useCase: statementService, responseWriter: responseWriter}
+}
+
+// swagger:parameters FindStatement
+type StatementRequestQuery struct {
+	// first day of statement in DD-MM-YYYY format, days are in Moscow time
+	// in:query
+	From string `json:"from"`
+	// last day of statement in DD-MM-YYYY format, included
+	// in:query
+	To string `json:"to"`
+	// in:query
+	Currency string `json:"currency"`
+	// one of csv, pdf, json, json by default
+	// in:query
+	Format string `json:"format"`
+}
+
+// swagger:route GET /customer/{id}/statement statements FindStatement
+// Streams statement of customer account in currency with opening balance, every posting with running balance
+// and closing balance. CSV uses semicolon separator and decimal comma, JSON amounts are in minor units.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *StatementHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	query, err := statementQueryFromRequest(ctx.QueryArgs())
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	accountStatement, err := h.useCase.Open(customerID.(string), query.Currency, query.From, query.To)
+	if err != nil {
+		h.writeStatementError(ctx, err)
+		return
+	}
+
+	h.responseWriter.WriteSuccessStream(
+		ctx,
+		query.Format.ContentType(),
+		statementFileName(accountStatement, query.Format),
+		func(w io.Writer) error {
+			writer, err := statement.NewWriter(query.Format, w)
+			if err != nil {
+				return err
+			}
+			return h.useCase.Write(accountStatement, writer)
+		},
+	)
+}
+
+func (h *StatementHandlerV1) writeStatementError(ctx *fasthttp.RequestCtx, err error) {
+	switch err.(type) {
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process statement. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestFindStatement_CSV(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	from := time.Date(2020, 8, 1, 0, 0, 0, 0, businessday.Location)
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("foobar").Return(&domain.Customer{GeneratedID: "foobar"}, nil)
+	postingRepositoryMock := mocks.NewMockPostingRepository(ctrl)
+	postingRepositoryMock.EXPECT().FindBalance("foobar", "RUB", from).Return(int64(100000), nil)
+	postingRepositoryMock.EXPECT().
+		StreamPostings("foobar", "RUB", from, from.AddDate(0, 1, 0), gomock.Any()).
+		DoAndReturn(func(_, _ string, _, _ time.Time, handle func(posting *domain.Posting) error) error {
+			postings := []*domain.Posting{
+				{Amount: -2550, Description: "Payment", Reference: "invoice:42", PostedAt: from.Add(time.Hour)},
+				{Amount: 1000, Description: "Refund", PostedAt: from.AddDate(0, 0, 30)},
+			}
+			for _, posting := range postings {
+				err := handle(posting)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+	useCase := usecase.NewStatementUseCase(postingRepositoryMock, customerRepositoryMock)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewStatementHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.GET("/customer/:id/statement", handlerV1.Find)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/foobar/statement?from=01-08-2020&to=31-08-2020&currency=RUB&format=csv")
+	request.Header.SetMethod(fasthttp.MethodGet)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusOK, response.Header.StatusCode())
+	assert.Equal(t, "text/csv; charset=utf-8", string(response.Header.ContentType()))
+	assert.Equal(
+		t,
+		`attachment; filename="statement-foobar-01-08-2020-31-08-2020.csv"`,
+		string(response.Header.Peek("Content-Disposition")),
+	)
+	assert.Equal(t, strings.Join([]string{
+		"date;description;reference;debit;credit;balance",
+		"01-08-2020;Opening balance;;;;1000,00",
+		"01-08-2020;Payment;invoice:42;25,50;;974,50",
+		"31-08-2020;Refund;;;10,00;984,50",
+		"31-08-2020;Closing balance;;;;984,50",
+		"",
+	}, "\n"), string(response.Body()))
+}
+
+func TestFindStatement_WrongPeriod(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	useCase := usecase.NewStatementUseCase(mocks.NewMockPostingRepository(ctrl), mocks.NewMockCustomerRepository(ctrl))
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewStatementHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.GET("/customer/:id/statement", handlerV1.Find)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/foobar/statement?from=31-08-2020&to=01-08-2020&currency=RUB")
+	request.Header.SetMethod(fasthttp.MethodGet)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusBadRequest, response.Header.StatusCode())
+	assert.Contains(t, string(response.Body()), "to should not be before from")
+}
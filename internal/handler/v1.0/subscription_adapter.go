@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func planFromRequest(request *PlanRequestBody) (*domain.Plan, error) {
+	if request.Name == "" {
+		return nil, domain.NewValidationError("name is mandatory field")
+	}
+	if request.Amount <= 0 {
+		return nil, domain.NewValidationError("amount should be positive")
+	}
+	if !currencyRegexp.MatchString(request.Currency) {
+		return nil, domain.NewValidationError("currency should be ISO 4217 code")
+	}
+	interval := domain.PlanInterval(request.Interval)
+	switch interval {
+	case domain.PlanIntervalDay, domain.PlanIntervalWeek, domain.PlanIntervalMonth, domain.PlanIntervalYear:
+	default:
+		return nil, domain.NewValidationError("interval should be one of day, week, month, year")
+	}
+	if request.IntervalCount == 0 {
+		request.IntervalCount = 1
+	}
+	if request.IntervalCount < 0 {
+		return nil, domain.NewValidationError("interval_count should be positive")
+	}
+	if request.TrialDays < 0 {
+		return nil, domain.NewValidationError("trial_days should not be negative")
+	}
+	return &domain.Plan{
+		Name:          request.Name,
+		Amount:        request.Amount,
+		Currency:      request.Currency,
+		Interval:      interval,
+		IntervalCount: request.IntervalCount,
+		TrialDays:     request.TrialDays,
+	}, nil
+}
+
+func subscriptionFromRequest(request *SubscriptionRequestBody, customerID string) (*domain.Subscription, error) {
+	if request.PlanID == "" {
+		return nil, domain.NewValidationError("plan_id is mandatory field")
+	}
+	if request.PaymentMethod == "" {
+		return nil, domain.NewValidationError("payment_method is mandatory field")
+	}
+	return &domain.Subscription{
+		CustomerID:    customerID,
+		PlanID:        request.PlanID,
+		PaymentMethod: request.PaymentMethod,
+	}, nil
+}
+
+func responseFromPlan(plan *domain.Plan) *PlanBody {
+	return &PlanBody{
+		PlanID:        plan.GeneratedID,
+		Name:          plan.Name,
+		Amount:        plan.Amount,
+		Currency:      plan.Currency,
+		Interval:      string(plan.Interval),
+		IntervalCount: plan.IntervalCount,
+		TrialDays:     plan.TrialDays,
+		CreatedAt:     plan.CreatedAt.Format(domain.DateTimeFormat),
+	}
+}
+
+func responseFromSubscription(subscription *domain.Subscription) *SubscriptionBody {
+	body := &SubscriptionBody{
+		SubscriptionID:     subscription.GeneratedID,
+		CustomerID:         subscription.CustomerID,
+		PlanID:             subscription.PlanID,
+		PaymentMethod:      subscription.PaymentMethod,
+		Status:             string(subscription.Status),
+		CurrentPeriodStart: subscription.CurrentPeriodStart.Format(domain.DateTimeFormat),
+		CurrentPeriodEnd:   subscription.CurrentPeriodEnd.Format(domain.DateTimeFormat),
+		CancelAtPeriodEnd:  subscription.CancelAtPeriodEnd,
+		PendingProration:   subscription.PendingProration,
+		CreatedAt:          subscription.CreatedAt.Format(domain.DateTimeFormat),
+		UpdatedAt:          subscription.UpdatedAt.Format(domain.DateTimeFormat),
+	}
+	if !subscription.TrialEnd.IsZero() {
+		body.TrialEnd = subscription.TrialEnd.Format(domain.DateTimeFormat)
+	}
+	if !subscription.CancelledAt.IsZero() {
+		body.CancelledAt = subscription.CancelledAt.Format(domain.DateTimeFormat)
+	}
+	return body
+}
+
+func responseFromSubscriptionInvoice(invoice *domain.SubscriptionInvoice) *SubscriptionInvoiceBody {
+	body := &SubscriptionInvoiceBody{
+		InvoiceID:      invoice.GeneratedID,
+		SubscriptionID: invoice.SubscriptionID,
+		PeriodStart:    invoice.PeriodStart.Format(domain.DateTimeFormat),
+		PeriodEnd:      invoice.PeriodEnd.Format(domain.DateTimeFormat),
+		Items:          make([]SubscriptionInvoiceItemBody, 0, len(invoice.Items)),
+		Amount:         invoice.Amount,
+		Currency:       invoice.Currency,
+		Status:         string(invoice.Status),
+		Attempts:       invoice.Attempts,
+		LastError:      invoice.LastError,
+		CreatedAt:      invoice.CreatedAt.Format(domain.DateTimeFormat),
+		UpdatedAt:      invoice.UpdatedAt.Format(domain.DateTimeFormat),
+	}
+	for _, item := range invoice.Items {
+		body.Items = append(body.Items, SubscriptionInvoiceItemBody{Description: item.Description, Amount: item.Amount})
+	}
+	if invoice.Status == domain.SubscriptionInvoiceStatusOpen {
+		body.NextAttemptAt = invoice.NextAttemptAt.Format(domain.DateTimeFormat)
+	}
+	return body
+}
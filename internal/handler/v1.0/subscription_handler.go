@@ -0,0 +1,372 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const (
+	PlanIdUrlPath         = "id"
+	SubscriptionIdUrlPath = "id"
+)
+
+type SubscriptionHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.SubscriptionUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewSubscriptionHandlerV1(
+	logger *zap.Logger,
+	subscriptionService *usecase.SubscriptionUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *SubscriptionHandlerV1 {
+	return &SubscriptionHandlerV1{logger: logger, useCase: subscriptionService, responseWriter: responseWriter}
+}
+
+// swagger:parameters CreatePlan
+type PlanRequestBody struct {
+	// in:body
+	Name string `json:"name"`
+	// in:body
+	Amount int64 `json:"amount"`
+	// in:body
+	Currency string `json:"currency"`
+	// one of day, week, month, year
+	// in:body
+	Interval string `json:"interval"`
+	// number of intervals in billing period, 1 by default
+	// in:body
+	IntervalCount int `json:"interval_count"`
+	// in:body
+	TrialDays int `json:"trial_days"`
+}
+
+type PlansBody struct {
+	Plans []*PlanBody `json:"plans"`
+}
+
+type PlanBody struct {
+	PlanID        string `json:"plan_id"`
+	Name          string `json:"name"`
+	Amount        int64  `json:"amount"`
+	Currency      string `json:"currency"`
+	Interval      string `json:"interval"`
+	IntervalCount int    `json:"interval_count"`
+	TrialDays     int    `json:"trial_days"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// swagger:parameters Subscribe ChangeSubscriptionPlan
+type SubscriptionRequestBody struct {
+	// in:body
+	PlanID string `json:"plan_id"`
+	// token of customer card or account to charge
+	// in:body
+	PaymentMethod string `json:"payment_method"`
+}
+
+type SubscriptionsBody struct {
+	Subscriptions []*SubscriptionBody `json:"subscriptions"`
+}
+
+type SubscriptionBody struct {
+	SubscriptionID     string `json:"subscription_id"`
+	CustomerID         string `json:"customer_id"`
+	PlanID             string `json:"plan_id"`
+	PaymentMethod      string `json:"payment_method"`
+	Status             string `json:"status"`
+	CurrentPeriodStart string `json:"current_period_start"`
+	CurrentPeriodEnd   string `json:"current_period_end"`
+	TrialEnd           string `json:"trial_end,omitempty"`
+	CancelAtPeriodEnd  bool   `json:"cancel_at_period_end"`
+	PendingProration   int64  `json:"pending_proration"`
+	CancelledAt        string `json:"cancelled_at,omitempty"`
+	CreatedAt          string `json:"created_at"`
+	UpdatedAt          string `json:"updated_at"`
+}
+
+type SubscriptionInvoicesBody struct {
+	Invoices []*SubscriptionInvoiceBody `json:"invoices"`
+}
+
+type SubscriptionInvoiceBody struct {
+	InvoiceID      string                        `json:"invoice_id"`
+	SubscriptionID string                        `json:"subscription_id"`
+	PeriodStart    string                        `json:"period_start"`
+	PeriodEnd      string                        `json:"period_end"`
+	Items          []SubscriptionInvoiceItemBody `json:"items"`
+	Amount         int64                         `json:"amount"`
+	Currency       string                        `json:"currency"`
+	Status         string                        `json:"status"`
+	Attempts       int                           `json:"attempts"`
+	LastError      string                        `json:"last_error,omitempty"`
+	NextAttemptAt  string                        `json:"next_attempt_at,omitempty"`
+	CreatedAt      string                        `json:"created_at"`
+	UpdatedAt      string                        `json:"updated_at"`
+}
+
+type SubscriptionInvoiceItemBody struct {
+	Description string `json:"description"`
+	Amount      int64  `json:"amount"`
+}
+
+// swagger:route POST /plans subscriptions CreatePlan
+// Creates subscription plan. Plans are immutable, create a new plan to change price.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *SubscriptionHandlerV1) CreatePlan(ctx *fasthttp.RequestCtx) {
+	request := &PlanRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	plan, err := planFromRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.CreatePlan(plan)
+	if err != nil {
+		h.writeSubscriptionError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromPlan(plan))
+}
+
+// swagger:route GET /plans subscriptions FindPlans
+// Lists subscription plans.
+// responses:
+//  200:
+//  500: ErrorResponse
+func (h *SubscriptionHandlerV1) FindPlans(ctx *fasthttp.RequestCtx) {
+	plans, err := h.useCase.FindPlans()
+	if err != nil {
+		h.writeSubscriptionError(ctx, err)
+		return
+	}
+
+	response := &PlansBody{Plans: make([]*PlanBody, 0, len(plans))}
+	for _, plan := range plans {
+		response.Plans = append(response.Plans, responseFromPlan(plan))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route GET /plans/{id} subscriptions FindPlan
+// Shows subscription plan.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *SubscriptionHandlerV1) FindPlan(ctx *fasthttp.RequestCtx) {
+	planID := ctx.UserValue(PlanIdUrlPath)
+	if _, ok := planID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.useCase.FindPlan(planID.(string))
+	if err != nil {
+		h.writeSubscriptionError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromPlan(plan))
+}
+
+// swagger:route POST /customer/{id}/subscriptions subscriptions Subscribe
+// Subscribes customer to plan. Plan without trial is invoiced and charged at once.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *SubscriptionHandlerV1) Subscribe(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &SubscriptionRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	subscription, err := subscriptionFromRequest(request, customerID.(string))
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Subscribe(subscription)
+	if err != nil {
+		h.writeSubscriptionError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromSubscription(subscription))
+}
+
+// swagger:route GET /customer/{id}/subscriptions subscriptions FindSubscriptions
+// Lists subscriptions of customer.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  500: ErrorResponse
+func (h *SubscriptionHandlerV1) FindByCustomer(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	subscriptions, err := h.useCase.FindByCustomer(customerID.(string))
+	if err != nil {
+		h.writeSubscriptionError(ctx, err)
+		return
+	}
+
+	response := &SubscriptionsBody{Subscriptions: make([]*SubscriptionBody, 0, len(subscriptions))}
+	for _, subscription := range subscriptions {
+		response.Subscriptions = append(response.Subscriptions, responseFromSubscription(subscription))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+// swagger:route GET /subscriptions/{id} subscriptions FindSubscription
+// Shows subscription.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *SubscriptionHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	subscriptionID := ctx.UserValue(SubscriptionIdUrlPath)
+	if _, ok := subscriptionID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.useCase.Find(subscriptionID.(string))
+	if err != nil {
+		h.writeSubscriptionError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromSubscription(subscription))
+}
+
+// swagger:route PUT /subscriptions/{id}/plan subscriptions ChangeSubscriptionPlan
+// Moves subscription to another plan at once, price difference for the rest of period goes to the next invoice.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *SubscriptionHandlerV1) ChangePlan(ctx *fasthttp.RequestCtx) {
+	subscriptionID := ctx.UserValue(SubscriptionIdUrlPath)
+	if _, ok := subscriptionID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &SubscriptionRequestBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	if request.PlanID == "" {
+		h.responseWriter.WriteError(ctx, "plan_id is mandatory field", fasthttp.StatusBadRequest)
+		return
+	}
+
+	subscription, err := h.useCase.ChangePlan(subscriptionID.(string), request.PlanID)
+	if err != nil {
+		h.writeSubscriptionError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromSubscription(subscription))
+}
+
+// swagger:route DELETE /subscriptions/{id} subscriptions CancelSubscription
+// Cancels subscription at once, or at the end of current period when at_period_end query parameter is true.
+// responses:
+//  204:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *SubscriptionHandlerV1) Cancel(ctx *fasthttp.RequestCtx) {
+	subscriptionID := ctx.UserValue(SubscriptionIdUrlPath)
+	if _, ok := subscriptionID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	_, err := h.useCase.Cancel(subscriptionID.(string), ctx.QueryArgs().GetBool("at_period_end"))
+	if err != nil {
+		h.writeSubscriptionError(ctx, err)
+		return
+	}
+	h.responseWriter.WriteSuccessDELETE(ctx)
+}
+
+// swagger:route GET /subscriptions/{id}/invoices subscriptions FindSubscriptionInvoices
+// Lists invoices of subscription, the latest period first.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *SubscriptionHandlerV1) FindInvoices(ctx *fasthttp.RequestCtx) {
+	subscriptionID := ctx.UserValue(SubscriptionIdUrlPath)
+	if _, ok := subscriptionID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	invoices, err := h.useCase.Invoices(subscriptionID.(string))
+	if err != nil {
+		h.writeSubscriptionError(ctx, err)
+		return
+	}
+
+	response := &SubscriptionInvoicesBody{Invoices: make([]*SubscriptionInvoiceBody, 0, len(invoices))}
+	for _, invoice := range invoices {
+		response.Invoices = append(response.Invoices, responseFromSubscriptionInvoice(invoice))
+	}
+	h.responseWriter.WriteSuccessGET(ctx, response)
+}
+
+func (h *SubscriptionHandlerV1) writeSubscriptionError(ctx *fasthttp.RequestCtx, err error) {
+	switch err.(type) {
+	case *domain.NotFoundError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+	case *domain.ValidationError:
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+	default:
+		h.logger.Error(fmt.Sprintf("error while process subscription. uri: %s, error: %s", ctx.RequestURI(), err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			http.StatusText(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+	}
+}
@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/billing"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestSubscribe_Trial(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("foobar").Return(&domain.Customer{GeneratedID: "foobar"}, nil)
+	repositoryMock := mocks.NewMockSubscriptionRepository(ctrl)
+	repositoryMock.EXPECT().
+		FindPlanByID("basic").
+		Return(&domain.Plan{
+			GeneratedID: "basic", Name: "Basic", Amount: 29900, Currency: "RUB",
+			Interval: domain.PlanIntervalMonth, IntervalCount: 1, TrialDays: 14,
+		}, nil)
+	repositoryMock.EXPECT().CreateSubscription(gomock.Any(), nil).Return(nil)
+
+	useCase := usecase.NewSubscriptionUseCase(
+		repositoryMock,
+		customerRepositoryMock,
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		billing.DefaultDunningPolicy,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewSubscriptionHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/subscriptions", handlerV1.Subscribe)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/foobar/subscriptions")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetBody([]byte(`{"plan_id": "basic", "payment_method": "card_token"}`))
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+	body := &SubscriptionBody{}
+	err := json.Unmarshal(response.Body(), body)
+	assert.NoError(t, err)
+	assert.Equal(t, "trialing", body.Status)
+	assert.Equal(t, body.TrialEnd, body.CurrentPeriodEnd)
+	trialEnd, _ := time.Parse(domain.DateTimeFormat, body.TrialEnd)
+	periodStart, _ := time.Parse(domain.DateTimeFormat, body.CurrentPeriodStart)
+	assert.Equal(t, 14*24*time.Hour, trialEnd.Sub(periodStart).Truncate(time.Hour))
+}
+
+func TestChangeSubscriptionPlan_Upgrade(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	subscription := &domain.Subscription{
+		GeneratedID:        "subscription",
+		CustomerID:         "foobar",
+		PlanID:             "basic",
+		Status:             domain.SubscriptionStatusActive,
+		BillingAnchor:      now.Add(-15 * 24 * time.Hour),
+		CurrentPeriodStart: now.Add(-15 * 24 * time.Hour),
+		CurrentPeriodEnd:   now.Add(15 * 24 * time.Hour),
+	}
+	repositoryMock := mocks.NewMockSubscriptionRepository(ctrl)
+	repositoryMock.EXPECT().FindSubscriptionByID("subscription").Return(subscription, nil)
+	repositoryMock.EXPECT().
+		FindPlanByID("basic").
+		Return(&domain.Plan{
+			GeneratedID: "basic", Amount: 1000, Currency: "RUB", Interval: domain.PlanIntervalMonth, IntervalCount: 1,
+		}, nil)
+	repositoryMock.EXPECT().
+		FindPlanByID("premium").
+		Return(&domain.Plan{
+			GeneratedID: "premium", Amount: 3000, Currency: "RUB", Interval: domain.PlanIntervalMonth, IntervalCount: 1,
+		}, nil)
+	repositoryMock.EXPECT().
+		UpdateSubscription("subscription", gomock.Any()).
+		DoAndReturn(func(_ string, update func(subscription *domain.Subscription) error) (*domain.Subscription, error) {
+			err := update(subscription)
+			if err != nil {
+				return nil, err
+			}
+			return subscription, nil
+		})
+
+	useCase := usecase.NewSubscriptionUseCase(
+		repositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl)),
+		billing.DefaultDunningPolicy,
+	)
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewSubscriptionHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.PUT("/subscriptions/:id/plan", handlerV1.ChangePlan)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/subscriptions/subscription/plan")
+	request.Header.SetMethod(fasthttp.MethodPut)
+	request.SetBody([]byte(`{"plan_id": "premium"}`))
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusOK, response.Header.StatusCode())
+	body := &SubscriptionBody{}
+	err := json.Unmarshal(response.Body(), body)
+	assert.NoError(t, err)
+	assert.Equal(t, "premium", body.PlanID)
+	assert.Equal(t, int64(1000), body.PendingProration)
+}
@@ -0,0 +1,68 @@
+package v1
+
+import (
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+type reviewDecision struct {
+	Status   domain.VerificationStatus
+	Level    domain.VerificationLevel
+	Reviewer string
+	Comment  string
+}
+
+func reviewFromRequest(request *ReviewBody) (*reviewDecision, error) {
+	status := domain.VerificationStatus(request.Status)
+	switch status {
+	case domain.VerificationStatusInReview,
+		domain.VerificationStatusApproved,
+		domain.VerificationStatusRejected,
+		domain.VerificationStatusNeedsMoreInfo:
+	case "":
+		return nil, domain.NewValidationError("status is mandatory field")
+	default:
+		return nil, domain.NewValidationError("status should be one of in_review, approved, rejected, needs_more_info")
+	}
+
+	level := domain.VerificationLevel(request.Level)
+	if status == domain.VerificationStatusApproved &&
+		level != domain.VerificationLevelBasic && level != domain.VerificationLevelFull {
+		return nil, domain.NewValidationError("level should be one of basic, full for approved verification")
+	}
+	if request.Reviewer == "" {
+		return nil, domain.NewValidationError("reviewer is mandatory field")
+	}
+	if request.Comment == "" &&
+		(status == domain.VerificationStatusRejected || status == domain.VerificationStatusNeedsMoreInfo) {
+		return nil, domain.NewValidationError("comment is mandatory field for rejected or needs_more_info verification")
+	}
+
+	return &reviewDecision{
+		Status:   status,
+		Level:    level,
+		Reviewer: request.Reviewer,
+		Comment:  request.Comment,
+	}, nil
+}
+
+func responseFromVerification(verification *domain.Verification) *VerificationBody {
+	checks := make([]VerificationCheckBody, 0, len(verification.Checks))
+	for _, check := range verification.Checks {
+		checks = append(checks, VerificationCheckBody{
+			Name:   check.Name,
+			Passed: check.Passed,
+			Reason: check.Reason,
+		})
+	}
+	return &VerificationBody{
+		VerificationID: verification.GeneratedID,
+		CustomerID:     verification.CustomerID,
+		Status:         string(verification.Status),
+		Level:          string(verification.Level),
+		Checks:         checks,
+		Reviewer:       verification.Reviewer,
+		Comment:        verification.Comment,
+		CreatedAt:      verification.CreatedAt.Format(domain.DateTimeFormat),
+		UpdatedAt:      verification.UpdatedAt.Format(domain.DateTimeFormat),
+	}
+}
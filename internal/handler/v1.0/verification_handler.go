@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/valyala/fasthttp"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	handler "github.com/yaroslavnayug/go-payment-system/internal/handler/common"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+const VerificationIdUrlPath = "id"
+
+type VerificationHandlerV1 struct {
+	logger         *zap.Logger
+	useCase        *usecase.VerificationUseCase
+	responseWriter handler.ResponseWriterInterface
+}
+
+func NewVerificationHandlerV1(
+	logger *zap.Logger,
+	verificationService *usecase.VerificationUseCase,
+	responseWriter handler.ResponseWriterInterface,
+) *VerificationHandlerV1 {
+	return &VerificationHandlerV1{logger: logger, useCase: verificationService, responseWriter: responseWriter}
+}
+
+type VerificationBody struct {
+	VerificationID string                  `json:"verification_id"`
+	CustomerID     string                  `json:"customer_id"`
+	Status         string                  `json:"status"`
+	Level          string                  `json:"level"`
+	Checks         []VerificationCheckBody `json:"checks"`
+	Reviewer       string                  `json:"reviewer"`
+	Comment        string                  `json:"comment"`
+	CreatedAt      string                  `json:"created_at"`
+	UpdatedAt      string                  `json:"updated_at"`
+}
+
+type VerificationCheckBody struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason"`
+}
+
+// swagger:parameters ReviewVerification
+type ReviewBody struct {
+	// in:body
+	Status string `json:"status"`
+	// in:body
+	Level string `json:"level"`
+	// in:body
+	Reviewer string `json:"reviewer"`
+	// in:body
+	Comment string `json:"comment"`
+}
+
+// swagger:route POST /customer/{id}/verification verifications SubmitVerification
+// Submits customer passport data for verification.
+// responses:
+//  201:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *VerificationHandlerV1) Submit(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	verification, err := h.useCase.Submit(customerID.(string))
+	if err != nil {
+		switch err.(type) {
+		case *domain.NotFoundError:
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+		case *domain.ValidationError:
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+		default:
+			h.logger.Error(
+				fmt.Sprintf("error while submit verification. customerID: %s, error: %s", customerID, err.Error()),
+			)
+			h.responseWriter.WriteError(
+				ctx,
+				http.StatusText(fasthttp.StatusInternalServerError),
+				fasthttp.StatusInternalServerError,
+			)
+		}
+		return
+	}
+	h.responseWriter.WriteSuccessPOST(ctx, responseFromVerification(verification))
+}
+
+// swagger:route GET /customer/{id}/verification verifications FindVerification
+// Finds the latest verification of customer.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  500: ErrorResponse
+func (h *VerificationHandlerV1) Find(ctx *fasthttp.RequestCtx) {
+	customerID := ctx.UserValue(CustomerIdUrlPath)
+	if _, ok := customerID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+	verification, err := h.useCase.Find(customerID.(string))
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("error while find verification. customerID: %s, error: %s", customerID, err.Error()))
+		h.responseWriter.WriteError(
+			ctx,
+			fasthttp.StatusMessage(fasthttp.StatusInternalServerError),
+			fasthttp.StatusInternalServerError,
+		)
+		return
+	}
+	if verification == nil {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusNotFound), fasthttp.StatusNotFound)
+		return
+	}
+	h.responseWriter.WriteSuccessGET(ctx, responseFromVerification(verification))
+}
+
+// swagger:route PUT /verification/{id} verifications ReviewVerification
+// Applies reviewer decision to verification.
+// responses:
+//  200:
+//  400: ErrorResponse
+//  404: ErrorResponse
+//  409: ErrorResponse
+//  500: ErrorResponse
+func (h *VerificationHandlerV1) Review(ctx *fasthttp.RequestCtx) {
+	verificationID := ctx.UserValue(VerificationIdUrlPath)
+	if _, ok := verificationID.(string); !ok {
+		h.responseWriter.WriteError(ctx, fasthttp.StatusMessage(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	request := &ReviewBody{}
+	err := json.Unmarshal(ctx.PostBody(), request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, http.StatusText(fasthttp.StatusBadRequest), fasthttp.StatusBadRequest)
+		return
+	}
+
+	decision, err := reviewFromRequest(request)
+	if err != nil {
+		h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusBadRequest)
+		return
+	}
+
+	err = h.useCase.Review(
+		verificationID.(string),
+		decision.Status,
+		decision.Level,
+		decision.Reviewer,
+		decision.Comment,
+	)
+	if err != nil {
+		switch err.(type) {
+		case *domain.NotFoundError:
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusNotFound)
+		case *domain.ValidationError:
+			h.responseWriter.WriteError(ctx, err.Error(), fasthttp.StatusConflict)
+		default:
+			h.logger.Error(
+				fmt.Sprintf("error while review verification. request: %s, error: %s", ctx.PostBody(), err.Error()),
+			)
+			h.responseWriter.WriteError(
+				ctx,
+				http.StatusText(fasthttp.StatusInternalServerError),
+				fasthttp.StatusInternalServerError,
+			)
+		}
+		return
+	}
+	h.responseWriter.WriteSuccessPUT(ctx)
+}
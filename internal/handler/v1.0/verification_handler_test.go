@@ -0,0 +1,267 @@
+package v1
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/buaazp/fasthttprouter"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttputil"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/kyc"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestSubmitVerification(t *testing.T) {
+	t.Parallel()
+
+	birthDate, _ := time.Parse(domain.DateFormat, "01-01-1990")
+	testCases := []struct {
+		name           string
+		issueDate      string
+		expectedStatus string
+	}{
+		{"ChecksPassed", "01-01-2010", "submitted"},
+		{"ChecksFailed", "01-01-2000", "needs_more_info"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			issueDate, _ := time.Parse(domain.DateFormat, test.issueDate)
+			customer := &domain.Customer{
+				GeneratedID: "foobar",
+				Passport: domain.Passport{
+					Number:    "1234567890",
+					IssueDate: issueDate,
+					BirthDate: birthDate,
+				},
+			}
+			customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+			customerRepositoryMock.EXPECT().FindByID("foobar").Return(customer, nil)
+			verificationRepositoryMock := mocks.NewMockVerificationRepository(ctrl)
+			verificationRepositoryMock.EXPECT().FindLastByCustomerID("foobar").Return(nil, nil)
+			verificationRepositoryMock.EXPECT().Create(gomock.Any()).Return(nil)
+
+			useCase := usecase.NewVerificationUseCase(verificationRepositoryMock, customerRepositoryMock, kyc.DefaultChecks())
+			logger, _ := zap.NewDevelopment()
+			writer := NewJSONResponseWriter(logger)
+			handlerV1 := NewVerificationHandlerV1(logger, useCase, writer)
+
+			// arrange fake server
+			router := fasthttprouter.New()
+			router.POST("/customer/:id/verification", handlerV1.Submit)
+
+			listener := fasthttputil.NewInmemoryListener()
+
+			server := &fasthttp.Server{
+				Handler: router.Handler,
+			}
+			go func() {
+				_ = server.Serve(listener)
+			}()
+
+			client := fasthttp.Client{
+				Dial: func(addr string) (net.Conn, error) {
+					return listener.Dial()
+				},
+			}
+			request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+			defer func() {
+				fasthttp.ReleaseRequest(request)
+				fasthttp.ReleaseResponse(response)
+			}()
+
+			// act
+			request.SetRequestURI("/customer/foobar/verification")
+			request.Header.SetMethod(fasthttp.MethodPost)
+			request.SetHost("localhost")
+
+			_ = client.Do(request, response)
+
+			// assert
+			responseJSON := VerificationBody{}
+			_ = json.Unmarshal(response.Body(), &responseJSON)
+
+			assert.Equal(t, fasthttp.StatusCreated, response.Header.StatusCode())
+			assert.Equal(t, "foobar", responseJSON.CustomerID)
+			assert.Equal(t, test.expectedStatus, responseJSON.Status)
+			assert.Len(t, responseJSON.Checks, 3)
+		})
+	}
+}
+
+func TestSubmitVerification_CustomerNotFound(t *testing.T) {
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().FindByID("foobar").Return(nil, nil)
+	verificationRepositoryMock := mocks.NewMockVerificationRepository(ctrl)
+
+	useCase := usecase.NewVerificationUseCase(verificationRepositoryMock, customerRepositoryMock, kyc.DefaultChecks())
+	logger, _ := zap.NewDevelopment()
+	writer := NewJSONResponseWriter(logger)
+	handlerV1 := NewVerificationHandlerV1(logger, useCase, writer)
+
+	// arrange fake server
+	router := fasthttprouter.New()
+	router.POST("/customer/:id/verification", handlerV1.Submit)
+
+	listener := fasthttputil.NewInmemoryListener()
+
+	server := &fasthttp.Server{
+		Handler: router.Handler,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	client := fasthttp.Client{
+		Dial: func(addr string) (net.Conn, error) {
+			return listener.Dial()
+		},
+	}
+	request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+	defer func() {
+		fasthttp.ReleaseRequest(request)
+		fasthttp.ReleaseResponse(response)
+	}()
+
+	// act
+	request.SetRequestURI("/customer/foobar/verification")
+	request.Header.SetMethod(fasthttp.MethodPost)
+	request.SetHost("localhost")
+
+	_ = client.Do(request, response)
+
+	// assert
+	assert.Equal(t, fasthttp.StatusNotFound, response.Header.StatusCode())
+	assert.Equal(t, `{"error":{"status":404,"message":"customer with such id not found"}}`, string(response.Body()))
+}
+
+func TestReviewVerification(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		currentStatus  domain.VerificationStatus
+		input          []byte
+		expectedStatus int
+		expectedResult string
+	}{
+		{
+			"Approved",
+			domain.VerificationStatusInReview,
+			[]byte(`{"status": "approved", "level": "full", "reviewer": "alfred"}`),
+			fasthttp.StatusOK,
+			"",
+		},
+		{
+			"RejectedWithoutComment",
+			domain.VerificationStatusInReview,
+			[]byte(`{"status": "rejected", "reviewer": "alfred"}`),
+			fasthttp.StatusBadRequest,
+			`{"error":{"status":400,"message":"comment is mandatory field for rejected or needs_more_info verification"}}`,
+		},
+		{
+			"ApprovedWithoutReview",
+			domain.VerificationStatusSubmitted,
+			[]byte(`{"status": "approved", "level": "basic", "reviewer": "alfred"}`),
+			fasthttp.StatusConflict,
+			`{"error":{"status":409,"message":"verification could not be moved from submitted to approved"}}`,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			verification := &domain.Verification{
+				GeneratedID: "verification",
+				CustomerID:  "foobar",
+				Status:      test.currentStatus,
+				Level:       domain.VerificationLevelNone,
+			}
+			customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+			verificationRepositoryMock := mocks.NewMockVerificationRepository(ctrl)
+			verificationRepositoryMock.EXPECT().FindByID("verification").AnyTimes().Return(verification, nil)
+			verificationRepositoryMock.EXPECT().Update(gomock.Any()).AnyTimes().Return(nil)
+
+			useCase := usecase.NewVerificationUseCase(verificationRepositoryMock, customerRepositoryMock, nil)
+			logger, _ := zap.NewDevelopment()
+			writer := NewJSONResponseWriter(logger)
+			handlerV1 := NewVerificationHandlerV1(logger, useCase, writer)
+
+			// arrange fake server
+			router := fasthttprouter.New()
+			router.PUT("/verification/:id", handlerV1.Review)
+
+			listener := fasthttputil.NewInmemoryListener()
+
+			server := &fasthttp.Server{
+				Handler: router.Handler,
+			}
+			go func() {
+				_ = server.Serve(listener)
+			}()
+
+			client := fasthttp.Client{
+				Dial: func(addr string) (net.Conn, error) {
+					return listener.Dial()
+				},
+			}
+			request, response := fasthttp.AcquireRequest(), fasthttp.AcquireResponse()
+			defer func() {
+				fasthttp.ReleaseRequest(request)
+				fasthttp.ReleaseResponse(response)
+			}()
+
+			// act
+			request.Header.SetMethod(fasthttp.MethodPut)
+			request.SetBody(test.input)
+			request.SetRequestURI("/verification/verification")
+			request.SetHost("localhost")
+
+			_ = client.Do(request, response)
+
+			// assert
+			assert.Equal(t, test.expectedStatus, response.Header.StatusCode())
+			assert.Equal(t, test.expectedResult, string(response.Body()))
+		})
+	}
+}
+
+// approvedVerification allows customers to move money
+var approvedVerification = &domain.Verification{
+	Status: domain.VerificationStatusApproved,
+	Level:  domain.VerificationLevelFull,
+}
+
+// newVerificationUseCase builds verification use case where every customer is active and has verification,
+// nil verification means customers are not verified
+func newVerificationUseCase(ctrl *gomock.Controller, verification *domain.Verification) *usecase.VerificationUseCase {
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().
+		FindByID(gomock.Any()).
+		DoAndReturn(func(customerID string) (*domain.Customer, error) {
+			return &domain.Customer{GeneratedID: customerID, Status: domain.CustomerStatusActive}, nil
+		}).
+		AnyTimes()
+	verificationRepositoryMock := mocks.NewMockVerificationRepository(ctrl)
+	verificationRepositoryMock.EXPECT().FindLastByCustomerID(gomock.Any()).Return(verification, nil).AnyTimes()
+	return usecase.NewVerificationUseCase(verificationRepositoryMock, customerRepositoryMock, nil)
+}
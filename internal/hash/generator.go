@@ -7,7 +7,38 @@ import (
 )
 
 const (
-	hashCustomerKey = "customer"
+	hashCustomerKey          = "customer"
+	hashVerificationKey      = "verification"
+	hashMovementKey          = "movement"
+	hashAlertKey             = "alert"
+	hashRiskKey              = "risk"
+	hashScheduleKey          = "schedule"
+	hashExecutionKey         = "execution"
+	hashPlanKey              = "plan"
+	hashSubscriptionKey      = "subscription"
+	hashInvoiceKey           = "invoice"
+	hashTenantInvoiceKey     = "tenant_invoice"
+	hashBankStatementKey     = "bank_statement"
+	hashBankEntryKey         = "bank_entry"
+	hashPayoutKey            = "payout"
+	hashPayoutBatchKey       = "payout_batch"
+	hashQRRequestKey         = "qr_payment_request"
+	hashQRPaymentKey         = "qr_payment"
+	hashP2PTransferKey       = "p2p_transfer"
+	hashEscrowKey            = "escrow"
+	hashSplitPaymentKey      = "split_payment"
+	hashPostingKey           = "posting"
+	hashDisputeKey           = "dispute"
+	hashEvidenceKey          = "evidence"
+	hashDepositProductKey    = "deposit_product"
+	hashDepositKey           = "deposit"
+	hashLoanProductKey       = "loan_product"
+	hashLoanApplicationKey   = "loan_application"
+	hashLoanKey              = "loan"
+	hashCreditLineKey        = "credit_line"
+	hashCardKey              = "card"
+	hashCardAuthorizationKey = "card_authorization"
+	hashBeneficiaryKey       = "beneficiary"
 )
 
 func GenerateUniqueCustomerID(firstName string, passportNumber string, timestamp int64) (string, error) {
@@ -15,6 +46,110 @@ func GenerateUniqueCustomerID(firstName string, passportNumber string, timestamp
 	return getHashForString(baseString)
 }
 
+func GenerateUniqueVerificationID(customerID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", customerID, hashVerificationKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueMovementID(customerID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", customerID, hashMovementKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueAlertID(movementID string, ruleName string) (string, error) {
+	baseString := fmt.Sprintf("%s%s%s", movementID, ruleName, hashAlertKey)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueRiskAssessmentID(customerID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", customerID, hashRiskKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueScheduleID(customerID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", customerID, hashScheduleKey, timestamp)
+	return getHashForString(baseString)
+}
+
+// GenerateUniqueExecutionID is the same for the same due time of schedule, so it is never executed twice
+func GenerateUniqueExecutionID(scheduleID string, dueAt int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", scheduleID, hashExecutionKey, dueAt)
+	return getHashForString(baseString)
+}
+
+func GenerateUniquePlanID(name string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", name, hashPlanKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueSubscriptionID(customerID string, planID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%s%d", customerID, planID, hashSubscriptionKey, timestamp)
+	return getHashForString(baseString)
+}
+
+// GenerateUniqueSubscriptionInvoiceID is the same for the same period of subscription, so period is billed once
+func GenerateUniqueSubscriptionInvoiceID(subscriptionID string, periodStart int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", subscriptionID, hashInvoiceKey, periodStart)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueInvoiceID(tenantID string, customerID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%s%d", tenantID, customerID, hashTenantInvoiceKey, timestamp)
+	return getHashForString(baseString)
+}
+
+// GenerateUniqueBankStatementID is the same for the same statement of account, so statement is imported once
+func GenerateUniqueBankStatementID(accountIBAN string, bankStatementID string) (string, error) {
+	baseString := fmt.Sprintf("%s%s%s", accountIBAN, bankStatementID, hashBankStatementKey)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueBankEntryID(statementID string, entryNumber int) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", statementID, hashBankEntryKey, entryNumber)
+	return getHashForString(baseString)
+}
+
+func GenerateUniquePayoutID(customerID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", customerID, hashPayoutKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniquePayoutBatchID(timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%d", hashPayoutBatchKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueQRPaymentRequestID(merchantID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", merchantID, hashQRRequestKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueQRPaymentID(requestID string, payerID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%s%d", requestID, payerID, hashQRPaymentKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueP2PTransferID(senderID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", senderID, hashP2PTransferKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueEscrowID(merchantID string, orderID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%s%d", merchantID, orderID, hashEscrowKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueSplitPaymentID(payerID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", payerID, hashSplitPaymentKey, timestamp)
+	return getHashForString(baseString)
+}
+
+// GenerateUniquePostingID is the same for the same leg of operation, so operation is never posted twice
+func GenerateUniquePostingID(reference string, leg int) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", reference, hashPostingKey, leg)
+	return getHashForString(baseString)
+}
+
 func getHashForString(baseString string) (string, error) {
 	hasher := md5.New()
 	_, err := hasher.Write([]byte(baseString))
@@ -23,3 +158,60 @@ func getHashForString(baseString string) (string, error) {
 	}
 	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
+
+func GenerateUniqueDisputeID(paymentID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", paymentID, hashDisputeKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueDisputeEvidenceID(disputeID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", disputeID, hashEvidenceKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueDepositProductID(name string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", name, hashDepositProductKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueDepositID(customerID string, productID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%s%d", customerID, productID, hashDepositKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueLoanProductID(name string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", name, hashLoanProductKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueLoanApplicationID(customerID string, productID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%s%d", customerID, productID, hashLoanApplicationKey, timestamp)
+	return getHashForString(baseString)
+}
+
+// GenerateUniqueLoanID is the same for the same application, so application is never disbursed twice
+func GenerateUniqueLoanID(applicationID string) (string, error) {
+	baseString := fmt.Sprintf("%s%s", applicationID, hashLoanKey)
+	return getHashForString(baseString)
+}
+
+// GenerateUniqueCreditLineID is the same for the same customer and currency, customer has one credit line in currency
+func GenerateUniqueCreditLineID(customerID string, currency string) (string, error) {
+	baseString := fmt.Sprintf("%s%s%s", customerID, currency, hashCreditLineKey)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueCardID(customerID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", customerID, hashCardKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueCardAuthorizationID(cardID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", cardID, hashCardAuthorizationKey, timestamp)
+	return getHashForString(baseString)
+}
+
+func GenerateUniqueBeneficiaryID(customerID string, timestamp int64) (string, error) {
+	baseString := fmt.Sprintf("%s%s%d", customerID, hashBeneficiaryKey, timestamp)
+	return getHashForString(baseString)
+}
@@ -11,3 +11,184 @@ func Test_GenerateUniqueCustomerID(t *testing.T) {
 	hash, _ := GenerateUniqueCustomerID("Misha", "1234567890", unixTime)
 	assert.Equal(t, "09b843b24f5c966771ce2029a173c9ad", hash)
 }
+
+func Test_GenerateUniqueVerificationID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueVerificationID("09b843b24f5c966771ce2029a173c9ad", unixNanoTime)
+	assert.Equal(t, "9fd9ecc6146b1993d94b91c83ae67529", hash)
+}
+
+func Test_GenerateUniqueMovementID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueMovementID("09b843b24f5c966771ce2029a173c9ad", unixNanoTime)
+	assert.Equal(t, "65d183df592c096f1f603c9f80cd35f2", hash)
+}
+
+func Test_GenerateUniqueAlertID(t *testing.T) {
+	hash, _ := GenerateUniqueAlertID("65d183df592c096f1f603c9f80cd35f2", "large transfer")
+	assert.Equal(t, "45c8de2bd8afcf9ecfab640f173c3ef8", hash)
+}
+
+func Test_GenerateUniqueRiskAssessmentID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueRiskAssessmentID("09b843b24f5c966771ce2029a173c9ad", unixNanoTime)
+	assert.Equal(t, "ef1fa99bc02746ed1234c62a561871ab", hash)
+}
+
+func Test_GenerateUniqueScheduleID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueScheduleID("09b843b24f5c966771ce2029a173c9ad", unixNanoTime)
+	assert.Equal(t, "9de52015d5fc3d55e83d8df9154cd5b2", hash)
+}
+
+func Test_GenerateUniqueExecutionID(t *testing.T) {
+	unixTime := int64(1597726137)
+	hash, _ := GenerateUniqueExecutionID("09b843b24f5c966771ce2029a173c9ad", unixTime)
+	assert.Equal(t, "0795e6e68126f2dc9e465356aa87580a", hash)
+}
+
+func Test_GenerateUniquePlanID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniquePlanID("Premium", unixNanoTime)
+	assert.Equal(t, "185cfc6757dc662759fb3d147f1cb37d", hash)
+}
+
+func Test_GenerateUniqueSubscriptionID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueSubscriptionID(
+		"09b843b24f5c966771ce2029a173c9ad",
+		"185cfc6757dc662759fb3d147f1cb37d",
+		unixNanoTime,
+	)
+	assert.Equal(t, "d0c3499f25d7a349423b8165efe0fb77", hash)
+}
+
+func Test_GenerateUniqueSubscriptionInvoiceID(t *testing.T) {
+	unixTime := int64(1597726137)
+	hash, _ := GenerateUniqueSubscriptionInvoiceID("d0c3499f25d7a349423b8165efe0fb77", unixTime)
+	assert.Equal(t, "202944c174e0f715b85c1338c3be03fe", hash)
+}
+
+func Test_GenerateUniqueInvoiceID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueInvoiceID("tenant", "09b843b24f5c966771ce2029a173c9ad", unixNanoTime)
+	assert.Equal(t, "afde0bd02f8e25390a0e4c947282b003", hash)
+}
+
+func Test_GenerateUniqueBankStatementID(t *testing.T) {
+	hash, _ := GenerateUniqueBankStatementID("RU0204452560040702810412345678901", "STMT-20200818-RUB")
+	assert.Equal(t, "cda5184a79dba408c24223b5bf5fdd63", hash)
+}
+
+func Test_GenerateUniqueBankEntryID(t *testing.T) {
+	hash, _ := GenerateUniqueBankEntryID("cda5184a79dba408c24223b5bf5fdd63", 1)
+	assert.Equal(t, "1e3f2b22dc4e1950b42bad21a2be7357", hash)
+}
+
+func Test_GenerateUniquePayoutID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniquePayoutID("09b843b24f5c966771ce2029a173c9ad", unixNanoTime)
+	assert.Equal(t, "f275681cd0b3c828fead8d3cd392fd4f", hash)
+}
+
+func Test_GenerateUniquePayoutBatchID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniquePayoutBatchID(unixNanoTime)
+	assert.Equal(t, "e831dcc7e531e40411818ed06634f209", hash)
+}
+
+func Test_GenerateUniqueQRPaymentRequestID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueQRPaymentRequestID("09b843b24f5c966771ce2029a173c9ad", unixNanoTime)
+	assert.Equal(t, "27771b5def0e30bd2ce5048e17032cab", hash)
+}
+
+func Test_GenerateUniqueQRPaymentID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueQRPaymentID("27771b5def0e30bd2ce5048e17032cab", "foobar", unixNanoTime)
+	assert.Equal(t, "09f847facf9d94d95cec0280c2c7858b", hash)
+}
+
+func Test_GenerateUniqueP2PTransferID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueP2PTransferID("09b843b24f5c966771ce2029a173c9ad", unixNanoTime)
+	assert.Equal(t, "24a2448993c3cb8904c65089f7932f69", hash)
+}
+
+func Test_GenerateUniqueEscrowID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueEscrowID("09b843b24f5c966771ce2029a173c9ad", "order-15", unixNanoTime)
+	assert.Equal(t, "c40eac1a8230137f48f6c7f2b5db46d3", hash)
+}
+
+func Test_GenerateUniqueSplitPaymentID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueSplitPaymentID("09b843b24f5c966771ce2029a173c9ad", unixNanoTime)
+	assert.Equal(t, "1593ac882bcf64cca1841393ee267688", hash)
+}
+
+func Test_GenerateUniquePostingID(t *testing.T) {
+	hash, _ := GenerateUniquePostingID("split_payment:foobar", 2)
+	assert.Equal(t, "197edc45feb344e87eacc5cac8ca9ba9", hash)
+}
+
+func Test_GenerateUniqueDisputeID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueDisputeID("09f847facf9d94d95cec0280c2c7858b", unixNanoTime)
+	assert.Equal(t, "c843d28bbee17a7dccdb28691d7f894d", hash)
+}
+
+func Test_GenerateUniqueDisputeEvidenceID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueDisputeEvidenceID("09f847facf9d94d95cec0280c2c7858b", unixNanoTime)
+	assert.Equal(t, "88dd5db541b2dd2bd14e771fa0a7d7f1", hash)
+}
+
+func Test_GenerateUniqueDepositProductID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueDepositProductID("Savings 12", unixNanoTime)
+	assert.Equal(t, "f332c24d8baff89cfd41763dc85b1dfb", hash)
+}
+
+func Test_GenerateUniqueDepositID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueDepositID("09b843b24f5c966771ce2029a173c9ad", "foobar", unixNanoTime)
+	assert.Equal(t, "acae780d4eb461bed866f7899acfcc45", hash)
+}
+
+func Test_GenerateUniqueLoanProductID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueLoanProductID("Consumer 24", unixNanoTime)
+	assert.Equal(t, "362075657a1f741c654096dc9151dc47", hash)
+}
+
+func Test_GenerateUniqueLoanApplicationID(t *testing.T) {
+	unixNanoTime := int64(1597726137000000000)
+	hash, _ := GenerateUniqueLoanApplicationID("09b843b24f5c966771ce2029a173c9ad", "foobar", unixNanoTime)
+	assert.Equal(t, "5ae8be32be0565684623f9134ee4c072", hash)
+}
+
+func Test_GenerateUniqueLoanID(t *testing.T) {
+	hash, _ := GenerateUniqueLoanID("foobar")
+	assert.Equal(t, "c247ec63066bdc234b89dc51a94922e0", hash)
+}
+
+func Test_GenerateUniqueCreditLineID(t *testing.T) {
+	hash, _ := GenerateUniqueCreditLineID("09b843b24f5c966771ce2029a173c9ad", "RUB")
+	assert.Equal(t, "2a3e578de78e26a1cccb7a678045bdf9", hash)
+}
+
+func Test_GenerateUniqueCardID(t *testing.T) {
+	hash, _ := GenerateUniqueCardID("09b843b24f5c966771ce2029a173c9ad", 1609459200000000000)
+	assert.Equal(t, "62dd1bd4131248bf8a89bebf99fd6b45", hash)
+}
+
+func Test_GenerateUniqueCardAuthorizationID(t *testing.T) {
+	hash, _ := GenerateUniqueCardAuthorizationID("2a3e578de78e26a1cccb7a678045bdf9", 1609459200000000000)
+	assert.Equal(t, "2b2784dca5def2adec5cf0a82c4a8cf1", hash)
+}
+
+func Test_GenerateUniqueBeneficiaryID(t *testing.T) {
+	hash, _ := GenerateUniqueBeneficiaryID("09b843b24f5c966771ce2029a173c9ad", 1609459200000000000)
+	assert.Equal(t, "96df9be23b21f31006fd0dfe9e189233", hash)
+}
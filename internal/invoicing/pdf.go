@@ -0,0 +1,141 @@
+package invoicing
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/pdf"
+)
+
+const (
+	pageMargin   = 40.0
+	rowHeight    = 16.0
+	fontSize     = 9.0
+	headerSize   = 18.0
+	tableTopGap  = 180.0
+	footerHeight = 40.0
+)
+
+// tableColumns are right edges of invoice table columns, description column is left aligned
+var tableColumns = struct {
+	number, description, quantity, unitPrice, vatRate, net, vat, total float64
+}{
+	number:      pageMargin + 20,
+	description: pageMargin + 190,
+	quantity:    pageMargin + 230,
+	unitPrice:   pageMargin + 290,
+	vatRate:     pageMargin + 325,
+	net:         pageMargin + 385,
+	vat:         pageMargin + 445,
+	total:       pdf.PageWidth - pageMargin,
+}
+
+// RenderPDF writes invoice as PDF document. Amounts are shown with two fraction digits.
+// Lines which do not fit into the first page continue on next pages with repeated table header.
+func RenderPDF(w io.Writer, invoice *domain.Invoice, customerName string) error {
+	document := pdf.NewWriter(w)
+	page := &pdf.Page{}
+
+	title := fmt.Sprintf("Invoice № %d", invoice.Number)
+	if invoice.Status == domain.InvoiceStatusDraft {
+		title = "Invoice draft"
+	}
+	y := pdf.PageHeight - pageMargin - headerSize
+	page.Text(pageMargin, y, headerSize, true, title)
+	page.TextRight(pdf.PageWidth-pageMargin, y, fontSize+2, true, strings.ToUpper(string(invoice.Status)))
+
+	y -= 2 * rowHeight
+	details := [][2]string{
+		{"Issuer", invoice.TenantID},
+		{"Bill to", customerName},
+		{"Customer ID", invoice.CustomerID},
+		{"Due date", invoice.DueDate.Format(domain.DateFormat)},
+	}
+	if !invoice.IssuedAt.IsZero() {
+		details = append(details, [2]string{"Issued at", invoice.IssuedAt.Format(domain.DateTimeFormat)})
+	}
+	if !invoice.PaidAt.IsZero() {
+		details = append(details, [2]string{"Paid at", invoice.PaidAt.Format(domain.DateTimeFormat)})
+	}
+	for _, detail := range details {
+		page.Text(pageMargin, y, fontSize, true, detail[0])
+		page.Text(pageMargin+80, y, fontSize, false, pdf.TruncateText(detail[1], fontSize, 300))
+		y -= rowHeight
+	}
+
+	y = pdf.PageHeight - tableTopGap
+	writeTableHeader(page, y)
+	y -= rowHeight
+	for i, line := range invoice.Lines {
+		if y < pageMargin+footerHeight {
+			err := document.WritePage(page)
+			if err != nil {
+				return err
+			}
+			page = &pdf.Page{}
+			y = pdf.PageHeight - pageMargin - rowHeight
+			writeTableHeader(page, y)
+			y -= rowHeight
+		}
+		writeTableRow(page, y, i+1, line)
+		y -= rowHeight
+	}
+
+	totals := [][2]string{{"Subtotal", pdf.FormatAmount(invoice.Subtotal)}}
+	for _, amount := range VATBreakdown(invoice) {
+		totals = append(totals, [2]string{
+			fmt.Sprintf("VAT %s%% on %s", amount.VATRate, pdf.FormatAmount(amount.Net)),
+			pdf.FormatAmount(amount.VAT),
+		})
+	}
+	totals = append(totals, [2]string{"Total " + invoice.Currency, pdf.FormatAmount(invoice.Total)})
+	if y-float64(len(totals))*rowHeight < pageMargin {
+		err := document.WritePage(page)
+		if err != nil {
+			return err
+		}
+		page = &pdf.Page{}
+		y = pdf.PageHeight - pageMargin - rowHeight
+	}
+	page.Line(tableColumns.unitPrice, y+rowHeight-4, tableColumns.total, y+rowHeight-4)
+	for i, total := range totals {
+		bold := i == len(totals)-1
+		page.TextRight(tableColumns.vat, y, fontSize, bold, total[0])
+		page.TextRight(tableColumns.total, y, fontSize, bold, total[1])
+		y -= rowHeight
+	}
+
+	err := document.WritePage(page)
+	if err != nil {
+		return err
+	}
+	return document.Close()
+}
+
+func writeTableHeader(page *pdf.Page, y float64) {
+	page.TextRight(tableColumns.number, y, fontSize, true, "#")
+	page.Text(tableColumns.number+10, y, fontSize, true, "Description")
+	page.TextRight(tableColumns.quantity, y, fontSize, true, "Qty")
+	page.TextRight(tableColumns.unitPrice, y, fontSize, true, "Unit price")
+	page.TextRight(tableColumns.vatRate, y, fontSize, true, "VAT %")
+	page.TextRight(tableColumns.net, y, fontSize, true, "Net")
+	page.TextRight(tableColumns.vat, y, fontSize, true, "VAT")
+	page.TextRight(tableColumns.total, y, fontSize, true, "Total")
+	page.Line(pageMargin, y-4, pdf.PageWidth-pageMargin, y-4)
+}
+
+func writeTableRow(page *pdf.Page, y float64, number int, line domain.InvoiceLine) {
+	descriptionWidth := tableColumns.description - tableColumns.number - 10
+	page.TextRight(tableColumns.number, y, fontSize, false, strconv.Itoa(number))
+	description := pdf.TruncateText(line.Description, fontSize, descriptionWidth)
+	page.Text(tableColumns.number+10, y, fontSize, false, description)
+	page.TextRight(tableColumns.quantity, y, fontSize, false, line.Quantity)
+	page.TextRight(tableColumns.unitPrice, y, fontSize, false, pdf.FormatAmount(line.UnitPrice))
+	page.TextRight(tableColumns.vatRate, y, fontSize, false, line.VATRate)
+	page.TextRight(tableColumns.net, y, fontSize, false, pdf.FormatAmount(line.Net))
+	page.TextRight(tableColumns.vat, y, fontSize, false, pdf.FormatAmount(line.VAT))
+	page.TextRight(tableColumns.total, y, fontSize, false, pdf.FormatAmount(line.Total))
+}
@@ -0,0 +1,79 @@
+package invoicing
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestRenderPDF(t *testing.T) {
+	t.Parallel()
+
+	invoice := &domain.Invoice{
+		TenantID:   "tenant",
+		CustomerID: "foobar",
+		Number:     42,
+		Status:     domain.InvoiceStatusOpen,
+		Currency:   "RUB",
+		DueDate:    time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC),
+		IssuedAt:   time.Date(2020, 8, 18, 12, 0, 0, 0, time.UTC),
+	}
+	for i := 0; i < 60; i++ {
+		invoice.Lines = append(invoice.Lines, domain.InvoiceLine{
+			Description: fmt.Sprintf("Услуга (hosting) №%d", i+1), Quantity: "1", UnitPrice: 123456, VATRate: "20",
+		})
+	}
+	err := Calculate(invoice)
+	assert.NoError(t, err)
+
+	var output bytes.Buffer
+	err = RenderPDF(&output, invoice, "Иван Петров")
+	assert.NoError(t, err)
+	document := output.Bytes()
+
+	// document has header, trailer and cross-reference table pointing to objects
+	assert.True(t, bytes.HasPrefix(document, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(document, []byte("%%EOF\n")))
+	startXref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(document)
+	assert.NotNil(t, startXref)
+	xrefOffset, _ := strconv.Atoi(string(startXref[1]))
+	assert.True(t, bytes.HasPrefix(document[xrefOffset:], []byte("xref\n")))
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(document[xrefOffset:], -1)
+	for i, offset := range offsets {
+		objectOffset, _ := strconv.Atoi(string(offset[1]))
+		assert.True(t, bytes.HasPrefix(document[objectOffset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))))
+	}
+
+	// lines do not fit into one page
+	assert.Contains(t, string(document), "/Count 2")
+
+	content := firstPageContent(t, document)
+	assert.Contains(t, content, `(Invoice \271 42) Tj`)
+	assert.Contains(t, content, `(\310\342\340\355 \317\345\362\360\356\342) Tj`)
+	assert.Contains(t, content, `(\323\361\353\363\343\340 \(hosting\) \2711) Tj`)
+	assert.Contains(t, content, `(1 234.56) Tj`)
+}
+
+func firstPageContent(t *testing.T, document []byte) string {
+	stream := regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindSubmatch(document)
+	if stream == nil {
+		t.Fatal("document has no content stream")
+	}
+	reader, err := zlib.NewReader(bytes.NewReader(stream[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
@@ -0,0 +1,113 @@
+package invoicing
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// decimalRegexp allows non-negative decimals with up to 6 fraction digits, like 1, 0.5 or 12.125
+var decimalRegexp = regexp.MustCompile(`^\d{1,12}(\.\d{1,6})?$`)
+
+var (
+	hundred  = big.NewRat(100, 1)
+	halfUnit = big.NewRat(1, 2)
+)
+
+// Calculate validates invoice lines and fills line amounts and invoice totals. Line net amount is quantity
+// times unit price, line VAT is net amount times VAT rate, both are calculated exactly and rounded half up
+// to a minor unit. Totals are sums of rounded line amounts.
+func Calculate(invoice *domain.Invoice) error {
+	if len(invoice.Lines) == 0 {
+		return fmt.Errorf("invoice should have lines")
+	}
+
+	invoice.Subtotal, invoice.VATTotal, invoice.Total = 0, 0, 0
+	for i := range invoice.Lines {
+		line := &invoice.Lines[i]
+		if line.Description == "" {
+			return fmt.Errorf("line %d: description is mandatory", i+1)
+		}
+		quantity, err := parseDecimal(line.Quantity)
+		if err != nil || quantity.Sign() == 0 {
+			return fmt.Errorf("line %d: quantity should be a positive decimal", i+1)
+		}
+		if line.UnitPrice < 0 {
+			return fmt.Errorf("line %d: unit price should not be negative", i+1)
+		}
+		rate, err := parseDecimal(line.VATRate)
+		if err != nil || rate.Cmp(hundred) > 0 {
+			return fmt.Errorf("line %d: VAT rate should be a decimal percent between 0 and 100", i+1)
+		}
+
+		net := new(big.Rat).Mul(quantity, new(big.Rat).SetInt64(line.UnitPrice))
+		line.Net, err = roundToMinorUnit(net)
+		if err != nil {
+			return fmt.Errorf("line %d: %s", i+1, err.Error())
+		}
+		vat := new(big.Rat).Mul(new(big.Rat).SetInt64(line.Net), rate)
+		line.VAT, err = roundToMinorUnit(vat.Quo(vat, hundred))
+		if err != nil {
+			return fmt.Errorf("line %d: %s", i+1, err.Error())
+		}
+		line.Total = line.Net + line.VAT
+
+		invoice.Subtotal += line.Net
+		invoice.VATTotal += line.VAT
+		invoice.Total += line.Total
+	}
+	return nil
+}
+
+// VATBreakdown sums invoice lines by VAT rate in order of their first appearance
+func VATBreakdown(invoice *domain.Invoice) []domain.InvoiceVATAmount {
+	var amounts []domain.InvoiceVATAmount
+	index := make(map[string]int)
+	for _, line := range invoice.Lines {
+		rate := line.VATRate
+		if parsed, err := parseDecimal(rate); err == nil {
+			rate = normalizeDecimal(parsed)
+		}
+		i, ok := index[rate]
+		if !ok {
+			i = len(amounts)
+			index[rate] = i
+			amounts = append(amounts, domain.InvoiceVATAmount{VATRate: rate})
+		}
+		amounts[i].Net += line.Net
+		amounts[i].VAT += line.VAT
+	}
+	return amounts
+}
+
+func parseDecimal(value string) (*big.Rat, error) {
+	if !decimalRegexp.MatchString(value) {
+		return nil, fmt.Errorf("%s is not a decimal", value)
+	}
+	rat, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a decimal", value)
+	}
+	return rat, nil
+}
+
+// roundToMinorUnit rounds non-negative amount half up
+func roundToMinorUnit(amount *big.Rat) (int64, error) {
+	rounded := new(big.Rat).Add(amount, halfUnit)
+	result := new(big.Int).Quo(rounded.Num(), rounded.Denom())
+	if !result.IsInt64() {
+		return 0, fmt.Errorf("amount is too large")
+	}
+	return result.Int64(), nil
+}
+
+// normalizeDecimal formats decimal without trailing zeros, so 20.0 and 20 are the same rate
+func normalizeDecimal(value *big.Rat) string {
+	if value.IsInt() {
+		return value.Num().String()
+	}
+	return strings.TrimRight(value.FloatString(6), "0")
+}
@@ -0,0 +1,123 @@
+package invoicing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestCalculate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name             string
+		lines            []domain.InvoiceLine
+		expectedErr      string
+		expectedLine     domain.InvoiceLine
+		expectedSubtotal int64
+		expectedVAT      int64
+		expectedTotal    int64
+	}{
+		{
+			"SingleLine",
+			[]domain.InvoiceLine{{Description: "Hosting", Quantity: "1", UnitPrice: 100000, VATRate: "20"}},
+			"",
+			domain.InvoiceLine{Net: 100000, VAT: 20000, Total: 120000},
+			100000, 20000, 120000,
+		},
+		{
+			"FractionalQuantityRoundedHalfUp",
+			[]domain.InvoiceLine{{Description: "Consulting", Quantity: "1.5", UnitPrice: 333, VATRate: "20"}},
+			"",
+			// 1.5 * 333 = 499.5 -> 500, 500 * 20% = 100
+			domain.InvoiceLine{Net: 500, VAT: 100, Total: 600},
+			500, 100, 600,
+		},
+		{
+			"VATRoundedPerLine",
+			[]domain.InvoiceLine{
+				{Description: "Pen", Quantity: "3", UnitPrice: 1, VATRate: "10"},
+				{Description: "Pencil", Quantity: "0.333333", UnitPrice: 300, VATRate: "0"},
+			},
+			"",
+			// 3 * 10% = 0.3 -> 0
+			domain.InvoiceLine{Net: 3, VAT: 0, Total: 3},
+			103, 0, 103,
+		},
+		{
+			"ExactDecimalRate",
+			[]domain.InvoiceLine{{Description: "Book", Quantity: "0.1", UnitPrice: 3, VATRate: "16.67"}},
+			"",
+			// 0.1 * 3 = 0.3 -> 0, float math would give 0.30000000000000004
+			domain.InvoiceLine{Net: 0, VAT: 0, Total: 0},
+			0, 0, 0,
+		},
+		{
+			"NoLines",
+			nil,
+			"invoice should have lines",
+			domain.InvoiceLine{},
+			0, 0, 0,
+		},
+		{
+			"WrongQuantity",
+			[]domain.InvoiceLine{{Description: "Hosting", Quantity: "1e3", UnitPrice: 100, VATRate: "20"}},
+			"line 1: quantity should be a positive decimal",
+			domain.InvoiceLine{},
+			0, 0, 0,
+		},
+		{
+			"ZeroQuantity",
+			[]domain.InvoiceLine{{Description: "Hosting", Quantity: "0.0", UnitPrice: 100, VATRate: "20"}},
+			"line 1: quantity should be a positive decimal",
+			domain.InvoiceLine{},
+			0, 0, 0,
+		},
+		{
+			"RateAboveHundred",
+			[]domain.InvoiceLine{{Description: "Hosting", Quantity: "1", UnitPrice: 100, VATRate: "100.5"}},
+			"line 1: VAT rate should be a decimal percent between 0 and 100",
+			domain.InvoiceLine{},
+			0, 0, 0,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			invoice := &domain.Invoice{Lines: test.lines}
+
+			err := Calculate(invoice)
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedLine.Net, invoice.Lines[0].Net)
+			assert.Equal(t, test.expectedLine.VAT, invoice.Lines[0].VAT)
+			assert.Equal(t, test.expectedLine.Total, invoice.Lines[0].Total)
+			assert.Equal(t, test.expectedSubtotal, invoice.Subtotal)
+			assert.Equal(t, test.expectedVAT, invoice.VATTotal)
+			assert.Equal(t, test.expectedTotal, invoice.Total)
+		})
+	}
+}
+
+func TestVATBreakdown(t *testing.T) {
+	t.Parallel()
+
+	invoice := &domain.Invoice{Lines: []domain.InvoiceLine{
+		{Description: "a", Quantity: "1", UnitPrice: 1000, VATRate: "20"},
+		{Description: "b", Quantity: "1", UnitPrice: 500, VATRate: "10"},
+		{Description: "c", Quantity: "2", UnitPrice: 1000, VATRate: "20.00"},
+	}}
+	err := Calculate(invoice)
+	assert.NoError(t, err)
+
+	assert.Equal(
+		t,
+		[]domain.InvoiceVATAmount{{VATRate: "20", Net: 3000, VAT: 600}, {VATRate: "10", Net: 500, VAT: 50}},
+		VATBreakdown(invoice),
+	)
+}
@@ -0,0 +1,36 @@
+package iso20022
+
+import (
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ibanRegexp = regexp.MustCompile(`^[A-Z]{2}\d{2}[A-Z0-9]{11,30}$`)
+	bicRegexp  = regexp.MustCompile(`^[A-Z]{6}[A-Z2-9][A-NP-Z0-9]([A-Z0-9]{3})?$`)
+)
+
+// ValidIBAN checks format and check digits of IBAN written without spaces
+func ValidIBAN(iban string) bool {
+	if !ibanRegexp.MatchString(iban) {
+		return false
+	}
+	// country code and check digits are moved to the end, letters are replaced with numbers A = 10 ... Z = 35
+	var digits strings.Builder
+	for _, char := range iban[4:] + iban[:4] {
+		if char >= 'A' && char <= 'Z' {
+			digits.WriteString(strconv.Itoa(int(char - 'A' + 10)))
+		} else {
+			digits.WriteRune(char)
+		}
+	}
+	number, _ := new(big.Int).SetString(digits.String(), 10)
+	return new(big.Int).Mod(number, big.NewInt(97)).Int64() == 1
+}
+
+// ValidBIC checks format of 8 or 11 characters long BIC
+func ValidBIC(bic string) bool {
+	return bicRegexp.MatchString(bic)
+}
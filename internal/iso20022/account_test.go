@@ -0,0 +1,42 @@
+package iso20022
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidIBAN(t *testing.T) {
+	testCases := []struct {
+		iban  string
+		valid bool
+	}{
+		{"DE89370400440532013000", true},
+		{"GB29NWBK60161331926819", true},
+		{"DE89370400440532013001", false},
+		{"DE89 3704 0044 0532 0130 00", false},
+		{"de89370400440532013000", false},
+		{"", false},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.valid, ValidIBAN(test.iban), test.iban)
+	}
+}
+
+func TestValidBIC(t *testing.T) {
+	testCases := []struct {
+		bic   string
+		valid bool
+	}{
+		{"COBADEFF", true},
+		{"COBADEFFXXX", true},
+		{"COBADEF", false},
+		{"COBADEFFXX", false},
+		{"cobadeff", false},
+	}
+
+	for _, test := range testCases {
+		assert.Equal(t, test.valid, ValidBIC(test.bic), test.bic)
+	}
+}
@@ -0,0 +1,239 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	bookedEntryStatus     = "BOOK"
+	openingBalanceCode    = "OPBD"
+	closingBalanceCode    = "CLBD"
+	creditIndicator       = "CRDT"
+	debitIndicator        = "DBIT"
+	notProvidedReference  = "NOTPROVIDED"
+	camtDocumentNamespace = "urn:iso:std:iso:20022:tech:xsd:camt.053"
+)
+
+// camtDocument is a subset of camt.053 BankToCustomerStatement common for versions 001.02 - 001.08.
+// Elements are matched by local names, so any version namespace is accepted.
+type camtDocument struct {
+	XMLName    xml.Name `xml:"Document"`
+	Statements []struct {
+		ID      string `xml:"Id"`
+		Account struct {
+			IBAN     string `xml:"Id>IBAN"`
+			Other    string `xml:"Id>Othr>Id"`
+			Currency string `xml:"Ccy"`
+		} `xml:"Acct"`
+		Balances []struct {
+			Code      string     `xml:"Tp>CdOrPrtry>Cd"`
+			Amount    camtAmount `xml:"Amt"`
+			Indicator string     `xml:"CdtDbtInd"`
+		} `xml:"Bal"`
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtEntry struct {
+	Amount    camtAmount `xml:"Amt"`
+	Indicator string     `xml:"CdtDbtInd"`
+	// Status is a text in 001.02 - 001.06 and Cd element since 001.07
+	Status struct {
+		Text string `xml:",chardata"`
+		Code string `xml:"Cd"`
+	} `xml:"Sts"`
+	BookingDate              camtDate `xml:"BookgDt"`
+	ValueDate                camtDate `xml:"ValDt"`
+	AccountServicerReference string   `xml:"AcctSvcrRef"`
+	Transactions             []struct {
+		EndToEndID string `xml:"Refs>EndToEndId"`
+		// Amount is Amt since 001.03 and AmtDtls>TxAmt>Amt in 001.02
+		Amount            camtAmount `xml:"Amt"`
+		TransactionAmount camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+		RemittanceInfo    []string   `xml:"RmtInf>Ustrd"`
+	} `xml:"NtryDtls>TxDtls"`
+	AdditionalInfo string `xml:"AddtlNtryInf"`
+}
+
+// ParseCamt053 reads statements of camt.053 document. Only booked entries are taken, batch entry
+// with amounts of every transaction is split into entries per transaction. Statement balances
+// are checked against entries, so a document with missed entries is rejected.
+func ParseCamt053(reader io.Reader) ([]*domain.BankStatement, error) {
+	document := camtDocument{}
+	err := xml.NewDecoder(reader).Decode(&document)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode xml: %s", err.Error())
+	}
+	if document.XMLName.Space != "" && !strings.HasPrefix(document.XMLName.Space, camtDocumentNamespace) {
+		return nil, fmt.Errorf("document namespace %s is not camt.053", document.XMLName.Space)
+	}
+	if len(document.Statements) == 0 {
+		return nil, fmt.Errorf("document has no statements")
+	}
+
+	statements := make([]*domain.BankStatement, 0, len(document.Statements))
+	for i, camtStatement := range document.Statements {
+		statement := &domain.BankStatement{
+			BankStatementID: strings.TrimSpace(camtStatement.ID),
+			AccountIBAN:     strings.TrimSpace(camtStatement.Account.IBAN),
+			Currency:        strings.TrimSpace(camtStatement.Account.Currency),
+		}
+		if statement.AccountIBAN == "" {
+			statement.AccountIBAN = strings.TrimSpace(camtStatement.Account.Other)
+		}
+		if statement.BankStatementID == "" || statement.AccountIBAN == "" {
+			return nil, fmt.Errorf("statement %d: id and account are mandatory", i+1)
+		}
+
+		var hasOpening, hasClosing bool
+		for _, balance := range camtStatement.Balances {
+			amount, err := parseSignedAmount(balance.Amount, balance.Indicator)
+			if err != nil {
+				return nil, fmt.Errorf("statement %s: balance %s: %s", statement.BankStatementID, balance.Code, err.Error())
+			}
+			if statement.Currency == "" {
+				statement.Currency = balance.Amount.Currency
+			}
+			switch balance.Code {
+			case openingBalanceCode:
+				statement.OpeningBalance, hasOpening = amount, true
+			case closingBalanceCode:
+				statement.ClosingBalance, hasClosing = amount, true
+			}
+		}
+
+		balance := statement.OpeningBalance
+		for j, camtEntry := range camtStatement.Entries {
+			entries, err := entriesFromCamt(camtEntry)
+			if err != nil {
+				return nil, fmt.Errorf("statement %s: entry %d: %s", statement.BankStatementID, j+1, err.Error())
+			}
+			for _, entry := range entries {
+				if entry.Direction == domain.BankEntryDirectionCredit {
+					balance += entry.Amount
+				} else {
+					balance -= entry.Amount
+				}
+			}
+			statement.Entries = append(statement.Entries, entries...)
+		}
+		if hasOpening && hasClosing && balance != statement.ClosingBalance {
+			return nil, fmt.Errorf(
+				"statement %s: closing balance %d does not match opening balance and entries %d",
+				statement.BankStatementID,
+				statement.ClosingBalance,
+				balance,
+			)
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+// entriesFromCamt returns nothing for not booked entry
+func entriesFromCamt(camtEntry camtEntry) ([]*domain.BankEntry, error) {
+	status := strings.TrimSpace(camtEntry.Status.Text)
+	if camtEntry.Status.Code != "" {
+		status = strings.TrimSpace(camtEntry.Status.Code)
+	}
+	if status != bookedEntryStatus {
+		return nil, nil
+	}
+
+	entry := &domain.BankEntry{
+		Currency:                 camtEntry.Amount.Currency,
+		AccountServicerReference: strings.TrimSpace(camtEntry.AccountServicerReference),
+		RemittanceInfo:           strings.TrimSpace(camtEntry.AdditionalInfo),
+	}
+	var err error
+	entry.Amount, err = parseAmount(camtEntry.Amount.Value)
+	if err != nil {
+		return nil, err
+	}
+	switch camtEntry.Indicator {
+	case creditIndicator:
+		entry.Direction = domain.BankEntryDirectionCredit
+	case debitIndicator:
+		entry.Direction = domain.BankEntryDirectionDebit
+	default:
+		return nil, fmt.Errorf("credit debit indicator should be one of CRDT, DBIT")
+	}
+	entry.BookingDate, err = parseDate(camtEntry.BookingDate.Date, camtEntry.BookingDate.DateTime)
+	if err != nil {
+		return nil, fmt.Errorf("booking date: %s", err.Error())
+	}
+	entry.ValueDate, err = parseDate(camtEntry.ValueDate.Date, camtEntry.ValueDate.DateTime)
+	if err != nil {
+		entry.ValueDate = entry.BookingDate
+	}
+
+	switch len(camtEntry.Transactions) {
+	case 0:
+		return []*domain.BankEntry{entry}, nil
+	case 1:
+		withTransactionDetails(entry, camtEntry.Transactions[0].EndToEndID, camtEntry.Transactions[0].RemittanceInfo)
+		return []*domain.BankEntry{entry}, nil
+	}
+
+	// batch entry is split only when it has amount of every transaction and they sum up to entry amount
+	entries := make([]*domain.BankEntry, 0, len(camtEntry.Transactions))
+	var sum int64
+	for _, transaction := range camtEntry.Transactions {
+		amount := transaction.Amount
+		if amount.Value == "" {
+			amount = transaction.TransactionAmount
+		}
+		if amount.Value == "" {
+			entries = nil
+			break
+		}
+		transactionEntry := *entry
+		transactionEntry.Amount, err = parseAmount(amount.Value)
+		if err != nil {
+			return nil, fmt.Errorf("transaction amount: %s", err.Error())
+		}
+		withTransactionDetails(&transactionEntry, transaction.EndToEndID, transaction.RemittanceInfo)
+		sum += transactionEntry.Amount
+		entries = append(entries, &transactionEntry)
+	}
+	if entries == nil || sum != entry.Amount {
+		return []*domain.BankEntry{entry}, nil
+	}
+	return entries, nil
+}
+
+func withTransactionDetails(entry *domain.BankEntry, endToEndID string, remittanceInfo []string) {
+	endToEndID = strings.TrimSpace(endToEndID)
+	if endToEndID != notProvidedReference {
+		entry.Reference = endToEndID
+	}
+	if len(remittanceInfo) > 0 {
+		entry.RemittanceInfo = strings.TrimSpace(strings.Join(remittanceInfo, " "))
+	}
+}
+
+func parseSignedAmount(amount camtAmount, indicator string) (int64, error) {
+	value, err := parseAmount(amount.Value)
+	if err != nil {
+		return 0, err
+	}
+	if indicator == debitIndicator {
+		return -value, nil
+	}
+	return value, nil
+}
@@ -0,0 +1,101 @@
+package iso20022
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestParseCamt053(t *testing.T) {
+	file, err := os.Open("testdata/camt053.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	statements, err := ParseCamt053(file)
+
+	assert.Nil(t, err)
+	bookingDate := time.Date(2020, 8, 18, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []*domain.BankStatement{{
+		BankStatementID: "STMT-20200818-RUB",
+		AccountIBAN:     "RU0204452560040702810412345678901",
+		Currency:        "RUB",
+		OpeningBalance:  10000000,
+		ClosingBalance:  10224950,
+		Entries: []*domain.BankEntry{
+			{
+				Direction:                domain.BankEntryDirectionCredit,
+				Amount:                   500000,
+				Currency:                 "RUB",
+				BookingDate:              bookingDate,
+				ValueDate:                bookingDate,
+				Reference:                "65d183df592c096f1f603c9f80cd35f2",
+				AccountServicerReference: "BANKREF-1",
+				RemittanceInfo:           "Пополнение счёта",
+			},
+			{
+				Direction:                domain.BankEntryDirectionDebit,
+				Amount:                   200000,
+				Currency:                 "RUB",
+				BookingDate:              bookingDate,
+				ValueDate:                bookingDate,
+				AccountServicerReference: "BANKREF-2",
+				RemittanceInfo:           "Withdrawal to card",
+			},
+			{
+				Direction:                domain.BankEntryDirectionDebit,
+				Amount:                   75050,
+				Currency:                 "RUB",
+				BookingDate:              bookingDate,
+				ValueDate:                bookingDate,
+				Reference:                "E2E-2",
+				AccountServicerReference: "BANKREF-2",
+			},
+		},
+	}}, statements)
+}
+
+func TestParseCamt053_Error(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		result string
+	}{
+		{
+			"OtherDocument",
+			`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.002.001.03"></Document>`,
+			"document namespace urn:iso:std:iso:20022:tech:xsd:pain.002.001.03 is not camt.053",
+		},
+		{"NoStatements", `<Document><BkToCstmrStmt></BkToCstmrStmt></Document>`, "document has no statements"},
+		{
+			"WrongAmount",
+			`<Document><BkToCstmrStmt><Stmt><Id>1</Id><Acct><Id><IBAN>RU02</IBAN></Id></Acct>
+			<Ntry><Amt Ccy="RUB">1.005</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts></Ntry>
+			</Stmt></BkToCstmrStmt></Document>`,
+			"statement 1: entry 1: wrong amount 1.005",
+		},
+		{
+			"BalanceMismatch",
+			`<Document><BkToCstmrStmt><Stmt><Id>1</Id><Acct><Id><IBAN>RU02</IBAN></Id></Acct>
+			<Bal><Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp><Amt Ccy="RUB">10</Amt><CdtDbtInd>DBIT</CdtDbtInd></Bal>
+			<Bal><Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp><Amt Ccy="RUB">5</Amt><CdtDbtInd>CRDT</CdtDbtInd></Bal>
+			<Ntry><Amt Ccy="RUB">10</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts><Cd>BOOK</Cd></Sts>
+			<BookgDt><Dt>2020-08-18</Dt></BookgDt></Ntry>
+			</Stmt></BkToCstmrStmt></Document>`,
+			"statement 1: closing balance 500 does not match opening balance and entries 0",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseCamt053(strings.NewReader(test.input))
+
+			assert.EqualError(t, err, test.result)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package iso20022
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// amountRegexp is ISO 20022 decimal amount of currency with two fraction digits at most
+var amountRegexp = regexp.MustCompile(`^\d{1,16}(\.\d{1,2})?$`)
+
+const (
+	isoDateFormat     = "2006-01-02"
+	isoDateTimeFormat = "2006-01-02T15:04:05"
+)
+
+// parseAmount converts decimal amount like 1234.5 into minor currency units
+func parseAmount(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if !amountRegexp.MatchString(value) {
+		return 0, fmt.Errorf("wrong amount %s", value)
+	}
+	parts := strings.SplitN(value, ".", 2)
+	fraction := "00"
+	if len(parts) == 2 {
+		fraction = (parts[1] + "0")[:2]
+	}
+	return strconv.ParseInt(parts[0]+fraction, 10, 64)
+}
+
+// formatAmount converts minor currency units into decimal amount with two fraction digits like 1234.50
+func formatAmount(amount int64) string {
+	return fmt.Sprintf("%d.%02d", amount/100, amount%100)
+}
+
+// parseDate reads ISO date or date time, date time without zone is taken as UTC.
+// Result is a date at UTC midnight.
+func parseDate(date string, dateTime string) (time.Time, error) {
+	if date = strings.TrimSpace(date); date != "" {
+		return time.Parse(isoDateFormat, date)
+	}
+	dateTime = strings.TrimSpace(dateTime)
+	parsed, err := time.Parse(time.RFC3339, dateTime)
+	if err != nil {
+		parsed, err = time.Parse(isoDateTimeFormat, dateTime)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("wrong date %s", dateTime)
+	}
+	year, month, day := parsed.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC), nil
+}
@@ -0,0 +1,185 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	pain001DocumentNamespace = "urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"
+	transferPaymentMethod    = "TRF"
+	// sharedChargeBearer means every party pays charges of its own bank
+	sharedChargeBearer = "SHAR"
+	// maxIDLength is a length limit of message, payment information, instruction and end to end ids
+	maxIDLength = 35
+	// notProvidedAgentID identifies agent which BIC is unknown
+	notProvidedAgentID = "NOTPROVIDED"
+)
+
+// Party is an account holder of credit transfer
+type Party struct {
+	Name string
+	IBAN string
+	BIC  string
+}
+
+type pain001Document struct {
+	XMLName     xml.Name             `xml:"urn:iso:std:iso:20022:tech:xsd:pain.001.001.09 Document"`
+	GroupHeader pain001GroupHeader   `xml:"CstmrCdtTrfInitn>GrpHdr"`
+	Payments    []pain001PaymentInfo `xml:"CstmrCdtTrfInitn>PmtInf"`
+}
+
+type pain001GroupHeader struct {
+	MessageID            string `xml:"MsgId"`
+	CreatedAt            string `xml:"CreDtTm"`
+	NumberOfTransactions int    `xml:"NbOfTxs"`
+	ControlSum           string `xml:"CtrlSum"`
+	InitiatingPartyName  string `xml:"InitgPty>Nm"`
+}
+
+type pain001PaymentInfo struct {
+	ID                     string               `xml:"PmtInfId"`
+	Method                 string               `xml:"PmtMtd"`
+	BatchBooking           bool                 `xml:"BtchBookg"`
+	NumberOfTransactions   int                  `xml:"NbOfTxs"`
+	ControlSum             string               `xml:"CtrlSum"`
+	RequestedExecutionDate string               `xml:"ReqdExctnDt>Dt"`
+	DebtorName             string               `xml:"Dbtr>Nm"`
+	DebtorIBAN             string               `xml:"DbtrAcct>Id>IBAN"`
+	DebtorAccountCurrency  string               `xml:"DbtrAcct>Ccy"`
+	DebtorAgent            pain001Agent         `xml:"DbtrAgt"`
+	ChargeBearer           string               `xml:"ChrgBr"`
+	Transactions           []pain001Transaction `xml:"CdtTrfTxInf"`
+}
+
+// pain001Transaction has optional elements as pointers, since omitempty leaves empty parent elements
+type pain001Transaction struct {
+	InstructionID  string             `xml:"PmtId>InstrId"`
+	EndToEndID     string             `xml:"PmtId>EndToEndId"`
+	Amount         camtAmount         `xml:"Amt>InstdAmt"`
+	CreditorAgent  *pain001Agent      `xml:"CdtrAgt"`
+	CreditorName   string             `xml:"Cdtr>Nm"`
+	CreditorIBAN   string             `xml:"CdtrAcct>Id>IBAN"`
+	RemittanceInfo *pain001Remittance `xml:"RmtInf"`
+}
+
+// pain001Agent is identified by BIC, agent without BIC is identified as not provided
+type pain001Agent struct {
+	BIC   string             `xml:"FinInstnId>BICFI,omitempty"`
+	Other *pain001AgentOther `xml:"FinInstnId>Othr"`
+}
+
+type pain001AgentOther struct {
+	ID string `xml:"Id"`
+}
+
+func newPain001Agent(bic string) pain001Agent {
+	if bic == "" {
+		return pain001Agent{Other: &pain001AgentOther{ID: notProvidedAgentID}}
+	}
+	return pain001Agent{BIC: bic}
+}
+
+type pain001Remittance struct {
+	Unstructured string `xml:"Ustrd"`
+}
+
+// PaymentInfoID is an id of payment information block of payouts in currency
+func PaymentInfoID(messageID string, currency string) string {
+	return fmt.Sprintf("%.*s-%s", maxIDLength-len(currency)-1, messageID, currency)
+}
+
+// GeneratePain001 writes pain.001.001.09 credit transfer initiation of payouts paid from debtor account.
+// Payouts are grouped into payment information blocks by currency in order of appearance, every block
+// is requested to be executed at the date of createdAt. Payout id is used as end to end id, so that bank
+// reports it back in statements and status reports.
+func GeneratePain001(
+	w io.Writer,
+	messageID string,
+	createdAt time.Time,
+	debtor Party,
+	payouts []*domain.Payout,
+) error {
+	if len(messageID) > maxIDLength {
+		return fmt.Errorf("message id should be at most %d characters long", maxIDLength)
+	}
+	if len(payouts) == 0 {
+		return fmt.Errorf("no payouts to initiate")
+	}
+
+	document := pain001Document{
+		GroupHeader: pain001GroupHeader{
+			MessageID:            messageID,
+			CreatedAt:            createdAt.UTC().Format(time.RFC3339),
+			NumberOfTransactions: len(payouts),
+			InitiatingPartyName:  debtor.Name,
+		},
+	}
+	var controlSum int64
+	paymentIndexes := make(map[string]int)
+	paymentSums := make(map[string]int64)
+	for _, payout := range payouts {
+		if len(payout.GeneratedID) > maxIDLength {
+			return fmt.Errorf("payout %s: id should be at most %d characters long", payout.GeneratedID, maxIDLength)
+		}
+		if payout.Amount <= 0 {
+			return fmt.Errorf("payout %s: amount should be positive", payout.GeneratedID)
+		}
+
+		index, ok := paymentIndexes[payout.Currency]
+		if !ok {
+			index = len(document.Payments)
+			paymentIndexes[payout.Currency] = index
+			document.Payments = append(document.Payments, pain001PaymentInfo{
+				ID:     PaymentInfoID(messageID, payout.Currency),
+				Method: transferPaymentMethod,
+				// every payout is booked separately, so that statement entries could be matched to payouts
+				BatchBooking:           false,
+				RequestedExecutionDate: createdAt.UTC().Format(isoDateFormat),
+				DebtorName:             debtor.Name,
+				DebtorIBAN:             debtor.IBAN,
+				DebtorAccountCurrency:  payout.Currency,
+				DebtorAgent:            newPain001Agent(debtor.BIC),
+				ChargeBearer:           sharedChargeBearer,
+			})
+		}
+
+		transaction := pain001Transaction{
+			InstructionID: payout.GeneratedID,
+			EndToEndID:    payout.GeneratedID,
+			Amount:        camtAmount{Value: formatAmount(payout.Amount), Currency: payout.Currency},
+			CreditorName:  payout.CreditorName,
+			CreditorIBAN:  payout.CreditorIBAN,
+		}
+		if payout.CreditorBIC != "" {
+			transaction.CreditorAgent = &pain001Agent{BIC: payout.CreditorBIC}
+		}
+		if payout.RemittanceInfo != "" {
+			transaction.RemittanceInfo = &pain001Remittance{Unstructured: payout.RemittanceInfo}
+		}
+		payment := &document.Payments[index]
+		payment.Transactions = append(payment.Transactions, transaction)
+		payment.NumberOfTransactions++
+		paymentSums[payout.Currency] += payout.Amount
+		payment.ControlSum = formatAmount(paymentSums[payout.Currency])
+		controlSum += payout.Amount
+	}
+	document.GroupHeader.ControlSum = formatAmount(controlSum)
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(document)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
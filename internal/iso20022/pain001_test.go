@@ -0,0 +1,78 @@
+package iso20022
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestGeneratePain001(t *testing.T) {
+	expected, err := ioutil.ReadFile("testdata/pain001.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	debtor := Party{Name: "Payment System LLC", IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX"}
+	payouts := []*domain.Payout{
+		{
+			GeneratedID:    "f275681cd0b3c828fead8d3cd392fd4f",
+			Amount:         150050,
+			Currency:       "EUR",
+			CreditorName:   "Ivan Ivanov",
+			CreditorIBAN:   "GB29NWBK60161331926819",
+			CreditorBIC:    "NWBKGB2L",
+			RemittanceInfo: "Withdrawal & refund",
+		},
+		{
+			GeneratedID:  "8a3bb3ef5e9f4b0cd6ee2a6b0a6e2f51",
+			Amount:       2500000,
+			Currency:     "RUB",
+			CreditorName: "Петр Петров",
+			CreditorIBAN: "DE89370400440532013000",
+		},
+		{
+			GeneratedID:  "0e6b1bc1a4dd2e4b6d3fa16f3f7b7c32",
+			Amount:       99,
+			Currency:     "EUR",
+			CreditorName: "Anna Smith",
+			CreditorIBAN: "GB29NWBK60161331926819",
+		},
+	}
+	createdAt := time.Date(2020, 8, 18, 13, 15, 0, 0, time.FixedZone("MSK", 3*60*60))
+
+	buffer := &bytes.Buffer{}
+	err = GeneratePain001(buffer, "e831dcc7e531e40411818ed06634f209", createdAt, debtor, payouts)
+
+	assert.Nil(t, err)
+	assert.Equal(t, string(expected), buffer.String())
+}
+
+func TestGeneratePain001_NoDebtorBIC(t *testing.T) {
+	debtor := Party{Name: "Payment System LLC", IBAN: "DE89370400440532013000"}
+	payouts := []*domain.Payout{
+		{GeneratedID: "payout", Amount: 100, Currency: "EUR", CreditorIBAN: "GB29NWBK60161331926819"},
+	}
+
+	buffer := &bytes.Buffer{}
+	err := GeneratePain001(buffer, "batch", time.Now(), debtor, payouts)
+
+	assert.Nil(t, err)
+	assert.Contains(t, buffer.String(), "<Othr>\n            <Id>NOTPROVIDED</Id>\n          </Othr>")
+	assert.NotContains(t, buffer.String(), "<BICFI>")
+}
+
+func TestGeneratePain001_WrongPayout(t *testing.T) {
+	payouts := []*domain.Payout{{GeneratedID: "payout", Amount: 0, Currency: "EUR"}}
+
+	err := GeneratePain001(&bytes.Buffer{}, "batch", time.Now(), Party{}, payouts)
+
+	assert.EqualError(t, err, "payout payout: amount should be positive")
+}
+
+func TestPaymentInfoID(t *testing.T) {
+	assert.Equal(t, "e831dcc7e531e40411818ed06634f20-EUR", PaymentInfoID("e831dcc7e531e40411818ed06634f209", "EUR"))
+	assert.Equal(t, "batch-EUR", PaymentInfoID("batch", "EUR"))
+}
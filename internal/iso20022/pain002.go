@@ -0,0 +1,143 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	pain002DocumentNamespace = "urn:iso:std:iso:20022:tech:xsd:pain.002"
+	// settlementCompletedStatus is set when debtor account is debited, creditor account status is reported
+	// by some banks as settlementCompletedCreditorStatus
+	settlementCompletedStatus         = "ACSC"
+	settlementCompletedCreditorStatus = "ACCC"
+	rejectedStatus                    = "RJCT"
+)
+
+// pain002Document is a subset of pain.002 CustomerPaymentStatusReport common for versions 001.03 - 001.10.
+// Elements are matched by local names, so any version namespace is accepted.
+type pain002Document struct {
+	XMLName xml.Name `xml:"Document"`
+	Group   struct {
+		OriginalMessageID string          `xml:"OrgnlMsgId"`
+		Status            string          `xml:"GrpSts"`
+		Reasons           []pain002Reason `xml:"StsRsnInf"`
+	} `xml:"CstmrPmtStsRpt>OrgnlGrpInfAndSts"`
+	Payments []struct {
+		ID           string          `xml:"OrgnlPmtInfId"`
+		Status       string          `xml:"PmtInfSts"`
+		Reasons      []pain002Reason `xml:"StsRsnInf"`
+		Transactions []struct {
+			EndToEndID string          `xml:"OrgnlEndToEndId"`
+			Status     string          `xml:"TxSts"`
+			Reasons    []pain002Reason `xml:"StsRsnInf"`
+		} `xml:"TxInfAndSts"`
+	} `xml:"CstmrPmtStsRpt>OrgnlPmtInfAndSts"`
+}
+
+type pain002Reason struct {
+	Code           string   `xml:"Rsn>Cd"`
+	Proprietary    string   `xml:"Rsn>Prtry"`
+	AdditionalInfo []string `xml:"AddtlInf"`
+}
+
+// ParsePain002 reads final statuses of payment status report of pain.001 file. Intermediate statuses
+// like ACCP or PDNG are skipped. Updates of single payouts go first and updates of payment information
+// blocks go before update of whole file, so that status of payout is taken from the narrowest level.
+func ParsePain002(reader io.Reader) (batchID string, updates []*domain.PayoutStatusUpdate, err error) {
+	document := pain002Document{}
+	err = xml.NewDecoder(reader).Decode(&document)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to decode xml: %s", err.Error())
+	}
+	if document.XMLName.Space != "" && !strings.HasPrefix(document.XMLName.Space, pain002DocumentNamespace) {
+		return "", nil, fmt.Errorf("document namespace %s is not pain.002", document.XMLName.Space)
+	}
+	batchID = strings.TrimSpace(document.Group.OriginalMessageID)
+	if batchID == "" {
+		return "", nil, fmt.Errorf("original message id is mandatory")
+	}
+
+	var paymentUpdates []*domain.PayoutStatusUpdate
+	for _, payment := range document.Payments {
+		paymentInfoID := strings.TrimSpace(payment.ID)
+		for _, transaction := range payment.Transactions {
+			status, ok := payoutStatus(transaction.Status)
+			if !ok {
+				continue
+			}
+			payoutID := strings.TrimSpace(transaction.EndToEndID)
+			if payoutID == "" {
+				return "", nil, fmt.Errorf("payment %s: original end to end id is mandatory", paymentInfoID)
+			}
+			updates = append(updates, &domain.PayoutStatusUpdate{
+				BatchID:       batchID,
+				PaymentInfoID: paymentInfoID,
+				PayoutID:      payoutID,
+				Status:        status,
+				Reason:        reasonText(transaction.Reasons),
+			})
+		}
+
+		status, ok := payoutStatus(payment.Status)
+		if !ok {
+			continue
+		}
+		if paymentInfoID == "" {
+			return "", nil, fmt.Errorf("original payment information id is mandatory")
+		}
+		paymentUpdates = append(paymentUpdates, &domain.PayoutStatusUpdate{
+			BatchID:       batchID,
+			PaymentInfoID: paymentInfoID,
+			Status:        status,
+			Reason:        reasonText(payment.Reasons),
+		})
+	}
+	updates = append(updates, paymentUpdates...)
+
+	status, ok := payoutStatus(document.Group.Status)
+	if ok {
+		updates = append(updates, &domain.PayoutStatusUpdate{
+			BatchID: batchID,
+			Status:  status,
+			Reason:  reasonText(document.Group.Reasons),
+		})
+	}
+	return batchID, updates, nil
+}
+
+// payoutStatus returns false for intermediate statuses
+func payoutStatus(status string) (domain.PayoutStatus, bool) {
+	switch strings.TrimSpace(status) {
+	case settlementCompletedStatus, settlementCompletedCreditorStatus:
+		return domain.PayoutStatusSettled, true
+	case rejectedStatus:
+		return domain.PayoutStatusRejected, true
+	default:
+		return "", false
+	}
+}
+
+// reasonText joins reason codes with additional information like "AC04 Closed account"
+func reasonText(reasons []pain002Reason) string {
+	var parts []string
+	for _, reason := range reasons {
+		code := strings.TrimSpace(reason.Code)
+		if code == "" {
+			code = strings.TrimSpace(reason.Proprietary)
+		}
+		if code != "" {
+			parts = append(parts, code)
+		}
+		for _, info := range reason.AdditionalInfo {
+			if info = strings.TrimSpace(info); info != "" {
+				parts = append(parts, info)
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
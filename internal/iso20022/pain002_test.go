@@ -0,0 +1,69 @@
+package iso20022
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestParsePain002(t *testing.T) {
+	file, err := os.Open("testdata/pain002.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	batchID, updates, err := ParsePain002(file)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "e831dcc7e531e40411818ed06634f209", batchID)
+	assert.Equal(t, []*domain.PayoutStatusUpdate{
+		{
+			BatchID:       batchID,
+			PaymentInfoID: "e831dcc7e531e40411818ed06634f20-EUR",
+			PayoutID:      "f275681cd0b3c828fead8d3cd392fd4f",
+			Status:        domain.PayoutStatusRejected,
+			Reason:        "AC04 Closed account",
+		},
+		{
+			BatchID:       batchID,
+			PaymentInfoID: "e831dcc7e531e40411818ed06634f20-RUB",
+			PayoutID:      "8a3bb3ef5e9f4b0cd6ee2a6b0a6e2f51",
+			Status:        domain.PayoutStatusSettled,
+		},
+		{
+			BatchID:       batchID,
+			PaymentInfoID: "e831dcc7e531e40411818ed06634f20-EUR",
+			Status:        domain.PayoutStatusSettled,
+		},
+	}, updates)
+}
+
+func TestParsePain002_GroupRejected(t *testing.T) {
+	document := `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.002.001.03"><CstmrPmtStsRpt>
+		<OrgnlGrpInfAndSts>
+			<OrgnlMsgId>batch</OrgnlMsgId>
+			<GrpSts>RJCT</GrpSts>
+			<StsRsnInf><Rsn><Cd>FF01</Cd></Rsn></StsRsnInf>
+		</OrgnlGrpInfAndSts>
+	</CstmrPmtStsRpt></Document>`
+
+	batchID, updates, err := ParsePain002(strings.NewReader(document))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "batch", batchID)
+	assert.Equal(t, []*domain.PayoutStatusUpdate{
+		{BatchID: "batch", Status: domain.PayoutStatusRejected, Reason: "FF01"},
+	}, updates)
+}
+
+func TestParsePain002_WrongNamespace(t *testing.T) {
+	document := `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"></Document>`
+
+	_, _, err := ParsePain002(strings.NewReader(document))
+
+	assert.EqualError(t, err, "document namespace urn:iso:std:iso:20022:tech:xsd:camt.053.001.02 is not pain.002")
+}
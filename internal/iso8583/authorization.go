@@ -0,0 +1,206 @@
+package iso8583
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/issuing"
+)
+
+const (
+	MTIAuthorizationRequest  = "0100"
+	MTIAuthorizationResponse = "0110"
+	MTIFinancialRequest      = "0200"
+	MTIFinancialResponse     = "0210"
+	MTIReversalRequest       = "0400"
+	MTIReversalResponse      = "0410"
+)
+
+const (
+	FieldPAN                  = 2
+	FieldProcessingCode       = 3
+	FieldAmount               = 4
+	FieldTransmissionDateTime = 7
+	FieldSTAN                 = 11
+	FieldLocalTime            = 12
+	FieldLocalDate            = 13
+	FieldExpiryDate           = 14
+	FieldMerchantType         = 18
+	FieldAcquiringInstitution = 32
+	FieldRetrievalReference   = 37
+	FieldAuthorizationID      = 38
+	FieldResponseCode         = 39
+	FieldTerminalID           = 41
+	FieldCardAcceptorID       = 42
+	FieldCardAcceptorName     = 43
+	FieldCurrencyCode         = 49
+	FieldOriginalDataElements = 90
+)
+
+const (
+	authorizationIDLength      = 6
+	acquiringInstitutionLength = 11
+	// cardAcceptorNameLength is a length of name part of card acceptor name and location followed by city and country
+	cardAcceptorNameLength = 25
+	// originalDataElementsLength is a length of MTI, STAN, transmission date and time, acquiring
+	// and forwarding institution codes of original request
+	originalDataElementsLength = 42
+)
+
+// Response codes answered when request is not decided by issuing rules
+const (
+	ResponseCodeInvalidTransaction = "12"
+	ResponseCodeInvalidCardNumber  = "14"
+	ResponseCodeRecordNotFound     = "25"
+	ResponseCodeFormatError        = "30"
+	ResponseCodeSystemMalfunction  = "96"
+)
+
+// echoedFields are copied from request to response, so that acquirer matches response with request
+var echoedFields = []int{
+	FieldPAN,
+	FieldProcessingCode,
+	FieldAmount,
+	FieldTransmissionDateTime,
+	FieldSTAN,
+	FieldLocalTime,
+	FieldLocalDate,
+	FieldAcquiringInstitution,
+	FieldRetrievalReference,
+	FieldTerminalID,
+	FieldCardAcceptorID,
+	FieldCurrencyCode,
+	FieldOriginalDataElements,
+}
+
+// currencyCodes are ISO 4217 numeric codes of currencies accounts are kept in
+var currencyCodes = map[string]string{
+	"156": "CNY",
+	"392": "JPY",
+	"398": "KZT",
+	"643": "RUB",
+	"756": "CHF",
+	"826": "GBP",
+	"840": "USD",
+	"933": "BYN",
+	"949": "TRY",
+	"978": "EUR",
+}
+
+// Authorizer decides on authorization requests of card network
+type Authorizer interface {
+	Authorize(request *domain.CardAuthorizationRequest) (*domain.CardAuthorization, error)
+	Capture(authorizationID string) (*domain.CardAuthorization, error)
+	ReverseByNetworkReference(reference string) (*domain.CardAuthorization, error)
+}
+
+// authorizationRequest maps 0100 and 0200 request to internal authorization request
+func authorizationRequest(message *Message) (*domain.CardAuthorizationRequest, error) {
+	for _, number := range []int{
+		FieldPAN,
+		FieldAmount,
+		FieldTransmissionDateTime,
+		FieldSTAN,
+		FieldExpiryDate,
+		FieldMerchantType,
+		FieldAcquiringInstitution,
+		FieldCurrencyCode,
+	} {
+		if !message.Has(number) {
+			return nil, fmt.Errorf("field %d is mandatory", number)
+		}
+	}
+	amount, err := strconv.ParseInt(message.Get(FieldAmount), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("field %d: %s", FieldAmount, err.Error())
+	}
+	expiry := message.Get(FieldExpiryDate)
+	if len(expiry) != 4 {
+		return nil, fmt.Errorf("field %d should be YYMM", FieldExpiryDate)
+	}
+	year, err := strconv.Atoi(expiry[:2])
+	if err != nil {
+		return nil, fmt.Errorf("field %d: %s", FieldExpiryDate, err.Error())
+	}
+	month, err := strconv.Atoi(expiry[2:])
+	if err != nil {
+		return nil, fmt.Errorf("field %d: %s", FieldExpiryDate, err.Error())
+	}
+	currencyCode := message.Get(FieldCurrencyCode)
+	currency, ok := currencyCodes[currencyCode]
+	if !ok {
+		return nil, fmt.Errorf("field %d: currency %s is not supported", FieldCurrencyCode, currencyCode)
+	}
+	merchantName := message.Get(FieldCardAcceptorName)
+	if len(merchantName) > cardAcceptorNameLength {
+		merchantName = merchantName[:cardAcceptorNameLength]
+	}
+
+	return &domain.CardAuthorizationRequest{
+		PAN:          message.Get(FieldPAN),
+		ExpiryMonth:  month,
+		ExpiryYear:   2000 + year,
+		Amount:       amount,
+		Currency:     currency,
+		MCC:          message.Get(FieldMerchantType),
+		MerchantName: strings.TrimSpace(merchantName),
+		NetworkReference: networkReference(
+			message.Get(FieldAcquiringInstitution),
+			message.Get(FieldSTAN),
+			message.Get(FieldTransmissionDateTime),
+		),
+	}, nil
+}
+
+// reversalReference is network reference of request reversed by 0400 request
+func reversalReference(message *Message) (string, error) {
+	original := message.Get(FieldOriginalDataElements)
+	if len(original) != originalDataElementsLength {
+		return "", fmt.Errorf("field %d is mandatory", FieldOriginalDataElements)
+	}
+	return networkReference(original[20:31], original[4:10], original[10:20]), nil
+}
+
+// networkReference identifies request by acquirer, STAN and transmission time the same way
+// for request and its reversal
+func networkReference(acquirer string, stan string, transmittedAt string) string {
+	if len(acquirer) < acquiringInstitutionLength {
+		acquirer = strings.Repeat("0", acquiringInstitutionLength-len(acquirer)) + acquirer
+	}
+	return acquirer + ":" + stan + ":" + transmittedAt
+}
+
+// responseTo copies echoed fields of request to response with response code
+func responseTo(request *Message, mti string, responseCode string) *Message {
+	response := NewMessage(mti)
+	for _, number := range echoedFields {
+		if request.Has(number) {
+			response.Set(number, request.Get(number))
+		}
+	}
+	response.Set(FieldResponseCode, responseCode)
+	return response
+}
+
+// authorizationResponse answers with response code of decision, approved authorization is identified
+// by the first characters of its id
+func authorizationResponse(request *Message, mti string, authorization *domain.CardAuthorization) *Message {
+	response := responseTo(request, mti, issuing.ResponseCode(authorization))
+	if authorization.Status != domain.CardAuthorizationStatusDeclined {
+		response.Set(FieldAuthorizationID, strings.ToUpper(authorization.GeneratedID[:authorizationIDLength]))
+	}
+	return response
+}
+
+// errorResponseCode tells network why request is not decided
+func errorResponseCode(err error, notFoundCode string) string {
+	switch err.(type) {
+	case *domain.NotFoundError:
+		return notFoundCode
+	case *domain.ValidationError:
+		return ResponseCodeInvalidTransaction
+	}
+	return ResponseCodeSystemMalfunction
+}
@@ -0,0 +1,62 @@
+package iso8583
+
+import (
+	"fmt"
+	"strings"
+)
+
+// encode writes value of digits or characters in encoding
+func encode(encoding Encoding, value string) ([]byte, error) {
+	if encoding == EncodingASCII {
+		for i := 0; i < len(value); i++ {
+			if value[i] < 0x20 || value[i] > 0x7e {
+				return nil, fmt.Errorf("value should be printable ASCII")
+			}
+		}
+		return []byte(value), nil
+	}
+
+	if strings.Trim(value, "0123456789") != "" {
+		return nil, fmt.Errorf("value should be digits")
+	}
+	if len(value)%2 == 1 {
+		value = "0" + value
+	}
+	packed := make([]byte, len(value)/2)
+	for i := range packed {
+		packed[i] = (value[2*i]-'0')<<4 | (value[2*i+1] - '0')
+	}
+	return packed, nil
+}
+
+// decode reads value of length digits or characters in encoding from the beginning of data
+// and returns number of bytes read
+func decode(encoding Encoding, data []byte, length int) (string, int, error) {
+	size := encodedSize(encoding, length)
+	if len(data) < size {
+		return "", 0, fmt.Errorf("%d bytes expected, %d left", size, len(data))
+	}
+
+	if encoding == EncodingASCII {
+		return string(data[:size]), size, nil
+	}
+
+	digits := make([]byte, 2*size)
+	for i, b := range data[:size] {
+		high, low := b>>4, b&0x0f
+		if high > 9 || low > 9 {
+			return "", 0, fmt.Errorf("byte %#02x is not BCD", b)
+		}
+		digits[2*i], digits[2*i+1] = '0'+high, '0'+low
+	}
+	// odd number of digits is padded with leading zero
+	return string(digits[len(digits)-length:]), size, nil
+}
+
+// encodedSize is a number of bytes length digits or characters take in encoding
+func encodedSize(encoding Encoding, length int) int {
+	if encoding == EncodingBCD {
+		return (length + 1) / 2
+	}
+	return length
+}
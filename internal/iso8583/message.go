@@ -0,0 +1,196 @@
+package iso8583
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+const (
+	mtiLength    = 4
+	bitmapLength = 8
+)
+
+// Message is an ISO 8583 message, fields are kept by number as digits or characters of their values
+type Message struct {
+	MTI    string
+	Fields map[int]string
+}
+
+func NewMessage(mti string) *Message {
+	return &Message{MTI: mti, Fields: map[int]string{}}
+}
+
+func (m *Message) Set(number int, value string) {
+	m.Fields[number] = value
+}
+
+// Get returns empty value for absent field
+func (m *Message) Get(number int) string {
+	return m.Fields[number]
+}
+
+func (m *Message) Has(number int) bool {
+	_, ok := m.Fields[number]
+	return ok
+}
+
+// Codec packs messages to bytes and unpacks them back as described by spec
+type Codec struct {
+	spec *Spec
+}
+
+func NewCodec(spec *Spec) *Codec {
+	return &Codec{spec: spec}
+}
+
+// Pack writes MTI, bitmap and fields in ascending order. Secondary bitmap is written when any of fields
+// 65-128 is set. Fixed field value should be exactly of field length.
+func (c *Codec) Pack(message *Message) ([]byte, error) {
+	if len(message.MTI) != mtiLength {
+		return nil, fmt.Errorf("mti should be %d digits", mtiLength)
+	}
+	packed, err := encode(c.spec.MTIEncoding, message.MTI)
+	if err != nil {
+		return nil, fmt.Errorf("mti: %s", err.Error())
+	}
+
+	numbers := make([]int, 0, len(message.Fields))
+	for number := range message.Fields {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	bitmap := make([]byte, bitmapLength)
+	if len(numbers) > 0 && numbers[len(numbers)-1] > 64 {
+		bitmap = make([]byte, 2*bitmapLength)
+		setBit(bitmap, 1)
+	}
+	var fields []byte
+	for _, number := range numbers {
+		field, ok := c.spec.Fields[number]
+		if !ok {
+			return nil, fmt.Errorf("field %d is not in spec", number)
+		}
+		value, err := packField(field, message.Fields[number])
+		if err != nil {
+			return nil, fmt.Errorf("field %d: %s", number, err.Error())
+		}
+		setBit(bitmap, number)
+		fields = append(fields, value...)
+	}
+
+	packed = append(packed, bitmap...)
+	return append(packed, fields...), nil
+}
+
+// Unpack reads message packed by Pack. Fields which are not in spec and trailing bytes are errors,
+// as the rest of message could not be read reliably.
+func (c *Codec) Unpack(data []byte) (*Message, error) {
+	message, err := c.unpack(data)
+	if err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// UnpackPartial reads MTI and fields of message which could not be unpacked up to the first broken field,
+// so that message could be answered with format error. Message is nil when its MTI could not be read.
+func (c *Codec) UnpackPartial(data []byte) *Message {
+	message, _ := c.unpack(data)
+	return message
+}
+
+// unpack returns message read before error along with it
+func (c *Codec) unpack(data []byte) (*Message, error) {
+	mti, offset, err := decode(c.spec.MTIEncoding, data, mtiLength)
+	if err != nil {
+		return nil, fmt.Errorf("mti: %s", err.Error())
+	}
+	message := NewMessage(mti)
+
+	if len(data[offset:]) < bitmapLength {
+		return message, fmt.Errorf("bitmap: %d bytes expected, %d left", bitmapLength, len(data[offset:]))
+	}
+	bitmap := data[offset : offset+bitmapLength]
+	if bitSet(bitmap, 1) {
+		if len(data[offset:]) < 2*bitmapLength {
+			return message, fmt.Errorf("secondary bitmap: %d bytes expected", bitmapLength)
+		}
+		bitmap = data[offset : offset+2*bitmapLength]
+	}
+	offset += len(bitmap)
+
+	for number := 2; number <= 8*len(bitmap); number++ {
+		if !bitSet(bitmap, number) {
+			continue
+		}
+		field, ok := c.spec.Fields[number]
+		if !ok {
+			return message, fmt.Errorf("field %d is not in spec", number)
+		}
+		value, size, err := unpackField(field, data[offset:])
+		if err != nil {
+			return message, fmt.Errorf("field %d: %s", number, err.Error())
+		}
+		message.Set(number, value)
+		offset += size
+	}
+	if offset != len(data) {
+		return message, fmt.Errorf("%d bytes left after the last field", len(data)-offset)
+	}
+	return message, nil
+}
+
+func packField(field FieldSpec, value string) ([]byte, error) {
+	if field.Prefix == PrefixFixed && len(value) != field.Length {
+		return nil, fmt.Errorf("value should be %d long", field.Length)
+	}
+	if len(value) > field.Length {
+		return nil, fmt.Errorf("value should not exceed %d", field.Length)
+	}
+	encoded, err := encode(field.Encoding, value)
+	if err != nil {
+		return nil, err
+	}
+	if field.Prefix == PrefixFixed {
+		return encoded, nil
+	}
+	prefix, err := encode(field.Encoding, fmt.Sprintf("%0*d", field.prefixDigits(), len(value)))
+	if err != nil {
+		return nil, err
+	}
+	return append(prefix, encoded...), nil
+}
+
+func unpackField(field FieldSpec, data []byte) (string, int, error) {
+	length, prefixSize := field.Length, 0
+	if field.Prefix != PrefixFixed {
+		prefix, size, err := decode(field.Encoding, data, field.prefixDigits())
+		if err != nil {
+			return "", 0, fmt.Errorf("length: %s", err.Error())
+		}
+		length, err = strconv.Atoi(prefix)
+		if err != nil {
+			return "", 0, fmt.Errorf("length %q is not a number", prefix)
+		}
+		if length > field.Length {
+			return "", 0, fmt.Errorf("length %d exceeds %d", length, field.Length)
+		}
+		prefixSize = size
+	}
+	value, size, err := decode(field.Encoding, data[prefixSize:], length)
+	if err != nil {
+		return "", 0, err
+	}
+	return value, prefixSize + size, nil
+}
+
+// setBit marks field in bitmap, field 1 is the most significant bit of the first byte
+func setBit(bitmap []byte, number int) {
+	bitmap[(number-1)/8] |= 0x80 >> uint((number-1)%8)
+}
+
+func bitSet(bitmap []byte, number int) bool {
+	return bitmap[(number-1)/8]&(0x80>>uint((number-1)%8)) != 0
+}
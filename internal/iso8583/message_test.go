@@ -0,0 +1,153 @@
+package iso8583
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readFixture reads hex dump of message, every line is a part of message followed by # comment. Fixtures are
+// synthetic messages written by hand after the spec, they are not captured from processor.
+func readFixture(t *testing.T, path string) []byte {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dump strings.Builder
+	for _, line := range strings.Split(string(content), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		dump.WriteString(strings.Join(strings.Fields(line), ""))
+	}
+	data, err := hex.DecodeString(dump.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func newTestCodec(t *testing.T) *Codec {
+	spec, err := LoadSpec("../../configs/iso8583_1987.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewCodec(spec)
+}
+
+// TestCodec_Fixtures unpacks every testdata/*.hex message and packs it back to the same bytes
+func TestCodec_Fixtures(t *testing.T) {
+	t.Parallel()
+
+	codec := newTestCodec(t)
+	paths, err := filepath.Glob("testdata/*.hex")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		data := readFixture(t, path)
+		message, err := codec.Unpack(data)
+		if !assert.NoError(t, err, path) {
+			continue
+		}
+		assert.Equal(t, filepath.Base(path)[:4], message.MTI, path)
+		packed, err := codec.Pack(message)
+		assert.NoError(t, err, path)
+		assert.Equal(t, data, packed, path)
+	}
+}
+
+func TestCodec_UnpackAuthorizationRequest(t *testing.T) {
+	t.Parallel()
+
+	message, err := newTestCodec(t).Unpack(readFixture(t, "testdata/0100_authorization_request.hex"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, MTIAuthorizationRequest, message.MTI)
+	assert.Len(t, message.Fields, 17)
+	assert.Equal(t, "2200120000001230", message.Get(FieldPAN))
+	assert.Equal(t, "000000015000", message.Get(FieldAmount))
+	assert.Equal(t, "2408", message.Get(FieldExpiryDate))
+	assert.Equal(t, "010", message.Get(22))
+	assert.Equal(t, "12345", message.Get(FieldAcquiringInstitution))
+	assert.Equal(t, "023112000123", message.Get(FieldRetrievalReference))
+	assert.Equal(t, "GROCERY STORE            MOSCOW       RU", message.Get(FieldCardAcceptorName))
+	assert.Equal(t, "643", message.Get(FieldCurrencyCode))
+}
+
+func TestCodec_UnpackReversalRequest(t *testing.T) {
+	t.Parallel()
+
+	message, err := newTestCodec(t).Unpack(readFixture(t, "testdata/0400_reversal_request.hex"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, MTIReversalRequest, message.MTI)
+	assert.Equal(t, "010000012308181200000000001234500000000000", message.Get(FieldOriginalDataElements))
+}
+
+func TestCodec_ASCIIVariableFields(t *testing.T) {
+	t.Parallel()
+
+	codec := NewCodec(&Spec{
+		MTIEncoding: EncodingASCII,
+		Fields: map[int]FieldSpec{
+			2:  {Length: 19, Prefix: PrefixLLVAR, Encoding: EncodingASCII},
+			48: {Length: 999, Prefix: PrefixLLLVAR, Encoding: EncodingASCII},
+		},
+	})
+	message := NewMessage("0100")
+	message.Set(2, "2200120000001230")
+	message.Set(48, "CVV2=123")
+
+	packed, err := codec.Pack(message)
+	assert.NoError(t, err)
+	assert.Equal(t, "0100\x40\x00\x00\x00\x00\x01\x00\x00"+"162200120000001230"+"008CVV2=123", string(packed))
+	unpacked, err := codec.Unpack(packed)
+	assert.NoError(t, err)
+	assert.Equal(t, message, unpacked)
+}
+
+func TestCodec_Errors(t *testing.T) {
+	t.Parallel()
+
+	codec := newTestCodec(t)
+	data := readFixture(t, "testdata/0100_authorization_request.hex")
+
+	_, err := codec.Unpack(data[:len(data)-1])
+	assert.EqualError(t, err, "field 49: 2 bytes expected, 1 left")
+	_, err = codec.Unpack(append(data, 0))
+	assert.EqualError(t, err, "1 bytes left after the last field")
+	_, err = codec.Unpack(data[:5])
+	assert.EqualError(t, err, "bitmap: 8 bytes expected, 3 left")
+	corrupted := append([]byte{}, data...)
+	corrupted[11] = 0x2a
+	_, err = codec.Unpack(corrupted)
+	assert.EqualError(t, err, "field 2: byte 0x2a is not BCD")
+	corrupted = append([]byte{}, data...)
+	corrupted[2] |= 0x01
+	_, err = codec.Unpack(corrupted)
+	assert.EqualError(t, err, "field 8 is not in spec")
+
+	message := NewMessage("0100")
+	message.Set(FieldAmount, "15000")
+	_, err = codec.Pack(message)
+	assert.EqualError(t, err, "field 4: value should be 12 long")
+	message = NewMessage("0100")
+	message.Set(FieldPAN, "22001200000012301234")
+	_, err = codec.Pack(message)
+	assert.EqualError(t, err, "field 2: value should not exceed 19")
+	message = NewMessage("0100")
+	message.Set(FieldSTAN, "00012a")
+	_, err = codec.Pack(message)
+	assert.EqualError(t, err, "field 11: value should be digits")
+	message = NewMessage("0100")
+	message.Set(8, "1")
+	_, err = codec.Pack(message)
+	assert.EqualError(t, err, "field 8 is not in spec")
+	_, err = codec.Pack(NewMessage("100"))
+	assert.EqualError(t, err, "mti should be 4 digits")
+}
@@ -0,0 +1,197 @@
+package iso8583
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"go.uber.org/zap"
+)
+
+// frameHeaderLength is a length of big endian message length written before every message on connection
+const frameHeaderLength = 2
+
+// Server answers requests of card network processor. Every connection is served in its own goroutine,
+// requests of connection are answered in order.
+type Server struct {
+	logger     *zap.Logger
+	codec      *Codec
+	authorizer Authorizer
+}
+
+func NewServer(logger *zap.Logger, codec *Codec, authorizer Authorizer) *Server {
+	return &Server{logger: logger, codec: codec, authorizer: authorizer}
+}
+
+// Serve accepts connections until listener is closed
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// responseMTIs are MTIs of responses to requests of card network
+var responseMTIs = map[string]string{
+	MTIAuthorizationRequest: MTIAuthorizationResponse,
+	MTIFinancialRequest:     MTIFinancialResponse,
+	MTIReversalRequest:      MTIReversalResponse,
+}
+
+// Handle maps 0100 request to authorization, 0200 request to authorization captured at once
+// and 0400 request to reversal of original request
+func (s *Server) Handle(request *Message) *Message {
+	switch request.MTI {
+	case MTIAuthorizationRequest:
+		return s.authorize(request, MTIAuthorizationResponse, false)
+	case MTIFinancialRequest:
+		return s.authorize(request, MTIFinancialResponse, true)
+	case MTIReversalRequest:
+		return s.reverse(request)
+	}
+	return nil
+}
+
+func (s *Server) authorize(request *Message, mti string, capture bool) *Message {
+	authorizationRequest, err := authorizationRequest(request)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("wrong %s request: %s", request.MTI, err.Error()))
+		return responseTo(request, mti, ResponseCodeFormatError)
+	}
+	authorization, err := s.authorizer.Authorize(authorizationRequest)
+	if err != nil {
+		return s.errorResponse(request, mti, err, ResponseCodeInvalidCardNumber)
+	}
+	if capture && authorization.Status == domain.CardAuthorizationStatusApproved {
+		captured, err := s.authorizer.Capture(authorization.GeneratedID)
+		if err != nil {
+			// request is not approved, so hold of authorization should not be left on card
+			s.reverseUncaptured(authorization, authorizationRequest.NetworkReference)
+			return s.errorResponse(request, mti, err, ResponseCodeRecordNotFound)
+		}
+		authorization = captured
+	}
+	return authorizationResponse(request, mti, authorization)
+}
+
+// reverseUncaptured releases hold of authorization of financial request which failed to capture
+func (s *Server) reverseUncaptured(authorization *domain.CardAuthorization, reference string) {
+	_, err := s.authorizer.ReverseByNetworkReference(reference)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf(
+			"unable to reverse authorization %s which failed to capture: %s",
+			authorization.GeneratedID,
+			err.Error(),
+		))
+	}
+}
+
+func (s *Server) reverse(request *Message) *Message {
+	reference, err := reversalReference(request)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("wrong %s request: %s", request.MTI, err.Error()))
+		return responseTo(request, MTIReversalResponse, ResponseCodeFormatError)
+	}
+	authorization, err := s.authorizer.ReverseByNetworkReference(reference)
+	if err != nil {
+		return s.errorResponse(request, MTIReversalResponse, err, ResponseCodeRecordNotFound)
+	}
+	return authorizationResponse(request, MTIReversalResponse, authorization)
+}
+
+func (s *Server) errorResponse(request *Message, mti string, err error, notFoundCode string) *Message {
+	code := errorResponseCode(err, notFoundCode)
+	if code == ResponseCodeSystemMalfunction {
+		s.logger.Error(fmt.Sprintf("error while process %s request: %s", request.MTI, err.Error()))
+	}
+	return responseTo(request, mti, code)
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		frame, err := readFrame(reader)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("unable to read message from %s: %s", conn.RemoteAddr(), err.Error()))
+			return
+		}
+		response := s.respond(frame, conn.RemoteAddr())
+		if response == nil {
+			continue
+		}
+		packed, err := s.codec.Pack(response)
+		if err != nil {
+			s.logger.Error(fmt.Sprintf("unable to pack %s response: %s", response.MTI, err.Error()))
+			continue
+		}
+		err = writeFrame(conn, packed)
+		if err != nil {
+			s.logger.Warn(fmt.Sprintf("unable to write message to %s: %s", conn.RemoteAddr(), err.Error()))
+			return
+		}
+	}
+}
+
+// respond handles request of frame, nil response means that request is dropped
+func (s *Server) respond(frame []byte, remoteAddr net.Addr) *Message {
+	request, err := s.codec.Unpack(frame)
+	if err != nil {
+		s.logger.Warn(fmt.Sprintf("unable to unpack message from %s: %s", remoteAddr, err.Error()))
+		return formatErrorResponse(s.codec.UnpackPartial(frame))
+	}
+	response := s.Handle(request)
+	if response == nil {
+		s.logger.Warn(fmt.Sprintf("message %s from %s is not supported", request.MTI, remoteAddr))
+	}
+	return response
+}
+
+// formatErrorResponse answers request which could not be unpacked with format error. Acquirer matches
+// response with request by STAN, so request is dropped when its MTI is unknown or STAN is not read.
+func formatErrorResponse(request *Message) *Message {
+	if request == nil || !request.Has(FieldSTAN) {
+		return nil
+	}
+	mti, ok := responseMTIs[request.MTI]
+	if !ok {
+		return nil
+	}
+	return responseTo(request, mti, ResponseCodeFormatError)
+}
+
+// readFrame reads message written by writeFrame
+func readFrame(reader io.Reader) ([]byte, error) {
+	header := make([]byte, frameHeaderLength)
+	_, err := io.ReadFull(reader, header)
+	if err != nil {
+		return nil, err
+	}
+	frame := make([]byte, binary.BigEndian.Uint16(header))
+	_, err = io.ReadFull(reader, frame)
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return frame, err
+}
+
+// writeFrame writes message with its length before it
+func writeFrame(writer io.Writer, frame []byte) error {
+	if len(frame) > 0xffff {
+		return fmt.Errorf("message of %d bytes is too long", len(frame))
+	}
+	packed := make([]byte, frameHeaderLength, frameHeaderLength+len(frame))
+	binary.BigEndian.PutUint16(packed, uint16(len(frame)))
+	_, err := writer.Write(append(packed, frame...))
+	return err
+}
@@ -0,0 +1,186 @@
+package iso8583
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"go.uber.org/zap"
+)
+
+type fakeAuthorizer struct {
+	authorization *domain.CardAuthorization
+	err           error
+	captureErr    error
+
+	requests           []*domain.CardAuthorizationRequest
+	captured           []string
+	reversedReferences []string
+}
+
+func (a *fakeAuthorizer) Authorize(request *domain.CardAuthorizationRequest) (*domain.CardAuthorization, error) {
+	a.requests = append(a.requests, request)
+	return a.authorization, a.err
+}
+
+func (a *fakeAuthorizer) Capture(authorizationID string) (*domain.CardAuthorization, error) {
+	a.captured = append(a.captured, authorizationID)
+	if a.captureErr != nil {
+		return nil, a.captureErr
+	}
+	return a.authorization, a.err
+}
+
+func (a *fakeAuthorizer) ReverseByNetworkReference(reference string) (*domain.CardAuthorization, error) {
+	a.reversedReferences = append(a.reversedReferences, reference)
+	return a.authorization, a.err
+}
+
+// exchange writes request fixture to server connection and returns response bytes
+func exchange(t *testing.T, authorizer Authorizer, fixture string) []byte {
+	return exchangeFrame(t, authorizer, readFixture(t, fixture))
+}
+
+// exchangeFrame writes request bytes to server connection and returns response bytes
+func exchangeFrame(t *testing.T, authorizer Authorizer, request []byte) []byte {
+	server := NewServer(zap.NewNop(), newTestCodec(t), authorizer)
+	client, conn := net.Pipe()
+	defer client.Close()
+	go server.serveConn(conn)
+
+	err := writeFrame(client, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := readFrame(bufio.NewReader(client))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response
+}
+
+func TestServer_Authorization(t *testing.T) {
+	t.Parallel()
+
+	authorizer := &fakeAuthorizer{authorization: &domain.CardAuthorization{
+		GeneratedID: "a1b2c3d4e5f6",
+		Status:      domain.CardAuthorizationStatusApproved,
+	}}
+
+	response := exchange(t, authorizer, "testdata/0100_authorization_request.hex")
+
+	assert.Equal(t, readFixture(t, "testdata/0110_authorization_response.hex"), response)
+	assert.Equal(t, []*domain.CardAuthorizationRequest{{
+		PAN:              "2200120000001230",
+		ExpiryMonth:      8,
+		ExpiryYear:       2024,
+		Amount:           15000,
+		Currency:         "RUB",
+		MCC:              "5411",
+		MerchantName:     "GROCERY STORE",
+		NetworkReference: "00000012345:000123:0818120000",
+	}}, authorizer.requests)
+	assert.Empty(t, authorizer.captured)
+}
+
+func TestServer_FinancialDeclined(t *testing.T) {
+	t.Parallel()
+
+	authorizer := &fakeAuthorizer{authorization: &domain.CardAuthorization{
+		GeneratedID:   "a1b2c3d4e5f6",
+		Status:        domain.CardAuthorizationStatusDeclined,
+		DeclineReason: domain.CardDeclineReasonInsufficientFunds,
+	}}
+
+	response := exchange(t, authorizer, "testdata/0200_financial_request.hex")
+
+	assert.Equal(t, readFixture(t, "testdata/0210_financial_response.hex"), response)
+	assert.Len(t, authorizer.requests, 1)
+	assert.Equal(t, int64(9900), authorizer.requests[0].Amount)
+	assert.Empty(t, authorizer.captured)
+}
+
+func TestServer_Reversal(t *testing.T) {
+	t.Parallel()
+
+	authorizer := &fakeAuthorizer{authorization: &domain.CardAuthorization{
+		GeneratedID: "a1b2c3d4e5f6",
+		Status:      domain.CardAuthorizationStatusReversed,
+	}}
+
+	response := exchange(t, authorizer, "testdata/0400_reversal_request.hex")
+
+	assert.Equal(t, readFixture(t, "testdata/0410_reversal_response.hex"), response)
+	assert.Equal(t, []string{"00000012345:000123:0818120000"}, authorizer.reversedReferences)
+}
+
+func TestServer_ReversalNotFound(t *testing.T) {
+	t.Parallel()
+
+	authorizer := &fakeAuthorizer{err: domain.NewNotFoundError("authorization not found")}
+	codec := newTestCodec(t)
+	request, err := codec.Unpack(readFixture(t, "testdata/0400_reversal_request.hex"))
+	assert.NoError(t, err)
+
+	response := NewServer(zap.NewNop(), codec, authorizer).Handle(request)
+
+	assert.Equal(t, MTIReversalResponse, response.MTI)
+	assert.Equal(t, ResponseCodeRecordNotFound, response.Get(FieldResponseCode))
+	assert.False(t, response.Has(FieldAuthorizationID))
+}
+
+func TestServer_FinancialCaptureFailed(t *testing.T) {
+	t.Parallel()
+
+	authorizer := &fakeAuthorizer{
+		authorization: &domain.CardAuthorization{
+			GeneratedID: "a1b2c3d4e5f6",
+			Status:      domain.CardAuthorizationStatusApproved,
+		},
+		captureErr: errors.New("connection reset"),
+	}
+	codec := newTestCodec(t)
+	request, err := codec.Unpack(readFixture(t, "testdata/0200_financial_request.hex"))
+	assert.NoError(t, err)
+
+	response := NewServer(zap.NewNop(), codec, authorizer).Handle(request)
+
+	assert.Equal(t, MTIFinancialResponse, response.MTI)
+	assert.Equal(t, ResponseCodeSystemMalfunction, response.Get(FieldResponseCode))
+	assert.False(t, response.Has(FieldAuthorizationID))
+	assert.Equal(t, []string{"a1b2c3d4e5f6"}, authorizer.captured)
+	if assert.Len(t, authorizer.requests, 1) {
+		assert.Equal(t, []string{authorizer.requests[0].NetworkReference}, authorizer.reversedReferences)
+	}
+}
+
+func TestServer_FormatError(t *testing.T) {
+	t.Parallel()
+
+	authorizer := &fakeAuthorizer{}
+	request := readFixture(t, "testdata/0100_authorization_request.hex")
+	// the last field is cut off, STAN is read before it
+	truncated := request[:len(request)-3]
+
+	packed := exchangeFrame(t, authorizer, truncated)
+
+	response, err := newTestCodec(t).Unpack(packed)
+	assert.NoError(t, err)
+	assert.Equal(t, MTIAuthorizationResponse, response.MTI)
+	assert.Equal(t, ResponseCodeFormatError, response.Get(FieldResponseCode))
+	assert.Equal(t, "000123", response.Get(FieldSTAN))
+	assert.Empty(t, authorizer.requests)
+}
+
+func TestServer_FormatErrorWithoutSTAN(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(zap.NewNop(), newTestCodec(t), &fakeAuthorizer{})
+	request := readFixture(t, "testdata/0100_authorization_request.hex")
+
+	// bitmap is cut off, so request could not be matched with response
+	assert.Nil(t, server.respond(request[:6], &net.TCPAddr{}))
+}
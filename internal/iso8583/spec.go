@@ -0,0 +1,127 @@
+package iso8583
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Encoding of field value and its length prefix
+type Encoding string
+
+const (
+	// EncodingASCII keeps every character in a byte
+	EncodingASCII Encoding = "ascii"
+	// EncodingBCD packs two digits in a byte, odd number of digits is padded with leading zero
+	EncodingBCD Encoding = "bcd"
+)
+
+// Prefix tells whether field is fixed length or how many digits variable length is written with
+type Prefix string
+
+const (
+	PrefixFixed  Prefix = "fixed"
+	PrefixLLVAR  Prefix = "llvar"
+	PrefixLLLVAR Prefix = "lllvar"
+)
+
+// maxFieldNumber is the last field of secondary bitmap, field 1 is secondary bitmap itself
+const maxFieldNumber = 128
+
+// FieldSpec describes field of message. Length is exact length of fixed field and maximum length
+// of variable one, in digits or characters.
+type FieldSpec struct {
+	Name     string   `yaml:"name"`
+	Length   int      `yaml:"length"`
+	Prefix   Prefix   `yaml:"prefix"`
+	Encoding Encoding `yaml:"encoding"`
+}
+
+// Spec is a dialect of ISO 8583 spoken with processor. Fields which are not in spec could not be sent or received.
+type Spec struct {
+	MTIEncoding Encoding          `yaml:"mti_encoding"`
+	Fields      map[int]FieldSpec `yaml:"fields"`
+}
+
+// LoadSpec reads spec from YAML file
+func LoadSpec(path string) (*Spec, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSpec(content)
+}
+
+func ParseSpec(content []byte) (*Spec, error) {
+	spec := &Spec{}
+	err := yaml.Unmarshal(content, spec)
+	if err != nil {
+		return nil, err
+	}
+	err = spec.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func (s *Spec) Validate() error {
+	err := validateEncoding(s.MTIEncoding)
+	if err != nil {
+		return fmt.Errorf("mti: %s", err.Error())
+	}
+	for number, field := range s.Fields {
+		if number < 2 || number > maxFieldNumber {
+			return fmt.Errorf("field %d: number should be between 2 and %d", number, maxFieldNumber)
+		}
+		err = field.validate()
+		if err != nil {
+			return fmt.Errorf("field %d: %s", number, err.Error())
+		}
+	}
+	return nil
+}
+
+func (f FieldSpec) validate() error {
+	err := validateEncoding(f.Encoding)
+	if err != nil {
+		return err
+	}
+	maxLength := 0
+	switch f.Prefix {
+	case PrefixFixed:
+	case PrefixLLVAR:
+		maxLength = 99
+	case PrefixLLLVAR:
+		maxLength = 999
+	default:
+		return fmt.Errorf("prefix should be one of fixed, llvar, lllvar")
+	}
+	if f.Length <= 0 {
+		return fmt.Errorf("length should be positive")
+	}
+	if maxLength > 0 && f.Length > maxLength {
+		return fmt.Errorf("length of %s field should not exceed %d", f.Prefix, maxLength)
+	}
+	return nil
+}
+
+// prefixDigits is a number of digits length of variable field is written with
+func (f FieldSpec) prefixDigits() int {
+	switch f.Prefix {
+	case PrefixLLVAR:
+		return 2
+	case PrefixLLLVAR:
+		return 3
+	}
+	return 0
+}
+
+func validateEncoding(encoding Encoding) error {
+	switch encoding {
+	case EncodingASCII, EncodingBCD:
+		return nil
+	}
+	return fmt.Errorf("encoding should be one of ascii, bcd")
+}
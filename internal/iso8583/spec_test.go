@@ -0,0 +1,40 @@
+package iso8583
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSpec(t *testing.T) {
+	t.Parallel()
+
+	spec, err := LoadSpec("../../configs/iso8583_1987.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, EncodingBCD, spec.MTIEncoding)
+	assert.Equal(t, FieldSpec{
+		Name:     "primary account number",
+		Length:   19,
+		Prefix:   PrefixLLVAR,
+		Encoding: EncodingBCD,
+	}, spec.Fields[FieldPAN])
+}
+
+func TestParseSpec_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for content, expected := range map[string]string{
+		"mti_encoding: ebcdic":                                          "mti: encoding should be one of ascii, bcd",
+		"mti_encoding: ascii\nfields: {1: {}}":                          "field 1: number should be between 2 and 128",
+		"mti_encoding: ascii\nfields: {129: {}}":                        "field 129: number should be between 2 and 128",
+		"mti_encoding: ascii\nfields: {2: {length: 19, prefix: llvar}}": "field 2: encoding should be one of ascii, bcd",
+		"mti_encoding: ascii\nfields: {2: {length: 19, prefix: var, encoding: bcd}}": "field 2: " +
+			"prefix should be one of fixed, llvar, lllvar",
+		"mti_encoding: ascii\nfields: {2: {prefix: fixed, encoding: bcd}}": "field 2: length should be positive",
+		"mti_encoding: ascii\nfields: {2: {length: 100, prefix: llvar, encoding: bcd}}": "field 2: " +
+			"length of llvar field should not exceed 99",
+	} {
+		_, err := ParseSpec([]byte(content))
+		assert.EqualError(t, err, expected, content)
+	}
+}
@@ -0,0 +1,107 @@
+package issuing
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// ResponseCodeApproved is ISO 8583 response code of approved authorization
+const ResponseCodeApproved = "00"
+
+// responseCodes are ISO 8583 response codes card network is answered with on decline
+var responseCodes = map[domain.CardDeclineReason]string{
+	domain.CardDeclineReasonCardFrozen:          "62",
+	domain.CardDeclineReasonCardTerminated:      "46",
+	domain.CardDeclineReasonCardExpired:         "54",
+	domain.CardDeclineReasonInvalidExpiry:       "54",
+	domain.CardDeclineReasonInvalidCVV:          "82",
+	domain.CardDeclineReasonCurrencyMismatch:    "57",
+	domain.CardDeclineReasonMCCBlocked:          "57",
+	domain.CardDeclineReasonPerTransactionLimit: "61",
+	domain.CardDeclineReasonDailyLimit:          "61",
+	domain.CardDeclineReasonCustomerLimit:       "61",
+	domain.CardDeclineReasonCustomerRestricted:  "57",
+	domain.CardDeclineReasonRisk:                "59",
+	domain.CardDeclineReasonInsufficientFunds:   "51",
+}
+
+// mccRegexp is a merchant category code of ISO 18245
+var mccRegexp = regexp.MustCompile(`^\d{4}$`)
+
+// ValidateControls checks that blocked MCCs are merchant category codes and limits are not negative
+func ValidateControls(controls domain.CardControls) error {
+	for _, mcc := range controls.BlockedMCCs {
+		err := ValidateMCC(mcc)
+		if err != nil {
+			return err
+		}
+	}
+	if controls.PerTransaction < 0 || controls.Daily < 0 {
+		return fmt.Errorf("limits should not be negative")
+	}
+	return nil
+}
+
+func ValidateMCC(mcc string) error {
+	if !mccRegexp.MatchString(mcc) {
+		return fmt.Errorf("MCC %q should be 4 digits", mcc)
+	}
+	return nil
+}
+
+// Decide checks authorization request against card, its controls and available balance of customer and returns
+// reason to decline it or empty reason to approve it. Spent is amount of authorizations of card approved today,
+// secrets are checked when request has CVV.
+func Decide(
+	card *domain.Card,
+	secrets *domain.CardSecrets,
+	request *domain.CardAuthorizationRequest,
+	balance *domain.AvailableBalance,
+	spent int64,
+	now time.Time,
+) domain.CardDeclineReason {
+	controls := card.Controls
+	switch {
+	case card.Status == domain.CardStatusTerminated:
+		return domain.CardDeclineReasonCardTerminated
+	case card.Status == domain.CardStatusFrozen:
+		return domain.CardDeclineReasonCardFrozen
+	case Expired(card, now):
+		return domain.CardDeclineReasonCardExpired
+	case request.ExpiryMonth != card.ExpiryMonth || request.ExpiryYear != card.ExpiryYear:
+		return domain.CardDeclineReasonInvalidExpiry
+	case request.CVV != "" && (secrets == nil || request.CVV != secrets.CVV):
+		return domain.CardDeclineReasonInvalidCVV
+	case request.Currency != card.Currency:
+		return domain.CardDeclineReasonCurrencyMismatch
+	case mccBlocked(controls.BlockedMCCs, request.MCC):
+		return domain.CardDeclineReasonMCCBlocked
+	case controls.PerTransaction > 0 && request.Amount > controls.PerTransaction:
+		return domain.CardDeclineReasonPerTransactionLimit
+	case controls.Daily > 0 && spent+request.Amount > controls.Daily:
+		return domain.CardDeclineReasonDailyLimit
+	case request.Amount > balance.Available():
+		return domain.CardDeclineReasonInsufficientFunds
+	}
+	return ""
+}
+
+// ResponseCode is ISO 8583 response code of authorization decision
+func ResponseCode(authorization *domain.CardAuthorization) string {
+	if authorization.Status == domain.CardAuthorizationStatusDeclined {
+		return responseCodes[authorization.DeclineReason]
+	}
+	return ResponseCodeApproved
+}
+
+func mccBlocked(blocked []string, mcc string) bool {
+	for _, blockedMCC := range blocked {
+		if blockedMCC == mcc {
+			return true
+		}
+	}
+	return false
+}
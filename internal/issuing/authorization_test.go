@@ -0,0 +1,122 @@
+package issuing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestDecide(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2021, time.March, 10, 12, 0, 0, 0, businessday.Location)
+	secrets := &domain.CardSecrets{PAN: "2200120000001234", CVV: "123"}
+	newCard := func() *domain.Card {
+		return &domain.Card{
+			Currency:    "RUB",
+			ExpiryMonth: 3,
+			ExpiryYear:  2024,
+			Status:      domain.CardStatusActive,
+			Controls: domain.CardControls{
+				BlockedMCCs:    []string{"7995"},
+				PerTransaction: 50000,
+				Daily:          80000,
+			},
+		}
+	}
+	newRequest := func() *domain.CardAuthorizationRequest {
+		return &domain.CardAuthorizationRequest{
+			PAN:         "2200120000001234",
+			ExpiryMonth: 3,
+			ExpiryYear:  2024,
+			CVV:         "123",
+			Amount:      30000,
+			Currency:    "RUB",
+			MCC:         "5411",
+		}
+	}
+	balance := &domain.AvailableBalance{Balance: 10000, Limit: 30000, Holds: 5000}
+
+	for name, tc := range map[string]struct {
+		change func(card *domain.Card, request *domain.CardAuthorizationRequest)
+		spent  int64
+		reason domain.CardDeclineReason
+	}{
+		"approved within limit of credit line": {
+			change: func(*domain.Card, *domain.CardAuthorizationRequest) {},
+		},
+		"approved without CVV": {
+			change: func(_ *domain.Card, request *domain.CardAuthorizationRequest) { request.CVV = "" },
+		},
+		"frozen": {
+			change: func(card *domain.Card, _ *domain.CardAuthorizationRequest) { card.Status = domain.CardStatusFrozen },
+			reason: domain.CardDeclineReasonCardFrozen,
+		},
+		"expired": {
+			change: func(card *domain.Card, request *domain.CardAuthorizationRequest) {
+				card.ExpiryYear, request.ExpiryYear = 2021, 2021
+				card.ExpiryMonth, request.ExpiryMonth = 2, 2
+			},
+			reason: domain.CardDeclineReasonCardExpired,
+		},
+		"wrong expiry": {
+			change: func(_ *domain.Card, request *domain.CardAuthorizationRequest) { request.ExpiryYear = 2025 },
+			reason: domain.CardDeclineReasonInvalidExpiry,
+		},
+		"wrong CVV": {
+			change: func(_ *domain.Card, request *domain.CardAuthorizationRequest) { request.CVV = "321" },
+			reason: domain.CardDeclineReasonInvalidCVV,
+		},
+		"other currency": {
+			change: func(_ *domain.Card, request *domain.CardAuthorizationRequest) { request.Currency = "USD" },
+			reason: domain.CardDeclineReasonCurrencyMismatch,
+		},
+		"blocked MCC": {
+			change: func(_ *domain.Card, request *domain.CardAuthorizationRequest) { request.MCC = "7995" },
+			reason: domain.CardDeclineReasonMCCBlocked,
+		},
+		"over per transaction limit": {
+			change: func(_ *domain.Card, request *domain.CardAuthorizationRequest) { request.Amount = 50001 },
+			reason: domain.CardDeclineReasonPerTransactionLimit,
+		},
+		"over daily limit": {
+			change: func(*domain.Card, *domain.CardAuthorizationRequest) {},
+			spent:  50001,
+			reason: domain.CardDeclineReasonDailyLimit,
+		},
+		"over available balance": {
+			change: func(_ *domain.Card, request *domain.CardAuthorizationRequest) { request.Amount = 35001 },
+			reason: domain.CardDeclineReasonInsufficientFunds,
+		},
+	} {
+		card, request := newCard(), newRequest()
+		tc.change(card, request)
+		assert.Equal(t, tc.reason, Decide(card, secrets, request, balance, tc.spent, now), name)
+	}
+}
+
+func TestValidateControls(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateControls(domain.CardControls{}))
+	assert.NoError(t, ValidateControls(domain.CardControls{BlockedMCCs: []string{"7995"}, Daily: 100}))
+	assert.EqualError(
+		t,
+		ValidateControls(domain.CardControls{BlockedMCCs: []string{"799"}}),
+		`MCC "799" should be 4 digits`,
+	)
+	assert.EqualError(t, ValidateControls(domain.CardControls{PerTransaction: -1}), "limits should not be negative")
+}
+
+func TestResponseCode(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "00", ResponseCode(&domain.CardAuthorization{Status: domain.CardAuthorizationStatusApproved}))
+	assert.Equal(t, "51", ResponseCode(&domain.CardAuthorization{
+		Status:        domain.CardAuthorizationStatusDeclined,
+		DeclineReason: domain.CardDeclineReasonInsufficientFunds,
+	}))
+}
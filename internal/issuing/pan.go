@@ -0,0 +1,111 @@
+package issuing
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	// PANLength of issued cards
+	PANLength = 16
+	// CVVLength of issued cards
+	CVVLength = 3
+	// ValidityYears of issued cards, card is valid till the end of expiry month
+	ValidityYears = 3
+)
+
+// binRegexp allows 6 and 8 digit bank identification numbers
+var binRegexp = regexp.MustCompile(`^\d{6}(\d{2})?$`)
+
+var ten = big.NewInt(10)
+
+// ValidateBIN checks that PANs could be issued from bin
+func ValidateBIN(bin string) error {
+	if !binRegexp.MatchString(bin) {
+		return fmt.Errorf("BIN should be 6 or 8 digits")
+	}
+	return nil
+}
+
+// GeneratePAN makes PAN of bin with random account number and Luhn check digit
+func GeneratePAN(bin string) (string, error) {
+	err := ValidateBIN(bin)
+	if err != nil {
+		return "", err
+	}
+	account, err := randomDigits(rand.Reader, PANLength-len(bin)-1)
+	if err != nil {
+		return "", err
+	}
+	payload := bin + account
+	return payload + string(luhnCheckDigit(payload)), nil
+}
+
+// GenerateCVV makes random CVV
+func GenerateCVV() (string, error) {
+	return randomDigits(rand.Reader, CVVLength)
+}
+
+// Expiry is a month and a year which card issued at now expires at the end of
+func Expiry(now time.Time) (int, int) {
+	expiresAt := now.In(businessday.Location).AddDate(ValidityYears, 0, 0)
+	return int(expiresAt.Month()), expiresAt.Year()
+}
+
+// Expired tells whether card is used after the end of its expiry month
+func Expired(card *domain.Card, now time.Time) bool {
+	validTill := time.Date(card.ExpiryYear, time.Month(card.ExpiryMonth)+1, 1, 0, 0, 0, 0, businessday.Location)
+	return !now.Before(validTill)
+}
+
+// LuhnValid checks digits and check digit of pan
+func LuhnValid(pan string) bool {
+	if len(pan) < 2 || strings.Trim(pan, "0123456789") != "" {
+		return false
+	}
+	return luhnCheckDigit(pan[:len(pan)-1]) == pan[len(pan)-1]
+}
+
+// Mask keeps the first 6 and the last 4 digits of pan
+func Mask(pan string) string {
+	if len(pan) <= 10 {
+		return strings.Repeat("*", len(pan))
+	}
+	return pan[:6] + strings.Repeat("*", len(pan)-10) + pan[len(pan)-4:]
+}
+
+// luhnCheckDigit doubles every second digit of payload starting from the rightmost one
+func luhnCheckDigit(payload string) byte {
+	sum := 0
+	for i := 0; i < len(payload); i++ {
+		digit := int(payload[len(payload)-1-i] - '0')
+		if i%2 == 0 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+func randomDigits(random io.Reader, count int) (string, error) {
+	digits := make([]byte, count)
+	for i := range digits {
+		digit, err := rand.Int(random, ten)
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + digit.Int64())
+	}
+	return string(digits), nil
+}
@@ -0,0 +1,65 @@
+package issuing
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestGeneratePAN(t *testing.T) {
+	t.Parallel()
+
+	for _, bin := range []string{"220012", "22001234"} {
+		pan, err := GeneratePAN(bin)
+		assert.NoError(t, err)
+		assert.Len(t, pan, PANLength)
+		assert.True(t, strings.HasPrefix(pan, bin))
+		assert.True(t, LuhnValid(pan), pan)
+	}
+
+	_, err := GeneratePAN("2200")
+	assert.EqualError(t, err, "BIN should be 6 or 8 digits")
+}
+
+func TestLuhnValid(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, LuhnValid("4111111111111111"))
+	assert.True(t, LuhnValid("2200000000000004"))
+	assert.True(t, LuhnValid("79927398713"))
+	assert.False(t, LuhnValid("4111111111111112"))
+	assert.False(t, LuhnValid("41111111a1111111"))
+	assert.False(t, LuhnValid(""))
+}
+
+func TestGenerateCVV(t *testing.T) {
+	t.Parallel()
+
+	cvv, err := GenerateCVV()
+	assert.NoError(t, err)
+	assert.Len(t, cvv, CVVLength)
+	assert.Empty(t, strings.Trim(cvv, "0123456789"))
+}
+
+func TestExpiry(t *testing.T) {
+	t.Parallel()
+
+	// card issued at night of the last day of month in Moscow expires in the same month
+	month, year := Expiry(time.Date(2021, time.January, 31, 22, 0, 0, 0, time.UTC))
+	assert.Equal(t, 2, month)
+	assert.Equal(t, 2024, year)
+
+	card := &domain.Card{ExpiryMonth: 2, ExpiryYear: 2024}
+	assert.False(t, Expired(card, time.Date(2024, time.February, 29, 23, 59, 0, 0, businessday.Location)))
+	assert.True(t, Expired(card, time.Date(2024, time.March, 1, 0, 0, 0, 0, businessday.Location)))
+}
+
+func TestMask(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "220012******1234", Mask("2200120000001234"))
+}
@@ -0,0 +1,96 @@
+package kyc
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	passportNumberCheckName    = "passport_number"
+	customerAgeCheckName       = "customer_age"
+	passportIssueDateCheckName = "passport_issue_date"
+
+	// russian passport is issued at the age of 14
+	passportMinIssueAge = 14
+)
+
+func DefaultChecks() []domain.VerificationCheck {
+	return []domain.VerificationCheck{
+		PassportNumberCheck{},
+		CustomerAgeCheck{MinAge: 18},
+		PassportIssueDateCheck{},
+	}
+}
+
+// PassportNumberCheck checks that passport number consists of 4 digits of series and 6 digits of number
+type PassportNumberCheck struct{}
+
+func (c PassportNumberCheck) Check(customer *domain.Customer) domain.VerificationCheckResult {
+	result := domain.VerificationCheckResult{Name: passportNumberCheckName}
+
+	digits := 0
+	for _, r := range customer.Passport.Number {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		if !unicode.IsDigit(r) {
+			result.Reason = "passport number should contain only digits"
+			return result
+		}
+		digits++
+	}
+	if digits != 10 {
+		result.Reason = "passport number should contain 10 digits"
+		return result
+	}
+
+	result.Passed = true
+	return result
+}
+
+type CustomerAgeCheck struct {
+	MinAge int
+}
+
+func (c CustomerAgeCheck) Check(customer *domain.Customer) domain.VerificationCheckResult {
+	result := domain.VerificationCheckResult{Name: customerAgeCheckName}
+
+	if AgeAt(customer.Passport.BirthDate, time.Now()) < c.MinAge {
+		result.Reason = fmt.Sprintf("customer should be at least %d years old", c.MinAge)
+		return result
+	}
+
+	result.Passed = true
+	return result
+}
+
+// PassportIssueDateCheck checks that passport was issued after customer reached the passport age and not in future
+type PassportIssueDateCheck struct{}
+
+func (c PassportIssueDateCheck) Check(customer *domain.Customer) domain.VerificationCheckResult {
+	result := domain.VerificationCheckResult{Name: passportIssueDateCheckName}
+
+	if customer.Passport.IssueDate.After(time.Now()) {
+		result.Reason = "passport issue date is in future"
+		return result
+	}
+	if AgeAt(customer.Passport.BirthDate, customer.Passport.IssueDate) < passportMinIssueAge {
+		result.Reason = fmt.Sprintf("passport could not be issued before the age of %d", passportMinIssueAge)
+		return result
+	}
+
+	result.Passed = true
+	return result
+}
+
+// AgeAt returns number of full years passed from birthDate till date
+func AgeAt(birthDate time.Time, date time.Time) int {
+	age := date.Year() - birthDate.Year()
+	if date.Month() < birthDate.Month() || (date.Month() == birthDate.Month() && date.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,82 @@
+package kyc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestPassportNumberCheck(t *testing.T) {
+	testCases := []struct {
+		name   string
+		number string
+		passed bool
+		reason string
+	}{
+		{"Valid", "1234567890", true, ""},
+		{"ValidWithSpaces", "1234 567890", true, ""},
+		{"TooShort", "123456789", false, "passport number should contain 10 digits"},
+		{"NotDigits", "12345678AB", false, "passport number should contain only digits"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			customer := &domain.Customer{Passport: domain.Passport{Number: test.number}}
+
+			result := PassportNumberCheck{}.Check(customer)
+
+			assert.Equal(t, test.passed, result.Passed)
+			assert.Equal(t, test.reason, result.Reason)
+		})
+	}
+}
+
+func TestCustomerAgeCheck(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name      string
+		birthDate time.Time
+		passed    bool
+	}{
+		{"Adult", now.AddDate(-30, 0, 0), true},
+		{"ExactlyEighteen", now.AddDate(-18, 0, 0), true},
+		{"OneDayBeforeEighteen", now.AddDate(-18, 0, 1), false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			customer := &domain.Customer{Passport: domain.Passport{BirthDate: test.birthDate}}
+
+			result := CustomerAgeCheck{MinAge: 18}.Check(customer)
+
+			assert.Equal(t, test.passed, result.Passed)
+		})
+	}
+}
+
+func TestPassportIssueDateCheck(t *testing.T) {
+	birthDate, _ := time.Parse(domain.DateFormat, "10-05-1990")
+	testCases := []struct {
+		name      string
+		issueDate time.Time
+		passed    bool
+		reason    string
+	}{
+		{"IssuedAtFourteen", birthDate.AddDate(14, 0, 0), true, ""},
+		{"IssuedBeforeFourteen", birthDate.AddDate(14, 0, -1), false, "passport could not be issued before the age of 14"},
+		{"IssuedInFuture", time.Now().AddDate(0, 0, 1), false, "passport issue date is in future"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			customer := &domain.Customer{Passport: domain.Passport{BirthDate: birthDate, IssueDate: test.issueDate}}
+
+			result := PassportIssueDateCheck{}.Check(customer)
+
+			assert.Equal(t, test.passed, result.Passed)
+			assert.Equal(t, test.reason, result.Reason)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package legalentity
+
+import (
+	"fmt"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// ValidateCompany checks requisites of company and its officers. Company should have a representative,
+// the same customer could not be listed twice in the same role and shares of owners could not exceed 100%.
+func ValidateCompany(company domain.Company) error {
+	if company.Name == "" {
+		return fmt.Errorf("company name is mandatory")
+	}
+	if !ValidINN(company.INN) {
+		return fmt.Errorf("inn should be 10 digits with valid check digit")
+	}
+	if !ValidKPP(company.KPP) {
+		return fmt.Errorf("kpp should be 9 characters of tax office, reason and number")
+	}
+	if !ValidOGRN(company.OGRN) {
+		return fmt.Errorf("ogrn should be 13 digits with valid check digit")
+	}
+
+	owners := map[string]bool{}
+	total := 0
+	for _, owner := range company.BeneficialOwners {
+		if owner.CustomerID == "" {
+			return fmt.Errorf("customer of beneficial owner is mandatory")
+		}
+		if owners[owner.CustomerID] {
+			return fmt.Errorf("beneficial owner %s is listed twice", owner.CustomerID)
+		}
+		owners[owner.CustomerID] = true
+		if owner.Share <= 0 || owner.Share > FullShare {
+			return fmt.Errorf("share of beneficial owner %s should be above 0 and up to 100 percent", owner.CustomerID)
+		}
+		total += owner.Share
+	}
+	if total > FullShare {
+		return fmt.Errorf("shares of beneficial owners exceed 100 percent")
+	}
+
+	if len(company.Representatives) == 0 {
+		return fmt.Errorf("company should have a representative")
+	}
+	representatives := map[string]bool{}
+	for _, representative := range company.Representatives {
+		if representative.CustomerID == "" {
+			return fmt.Errorf("customer of representative is mandatory")
+		}
+		if representatives[representative.CustomerID] {
+			return fmt.Errorf("representative %s is listed twice", representative.CustomerID)
+		}
+		representatives[representative.CustomerID] = true
+		if representative.Position == "" {
+			return fmt.Errorf("position of representative %s is mandatory", representative.CustomerID)
+		}
+	}
+	return nil
+}
+
+// OfficerIDs are ids of beneficial owners and representatives, customer of both roles is listed once
+func OfficerIDs(company domain.Company) []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, owner := range company.BeneficialOwners {
+		if !seen[owner.CustomerID] {
+			seen[owner.CustomerID] = true
+			ids = append(ids, owner.CustomerID)
+		}
+	}
+	for _, representative := range company.Representatives {
+		if !seen[representative.CustomerID] {
+			seen[representative.CustomerID] = true
+			ids = append(ids, representative.CustomerID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,60 @@
+package legalentity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func testCompany() domain.Company {
+	return domain.Company{
+		Name: "Wayne Enterprises LLC",
+		INN:  "7707083893",
+		KPP:  "773601001",
+		OGRN: "1027700132195",
+		BeneficialOwners: []domain.BeneficialOwner{
+			{CustomerID: "bruce", Share: 7500},
+			{CustomerID: "alfred", Share: 2500},
+		},
+		Representatives: []domain.Representative{{CustomerID: "bruce", Position: "CEO"}},
+	}
+}
+
+func TestValidateCompany(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateCompany(testCompany()))
+
+	for expected, change := range map[string]func(company *domain.Company){
+		"company name is mandatory": func(company *domain.Company) { company.Name = "" },
+		"inn should be 10 digits with valid check digit": func(company *domain.Company) {
+			company.INN = "7707083894"
+		},
+		"ogrn should be 13 digits with valid check digit": func(company *domain.Company) {
+			company.OGRN = "1027700132196"
+		},
+		"beneficial owner bruce is listed twice": func(company *domain.Company) {
+			company.BeneficialOwners[1].CustomerID = "bruce"
+		},
+		"shares of beneficial owners exceed 100 percent": func(company *domain.Company) {
+			company.BeneficialOwners[1].Share = 2501
+		},
+		"company should have a representative": func(company *domain.Company) {
+			company.Representatives = nil
+		},
+		"position of representative bruce is mandatory": func(company *domain.Company) {
+			company.Representatives[0].Position = ""
+		},
+	} {
+		company := testCompany()
+		change(&company)
+		assert.EqualError(t, ValidateCompany(company), expected)
+	}
+}
+
+func TestOfficerIDs(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"bruce", "alfred"}, OfficerIDs(testCompany()))
+}
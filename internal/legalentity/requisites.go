@@ -0,0 +1,58 @@
+package legalentity
+
+import "strings"
+
+const (
+	innLength  = 10
+	kppLength  = 9
+	ogrnLength = 13
+)
+
+// innWeights are weights of the first 9 digits of legal entity INN which check digit is calculated with
+var innWeights = []int{2, 4, 10, 3, 5, 9, 4, 6, 8}
+
+// ValidINN checks 10 digits taxpayer number of legal entity: the last digit is a weighted sum of the others
+// modulo 11 modulo 10
+func ValidINN(inn string) bool {
+	if len(inn) != innLength || !digits(inn) {
+		return false
+	}
+	sum := 0
+	for i, weight := range innWeights {
+		sum += int(inn[i]-'0') * weight
+	}
+	return sum%11%10 == int(inn[innLength-1]-'0')
+}
+
+// ValidKPP checks 9 characters tax registration reason code: 4 digits of tax office, 2 digits or capital
+// latin letters of reason and 3 digits of registration number. KPP has no check digit.
+func ValidKPP(kpp string) bool {
+	if len(kpp) != kppLength {
+		return false
+	}
+	if !digits(kpp[:4]) || !digits(kpp[6:]) {
+		return false
+	}
+	return strings.Trim(kpp[4:6], "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ") == ""
+}
+
+// ValidOGRN checks 13 digits state registration number of legal entity: the last digit is the number
+// of the first 12 digits modulo 11 modulo 10. The first digit is 1 or 5 for legal entities.
+func ValidOGRN(ogrn string) bool {
+	if len(ogrn) != ogrnLength || !digits(ogrn) {
+		return false
+	}
+	if ogrn[0] != '1' && ogrn[0] != '5' {
+		return false
+	}
+	// 12 digits do not fit into int of 32 bit platforms, so remainder is calculated digit by digit
+	remainder := 0
+	for _, digit := range ogrn[:ogrnLength-1] {
+		remainder = (remainder*10 + int(digit-'0')) % 11
+	}
+	return remainder%10 == int(ogrn[ogrnLength-1]-'0')
+}
+
+func digits(value string) bool {
+	return value != "" && strings.Trim(value, "0123456789") == ""
+}
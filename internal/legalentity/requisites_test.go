@@ -0,0 +1,55 @@
+package legalentity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidINN(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, ValidINN("7707083893"))
+	assert.True(t, ValidINN("7702070139"))
+	assert.False(t, ValidINN("7707083894"))
+	assert.False(t, ValidINN("770708389"))
+	assert.False(t, ValidINN("500100732259"))
+	assert.False(t, ValidINN("77070838a3"))
+}
+
+func TestValidKPP(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, ValidKPP("773601001"))
+	assert.True(t, ValidKPP("7736AB001"))
+	assert.False(t, ValidKPP("7736ab001"))
+	assert.False(t, ValidKPP("77360100"))
+	assert.False(t, ValidKPP("77A601001"))
+}
+
+func TestValidOGRN(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, ValidOGRN("1027700132195"))
+	assert.True(t, ValidOGRN("1027739609391"))
+	assert.False(t, ValidOGRN("1027700132196"))
+	assert.False(t, ValidOGRN("3027700132195"))
+	assert.False(t, ValidOGRN("304500116000157"))
+	assert.False(t, ValidOGRN("10277001321a5"))
+}
+
+func TestParseShare(t *testing.T) {
+	t.Parallel()
+
+	for value, expected := range map[string]int{"25": 2500, "25.5": 2550, "33.33": 3333, "100": 10000, "0.01": 1} {
+		share, err := ParseShare(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, share, value)
+	}
+	for _, value := range []string{"", "0", "100.01", "25.", "25.555", "-5", "+5", "2,5", "abc"} {
+		_, err := ParseShare(value)
+		assert.Error(t, err, value)
+	}
+	assert.Equal(t, "25.50", FormatShare(2550))
+	assert.Equal(t, "100.00", FormatShare(FullShare))
+}
@@ -0,0 +1,37 @@
+package legalentity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FullShare is 100% in hundredths of a percent shares are kept in
+const FullShare = 10000
+
+// ParseShare reads share of ownership written as percent with up to 2 decimals like 25.5
+// and returns it in hundredths of a percent
+func ParseShare(value string) (int, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), ".", 2)
+	whole, err := strconv.Atoi(parts[0])
+	if err != nil || whole < 0 || strings.HasPrefix(parts[0], "+") {
+		return 0, fmt.Errorf("share should be percent like 25.5")
+	}
+	fraction := 0
+	if len(parts) == 2 {
+		if len(parts[1]) == 0 || len(parts[1]) > 2 || !digits(parts[1]) {
+			return 0, fmt.Errorf("share should have 2 decimals at most")
+		}
+		fraction, _ = strconv.Atoi((parts[1] + "0")[:2])
+	}
+	share := whole*100 + fraction
+	if share <= 0 || share > FullShare {
+		return 0, fmt.Errorf("share should be above 0 and up to 100 percent")
+	}
+	return share, nil
+}
+
+// FormatShare writes share in hundredths of a percent as percent with 2 decimals
+func FormatShare(share int) string {
+	return fmt.Sprintf("%d.%02d", share/100, share%100)
+}
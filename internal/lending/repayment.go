@@ -0,0 +1,304 @@
+package lending
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/deposit"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// daysInYear of penalty interest
+const daysInYear = 365
+
+// Service accrues penalty on overdue installments, marks installments which are not repaid after due day
+// as overdue and collects penalty and due installments from balance of customer as far as balance allows.
+// Returns nil repayment when nothing is collected.
+func Service(loan *domain.Loan, now time.Time, balance int64) (*domain.LoanRepayment, error) {
+	if loan.Status == domain.LoanStatusRepaid {
+		return nil, nil
+	}
+	day := businessday.Day(now)
+	err := refresh(loan, day)
+	if err != nil {
+		return nil, err
+	}
+
+	due := loan.Penalty
+	for _, installment := range dueInstallments(loan, day) {
+		due += installment.Unpaid()
+	}
+	if balance < due {
+		due = balance
+	}
+	var repayment *domain.LoanRepayment
+	if due > 0 {
+		repayment = newRepayment(loan, domain.LoanRepaymentTypeScheduled, due, now)
+		repayDue(loan, repayment, now)
+	}
+	updateStatus(loan, day, now)
+	return repayment, nil
+}
+
+// Repay repays loan early by amount. Amount repays penalty, due installments and interest accrued for the current
+// month, the rest repays principal and the following installments are planned again for the same months.
+// Amount is cut to repay loan in full when it is more than loan costs today.
+func Repay(loan *domain.Loan, now time.Time, amount int64) (*domain.LoanRepayment, error) {
+	if loan.Status == domain.LoanStatusRepaid {
+		return nil, fmt.Errorf("loan is already repaid")
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("amount should be positive")
+	}
+	day := businessday.Day(now)
+	err := refresh(loan, day)
+	if err != nil {
+		return nil, err
+	}
+
+	current, accrued := currentInstallment(loan, day)
+	payoff := loan.Penalty + accrued
+	duePrincipal := int64(0)
+	for _, installment := range dueInstallments(loan, day) {
+		payoff += installment.Unpaid()
+		duePrincipal += installment.Principal - installment.PrincipalPaid
+	}
+	payoff += loan.Outstanding - duePrincipal
+	if amount > payoff {
+		amount = payoff
+	}
+
+	repayment := newRepayment(loan, domain.LoanRepaymentTypeEarly, amount, now)
+	left := repayDue(loan, repayment, now)
+	if current != nil {
+		interest := min(left, accrued)
+		current.InterestPaid += interest
+		repayment.Interest += interest
+		loan.InterestPaidUntil = day
+		left -= interest
+		if left > 0 {
+			repayment.Principal += left
+			loan.Outstanding -= left
+			err = replan(loan, current, left, day, now)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	updateStatus(loan, day, now)
+	return repayment, nil
+}
+
+// refresh accrues penalty on installments not repaid after due day for days of delay since the last service
+// and marks such installments as overdue
+func refresh(loan *domain.Loan, day time.Time) error {
+	penaltyRate, err := deposit.ParseRate(loan.PenaltyRate)
+	if err != nil {
+		return err
+	}
+	accruedUntil := businessday.Day(loan.PenaltyAccruedUntil)
+	overdueDays := int64(0)
+	for _, installment := range loan.Installments {
+		if installment.Status != domain.LoanInstallmentStatusPending &&
+			installment.Status != domain.LoanInstallmentStatusOverdue {
+			continue
+		}
+		// delay starts on the day after due day
+		from := businessday.Day(installment.DueDay).AddDate(0, 0, 1)
+		if from.Before(accruedUntil) {
+			from = accruedUntil
+		}
+		if from.Before(day) {
+			overdueDays += installment.Unpaid() * int64(day.Sub(from).Hours()/24)
+		}
+		if businessday.Day(installment.DueDay).Before(day) {
+			installment.Status = domain.LoanInstallmentStatusOverdue
+		}
+	}
+	penalty := new(big.Rat).Mul(big.NewRat(overdueDays, daysInYear), penaltyRate)
+	loan.Penalty += floor(penalty.Quo(penalty, hundred))
+	if accruedUntil.Before(day) {
+		loan.PenaltyAccruedUntil = day
+	}
+	return nil
+}
+
+// dueInstallments are overdue installments and installments due on day, the oldest first
+func dueInstallments(loan *domain.Loan, day time.Time) []*domain.LoanInstallment {
+	var installments []*domain.LoanInstallment
+	for _, installment := range loan.Installments {
+		switch installment.Status {
+		case domain.LoanInstallmentStatusOverdue:
+			installments = append(installments, installment)
+		case domain.LoanInstallmentStatusPending:
+			if !businessday.Day(installment.DueDay).After(day) {
+				installments = append(installments, installment)
+			}
+		}
+	}
+	return installments
+}
+
+// currentInstallment is the first installment due after day with its interest accrued till day and not paid yet.
+// Interest of month is accrued evenly since the start of month or since the last early repayment.
+func currentInstallment(loan *domain.Loan, day time.Time) (*domain.LoanInstallment, int64) {
+	start := businessday.Day(loan.DisbursedAt)
+	for _, installment := range loan.Installments {
+		dueDay := businessday.Day(installment.DueDay)
+		if installment.Status != domain.LoanInstallmentStatusPending || !dueDay.After(day) {
+			if installment.Status != domain.LoanInstallmentStatusCancelled {
+				start = dueDay
+			}
+			continue
+		}
+		if paidUntil := businessday.Day(loan.InterestPaidUntil); paidUntil.After(start) {
+			start = paidUntil
+		}
+		if !day.After(start) {
+			return installment, 0
+		}
+		interest := big.NewRat(installment.Interest-installment.InterestPaid, 1)
+		interest.Mul(interest, big.NewRat(int64(day.Sub(start).Hours()/24), int64(dueDay.Sub(start).Hours()/24)))
+		return installment, floor(interest)
+	}
+	return nil, 0
+}
+
+// repayDue pays penalty and due installments from repayment, interest before principal and the oldest first.
+// Returns the rest of repayment amount.
+func repayDue(loan *domain.Loan, repayment *domain.LoanRepayment, now time.Time) int64 {
+	left := repayment.Amount
+	penalty := min(left, loan.Penalty)
+	loan.Penalty -= penalty
+	repayment.Penalty += penalty
+	left -= penalty
+
+	for _, installment := range dueInstallments(loan, businessday.Day(now)) {
+		interest := min(left, installment.Interest-installment.InterestPaid)
+		installment.InterestPaid += interest
+		repayment.Interest += interest
+		left -= interest
+
+		principal := min(left, installment.Principal-installment.PrincipalPaid)
+		installment.PrincipalPaid += principal
+		repayment.Principal += principal
+		loan.Outstanding -= principal
+		left -= principal
+
+		if installment.Unpaid() == 0 {
+			installment.Status = domain.LoanInstallmentStatusPaid
+			installment.PaidAt = now
+		}
+	}
+	return left
+}
+
+// replan plans installments starting from current again for principal outstanding after early repayment.
+// Principal prepaid is a paid part of current installment. Interest of current installment is interest paid
+// for days till day and interest on the rest of principal for the rest of its month.
+func replan(
+	loan *domain.Loan,
+	current *domain.LoanInstallment,
+	prepaid int64,
+	day time.Time,
+	now time.Time,
+) error {
+	rate, err := monthlyRate(loan.AnnualRate)
+	if err != nil {
+		return err
+	}
+	var following []*domain.LoanInstallment
+	var dueDays []time.Time
+	start := businessday.Day(loan.DisbursedAt)
+	for _, installment := range loan.Installments {
+		if installment.Number < current.Number {
+			start = businessday.Day(installment.DueDay)
+			continue
+		}
+		following = append(following, installment)
+		dueDays = append(dueDays, installment.DueDay)
+	}
+
+	current.PrincipalPaid += prepaid
+	if loan.Outstanding == 0 {
+		current.Principal = current.PrincipalPaid
+		current.Interest = current.InterestPaid
+		current.Status = domain.LoanInstallmentStatusPaid
+		current.PaidAt = now
+		for _, installment := range following[1:] {
+			installment.Status = domain.LoanInstallmentStatusCancelled
+		}
+		return nil
+	}
+
+	planned := plan(loan.Outstanding, rate, loan.Amortisation, dueDays)
+	for i, installment := range following {
+		installment.Principal = planned[i].Principal
+		installment.Interest = planned[i].Interest
+	}
+	current.Principal += current.PrincipalPaid
+	dueDay := businessday.Day(current.DueDay)
+	rest := big.NewRat(planned[0].Interest, 1)
+	rest.Mul(rest, big.NewRat(int64(dueDay.Sub(day).Hours()/24), int64(dueDay.Sub(start).Hours()/24)))
+	current.Interest = current.InterestPaid + round(rest)
+	return nil
+}
+
+// updateStatus sets status of loan and its next service day after repayment
+func updateStatus(loan *domain.Loan, day time.Time, now time.Time) {
+	loan.UpdatedAt = now
+	loan.Status = domain.LoanStatusActive
+	var next time.Time
+	for _, installment := range loan.Installments {
+		switch installment.Status {
+		case domain.LoanInstallmentStatusOverdue:
+			loan.Status = domain.LoanStatusOverdue
+			loan.NextServiceDay = day.AddDate(0, 0, 1)
+			return
+		case domain.LoanInstallmentStatusPending:
+			dueDay := businessday.Day(installment.DueDay)
+			if !dueDay.After(day) {
+				dueDay = day.AddDate(0, 0, 1)
+			}
+			if next.IsZero() || dueDay.Before(next) {
+				next = dueDay
+			}
+		}
+	}
+	if next.IsZero() && loan.Penalty == 0 {
+		loan.Status = domain.LoanStatusRepaid
+		loan.RepaidAt = now
+		return
+	}
+	if next.IsZero() {
+		// penalty is left to be repaid after all installments
+		loan.Status = domain.LoanStatusOverdue
+		next = day.AddDate(0, 0, 1)
+	}
+	loan.NextServiceDay = next
+}
+
+func newRepayment(
+	loan *domain.Loan,
+	repaymentType domain.LoanRepaymentType,
+	amount int64,
+	now time.Time,
+) *domain.LoanRepayment {
+	loan.Repayments++
+	return &domain.LoanRepayment{
+		LoanID: loan.GeneratedID,
+		Number: loan.Repayments,
+		Type:   repaymentType,
+		Amount: amount,
+		PaidAt: now,
+	}
+}
+
+func min(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
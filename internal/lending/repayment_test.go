@@ -0,0 +1,89 @@
+package lending
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestService_Overdue(t *testing.T) {
+	t.Parallel()
+
+	loan := newLoan(domain.AmortisationAnnuity)
+	assert.NoError(t, Schedule(loan))
+
+	// nothing is collected on due day from empty balance
+	repayment, err := Service(loan, time.Date(2021, time.February, 28, 3, 0, 0, 0, businessday.Location), 0)
+	assert.NoError(t, err)
+	assert.Nil(t, repayment)
+	assert.Equal(t, domain.LoanStatusActive, loan.Status)
+	assert.Equal(t, day(2021, time.March, 1), loan.NextServiceDay)
+
+	// installment is overdue on the next day
+	repayment, err = Service(loan, time.Date(2021, time.March, 1, 3, 0, 0, 0, businessday.Location), 5000)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5000), repayment.Amount)
+	assert.Equal(t, int64(1000), repayment.Interest)
+	assert.Equal(t, int64(4000), repayment.Principal)
+	assert.Equal(t, domain.LoanStatusOverdue, loan.Status)
+	assert.Equal(t, domain.LoanInstallmentStatusOverdue, loan.Installments[0].Status)
+	assert.Equal(t, day(2021, time.March, 2), loan.NextServiceDay)
+
+	// penalty is accrued for 9 days of delay on the rest of installment
+	repayment, err = Service(loan, time.Date(2021, time.March, 10, 3, 0, 0, 0, businessday.Location), 100000)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3904), repayment.Amount)
+	assert.Equal(t, int64(19), repayment.Penalty)
+	assert.Equal(t, int64(3885), repayment.Principal)
+	assert.Equal(t, 2, repayment.Number)
+	assert.Equal(t, domain.LoanStatusActive, loan.Status)
+	assert.Equal(t, domain.LoanInstallmentStatusPaid, loan.Installments[0].Status)
+	assert.Equal(t, int64(92115), loan.Outstanding)
+	assert.Equal(t, day(2021, time.March, 31), loan.NextServiceDay)
+}
+
+func TestRepay(t *testing.T) {
+	t.Parallel()
+
+	loan := newLoan(domain.AmortisationAnnuity)
+	assert.NoError(t, Schedule(loan))
+	_, err := Service(loan, time.Date(2021, time.February, 28, 3, 0, 0, 0, businessday.Location), 100000)
+	assert.NoError(t, err)
+
+	// partial repayment pays interest for 14 days of 31 and plans the rest of principal again
+	repayment, err := Repay(loan, time.Date(2021, time.March, 14, 12, 0, 0, 0, businessday.Location), 50000)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.LoanRepaymentTypeEarly, repayment.Type)
+	assert.Equal(t, int64(415), repayment.Interest)
+	assert.Equal(t, int64(49585), repayment.Principal)
+	assert.Equal(t, int64(42530), loan.Outstanding)
+	current := loan.Installments[1]
+	assert.Equal(t, int64(49585+3677), current.Principal)
+	assert.Equal(t, int64(49585), current.PrincipalPaid)
+	assert.Equal(t, int64(415+233), current.Interest)
+	assert.Equal(t, int64(4102), loan.Installments[2].Amount())
+	assert.Equal(t, domain.LoanStatusActive, loan.Status)
+
+	// repayment of more than loan costs repays it in full with interest for 6 of 17 days left in month
+	repayment, err = Repay(loan, time.Date(2021, time.March, 20, 12, 0, 0, 0, businessday.Location), 1000000)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42530), repayment.Principal)
+	assert.Equal(t, int64(42530+82), repayment.Amount)
+	assert.Equal(t, int64(0), loan.Outstanding)
+	assert.Equal(t, domain.LoanStatusRepaid, loan.Status)
+	assert.Equal(t, domain.LoanInstallmentStatusPaid, loan.Installments[1].Status)
+	assert.Equal(t, domain.LoanInstallmentStatusCancelled, loan.Installments[2].Status)
+	var principal int64
+	for _, installment := range loan.Installments {
+		if installment.Status != domain.LoanInstallmentStatusCancelled {
+			principal += installment.Principal
+		}
+	}
+	assert.Equal(t, int64(100000), principal)
+
+	_, err = Repay(loan, time.Date(2021, time.March, 21, 12, 0, 0, 0, businessday.Location), 100)
+	assert.EqualError(t, err, "loan is already repaid")
+}
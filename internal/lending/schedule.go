@@ -0,0 +1,180 @@
+package lending
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/deposit"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	// MinBorrowerAge is an age of majority, younger customers could not borrow
+	MinBorrowerAge = 18
+	// MaxTermMonths limits term of loans to 30 years
+	MaxTermMonths = 360
+)
+
+var (
+	half    = big.NewRat(1, 2)
+	hundred = big.NewRat(100, 1)
+	// monthsInYear of monthly interest rate
+	monthsInYear = big.NewRat(12, 1)
+)
+
+// Age is a number of full years customer born on birthDate has on day
+func Age(birthDate time.Time, day time.Time) int {
+	birthDate = businessday.Day(birthDate)
+	day = businessday.Day(day)
+	age := day.Year() - birthDate.Year()
+	if day.Before(birthDate.AddDate(age, 0, 0)) {
+		age--
+	}
+	return age
+}
+
+// ValidateProduct checks that product could be offered
+func ValidateProduct(product *domain.LoanProduct) error {
+	_, err := deposit.ParseRate(product.AnnualRate)
+	if err != nil {
+		return fmt.Errorf("annual rate: %s", err.Error())
+	}
+	_, err = deposit.ParseRate(product.PenaltyRate)
+	if err != nil {
+		return fmt.Errorf("penalty rate: %s", err.Error())
+	}
+	if product.MinAmount <= 0 || product.MaxAmount < product.MinAmount {
+		return fmt.Errorf("min amount should be positive and max amount should not be less than min amount")
+	}
+	if product.MinTermMonths < 1 || product.MaxTermMonths < product.MinTermMonths ||
+		product.MaxTermMonths > MaxTermMonths {
+		return fmt.Errorf("term should be between 1 and %d months, max term not less than min term", MaxTermMonths)
+	}
+	return nil
+}
+
+// ValidateApplication checks that application fits limits of product
+func ValidateApplication(application *domain.LoanApplication, product *domain.LoanProduct) error {
+	if application.Amount < product.MinAmount || application.Amount > product.MaxAmount {
+		return fmt.Errorf("amount should be between %d and %d", product.MinAmount, product.MaxAmount)
+	}
+	if application.TermMonths < product.MinTermMonths || application.TermMonths > product.MaxTermMonths {
+		return fmt.Errorf("term should be between %d and %d months", product.MinTermMonths, product.MaxTermMonths)
+	}
+	switch application.Amortisation {
+	case domain.AmortisationAnnuity, domain.AmortisationDifferentiated:
+	default:
+		return fmt.Errorf("amortisation should be one of annuity, differentiated")
+	}
+	return nil
+}
+
+// Schedule plans monthly installments of loan which repay its principal by the end of term.
+// The first installment is due a month after disbursement on the same day of month, or on the last day
+// of shorter months.
+func Schedule(loan *domain.Loan) error {
+	rate, err := monthlyRate(loan.AnnualRate)
+	if err != nil {
+		return err
+	}
+	disbursed := businessday.Day(loan.DisbursedAt)
+	dueDays := make([]time.Time, 0, loan.TermMonths)
+	for month := 1; month <= loan.TermMonths; month++ {
+		dueDays = append(dueDays, addMonths(disbursed, month))
+	}
+
+	loan.Installments = plan(loan.Principal, rate, loan.Amortisation, dueDays)
+	for i, installment := range loan.Installments {
+		installment.LoanID = loan.GeneratedID
+		installment.Number = i + 1
+	}
+	return nil
+}
+
+// plan splits principal between installments due on dueDays. Interest of installment is charged on
+// principal outstanding during its month and rounded to a minor unit, the last installment repays
+// the rest of principal.
+func plan(
+	principal int64,
+	rate *big.Rat,
+	amortisation domain.AmortisationType,
+	dueDays []time.Time,
+) []*domain.LoanInstallment {
+	count := int64(len(dueDays))
+	var payment int64
+	if amortisation == domain.AmortisationAnnuity && count > 0 {
+		payment = annuityPayment(principal, rate, len(dueDays))
+	}
+
+	installments := make([]*domain.LoanInstallment, 0, len(dueDays))
+	outstanding := principal
+	for i, dueDay := range dueDays {
+		installment := &domain.LoanInstallment{
+			DueDay:   dueDay,
+			Interest: round(new(big.Rat).Mul(big.NewRat(outstanding, 1), rate)),
+			Status:   domain.LoanInstallmentStatusPending,
+		}
+		switch {
+		case i == len(dueDays)-1:
+			installment.Principal = outstanding
+		case amortisation == domain.AmortisationAnnuity:
+			installment.Principal = payment - installment.Interest
+		default:
+			installment.Principal = principal / count
+		}
+		if installment.Principal < 0 {
+			installment.Principal = 0
+		}
+		if installment.Principal > outstanding {
+			installment.Principal = outstanding
+		}
+		outstanding -= installment.Principal
+		installments = append(installments, installment)
+	}
+	return installments
+}
+
+// annuityPayment is an equal monthly payment P * r / (1 - (1 + r)^-n) rounded to a minor unit
+func annuityPayment(principal int64, rate *big.Rat, count int) int64 {
+	if rate.Sign() == 0 {
+		return round(big.NewRat(principal, int64(count)))
+	}
+	growth := big.NewRat(1, 1)
+	base := new(big.Rat).Add(big.NewRat(1, 1), rate)
+	for i := 0; i < count; i++ {
+		growth.Mul(growth, base)
+	}
+	payment := new(big.Rat).Mul(big.NewRat(principal, 1), rate)
+	payment.Mul(payment, growth)
+	return round(payment.Quo(payment, growth.Sub(growth, big.NewRat(1, 1))))
+}
+
+// addMonths moves day by months keeping day of month within the month, unlike time.AddDate
+func addMonths(day time.Time, months int) time.Time {
+	first := time.Date(day.Year(), day.Month()+time.Month(months), 1, 0, 0, 0, 0, day.Location())
+	dayOfMonth := day.Day()
+	if last := first.AddDate(0, 1, -1).Day(); dayOfMonth > last {
+		dayOfMonth = last
+	}
+	return first.AddDate(0, 0, dayOfMonth-1)
+}
+
+// monthlyRate is a twelfth of annual percent as a fraction
+func monthlyRate(annualRate string) (*big.Rat, error) {
+	rate, err := deposit.ParseRate(annualRate)
+	if err != nil {
+		return nil, err
+	}
+	rate = new(big.Rat).Quo(rate, hundred)
+	return rate.Quo(rate, monthsInYear), nil
+}
+
+func round(amount *big.Rat) int64 {
+	return floor(new(big.Rat).Add(amount, half))
+}
+
+func floor(amount *big.Rat) int64 {
+	return new(big.Int).Quo(amount.Num(), amount.Denom()).Int64()
+}
@@ -0,0 +1,109 @@
+package lending
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func day(year int, month time.Month, dayOfMonth int) time.Time {
+	return time.Date(year, month, dayOfMonth, 0, 0, 0, 0, businessday.Location)
+}
+
+func newLoan(amortisation domain.AmortisationType) *domain.Loan {
+	disbursedAt := time.Date(2021, time.January, 31, 12, 0, 0, 0, businessday.Location)
+	return &domain.Loan{
+		GeneratedID:         "loan",
+		Principal:           100000,
+		Outstanding:         100000,
+		AnnualRate:          "12",
+		PenaltyRate:         "20",
+		Amortisation:        amortisation,
+		TermMonths:          12,
+		Status:              domain.LoanStatusActive,
+		PenaltyAccruedUntil: day(2021, time.January, 31),
+		NextServiceDay:      day(2021, time.February, 28),
+		DisbursedAt:         disbursedAt,
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                  string
+		amortisation          domain.AmortisationType
+		expectedFirst         [2]int64
+		expectedLast          [2]int64
+		expectedTotalInterest int64
+	}{
+		{
+			name:                  "Annuity",
+			amortisation:          domain.AmortisationAnnuity,
+			expectedFirst:         [2]int64{7885, 1000},
+			expectedLast:          [2]int64{8796, 88},
+			expectedTotalInterest: 6619,
+		},
+		{
+			name:                  "Differentiated",
+			amortisation:          domain.AmortisationDifferentiated,
+			expectedFirst:         [2]int64{8333, 1000},
+			expectedLast:          [2]int64{8337, 83},
+			expectedTotalInterest: 6500,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			loan := newLoan(testCase.amortisation)
+			err := Schedule(loan)
+			assert.NoError(t, err)
+
+			assert.Len(t, loan.Installments, 12)
+			first, last := loan.Installments[0], loan.Installments[11]
+			assert.Equal(t, testCase.expectedFirst, [2]int64{first.Principal, first.Interest})
+			assert.Equal(t, testCase.expectedLast, [2]int64{last.Principal, last.Interest})
+			var principal, interest int64
+			for _, installment := range loan.Installments {
+				principal += installment.Principal
+				interest += installment.Interest
+			}
+			assert.Equal(t, int64(100000), principal)
+			assert.Equal(t, testCase.expectedTotalInterest, interest)
+
+			// due days stay at the end of shorter months
+			assert.Equal(t, day(2021, time.February, 28), first.DueDay)
+			assert.Equal(t, day(2021, time.March, 31), loan.Installments[1].DueDay)
+			assert.Equal(t, day(2022, time.January, 31), last.DueDay)
+		})
+	}
+}
+
+func TestAge(t *testing.T) {
+	t.Parallel()
+
+	birthDate := day(2003, time.March, 15)
+	assert.Equal(t, 17, Age(birthDate, day(2021, time.March, 14)))
+	assert.Equal(t, 18, Age(birthDate, day(2021, time.March, 15)))
+}
+
+func TestValidateApplication(t *testing.T) {
+	t.Parallel()
+
+	product := &domain.LoanProduct{MinAmount: 10000, MaxAmount: 100000, MinTermMonths: 3, MaxTermMonths: 24}
+	application := &domain.LoanApplication{Amount: 50000, TermMonths: 12, Amortisation: domain.AmortisationAnnuity}
+	assert.NoError(t, ValidateApplication(application, product))
+
+	application.Amount = 200000
+	assert.EqualError(t, ValidateApplication(application, product), "amount should be between 10000 and 100000")
+
+	application.Amount = 50000
+	application.TermMonths = 36
+	assert.EqualError(t, ValidateApplication(application, product), "term should be between 3 and 24 months")
+}
@@ -0,0 +1,120 @@
+package monitoring
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// Evaluate checks movement against rules. History contains previous movements of the same customer
+// for at least Rules.MaxWindow before the movement. Returned alerts have only rule fields and details filled.
+func Evaluate(
+	rules Rules,
+	movement *domain.Movement,
+	customer *domain.Customer,
+	history []*domain.Movement,
+) []*domain.Alert {
+	var alerts []*domain.Alert
+	for _, rule := range rules.Rules {
+		if rule.Currency != "" && rule.Currency != movement.Currency {
+			continue
+		}
+
+		details, triggered := evaluateRule(rule, movement, customer, history)
+		if !triggered {
+			continue
+		}
+		alerts = append(alerts, &domain.Alert{
+			CustomerID: movement.CustomerID,
+			MovementID: movement.GeneratedID,
+			RuleName:   rule.Name,
+			RuleType:   string(rule.Type),
+			Severity:   rule.Severity,
+			Details:    details,
+		})
+	}
+	return alerts
+}
+
+func evaluateRule(
+	rule Rule,
+	movement *domain.Movement,
+	customer *domain.Customer,
+	history []*domain.Movement,
+) (string, bool) {
+	switch rule.Type {
+	case RuleTypeAmountThreshold:
+		if movement.Amount >= rule.Amount {
+			return fmt.Sprintf("amount %d %s is not less than %d", movement.Amount, movement.Currency, rule.Amount), true
+		}
+
+	case RuleTypeVelocity:
+		count, amount := 1, movement.Amount
+		for _, previous := range withinWindow(rule, movement, history) {
+			count++
+			amount += previous.Amount
+		}
+		if rule.MaxCount > 0 && count > rule.MaxCount {
+			return fmt.Sprintf("%d movements within %s, max %d", count, rule.Window, rule.MaxCount), true
+		}
+		if rule.MaxAmount > 0 && amount > rule.MaxAmount {
+			return fmt.Sprintf(
+				"%d %s moved within %s, max %d", amount, movement.Currency, rule.Window, rule.MaxAmount,
+			), true
+		}
+
+	case RuleTypeStructuring:
+		lowerBound := rule.Amount * (100 - rule.MarginPercent) / 100
+		isBelowThreshold := func(m *domain.Movement) bool {
+			return m.Amount >= lowerBound && m.Amount < rule.Amount
+		}
+		if !isBelowThreshold(movement) {
+			return "", false
+		}
+		count := 1
+		for _, previous := range withinWindow(rule, movement, history) {
+			if isBelowThreshold(previous) {
+				count++
+			}
+		}
+		if count >= rule.MinCount {
+			return fmt.Sprintf(
+				"%d movements between %d and %d %s within %s",
+				count, lowerBound, rule.Amount, movement.Currency, rule.Window,
+			), true
+		}
+
+	case RuleTypeNewCustomer:
+		accountAge := movement.CreatedAt.Sub(customer.CreatedAt)
+		if accountAge < rule.AccountAge && movement.Amount >= rule.Amount {
+			return fmt.Sprintf(
+				"amount %d %s moved by customer registered %s ago",
+				movement.Amount, movement.Currency, accountAge.Truncate(1e9),
+			), true
+		}
+
+	case RuleTypeCountryRisk:
+		for _, country := range rule.Countries {
+			if strings.EqualFold(strings.TrimSpace(customer.Address.Country), country) && movement.Amount >= rule.Amount {
+				return fmt.Sprintf("customer lives in high risk country %s", customer.Address.Country), true
+			}
+		}
+	}
+	return "", false
+}
+
+// withinWindow returns previous movements in the same currency made within rule window before movement
+func withinWindow(rule Rule, movement *domain.Movement, history []*domain.Movement) []*domain.Movement {
+	since := movement.CreatedAt.Add(-rule.Window)
+	var movements []*domain.Movement
+	for _, previous := range history {
+		if previous.GeneratedID == movement.GeneratedID || previous.Currency != movement.Currency {
+			continue
+		}
+		if previous.CreatedAt.After(since) && !previous.CreatedAt.After(movement.CreatedAt) {
+			movements = append(movements, previous)
+		}
+	}
+	return movements
+}
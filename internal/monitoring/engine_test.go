@@ -0,0 +1,155 @@
+package monitoring
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 8, 18, 12, 0, 0, 0, time.UTC)
+	customer := &domain.Customer{
+		GeneratedID: "foobar",
+		Address:     domain.Address{Country: "Russia"},
+		CreatedAt:   now.Add(-365 * 24 * time.Hour),
+	}
+	movement := func(id string, amount int64, ago time.Duration) *domain.Movement {
+		return &domain.Movement{
+			GeneratedID: id,
+			CustomerID:  "foobar",
+			Type:        domain.MovementTypeTransfer,
+			Amount:      amount,
+			Currency:    "RUB",
+			CreatedAt:   now.Add(-ago),
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		rule          Rule
+		customer      *domain.Customer
+		movement      *domain.Movement
+		history       []*domain.Movement
+		expectedAlert bool
+	}{
+		{
+			"AmountThreshold",
+			Rule{Name: "large", Type: RuleTypeAmountThreshold, Amount: 60000000},
+			customer,
+			movement("m", 60000000, 0),
+			nil,
+			true,
+		},
+		{
+			"AmountThresholdBelow",
+			Rule{Name: "large", Type: RuleTypeAmountThreshold, Amount: 60000000},
+			customer,
+			movement("m", 59999999, 0),
+			nil,
+			false,
+		},
+		{
+			"AmountThresholdOtherCurrency",
+			Rule{Name: "large", Type: RuleTypeAmountThreshold, Currency: "USD", Amount: 1000000},
+			customer,
+			movement("m", 60000000, 0),
+			nil,
+			false,
+		},
+		{
+			"VelocityCount",
+			Rule{Name: "frequent", Type: RuleTypeVelocity, Window: time.Hour, MaxCount: 2},
+			customer,
+			movement("m", 100, 0),
+			[]*domain.Movement{movement("a", 100, 10*time.Minute), movement("b", 100, 20*time.Minute)},
+			true,
+		},
+		{
+			"VelocityCountOutsideWindow",
+			Rule{Name: "frequent", Type: RuleTypeVelocity, Window: time.Hour, MaxCount: 2},
+			customer,
+			movement("m", 100, 0),
+			[]*domain.Movement{movement("a", 100, 10*time.Minute), movement("b", 100, 2*time.Hour)},
+			false,
+		},
+		{
+			"VelocityAmount",
+			Rule{Name: "turnover", Type: RuleTypeVelocity, Window: 24 * time.Hour, MaxAmount: 1000},
+			customer,
+			movement("m", 600, 0),
+			[]*domain.Movement{movement("a", 500, time.Hour)},
+			true,
+		},
+		{
+			"Structuring",
+			Rule{
+				Name: "structuring", Type: RuleTypeStructuring, Amount: 60000000,
+				MarginPercent: 10, MinCount: 3, Window: 24 * time.Hour,
+			},
+			customer,
+			movement("m", 59000000, 0),
+			[]*domain.Movement{
+				movement("a", 58000000, time.Hour),
+				movement("b", 55000000, 2*time.Hour),
+				movement("c", 1000, 3*time.Hour),
+			},
+			true,
+		},
+		{
+			"StructuringNotEnoughMovements",
+			Rule{
+				Name: "structuring", Type: RuleTypeStructuring, Amount: 60000000,
+				MarginPercent: 10, MinCount: 3, Window: 24 * time.Hour,
+			},
+			customer,
+			movement("m", 59000000, 0),
+			[]*domain.Movement{movement("a", 58000000, time.Hour), movement("b", 50000000, 2*time.Hour)},
+			false,
+		},
+		{
+			"NewCustomer",
+			Rule{Name: "new", Type: RuleTypeNewCustomer, AccountAge: 30 * 24 * time.Hour, Amount: 1000000},
+			&domain.Customer{GeneratedID: "foobar", CreatedAt: now.Add(-24 * time.Hour)},
+			movement("m", 1000000, 0),
+			nil,
+			true,
+		},
+		{
+			"OldCustomer",
+			Rule{Name: "new", Type: RuleTypeNewCustomer, AccountAge: 30 * 24 * time.Hour, Amount: 1000000},
+			customer,
+			movement("m", 1000000, 0),
+			nil,
+			false,
+		},
+		{
+			"CountryRisk",
+			Rule{Name: "country", Type: RuleTypeCountryRisk, Countries: []string{"Iran", "russia"}},
+			customer,
+			movement("m", 1, 0),
+			nil,
+			true,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			alerts := Evaluate(Rules{Rules: []Rule{test.rule}}, test.movement, test.customer, test.history)
+
+			if !test.expectedAlert {
+				assert.Empty(t, alerts)
+				return
+			}
+			assert.Len(t, alerts, 1)
+			assert.Equal(t, test.rule.Name, alerts[0].RuleName)
+			assert.Equal(t, string(test.rule.Type), alerts[0].RuleType)
+			assert.Equal(t, "m", alerts[0].MovementID)
+			assert.Equal(t, "foobar", alerts[0].CustomerID)
+			assert.NotEmpty(t, alerts[0].Details)
+		})
+	}
+}
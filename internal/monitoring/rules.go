@@ -0,0 +1,114 @@
+package monitoring
+
+import (
+	"fmt"
+	"time"
+)
+
+type RuleType string
+
+const (
+	// RuleTypeAmountThreshold alerts on single movement of at least Amount
+	RuleTypeAmountThreshold RuleType = "amount_threshold"
+	// RuleTypeVelocity alerts when customer makes more than MaxCount movements
+	// or moves more than MaxAmount within Window
+	RuleTypeVelocity RuleType = "velocity"
+	// RuleTypeStructuring alerts when customer makes at least MinCount movements within Window
+	// which are just below Amount, i.e. not less than Amount minus MarginPercent
+	RuleTypeStructuring RuleType = "structuring"
+	// RuleTypeNewCustomer alerts on movement of at least Amount made by customer registered less than AccountAge ago
+	RuleTypeNewCustomer RuleType = "new_customer"
+	// RuleTypeCountryRisk alerts on movement of at least Amount made by customer living in one of Countries
+	RuleTypeCountryRisk RuleType = "country_risk"
+)
+
+type Rules struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule is declared in rules file. Which fields are used depends on Type.
+// Amounts are in minor currency units, rule applies to all currencies when Currency is empty.
+type Rule struct {
+	Name          string        `yaml:"name"`
+	Type          RuleType      `yaml:"type"`
+	Severity      string        `yaml:"severity"`
+	Currency      string        `yaml:"currency"`
+	Amount        int64         `yaml:"amount"`
+	Window        time.Duration `yaml:"window"`
+	MaxCount      int           `yaml:"max_count"`
+	MaxAmount     int64         `yaml:"max_amount"`
+	MarginPercent int64         `yaml:"margin_percent"`
+	MinCount      int           `yaml:"min_count"`
+	AccountAge    time.Duration `yaml:"account_age"`
+	Countries     []string      `yaml:"countries"`
+}
+
+func (r Rules) Validate() error {
+	names := make(map[string]bool, len(r.Rules))
+	for _, rule := range r.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rule name is mandatory")
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("rule %s is declared twice", rule.Name)
+		}
+		names[rule.Name] = true
+
+		err := rule.validate()
+		if err != nil {
+			return fmt.Errorf("rule %s: %s", rule.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// MaxWindow is a period of customer history needed to evaluate all rules
+func (r Rules) MaxWindow() time.Duration {
+	var window time.Duration
+	for _, rule := range r.Rules {
+		if rule.Window > window {
+			window = rule.Window
+		}
+	}
+	return window
+}
+
+func (r Rule) validate() error {
+	switch r.Type {
+	case RuleTypeAmountThreshold:
+		if r.Amount <= 0 {
+			return fmt.Errorf("amount should be positive")
+		}
+	case RuleTypeVelocity:
+		if r.Window <= 0 {
+			return fmt.Errorf("window should be positive")
+		}
+		if r.MaxCount <= 0 && r.MaxAmount <= 0 {
+			return fmt.Errorf("max_count or max_amount should be positive")
+		}
+	case RuleTypeStructuring:
+		if r.Window <= 0 {
+			return fmt.Errorf("window should be positive")
+		}
+		if r.Amount <= 0 {
+			return fmt.Errorf("amount should be positive")
+		}
+		if r.MarginPercent <= 0 || r.MarginPercent >= 100 {
+			return fmt.Errorf("margin_percent should be between 0 and 100")
+		}
+		if r.MinCount <= 1 {
+			return fmt.Errorf("min_count should be greater than 1")
+		}
+	case RuleTypeNewCustomer:
+		if r.AccountAge <= 0 {
+			return fmt.Errorf("account_age should be positive")
+		}
+	case RuleTypeCountryRisk:
+		if len(r.Countries) == 0 {
+			return fmt.Errorf("countries should not be empty")
+		}
+	default:
+		return fmt.Errorf("unknown rule type %s", r.Type)
+	}
+	return nil
+}
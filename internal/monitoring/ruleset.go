@@ -0,0 +1,96 @@
+package monitoring
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleSet holds rules loaded from YAML file and reloads them when file changes
+type RuleSet struct {
+	path string
+
+	mu      sync.RWMutex
+	rules   Rules
+	modTime time.Time
+}
+
+// LoadRuleSet reads rules from path, file should exist and contain valid rules
+func LoadRuleSet(path string) (*RuleSet, error) {
+	ruleSet := &RuleSet{path: path}
+	_, err := ruleSet.Reload()
+	if err != nil {
+		return nil, err
+	}
+	return ruleSet, nil
+}
+
+func (s *RuleSet) Rules() Rules {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.rules
+}
+
+// Reload reads rules file if it was modified since last load. Invalid file keeps previously loaded rules
+// and is reported once, until it is modified again.
+func (s *RuleSet) Reload() (bool, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.RLock()
+	modTime := s.modTime
+	s.mu.RUnlock()
+	if info.ModTime().Equal(modTime) {
+		return false, nil
+	}
+
+	content, err := ioutil.ReadFile(s.path)
+	if err != nil {
+		return false, err
+	}
+	rules, err := ParseRules(content)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.modTime = info.ModTime()
+	if err != nil {
+		return false, fmt.Errorf("%s: %s", s.path, err.Error())
+	}
+	s.rules = rules
+	return true, nil
+}
+
+// Watch checks rules file every interval until stop is closed
+func (s *RuleSet) Watch(stop <-chan struct{}, interval time.Duration, onReload func(err error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			reloaded, err := s.Reload()
+			if reloaded || err != nil {
+				onReload(err)
+			}
+		}
+	}
+}
+
+func ParseRules(content []byte) (Rules, error) {
+	var rules Rules
+	err := yaml.Unmarshal(content, &rules)
+	if err != nil {
+		return Rules{}, err
+	}
+	err = rules.Validate()
+	if err != nil {
+		return Rules{}, err
+	}
+	return rules, nil
+}
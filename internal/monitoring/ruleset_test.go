@@ -0,0 +1,129 @@
+package monitoring
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRules(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		input         string
+		expectedRules Rules
+		expectedError string
+	}{
+		{
+			"Valid",
+			`
+rules:
+  - name: frequent transfers
+    type: velocity
+    severity: medium
+    window: 1h
+    max_count: 10
+  - name: sanctioned countries
+    type: country_risk
+    severity: high
+    countries: [Iran, North Korea]
+`,
+			Rules{Rules: []Rule{
+				{Name: "frequent transfers", Type: RuleTypeVelocity, Severity: "medium", Window: time.Hour, MaxCount: 10},
+				{
+					Name: "sanctioned countries", Type: RuleTypeCountryRisk, Severity: "high",
+					Countries: []string{"Iran", "North Korea"},
+				},
+			}},
+			"",
+		},
+		{
+			"UnknownType",
+			"rules:\n  - name: foo\n    type: bar\n",
+			Rules{},
+			"rule foo: unknown rule type bar",
+		},
+		{
+			"DuplicateName",
+			"rules:\n  - {name: foo, type: amount_threshold, amount: 1}\n  - {name: foo, type: amount_threshold, amount: 2}\n",
+			Rules{},
+			"rule foo is declared twice",
+		},
+		{
+			"InvalidStructuring",
+			"rules:\n  - {name: foo, type: structuring, amount: 100, window: 24h, margin_percent: 10, min_count: 1}\n",
+			Rules{},
+			"rule foo: min_count should be greater than 1",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			rules, err := ParseRules([]byte(test.input))
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedRules, rules)
+		})
+	}
+}
+
+func TestRuleSet_Reload(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "monitoring")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rules.yaml")
+
+	writeRules := func(content string, modTime time.Time) {
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+		assert.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	start := time.Now().Add(-time.Hour)
+
+	writeRules("rules:\n  - {name: large, type: amount_threshold, amount: 100}\n", start)
+	ruleSet, err := LoadRuleSet(path)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), ruleSet.Rules().Rules[0].Amount)
+
+	// file not changed
+	reloaded, err := ruleSet.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+
+	// file changed
+	writeRules("rules:\n  - {name: large, type: amount_threshold, amount: 200}\n", start.Add(time.Minute))
+	reloaded, err = ruleSet.Reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, int64(200), ruleSet.Rules().Rules[0].Amount)
+
+	// invalid file keeps previous rules
+	writeRules("rules:\n  - {name: large, type: amount_threshold}\n", start.Add(2*time.Minute))
+	reloaded, err = ruleSet.Reload()
+	assert.Error(t, err)
+	assert.False(t, reloaded)
+	assert.Equal(t, int64(200), ruleSet.Rules().Rules[0].Amount)
+
+	// unchanged invalid file is reported once
+	reloaded, err = ruleSet.Reload()
+	assert.NoError(t, err)
+	assert.False(t, reloaded)
+	assert.Equal(t, int64(200), ruleSet.Rules().Rules[0].Amount)
+
+	// fixed file is loaded
+	writeRules("rules:\n  - {name: large, type: amount_threshold, amount: 300}\n", start.Add(3*time.Minute))
+	reloaded, err = ruleSet.Reload()
+	assert.NoError(t, err)
+	assert.True(t, reloaded)
+	assert.Equal(t, int64(300), ruleSet.Rules().Rules[0].Amount)
+}
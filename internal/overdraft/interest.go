@@ -0,0 +1,88 @@
+package overdraft
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/deposit"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// daysInYear of overdraft interest
+const daysInYear = 365
+
+var hundred = big.NewRat(100, 1)
+
+// ValidateLine checks that credit line could be approved
+func ValidateLine(line *domain.CreditLine) error {
+	if line.Limit < 0 {
+		return fmt.Errorf("limit should not be negative")
+	}
+	_, err := deposit.ParseRate(line.AnnualRate)
+	if err != nil {
+		return fmt.Errorf("annual rate: %s", err.Error())
+	}
+	return nil
+}
+
+// Accrue accrues interest on negative closing balances of days since credit line is accrued until and returns
+// interest to charge. Interest of day is rounded down to a minor unit. Interest accrued during month
+// is charged once the last day of month is accrued, so it is charged on the first day of the next month.
+func Accrue(line *domain.CreditLine, closing []*domain.ClosingBalance) (int64, error) {
+	rate, err := deposit.ParseRate(line.AnnualRate)
+	if err != nil {
+		return 0, err
+	}
+	// days are read from database in any time zone, months start on the first days of month in Moscow
+	line.AccruedUntil = businessday.Day(line.AccruedUntil)
+
+	charge := int64(0)
+	for _, balance := range closing {
+		day := businessday.Day(balance.Day)
+		if day.Before(line.AccruedUntil) {
+			continue
+		}
+		if balance.Balance < 0 {
+			line.AccruedInterest += interest(-balance.Balance, rate)
+		}
+		line.AccruedUntil = day.AddDate(0, 0, 1)
+		if line.AccruedUntil.Day() == 1 {
+			charge += line.AccruedInterest
+			line.AccruedInterest = 0
+		}
+	}
+	if charge > 0 {
+		line.ChargedInterest += charge
+		line.Charges++
+	}
+	return charge, nil
+}
+
+// Utilise reports a part of credit limit used by balance. Holds use limit only when own funds do not cover them.
+func Utilise(line *domain.CreditLine, balance *domain.AvailableBalance) *domain.CreditLineUtilisation {
+	used := balance.Holds - balance.Balance
+	if used < 0 {
+		used = 0
+	}
+	if used > line.Limit {
+		used = line.Limit
+	}
+	percent := "0.00"
+	if line.Limit > 0 {
+		percent = big.NewRat(used*100, line.Limit).FloatString(2)
+	}
+	return &domain.CreditLineUtilisation{
+		Line:    line,
+		Balance: balance,
+		Used:    used,
+		Percent: percent,
+	}
+}
+
+// interest of a day on overdrawn amount rounded down to a minor unit
+func interest(overdrawn int64, rate *big.Rat) int64 {
+	amount := new(big.Rat).Mul(big.NewRat(overdrawn, daysInYear), rate)
+	amount.Quo(amount, hundred)
+	return new(big.Int).Quo(amount.Num(), amount.Denom()).Int64()
+}
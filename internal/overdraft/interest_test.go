@@ -0,0 +1,80 @@
+package overdraft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestAccrue(t *testing.T) {
+	t.Parallel()
+
+	// 36.5% a year is 0.1% a day
+	line := &domain.CreditLine{
+		Limit:        200000,
+		AnnualRate:   "36.5",
+		AccruedUntil: day(2021, time.January, 30),
+	}
+	charge, err := Accrue(line, []*domain.ClosingBalance{
+		{Day: day(2021, time.January, 29), Balance: -900000},
+		{Day: day(2021, time.January, 30), Balance: -100000},
+		{Day: day(2021, time.January, 31), Balance: -50000},
+		{Day: day(2021, time.February, 1), Balance: -10001},
+		{Day: day(2021, time.February, 2), Balance: 5000},
+	})
+	assert.NoError(t, err)
+
+	// day which is already accrued is skipped, January interest is charged once January 31 is accrued
+	assert.Equal(t, int64(150), charge)
+	assert.Equal(t, int64(150), line.ChargedInterest)
+	assert.Equal(t, 1, line.Charges)
+	// interest of day is rounded down and positive balance does not accrue interest
+	assert.Equal(t, int64(10), line.AccruedInterest)
+	assert.Equal(t, day(2021, time.February, 3), line.AccruedUntil)
+
+	charge, err = Accrue(line, []*domain.ClosingBalance{{Day: day(2021, time.February, 3), Balance: -20000}})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), charge)
+	assert.Equal(t, int64(30), line.AccruedInterest)
+	assert.Equal(t, 1, line.Charges)
+}
+
+func TestUtilise(t *testing.T) {
+	t.Parallel()
+
+	line := &domain.CreditLine{Limit: 100000}
+	for _, tc := range []struct {
+		balance *domain.AvailableBalance
+		used    int64
+		percent string
+	}{
+		{balance: &domain.AvailableBalance{Balance: 10000, Limit: 100000, Holds: 5000}, used: 0, percent: "0.00"},
+		{balance: &domain.AvailableBalance{Balance: -30000, Limit: 100000, Holds: 5000}, used: 35000, percent: "35.00"},
+		{balance: &domain.AvailableBalance{Balance: -1, Limit: 100000}, used: 1, percent: "0.00"},
+		{balance: &domain.AvailableBalance{Balance: -150000, Limit: 100000}, used: 100000, percent: "100.00"},
+	} {
+		utilisation := Utilise(line, tc.balance)
+		assert.Equal(t, tc.used, utilisation.Used)
+		assert.Equal(t, tc.percent, utilisation.Percent)
+		assert.Equal(t, tc.balance.Balance+tc.balance.Limit-tc.balance.Holds, utilisation.Balance.Available())
+	}
+
+	utilisation := Utilise(&domain.CreditLine{}, &domain.AvailableBalance{Balance: -100})
+	assert.Equal(t, int64(0), utilisation.Used)
+	assert.Equal(t, "0.00", utilisation.Percent)
+}
+
+func TestValidateLine(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateLine(&domain.CreditLine{Limit: 100000, AnnualRate: "24.9"}))
+	assert.EqualError(t, ValidateLine(&domain.CreditLine{Limit: -1, AnnualRate: "24.9"}), "limit should not be negative")
+	assert.Error(t, ValidateLine(&domain.CreditLine{Limit: 100000, AnnualRate: "-1"}))
+}
+
+func day(year int, month time.Month, dayOfMonth int) time.Time {
+	return time.Date(year, month, dayOfMonth, 0, 0, 0, 0, businessday.Location)
+}
@@ -0,0 +1,17 @@
+package p2p
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// MaskName shows first name and initial of last name, like Bruce W.
+func MaskName(firstName string, lastName string) string {
+	firstName = strings.TrimSpace(firstName)
+	lastName = strings.TrimSpace(lastName)
+	if lastName == "" {
+		return firstName
+	}
+	initial, _ := utf8.DecodeRuneInString(lastName)
+	return strings.TrimSpace(firstName + " " + strings.ToUpper(string(initial)) + ".")
+}
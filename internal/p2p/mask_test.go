@@ -0,0 +1,16 @@
+package p2p
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Bruce W.", MaskName("Bruce", "Wayne"))
+	assert.Equal(t, "Иван И.", MaskName("Иван", " иванов"))
+	assert.Equal(t, "Bruce", MaskName("Bruce", ""))
+	assert.Equal(t, "W.", MaskName("", "Wayne"))
+}
@@ -0,0 +1,22 @@
+package p2p
+
+import "time"
+
+// LookupLimit allows to look up at most Phones distinct phones within Window
+type LookupLimit struct {
+	Window time.Duration
+	Phones int
+}
+
+// LookupPolicy limits phone lookups of customer, so that phones of customers could not be enumerated.
+// Lookups of phones already looked up within window are not limited, as they reveal nothing new.
+type LookupPolicy struct {
+	Limits []LookupLimit
+}
+
+var DefaultLookupPolicy = LookupPolicy{
+	Limits: []LookupLimit{
+		{Window: time.Hour, Phones: 10},
+		{Window: 24 * time.Hour, Phones: 30},
+	},
+}
@@ -0,0 +1,25 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatAmount shows amount in minor units as 1 234 567.89
+func FormatAmount(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	whole := strconv.FormatInt(amount/100, 10)
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(' ')
+		}
+		grouped.WriteRune(digit)
+	}
+	return fmt.Sprintf("%s%s.%02d", sign, grouped.String(), amount%100)
+}
@@ -0,0 +1,16 @@
+package pdf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAmount(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "0.05", FormatAmount(5))
+	assert.Equal(t, "999.99", FormatAmount(99999))
+	assert.Equal(t, "1 000.00", FormatAmount(100000))
+	assert.Equal(t, "-12 345 678.90", FormatAmount(-1234567890))
+}
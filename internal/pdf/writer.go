@@ -0,0 +1,239 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in points
+const (
+	PageWidth  = 595.0
+	PageHeight = 842.0
+)
+
+// object numbers reserved for objects written on Close, pages are numbered after them
+const (
+	catalogObject = iota + 1
+	pagesObject
+	regularFontObject
+	boldFontObject
+	encodingObject
+	firstPageObject
+)
+
+// cyrillicGlyphs are glyph names of Windows-1251 bytes 0xC0-0xFF, i.e. А-Я and а-я without Ё and ё
+var cyrillicGlyphs = func() []string {
+	glyphs := make([]string, 0, 64)
+	for _, first := range []int{10017, 10065} {
+		for i := 0; i < 33; i++ {
+			// Ё and ё have glyph names between Е and Ж but are outside of the range in Windows-1251
+			if i == 6 {
+				continue
+			}
+			glyphs = append(glyphs, fmt.Sprintf("/afii%d", first+i))
+		}
+	}
+	return glyphs
+}()
+
+// helveticaWidths are widths of printable ASCII characters in Helvetica in thousandths of font size
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// Writer streams PDF 1.4 document of A4 pages with Helvetica and Helvetica-Bold standard fonts.
+// Every page is written as soon as it is complete, so only the current page is kept in memory.
+// Fonts are not embedded, text is encoded in Windows-1251 with Cyrillic glyph names,
+// so Russian text is shown by viewers which provide standard fonts with Cyrillic glyphs.
+type Writer struct {
+	out *countingWriter
+	// offsets of objects by object number, the first one is never used
+	offsets []int64
+	pages   int
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{out: &countingWriter{writer: w}, offsets: make([]int64, firstPageObject)}
+}
+
+type Page struct {
+	content bytes.Buffer
+}
+
+// Text draws text with baseline starting at x, y from the bottom left corner of page
+func (p *Page) Text(x, y, size float64, bold bool, value string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, encodeText(value))
+}
+
+// TextRight draws text ending at x
+func (p *Page) TextRight(x, y, size float64, bold bool, value string) {
+	p.Text(x-TextWidth(value, size), y, size, bold, value)
+}
+
+func (p *Page) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+// WritePage writes page and its content, page should not be changed afterwards
+func (w *Writer) WritePage(page *Page) error {
+	if w.pages == 0 {
+		// binary comment tells transfer tools that file is not a text
+		_, _ = io.WriteString(w.out, "%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	}
+	pageObject := firstPageObject + 2*w.pages
+	w.pages++
+
+	w.object(pageObject, fmt.Sprintf(
+		"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] "+
+			"/Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+		pagesObject,
+		PageWidth,
+		PageHeight,
+		regularFontObject,
+		boldFontObject,
+		pageObject+1,
+	))
+
+	var compressed bytes.Buffer
+	zipper := zlib.NewWriter(&compressed)
+	_, err := zipper.Write(page.content.Bytes())
+	if err != nil {
+		return err
+	}
+	err = zipper.Close()
+	if err != nil {
+		return err
+	}
+	w.object(pageObject+1, fmt.Sprintf(
+		"<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+		compressed.Len(),
+		compressed.Bytes(),
+	))
+	return w.out.err
+}
+
+// Close writes catalog, page tree and fonts which are referenced by pages, then cross-reference table.
+// Document without pages gets an empty page.
+func (w *Writer) Close() error {
+	if w.pages == 0 {
+		err := w.WritePage(&Page{})
+		if err != nil {
+			return err
+		}
+	}
+
+	kids := make([]string, 0, w.pages)
+	for i := 0; i < w.pages; i++ {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPageObject+2*i))
+	}
+	w.object(catalogObject, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObject))
+	w.object(pagesObject, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), w.pages))
+	font := "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding %d 0 R >>"
+	w.object(regularFontObject, fmt.Sprintf(font, "Helvetica", encodingObject))
+	w.object(boldFontObject, fmt.Sprintf(font, "Helvetica-Bold", encodingObject))
+	w.object(encodingObject, fmt.Sprintf(
+		"<< /Type /Encoding /BaseEncoding /WinAnsiEncoding "+
+			"/Differences [168 /afii10023 184 /afii10071 /afii61352 192 %s] >>",
+		strings.Join(cyrillicGlyphs, " "),
+	))
+
+	xrefOffset := w.out.count
+	fmt.Fprintf(w.out, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets))
+	for _, offset := range w.offsets[1:] {
+		fmt.Fprintf(w.out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(
+		w.out,
+		"trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.offsets),
+		catalogObject,
+		xrefOffset,
+	)
+	return w.out.err
+}
+
+func (w *Writer) object(number int, body string) {
+	for len(w.offsets) <= number {
+		w.offsets = append(w.offsets, 0)
+	}
+	w.offsets[number] = w.out.count
+	fmt.Fprintf(w.out, "%d 0 obj\n%s\nendobj\n", number, body)
+}
+
+// encodeText converts text to Windows-1251 bytes escaped for PDF string, unsupported characters become ?
+func encodeText(value string) string {
+	var encoded strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			encoded.WriteByte('\\')
+			encoded.WriteRune(r)
+		case r >= ' ' && r <= '~':
+			encoded.WriteRune(r)
+		case r >= 'А' && r <= 'я':
+			fmt.Fprintf(&encoded, "\\%03o", 0xC0+r-'А')
+		case r == 'Ё':
+			encoded.WriteString("\\250")
+		case r == 'ё':
+			encoded.WriteString("\\270")
+		case r == '№':
+			encoded.WriteString("\\271")
+		default:
+			encoded.WriteByte('?')
+		}
+	}
+	return encoded.String()
+}
+
+// TextWidth approximates width of text in points, characters out of ASCII are counted as digits
+func TextWidth(value string, size float64) float64 {
+	var width int
+	for _, r := range value {
+		if r >= ' ' && r <= '~' {
+			width += helveticaWidths[r-' ']
+		} else {
+			width += 556
+		}
+	}
+	return float64(width) * size / 1000
+}
+
+// TruncateText cuts text to fit into width
+func TruncateText(value string, size float64, width float64) string {
+	if TextWidth(value, size) <= width {
+		return value
+	}
+	runes := []rune(value)
+	for len(runes) > 0 && TextWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+type countingWriter struct {
+	writer io.Writer
+	count  int64
+	err    error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.writer.Write(p)
+	c.count += int64(n)
+	c.err = err
+	return n, err
+}
@@ -0,0 +1,69 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		pages int
+		kids  string
+	}{
+		{name: "Pages", pages: 3, kids: "/Kids [6 0 R 8 0 R 10 0 R] /Count 3"},
+		{name: "NoPages", pages: 0, kids: "/Kids [6 0 R] /Count 1"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			var output bytes.Buffer
+			writer := NewWriter(&output)
+			for i := 0; i < tt.pages; i++ {
+				page := &Page{}
+				page.Text(40, 800, 12, i == 0, fmt.Sprintf("Страница %d", i+1))
+				err := writer.WritePage(page)
+				assert.NoError(t, err)
+				// pages are written at once
+				assert.Contains(t, output.String(), fmt.Sprintf("%d 0 obj\n<< /Type /Page ", 6+2*i))
+			}
+			err := writer.Close()
+			assert.NoError(t, err)
+
+			// assert
+			document := output.Bytes()
+			assert.True(t, bytes.HasPrefix(document, []byte("%PDF-1.4\n")))
+			assert.True(t, bytes.HasSuffix(document, []byte("%%EOF\n")))
+			assert.Contains(t, string(document), tt.kids)
+
+			startXref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(document)
+			assert.NotNil(t, startXref)
+			xrefOffset, _ := strconv.Atoi(string(startXref[1]))
+			assert.True(t, bytes.HasPrefix(document[xrefOffset:], []byte("xref\n")))
+			offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(document[xrefOffset:], -1)
+			assert.Len(t, offsets, 5+2*len(regexp.MustCompile(`/Type /Page `).FindAll(document, -1)))
+			for i, offset := range offsets {
+				objectOffset, _ := strconv.Atoi(string(offset[1]))
+				assert.True(t, bytes.HasPrefix(document[objectOffset:], []byte(fmt.Sprintf("%d 0 obj\n", i+1))))
+			}
+		})
+	}
+}
+
+func TestEncodeText(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, `Invoice \271 1 \(draft\)`, encodeText("Invoice № 1 (draft)"))
+	assert.Equal(t, `\300\337\340\377\250\270`, encodeText("АЯаяЁё"))
+	assert.Equal(t, "?5", encodeText("€5"))
+}
@@ -0,0 +1,52 @@
+package phone
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// E.164 number is at most 15 digits long including country code, the shortest numbers in use are 7 digits long
+	minDigits = 7
+	maxDigits = 15
+	// russianCountryCode is assumed for numbers written in national format
+	russianCountryCode = "7"
+	// russianDigits is a length of Russian and Kazakh numbers with country code
+	russianDigits = 11
+)
+
+// separators are ignored in written phone numbers
+var separators = strings.NewReplacer(" ", "", "\u00A0", "", "-", "", "(", "", ")", "", ".", "")
+
+// Normalize converts phone number to E.164 format like +79161234567. Separators are ignored and international
+// call prefix 00 is read as +. Numbers without + are read as Russian ones: 8 916 123-45-67 with trunk prefix,
+// 916 123-45-67 without it and 7 916 123-45-67 with country code but without +.
+func Normalize(number string) (string, error) {
+	digits := separators.Replace(strings.TrimSpace(number))
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		digits = digits[1:]
+	case strings.HasPrefix(digits, "00"):
+		digits = digits[2:]
+	case len(digits) == russianDigits && digits[0] == '8':
+		digits = russianCountryCode + digits[1:]
+	case len(digits) == russianDigits-1:
+		digits = russianCountryCode + digits
+	case len(digits) == russianDigits && digits[0] == '7':
+	default:
+		return "", fmt.Errorf("phone should start with country code")
+	}
+
+	for _, digit := range digits {
+		if digit < '0' || digit > '9' {
+			return "", fmt.Errorf("phone should contain only digits and separators")
+		}
+	}
+	if len(digits) < minDigits || len(digits) > maxDigits || digits[0] == '0' {
+		return "", fmt.Errorf("phone should be 7 to 15 digits long with country code")
+	}
+	if strings.HasPrefix(digits, russianCountryCode) && len(digits) != russianDigits {
+		return "", fmt.Errorf("phone with country code 7 should be 11 digits long")
+	}
+	return "+" + digits, nil
+}
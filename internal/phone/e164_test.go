@@ -0,0 +1,56 @@
+package phone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input  string
+		result string
+	}{
+		{"+79161234567", "+79161234567"},
+		{" +7 (916) 123-45-67 ", "+79161234567"},
+		{"8 916 123 45 67", "+79161234567"},
+		{"89161234567", "+79161234567"},
+		{"79161234567", "+79161234567"},
+		{"(916) 123-45-67", "+79161234567"},
+		{"0049 30 1234567", "+49301234567"},
+		{"+44 20.7946.0958", "+442079460958"},
+		{"+290 4002", "+2904002"},
+	}
+
+	for _, test := range testCases {
+		result, err := Normalize(test.input)
+
+		assert.Nil(t, err, test.input)
+		assert.Equal(t, test.result, result, test.input)
+	}
+}
+
+func TestNormalize_Error(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input  string
+		result string
+	}{
+		{"", "phone should start with country code"},
+		{"123-45-67", "phone should start with country code"},
+		{"+7 916 ABC-45-67", "phone should contain only digits and separators"},
+		{"+7993", "phone should be 7 to 15 digits long with country code"},
+		{"+0 916 123 45 67", "phone should be 7 to 15 digits long with country code"},
+		{"+1234567890123456", "phone should be 7 to 15 digits long with country code"},
+		{"+7 916 123 45 678", "phone with country code 7 should be 11 digits long"},
+	}
+
+	for _, test := range testCases {
+		_, err := Normalize(test.input)
+
+		assert.EqualError(t, err, test.result, test.input)
+	}
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const beneficiaryTableName = "beneficiary"
+
+var beneficiaryColumns = []string{
+	"uid",
+	"customeruid",
+	"type",
+	"nickname",
+	"recipientuid",
+	"phone",
+	"accountname",
+	"iban",
+	"bic",
+	"payeekey",
+	"trustedat",
+	"createdat",
+	"updatedat",
+}
+
+var preparedBeneficiaryColumns = strings.Join(beneficiaryColumns, ", ")
+
+type BeneficiaryRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewBeneficiaryRepository(pgConn *pgxpool.Pool) *BeneficiaryRepository {
+	return &BeneficiaryRepository{pgConn: pgConn}
+}
+
+func (a *BeneficiaryRepository) Create(beneficiary *domain.Beneficiary) (bool, error) {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (customeruid, payeekey) DO NOTHING;`,
+		beneficiaryTableName,
+		preparedBeneficiaryColumns,
+		getSubstitutionVerbsForColumns(beneficiaryColumns),
+	)
+	result, err := a.pgConn.Exec(
+		context.Background(),
+		query,
+		beneficiary.GeneratedID,
+		beneficiary.CustomerID,
+		beneficiary.Type,
+		beneficiary.Nickname,
+		beneficiary.RecipientID,
+		beneficiary.Phone,
+		beneficiary.AccountName,
+		beneficiary.IBAN,
+		beneficiary.BIC,
+		beneficiary.PayeeKey,
+		beneficiary.TrustedAt,
+		beneficiary.CreatedAt,
+		beneficiary.UpdatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (a *BeneficiaryRepository) FindByID(beneficiaryID string) (beneficiary *domain.Beneficiary, err error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE uid=$1;`, preparedBeneficiaryColumns, beneficiaryTableName)
+
+	beneficiary, err = scanBeneficiary(a.pgConn.QueryRow(context.Background(), query, beneficiaryID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return beneficiary, nil
+}
+
+func (a *BeneficiaryRepository) FindByCustomerID(customerID string) (beneficiaries []*domain.Beneficiary, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 ORDER BY nickname, createdat;`,
+		preparedBeneficiaryColumns,
+		beneficiaryTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		beneficiary, err := scanBeneficiary(rows)
+		if err != nil {
+			return nil, err
+		}
+		beneficiaries = append(beneficiaries, beneficiary)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return beneficiaries, nil
+}
+
+func (a *BeneficiaryRepository) FindByPayeeKey(
+	customerID string,
+	payeeKey string,
+) (beneficiary *domain.Beneficiary, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 AND payeekey=$2;`,
+		preparedBeneficiaryColumns,
+		beneficiaryTableName,
+	)
+
+	beneficiary, err = scanBeneficiary(a.pgConn.QueryRow(context.Background(), query, customerID, payeeKey))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return beneficiary, nil
+}
+
+func (a *BeneficiaryRepository) UpdateNickname(beneficiaryID string, nickname string, updatedAt time.Time) error {
+	query := fmt.Sprintf(`UPDATE %s SET nickname=$2, updatedat=$3 WHERE uid=$1;`, beneficiaryTableName)
+	_, err := a.pgConn.Exec(context.Background(), query, beneficiaryID, nickname, updatedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *BeneficiaryRepository) Delete(beneficiaryID string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE uid=$1;`, beneficiaryTableName)
+	_, err := a.pgConn.Exec(context.Background(), query, beneficiaryID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func scanBeneficiary(row pgx.Row) (*domain.Beneficiary, error) {
+	beneficiary := &domain.Beneficiary{}
+	err := row.Scan(
+		&beneficiary.GeneratedID,
+		&beneficiary.CustomerID,
+		&beneficiary.Type,
+		&beneficiary.Nickname,
+		&beneficiary.RecipientID,
+		&beneficiary.Phone,
+		&beneficiary.AccountName,
+		&beneficiary.IBAN,
+		&beneficiary.BIC,
+		&beneficiary.PayeeKey,
+		&beneficiary.TrustedAt,
+		&beneficiary.CreatedAt,
+		&beneficiary.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return beneficiary, nil
+}
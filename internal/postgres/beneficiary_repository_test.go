@@ -0,0 +1,69 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestBeneficiary_CreateDeduplicatesPayee(t *testing.T) {
+	// clean
+	_, err := PostgresConnection.Exec(
+		context.Background(),
+		`DELETE FROM beneficiary WHERE customeruid='beneficiary_customer';`,
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewBeneficiaryRepository(PostgresConnection)
+	now := time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	// act
+	beneficiary := &domain.Beneficiary{
+		GeneratedID: "beneficiary",
+		CustomerID:  "beneficiary_customer",
+		Type:        domain.BeneficiaryTypeBankAccount,
+		Nickname:    "Rent",
+		AccountName: "Bruce Wayne",
+		IBAN:        "DE89370400440532013000",
+		PayeeKey:    "bank_account:DE89370400440532013000",
+		TrustedAt:   now.Add(24 * time.Hour),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	created, err := repository.Create(beneficiary)
+	assert.NoError(t, err)
+	assert.True(t, created)
+
+	duplicate := *beneficiary
+	duplicate.GeneratedID = "beneficiary_duplicate"
+	duplicate.Nickname = "Landlord"
+	created, err = repository.Create(&duplicate)
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	err = repository.UpdateNickname("beneficiary", "Flat rent", now.Add(time.Hour))
+	assert.NoError(t, err)
+
+	// assert
+	found, err := repository.FindByPayeeKey("beneficiary_customer", "bank_account:DE89370400440532013000")
+	assert.NoError(t, err)
+	assert.Equal(t, "beneficiary", found.GeneratedID)
+	assert.Equal(t, "Flat rent", found.Nickname)
+	assert.True(t, found.TrustedAt.Equal(now.Add(24*time.Hour)))
+
+	beneficiaries, err := repository.FindByCustomerID("beneficiary_customer")
+	assert.NoError(t, err)
+	assert.Len(t, beneficiaries, 1)
+
+	err = repository.Delete("beneficiary")
+	assert.NoError(t, err)
+	found, err = repository.FindByID("beneficiary")
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+}
@@ -0,0 +1,500 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	cardTableName              = "card"
+	cardAuthorizationTableName = "card_authorization"
+)
+
+var cardColumns = []string{
+	"uid",
+	"customeruid",
+	"currency",
+	"maskedpan",
+	"panfingerprint",
+	"expirymonth",
+	"expiryyear",
+	"status",
+	"blockedmccs",
+	"pertransactionlimit",
+	"dailylimit",
+	"createdat",
+	"updatedat",
+}
+
+var preparedCardColumns = strings.Join(cardColumns, ", ")
+
+var cardAuthorizationColumns = []string{
+	"uid",
+	"carduid",
+	"customeruid",
+	"amount",
+	"currency",
+	"mcc",
+	"merchantname",
+	"status",
+	"declinereason",
+	"networkreference",
+	"riskassessmentuid",
+	"riskdecision",
+	"createdat",
+	"updatedat",
+}
+
+var preparedCardAuthorizationColumns = strings.Join(cardAuthorizationColumns, ", ")
+
+type CardRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewCardRepository(pgConn *pgxpool.Pool) *CardRepository {
+	return &CardRepository{pgConn: pgConn}
+}
+
+func (a *CardRepository) Create(card *domain.Card) (bool, error) {
+	args, err := cardArgs(card)
+	if err != nil {
+		return false, err
+	}
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (panfingerprint) DO NOTHING;`,
+		cardTableName,
+		preparedCardColumns,
+		getSubstitutionVerbsForColumns(cardColumns),
+	)
+	result, err := a.pgConn.Exec(context.Background(), query, args...)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (a *CardRepository) FindByID(cardID string) (card *domain.Card, err error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE uid=$1;`, preparedCardColumns, cardTableName)
+
+	card, err = scanCard(a.pgConn.QueryRow(context.Background(), query, cardID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+func (a *CardRepository) FindByFingerprint(fingerprint string) (card *domain.Card, err error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE panfingerprint=$1;`, preparedCardColumns, cardTableName)
+
+	card, err = scanCard(a.pgConn.QueryRow(context.Background(), query, fingerprint))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+func (a *CardRepository) FindByCustomerID(customerID string) (cards []*domain.Card, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 ORDER BY createdat DESC;`,
+		preparedCardColumns,
+		cardTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		card, err := scanCard(rows)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return cards, nil
+}
+
+func (a *CardRepository) Update(cardID string, update func(card *domain.Card) error) (card *domain.Card, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	card, err = lockCard(tx, cardID)
+	if err == pgx.ErrNoRows {
+		return nil, tx.Rollback(context.Background())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = update(card)
+	if err != nil {
+		return nil, err
+	}
+	args, err := cardArgs(card)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		cardTableName,
+		preparedCardColumns,
+		getSubstitutionVerbsForColumns(cardColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+func (a *CardRepository) Authorize(
+	cardID string,
+	now time.Time,
+	dayStart time.Time,
+	authorize func(
+		card *domain.Card,
+		balance *domain.AvailableBalance,
+		spent int64,
+		used domain.LimitUsage,
+	) (*domain.CardAuthorization, error),
+) (authorization *domain.CardAuthorization, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	// card is locked before customer, so that status change of card waits for authorization in progress
+	card, err := lockCard(tx, cardID)
+	if err == pgx.ErrNoRows {
+		return nil, tx.Rollback(context.Background())
+	}
+	if err != nil {
+		return nil, err
+	}
+	balance, err := lockedAvailableBalance(tx, card.CustomerID, card.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(
+		`SELECT COALESCE(SUM(amount), 0) FROM %s WHERE carduid=$1 AND status IN ($2, $3) AND createdat>=$4;`,
+		cardAuthorizationTableName,
+	)
+	var spent int64
+	err = tx.QueryRow(
+		context.Background(),
+		query,
+		card.GeneratedID,
+		domain.CardAuthorizationStatusApproved,
+		domain.CardAuthorizationStatusCaptured,
+		dayStart,
+	).Scan(&spent)
+	if err != nil {
+		return nil, err
+	}
+	used, err := lockedLimitUsage(tx, card.CustomerID, card.Currency, now)
+	if err != nil {
+		return nil, err
+	}
+
+	authorization, err = authorize(card, balance, spent, used)
+	if err != nil {
+		return nil, err
+	}
+	query = fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		cardAuthorizationTableName,
+		preparedCardAuthorizationColumns,
+		getSubstitutionVerbsForColumns(cardAuthorizationColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, cardAuthorizationArgs(authorization)...)
+	if err != nil {
+		return nil, err
+	}
+	if authorization.Status == domain.CardAuthorizationStatusApproved {
+		err = placeHold(
+			tx,
+			authorization.GeneratedID,
+			authorization.CustomerID,
+			authorization.Amount,
+			authorization.Currency,
+			fmt.Sprintf("card-authorization:%s", authorization.GeneratedID),
+			authorization.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		err = addLimitUsage(tx, card.CustomerID, card.Currency, authorization.Amount, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return authorization, nil
+}
+
+func (a *CardRepository) FindAuthorizationByID(
+	authorizationID string,
+) (authorization *domain.CardAuthorization, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedCardAuthorizationColumns,
+		cardAuthorizationTableName,
+	)
+
+	authorization, err = scanCardAuthorization(a.pgConn.QueryRow(context.Background(), query, authorizationID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return authorization, nil
+}
+
+func (a *CardRepository) FindAuthorizationByNetworkReference(
+	reference string,
+) (authorization *domain.CardAuthorization, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE networkreference=$1;`,
+		preparedCardAuthorizationColumns,
+		cardAuthorizationTableName,
+	)
+
+	authorization, err = scanCardAuthorization(a.pgConn.QueryRow(context.Background(), query, reference))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return authorization, nil
+}
+
+func (a *CardRepository) FindAuthorizationsByCardID(
+	cardID string,
+) (authorizations []*domain.CardAuthorization, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE carduid=$1 ORDER BY createdat DESC;`,
+		preparedCardAuthorizationColumns,
+		cardAuthorizationTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, cardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		authorization, err := scanCardAuthorization(rows)
+		if err != nil {
+			return nil, err
+		}
+		authorizations = append(authorizations, authorization)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return authorizations, nil
+}
+
+func (a *CardRepository) UpdateAuthorization(
+	authorizationID string,
+	update func(authorization *domain.CardAuthorization) ([]*domain.Posting, error),
+) (authorization *domain.CardAuthorization, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1 FOR UPDATE;`,
+		preparedCardAuthorizationColumns,
+		cardAuthorizationTableName,
+	)
+	authorization, err = scanCardAuthorization(tx.QueryRow(context.Background(), query, authorizationID))
+	if err == pgx.ErrNoRows {
+		return nil, tx.Rollback(context.Background())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	postings, err := update(authorization)
+	if err != nil {
+		return nil, err
+	}
+	query = fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		cardAuthorizationTableName,
+		preparedCardAuthorizationColumns,
+		getSubstitutionVerbsForColumns(cardAuthorizationColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, cardAuthorizationArgs(authorization)...)
+	if err != nil {
+		return nil, err
+	}
+	if authorization.Status != domain.CardAuthorizationStatusApproved {
+		err = releaseHold(tx, authorization.GeneratedID, authorization.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = createForcedPostings(tx, postings)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return authorization, nil
+}
+
+func lockCard(tx pgx.Tx, cardID string) (*domain.Card, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE uid=$1 FOR UPDATE;`, preparedCardColumns, cardTableName)
+	return scanCard(tx.QueryRow(context.Background(), query, cardID))
+}
+
+func cardArgs(card *domain.Card) ([]interface{}, error) {
+	blockedMCCs := card.Controls.BlockedMCCs
+	if blockedMCCs == nil {
+		blockedMCCs = []string{}
+	}
+	mccs, err := json.Marshal(blockedMCCs)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
+		card.GeneratedID,
+		card.CustomerID,
+		card.Currency,
+		card.MaskedPAN,
+		card.PANFingerprint,
+		card.ExpiryMonth,
+		card.ExpiryYear,
+		card.Status,
+		string(mccs),
+		card.Controls.PerTransaction,
+		card.Controls.Daily,
+		card.CreatedAt,
+		card.UpdatedAt,
+	}, nil
+}
+
+func scanCard(row pgx.Row) (*domain.Card, error) {
+	card := &domain.Card{}
+	var mccs []byte
+	err := row.Scan(
+		&card.GeneratedID,
+		&card.CustomerID,
+		&card.Currency,
+		&card.MaskedPAN,
+		&card.PANFingerprint,
+		&card.ExpiryMonth,
+		&card.ExpiryYear,
+		&card.Status,
+		&mccs,
+		&card.Controls.PerTransaction,
+		&card.Controls.Daily,
+		&card.CreatedAt,
+		&card.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(mccs, &card.Controls.BlockedMCCs)
+	if err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+func cardAuthorizationArgs(authorization *domain.CardAuthorization) []interface{} {
+	return []interface{}{
+		authorization.GeneratedID,
+		authorization.CardID,
+		authorization.CustomerID,
+		authorization.Amount,
+		authorization.Currency,
+		authorization.MCC,
+		authorization.MerchantName,
+		authorization.Status,
+		authorization.DeclineReason,
+		authorization.NetworkReference,
+		authorization.RiskAssessmentID,
+		authorization.RiskDecision,
+		authorization.CreatedAt,
+		authorization.UpdatedAt,
+	}
+}
+
+func scanCardAuthorization(row pgx.Row) (*domain.CardAuthorization, error) {
+	authorization := &domain.CardAuthorization{}
+	err := row.Scan(
+		&authorization.GeneratedID,
+		&authorization.CardID,
+		&authorization.CustomerID,
+		&authorization.Amount,
+		&authorization.Currency,
+		&authorization.MCC,
+		&authorization.MerchantName,
+		&authorization.Status,
+		&authorization.DeclineReason,
+		&authorization.NetworkReference,
+		&authorization.RiskAssessmentID,
+		&authorization.RiskDecision,
+		&authorization.CreatedAt,
+		&authorization.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return authorization, nil
+}
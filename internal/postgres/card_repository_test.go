@@ -0,0 +1,167 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestCard_AuthorizeCaptureAndReverse(t *testing.T) {
+	// clean
+	for _, query := range []string{
+		`DELETE FROM card_authorization WHERE customeruid='card_customer';`,
+		`DELETE FROM card WHERE customeruid='card_customer';`,
+		`DELETE FROM hold WHERE customeruid='card_customer';`,
+		`DELETE FROM limit_usage WHERE customeruid='card_customer';`,
+		`DELETE FROM posting WHERE customeruid='card_customer';`,
+		`DELETE FROM customer WHERE uid='card_customer';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewCardRepository(PostgresConnection)
+	now := time.Date(2020, 8, 18, 12, 0, 0, 0, time.UTC)
+
+	// arrange
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "card_customer",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Barbara",
+		LastName:    "Gordon",
+		Phone:       "+79930000005",
+		CreatedAt:   now,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = NewPostingRepository(PostgresConnection).Create(&domain.Posting{
+		GeneratedID: "card_top_up",
+		CustomerID:  "card_customer",
+		Amount:      50000,
+		Currency:    "RUB",
+		PostedAt:    now.Add(-time.Hour),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	card := &domain.Card{
+		GeneratedID:    "card",
+		CustomerID:     "card_customer",
+		Currency:       "RUB",
+		MaskedPAN:      "220012******1234",
+		PANFingerprint: "card_fingerprint",
+		ExpiryMonth:    8,
+		ExpiryYear:     2023,
+		Status:         domain.CardStatusActive,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	created, err := repository.Create(card)
+	assert.NoError(t, err)
+	assert.True(t, created)
+	duplicate := *card
+	duplicate.GeneratedID = "card_duplicate"
+	created, err = repository.Create(&duplicate)
+	assert.NoError(t, err)
+	assert.False(t, created)
+
+	// act
+	updated, err := repository.Update("card", func(card *domain.Card) error {
+		card.Controls = domain.CardControls{BlockedMCCs: []string{"7995"}, Daily: 40000}
+		return nil
+	})
+	assert.NoError(t, err)
+	authorize := func(authorizationID string, amount int64) (*domain.CardAuthorization, error) {
+		return repository.Authorize(
+			"card",
+			now,
+			now.Add(-12*time.Hour),
+			func(
+				card *domain.Card,
+				balance *domain.AvailableBalance,
+				spent int64,
+				used domain.LimitUsage,
+			) (*domain.CardAuthorization, error) {
+				authorization := &domain.CardAuthorization{
+					GeneratedID: authorizationID,
+					CardID:      card.GeneratedID,
+					CustomerID:  card.CustomerID,
+					Amount:      amount,
+					Currency:    card.Currency,
+					MCC:         "5411",
+					Status:      domain.CardAuthorizationStatusApproved,
+					CreatedAt:   now,
+					UpdatedAt:   now,
+				}
+				if authorizationID == "card_authorization_first" {
+					authorization.NetworkReference = "00000012345:000001:0818120000"
+				}
+				if spent+amount > card.Controls.Daily || amount > balance.Available() {
+					authorization.Status = domain.CardAuthorizationStatusDeclined
+					authorization.DeclineReason = domain.CardDeclineReasonDailyLimit
+				}
+				return authorization, nil
+			},
+		)
+	}
+	first, firstErr := authorize("card_authorization_first", 30000)
+	second, secondErr := authorize("card_authorization_second", 20000)
+	balanceAfterAuthorize, _ := NewCreditLineRepository(PostgresConnection).FindAvailableBalance("card_customer", "RUB")
+	usageAfterAuthorize, _ := NewLimitRepository(PostgresConnection).FindUsage("card_customer", "RUB", now)
+	captured, captureErr := repository.UpdateAuthorization(
+		"card_authorization_first",
+		func(authorization *domain.CardAuthorization) ([]*domain.Posting, error) {
+			authorization.Status = domain.CardAuthorizationStatusCaptured
+			return []*domain.Posting{
+				{
+					GeneratedID: "card_capture",
+					CustomerID:  authorization.CustomerID,
+					Amount:      -authorization.Amount,
+					Currency:    authorization.Currency,
+					PostedAt:    now,
+				},
+				{
+					GeneratedID: "card_capture_settlement",
+					CustomerID:  domain.LedgerAccountCardSettlement,
+					Amount:      authorization.Amount,
+					Currency:    authorization.Currency,
+					PostedAt:    now,
+				},
+			}, nil
+		},
+	)
+	balanceAfterCapture, _ := NewCreditLineRepository(PostgresConnection).FindAvailableBalance("card_customer", "RUB")
+	authorizations, _ := repository.FindAuthorizationsByCardID("card")
+	byFingerprint, _ := repository.FindByFingerprint("card_fingerprint")
+	byReference, _ := repository.FindAuthorizationByNetworkReference("00000012345:000001:0818120000")
+	missing, missingErr := repository.UpdateAuthorization(
+		"missing",
+		func(*domain.CardAuthorization) ([]*domain.Posting, error) { return nil, nil },
+	)
+
+	// assert
+	assert.Equal(t, []string{"7995"}, updated.Controls.BlockedMCCs)
+	assert.NoError(t, firstErr)
+	assert.Equal(t, domain.CardAuthorizationStatusApproved, first.Status)
+	assert.NoError(t, secondErr)
+	assert.Equal(t, domain.CardAuthorizationStatusDeclined, second.Status)
+	assert.Equal(t, &domain.AvailableBalance{Balance: 50000, Holds: 30000}, balanceAfterAuthorize)
+	assert.Equal(t, domain.LimitUsage{DailyAmount: 30000, MonthlyAmount: 30000, DailyCount: 1, MonthlyCount: 1},
+		usageAfterAuthorize)
+	assert.NoError(t, captureErr)
+	assert.Equal(t, domain.CardAuthorizationStatusCaptured, captured.Status)
+	assert.Equal(t, &domain.AvailableBalance{Balance: 20000}, balanceAfterCapture)
+	assert.Len(t, authorizations, 2)
+	assert.Equal(t, "card", byFingerprint.GeneratedID)
+	assert.Equal(t, []string{"7995"}, byFingerprint.Controls.BlockedMCCs)
+	assert.Equal(t, "card_authorization_first", byReference.GeneratedID)
+	assert.NoError(t, missingErr)
+	assert.Nil(t, missing)
+}
@@ -3,6 +3,7 @@ package postgres
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // creates sequence ($1, $2, $3, ...) base on columns length
@@ -13,3 +14,11 @@ func getSubstitutionVerbsForColumns(columns []string) string {
 	}
 	return strings.Join(verbs, ", ")
 }
+
+// stores zero time as NULL
+func nullableTime(value time.Time) interface{} {
+	if value.IsZero() {
+		return nil
+	}
+	return value
+}
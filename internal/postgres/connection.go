@@ -0,0 +1,30 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/log/zapadapter"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/config"
+	"go.uber.org/zap"
+)
+
+func MustConnect(config config.Config, logger *zap.Logger) *pgxpool.Pool {
+	pgxCfg, _ := pgx.ParseConfig(config.PostgresConfig.HostString)
+	pgxCfg.Logger = zapadapter.NewLogger(logger)
+	pgxCfg.LogLevel = config.PostgresConfig.LogLevel
+	pgxCfg.PreferSimpleProtocol = true
+
+	pgxPoolCfg, _ := pgxpool.ParseConfig("")
+	pgxPoolCfg.ConnConfig = pgxCfg
+	pgxPoolCfg.MaxConns = config.PostgresConfig.MaxConnections
+	pgxPoolCfg.MinConns = config.PostgresConfig.MinConnections
+
+	connection, err := pgxpool.ConnectConfig(context.Background(), pgxPoolCfg)
+	if err != nil {
+		logger.Fatal(fmt.Sprintf("unable to connect to database: %s", err.Error()))
+	}
+	return connection
+}
@@ -0,0 +1,265 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const creditLineTableName = "credit_line"
+
+var creditLineColumns = []string{
+	"uid",
+	"customeruid",
+	"currency",
+	"limitamount",
+	"annualrate",
+	"accruedinterest",
+	"chargedinterest",
+	"charges",
+	"accrueduntil",
+	"createdat",
+	"updatedat",
+}
+
+var preparedCreditLineColumns = strings.Join(creditLineColumns, ", ")
+
+// creditLineTermsColumns are updated when limit of existing credit line is approved again
+var creditLineTermsColumns = []string{
+	"limitamount",
+	"annualrate",
+	"updatedat",
+}
+
+type CreditLineRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewCreditLineRepository(pgConn *pgxpool.Pool) *CreditLineRepository {
+	return &CreditLineRepository{pgConn: pgConn}
+}
+
+func (a *CreditLineRepository) Save(line *domain.CreditLine) (err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	err = lockCustomer(tx, line.CustomerID)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (uid) DO UPDATE SET (%s) = ROW (EXCLUDED.%s);`,
+		creditLineTableName,
+		preparedCreditLineColumns,
+		getSubstitutionVerbsForColumns(creditLineColumns),
+		strings.Join(creditLineTermsColumns, ", "),
+		strings.Join(creditLineTermsColumns, ", EXCLUDED."),
+	)
+	_, err = tx.Exec(context.Background(), query, creditLineArgs(line)...)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(context.Background())
+}
+
+func (a *CreditLineRepository) Find(customerID string, currency string) (line *domain.CreditLine, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 AND currency=$2;`,
+		preparedCreditLineColumns,
+		creditLineTableName,
+	)
+
+	line, err = scanCreditLine(a.pgConn.QueryRow(context.Background(), query, customerID, currency))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
+
+func (a *CreditLineRepository) FindByCustomerID(customerID string) (lines []*domain.CreditLine, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 ORDER BY currency;`,
+		preparedCreditLineColumns,
+		creditLineTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanCreditLines(rows)
+}
+
+func (a *CreditLineRepository) FindAvailableBalance(
+	customerID string,
+	currency string,
+) (balance *domain.AvailableBalance, err error) {
+	return findAvailableBalance(a.pgConn, customerID, currency)
+}
+
+func (a *CreditLineRepository) ClaimDue(
+	today time.Time,
+	limit int,
+	accrue func(line *domain.CreditLine, closing []*domain.ClosingBalance) ([]*domain.Posting, error),
+) (claimed int, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	// customer of credit line is locked as well, so that interest debit is serialized with other debits
+	// of customer. Customer locked by a debit in progress is skipped till the next claim.
+	query := fmt.Sprintf(
+		`SELECT %s.%s FROM %s JOIN %s ON %s.uid=%s.customeruid
+		WHERE accrueduntil<$1 ORDER BY accrueduntil LIMIT $2 FOR UPDATE OF %s, %s SKIP LOCKED;`,
+		creditLineTableName,
+		strings.Join(creditLineColumns, ", "+creditLineTableName+"."),
+		creditLineTableName,
+		customerTableName,
+		customerTableName,
+		creditLineTableName,
+		creditLineTableName,
+		customerTableName,
+	)
+	rows, err := tx.Query(context.Background(), query, today, limit)
+	if err != nil {
+		return 0, err
+	}
+	lines, err := scanCreditLines(rows)
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	query = fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		creditLineTableName,
+		preparedCreditLineColumns,
+		getSubstitutionVerbsForColumns(creditLineColumns),
+	)
+	for _, line := range lines {
+		var closing []*domain.ClosingBalance
+		closing, err = findClosingBalances(tx, line, today)
+		if err != nil {
+			return 0, err
+		}
+		var postings []*domain.Posting
+		postings, err = accrue(line, closing)
+		if err != nil {
+			return 0, err
+		}
+		_, err = tx.Exec(context.Background(), query, creditLineArgs(line)...)
+		if err != nil {
+			return 0, err
+		}
+		err = createPostings(tx, postings)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return len(lines), nil
+}
+
+// findClosingBalances sums postings of customer of credit line posted before the end of every day
+// since credit line is accrued until till today
+func findClosingBalances(tx pgx.Tx, line *domain.CreditLine, today time.Time) ([]*domain.ClosingBalance, error) {
+	query := fmt.Sprintf(
+		`SELECT COALESCE(SUM(amount), 0) FROM %s WHERE customeruid=$1 AND currency=$2 AND postedat<$3;`,
+		postingTableName,
+	)
+	var closing []*domain.ClosingBalance
+	for day := line.AccruedUntil; day.Before(today); day = day.AddDate(0, 0, 1) {
+		balance := &domain.ClosingBalance{Day: day}
+		err := tx.QueryRow(
+			context.Background(),
+			query,
+			line.CustomerID,
+			line.Currency,
+			day.AddDate(0, 0, 1),
+		).Scan(&balance.Balance)
+		if err != nil {
+			return nil, err
+		}
+		closing = append(closing, balance)
+	}
+	return closing, nil
+}
+
+func creditLineArgs(line *domain.CreditLine) []interface{} {
+	return []interface{}{
+		line.GeneratedID,
+		line.CustomerID,
+		line.Currency,
+		line.Limit,
+		line.AnnualRate,
+		line.AccruedInterest,
+		line.ChargedInterest,
+		line.Charges,
+		line.AccruedUntil,
+		line.CreatedAt,
+		line.UpdatedAt,
+	}
+}
+
+func scanCreditLines(rows pgx.Rows) ([]*domain.CreditLine, error) {
+	var lines []*domain.CreditLine
+	for rows.Next() {
+		line, err := scanCreditLine(rows)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, line)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return lines, nil
+}
+
+func scanCreditLine(row pgx.Row) (*domain.CreditLine, error) {
+	line := &domain.CreditLine{}
+	err := row.Scan(
+		&line.GeneratedID,
+		&line.CustomerID,
+		&line.Currency,
+		&line.Limit,
+		&line.AnnualRate,
+		&line.AccruedInterest,
+		&line.ChargedInterest,
+		&line.Charges,
+		&line.AccruedUntil,
+		&line.CreatedAt,
+		&line.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return line, nil
+}
@@ -0,0 +1,159 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestCreditLine_OverdraftAndClaimDue(t *testing.T) {
+	// clean
+	for _, query := range []string{
+		`DELETE FROM credit_line WHERE customeruid='credit_line_customer';`,
+		`DELETE FROM hold WHERE customeruid='credit_line_customer';`,
+		`DELETE FROM deposit WHERE customeruid='credit_line_customer';`,
+		`DELETE FROM posting WHERE customeruid='credit_line_customer';`,
+		`DELETE FROM customer WHERE uid='credit_line_customer';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewCreditLineRepository(PostgresConnection)
+	depositRepository := NewDepositRepository(PostgresConnection)
+	today := time.Date(2020, 8, 18, 0, 0, 0, 0, time.UTC)
+
+	// arrange
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "credit_line_customer",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Selina",
+		LastName:    "Kyle",
+		Phone:       "+79930000004",
+		CreatedAt:   today,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = NewPostingRepository(PostgresConnection).Create(&domain.Posting{
+		GeneratedID: "credit_line_top_up",
+		CustomerID:  "credit_line_customer",
+		Amount:      50000,
+		Currency:    "RUB",
+		PostedAt:    today.Add(-48 * time.Hour),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = PostgresConnection.Exec(
+		context.Background(),
+		`INSERT INTO hold (uid, customeruid, amount, currency, status) VALUES ($1, $2, $3, $4, $5);`,
+		"credit_line_hold", "credit_line_customer", 10000, "RUB", holdStatusActive,
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	line := &domain.CreditLine{
+		GeneratedID:  "credit_line",
+		CustomerID:   "credit_line_customer",
+		Currency:     "RUB",
+		Limit:        100000,
+		AnnualRate:   "36.5",
+		AccruedUntil: today.Add(-48 * time.Hour),
+		CreatedAt:    today,
+		UpdatedAt:    today,
+	}
+	err = repository.Save(line)
+	if err != nil {
+		t.Error(err)
+	}
+	deposit := func(depositID string, principal int64) (bool, error) {
+		return depositRepository.Create(
+			&domain.Deposit{
+				GeneratedID:  depositID,
+				ProductID:    "deposit_product",
+				CustomerID:   "credit_line_customer",
+				Currency:     "RUB",
+				Principal:    principal,
+				Status:       domain.DepositStatusOpen,
+				AccrualStart: today,
+				AccruedUntil: today,
+				OpenedAt:     today,
+				UpdatedAt:    today,
+			},
+			[]*domain.Posting{{
+				GeneratedID: depositID + "_debit",
+				CustomerID:  "credit_line_customer",
+				Amount:      -principal,
+				Currency:    "RUB",
+				Reference:   "deposit:" + depositID,
+				PostedAt:    today.Add(-24 * time.Hour),
+			}},
+		)
+	}
+
+	// act
+	available, err := repository.FindAvailableBalance("credit_line_customer", "RUB")
+	if err != nil {
+		t.Error(err)
+	}
+	createdOverLimit, err := deposit("credit_line_deposit_over_limit", 150000)
+	if err != nil {
+		t.Error(err)
+	}
+	createdWithinLimit, err := deposit("credit_line_deposit", 120000)
+	if err != nil {
+		t.Error(err)
+	}
+	var closing []*domain.ClosingBalance
+	accrue := func(line *domain.CreditLine, balances []*domain.ClosingBalance) ([]*domain.Posting, error) {
+		closing = balances
+		line.AccruedInterest += 70
+		line.AccruedUntil = today
+		return nil, nil
+	}
+	claimed, err := repository.ClaimDue(today, 10, accrue)
+	if err != nil {
+		t.Error(err)
+	}
+	claimedAgain, err := repository.ClaimDue(today, 10, accrue)
+	if err != nil {
+		t.Error(err)
+	}
+	line.Limit = 200000
+	line.AccruedInterest = 0
+	err = repository.Save(line)
+	if err != nil {
+		t.Error(err)
+	}
+	found, err := repository.Find("credit_line_customer", "RUB")
+	if err != nil {
+		t.Error(err)
+	}
+	lines, err := repository.FindByCustomerID("credit_line_customer")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Equal(t, int64(140000), available.Available())
+	assert.False(t, createdOverLimit)
+	assert.True(t, createdWithinLimit)
+	assert.Equal(t, 1, claimed)
+	assert.Equal(t, 0, claimedAgain)
+	if assert.Len(t, closing, 2) {
+		assert.Equal(t, int64(50000), closing[0].Balance)
+		assert.Equal(t, int64(-70000), closing[1].Balance)
+	}
+	// approving limit again keeps interest accrued
+	assert.Equal(t, int64(200000), found.Limit)
+	assert.Equal(t, int64(70), found.AccruedInterest)
+	assert.True(t, today.Equal(found.AccruedUntil))
+	assert.Len(t, lines, 1)
+}
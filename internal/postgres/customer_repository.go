@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -10,7 +11,7 @@ import (
 	"github.com/yaroslavnayug/go-payment-system/internal/domain"
 )
 
-const tableName = "customer"
+const customerTableName = "customer"
 
 var customerColumns = []string{
 	"uid",
@@ -28,6 +29,15 @@ var customerColumns = []string{
 	"passportissuer",
 	"birthdate",
 	"birthplace",
+	"type",
+	"companyname",
+	"inn",
+	"kpp",
+	"ogrn",
+	"beneficialowners",
+	"representatives",
+	"status",
+	"createdat",
 }
 
 var preparedCustomerColumns = strings.Join(customerColumns, ", ")
@@ -43,30 +53,15 @@ func NewCustomerRepository(pgConn *pgxpool.Pool) *CustomerRepository {
 func (a *CustomerRepository) Create(customer *domain.Customer) error {
 	query := fmt.Sprintf(
 		`INSERT INTO %s (%s) VALUES (%s);`,
-		tableName,
+		customerTableName,
 		preparedCustomerColumns,
 		getSubstitutionVerbsForColumns(customerColumns),
 	)
-	_, err := a.pgConn.Exec(
-		context.Background(),
-		query,
-		customer.GeneratedID,
-		customer.FirstName,
-		customer.LastName,
-		customer.Email,
-		customer.Phone,
-		customer.Address.Country,
-		customer.Address.Region,
-		customer.Address.City,
-		customer.Address.Street,
-		customer.Address.Building,
-		customer.Passport.Number,
-		customer.Passport.IssueDate,
-		customer.Passport.Issuer,
-		customer.Passport.BirthDate,
-		customer.Passport.BirthPlace,
-	)
-
+	args, err := customerArgs(customer)
+	if err != nil {
+		return err
+	}
+	_, err = a.pgConn.Exec(context.Background(), query, args...)
 	if err != nil {
 		return err
 	}
@@ -77,32 +72,15 @@ func (a *CustomerRepository) FindByID(customerID string) (customer *domain.Custo
 	query := fmt.Sprintf(
 		`SELECT %s FROM %s WHERE uid=$1;`,
 		preparedCustomerColumns,
-		tableName,
+		customerTableName,
 	)
 
-	customer = &domain.Customer{}
 	queryRow := a.pgConn.QueryRow(
 		context.Background(),
 		query,
 		customerID,
 	)
-	err = queryRow.Scan(
-		&customer.GeneratedID,
-		&customer.FirstName,
-		&customer.LastName,
-		&customer.Email,
-		&customer.Phone,
-		&customer.Address.Country,
-		&customer.Address.Region,
-		&customer.Address.City,
-		&customer.Address.Street,
-		&customer.Address.Building,
-		&customer.Passport.Number,
-		&customer.Passport.IssueDate,
-		&customer.Passport.Issuer,
-		&customer.Passport.BirthDate,
-		&customer.Passport.BirthPlace,
-	)
+	customer, err = scanCustomer(queryRow)
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
@@ -116,54 +94,175 @@ func (a *CustomerRepository) FindByPassportNumber(passportNumber string) (custom
 	query := fmt.Sprintf(
 		`SELECT %s FROM %s WHERE	passportnumber=$1;`,
 		preparedCustomerColumns,
-		tableName,
+		customerTableName,
 	)
 
-	customer = &domain.Customer{}
 	queryRow := a.pgConn.QueryRow(
 		context.Background(),
 		query,
 		passportNumber,
 	)
-	err = queryRow.Scan(
-		&customer.GeneratedID,
-		&customer.FirstName,
-		&customer.LastName,
-		&customer.Email,
-		&customer.Phone,
-		&customer.Address.Country,
-		&customer.Address.Region,
-		&customer.Address.City,
-		&customer.Address.Street,
-		&customer.Address.Building,
-		&customer.Passport.Number,
-		&customer.Passport.IssueDate,
-		&customer.Passport.Issuer,
-		&customer.Passport.BirthDate,
-		&customer.Passport.BirthPlace,
+	customer, err = scanCustomer(queryRow)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+func (a *CustomerRepository) FindByOGRN(ogrn string) (customer *domain.Customer, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE ogrn=$1 AND type=$2;`,
+		preparedCustomerColumns,
+		customerTableName,
 	)
 
+	queryRow := a.pgConn.QueryRow(context.Background(), query, ogrn, domain.CustomerTypeLegalEntity)
+	customer, err = scanCustomer(queryRow)
 	if err == pgx.ErrNoRows {
 		return nil, nil
 	}
+	if err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
 
+func (a *CustomerRepository) FindByPhone(phone string) (customer *domain.Customer, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE phone=$1;`,
+		preparedCustomerColumns,
+		customerTableName,
+	)
+
+	customer, err = scanCustomer(a.pgConn.QueryRow(context.Background(), query, phone))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 	return customer, nil
 }
 
+func (a *CustomerRepository) FindByStatus(status domain.CustomerStatus) (customers []*domain.Customer, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE status=$1;`,
+		preparedCustomerColumns,
+		customerTableName,
+	)
+
+	rows, err := a.pgConn.Query(
+		context.Background(),
+		query,
+		status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return nil, err
+		}
+		customers = append(customers, customer)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return customers, nil
+}
+
 func (a *CustomerRepository) Update(customer *domain.Customer) error {
 	query := fmt.Sprintf(
-		`UPDATE %s SET (%s) = ROW (%s) WHERE uid='%s';`,
-		tableName,
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		customerTableName,
 		preparedCustomerColumns,
 		getSubstitutionVerbsForColumns(customerColumns),
-		customer.GeneratedID,
+	)
+	args, err := customerArgs(customer)
+	if err != nil {
+		return err
+	}
+	_, err = a.pgConn.Exec(context.Background(), query, args...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *CustomerRepository) UpdatePhone(customerID string, phone string) error {
+	query := fmt.Sprintf(
+		`UPDATE %s SET phone=$2 WHERE uid=$1;`,
+		customerTableName,
+	)
+	_, err := a.pgConn.Exec(context.Background(), query, customerID, phone)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *CustomerRepository) Delete(customerID string) error {
+	query := fmt.Sprintf(
+		`DELETE FROM	%s WHERE uid = $1;`,
+		customerTableName,
 	)
 	_, err := a.pgConn.Exec(
 		context.Background(),
 		query,
+		customerID,
+	)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// beneficialOwnerRow and representativeRow are kept in jsonb columns of legal entity customer
+type beneficialOwnerRow struct {
+	CustomerID string `json:"customer_id"`
+	Share      int    `json:"share"`
+}
+
+type representativeRow struct {
+	CustomerID string `json:"customer_id"`
+	Position   string `json:"position"`
+}
+
+func customerArgs(customer *domain.Customer) ([]interface{}, error) {
+	ownerRows := make([]beneficialOwnerRow, 0, len(customer.Company.BeneficialOwners))
+	for _, owner := range customer.Company.BeneficialOwners {
+		ownerRows = append(ownerRows, beneficialOwnerRow{CustomerID: owner.CustomerID, Share: owner.Share})
+	}
+	owners, err := json.Marshal(ownerRows)
+	if err != nil {
+		return nil, err
+	}
+	representativeRows := make([]representativeRow, 0, len(customer.Company.Representatives))
+	for _, representative := range customer.Company.Representatives {
+		representativeRows = append(representativeRows, representativeRow{
+			CustomerID: representative.CustomerID,
+			Position:   representative.Position,
+		})
+	}
+	representatives, err := json.Marshal(representativeRows)
+	if err != nil {
+		return nil, err
+	}
+	customerType := customer.Type
+	if customerType == "" {
+		customerType = domain.CustomerTypeIndividual
+	}
+
+	return []interface{}{
 		customer.GeneratedID,
 		customer.FirstName,
 		customer.LastName,
@@ -179,27 +278,72 @@ func (a *CustomerRepository) Update(customer *domain.Customer) error {
 		customer.Passport.Issuer,
 		customer.Passport.BirthDate,
 		customer.Passport.BirthPlace,
-	)
-
-	if err != nil {
-		return err
-	}
-	return nil
+		customerType,
+		customer.Company.Name,
+		customer.Company.INN,
+		customer.Company.KPP,
+		customer.Company.OGRN,
+		string(owners),
+		string(representatives),
+		customer.Status,
+		customer.CreatedAt,
+	}, nil
 }
 
-func (a *CustomerRepository) Delete(customerID string) error {
-	query := fmt.Sprintf(
-		`DELETE FROM	%s WHERE uid = $1;`,
-		tableName,
-	)
-	_, err := a.pgConn.Exec(
-		context.Background(),
-		query,
-		customerID,
+func scanCustomer(row pgx.Row) (*domain.Customer, error) {
+	customer := &domain.Customer{}
+	var owners, representatives []byte
+	err := row.Scan(
+		&customer.GeneratedID,
+		&customer.FirstName,
+		&customer.LastName,
+		&customer.Email,
+		&customer.Phone,
+		&customer.Address.Country,
+		&customer.Address.Region,
+		&customer.Address.City,
+		&customer.Address.Street,
+		&customer.Address.Building,
+		&customer.Passport.Number,
+		&customer.Passport.IssueDate,
+		&customer.Passport.Issuer,
+		&customer.Passport.BirthDate,
+		&customer.Passport.BirthPlace,
+		&customer.Type,
+		&customer.Company.Name,
+		&customer.Company.INN,
+		&customer.Company.KPP,
+		&customer.Company.OGRN,
+		&owners,
+		&representatives,
+		&customer.Status,
+		&customer.CreatedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
 
+	var ownerRows []beneficialOwnerRow
+	err = json.Unmarshal(owners, &ownerRows)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	for _, owner := range ownerRows {
+		customer.Company.BeneficialOwners = append(customer.Company.BeneficialOwners, domain.BeneficialOwner{
+			CustomerID: owner.CustomerID,
+			Share:      owner.Share,
+		})
+	}
+	var representativeRows []representativeRow
+	err = json.Unmarshal(representatives, &representativeRows)
+	if err != nil {
+		return nil, err
+	}
+	for _, representative := range representativeRows {
+		customer.Company.Representatives = append(customer.Company.Representatives, domain.Representative{
+			CustomerID: representative.CustomerID,
+			Position:   representative.Position,
+		})
+	}
+	return customer, nil
 }
@@ -70,6 +70,8 @@ func TestCreate_Find_Update_Delete(t *testing.T) {
 	birthDate, _ := time.Parse(domain.DateFormat, "01-01-2020")
 	customer := &domain.Customer{
 		GeneratedID: "foobar123",
+		Status:      domain.CustomerStatusActive,
+		CreatedAt:   time.Now().Truncate(time.Second),
 		FirstName:   "Bruce",
 		LastName:    "Wayne",
 		Email:       "goo@gmail.com",
@@ -102,6 +104,8 @@ func TestCreate_Find_Update_Delete(t *testing.T) {
 		t.Error(err)
 	}
 	assert.Equal(t, customer.GeneratedID, dbCustomer.GeneratedID)
+	assert.Equal(t, customer.Status, dbCustomer.Status)
+	assert.True(t, customer.CreatedAt.Equal(dbCustomer.CreatedAt))
 	assert.Equal(t, customer.FirstName, dbCustomer.FirstName)
 	assert.Equal(t, customer.LastName, dbCustomer.LastName)
 	assert.Equal(t, customer.Email, dbCustomer.Email)
@@ -122,6 +126,8 @@ func TestCreate_Find_Update_Delete(t *testing.T) {
 	birthDate, _ = time.Parse(domain.DateFormat, "01-01-2021")
 	customer = &domain.Customer{
 		GeneratedID: "foobar123",
+		Status:      domain.CustomerStatusPendingReview,
+		CreatedAt:   time.Now().Truncate(time.Second),
 		FirstName:   "Bruce_new",
 		LastName:    "Wayne_new",
 		Email:       "goo_new@gmail.com",
@@ -167,6 +173,18 @@ func TestCreate_Find_Update_Delete(t *testing.T) {
 	assert.Equal(t, customer.Passport.BirthPlace, updatedCustomer.Passport.BirthPlace)
 	assert.Equal(t, customer.Passport.IssueDate, updatedCustomer.Passport.IssueDate)
 	assert.Equal(t, customer.Passport.Issuer, updatedCustomer.Passport.Issuer)
+	assert.Equal(t, customer.Status, updatedCustomer.Status)
+
+	// assert Update via FindByStatus
+	pendingCustomers, err := Repository.FindByStatus(domain.CustomerStatusPendingReview)
+	if err != nil {
+		t.Error(err)
+	}
+	var pendingCustomerIDs []string
+	for _, pendingCustomer := range pendingCustomers {
+		pendingCustomerIDs = append(pendingCustomerIDs, pendingCustomer.GeneratedID)
+	}
+	assert.Contains(t, pendingCustomerIDs, customer.GeneratedID)
 
 	// act Delete
 	err = Repository.Delete(updatedCustomer.GeneratedID)
@@ -181,3 +199,61 @@ func TestCreate_Find_Update_Delete(t *testing.T) {
 	}
 	assert.Nil(t, customer)
 }
+
+func TestCreate_FindByOGRN_LegalEntity(t *testing.T) {
+	t.Parallel()
+
+	// clean
+	query := `DELETE FROM customer WHERE uid = $1;`
+	_, err := PostgresConnection.Exec(context.Background(), query, "legalentity123")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// arrange
+	customer := &domain.Customer{
+		GeneratedID: "legalentity123",
+		Type:        domain.CustomerTypeLegalEntity,
+		Status:      domain.CustomerStatusActive,
+		CreatedAt:   time.Now().Truncate(time.Second),
+		Email:       "office@wayne.com",
+		Phone:       "+79930000006",
+		Address: domain.Address{
+			Country:  "Russia",
+			Region:   "Moscow",
+			City:     "Moscow",
+			Street:   "Tverskaya",
+			Building: "1",
+		},
+		Company: domain.Company{
+			Name:             "Wayne Enterprises LLC",
+			INN:              "7707083893",
+			KPP:              "773601001",
+			OGRN:             "1027700132195",
+			BeneficialOwners: []domain.BeneficialOwner{{CustomerID: "foobar123", Share: 7550}},
+			Representatives:  []domain.Representative{{CustomerID: "foobar123", Position: "CEO"}},
+		},
+	}
+
+	// act
+	err = Repository.Create(customer)
+	if err != nil {
+		t.Error(err)
+	}
+	dbCustomer, err := Repository.FindByOGRN("1027700132195")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Equal(t, customer.GeneratedID, dbCustomer.GeneratedID)
+	assert.Equal(t, domain.CustomerTypeLegalEntity, dbCustomer.Type)
+	assert.Equal(t, customer.Company, dbCustomer.Company)
+	assert.Equal(t, customer.Address, dbCustomer.Address)
+
+	// clean
+	err = Repository.Delete(customer.GeneratedID)
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,462 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	depositProductTableName       = "deposit_product"
+	depositTableName              = "deposit"
+	depositInterestEntryTableName = "deposit_interest_entry"
+)
+
+var depositProductColumns = []string{
+	"uid",
+	"name",
+	"type",
+	"currency",
+	"annualrate",
+	"capitalisation",
+	"daycount",
+	"termmonths",
+	"earlywithdrawalpenalty",
+	"minamount",
+	"createdat",
+}
+
+var preparedDepositProductColumns = strings.Join(depositProductColumns, ", ")
+
+var depositColumns = []string{
+	"uid",
+	"productuid",
+	"customeruid",
+	"currency",
+	"principal",
+	"accruedinterest",
+	"capitalisedinterest",
+	"status",
+	"accrualstart",
+	"accrueduntil",
+	"openedat",
+	"maturesat",
+	"paidout",
+	"closedat",
+	"updatedat",
+}
+
+var preparedDepositColumns = strings.Join(depositColumns, ", ")
+
+var depositInterestEntryColumns = []string{
+	"deposituid",
+	"type",
+	"amount",
+	"day",
+}
+
+var preparedDepositInterestEntryColumns = strings.Join(depositInterestEntryColumns, ", ")
+
+type DepositRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewDepositRepository(pgConn *pgxpool.Pool) *DepositRepository {
+	return &DepositRepository{pgConn: pgConn}
+}
+
+func (a *DepositRepository) CreateProduct(product *domain.DepositProduct) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		depositProductTableName,
+		preparedDepositProductColumns,
+		getSubstitutionVerbsForColumns(depositProductColumns),
+	)
+	_, err := a.pgConn.Exec(
+		context.Background(),
+		query,
+		product.GeneratedID,
+		product.Name,
+		product.Type,
+		product.Currency,
+		product.AnnualRate,
+		product.Capitalisation,
+		product.DayCount,
+		product.TermMonths,
+		product.EarlyWithdrawalPenalty,
+		product.MinAmount,
+		product.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *DepositRepository) FindProductByID(productID string) (product *domain.DepositProduct, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedDepositProductColumns,
+		depositProductTableName,
+	)
+
+	product, err = scanDepositProduct(a.pgConn.QueryRow(context.Background(), query, productID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (a *DepositRepository) FindProducts() (products []*domain.DepositProduct, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s ORDER BY createdat;`,
+		preparedDepositProductColumns,
+		depositProductTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		product, err := scanDepositProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return products, nil
+}
+
+func (a *DepositRepository) Create(deposit *domain.Deposit, postings []*domain.Posting) (created bool, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	balance, err := lockedAvailableBalance(tx, deposit.CustomerID, deposit.Currency)
+	if err != nil {
+		return false, err
+	}
+	if balance.Available() < deposit.Principal {
+		return false, tx.Rollback(context.Background())
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		depositTableName,
+		preparedDepositColumns,
+		getSubstitutionVerbsForColumns(depositColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, depositArgs(deposit)...)
+	if err != nil {
+		return false, err
+	}
+	err = createPostings(tx, postings)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *DepositRepository) FindByID(depositID string) (deposit *domain.Deposit, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedDepositColumns,
+		depositTableName,
+	)
+
+	deposit, err = scanDeposit(a.pgConn.QueryRow(context.Background(), query, depositID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return deposit, nil
+}
+
+func (a *DepositRepository) FindByCustomerID(customerID string) (deposits []*domain.Deposit, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 ORDER BY openedat DESC;`,
+		preparedDepositColumns,
+		depositTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDeposits(rows)
+}
+
+func (a *DepositRepository) Update(
+	depositID string,
+	update func(deposit *domain.Deposit) ([]*domain.DepositInterestEntry, []*domain.Posting, error),
+) (deposit *domain.Deposit, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1 FOR UPDATE;`,
+		preparedDepositColumns,
+		depositTableName,
+	)
+	deposit, err = scanDeposit(tx.QueryRow(context.Background(), query, depositID))
+	if err == pgx.ErrNoRows {
+		return nil, tx.Rollback(context.Background())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entries, postings, err := update(deposit)
+	if err != nil {
+		return nil, err
+	}
+	err = saveDeposit(tx, deposit, entries)
+	if err != nil {
+		return nil, err
+	}
+	err = createPostings(tx, postings)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return deposit, nil
+}
+
+func (a *DepositRepository) ClaimDue(
+	today time.Time,
+	limit int,
+	accrue func(deposit *domain.Deposit) ([]*domain.DepositInterestEntry, []*domain.Posting, error),
+) (claimed int, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE status=$1 AND accrueduntil<$2
+		ORDER BY accrueduntil LIMIT $3 FOR UPDATE SKIP LOCKED;`,
+		preparedDepositColumns,
+		depositTableName,
+	)
+	rows, err := tx.Query(context.Background(), query, domain.DepositStatusOpen, today, limit)
+	if err != nil {
+		return 0, err
+	}
+	deposits, err := scanDeposits(rows)
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, deposit := range deposits {
+		var entries []*domain.DepositInterestEntry
+		var postings []*domain.Posting
+		entries, postings, err = accrue(deposit)
+		if err != nil {
+			return 0, err
+		}
+		err = saveDeposit(tx, deposit, entries)
+		if err != nil {
+			return 0, err
+		}
+		err = createPostings(tx, postings)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return len(deposits), nil
+}
+
+func (a *DepositRepository) FindInterestEntries(
+	depositID string,
+) (entries []*domain.DepositInterestEntry, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE deposituid=$1 ORDER BY day;`,
+		preparedDepositInterestEntryColumns,
+		depositInterestEntryTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, depositID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := &domain.DepositInterestEntry{}
+		err = rows.Scan(&entry.DepositID, &entry.Type, &entry.Amount, &entry.Day)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return entries, nil
+}
+
+// saveDeposit updates deposit and adds its interest entries within transaction
+func saveDeposit(tx pgx.Tx, deposit *domain.Deposit, entries []*domain.DepositInterestEntry) error {
+	query := fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		depositTableName,
+		preparedDepositColumns,
+		getSubstitutionVerbsForColumns(depositColumns),
+	)
+	_, err := tx.Exec(context.Background(), query, depositArgs(deposit)...)
+	if err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		depositInterestEntryTableName,
+		preparedDepositInterestEntryColumns,
+		getSubstitutionVerbsForColumns(depositInterestEntryColumns),
+	)
+	for _, entry := range entries {
+		_, err = tx.Exec(context.Background(), query, entry.DepositID, entry.Type, entry.Amount, entry.Day)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func depositArgs(deposit *domain.Deposit) []interface{} {
+	return []interface{}{
+		deposit.GeneratedID,
+		deposit.ProductID,
+		deposit.CustomerID,
+		deposit.Currency,
+		deposit.Principal,
+		deposit.AccruedInterest,
+		deposit.CapitalisedInterest,
+		deposit.Status,
+		deposit.AccrualStart,
+		deposit.AccruedUntil,
+		deposit.OpenedAt,
+		nullableTime(deposit.MaturesAt),
+		deposit.PaidOut,
+		nullableTime(deposit.ClosedAt),
+		deposit.UpdatedAt,
+	}
+}
+
+func scanDepositProduct(row pgx.Row) (*domain.DepositProduct, error) {
+	product := &domain.DepositProduct{}
+	err := row.Scan(
+		&product.GeneratedID,
+		&product.Name,
+		&product.Type,
+		&product.Currency,
+		&product.AnnualRate,
+		&product.Capitalisation,
+		&product.DayCount,
+		&product.TermMonths,
+		&product.EarlyWithdrawalPenalty,
+		&product.MinAmount,
+		&product.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func scanDeposit(row pgx.Row) (*domain.Deposit, error) {
+	deposit := &domain.Deposit{}
+	var maturesAt, closedAt *time.Time
+	err := row.Scan(
+		&deposit.GeneratedID,
+		&deposit.ProductID,
+		&deposit.CustomerID,
+		&deposit.Currency,
+		&deposit.Principal,
+		&deposit.AccruedInterest,
+		&deposit.CapitalisedInterest,
+		&deposit.Status,
+		&deposit.AccrualStart,
+		&deposit.AccruedUntil,
+		&deposit.OpenedAt,
+		&maturesAt,
+		&deposit.PaidOut,
+		&closedAt,
+		&deposit.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if maturesAt != nil {
+		deposit.MaturesAt = *maturesAt
+	}
+	if closedAt != nil {
+		deposit.ClosedAt = *closedAt
+	}
+	return deposit, nil
+}
+
+func scanDeposits(rows pgx.Rows) ([]*domain.Deposit, error) {
+	var deposits []*domain.Deposit
+	for rows.Next() {
+		deposit, err := scanDeposit(rows)
+		if err != nil {
+			return nil, err
+		}
+		deposits = append(deposits, deposit)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return deposits, nil
+}
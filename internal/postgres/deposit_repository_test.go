@@ -0,0 +1,136 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestDeposit_ClaimDue(t *testing.T) {
+	// clean
+	for _, query := range []string{
+		`DELETE FROM deposit;`,
+		`DELETE FROM deposit_interest_entry;`,
+		`DELETE FROM posting WHERE customeruid='deposit_customer' OR reference LIKE 'deposit:deposit_%';`,
+		`DELETE FROM customer WHERE uid='deposit_customer';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewDepositRepository(PostgresConnection)
+	today := time.Date(2020, 8, 18, 0, 0, 0, 0, time.UTC)
+
+	// arrange
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "deposit_customer",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Bruce",
+		LastName:    "Wayne",
+		Phone:       "+79930000002",
+		CreatedAt:   today,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = NewPostingRepository(PostgresConnection).Create(&domain.Posting{
+		GeneratedID: "deposit_top_up",
+		CustomerID:  "deposit_customer",
+		Amount:      150000,
+		Currency:    "RUB",
+		PostedAt:    today.Add(-48 * time.Hour),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	deposit := func(depositID string, accruedUntil time.Time) *domain.Deposit {
+		return &domain.Deposit{
+			GeneratedID:  depositID,
+			ProductID:    "deposit_product",
+			CustomerID:   "deposit_customer",
+			Currency:     "RUB",
+			Principal:    100000,
+			Status:       domain.DepositStatusOpen,
+			AccrualStart: accruedUntil,
+			AccruedUntil: accruedUntil,
+			OpenedAt:     accruedUntil,
+			UpdatedAt:    accruedUntil,
+		}
+	}
+	debit := func(depositID string) []*domain.Posting {
+		return []*domain.Posting{{
+			GeneratedID: depositID + "_debit",
+			CustomerID:  "deposit_customer",
+			Amount:      -100000,
+			Currency:    "RUB",
+			Reference:   "deposit:" + depositID,
+			PostedAt:    today.Add(-24 * time.Hour),
+		}}
+	}
+	created, err := repository.Create(deposit("deposit_due", today.Add(-24*time.Hour)), debit("deposit_due"))
+	if err != nil {
+		t.Error(err)
+	}
+	createdOverBalance, err := repository.Create(deposit("deposit_accrued", today), debit("deposit_accrued"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	// act
+	accrue := func(deposit *domain.Deposit) ([]*domain.DepositInterestEntry, []*domain.Posting, error) {
+		deposit.AccruedInterest += 27
+		deposit.AccruedUntil = today
+		entries := []*domain.DepositInterestEntry{{
+			DepositID: deposit.GeneratedID,
+			Type:      domain.DepositInterestEntryTypeAccrual,
+			Amount:    27,
+			Day:       today.Add(-24 * time.Hour),
+		}}
+		postings := []*domain.Posting{{
+			GeneratedID: deposit.GeneratedID + "_accrual",
+			CustomerID:  domain.LedgerAccountAccruedInterest,
+			Amount:      27,
+			Currency:    "RUB",
+			Reference:   "deposit:" + deposit.GeneratedID + ":accrual",
+			PostedAt:    today,
+		}}
+		return entries, postings, nil
+	}
+	claimed, err := repository.ClaimDue(today, 10, accrue)
+	if err != nil {
+		t.Error(err)
+	}
+	claimedAgain, err := repository.ClaimDue(today, 10, accrue)
+	if err != nil {
+		t.Error(err)
+	}
+	found, err := repository.FindByID("deposit_due")
+	if err != nil {
+		t.Error(err)
+	}
+	entries, err := repository.FindInterestEntries("deposit_due")
+	if err != nil {
+		t.Error(err)
+	}
+	deposits, err := repository.FindByCustomerID("deposit_customer")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.True(t, created)
+	assert.False(t, createdOverBalance)
+	assert.Equal(t, 1, claimed)
+	assert.Equal(t, 0, claimedAgain)
+	assert.Equal(t, int64(27), found.AccruedInterest)
+	assert.True(t, today.Equal(found.AccruedUntil))
+	assert.True(t, found.MaturesAt.IsZero())
+	assert.Len(t, entries, 1)
+	assert.Len(t, deposits, 1)
+}
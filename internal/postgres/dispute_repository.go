@@ -0,0 +1,364 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	disputeTableName         = "dispute"
+	disputeEvidenceTableName = "dispute_evidence"
+)
+
+var disputeColumns = []string{
+	"uid",
+	"paymentuid",
+	"merchantuid",
+	"payeruid",
+	"amount",
+	"currency",
+	"reasoncode",
+	"description",
+	"status",
+	"respondby",
+	"resolvedat",
+	"createdat",
+	"updatedat",
+}
+
+var preparedDisputeColumns = strings.Join(disputeColumns, ", ")
+
+var disputeEvidenceColumns = []string{
+	"uid",
+	"disputeuid",
+	"customeruid",
+	"filename",
+	"contenttype",
+	"size",
+	"blobkey",
+	"createdat",
+}
+
+var preparedDisputeEvidenceColumns = strings.Join(disputeEvidenceColumns, ", ")
+
+type DisputeRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewDisputeRepository(pgConn *pgxpool.Pool) *DisputeRepository {
+	return &DisputeRepository{pgConn: pgConn}
+}
+
+func (a *DisputeRepository) Create(dispute *domain.Dispute, postings []*domain.Posting) (err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		disputeTableName,
+		preparedDisputeColumns,
+		getSubstitutionVerbsForColumns(disputeColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, disputeArgs(dispute)...)
+	if err != nil {
+		return err
+	}
+	err = createForcedPostings(tx, postings)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(context.Background())
+}
+
+func (a *DisputeRepository) FindByID(disputeID string) (dispute *domain.Dispute, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedDisputeColumns,
+		disputeTableName,
+	)
+
+	dispute, err = scanDispute(a.pgConn.QueryRow(context.Background(), query, disputeID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+func (a *DisputeRepository) FindByPaymentID(paymentID string) (dispute *domain.Dispute, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE paymentuid=$1;`,
+		preparedDisputeColumns,
+		disputeTableName,
+	)
+
+	dispute, err = scanDispute(a.pgConn.QueryRow(context.Background(), query, paymentID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+func (a *DisputeRepository) FindByMerchantID(merchantID string) (disputes []*domain.Dispute, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE merchantuid=$1 ORDER BY createdat DESC;`,
+		preparedDisputeColumns,
+		disputeTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanDisputes(rows)
+}
+
+func (a *DisputeRepository) Update(
+	disputeID string,
+	update func(dispute *domain.Dispute) ([]*domain.Posting, error),
+) (dispute *domain.Dispute, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1 FOR UPDATE;`,
+		preparedDisputeColumns,
+		disputeTableName,
+	)
+	dispute, err = scanDispute(tx.QueryRow(context.Background(), query, disputeID))
+	if err == pgx.ErrNoRows {
+		return nil, tx.Rollback(context.Background())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	postings, err := update(dispute)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(context.Background(), updateDisputeQuery(), disputeArgs(dispute)...)
+	if err != nil {
+		return nil, err
+	}
+	err = createPostings(tx, postings)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+func (a *DisputeRepository) ClaimOverdue(
+	now time.Time,
+	limit int,
+	settle func(dispute *domain.Dispute) ([]*domain.Posting, error),
+) (claimed int, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE status=$1 AND respondby<=$2
+		ORDER BY respondby LIMIT $3 FOR UPDATE SKIP LOCKED;`,
+		preparedDisputeColumns,
+		disputeTableName,
+	)
+	rows, err := tx.Query(context.Background(), query, domain.DisputeStatusOpen, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	disputes, err := scanDisputes(rows)
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, dispute := range disputes {
+		var postings []*domain.Posting
+		postings, err = settle(dispute)
+		if err != nil {
+			return 0, err
+		}
+		_, err = tx.Exec(context.Background(), updateDisputeQuery(), disputeArgs(dispute)...)
+		if err != nil {
+			return 0, err
+		}
+		err = createPostings(tx, postings)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return len(disputes), nil
+}
+
+func (a *DisputeRepository) AddEvidence(evidence *domain.DisputeEvidence) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		disputeEvidenceTableName,
+		preparedDisputeEvidenceColumns,
+		getSubstitutionVerbsForColumns(disputeEvidenceColumns),
+	)
+	_, err := a.pgConn.Exec(
+		context.Background(),
+		query,
+		evidence.GeneratedID,
+		evidence.DisputeID,
+		evidence.CustomerID,
+		evidence.FileName,
+		evidence.ContentType,
+		evidence.Size,
+		evidence.BlobKey,
+		evidence.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *DisputeRepository) FindEvidence(disputeID string) (evidence []*domain.DisputeEvidence, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE disputeuid=$1 ORDER BY createdat;`,
+		preparedDisputeEvidenceColumns,
+		disputeEvidenceTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		file := &domain.DisputeEvidence{}
+		err = rows.Scan(
+			&file.GeneratedID,
+			&file.DisputeID,
+			&file.CustomerID,
+			&file.FileName,
+			&file.ContentType,
+			&file.Size,
+			&file.BlobKey,
+			&file.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		evidence = append(evidence, file)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return evidence, nil
+}
+
+func updateDisputeQuery() string {
+	return fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		disputeTableName,
+		preparedDisputeColumns,
+		getSubstitutionVerbsForColumns(disputeColumns),
+	)
+}
+
+func disputeArgs(dispute *domain.Dispute) []interface{} {
+	return []interface{}{
+		dispute.GeneratedID,
+		dispute.PaymentID,
+		dispute.MerchantID,
+		dispute.PayerID,
+		dispute.Amount,
+		dispute.Currency,
+		dispute.ReasonCode,
+		dispute.Description,
+		dispute.Status,
+		dispute.RespondBy,
+		nullableTime(dispute.ResolvedAt),
+		dispute.CreatedAt,
+		dispute.UpdatedAt,
+	}
+}
+
+func scanDispute(row pgx.Row) (*domain.Dispute, error) {
+	dispute := &domain.Dispute{}
+	var resolvedAt *time.Time
+	err := row.Scan(
+		&dispute.GeneratedID,
+		&dispute.PaymentID,
+		&dispute.MerchantID,
+		&dispute.PayerID,
+		&dispute.Amount,
+		&dispute.Currency,
+		&dispute.ReasonCode,
+		&dispute.Description,
+		&dispute.Status,
+		&dispute.RespondBy,
+		&resolvedAt,
+		&dispute.CreatedAt,
+		&dispute.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if resolvedAt != nil {
+		dispute.ResolvedAt = *resolvedAt
+	}
+	return dispute, nil
+}
+
+func scanDisputes(rows pgx.Rows) ([]*domain.Dispute, error) {
+	var disputes []*domain.Dispute
+	for rows.Next() {
+		dispute, err := scanDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		disputes = append(disputes, dispute)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return disputes, nil
+}
@@ -0,0 +1,140 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestDispute_ClaimOverdue(t *testing.T) {
+	// clean
+	for _, query := range []string{
+		`DELETE FROM dispute;`,
+		`DELETE FROM dispute_evidence;`,
+		`DELETE FROM posting WHERE customeruid LIKE 'dispute_%';`,
+		`DELETE FROM customer WHERE uid='dispute_merchant';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewDisputeRepository(PostgresConnection)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// arrange
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "dispute_merchant",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Bruce",
+		LastName:    "Wayne",
+		Phone:       "+79930000009",
+		CreatedAt:   now,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	dispute := func(disputeID string, status domain.DisputeStatus, respondBy time.Time) *domain.Dispute {
+		return &domain.Dispute{
+			GeneratedID: disputeID,
+			PaymentID:   disputeID + "_payment",
+			MerchantID:  "dispute_merchant",
+			PayerID:     "dispute_payer",
+			Amount:      10000,
+			Currency:    "RUB",
+			ReasonCode:  domain.DisputeReasonCodeProductNotReceived,
+			Status:      status,
+			RespondBy:   respondBy,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	}
+	provisionalDebit := func(disputeID string) []*domain.Posting {
+		return []*domain.Posting{{
+			GeneratedID: disputeID + "_debit",
+			CustomerID:  "dispute_merchant",
+			Amount:      -10000,
+			Currency:    "RUB",
+			Reference:   "dispute:" + disputeID,
+			PostedAt:    now.Add(-time.Hour),
+		}}
+	}
+	for _, item := range []*domain.Dispute{
+		dispute("dispute_overdue", domain.DisputeStatusOpen, now.Add(-time.Minute)),
+		dispute("dispute_open", domain.DisputeStatusOpen, now.Add(time.Hour)),
+		dispute("dispute_reviewed", domain.DisputeStatusUnderReview, now.Add(-time.Minute)),
+	} {
+		err = repository.Create(item, provisionalDebit(item.GeneratedID))
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	err = repository.AddEvidence(&domain.DisputeEvidence{
+		GeneratedID: "dispute_receipt",
+		DisputeID:   "dispute_reviewed",
+		CustomerID:  "dispute_merchant",
+		FileName:    "receipt.pdf",
+		ContentType: "application/pdf",
+		Size:        1024,
+		BlobKey:     "disputes/dispute_reviewed/dispute_receipt",
+		CreatedAt:   now,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	// act
+	settle := func(dispute *domain.Dispute) ([]*domain.Posting, error) {
+		dispute.Status = domain.DisputeStatusLost
+		dispute.ResolvedAt = now
+		dispute.UpdatedAt = now
+		return []*domain.Posting{{
+			GeneratedID: dispute.GeneratedID + "_refund",
+			CustomerID:  "dispute_payer",
+			Amount:      dispute.Amount,
+			Currency:    dispute.Currency,
+			Reference:   "dispute:" + dispute.GeneratedID,
+			PostedAt:    now,
+		}}, nil
+	}
+	claimed, err := repository.ClaimOverdue(now, 10, settle)
+	if err != nil {
+		t.Error(err)
+	}
+	overdue, err := repository.FindByPaymentID("dispute_overdue_payment")
+	if err != nil {
+		t.Error(err)
+	}
+	merchantDisputes, err := repository.FindByMerchantID("dispute_merchant")
+	if err != nil {
+		t.Error(err)
+	}
+	evidence, err := repository.FindEvidence("dispute_reviewed")
+	if err != nil {
+		t.Error(err)
+	}
+	postings := NewPostingRepository(PostgresConnection)
+	merchantBalance, err := postings.FindBalance("dispute_merchant", "RUB", now.Add(time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+	payerBalance, err := postings.FindBalance("dispute_payer", "RUB", now.Add(time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Equal(t, 1, claimed)
+	assert.Equal(t, domain.DisputeStatusLost, overdue.Status)
+	assert.True(t, now.Equal(overdue.ResolvedAt))
+	assert.Len(t, merchantDisputes, 3)
+	assert.Len(t, evidence, 1)
+	assert.Equal(t, "receipt.pdf", evidence[0].FileName)
+	assert.Equal(t, int64(-30000), merchantBalance)
+	assert.Equal(t, int64(10000), payerBalance)
+}
@@ -0,0 +1,309 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const escrowTableName = "escrow"
+
+var escrowColumns = []string{
+	"uid",
+	"merchantuid",
+	"orderid",
+	"buyeruid",
+	"selleruid",
+	"amount",
+	"currency",
+	"releasecondition",
+	"expiryaction",
+	"status",
+	"selleramount",
+	"disputereason",
+	"riskassessmentuid",
+	"riskdecision",
+	"expiresat",
+	"settledat",
+	"createdat",
+	"updatedat",
+}
+
+var preparedEscrowColumns = strings.Join(escrowColumns, ", ")
+
+type EscrowRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewEscrowRepository(pgConn *pgxpool.Pool) *EscrowRepository {
+	return &EscrowRepository{pgConn: pgConn}
+}
+
+func (a *EscrowRepository) Create(escrow *domain.Escrow, debit *domain.Debit) (err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		escrowTableName,
+		preparedEscrowColumns,
+		getSubstitutionVerbsForColumns(escrowColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, escrowArgs(escrow)...)
+	if err != nil {
+		return err
+	}
+	err = createDebit(tx, debit)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(context.Background())
+}
+
+func (a *EscrowRepository) FindByID(escrowID string) (escrow *domain.Escrow, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedEscrowColumns,
+		escrowTableName,
+	)
+
+	escrow, err = scanEscrow(a.pgConn.QueryRow(context.Background(), query, escrowID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return escrow, nil
+}
+
+func (a *EscrowRepository) FindByOrderID(merchantID string, orderID string) (escrow *domain.Escrow, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE merchantuid=$1 AND orderid=$2;`,
+		preparedEscrowColumns,
+		escrowTableName,
+	)
+
+	escrow, err = scanEscrow(a.pgConn.QueryRow(context.Background(), query, merchantID, orderID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return escrow, nil
+}
+
+func (a *EscrowRepository) FindByMerchantID(merchantID string) (escrows []*domain.Escrow, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE merchantuid=$1 ORDER BY createdat DESC;`,
+		preparedEscrowColumns,
+		escrowTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanEscrows(rows)
+}
+
+func (a *EscrowRepository) Update(
+	escrowID string,
+	update func(escrow *domain.Escrow) ([]*domain.Debit, error),
+) (escrow *domain.Escrow, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1 FOR UPDATE;`,
+		preparedEscrowColumns,
+		escrowTableName,
+	)
+	escrow, err = scanEscrow(tx.QueryRow(context.Background(), query, escrowID))
+	if err == pgx.ErrNoRows {
+		return nil, tx.Rollback(context.Background())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	debits, err := update(escrow)
+	if err != nil {
+		return nil, err
+	}
+	err = saveEscrow(tx, escrow, debits)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return escrow, nil
+}
+
+func (a *EscrowRepository) ClaimExpired(
+	now time.Time,
+	limit int,
+	settle func(escrow *domain.Escrow) ([]*domain.Debit, error),
+) (claimed int, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE status=$1 AND expiresat<=$2
+		ORDER BY expiresat LIMIT $3 FOR UPDATE SKIP LOCKED;`,
+		preparedEscrowColumns,
+		escrowTableName,
+	)
+	rows, err := tx.Query(context.Background(), query, domain.EscrowStatusHeld, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	escrows, err := scanEscrows(rows)
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, escrow := range escrows {
+		var debits []*domain.Debit
+		debits, err = settle(escrow)
+		if err != nil {
+			return 0, err
+		}
+		err = saveEscrow(tx, escrow, debits)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return len(escrows), nil
+}
+
+// saveEscrow saves changes of locked escrow with debits of escrow account
+func saveEscrow(tx pgx.Tx, escrow *domain.Escrow, debits []*domain.Debit) error {
+	_, err := tx.Exec(context.Background(), updateEscrowQuery(), escrowArgs(escrow)...)
+	if err != nil {
+		return err
+	}
+	for _, debit := range debits {
+		err = createDebit(tx, debit)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func updateEscrowQuery() string {
+	return fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		escrowTableName,
+		preparedEscrowColumns,
+		getSubstitutionVerbsForColumns(escrowColumns),
+	)
+}
+
+func escrowArgs(escrow *domain.Escrow) []interface{} {
+	return []interface{}{
+		escrow.GeneratedID,
+		escrow.MerchantID,
+		escrow.OrderID,
+		escrow.BuyerID,
+		escrow.SellerID,
+		escrow.Amount,
+		escrow.Currency,
+		escrow.ReleaseCondition,
+		escrow.ExpiryAction,
+		escrow.Status,
+		escrow.SellerAmount,
+		escrow.DisputeReason,
+		escrow.RiskAssessmentID,
+		escrow.RiskDecision,
+		escrow.ExpiresAt,
+		nullableTime(escrow.SettledAt),
+		escrow.CreatedAt,
+		escrow.UpdatedAt,
+	}
+}
+
+func scanEscrow(row pgx.Row) (*domain.Escrow, error) {
+	escrow := &domain.Escrow{}
+	var settledAt *time.Time
+	err := row.Scan(
+		&escrow.GeneratedID,
+		&escrow.MerchantID,
+		&escrow.OrderID,
+		&escrow.BuyerID,
+		&escrow.SellerID,
+		&escrow.Amount,
+		&escrow.Currency,
+		&escrow.ReleaseCondition,
+		&escrow.ExpiryAction,
+		&escrow.Status,
+		&escrow.SellerAmount,
+		&escrow.DisputeReason,
+		&escrow.RiskAssessmentID,
+		&escrow.RiskDecision,
+		&escrow.ExpiresAt,
+		&settledAt,
+		&escrow.CreatedAt,
+		&escrow.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if settledAt != nil {
+		escrow.SettledAt = *settledAt
+	}
+	return escrow, nil
+}
+
+func scanEscrows(rows pgx.Rows) ([]*domain.Escrow, error) {
+	var escrows []*domain.Escrow
+	for rows.Next() {
+		escrow, err := scanEscrow(rows)
+		if err != nil {
+			return nil, err
+		}
+		escrows = append(escrows, escrow)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return escrows, nil
+}
@@ -0,0 +1,202 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// escrowDebit moves amount from payer to payee, escrow buyer is funded by cleanEscrows
+func escrowDebit(reference string, payerID string, payeeID string, amount int64) *domain.Debit {
+	now := time.Now().UTC()
+	return &domain.Debit{
+		PayerID:   payerID,
+		PayeeID:   payeeID,
+		Amount:    amount,
+		Currency:  "RUB",
+		Reference: reference,
+		Postings: []*domain.Posting{
+			{GeneratedID: reference + "_0", CustomerID: payerID, Amount: -amount, Currency: "RUB",
+				Reference: reference, PostedAt: now},
+			{GeneratedID: reference + "_1", CustomerID: payeeID, Amount: amount, Currency: "RUB",
+				Reference: reference, PostedAt: now},
+		},
+		PostedAt: now,
+	}
+}
+
+// cleanEscrows removes escrows and creates escrow_buyer customer with balance
+func cleanEscrows(t *testing.T) {
+	for _, query := range []string{
+		`DELETE FROM escrow;`,
+		`DELETE FROM posting WHERE reference LIKE 'escrow_%';`,
+		`DELETE FROM customer WHERE uid='escrow_buyer';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "escrow_buyer",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Bruce",
+		LastName:    "Wayne",
+		Phone:       "+79930000006",
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = NewPostingRepository(PostgresConnection).Create(&domain.Posting{
+		GeneratedID: "escrow_top_up",
+		CustomerID:  "escrow_buyer",
+		Amount:      35000,
+		Currency:    "RUB",
+		Reference:   "escrow_top_up",
+		PostedAt:    time.Now().Add(-time.Hour),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestEscrow_ClaimExpired(t *testing.T) {
+	// clean
+	cleanEscrows(t)
+	repository := NewEscrowRepository(PostgresConnection)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// arrange
+	escrow := func(escrowID string, status domain.EscrowStatus, expiresAt time.Time) *domain.Escrow {
+		return &domain.Escrow{
+			GeneratedID:      escrowID,
+			MerchantID:       "escrow_merchant",
+			OrderID:          escrowID + "_order",
+			BuyerID:          "escrow_buyer",
+			SellerID:         "escrow_seller",
+			Amount:           10000,
+			Currency:         "RUB",
+			ReleaseCondition: domain.EscrowReleaseConditionBuyerConfirmation,
+			ExpiryAction:     domain.EscrowExpiryActionRelease,
+			Status:           status,
+			ExpiresAt:        expiresAt,
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+	}
+	for _, item := range []*domain.Escrow{
+		escrow("escrow_expired", domain.EscrowStatusHeld, now.Add(-time.Minute)),
+		escrow("escrow_held", domain.EscrowStatusHeld, now.Add(time.Hour)),
+		escrow("escrow_disputed", domain.EscrowStatusDisputed, now.Add(-time.Minute)),
+	} {
+		err := repository.Create(item, escrowDebit(item.GeneratedID, "escrow_buyer", domain.LedgerAccountEscrow, 10000))
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	overBalanceErr := repository.Create(
+		escrow("escrow_over_balance", domain.EscrowStatusHeld, now.Add(time.Hour)),
+		escrowDebit("escrow_over_balance", "escrow_buyer", domain.LedgerAccountEscrow, 10000),
+	)
+
+	// act
+	settle := func(escrow *domain.Escrow) ([]*domain.Debit, error) {
+		escrow.Status = domain.EscrowStatusReleased
+		escrow.SellerAmount = escrow.Amount
+		escrow.SettledAt = now
+		escrow.UpdatedAt = now
+		return []*domain.Debit{
+			escrowDebit(escrow.GeneratedID+"_seller", domain.LedgerAccountEscrow, "escrow_seller", escrow.Amount),
+		}, nil
+	}
+	claimed, err := repository.ClaimExpired(now, 10, settle)
+	if err != nil {
+		t.Error(err)
+	}
+	claimedAgain, err := repository.ClaimExpired(now, 10, settle)
+	if err != nil {
+		t.Error(err)
+	}
+	released, err := repository.FindByOrderID("escrow_merchant", "escrow_expired_order")
+	if err != nil {
+		t.Error(err)
+	}
+	escrows, err := repository.FindByMerchantID("escrow_merchant")
+	if err != nil {
+		t.Error(err)
+	}
+	sellerBalance, err := NewPostingRepository(PostgresConnection).FindBalance("escrow_seller", "RUB", now.Add(time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Equal(t, domain.ErrInsufficientFunds, overBalanceErr)
+	assert.Equal(t, int64(10000), sellerBalance)
+	assert.Equal(t, 1, claimed)
+	assert.Equal(t, 0, claimedAgain)
+	assert.Equal(t, domain.EscrowStatusReleased, released.Status)
+	assert.Equal(t, int64(10000), released.SellerAmount)
+	assert.True(t, now.Equal(released.SettledAt))
+	assert.Len(t, escrows, 3)
+}
+
+func TestEscrow_Update(t *testing.T) {
+	// clean
+	cleanEscrows(t)
+	repository := NewEscrowRepository(PostgresConnection)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// arrange
+	err := repository.Create(&domain.Escrow{
+		GeneratedID:      "escrow_1",
+		MerchantID:       "escrow_merchant",
+		OrderID:          "order_1",
+		BuyerID:          "escrow_buyer",
+		SellerID:         "escrow_seller",
+		Amount:           10000,
+		Currency:         "RUB",
+		ReleaseCondition: domain.EscrowReleaseConditionMerchantConfirmation,
+		ExpiryAction:     domain.EscrowExpiryActionRefund,
+		Status:           domain.EscrowStatusHeld,
+		ExpiresAt:        now.Add(time.Hour),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}, escrowDebit("escrow_1", "escrow_buyer", domain.LedgerAccountEscrow, 10000))
+	if err != nil {
+		t.Error(err)
+	}
+
+	// act
+	updated, err := repository.Update("escrow_1", func(escrow *domain.Escrow) ([]*domain.Debit, error) {
+		escrow.Status = domain.EscrowStatusDisputed
+		escrow.DisputeReason = "item not received"
+		return nil, nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	notFound, err := repository.Update("escrow_unknown", func(escrow *domain.Escrow) ([]*domain.Debit, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	found, err := repository.FindByID("escrow_1")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Equal(t, domain.EscrowStatusDisputed, updated.Status)
+	assert.Nil(t, notFound)
+	assert.Equal(t, domain.EscrowStatusDisputed, found.Status)
+	assert.Equal(t, "item not received", found.DisputeReason)
+	assert.True(t, found.SettledAt.IsZero())
+}
@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const feeScheduleTableName = "fee_schedule"
+
+var feeScheduleColumns = []string{
+	"version",
+	"effectivefrom",
+	"rules",
+	"createdat",
+}
+
+var preparedFeeScheduleColumns = strings.Join(feeScheduleColumns, ", ")
+
+// feeRuleRow is a json representation of domain.FeeRule stored in rules column
+type feeRuleRow struct {
+	Operation   string       `json:"operation"`
+	Currency    string       `json:"currency"`
+	Fixed       int64        `json:"fixed"`
+	BasisPoints int64        `json:"basis_points"`
+	Tiers       []feeTierRow `json:"tiers"`
+	Min         int64        `json:"min"`
+	Max         int64        `json:"max"`
+}
+
+type feeTierRow struct {
+	From        int64 `json:"from"`
+	Fixed       int64 `json:"fixed"`
+	BasisPoints int64 `json:"basis_points"`
+}
+
+type FeeRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewFeeRepository(pgConn *pgxpool.Pool) *FeeRepository {
+	return &FeeRepository{pgConn: pgConn}
+}
+
+func (a *FeeRepository) CreateSchedule(schedule *domain.FeeSchedule) error {
+	rules, err := marshalFeeRules(schedule.Rules)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		feeScheduleTableName,
+		preparedFeeScheduleColumns,
+		getSubstitutionVerbsForColumns(feeScheduleColumns),
+	)
+	_, err = a.pgConn.Exec(
+		context.Background(),
+		query,
+		schedule.Version,
+		schedule.EffectiveFrom,
+		rules,
+		schedule.CreatedAt,
+	)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *FeeRepository) FindScheduleByVersion(version int) (schedule *domain.FeeSchedule, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE version=$1;`,
+		preparedFeeScheduleColumns,
+		feeScheduleTableName,
+	)
+	return a.findOne(query, version)
+}
+
+func (a *FeeRepository) FindEffectiveSchedule(at time.Time) (schedule *domain.FeeSchedule, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE effectivefrom<=$1 ORDER BY effectivefrom DESC, version DESC LIMIT 1;`,
+		preparedFeeScheduleColumns,
+		feeScheduleTableName,
+	)
+	return a.findOne(query, at)
+}
+
+func (a *FeeRepository) FindLatestSchedule() (schedule *domain.FeeSchedule, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s ORDER BY version DESC LIMIT 1;`,
+		preparedFeeScheduleColumns,
+		feeScheduleTableName,
+	)
+	return a.findOne(query)
+}
+
+func (a *FeeRepository) findOne(query string, args ...interface{}) (*domain.FeeSchedule, error) {
+	schedule := &domain.FeeSchedule{}
+	var rules []byte
+	err := a.pgConn.QueryRow(context.Background(), query, args...).Scan(
+		&schedule.Version,
+		&schedule.EffectiveFrom,
+		&rules,
+		&schedule.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.Rules, err = unmarshalFeeRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func marshalFeeRules(rules []domain.FeeRule) (string, error) {
+	rows := make([]feeRuleRow, 0, len(rules))
+	for _, rule := range rules {
+		row := feeRuleRow{
+			Operation:   string(rule.Operation),
+			Currency:    rule.Currency,
+			Fixed:       rule.Fixed,
+			BasisPoints: rule.BasisPoints,
+			Min:         rule.Min,
+			Max:         rule.Max,
+		}
+		for _, tier := range rule.Tiers {
+			row.Tiers = append(row.Tiers, feeTierRow{From: tier.From, Fixed: tier.Fixed, BasisPoints: tier.BasisPoints})
+		}
+		rows = append(rows, row)
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func unmarshalFeeRules(data []byte) ([]domain.FeeRule, error) {
+	var rows []feeRuleRow
+	err := json.Unmarshal(data, &rows)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]domain.FeeRule, 0, len(rows))
+	for _, row := range rows {
+		rule := domain.FeeRule{
+			Operation:   domain.MovementType(row.Operation),
+			Currency:    row.Currency,
+			Fixed:       row.Fixed,
+			BasisPoints: row.BasisPoints,
+			Min:         row.Min,
+			Max:         row.Max,
+		}
+		for _, tier := range row.Tiers {
+			rule.Tiers = append(rule.Tiers, domain.FeeTier{From: tier.From, Fixed: tier.Fixed, BasisPoints: tier.BasisPoints})
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
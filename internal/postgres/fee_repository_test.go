@@ -0,0 +1,71 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestFee_CreateSchedule_FindEffective(t *testing.T) {
+	// clean
+	_, err := PostgresConnection.Exec(context.Background(), `DELETE FROM fee_schedule;`)
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewFeeRepository(PostgresConnection)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// arrange
+	current := &domain.FeeSchedule{
+		Version:       1,
+		EffectiveFrom: now.Add(-time.Hour),
+		Rules: []domain.FeeRule{
+			{Operation: domain.MovementTypeTransfer, BasisPoints: 100, Min: 3000},
+			{
+				Operation: domain.MovementTypeConversion,
+				Currency:  "USD",
+				Tiers:     []domain.FeeTier{{From: 0, BasisPoints: 200}, {From: 1000000, BasisPoints: 100}},
+			},
+		},
+		CreatedAt: now,
+	}
+	future := &domain.FeeSchedule{
+		Version:       2,
+		EffectiveFrom: now.Add(time.Hour),
+		Rules:         []domain.FeeRule{{Operation: domain.MovementTypeTransfer, Fixed: 1000}},
+		CreatedAt:     now,
+	}
+
+	// act
+	for _, schedule := range []*domain.FeeSchedule{current, future} {
+		err = repository.CreateSchedule(schedule)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// assert
+	effective, err := repository.FindEffectiveSchedule(now)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, 1, effective.Version)
+	assert.Equal(t, current.Rules, effective.Rules)
+
+	latest, err := repository.FindLatestSchedule()
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, 2, latest.Version)
+
+	schedule, err := repository.FindScheduleByVersion(3)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Nil(t, schedule)
+}
@@ -0,0 +1,336 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	invoiceTableName         = "invoice"
+	invoiceSequenceTableName = "invoice_sequence"
+)
+
+var invoiceColumns = []string{
+	"uid",
+	"tenantuid",
+	"customeruid",
+	"number",
+	"status",
+	"currency",
+	"lines",
+	"subtotal",
+	"vattotal",
+	"total",
+	"duedate",
+	"issuedat",
+	"paidat",
+	"voidedat",
+	"riskassessmentuid",
+	"riskdecision",
+	"createdat",
+	"updatedat",
+}
+
+var preparedInvoiceColumns = strings.Join(invoiceColumns, ", ")
+
+// invoiceLineRow is a json representation of domain.InvoiceLine stored in lines column
+type invoiceLineRow struct {
+	Description string `json:"description"`
+	Quantity    string `json:"quantity"`
+	UnitPrice   int64  `json:"unit_price"`
+	VATRate     string `json:"vat_rate"`
+	Net         int64  `json:"net"`
+	VAT         int64  `json:"vat"`
+	Total       int64  `json:"total"`
+}
+
+type InvoiceRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewInvoiceRepository(pgConn *pgxpool.Pool) *InvoiceRepository {
+	return &InvoiceRepository{pgConn: pgConn}
+}
+
+func (a *InvoiceRepository) Create(invoice *domain.Invoice) error {
+	args, err := invoiceArgs(invoice)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		invoiceTableName,
+		preparedInvoiceColumns,
+		getSubstitutionVerbsForColumns(invoiceColumns),
+	)
+	_, err = a.pgConn.Exec(context.Background(), query, args...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *InvoiceRepository) FindByID(invoiceID string) (invoice *domain.Invoice, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedInvoiceColumns,
+		invoiceTableName,
+	)
+
+	invoice, err = scanInvoice(a.pgConn.QueryRow(context.Background(), query, invoiceID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return invoice, nil
+}
+
+func (a *InvoiceRepository) FindByCustomerID(customerID string) (invoices []*domain.Invoice, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 ORDER BY createdat DESC;`,
+		preparedInvoiceColumns,
+		invoiceTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var invoice *domain.Invoice
+		invoice, err = scanInvoice(rows)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return invoices, nil
+}
+
+func (a *InvoiceRepository) Update(invoice *domain.Invoice, expectedStatus domain.InvoiceStatus) (bool, error) {
+	args, err := invoiceArgs(invoice)
+	if err != nil {
+		return false, err
+	}
+	query := fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1 AND status=$%d;`,
+		invoiceTableName,
+		preparedInvoiceColumns,
+		getSubstitutionVerbsForColumns(invoiceColumns),
+		len(invoiceColumns)+1,
+	)
+	result, err := a.pgConn.Exec(context.Background(), query, append(args, expectedStatus)...)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (a *InvoiceRepository) Pay(invoice *domain.Invoice, debit *domain.Debit) (paid bool, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	args, err := invoiceArgs(invoice)
+	if err != nil {
+		return false, err
+	}
+	// open invoice stays locked till debit is saved, so concurrent payments do not debit customer twice
+	query := fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1 AND status='%s';`,
+		invoiceTableName,
+		preparedInvoiceColumns,
+		getSubstitutionVerbsForColumns(invoiceColumns),
+		domain.InvoiceStatusOpen,
+	)
+	result, err := tx.Exec(context.Background(), query, args...)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, tx.Rollback(context.Background())
+	}
+	err = createDebit(tx, debit)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *InvoiceRepository) Finalize(invoice *domain.Invoice) (finalized bool, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	// draft is locked before number is taken, so concurrent finalization does not leave a gap in sequence
+	query := fmt.Sprintf(`SELECT status FROM %s WHERE uid=$1 FOR UPDATE;`, invoiceTableName)
+	var status domain.InvoiceStatus
+	err = tx.QueryRow(context.Background(), query, invoice.GeneratedID).Scan(&status)
+	if err == pgx.ErrNoRows {
+		return false, tx.Rollback(context.Background())
+	}
+	if err != nil {
+		return false, err
+	}
+	if status != domain.InvoiceStatusDraft {
+		return false, tx.Rollback(context.Background())
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %[1]s (tenantuid, lastnumber) VALUES ($1, 1)
+		ON CONFLICT (tenantuid) DO UPDATE SET lastnumber = %[1]s.lastnumber + 1 RETURNING lastnumber;`,
+		invoiceSequenceTableName,
+	)
+	err = tx.QueryRow(context.Background(), query, invoice.TenantID).Scan(&invoice.Number)
+	if err != nil {
+		return false, err
+	}
+
+	args, err := invoiceArgs(invoice)
+	if err != nil {
+		return false, err
+	}
+	query = fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		invoiceTableName,
+		preparedInvoiceColumns,
+		getSubstitutionVerbsForColumns(invoiceColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func invoiceArgs(invoice *domain.Invoice) ([]interface{}, error) {
+	rows := make([]invoiceLineRow, 0, len(invoice.Lines))
+	for _, line := range invoice.Lines {
+		rows = append(rows, invoiceLineRow{
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			VATRate:     line.VATRate,
+			Net:         line.Net,
+			VAT:         line.VAT,
+			Total:       line.Total,
+		})
+	}
+	lines, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return []interface{}{
+		invoice.GeneratedID,
+		invoice.TenantID,
+		invoice.CustomerID,
+		invoice.Number,
+		invoice.Status,
+		invoice.Currency,
+		string(lines),
+		invoice.Subtotal,
+		invoice.VATTotal,
+		invoice.Total,
+		invoice.DueDate,
+		nullableTime(invoice.IssuedAt),
+		nullableTime(invoice.PaidAt),
+		nullableTime(invoice.VoidedAt),
+		invoice.RiskAssessmentID,
+		invoice.RiskDecision,
+		invoice.CreatedAt,
+		invoice.UpdatedAt,
+	}, nil
+}
+
+func scanInvoice(row pgx.Row) (*domain.Invoice, error) {
+	invoice := &domain.Invoice{}
+	var lines []byte
+	var issuedAt, paidAt, voidedAt *time.Time
+	err := row.Scan(
+		&invoice.GeneratedID,
+		&invoice.TenantID,
+		&invoice.CustomerID,
+		&invoice.Number,
+		&invoice.Status,
+		&invoice.Currency,
+		&lines,
+		&invoice.Subtotal,
+		&invoice.VATTotal,
+		&invoice.Total,
+		&invoice.DueDate,
+		&issuedAt,
+		&paidAt,
+		&voidedAt,
+		&invoice.RiskAssessmentID,
+		&invoice.RiskDecision,
+		&invoice.CreatedAt,
+		&invoice.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if issuedAt != nil {
+		invoice.IssuedAt = *issuedAt
+	}
+	if paidAt != nil {
+		invoice.PaidAt = *paidAt
+	}
+	if voidedAt != nil {
+		invoice.VoidedAt = *voidedAt
+	}
+
+	var lineRows []invoiceLineRow
+	err = json.Unmarshal(lines, &lineRows)
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range lineRows {
+		invoice.Lines = append(invoice.Lines, domain.InvoiceLine{
+			Description: line.Description,
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+			VATRate:     line.VATRate,
+			Net:         line.Net,
+			VAT:         line.VAT,
+			Total:       line.Total,
+		})
+	}
+	return invoice, nil
+}
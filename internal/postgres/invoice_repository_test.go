@@ -0,0 +1,244 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestInvoice_FinalizeNumbersPerTenant(t *testing.T) {
+	// clean
+	for _, table := range []string{"invoice", "invoice_sequence"} {
+		_, err := PostgresConnection.Exec(context.Background(), `DELETE FROM `+table+`;`)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewInvoiceRepository(PostgresConnection)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// arrange
+	newDraft := func(id string, tenantID string) *domain.Invoice {
+		return &domain.Invoice{
+			GeneratedID: id,
+			TenantID:    tenantID,
+			CustomerID:  "invoice_customer",
+			Status:      domain.InvoiceStatusDraft,
+			Currency:    "RUB",
+			Lines: []domain.InvoiceLine{{
+				Description: "Hosting", Quantity: "1", UnitPrice: 10000, VATRate: "20", Net: 10000, VAT: 2000, Total: 12000,
+			}},
+			Subtotal:  10000,
+			VATTotal:  2000,
+			Total:     12000,
+			DueDate:   now.Add(14 * 24 * time.Hour),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+	drafts := []*domain.Invoice{
+		newDraft("invoice_1", "tenant_a"),
+		newDraft("invoice_2", "tenant_a"),
+		newDraft("invoice_3", "tenant_b"),
+	}
+	for _, draft := range drafts {
+		err := repository.Create(draft)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// act
+	for _, draft := range drafts {
+		draft.Status = domain.InvoiceStatusOpen
+		draft.IssuedAt = now
+		finalized, err := repository.Finalize(draft)
+		if err != nil {
+			t.Error(err)
+		}
+		assert.True(t, finalized)
+	}
+	finalizedTwice, err := repository.Finalize(drafts[0])
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.False(t, finalizedTwice)
+	assert.Equal(t, int64(1), drafts[0].Number)
+	assert.Equal(t, int64(2), drafts[1].Number)
+	assert.Equal(t, int64(1), drafts[2].Number)
+
+	invoice, err := repository.FindByID("invoice_2")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, int64(2), invoice.Number)
+	assert.Equal(t, domain.InvoiceStatusOpen, invoice.Status)
+	assert.Equal(t, drafts[1].Lines, invoice.Lines)
+	assert.Equal(t, now, invoice.IssuedAt.UTC())
+	assert.True(t, invoice.PaidAt.IsZero())
+}
+
+func TestInvoice_UpdateExpectedStatus(t *testing.T) {
+	// clean
+	_, err := PostgresConnection.Exec(context.Background(), `DELETE FROM invoice;`)
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewInvoiceRepository(PostgresConnection)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// arrange
+	invoice := &domain.Invoice{
+		GeneratedID: "invoice_1",
+		TenantID:    "tenant_a",
+		CustomerID:  "invoice_customer",
+		Number:      1,
+		Status:      domain.InvoiceStatusOpen,
+		Currency:    "RUB",
+		Total:       12000,
+		DueDate:     now,
+		IssuedAt:    now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	err = repository.Create(invoice)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// act
+	invoice.Status = domain.InvoiceStatusPaid
+	invoice.PaidAt = now
+	paid, err := repository.Update(invoice, domain.InvoiceStatusOpen)
+	if err != nil {
+		t.Error(err)
+	}
+	invoice.Status = domain.InvoiceStatusVoid
+	voided, err := repository.Update(invoice, domain.InvoiceStatusOpen)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.True(t, paid)
+	assert.False(t, voided)
+	found, err := repository.FindByID("invoice_1")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, domain.InvoiceStatusPaid, found.Status)
+}
+
+func TestInvoice_Pay(t *testing.T) {
+	// clean
+	for _, query := range []string{
+		`DELETE FROM invoice;`,
+		`DELETE FROM posting WHERE reference LIKE 'invoice_%';`,
+		`DELETE FROM customer WHERE uid='invoice_payer';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewInvoiceRepository(PostgresConnection)
+	postingRepository := NewPostingRepository(PostgresConnection)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// arrange
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "invoice_payer",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Bruce",
+		LastName:    "Wayne",
+		Phone:       "+79930000003",
+		CreatedAt:   now,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = postingRepository.Create(&domain.Posting{
+		GeneratedID: "invoice_top_up",
+		CustomerID:  "invoice_payer",
+		Amount:      20000,
+		Currency:    "RUB",
+		Reference:   "invoice_top_up",
+		PostedAt:    now.Add(-time.Hour),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	newInvoice := func(id string) *domain.Invoice {
+		invoice := &domain.Invoice{
+			GeneratedID: id,
+			TenantID:    "tenant_a",
+			CustomerID:  "invoice_payer",
+			Number:      1,
+			Status:      domain.InvoiceStatusOpen,
+			Currency:    "RUB",
+			Total:       12000,
+			DueDate:     now,
+			IssuedAt:    now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		err := repository.Create(invoice)
+		if err != nil {
+			t.Error(err)
+		}
+		invoice.Status = domain.InvoiceStatusPaid
+		invoice.PaidAt = now
+		return invoice
+	}
+	debit := func(invoiceID string) *domain.Debit {
+		return &domain.Debit{
+			PayerID:   "invoice_payer",
+			PayeeID:   domain.TenantLedgerAccount("tenant_a"),
+			Amount:    12000,
+			Currency:  "RUB",
+			Reference: invoiceID,
+			Postings: []*domain.Posting{
+				{GeneratedID: invoiceID + "_0", CustomerID: "invoice_payer", Amount: -12000, Currency: "RUB",
+					Reference: invoiceID, PostedAt: now},
+				{GeneratedID: invoiceID + "_1", CustomerID: domain.TenantLedgerAccount("tenant_a"), Amount: 12000,
+					Currency: "RUB", Reference: invoiceID, PostedAt: now},
+			},
+			PostedAt: now,
+		}
+	}
+	invoice := newInvoice("invoice_1")
+	overBalanceInvoice := newInvoice("invoice_2")
+
+	// act
+	paid, err := repository.Pay(invoice, debit("invoice_1"))
+	if err != nil {
+		t.Error(err)
+	}
+	paidAgain, err := repository.Pay(invoice, debit("invoice_1"))
+	if err != nil {
+		t.Error(err)
+	}
+	_, overBalanceErr := repository.Pay(overBalanceInvoice, debit("invoice_2"))
+	balance, err := postingRepository.FindBalance("invoice_payer", "RUB", now.Add(time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+	notPaid, err := repository.FindByID("invoice_2")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.True(t, paid)
+	assert.False(t, paidAgain)
+	assert.Equal(t, domain.ErrInsufficientFunds, overBalanceErr)
+	assert.Equal(t, int64(8000), balance)
+	assert.Equal(t, domain.InvoiceStatusOpen, notPaid.Status)
+}
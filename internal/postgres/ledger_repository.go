@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+type LedgerRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewLedgerRepository(pgConn *pgxpool.Pool) *LedgerRepository {
+	return &LedgerRepository{pgConn: pgConn}
+}
+
+// Post checks that debit is not posted yet, concurrent posts of the same debit are rejected by unique ids of postings
+func (a *LedgerRepository) Post(debit *domain.Debit) (posted bool, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	exists, err := isPosted(tx, debit.Reference)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return false, tx.Rollback(context.Background())
+	}
+	err = createDebit(tx, debit)
+	if err != nil {
+		return false, err
+	}
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// createDebit saves postings of debit within transaction of operation. Customer payer is locked till the end
+// of transaction, so that concurrent debits could not overdraw available balance or breach limits of payer.
+func createDebit(tx pgx.Tx, debit *domain.Debit) error {
+	if !domain.IsLedgerAccount(debit.PayerID) {
+		balance, err := lockedAvailableBalance(tx, debit.PayerID, debit.Currency)
+		if err != nil {
+			return err
+		}
+		if balance.Available() < debit.Amount+debit.Fee {
+			return domain.ErrInsufficientFunds
+		}
+	}
+	if debit.Limit != nil {
+		err := chargeLimit(tx, debit.Limit)
+		if err != nil {
+			return err
+		}
+	}
+	return createPostings(tx, debit.Postings)
+}
+
+// isPosted tells whether operation with reference already has postings
+func isPosted(tx pgx.Tx, reference string) (bool, error) {
+	query := fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE reference=$1);`, postingTableName)
+	var posted bool
+	err := tx.QueryRow(context.Background(), query, reference).Scan(&posted)
+	if err != nil {
+		return false, err
+	}
+	return posted, nil
+}
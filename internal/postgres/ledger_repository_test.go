@@ -0,0 +1,87 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestLedger_Post(t *testing.T) {
+	// clean
+	for _, query := range []string{
+		`DELETE FROM posting WHERE reference LIKE 'ledger_%';`,
+		`DELETE FROM customer WHERE uid='ledger_payer';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewLedgerRepository(PostgresConnection)
+	postingRepository := NewPostingRepository(PostgresConnection)
+	now := time.Date(2020, 8, 18, 10, 0, 0, 0, time.UTC)
+
+	// arrange
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "ledger_payer",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Bruce",
+		LastName:    "Wayne",
+		Phone:       "+79930000002",
+		CreatedAt:   now,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = postingRepository.Create(&domain.Posting{
+		GeneratedID: "ledger_top_up",
+		CustomerID:  "ledger_payer",
+		Amount:      15000,
+		Currency:    "RUB",
+		Reference:   "ledger_top_up",
+		PostedAt:    now.Add(-time.Hour),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	debit := func(reference string) *domain.Debit {
+		return &domain.Debit{
+			PayerID:   "ledger_payer",
+			PayeeID:   domain.LedgerAccountEscrow,
+			Amount:    10000,
+			Currency:  "RUB",
+			Reference: reference,
+			Postings: []*domain.Posting{
+				{GeneratedID: reference + "_0", CustomerID: "ledger_payer", Amount: -10000, Currency: "RUB",
+					Reference: reference, PostedAt: now},
+				{GeneratedID: reference + "_1", CustomerID: domain.LedgerAccountEscrow, Amount: 10000, Currency: "RUB",
+					Reference: reference, PostedAt: now},
+			},
+			PostedAt: now,
+		}
+	}
+
+	// act
+	posted, err := repository.Post(debit("ledger_1"))
+	if err != nil {
+		t.Error(err)
+	}
+	repeatPosted, repeatErr := repository.Post(debit("ledger_1"))
+	_, overBalanceErr := repository.Post(debit("ledger_2"))
+	balance, err := postingRepository.FindBalance("ledger_payer", "RUB", now.Add(time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.True(t, posted)
+	assert.False(t, repeatPosted)
+	assert.Nil(t, repeatErr)
+	assert.Equal(t, domain.ErrInsufficientFunds, overBalanceErr)
+	assert.Equal(t, int64(5000), balance)
+}
@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	limitTierTableName     = "limit_tier"
+	limitCustomerTableName = "limit_customer"
+	limitUsageTableName    = "limit_usage"
+
+	limitPeriodDay   = "day"
+	limitPeriodMonth = "month"
+)
+
+var limitColumns = []string{
+	"pertransaction",
+	"daily",
+	"monthly",
+	"dailycount",
+	"monthlycount",
+}
+
+var preparedLimitColumns = strings.Join(limitColumns, ", ")
+
+var limitCustomerColumns = append([]string{"customeruid", "currency"}, limitColumns...)
+
+var preparedLimitCustomerColumns = strings.Join(limitCustomerColumns, ", ")
+
+type LimitRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewLimitRepository(pgConn *pgxpool.Pool) *LimitRepository {
+	return &LimitRepository{pgConn: pgConn}
+}
+
+func (a *LimitRepository) FindTierLimits(
+	level domain.VerificationLevel,
+	currency string,
+) (limits *domain.TransactionLimits, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE level=$1 AND currency=$2;`,
+		preparedLimitColumns,
+		limitTierTableName,
+	)
+	return a.findLimits(query, level, currency)
+}
+
+func (a *LimitRepository) FindCustomerLimits(
+	customerID string,
+	currency string,
+) (limits *domain.TransactionLimits, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 AND currency=$2;`,
+		preparedLimitColumns,
+		limitCustomerTableName,
+	)
+	return a.findLimits(query, customerID, currency)
+}
+
+func (a *LimitRepository) SaveCustomerLimits(
+	customerID string,
+	currency string,
+	limits domain.TransactionLimits,
+) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (customeruid, currency) DO UPDATE SET (%s) = ROW (EXCLUDED.%s);`,
+		limitCustomerTableName,
+		preparedLimitCustomerColumns,
+		getSubstitutionVerbsForColumns(limitCustomerColumns),
+		preparedLimitColumns,
+		strings.Join(limitColumns, ", EXCLUDED."),
+	)
+	_, err := a.pgConn.Exec(
+		context.Background(),
+		query,
+		customerID,
+		currency,
+		limits.PerTransaction,
+		limits.Daily,
+		limits.Monthly,
+		limits.DailyCount,
+		limits.MonthlyCount,
+	)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *LimitRepository) DeleteCustomerLimits(customerID string, currency string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE customeruid=$1 AND currency=$2;`, limitCustomerTableName)
+	_, err := a.pgConn.Exec(context.Background(), query, customerID, currency)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *LimitRepository) FindUsage(customerID string, currency string, at time.Time) (domain.LimitUsage, error) {
+	day, month := limitPeriods(at)
+	query := fmt.Sprintf(
+		`SELECT period, amount, count FROM %s WHERE customeruid=$1 AND currency=$2
+			AND ((period='%s' AND periodstart=$3) OR (period='%s' AND periodstart=$4));`,
+		limitUsageTableName,
+		limitPeriodDay,
+		limitPeriodMonth,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, customerID, currency, day, month)
+	if err != nil {
+		return domain.LimitUsage{}, err
+	}
+	defer rows.Close()
+	return scanLimitUsage(rows)
+}
+
+// chargeLimit checks debit against limits of customer and records it in usage within transaction of debit.
+// Returns LimitExceededError when debit would breach one of limits.
+func chargeLimit(tx pgx.Tx, charge *domain.LimitCharge) error {
+	usage, err := lockedLimitUsage(tx, charge.CustomerID, charge.Currency, charge.ChargedAt)
+	if err != nil {
+		return err
+	}
+	err = charge.Limits.Check(usage, charge.Amount)
+	if err != nil {
+		return err
+	}
+	return addLimitUsage(tx, charge.CustomerID, charge.Currency, charge.Amount, charge.ChargedAt)
+}
+
+// lockedLimitUsage locks usage of customer in currency for the day and the month of at till the end of transaction
+func lockedLimitUsage(tx pgx.Tx, customerID string, currency string, at time.Time) (domain.LimitUsage, error) {
+	day, month := limitPeriods(at)
+	query := fmt.Sprintf(
+		`INSERT INTO %s (customeruid, currency, period, periodstart) VALUES ($1, $2, '%s', $3), ($1, $2, '%s', $4)
+			ON CONFLICT DO NOTHING;`,
+		limitUsageTableName,
+		limitPeriodDay,
+		limitPeriodMonth,
+	)
+	_, err := tx.Exec(context.Background(), query, customerID, currency, day, month)
+	if err != nil {
+		return domain.LimitUsage{}, err
+	}
+
+	// rows are always locked in the same order to avoid deadlocks between concurrent debits
+	query = fmt.Sprintf(
+		`SELECT period, amount, count FROM %s WHERE customeruid=$1 AND currency=$2
+			AND ((period='%s' AND periodstart=$3) OR (period='%s' AND periodstart=$4))
+			ORDER BY period FOR UPDATE;`,
+		limitUsageTableName,
+		limitPeriodDay,
+		limitPeriodMonth,
+	)
+	rows, err := tx.Query(context.Background(), query, customerID, currency, day, month)
+	if err != nil {
+		return domain.LimitUsage{}, err
+	}
+	defer rows.Close()
+	return scanLimitUsage(rows)
+}
+
+// addLimitUsage records debit of amount in usage of customer locked by lockedLimitUsage
+func addLimitUsage(tx pgx.Tx, customerID string, currency string, amount int64, at time.Time) error {
+	day, month := limitPeriods(at)
+	query := fmt.Sprintf(
+		`UPDATE %s SET amount=amount+$5, count=count+1 WHERE customeruid=$1 AND currency=$2
+			AND ((period='%s' AND periodstart=$3) OR (period='%s' AND periodstart=$4));`,
+		limitUsageTableName,
+		limitPeriodDay,
+		limitPeriodMonth,
+	)
+	_, err := tx.Exec(context.Background(), query, customerID, currency, day, month, amount)
+	return err
+}
+
+func (a *LimitRepository) findLimits(query string, args ...interface{}) (*domain.TransactionLimits, error) {
+	limits := &domain.TransactionLimits{}
+	err := a.pgConn.QueryRow(context.Background(), query, args...).Scan(
+		&limits.PerTransaction,
+		&limits.Daily,
+		&limits.Monthly,
+		&limits.DailyCount,
+		&limits.MonthlyCount,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// limitPeriods returns start dates of business day and month of at
+func limitPeriods(at time.Time) (time.Time, time.Time) {
+	return businessday.Day(at), businessday.Month(at)
+}
+
+func scanLimitUsage(rows pgx.Rows) (domain.LimitUsage, error) {
+	usage := domain.LimitUsage{}
+	for rows.Next() {
+		var period string
+		var amount, count int64
+		err := rows.Scan(&period, &amount, &count)
+		if err != nil {
+			return domain.LimitUsage{}, err
+		}
+		switch period {
+		case limitPeriodDay:
+			usage.DailyAmount, usage.DailyCount = amount, count
+		case limitPeriodMonth:
+			usage.MonthlyAmount, usage.MonthlyCount = amount, count
+		}
+	}
+	if rows.Err() != nil {
+		return domain.LimitUsage{}, rows.Err()
+	}
+	return usage, nil
+}
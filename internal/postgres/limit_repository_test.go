@@ -0,0 +1,190 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestLimit_FindTierLimits(t *testing.T) {
+	t.Parallel()
+
+	repository := NewLimitRepository(PostgresConnection)
+
+	limits, err := repository.FindTierLimits(domain.VerificationLevelBasic, "RUB")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, int64(1500000), limits.PerTransaction)
+
+	limits, err = repository.FindTierLimits(domain.VerificationLevelBasic, "USD")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, int64(20000), limits.PerTransaction)
+
+	limits, err = repository.FindTierLimits("unknown", "RUB")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Nil(t, limits)
+}
+
+func TestLimit_SaveCustomerLimits(t *testing.T) {
+	t.Parallel()
+
+	repository := NewLimitRepository(PostgresConnection)
+	limits := domain.TransactionLimits{PerTransaction: 1, Daily: 2, Monthly: 3, DailyCount: 4, MonthlyCount: 5}
+
+	// act
+	err := repository.SaveCustomerLimits("limit_customer", "RUB", limits)
+	if err != nil {
+		t.Error(err)
+	}
+	limits.Daily = 20
+	err = repository.SaveCustomerLimits("limit_customer", "RUB", limits)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	foundLimits, err := repository.FindCustomerLimits("limit_customer", "RUB")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, &limits, foundLimits)
+	otherCurrencyLimits, err := repository.FindCustomerLimits("limit_customer", "USD")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Nil(t, otherCurrencyLimits)
+
+	err = repository.DeleteCustomerLimits("limit_customer", "RUB")
+	if err != nil {
+		t.Error(err)
+	}
+	foundLimits, err = repository.FindCustomerLimits("limit_customer", "RUB")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Nil(t, foundLimits)
+}
+
+func TestLimit_ChargeLimit(t *testing.T) {
+	t.Parallel()
+
+	// clean
+	query := `DELETE FROM limit_usage WHERE customeruid = $1;`
+	_, err := PostgresConnection.Exec(context.Background(), query, "limit_usage_customer")
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewLimitRepository(PostgresConnection)
+	limits := domain.TransactionLimits{PerTransaction: 100, Daily: 500, Monthly: 1000, DailyCount: 100, MonthlyCount: 100}
+	now := time.Now()
+	charge := func(currency string) error {
+		tx, err := PostgresConnection.Begin(context.Background())
+		if err != nil {
+			return err
+		}
+		err = chargeLimit(tx, &domain.LimitCharge{
+			CustomerID: "limit_usage_customer",
+			Currency:   currency,
+			Amount:     100,
+			Limits:     limits,
+			ChargedAt:  now,
+		})
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+			return err
+		}
+		return tx.Commit(context.Background())
+	}
+
+	// act
+	wg := sync.WaitGroup{}
+	var mu sync.Mutex
+	var exceeded int
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			chargeErr := charge("RUB")
+			if _, ok := chargeErr.(*domain.LimitExceededError); ok {
+				mu.Lock()
+				exceeded++
+				mu.Unlock()
+			} else if chargeErr != nil {
+				t.Error(chargeErr)
+			}
+		}()
+	}
+	wg.Wait()
+	otherCurrencyErr := charge("USD")
+
+	// assert
+	usage, err := repository.FindUsage("limit_usage_customer", "RUB", now)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, 5, exceeded)
+	assert.Equal(t, domain.LimitUsage{DailyAmount: 500, MonthlyAmount: 500, DailyCount: 5, MonthlyCount: 5}, usage)
+	assert.NoError(t, otherCurrencyErr)
+	usage, err = repository.FindUsage("limit_usage_customer", "USD", now)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, domain.LimitUsage{DailyAmount: 100, MonthlyAmount: 100, DailyCount: 1, MonthlyCount: 1}, usage)
+}
+
+func TestLimit_UsageOfBusinessDay(t *testing.T) {
+	t.Parallel()
+
+	// clean
+	query := `DELETE FROM limit_usage WHERE customeruid = $1;`
+	_, err := PostgresConnection.Exec(context.Background(), query, "limit_business_day_customer")
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewLimitRepository(PostgresConnection)
+	limits := domain.TransactionLimits{PerTransaction: 100, Daily: 500, Monthly: 1000, DailyCount: 10, MonthlyCount: 10}
+	// late evening of January 31 in UTC is February 1 in Moscow
+	chargedAt := time.Date(2021, time.January, 31, 22, 0, 0, 0, time.UTC)
+
+	// act
+	tx, err := PostgresConnection.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = chargeLimit(tx, &domain.LimitCharge{
+		CustomerID: "limit_business_day_customer",
+		Currency:   "RUB",
+		Amount:     100,
+		Limits:     limits,
+		ChargedAt:  chargedAt,
+	})
+	if err != nil {
+		_ = tx.Rollback(context.Background())
+		t.Fatal(err)
+	}
+	err = tx.Commit(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// assert
+	previousDay := time.Date(2021, time.January, 31, 12, 0, 0, 0, time.UTC)
+	usage, err := repository.FindUsage("limit_business_day_customer", "RUB", previousDay)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.LimitUsage{}, usage)
+	sameDay := time.Date(2021, time.February, 1, 12, 0, 0, 0, time.UTC)
+	usage, err = repository.FindUsage("limit_business_day_customer", "RUB", sameDay)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.LimitUsage{DailyAmount: 100, MonthlyAmount: 100, DailyCount: 1, MonthlyCount: 1}, usage)
+}
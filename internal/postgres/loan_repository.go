@@ -0,0 +1,749 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	loanProductTableName     = "loan_product"
+	loanApplicationTableName = "loan_application"
+	loanTableName            = "loan"
+	loanInstallmentTableName = "loan_installment"
+	loanRepaymentTableName   = "loan_repayment"
+)
+
+var loanProductColumns = []string{
+	"uid",
+	"name",
+	"currency",
+	"annualrate",
+	"penaltyrate",
+	"minamount",
+	"maxamount",
+	"mintermmonths",
+	"maxtermmonths",
+	"createdat",
+}
+
+var preparedLoanProductColumns = strings.Join(loanProductColumns, ", ")
+
+var loanApplicationColumns = []string{
+	"uid",
+	"productuid",
+	"customeruid",
+	"amount",
+	"currency",
+	"termmonths",
+	"amortisation",
+	"status",
+	"rejectionreason",
+	"loanuid",
+	"createdat",
+	"updatedat",
+}
+
+var preparedLoanApplicationColumns = strings.Join(loanApplicationColumns, ", ")
+
+var loanColumns = []string{
+	"uid",
+	"applicationuid",
+	"productuid",
+	"customeruid",
+	"currency",
+	"principal",
+	"outstanding",
+	"annualrate",
+	"penaltyrate",
+	"amortisation",
+	"termmonths",
+	"status",
+	"penalty",
+	"penaltyaccrueduntil",
+	"interestpaiduntil",
+	"nextserviceday",
+	"repayments",
+	"disbursedat",
+	"repaidat",
+	"updatedat",
+}
+
+var preparedLoanColumns = strings.Join(loanColumns, ", ")
+
+// loanInstallmentValueColumns are updated when schedule is saved again
+var loanInstallmentValueColumns = []string{
+	"dueday",
+	"principal",
+	"interest",
+	"principalpaid",
+	"interestpaid",
+	"status",
+	"paidat",
+}
+
+var loanInstallmentColumns = append([]string{"loanuid", "number"}, loanInstallmentValueColumns...)
+
+var preparedLoanInstallmentColumns = strings.Join(loanInstallmentColumns, ", ")
+
+var loanRepaymentColumns = []string{
+	"loanuid",
+	"number",
+	"type",
+	"amount",
+	"penalty",
+	"interest",
+	"principal",
+	"paidat",
+}
+
+var preparedLoanRepaymentColumns = strings.Join(loanRepaymentColumns, ", ")
+
+type LoanRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewLoanRepository(pgConn *pgxpool.Pool) *LoanRepository {
+	return &LoanRepository{pgConn: pgConn}
+}
+
+func (a *LoanRepository) CreateProduct(product *domain.LoanProduct) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		loanProductTableName,
+		preparedLoanProductColumns,
+		getSubstitutionVerbsForColumns(loanProductColumns),
+	)
+	_, err := a.pgConn.Exec(
+		context.Background(),
+		query,
+		product.GeneratedID,
+		product.Name,
+		product.Currency,
+		product.AnnualRate,
+		product.PenaltyRate,
+		product.MinAmount,
+		product.MaxAmount,
+		product.MinTermMonths,
+		product.MaxTermMonths,
+		product.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *LoanRepository) FindProductByID(productID string) (product *domain.LoanProduct, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedLoanProductColumns,
+		loanProductTableName,
+	)
+
+	product, err = scanLoanProduct(a.pgConn.QueryRow(context.Background(), query, productID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (a *LoanRepository) FindProducts() (products []*domain.LoanProduct, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s ORDER BY createdat;`,
+		preparedLoanProductColumns,
+		loanProductTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		product, err := scanLoanProduct(rows)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, product)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return products, nil
+}
+
+func (a *LoanRepository) CreateApplication(application *domain.LoanApplication) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		loanApplicationTableName,
+		preparedLoanApplicationColumns,
+		getSubstitutionVerbsForColumns(loanApplicationColumns),
+	)
+	_, err := a.pgConn.Exec(context.Background(), query, loanApplicationArgs(application)...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *LoanRepository) FindApplicationByID(
+	applicationID string,
+) (application *domain.LoanApplication, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedLoanApplicationColumns,
+		loanApplicationTableName,
+	)
+
+	application, err = scanLoanApplication(a.pgConn.QueryRow(context.Background(), query, applicationID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+func (a *LoanRepository) FindApplicationsByCustomerID(
+	customerID string,
+) (applications []*domain.LoanApplication, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 ORDER BY createdat DESC;`,
+		preparedLoanApplicationColumns,
+		loanApplicationTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		application, err := scanLoanApplication(rows)
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, application)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return applications, nil
+}
+
+func (a *LoanRepository) Disburse(
+	applicationID string,
+	disburse func(application *domain.LoanApplication) (*domain.Loan, []*domain.Posting, error),
+) (loan *domain.Loan, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1 FOR UPDATE;`,
+		preparedLoanApplicationColumns,
+		loanApplicationTableName,
+	)
+	application, err := scanLoanApplication(tx.QueryRow(context.Background(), query, applicationID))
+	if err == pgx.ErrNoRows {
+		return nil, tx.Rollback(context.Background())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	loan, postings, err := disburse(application)
+	if err != nil {
+		return nil, err
+	}
+	query = fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		loanApplicationTableName,
+		preparedLoanApplicationColumns,
+		getSubstitutionVerbsForColumns(loanApplicationColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, loanApplicationArgs(application)...)
+	if err != nil {
+		return nil, err
+	}
+	query = fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		loanTableName,
+		preparedLoanColumns,
+		getSubstitutionVerbsForColumns(loanColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, loanArgs(loan)...)
+	if err != nil {
+		return nil, err
+	}
+	err = saveLoanInstallments(tx, loan.Installments)
+	if err != nil {
+		return nil, err
+	}
+	err = createPostings(tx, postings)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+func (a *LoanRepository) FindByID(loanID string) (loan *domain.Loan, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedLoanColumns,
+		loanTableName,
+	)
+
+	loan, err = scanLoan(a.pgConn.QueryRow(context.Background(), query, loanID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	loan.Installments, err = findLoanInstallments(a.pgConn, loanID)
+	if err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+func (a *LoanRepository) FindByCustomerID(customerID string) (loans []*domain.Loan, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 ORDER BY disbursedat DESC;`,
+		preparedLoanColumns,
+		loanTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanLoans(rows)
+}
+
+func (a *LoanRepository) Update(
+	loanID string,
+	update func(loan *domain.Loan, balance int64) (*domain.LoanRepayment, []*domain.Posting, error),
+) (loan *domain.Loan, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1 FOR UPDATE;`,
+		preparedLoanColumns,
+		loanTableName,
+	)
+	loan, err = scanLoan(tx.QueryRow(context.Background(), query, loanID))
+	if err == pgx.ErrNoRows {
+		return nil, tx.Rollback(context.Background())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = updateLoan(tx, loan, update)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+func (a *LoanRepository) ClaimDue(
+	today time.Time,
+	limit int,
+	service func(loan *domain.Loan, balance int64) (*domain.LoanRepayment, []*domain.Posting, error),
+) (claimed int, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE status IN ($1, $2) AND nextserviceday<=$3
+		ORDER BY nextserviceday LIMIT $4 FOR UPDATE SKIP LOCKED;`,
+		preparedLoanColumns,
+		loanTableName,
+	)
+	rows, err := tx.Query(
+		context.Background(),
+		query,
+		domain.LoanStatusActive,
+		domain.LoanStatusOverdue,
+		today,
+		limit,
+	)
+	if err != nil {
+		return 0, err
+	}
+	loans, err := scanLoans(rows)
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, loan := range loans {
+		err = updateLoan(tx, loan, service)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return len(loans), nil
+}
+
+func (a *LoanRepository) FindRepayments(loanID string) (repayments []*domain.LoanRepayment, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE loanuid=$1 ORDER BY number;`,
+		preparedLoanRepaymentColumns,
+		loanRepaymentTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, loanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		repayment := &domain.LoanRepayment{}
+		err = rows.Scan(
+			&repayment.LoanID,
+			&repayment.Number,
+			&repayment.Type,
+			&repayment.Amount,
+			&repayment.Penalty,
+			&repayment.Interest,
+			&repayment.Principal,
+			&repayment.PaidAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		repayments = append(repayments, repayment)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return repayments, nil
+}
+
+// updateLoan reads schedule and funds of customer of locked loan, passes them to update and saves
+// loan with its schedule, repayment and postings within transaction
+func updateLoan(
+	tx pgx.Tx,
+	loan *domain.Loan,
+	update func(loan *domain.Loan, balance int64) (*domain.LoanRepayment, []*domain.Posting, error),
+) error {
+	installments, err := findLoanInstallments(tx, loan.GeneratedID)
+	if err != nil {
+		return err
+	}
+	loan.Installments = installments
+	balance, err := lockedAvailableBalance(tx, loan.CustomerID, loan.Currency)
+	if err != nil {
+		return err
+	}
+
+	repayment, postings, err := update(loan, balance.Funds())
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		loanTableName,
+		preparedLoanColumns,
+		getSubstitutionVerbsForColumns(loanColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, loanArgs(loan)...)
+	if err != nil {
+		return err
+	}
+	err = saveLoanInstallments(tx, loan.Installments)
+	if err != nil {
+		return err
+	}
+	if repayment != nil {
+		query = fmt.Sprintf(
+			`INSERT INTO %s (%s) VALUES (%s);`,
+			loanRepaymentTableName,
+			preparedLoanRepaymentColumns,
+			getSubstitutionVerbsForColumns(loanRepaymentColumns),
+		)
+		_, err = tx.Exec(
+			context.Background(),
+			query,
+			repayment.LoanID,
+			repayment.Number,
+			repayment.Type,
+			repayment.Amount,
+			repayment.Penalty,
+			repayment.Interest,
+			repayment.Principal,
+			repayment.PaidAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return createPostings(tx, postings)
+}
+
+// saveLoanInstallments inserts schedule of new loan or updates schedule of existing one within transaction
+func saveLoanInstallments(tx pgx.Tx, installments []*domain.LoanInstallment) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (loanuid, number) DO UPDATE SET (%s) = ROW (EXCLUDED.%s);`,
+		loanInstallmentTableName,
+		preparedLoanInstallmentColumns,
+		getSubstitutionVerbsForColumns(loanInstallmentColumns),
+		strings.Join(loanInstallmentValueColumns, ", "),
+		strings.Join(loanInstallmentValueColumns, ", EXCLUDED."),
+	)
+	for _, installment := range installments {
+		_, err := tx.Exec(
+			context.Background(),
+			query,
+			installment.LoanID,
+			installment.Number,
+			installment.DueDay,
+			installment.Principal,
+			installment.Interest,
+			installment.PrincipalPaid,
+			installment.InterestPaid,
+			installment.Status,
+			nullableTime(installment.PaidAt),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loanQuerier is a pool or a transaction which schedule of loan is read with
+type loanQuerier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+func findLoanInstallments(querier loanQuerier, loanID string) ([]*domain.LoanInstallment, error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE loanuid=$1 ORDER BY number;`,
+		preparedLoanInstallmentColumns,
+		loanInstallmentTableName,
+	)
+
+	rows, err := querier.Query(context.Background(), query, loanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var installments []*domain.LoanInstallment
+	for rows.Next() {
+		installment := &domain.LoanInstallment{}
+		var paidAt *time.Time
+		err = rows.Scan(
+			&installment.LoanID,
+			&installment.Number,
+			&installment.DueDay,
+			&installment.Principal,
+			&installment.Interest,
+			&installment.PrincipalPaid,
+			&installment.InterestPaid,
+			&installment.Status,
+			&paidAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if paidAt != nil {
+			installment.PaidAt = *paidAt
+		}
+		installments = append(installments, installment)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return installments, nil
+}
+
+func loanApplicationArgs(application *domain.LoanApplication) []interface{} {
+	return []interface{}{
+		application.GeneratedID,
+		application.ProductID,
+		application.CustomerID,
+		application.Amount,
+		application.Currency,
+		application.TermMonths,
+		application.Amortisation,
+		application.Status,
+		application.RejectionReason,
+		application.LoanID,
+		application.CreatedAt,
+		application.UpdatedAt,
+	}
+}
+
+func loanArgs(loan *domain.Loan) []interface{} {
+	return []interface{}{
+		loan.GeneratedID,
+		loan.ApplicationID,
+		loan.ProductID,
+		loan.CustomerID,
+		loan.Currency,
+		loan.Principal,
+		loan.Outstanding,
+		loan.AnnualRate,
+		loan.PenaltyRate,
+		loan.Amortisation,
+		loan.TermMonths,
+		loan.Status,
+		loan.Penalty,
+		loan.PenaltyAccruedUntil,
+		nullableTime(loan.InterestPaidUntil),
+		loan.NextServiceDay,
+		loan.Repayments,
+		loan.DisbursedAt,
+		nullableTime(loan.RepaidAt),
+		loan.UpdatedAt,
+	}
+}
+
+func scanLoanProduct(row pgx.Row) (*domain.LoanProduct, error) {
+	product := &domain.LoanProduct{}
+	err := row.Scan(
+		&product.GeneratedID,
+		&product.Name,
+		&product.Currency,
+		&product.AnnualRate,
+		&product.PenaltyRate,
+		&product.MinAmount,
+		&product.MaxAmount,
+		&product.MinTermMonths,
+		&product.MaxTermMonths,
+		&product.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func scanLoanApplication(row pgx.Row) (*domain.LoanApplication, error) {
+	application := &domain.LoanApplication{}
+	err := row.Scan(
+		&application.GeneratedID,
+		&application.ProductID,
+		&application.CustomerID,
+		&application.Amount,
+		&application.Currency,
+		&application.TermMonths,
+		&application.Amortisation,
+		&application.Status,
+		&application.RejectionReason,
+		&application.LoanID,
+		&application.CreatedAt,
+		&application.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return application, nil
+}
+
+func scanLoan(row pgx.Row) (*domain.Loan, error) {
+	loan := &domain.Loan{}
+	var interestPaidUntil, repaidAt *time.Time
+	err := row.Scan(
+		&loan.GeneratedID,
+		&loan.ApplicationID,
+		&loan.ProductID,
+		&loan.CustomerID,
+		&loan.Currency,
+		&loan.Principal,
+		&loan.Outstanding,
+		&loan.AnnualRate,
+		&loan.PenaltyRate,
+		&loan.Amortisation,
+		&loan.TermMonths,
+		&loan.Status,
+		&loan.Penalty,
+		&loan.PenaltyAccruedUntil,
+		&interestPaidUntil,
+		&loan.NextServiceDay,
+		&loan.Repayments,
+		&loan.DisbursedAt,
+		&repaidAt,
+		&loan.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if interestPaidUntil != nil {
+		loan.InterestPaidUntil = *interestPaidUntil
+	}
+	if repaidAt != nil {
+		loan.RepaidAt = *repaidAt
+	}
+	return loan, nil
+}
+
+func scanLoans(rows pgx.Rows) ([]*domain.Loan, error) {
+	var loans []*domain.Loan
+	for rows.Next() {
+		loan, err := scanLoan(rows)
+		if err != nil {
+			return nil, err
+		}
+		loans = append(loans, loan)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return loans, nil
+}
@@ -0,0 +1,185 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestLoan_DisburseAndClaimDue(t *testing.T) {
+	// clean
+	for _, query := range []string{
+		`DELETE FROM loan_application;`,
+		`DELETE FROM loan;`,
+		`DELETE FROM loan_installment;`,
+		`DELETE FROM loan_repayment;`,
+		`DELETE FROM posting WHERE reference='loan:loan';`,
+		`DELETE FROM credit_line WHERE customeruid='loan_customer';`,
+		`DELETE FROM customer WHERE uid='loan_customer';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewLoanRepository(PostgresConnection)
+	today := time.Date(2020, 8, 18, 0, 0, 0, 0, time.UTC)
+
+	// arrange
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "loan_customer",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Bruce",
+		LastName:    "Wayne",
+		Phone:       "+79930000003",
+		CreatedAt:   today,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = repository.CreateApplication(&domain.LoanApplication{
+		GeneratedID:  "loan_application",
+		ProductID:    "loan_product",
+		CustomerID:   "loan_customer",
+		Amount:       100000,
+		Currency:     "RUB",
+		TermMonths:   2,
+		Amortisation: domain.AmortisationDifferentiated,
+		Status:       domain.LoanApplicationStatusApproved,
+		CreatedAt:    today,
+		UpdatedAt:    today,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	// credit limit is not used to repay loans
+	err = NewCreditLineRepository(PostgresConnection).Save(&domain.CreditLine{
+		GeneratedID:  "loan_credit_line",
+		CustomerID:   "loan_customer",
+		Currency:     "RUB",
+		Limit:        30000,
+		AnnualRate:   "20",
+		AccruedUntil: today,
+		CreatedAt:    today,
+		UpdatedAt:    today,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	disbursedAt := today.AddDate(0, -1, 0)
+	disburse := func(application *domain.LoanApplication) (*domain.Loan, []*domain.Posting, error) {
+		application.Status = domain.LoanApplicationStatusDisbursed
+		application.LoanID = "loan"
+		installment := func(number int, principal int64) *domain.LoanInstallment {
+			return &domain.LoanInstallment{
+				LoanID:    "loan",
+				Number:    number,
+				DueDay:    disbursedAt.AddDate(0, number, 0),
+				Principal: principal,
+				Interest:  1000,
+				Status:    domain.LoanInstallmentStatusPending,
+			}
+		}
+		loan := &domain.Loan{
+			GeneratedID:         "loan",
+			ApplicationID:       application.GeneratedID,
+			ProductID:           application.ProductID,
+			CustomerID:          application.CustomerID,
+			Currency:            application.Currency,
+			Principal:           application.Amount,
+			Outstanding:         application.Amount,
+			AnnualRate:          "12",
+			PenaltyRate:         "20",
+			Amortisation:        application.Amortisation,
+			TermMonths:          application.TermMonths,
+			Status:              domain.LoanStatusActive,
+			PenaltyAccruedUntil: disbursedAt,
+			NextServiceDay:      today,
+			Installments:        []*domain.LoanInstallment{installment(1, 50000), installment(2, 50000)},
+			DisbursedAt:         disbursedAt,
+			UpdatedAt:           disbursedAt,
+		}
+		postings := []*domain.Posting{
+			{GeneratedID: "loan_book_disbursement", CustomerID: domain.LedgerAccountLoans, Amount: -application.Amount},
+			{GeneratedID: "loan_disbursement", CustomerID: application.CustomerID, Amount: application.Amount},
+		}
+		for _, posting := range postings {
+			posting.Currency = application.Currency
+			posting.Reference = "loan:loan"
+			posting.PostedAt = disbursedAt
+		}
+		return loan, postings, nil
+	}
+	loan, err := repository.Disburse("loan_application", disburse)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// act
+	var serviceBalance int64
+	service := func(loan *domain.Loan, balance int64) (*domain.LoanRepayment, []*domain.Posting, error) {
+		serviceBalance = balance
+		loan.Installments[0].PrincipalPaid = 50000
+		loan.Installments[0].InterestPaid = 1000
+		loan.Installments[0].Status = domain.LoanInstallmentStatusPaid
+		loan.Installments[0].PaidAt = today
+		loan.Outstanding -= 50000
+		loan.Repayments++
+		loan.NextServiceDay = today.AddDate(0, 1, 0)
+		repayment := &domain.LoanRepayment{
+			LoanID:    loan.GeneratedID,
+			Number:    loan.Repayments,
+			Type:      domain.LoanRepaymentTypeScheduled,
+			Amount:    51000,
+			Interest:  1000,
+			Principal: 50000,
+			PaidAt:    today,
+		}
+		return repayment, []*domain.Posting{{
+			GeneratedID: "loan_repayment",
+			CustomerID:  loan.CustomerID,
+			Amount:      -51000,
+			Currency:    loan.Currency,
+			Reference:   "loan:loan",
+			PostedAt:    today,
+		}}, nil
+	}
+	claimed, err := repository.ClaimDue(today, 10, service)
+	if err != nil {
+		t.Error(err)
+	}
+	claimedAgain, err := repository.ClaimDue(today, 10, service)
+	if err != nil {
+		t.Error(err)
+	}
+	found, err := repository.FindByID("loan")
+	if err != nil {
+		t.Error(err)
+	}
+	application, err := repository.FindApplicationByID("loan_application")
+	if err != nil {
+		t.Error(err)
+	}
+	repayments, err := repository.FindRepayments("loan")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Equal(t, "loan", loan.GeneratedID)
+	assert.Equal(t, 1, claimed)
+	assert.Equal(t, 0, claimedAgain)
+	assert.Equal(t, int64(100000), serviceBalance)
+	assert.Equal(t, int64(50000), found.Outstanding)
+	assert.Len(t, found.Installments, 2)
+	assert.Equal(t, domain.LoanInstallmentStatusPaid, found.Installments[0].Status)
+	assert.Equal(t, domain.LoanInstallmentStatusPending, found.Installments[1].Status)
+	assert.Equal(t, domain.LoanApplicationStatusDisbursed, application.Status)
+	assert.Equal(t, "loan", application.LoanID)
+	assert.Len(t, repayments, 1)
+}
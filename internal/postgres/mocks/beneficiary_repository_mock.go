@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: BeneficiaryRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockBeneficiaryRepository is a mock of BeneficiaryRepository interface
+type MockBeneficiaryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockBeneficiaryRepositoryMockRecorder
+}
+
+// MockBeneficiaryRepositoryMockRecorder is the mock recorder for MockBeneficiaryRepository
+type MockBeneficiaryRepositoryMockRecorder struct {
+	mock *MockBeneficiaryRepository
+}
+
+// NewMockBeneficiaryRepository creates a new mock instance
+func NewMockBeneficiaryRepository(ctrl *gomock.Controller) *MockBeneficiaryRepository {
+	mock := &MockBeneficiaryRepository{ctrl: ctrl}
+	mock.recorder = &MockBeneficiaryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockBeneficiaryRepository) EXPECT() *MockBeneficiaryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockBeneficiaryRepository) Create(arg0 *domain.Beneficiary) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockBeneficiaryRepositoryMockRecorder) Create(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockBeneficiaryRepository)(nil).Create), arg0)
+}
+
+// Delete mocks base method
+func (m *MockBeneficiaryRepository) Delete(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete
+func (mr *MockBeneficiaryRepositoryMockRecorder) Delete(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockBeneficiaryRepository)(nil).Delete), arg0)
+}
+
+// FindByCustomerID mocks base method
+func (m *MockBeneficiaryRepository) FindByCustomerID(arg0 string) ([]*domain.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCustomerID", arg0)
+	ret0, _ := ret[0].([]*domain.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCustomerID indicates an expected call of FindByCustomerID
+func (mr *MockBeneficiaryRepositoryMockRecorder) FindByCustomerID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCustomerID", reflect.TypeOf((*MockBeneficiaryRepository)(nil).FindByCustomerID), arg0)
+}
+
+// FindByID mocks base method
+func (m *MockBeneficiaryRepository) FindByID(arg0 string) (*domain.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret0, _ := ret[0].(*domain.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockBeneficiaryRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockBeneficiaryRepository)(nil).FindByID), arg0)
+}
+
+// FindByPayeeKey mocks base method
+func (m *MockBeneficiaryRepository) FindByPayeeKey(arg0, arg1 string) (*domain.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPayeeKey", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPayeeKey indicates an expected call of FindByPayeeKey
+func (mr *MockBeneficiaryRepositoryMockRecorder) FindByPayeeKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPayeeKey", reflect.TypeOf((*MockBeneficiaryRepository)(nil).FindByPayeeKey), arg0, arg1)
+}
+
+// UpdateNickname mocks base method
+func (m *MockBeneficiaryRepository) UpdateNickname(arg0, arg1 string, arg2 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNickname", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNickname indicates an expected call of UpdateNickname
+func (mr *MockBeneficiaryRepositoryMockRecorder) UpdateNickname(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNickname", reflect.TypeOf((*MockBeneficiaryRepository)(nil).UpdateNickname), arg0, arg1, arg2)
+}
@@ -0,0 +1,185 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: CardRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockCardRepository is a mock of CardRepository interface
+type MockCardRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCardRepositoryMockRecorder
+}
+
+// MockCardRepositoryMockRecorder is the mock recorder for MockCardRepository
+type MockCardRepositoryMockRecorder struct {
+	mock *MockCardRepository
+}
+
+// NewMockCardRepository creates a new mock instance
+func NewMockCardRepository(ctrl *gomock.Controller) *MockCardRepository {
+	mock := &MockCardRepository{ctrl: ctrl}
+	mock.recorder = &MockCardRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCardRepository) EXPECT() *MockCardRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Authorize mocks base method
+func (m *MockCardRepository) Authorize(arg0 string, arg1, arg2 time.Time, arg3 func(*domain.Card, *domain.AvailableBalance, int64, domain.LimitUsage) (*domain.CardAuthorization, error)) (*domain.CardAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authorize", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*domain.CardAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authorize indicates an expected call of Authorize
+func (mr *MockCardRepositoryMockRecorder) Authorize(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authorize", reflect.TypeOf((*MockCardRepository)(nil).Authorize), arg0, arg1, arg2, arg3)
+}
+
+// Create mocks base method
+func (m *MockCardRepository) Create(arg0 *domain.Card) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockCardRepositoryMockRecorder) Create(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCardRepository)(nil).Create), arg0)
+}
+
+// FindAuthorizationByID mocks base method
+func (m *MockCardRepository) FindAuthorizationByID(arg0 string) (*domain.CardAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAuthorizationByID", arg0)
+	ret0, _ := ret[0].(*domain.CardAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAuthorizationByID indicates an expected call of FindAuthorizationByID
+func (mr *MockCardRepositoryMockRecorder) FindAuthorizationByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAuthorizationByID", reflect.TypeOf((*MockCardRepository)(nil).FindAuthorizationByID), arg0)
+}
+
+// FindAuthorizationByNetworkReference mocks base method
+func (m *MockCardRepository) FindAuthorizationByNetworkReference(arg0 string) (*domain.CardAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAuthorizationByNetworkReference", arg0)
+	ret0, _ := ret[0].(*domain.CardAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAuthorizationByNetworkReference indicates an expected call of FindAuthorizationByNetworkReference
+func (mr *MockCardRepositoryMockRecorder) FindAuthorizationByNetworkReference(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAuthorizationByNetworkReference", reflect.TypeOf((*MockCardRepository)(nil).FindAuthorizationByNetworkReference), arg0)
+}
+
+// FindAuthorizationsByCardID mocks base method
+func (m *MockCardRepository) FindAuthorizationsByCardID(arg0 string) ([]*domain.CardAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAuthorizationsByCardID", arg0)
+	ret0, _ := ret[0].([]*domain.CardAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAuthorizationsByCardID indicates an expected call of FindAuthorizationsByCardID
+func (mr *MockCardRepositoryMockRecorder) FindAuthorizationsByCardID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAuthorizationsByCardID", reflect.TypeOf((*MockCardRepository)(nil).FindAuthorizationsByCardID), arg0)
+}
+
+// FindByCustomerID mocks base method
+func (m *MockCardRepository) FindByCustomerID(arg0 string) ([]*domain.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCustomerID", arg0)
+	ret0, _ := ret[0].([]*domain.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCustomerID indicates an expected call of FindByCustomerID
+func (mr *MockCardRepositoryMockRecorder) FindByCustomerID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCustomerID", reflect.TypeOf((*MockCardRepository)(nil).FindByCustomerID), arg0)
+}
+
+// FindByFingerprint mocks base method
+func (m *MockCardRepository) FindByFingerprint(arg0 string) (*domain.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByFingerprint", arg0)
+	ret0, _ := ret[0].(*domain.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByFingerprint indicates an expected call of FindByFingerprint
+func (mr *MockCardRepositoryMockRecorder) FindByFingerprint(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByFingerprint", reflect.TypeOf((*MockCardRepository)(nil).FindByFingerprint), arg0)
+}
+
+// FindByID mocks base method
+func (m *MockCardRepository) FindByID(arg0 string) (*domain.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret0, _ := ret[0].(*domain.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockCardRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCardRepository)(nil).FindByID), arg0)
+}
+
+// Update mocks base method
+func (m *MockCardRepository) Update(arg0 string, arg1 func(*domain.Card) error) (*domain.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockCardRepositoryMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCardRepository)(nil).Update), arg0, arg1)
+}
+
+// UpdateAuthorization mocks base method
+func (m *MockCardRepository) UpdateAuthorization(arg0 string, arg1 func(*domain.CardAuthorization) ([]*domain.Posting, error)) (*domain.CardAuthorization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthorization", arg0, arg1)
+	ret0, _ := ret[0].(*domain.CardAuthorization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAuthorization indicates an expected call of UpdateAuthorization
+func (mr *MockCardRepositoryMockRecorder) UpdateAuthorization(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthorization", reflect.TypeOf((*MockCardRepository)(nil).UpdateAuthorization), arg0, arg1)
+}
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: CreditLineRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockCreditLineRepository is a mock of CreditLineRepository interface
+type MockCreditLineRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCreditLineRepositoryMockRecorder
+}
+
+// MockCreditLineRepositoryMockRecorder is the mock recorder for MockCreditLineRepository
+type MockCreditLineRepositoryMockRecorder struct {
+	mock *MockCreditLineRepository
+}
+
+// NewMockCreditLineRepository creates a new mock instance
+func NewMockCreditLineRepository(ctrl *gomock.Controller) *MockCreditLineRepository {
+	mock := &MockCreditLineRepository{ctrl: ctrl}
+	mock.recorder = &MockCreditLineRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockCreditLineRepository) EXPECT() *MockCreditLineRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method
+func (m *MockCreditLineRepository) ClaimDue(arg0 time.Time, arg1 int, arg2 func(*domain.CreditLine, []*domain.ClosingBalance) ([]*domain.Posting, error)) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue
+func (mr *MockCreditLineRepositoryMockRecorder) ClaimDue(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockCreditLineRepository)(nil).ClaimDue), arg0, arg1, arg2)
+}
+
+// Find mocks base method
+func (m *MockCreditLineRepository) Find(arg0, arg1 string) (*domain.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", arg0, arg1)
+	ret0, _ := ret[0].(*domain.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find
+func (mr *MockCreditLineRepositoryMockRecorder) Find(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockCreditLineRepository)(nil).Find), arg0, arg1)
+}
+
+// FindAvailableBalance mocks base method
+func (m *MockCreditLineRepository) FindAvailableBalance(arg0, arg1 string) (*domain.AvailableBalance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAvailableBalance", arg0, arg1)
+	ret0, _ := ret[0].(*domain.AvailableBalance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAvailableBalance indicates an expected call of FindAvailableBalance
+func (mr *MockCreditLineRepositoryMockRecorder) FindAvailableBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAvailableBalance", reflect.TypeOf((*MockCreditLineRepository)(nil).FindAvailableBalance), arg0, arg1)
+}
+
+// FindByCustomerID mocks base method
+func (m *MockCreditLineRepository) FindByCustomerID(arg0 string) ([]*domain.CreditLine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCustomerID", arg0)
+	ret0, _ := ret[0].([]*domain.CreditLine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCustomerID indicates an expected call of FindByCustomerID
+func (mr *MockCreditLineRepositoryMockRecorder) FindByCustomerID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCustomerID", reflect.TypeOf((*MockCreditLineRepository)(nil).FindByCustomerID), arg0)
+}
+
+// Save mocks base method
+func (m *MockCreditLineRepository) Save(arg0 *domain.CreditLine) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save
+func (mr *MockCreditLineRepositoryMockRecorder) Save(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCreditLineRepository)(nil).Save), arg0)
+}
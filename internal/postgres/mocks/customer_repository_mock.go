@@ -76,6 +76,21 @@ func (mr *MockCustomerRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCustomerRepository)(nil).FindByID), arg0)
 }
 
+// FindByOGRN mocks base method
+func (m *MockCustomerRepository) FindByOGRN(arg0 string) (*domain.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOGRN", arg0)
+	ret0, _ := ret[0].(*domain.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOGRN indicates an expected call of FindByOGRN
+func (mr *MockCustomerRepositoryMockRecorder) FindByOGRN(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOGRN", reflect.TypeOf((*MockCustomerRepository)(nil).FindByOGRN), arg0)
+}
+
 // FindByPassportNumber mocks base method
 func (m *MockCustomerRepository) FindByPassportNumber(arg0 string) (*domain.Customer, error) {
 	m.ctrl.T.Helper()
@@ -91,6 +106,36 @@ func (mr *MockCustomerRepositoryMockRecorder) FindByPassportNumber(arg0 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPassportNumber", reflect.TypeOf((*MockCustomerRepository)(nil).FindByPassportNumber), arg0)
 }
 
+// FindByPhone mocks base method
+func (m *MockCustomerRepository) FindByPhone(arg0 string) (*domain.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPhone", arg0)
+	ret0, _ := ret[0].(*domain.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPhone indicates an expected call of FindByPhone
+func (mr *MockCustomerRepositoryMockRecorder) FindByPhone(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPhone", reflect.TypeOf((*MockCustomerRepository)(nil).FindByPhone), arg0)
+}
+
+// FindByStatus mocks base method
+func (m *MockCustomerRepository) FindByStatus(arg0 domain.CustomerStatus) ([]*domain.Customer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByStatus", arg0)
+	ret0, _ := ret[0].([]*domain.Customer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByStatus indicates an expected call of FindByStatus
+func (mr *MockCustomerRepositoryMockRecorder) FindByStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByStatus", reflect.TypeOf((*MockCustomerRepository)(nil).FindByStatus), arg0)
+}
+
 // Update mocks base method
 func (m *MockCustomerRepository) Update(arg0 *domain.Customer) error {
 	m.ctrl.T.Helper()
@@ -104,3 +149,17 @@ func (mr *MockCustomerRepositoryMockRecorder) Update(arg0 interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockCustomerRepository)(nil).Update), arg0)
 }
+
+// UpdatePhone mocks base method
+func (m *MockCustomerRepository) UpdatePhone(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePhone", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePhone indicates an expected call of UpdatePhone
+func (mr *MockCustomerRepositoryMockRecorder) UpdatePhone(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePhone", reflect.TypeOf((*MockCustomerRepository)(nil).UpdatePhone), arg0, arg1)
+}
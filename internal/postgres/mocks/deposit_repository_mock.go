@@ -0,0 +1,169 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: DepositRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockDepositRepository is a mock of DepositRepository interface
+type MockDepositRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDepositRepositoryMockRecorder
+}
+
+// MockDepositRepositoryMockRecorder is the mock recorder for MockDepositRepository
+type MockDepositRepositoryMockRecorder struct {
+	mock *MockDepositRepository
+}
+
+// NewMockDepositRepository creates a new mock instance
+func NewMockDepositRepository(ctrl *gomock.Controller) *MockDepositRepository {
+	mock := &MockDepositRepository{ctrl: ctrl}
+	mock.recorder = &MockDepositRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDepositRepository) EXPECT() *MockDepositRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method
+func (m *MockDepositRepository) ClaimDue(arg0 time.Time, arg1 int, arg2 func(*domain.Deposit) ([]*domain.DepositInterestEntry, []*domain.Posting, error)) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue
+func (mr *MockDepositRepositoryMockRecorder) ClaimDue(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockDepositRepository)(nil).ClaimDue), arg0, arg1, arg2)
+}
+
+// Create mocks base method
+func (m *MockDepositRepository) Create(arg0 *domain.Deposit, arg1 []*domain.Posting) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create
+func (mr *MockDepositRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDepositRepository)(nil).Create), arg0, arg1)
+}
+
+// CreateProduct mocks base method
+func (m *MockDepositRepository) CreateProduct(arg0 *domain.DepositProduct) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProduct", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProduct indicates an expected call of CreateProduct
+func (mr *MockDepositRepositoryMockRecorder) CreateProduct(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockDepositRepository)(nil).CreateProduct), arg0)
+}
+
+// FindByCustomerID mocks base method
+func (m *MockDepositRepository) FindByCustomerID(arg0 string) ([]*domain.Deposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCustomerID", arg0)
+	ret0, _ := ret[0].([]*domain.Deposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCustomerID indicates an expected call of FindByCustomerID
+func (mr *MockDepositRepositoryMockRecorder) FindByCustomerID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCustomerID", reflect.TypeOf((*MockDepositRepository)(nil).FindByCustomerID), arg0)
+}
+
+// FindByID mocks base method
+func (m *MockDepositRepository) FindByID(arg0 string) (*domain.Deposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret0, _ := ret[0].(*domain.Deposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockDepositRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockDepositRepository)(nil).FindByID), arg0)
+}
+
+// FindInterestEntries mocks base method
+func (m *MockDepositRepository) FindInterestEntries(arg0 string) ([]*domain.DepositInterestEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInterestEntries", arg0)
+	ret0, _ := ret[0].([]*domain.DepositInterestEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInterestEntries indicates an expected call of FindInterestEntries
+func (mr *MockDepositRepositoryMockRecorder) FindInterestEntries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInterestEntries", reflect.TypeOf((*MockDepositRepository)(nil).FindInterestEntries), arg0)
+}
+
+// FindProductByID mocks base method
+func (m *MockDepositRepository) FindProductByID(arg0 string) (*domain.DepositProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindProductByID", arg0)
+	ret0, _ := ret[0].(*domain.DepositProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindProductByID indicates an expected call of FindProductByID
+func (mr *MockDepositRepositoryMockRecorder) FindProductByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProductByID", reflect.TypeOf((*MockDepositRepository)(nil).FindProductByID), arg0)
+}
+
+// FindProducts mocks base method
+func (m *MockDepositRepository) FindProducts() ([]*domain.DepositProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindProducts")
+	ret0, _ := ret[0].([]*domain.DepositProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindProducts indicates an expected call of FindProducts
+func (mr *MockDepositRepositoryMockRecorder) FindProducts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProducts", reflect.TypeOf((*MockDepositRepository)(nil).FindProducts))
+}
+
+// Update mocks base method
+func (m *MockDepositRepository) Update(arg0 string, arg1 func(*domain.Deposit) ([]*domain.DepositInterestEntry, []*domain.Posting, error)) (*domain.Deposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Deposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockDepositRepositoryMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDepositRepository)(nil).Update), arg0, arg1)
+}
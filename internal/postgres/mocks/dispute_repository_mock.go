@@ -0,0 +1,153 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: DisputeRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockDisputeRepository is a mock of DisputeRepository interface
+type MockDisputeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDisputeRepositoryMockRecorder
+}
+
+// MockDisputeRepositoryMockRecorder is the mock recorder for MockDisputeRepository
+type MockDisputeRepositoryMockRecorder struct {
+	mock *MockDisputeRepository
+}
+
+// NewMockDisputeRepository creates a new mock instance
+func NewMockDisputeRepository(ctrl *gomock.Controller) *MockDisputeRepository {
+	mock := &MockDisputeRepository{ctrl: ctrl}
+	mock.recorder = &MockDisputeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockDisputeRepository) EXPECT() *MockDisputeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddEvidence mocks base method
+func (m *MockDisputeRepository) AddEvidence(arg0 *domain.DisputeEvidence) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEvidence", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEvidence indicates an expected call of AddEvidence
+func (mr *MockDisputeRepositoryMockRecorder) AddEvidence(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvidence", reflect.TypeOf((*MockDisputeRepository)(nil).AddEvidence), arg0)
+}
+
+// ClaimOverdue mocks base method
+func (m *MockDisputeRepository) ClaimOverdue(arg0 time.Time, arg1 int, arg2 func(*domain.Dispute) ([]*domain.Posting, error)) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOverdue", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOverdue indicates an expected call of ClaimOverdue
+func (mr *MockDisputeRepositoryMockRecorder) ClaimOverdue(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOverdue", reflect.TypeOf((*MockDisputeRepository)(nil).ClaimOverdue), arg0, arg1, arg2)
+}
+
+// Create mocks base method
+func (m *MockDisputeRepository) Create(arg0 *domain.Dispute, arg1 []*domain.Posting) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockDisputeRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDisputeRepository)(nil).Create), arg0, arg1)
+}
+
+// FindByID mocks base method
+func (m *MockDisputeRepository) FindByID(arg0 string) (*domain.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret0, _ := ret[0].(*domain.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockDisputeRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockDisputeRepository)(nil).FindByID), arg0)
+}
+
+// FindByMerchantID mocks base method
+func (m *MockDisputeRepository) FindByMerchantID(arg0 string) ([]*domain.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByMerchantID", arg0)
+	ret0, _ := ret[0].([]*domain.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByMerchantID indicates an expected call of FindByMerchantID
+func (mr *MockDisputeRepositoryMockRecorder) FindByMerchantID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByMerchantID", reflect.TypeOf((*MockDisputeRepository)(nil).FindByMerchantID), arg0)
+}
+
+// FindByPaymentID mocks base method
+func (m *MockDisputeRepository) FindByPaymentID(arg0 string) (*domain.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByPaymentID", arg0)
+	ret0, _ := ret[0].(*domain.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByPaymentID indicates an expected call of FindByPaymentID
+func (mr *MockDisputeRepositoryMockRecorder) FindByPaymentID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByPaymentID", reflect.TypeOf((*MockDisputeRepository)(nil).FindByPaymentID), arg0)
+}
+
+// FindEvidence mocks base method
+func (m *MockDisputeRepository) FindEvidence(arg0 string) ([]*domain.DisputeEvidence, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindEvidence", arg0)
+	ret0, _ := ret[0].([]*domain.DisputeEvidence)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEvidence indicates an expected call of FindEvidence
+func (mr *MockDisputeRepositoryMockRecorder) FindEvidence(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEvidence", reflect.TypeOf((*MockDisputeRepository)(nil).FindEvidence), arg0)
+}
+
+// Update mocks base method
+func (m *MockDisputeRepository) Update(arg0 string, arg1 func(*domain.Dispute) ([]*domain.Posting, error)) (*domain.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockDisputeRepositoryMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockDisputeRepository)(nil).Update), arg0, arg1)
+}
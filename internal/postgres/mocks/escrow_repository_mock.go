@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: EscrowRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockEscrowRepository is a mock of EscrowRepository interface
+type MockEscrowRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEscrowRepositoryMockRecorder
+}
+
+// MockEscrowRepositoryMockRecorder is the mock recorder for MockEscrowRepository
+type MockEscrowRepositoryMockRecorder struct {
+	mock *MockEscrowRepository
+}
+
+// NewMockEscrowRepository creates a new mock instance
+func NewMockEscrowRepository(ctrl *gomock.Controller) *MockEscrowRepository {
+	mock := &MockEscrowRepository{ctrl: ctrl}
+	mock.recorder = &MockEscrowRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockEscrowRepository) EXPECT() *MockEscrowRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimExpired mocks base method
+func (m *MockEscrowRepository) ClaimExpired(arg0 time.Time, arg1 int, arg2 func(*domain.Escrow) ([]*domain.Debit, error)) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimExpired", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimExpired indicates an expected call of ClaimExpired
+func (mr *MockEscrowRepositoryMockRecorder) ClaimExpired(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimExpired", reflect.TypeOf((*MockEscrowRepository)(nil).ClaimExpired), arg0, arg1, arg2)
+}
+
+// Create mocks base method
+func (m *MockEscrowRepository) Create(arg0 *domain.Escrow, arg1 *domain.Debit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockEscrowRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEscrowRepository)(nil).Create), arg0, arg1)
+}
+
+// FindByID mocks base method
+func (m *MockEscrowRepository) FindByID(arg0 string) (*domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret0, _ := ret[0].(*domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockEscrowRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockEscrowRepository)(nil).FindByID), arg0)
+}
+
+// FindByMerchantID mocks base method
+func (m *MockEscrowRepository) FindByMerchantID(arg0 string) ([]*domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByMerchantID", arg0)
+	ret0, _ := ret[0].([]*domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByMerchantID indicates an expected call of FindByMerchantID
+func (mr *MockEscrowRepositoryMockRecorder) FindByMerchantID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByMerchantID", reflect.TypeOf((*MockEscrowRepository)(nil).FindByMerchantID), arg0)
+}
+
+// FindByOrderID mocks base method
+func (m *MockEscrowRepository) FindByOrderID(arg0, arg1 string) (*domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByOrderID", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByOrderID indicates an expected call of FindByOrderID
+func (mr *MockEscrowRepositoryMockRecorder) FindByOrderID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByOrderID", reflect.TypeOf((*MockEscrowRepository)(nil).FindByOrderID), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockEscrowRepository) Update(arg0 string, arg1 func(*domain.Escrow) ([]*domain.Debit, error)) (*domain.Escrow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Escrow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockEscrowRepositoryMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockEscrowRepository)(nil).Update), arg0, arg1)
+}
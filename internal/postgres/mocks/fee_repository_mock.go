@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: FeeRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockFeeRepository is a mock of FeeRepository interface
+type MockFeeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockFeeRepositoryMockRecorder
+}
+
+// MockFeeRepositoryMockRecorder is the mock recorder for MockFeeRepository
+type MockFeeRepositoryMockRecorder struct {
+	mock *MockFeeRepository
+}
+
+// NewMockFeeRepository creates a new mock instance
+func NewMockFeeRepository(ctrl *gomock.Controller) *MockFeeRepository {
+	mock := &MockFeeRepository{ctrl: ctrl}
+	mock.recorder = &MockFeeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockFeeRepository) EXPECT() *MockFeeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSchedule mocks base method
+func (m *MockFeeRepository) CreateSchedule(arg0 *domain.FeeSchedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSchedule", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSchedule indicates an expected call of CreateSchedule
+func (mr *MockFeeRepositoryMockRecorder) CreateSchedule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchedule", reflect.TypeOf((*MockFeeRepository)(nil).CreateSchedule), arg0)
+}
+
+// FindEffectiveSchedule mocks base method
+func (m *MockFeeRepository) FindEffectiveSchedule(arg0 time.Time) (*domain.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindEffectiveSchedule", arg0)
+	ret0, _ := ret[0].(*domain.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEffectiveSchedule indicates an expected call of FindEffectiveSchedule
+func (mr *MockFeeRepositoryMockRecorder) FindEffectiveSchedule(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEffectiveSchedule", reflect.TypeOf((*MockFeeRepository)(nil).FindEffectiveSchedule), arg0)
+}
+
+// FindLatestSchedule mocks base method
+func (m *MockFeeRepository) FindLatestSchedule() (*domain.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLatestSchedule")
+	ret0, _ := ret[0].(*domain.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLatestSchedule indicates an expected call of FindLatestSchedule
+func (mr *MockFeeRepositoryMockRecorder) FindLatestSchedule() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLatestSchedule", reflect.TypeOf((*MockFeeRepository)(nil).FindLatestSchedule))
+}
+
+// FindScheduleByVersion mocks base method
+func (m *MockFeeRepository) FindScheduleByVersion(arg0 int) (*domain.FeeSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindScheduleByVersion", arg0)
+	ret0, _ := ret[0].(*domain.FeeSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindScheduleByVersion indicates an expected call of FindScheduleByVersion
+func (mr *MockFeeRepositoryMockRecorder) FindScheduleByVersion(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindScheduleByVersion", reflect.TypeOf((*MockFeeRepository)(nil).FindScheduleByVersion), arg0)
+}
@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: InvoiceRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+)
+
+// MockInvoiceRepository is a mock of InvoiceRepository interface
+type MockInvoiceRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvoiceRepositoryMockRecorder
+}
+
+// MockInvoiceRepositoryMockRecorder is the mock recorder for MockInvoiceRepository
+type MockInvoiceRepositoryMockRecorder struct {
+	mock *MockInvoiceRepository
+}
+
+// NewMockInvoiceRepository creates a new mock instance
+func NewMockInvoiceRepository(ctrl *gomock.Controller) *MockInvoiceRepository {
+	mock := &MockInvoiceRepository{ctrl: ctrl}
+	mock.recorder = &MockInvoiceRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockInvoiceRepository) EXPECT() *MockInvoiceRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockInvoiceRepository) Create(arg0 *domain.Invoice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockInvoiceRepositoryMockRecorder) Create(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInvoiceRepository)(nil).Create), arg0)
+}
+
+// Finalize mocks base method
+func (m *MockInvoiceRepository) Finalize(arg0 *domain.Invoice) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Finalize", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Finalize indicates an expected call of Finalize
+func (mr *MockInvoiceRepositoryMockRecorder) Finalize(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Finalize", reflect.TypeOf((*MockInvoiceRepository)(nil).Finalize), arg0)
+}
+
+// FindByCustomerID mocks base method
+func (m *MockInvoiceRepository) FindByCustomerID(arg0 string) ([]*domain.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCustomerID", arg0)
+	ret0, _ := ret[0].([]*domain.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCustomerID indicates an expected call of FindByCustomerID
+func (mr *MockInvoiceRepositoryMockRecorder) FindByCustomerID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCustomerID", reflect.TypeOf((*MockInvoiceRepository)(nil).FindByCustomerID), arg0)
+}
+
+// FindByID mocks base method
+func (m *MockInvoiceRepository) FindByID(arg0 string) (*domain.Invoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret0, _ := ret[0].(*domain.Invoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockInvoiceRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockInvoiceRepository)(nil).FindByID), arg0)
+}
+
+// Pay mocks base method
+func (m *MockInvoiceRepository) Pay(arg0 *domain.Invoice, arg1 *domain.Debit) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pay", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pay indicates an expected call of Pay
+func (mr *MockInvoiceRepositoryMockRecorder) Pay(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pay", reflect.TypeOf((*MockInvoiceRepository)(nil).Pay), arg0, arg1)
+}
+
+// Update mocks base method
+func (m *MockInvoiceRepository) Update(arg0 *domain.Invoice, arg1 domain.InvoiceStatus) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockInvoiceRepositoryMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockInvoiceRepository)(nil).Update), arg0, arg1)
+}
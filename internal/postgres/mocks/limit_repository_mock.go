@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: LimitRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockLimitRepository is a mock of LimitRepository interface
+type MockLimitRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLimitRepositoryMockRecorder
+}
+
+// MockLimitRepositoryMockRecorder is the mock recorder for MockLimitRepository
+type MockLimitRepositoryMockRecorder struct {
+	mock *MockLimitRepository
+}
+
+// NewMockLimitRepository creates a new mock instance
+func NewMockLimitRepository(ctrl *gomock.Controller) *MockLimitRepository {
+	mock := &MockLimitRepository{ctrl: ctrl}
+	mock.recorder = &MockLimitRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLimitRepository) EXPECT() *MockLimitRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteCustomerLimits mocks base method
+func (m *MockLimitRepository) DeleteCustomerLimits(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCustomerLimits", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCustomerLimits indicates an expected call of DeleteCustomerLimits
+func (mr *MockLimitRepositoryMockRecorder) DeleteCustomerLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCustomerLimits", reflect.TypeOf((*MockLimitRepository)(nil).DeleteCustomerLimits), arg0, arg1)
+}
+
+// FindCustomerLimits mocks base method
+func (m *MockLimitRepository) FindCustomerLimits(arg0, arg1 string) (*domain.TransactionLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCustomerLimits", arg0, arg1)
+	ret0, _ := ret[0].(*domain.TransactionLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCustomerLimits indicates an expected call of FindCustomerLimits
+func (mr *MockLimitRepositoryMockRecorder) FindCustomerLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCustomerLimits", reflect.TypeOf((*MockLimitRepository)(nil).FindCustomerLimits), arg0, arg1)
+}
+
+// FindTierLimits mocks base method
+func (m *MockLimitRepository) FindTierLimits(arg0 domain.VerificationLevel, arg1 string) (*domain.TransactionLimits, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTierLimits", arg0, arg1)
+	ret0, _ := ret[0].(*domain.TransactionLimits)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTierLimits indicates an expected call of FindTierLimits
+func (mr *MockLimitRepositoryMockRecorder) FindTierLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTierLimits", reflect.TypeOf((*MockLimitRepository)(nil).FindTierLimits), arg0, arg1)
+}
+
+// FindUsage mocks base method
+func (m *MockLimitRepository) FindUsage(arg0, arg1 string, arg2 time.Time) (domain.LimitUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUsage", arg0, arg1, arg2)
+	ret0, _ := ret[0].(domain.LimitUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUsage indicates an expected call of FindUsage
+func (mr *MockLimitRepositoryMockRecorder) FindUsage(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUsage", reflect.TypeOf((*MockLimitRepository)(nil).FindUsage), arg0, arg1, arg2)
+}
+
+// SaveCustomerLimits mocks base method
+func (m *MockLimitRepository) SaveCustomerLimits(arg0, arg1 string, arg2 domain.TransactionLimits) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCustomerLimits", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCustomerLimits indicates an expected call of SaveCustomerLimits
+func (mr *MockLimitRepositoryMockRecorder) SaveCustomerLimits(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCustomerLimits", reflect.TypeOf((*MockLimitRepository)(nil).SaveCustomerLimits), arg0, arg1, arg2)
+}
@@ -0,0 +1,213 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: LoanRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockLoanRepository is a mock of LoanRepository interface
+type MockLoanRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoanRepositoryMockRecorder
+}
+
+// MockLoanRepositoryMockRecorder is the mock recorder for MockLoanRepository
+type MockLoanRepositoryMockRecorder struct {
+	mock *MockLoanRepository
+}
+
+// NewMockLoanRepository creates a new mock instance
+func NewMockLoanRepository(ctrl *gomock.Controller) *MockLoanRepository {
+	mock := &MockLoanRepository{ctrl: ctrl}
+	mock.recorder = &MockLoanRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockLoanRepository) EXPECT() *MockLoanRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method
+func (m *MockLoanRepository) ClaimDue(arg0 time.Time, arg1 int, arg2 func(*domain.Loan, int64) (*domain.LoanRepayment, []*domain.Posting, error)) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue
+func (mr *MockLoanRepositoryMockRecorder) ClaimDue(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockLoanRepository)(nil).ClaimDue), arg0, arg1, arg2)
+}
+
+// CreateApplication mocks base method
+func (m *MockLoanRepository) CreateApplication(arg0 *domain.LoanApplication) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApplication", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateApplication indicates an expected call of CreateApplication
+func (mr *MockLoanRepositoryMockRecorder) CreateApplication(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplication", reflect.TypeOf((*MockLoanRepository)(nil).CreateApplication), arg0)
+}
+
+// CreateProduct mocks base method
+func (m *MockLoanRepository) CreateProduct(arg0 *domain.LoanProduct) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProduct", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProduct indicates an expected call of CreateProduct
+func (mr *MockLoanRepositoryMockRecorder) CreateProduct(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockLoanRepository)(nil).CreateProduct), arg0)
+}
+
+// Disburse mocks base method
+func (m *MockLoanRepository) Disburse(arg0 string, arg1 func(*domain.LoanApplication) (*domain.Loan, []*domain.Posting, error)) (*domain.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Disburse", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Disburse indicates an expected call of Disburse
+func (mr *MockLoanRepositoryMockRecorder) Disburse(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disburse", reflect.TypeOf((*MockLoanRepository)(nil).Disburse), arg0, arg1)
+}
+
+// FindApplicationByID mocks base method
+func (m *MockLoanRepository) FindApplicationByID(arg0 string) (*domain.LoanApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindApplicationByID", arg0)
+	ret0, _ := ret[0].(*domain.LoanApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindApplicationByID indicates an expected call of FindApplicationByID
+func (mr *MockLoanRepositoryMockRecorder) FindApplicationByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindApplicationByID", reflect.TypeOf((*MockLoanRepository)(nil).FindApplicationByID), arg0)
+}
+
+// FindApplicationsByCustomerID mocks base method
+func (m *MockLoanRepository) FindApplicationsByCustomerID(arg0 string) ([]*domain.LoanApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindApplicationsByCustomerID", arg0)
+	ret0, _ := ret[0].([]*domain.LoanApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindApplicationsByCustomerID indicates an expected call of FindApplicationsByCustomerID
+func (mr *MockLoanRepositoryMockRecorder) FindApplicationsByCustomerID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindApplicationsByCustomerID", reflect.TypeOf((*MockLoanRepository)(nil).FindApplicationsByCustomerID), arg0)
+}
+
+// FindByCustomerID mocks base method
+func (m *MockLoanRepository) FindByCustomerID(arg0 string) ([]*domain.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCustomerID", arg0)
+	ret0, _ := ret[0].([]*domain.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCustomerID indicates an expected call of FindByCustomerID
+func (mr *MockLoanRepositoryMockRecorder) FindByCustomerID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCustomerID", reflect.TypeOf((*MockLoanRepository)(nil).FindByCustomerID), arg0)
+}
+
+// FindByID mocks base method
+func (m *MockLoanRepository) FindByID(arg0 string) (*domain.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret0, _ := ret[0].(*domain.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockLoanRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockLoanRepository)(nil).FindByID), arg0)
+}
+
+// FindProductByID mocks base method
+func (m *MockLoanRepository) FindProductByID(arg0 string) (*domain.LoanProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindProductByID", arg0)
+	ret0, _ := ret[0].(*domain.LoanProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindProductByID indicates an expected call of FindProductByID
+func (mr *MockLoanRepositoryMockRecorder) FindProductByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProductByID", reflect.TypeOf((*MockLoanRepository)(nil).FindProductByID), arg0)
+}
+
+// FindProducts mocks base method
+func (m *MockLoanRepository) FindProducts() ([]*domain.LoanProduct, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindProducts")
+	ret0, _ := ret[0].([]*domain.LoanProduct)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindProducts indicates an expected call of FindProducts
+func (mr *MockLoanRepositoryMockRecorder) FindProducts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindProducts", reflect.TypeOf((*MockLoanRepository)(nil).FindProducts))
+}
+
+// FindRepayments mocks base method
+func (m *MockLoanRepository) FindRepayments(arg0 string) ([]*domain.LoanRepayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRepayments", arg0)
+	ret0, _ := ret[0].([]*domain.LoanRepayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRepayments indicates an expected call of FindRepayments
+func (mr *MockLoanRepositoryMockRecorder) FindRepayments(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRepayments", reflect.TypeOf((*MockLoanRepository)(nil).FindRepayments), arg0)
+}
+
+// Update mocks base method
+func (m *MockLoanRepository) Update(arg0 string, arg1 func(*domain.Loan, int64) (*domain.LoanRepayment, []*domain.Posting, error)) (*domain.Loan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Loan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Update indicates an expected call of Update
+func (mr *MockLoanRepositoryMockRecorder) Update(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockLoanRepository)(nil).Update), arg0, arg1)
+}
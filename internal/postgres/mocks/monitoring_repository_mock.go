@@ -0,0 +1,122 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: MonitoringRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockMonitoringRepository is a mock of MonitoringRepository interface
+type MockMonitoringRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMonitoringRepositoryMockRecorder
+}
+
+// MockMonitoringRepositoryMockRecorder is the mock recorder for MockMonitoringRepository
+type MockMonitoringRepositoryMockRecorder struct {
+	mock *MockMonitoringRepository
+}
+
+// NewMockMonitoringRepository creates a new mock instance
+func NewMockMonitoringRepository(ctrl *gomock.Controller) *MockMonitoringRepository {
+	mock := &MockMonitoringRepository{ctrl: ctrl}
+	mock.recorder = &MockMonitoringRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMonitoringRepository) EXPECT() *MockMonitoringRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAlerts mocks base method
+func (m *MockMonitoringRepository) CreateAlerts(arg0 []*domain.Alert) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAlerts", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAlerts indicates an expected call of CreateAlerts
+func (mr *MockMonitoringRepositoryMockRecorder) CreateAlerts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAlerts", reflect.TypeOf((*MockMonitoringRepository)(nil).CreateAlerts), arg0)
+}
+
+// CreateMovement mocks base method
+func (m *MockMonitoringRepository) CreateMovement(arg0 *domain.Movement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMovement", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMovement indicates an expected call of CreateMovement
+func (mr *MockMonitoringRepositoryMockRecorder) CreateMovement(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMovement", reflect.TypeOf((*MockMonitoringRepository)(nil).CreateMovement), arg0)
+}
+
+// FindAlertByID mocks base method
+func (m *MockMonitoringRepository) FindAlertByID(arg0 string) (*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAlertByID", arg0)
+	ret0, _ := ret[0].(*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAlertByID indicates an expected call of FindAlertByID
+func (mr *MockMonitoringRepositoryMockRecorder) FindAlertByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAlertByID", reflect.TypeOf((*MockMonitoringRepository)(nil).FindAlertByID), arg0)
+}
+
+// FindAlertsByStatus mocks base method
+func (m *MockMonitoringRepository) FindAlertsByStatus(arg0 domain.AlertStatus) ([]*domain.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAlertsByStatus", arg0)
+	ret0, _ := ret[0].([]*domain.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAlertsByStatus indicates an expected call of FindAlertsByStatus
+func (mr *MockMonitoringRepositoryMockRecorder) FindAlertsByStatus(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAlertsByStatus", reflect.TypeOf((*MockMonitoringRepository)(nil).FindAlertsByStatus), arg0)
+}
+
+// FindMovements mocks base method
+func (m *MockMonitoringRepository) FindMovements(arg0 string, arg1 time.Time) ([]*domain.Movement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMovements", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.Movement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMovements indicates an expected call of FindMovements
+func (mr *MockMonitoringRepositoryMockRecorder) FindMovements(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMovements", reflect.TypeOf((*MockMonitoringRepository)(nil).FindMovements), arg0, arg1)
+}
+
+// UpdateAlert mocks base method
+func (m *MockMonitoringRepository) UpdateAlert(arg0 *domain.Alert) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAlert", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAlert indicates an expected call of UpdateAlert
+func (mr *MockMonitoringRepositoryMockRecorder) UpdateAlert(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAlert", reflect.TypeOf((*MockMonitoringRepository)(nil).UpdateAlert), arg0)
+}
@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: P2PRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockP2PRepository is a mock of P2PRepository interface
+type MockP2PRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockP2PRepositoryMockRecorder
+}
+
+// MockP2PRepositoryMockRecorder is the mock recorder for MockP2PRepository
+type MockP2PRepositoryMockRecorder struct {
+	mock *MockP2PRepository
+}
+
+// NewMockP2PRepository creates a new mock instance
+func NewMockP2PRepository(ctrl *gomock.Controller) *MockP2PRepository {
+	mock := &MockP2PRepository{ctrl: ctrl}
+	mock.recorder = &MockP2PRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockP2PRepository) EXPECT() *MockP2PRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountLookedUpPhones mocks base method
+func (m *MockP2PRepository) CountLookedUpPhones(arg0, arg1 string, arg2 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLookedUpPhones", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLookedUpPhones indicates an expected call of CountLookedUpPhones
+func (mr *MockP2PRepositoryMockRecorder) CountLookedUpPhones(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLookedUpPhones", reflect.TypeOf((*MockP2PRepository)(nil).CountLookedUpPhones), arg0, arg1, arg2)
+}
+
+// CreatePhoneLookup mocks base method
+func (m *MockP2PRepository) CreatePhoneLookup(arg0 *domain.PhoneLookup) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePhoneLookup", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePhoneLookup indicates an expected call of CreatePhoneLookup
+func (mr *MockP2PRepositoryMockRecorder) CreatePhoneLookup(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePhoneLookup", reflect.TypeOf((*MockP2PRepository)(nil).CreatePhoneLookup), arg0)
+}
+
+// CreateTransfer mocks base method
+func (m *MockP2PRepository) CreateTransfer(arg0 *domain.P2PTransfer, arg1 *domain.Debit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer
+func (mr *MockP2PRepositoryMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockP2PRepository)(nil).CreateTransfer), arg0, arg1)
+}
+
+// FindTransferByID mocks base method
+func (m *MockP2PRepository) FindTransferByID(arg0 string) (*domain.P2PTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindTransferByID", arg0)
+	ret0, _ := ret[0].(*domain.P2PTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindTransferByID indicates an expected call of FindTransferByID
+func (mr *MockP2PRepositoryMockRecorder) FindTransferByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindTransferByID", reflect.TypeOf((*MockP2PRepository)(nil).FindTransferByID), arg0)
+}
@@ -0,0 +1,152 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: PaymentScheduleRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockPaymentScheduleRepository is a mock of PaymentScheduleRepository interface
+type MockPaymentScheduleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentScheduleRepositoryMockRecorder
+}
+
+// MockPaymentScheduleRepositoryMockRecorder is the mock recorder for MockPaymentScheduleRepository
+type MockPaymentScheduleRepositoryMockRecorder struct {
+	mock *MockPaymentScheduleRepository
+}
+
+// NewMockPaymentScheduleRepository creates a new mock instance
+func NewMockPaymentScheduleRepository(ctrl *gomock.Controller) *MockPaymentScheduleRepository {
+	mock := &MockPaymentScheduleRepository{ctrl: ctrl}
+	mock.recorder = &MockPaymentScheduleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPaymentScheduleRepository) EXPECT() *MockPaymentScheduleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueSchedules mocks base method
+func (m *MockPaymentScheduleRepository) ClaimDueSchedules(arg0 time.Time, arg1 int, arg2 func(*domain.PaymentSchedule) *domain.ScheduleExecution) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueSchedules", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueSchedules indicates an expected call of ClaimDueSchedules
+func (mr *MockPaymentScheduleRepositoryMockRecorder) ClaimDueSchedules(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueSchedules", reflect.TypeOf((*MockPaymentScheduleRepository)(nil).ClaimDueSchedules), arg0, arg1, arg2)
+}
+
+// ClaimExecutions mocks base method
+func (m *MockPaymentScheduleRepository) ClaimExecutions(arg0, arg1 time.Time, arg2 int) ([]*domain.ScheduleExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimExecutions", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.ScheduleExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimExecutions indicates an expected call of ClaimExecutions
+func (mr *MockPaymentScheduleRepositoryMockRecorder) ClaimExecutions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimExecutions", reflect.TypeOf((*MockPaymentScheduleRepository)(nil).ClaimExecutions), arg0, arg1, arg2)
+}
+
+// Create mocks base method
+func (m *MockPaymentScheduleRepository) Create(arg0 *domain.PaymentSchedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockPaymentScheduleRepositoryMockRecorder) Create(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPaymentScheduleRepository)(nil).Create), arg0)
+}
+
+// FindByCustomerID mocks base method
+func (m *MockPaymentScheduleRepository) FindByCustomerID(arg0 string) ([]*domain.PaymentSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCustomerID", arg0)
+	ret0, _ := ret[0].([]*domain.PaymentSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCustomerID indicates an expected call of FindByCustomerID
+func (mr *MockPaymentScheduleRepositoryMockRecorder) FindByCustomerID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCustomerID", reflect.TypeOf((*MockPaymentScheduleRepository)(nil).FindByCustomerID), arg0)
+}
+
+// FindByID mocks base method
+func (m *MockPaymentScheduleRepository) FindByID(arg0 string) (*domain.PaymentSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret0, _ := ret[0].(*domain.PaymentSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockPaymentScheduleRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockPaymentScheduleRepository)(nil).FindByID), arg0)
+}
+
+// FindExecutions mocks base method
+func (m *MockPaymentScheduleRepository) FindExecutions(arg0 string) ([]*domain.ScheduleExecution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindExecutions", arg0)
+	ret0, _ := ret[0].([]*domain.ScheduleExecution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindExecutions indicates an expected call of FindExecutions
+func (mr *MockPaymentScheduleRepositoryMockRecorder) FindExecutions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindExecutions", reflect.TypeOf((*MockPaymentScheduleRepository)(nil).FindExecutions), arg0)
+}
+
+// Update mocks base method
+func (m *MockPaymentScheduleRepository) Update(arg0 *domain.PaymentSchedule) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockPaymentScheduleRepositoryMockRecorder) Update(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockPaymentScheduleRepository)(nil).Update), arg0)
+}
+
+// UpdateExecution mocks base method
+func (m *MockPaymentScheduleRepository) UpdateExecution(arg0 *domain.ScheduleExecution) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExecution", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateExecution indicates an expected call of UpdateExecution
+func (mr *MockPaymentScheduleRepositoryMockRecorder) UpdateExecution(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExecution", reflect.TypeOf((*MockPaymentScheduleRepository)(nil).UpdateExecution), arg0)
+}
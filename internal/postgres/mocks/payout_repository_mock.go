@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: PayoutRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockPayoutRepository is a mock of PayoutRepository interface
+type MockPayoutRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPayoutRepositoryMockRecorder
+}
+
+// MockPayoutRepositoryMockRecorder is the mock recorder for MockPayoutRepository
+type MockPayoutRepositoryMockRecorder struct {
+	mock *MockPayoutRepository
+}
+
+// NewMockPayoutRepository creates a new mock instance
+func NewMockPayoutRepository(ctrl *gomock.Controller) *MockPayoutRepository {
+	mock := &MockPayoutRepository{ctrl: ctrl}
+	mock.recorder = &MockPayoutRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPayoutRepository) EXPECT() *MockPayoutRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockPayoutRepository) Create(arg0 *domain.Payout, arg1 *domain.Debit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockPayoutRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPayoutRepository)(nil).Create), arg0, arg1)
+}
+
+// FindBatchByID mocks base method
+func (m *MockPayoutRepository) FindBatchByID(arg0 string) (*domain.PayoutBatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBatchByID", arg0)
+	ret0, _ := ret[0].(*domain.PayoutBatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBatchByID indicates an expected call of FindBatchByID
+func (mr *MockPayoutRepositoryMockRecorder) FindBatchByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBatchByID", reflect.TypeOf((*MockPayoutRepository)(nil).FindBatchByID), arg0)
+}
+
+// FindByCustomerID mocks base method
+func (m *MockPayoutRepository) FindByCustomerID(arg0 string) ([]*domain.Payout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByCustomerID", arg0)
+	ret0, _ := ret[0].([]*domain.Payout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByCustomerID indicates an expected call of FindByCustomerID
+func (mr *MockPayoutRepositoryMockRecorder) FindByCustomerID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByCustomerID", reflect.TypeOf((*MockPayoutRepository)(nil).FindByCustomerID), arg0)
+}
+
+// FindByID mocks base method
+func (m *MockPayoutRepository) FindByID(arg0 string) (*domain.Payout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret0, _ := ret[0].(*domain.Payout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockPayoutRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockPayoutRepository)(nil).FindByID), arg0)
+}
+
+// SubmitPending mocks base method
+func (m *MockPayoutRepository) SubmitPending(arg0 int, arg1 func([]*domain.Payout) (*domain.PayoutBatch, error)) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitPending", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitPending indicates an expected call of SubmitPending
+func (mr *MockPayoutRepositoryMockRecorder) SubmitPending(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitPending", reflect.TypeOf((*MockPayoutRepository)(nil).SubmitPending), arg0, arg1)
+}
+
+// UpdateSubmitted mocks base method
+func (m *MockPayoutRepository) UpdateSubmitted(arg0 *domain.PayoutStatusUpdate, arg1 time.Time, arg2 func(*domain.Payout) (*domain.Debit, error)) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSubmitted", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSubmitted indicates an expected call of UpdateSubmitted
+func (mr *MockPayoutRepositoryMockRecorder) UpdateSubmitted(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubmitted", reflect.TypeOf((*MockPayoutRepository)(nil).UpdateSubmitted), arg0, arg1, arg2)
+}
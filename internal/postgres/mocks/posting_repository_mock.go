@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: PostingRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockPostingRepository is a mock of PostingRepository interface
+type MockPostingRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPostingRepositoryMockRecorder
+}
+
+// MockPostingRepositoryMockRecorder is the mock recorder for MockPostingRepository
+type MockPostingRepositoryMockRecorder struct {
+	mock *MockPostingRepository
+}
+
+// NewMockPostingRepository creates a new mock instance
+func NewMockPostingRepository(ctrl *gomock.Controller) *MockPostingRepository {
+	mock := &MockPostingRepository{ctrl: ctrl}
+	mock.recorder = &MockPostingRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPostingRepository) EXPECT() *MockPostingRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockPostingRepository) Create(arg0 *domain.Posting) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockPostingRepositoryMockRecorder) Create(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPostingRepository)(nil).Create), arg0)
+}
+
+// FindBalance mocks base method
+func (m *MockPostingRepository) FindBalance(arg0, arg1 string, arg2 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBalance", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBalance indicates an expected call of FindBalance
+func (mr *MockPostingRepositoryMockRecorder) FindBalance(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBalance", reflect.TypeOf((*MockPostingRepository)(nil).FindBalance), arg0, arg1, arg2)
+}
+
+// FindByReference mocks base method
+func (m *MockPostingRepository) FindByReference(arg0 string) ([]*domain.Posting, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByReference", arg0)
+	ret0, _ := ret[0].([]*domain.Posting)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByReference indicates an expected call of FindByReference
+func (mr *MockPostingRepositoryMockRecorder) FindByReference(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByReference", reflect.TypeOf((*MockPostingRepository)(nil).FindByReference), arg0)
+}
+
+// StreamPostings mocks base method
+func (m *MockPostingRepository) StreamPostings(arg0, arg1 string, arg2, arg3 time.Time, arg4 func(*domain.Posting) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamPostings", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamPostings indicates an expected call of StreamPostings
+func (mr *MockPostingRepositoryMockRecorder) StreamPostings(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamPostings", reflect.TypeOf((*MockPostingRepository)(nil).StreamPostings), arg0, arg1, arg2, arg3, arg4)
+}
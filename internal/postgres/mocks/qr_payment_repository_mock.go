@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: QRPaymentRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+)
+
+// MockQRPaymentRepository is a mock of QRPaymentRepository interface
+type MockQRPaymentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockQRPaymentRepositoryMockRecorder
+}
+
+// MockQRPaymentRepositoryMockRecorder is the mock recorder for MockQRPaymentRepository
+type MockQRPaymentRepositoryMockRecorder struct {
+	mock *MockQRPaymentRepository
+}
+
+// NewMockQRPaymentRepository creates a new mock instance
+func NewMockQRPaymentRepository(ctrl *gomock.Controller) *MockQRPaymentRepository {
+	mock := &MockQRPaymentRepository{ctrl: ctrl}
+	mock.recorder = &MockQRPaymentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockQRPaymentRepository) EXPECT() *MockQRPaymentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddPayment mocks base method
+func (m *MockQRPaymentRepository) AddPayment(arg0 *domain.QRPayment, arg1 domain.QRPaymentRequestStatus, arg2 *domain.Debit) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPayment", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddPayment indicates an expected call of AddPayment
+func (mr *MockQRPaymentRepositoryMockRecorder) AddPayment(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPayment", reflect.TypeOf((*MockQRPaymentRepository)(nil).AddPayment), arg0, arg1, arg2)
+}
+
+// CreateRequest mocks base method
+func (m *MockQRPaymentRepository) CreateRequest(arg0 *domain.QRPaymentRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRequest", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRequest indicates an expected call of CreateRequest
+func (mr *MockQRPaymentRepositoryMockRecorder) CreateRequest(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRequest", reflect.TypeOf((*MockQRPaymentRepository)(nil).CreateRequest), arg0)
+}
+
+// FindPaymentByID mocks base method
+func (m *MockQRPaymentRepository) FindPaymentByID(arg0 string) (*domain.QRPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPaymentByID", arg0)
+	ret0, _ := ret[0].(*domain.QRPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPaymentByID indicates an expected call of FindPaymentByID
+func (mr *MockQRPaymentRepositoryMockRecorder) FindPaymentByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPaymentByID", reflect.TypeOf((*MockQRPaymentRepository)(nil).FindPaymentByID), arg0)
+}
+
+// FindPayments mocks base method
+func (m *MockQRPaymentRepository) FindPayments(arg0 string) ([]*domain.QRPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPayments", arg0)
+	ret0, _ := ret[0].([]*domain.QRPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPayments indicates an expected call of FindPayments
+func (mr *MockQRPaymentRepositoryMockRecorder) FindPayments(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPayments", reflect.TypeOf((*MockQRPaymentRepository)(nil).FindPayments), arg0)
+}
+
+// FindRequestByID mocks base method
+func (m *MockQRPaymentRepository) FindRequestByID(arg0 string) (*domain.QRPaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRequestByID", arg0)
+	ret0, _ := ret[0].(*domain.QRPaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRequestByID indicates an expected call of FindRequestByID
+func (mr *MockQRPaymentRepositoryMockRecorder) FindRequestByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRequestByID", reflect.TypeOf((*MockQRPaymentRepository)(nil).FindRequestByID), arg0)
+}
+
+// FindRequestsByMerchantID mocks base method
+func (m *MockQRPaymentRepository) FindRequestsByMerchantID(arg0 string) ([]*domain.QRPaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRequestsByMerchantID", arg0)
+	ret0, _ := ret[0].([]*domain.QRPaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRequestsByMerchantID indicates an expected call of FindRequestsByMerchantID
+func (mr *MockQRPaymentRepositoryMockRecorder) FindRequestsByMerchantID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRequestsByMerchantID", reflect.TypeOf((*MockQRPaymentRepository)(nil).FindRequestsByMerchantID), arg0)
+}
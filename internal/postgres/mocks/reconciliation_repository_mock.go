@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: ReconciliationRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockReconciliationRepository is a mock of ReconciliationRepository interface
+type MockReconciliationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconciliationRepositoryMockRecorder
+}
+
+// MockReconciliationRepositoryMockRecorder is the mock recorder for MockReconciliationRepository
+type MockReconciliationRepositoryMockRecorder struct {
+	mock *MockReconciliationRepository
+}
+
+// NewMockReconciliationRepository creates a new mock instance
+func NewMockReconciliationRepository(ctrl *gomock.Controller) *MockReconciliationRepository {
+	mock := &MockReconciliationRepository{ctrl: ctrl}
+	mock.recorder = &MockReconciliationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockReconciliationRepository) EXPECT() *MockReconciliationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateStatement mocks base method
+func (m *MockReconciliationRepository) CreateStatement(arg0 *domain.BankStatement) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStatement", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStatement indicates an expected call of CreateStatement
+func (mr *MockReconciliationRepositoryMockRecorder) CreateStatement(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStatement", reflect.TypeOf((*MockReconciliationRepository)(nil).CreateStatement), arg0)
+}
+
+// FindEntries mocks base method
+func (m *MockReconciliationRepository) FindEntries(arg0 domain.BankAccount, arg1, arg2 time.Time) ([]*domain.BankEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindEntries", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.BankEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEntries indicates an expected call of FindEntries
+func (mr *MockReconciliationRepositoryMockRecorder) FindEntries(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEntries", reflect.TypeOf((*MockReconciliationRepository)(nil).FindEntries), arg0, arg1, arg2)
+}
+
+// FindEntryByID mocks base method
+func (m *MockReconciliationRepository) FindEntryByID(arg0 string) (*domain.BankEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindEntryByID", arg0)
+	ret0, _ := ret[0].(*domain.BankEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEntryByID indicates an expected call of FindEntryByID
+func (mr *MockReconciliationRepositoryMockRecorder) FindEntryByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEntryByID", reflect.TypeOf((*MockReconciliationRepository)(nil).FindEntryByID), arg0)
+}
+
+// FindMovementByID mocks base method
+func (m *MockReconciliationRepository) FindMovementByID(arg0 string) (*domain.Movement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMovementByID", arg0)
+	ret0, _ := ret[0].(*domain.Movement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMovementByID indicates an expected call of FindMovementByID
+func (mr *MockReconciliationRepositoryMockRecorder) FindMovementByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMovementByID", reflect.TypeOf((*MockReconciliationRepository)(nil).FindMovementByID), arg0)
+}
+
+// FindUnmatchedEntries mocks base method
+func (m *MockReconciliationRepository) FindUnmatchedEntries(arg0 domain.BankAccount, arg1, arg2 time.Time) ([]*domain.BankEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUnmatchedEntries", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.BankEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUnmatchedEntries indicates an expected call of FindUnmatchedEntries
+func (mr *MockReconciliationRepositoryMockRecorder) FindUnmatchedEntries(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnmatchedEntries", reflect.TypeOf((*MockReconciliationRepository)(nil).FindUnmatchedEntries), arg0, arg1, arg2)
+}
+
+// FindUnmatchedMovements mocks base method
+func (m *MockReconciliationRepository) FindUnmatchedMovements(arg0 string, arg1, arg2 time.Time) ([]*domain.Movement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindUnmatchedMovements", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.Movement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindUnmatchedMovements indicates an expected call of FindUnmatchedMovements
+func (mr *MockReconciliationRepositoryMockRecorder) FindUnmatchedMovements(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindUnmatchedMovements", reflect.TypeOf((*MockReconciliationRepository)(nil).FindUnmatchedMovements), arg0, arg1, arg2)
+}
+
+// Match mocks base method
+func (m *MockReconciliationRepository) Match(arg0, arg1 string, arg2 domain.BankEntryMatchType, arg3 time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Match", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Match indicates an expected call of Match
+func (mr *MockReconciliationRepositoryMockRecorder) Match(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Match", reflect.TypeOf((*MockReconciliationRepository)(nil).Match), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: RiskRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockRiskRepository is a mock of RiskRepository interface
+type MockRiskRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRiskRepositoryMockRecorder
+}
+
+// MockRiskRepositoryMockRecorder is the mock recorder for MockRiskRepository
+type MockRiskRepositoryMockRecorder struct {
+	mock *MockRiskRepository
+}
+
+// NewMockRiskRepository creates a new mock instance
+func NewMockRiskRepository(ctrl *gomock.Controller) *MockRiskRepository {
+	mock := &MockRiskRepository{ctrl: ctrl}
+	mock.recorder = &MockRiskRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockRiskRepository) EXPECT() *MockRiskRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAssessment mocks base method
+func (m *MockRiskRepository) CreateAssessment(arg0 *domain.RiskAssessment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAssessment", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAssessment indicates an expected call of CreateAssessment
+func (mr *MockRiskRepositoryMockRecorder) CreateAssessment(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAssessment", reflect.TypeOf((*MockRiskRepository)(nil).CreateAssessment), arg0)
+}
+
+// FindAssessmentByID mocks base method
+func (m *MockRiskRepository) FindAssessmentByID(arg0 string) (*domain.RiskAssessment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAssessmentByID", arg0)
+	ret0, _ := ret[0].(*domain.RiskAssessment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAssessmentByID indicates an expected call of FindAssessmentByID
+func (mr *MockRiskRepositoryMockRecorder) FindAssessmentByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAssessmentByID", reflect.TypeOf((*MockRiskRepository)(nil).FindAssessmentByID), arg0)
+}
+
+// FindAssessments mocks base method
+func (m *MockRiskRepository) FindAssessments(arg0 string, arg1 time.Time) ([]*domain.RiskAssessment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAssessments", arg0, arg1)
+	ret0, _ := ret[0].([]*domain.RiskAssessment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAssessments indicates an expected call of FindAssessments
+func (mr *MockRiskRepositoryMockRecorder) FindAssessments(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAssessments", reflect.TypeOf((*MockRiskRepository)(nil).FindAssessments), arg0, arg1)
+}
+
+// FindDevices mocks base method
+func (m *MockRiskRepository) FindDevices(arg0 string) ([]domain.RiskDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDevices", arg0)
+	ret0, _ := ret[0].([]domain.RiskDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDevices indicates an expected call of FindDevices
+func (mr *MockRiskRepositoryMockRecorder) FindDevices(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDevices", reflect.TypeOf((*MockRiskRepository)(nil).FindDevices), arg0)
+}
+
+// SaveDevice mocks base method
+func (m *MockRiskRepository) SaveDevice(arg0 domain.RiskDevice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDevice", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveDevice indicates an expected call of SaveDevice
+func (mr *MockRiskRepositoryMockRecorder) SaveDevice(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDevice", reflect.TypeOf((*MockRiskRepository)(nil).SaveDevice), arg0)
+}
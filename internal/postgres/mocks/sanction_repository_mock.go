@@ -0,0 +1,107 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: SanctionRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockSanctionRepository is a mock of SanctionRepository interface
+type MockSanctionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSanctionRepositoryMockRecorder
+}
+
+// MockSanctionRepositoryMockRecorder is the mock recorder for MockSanctionRepository
+type MockSanctionRepositoryMockRecorder struct {
+	mock *MockSanctionRepository
+}
+
+// NewMockSanctionRepository creates a new mock instance
+func NewMockSanctionRepository(ctrl *gomock.Controller) *MockSanctionRepository {
+	mock := &MockSanctionRepository{ctrl: ctrl}
+	mock.recorder = &MockSanctionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSanctionRepository) EXPECT() *MockSanctionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateMatches mocks base method
+func (m *MockSanctionRepository) CreateMatches(arg0 []domain.SanctionMatch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMatches", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMatches indicates an expected call of CreateMatches
+func (mr *MockSanctionRepositoryMockRecorder) CreateMatches(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMatches", reflect.TypeOf((*MockSanctionRepository)(nil).CreateMatches), arg0)
+}
+
+// FindCandidates mocks base method
+func (m *MockSanctionRepository) FindCandidates(arg0 time.Time) ([]domain.SanctionEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindCandidates", arg0)
+	ret0, _ := ret[0].([]domain.SanctionEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindCandidates indicates an expected call of FindCandidates
+func (mr *MockSanctionRepositoryMockRecorder) FindCandidates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindCandidates", reflect.TypeOf((*MockSanctionRepository)(nil).FindCandidates), arg0)
+}
+
+// FindMatchesByCustomerID mocks base method
+func (m *MockSanctionRepository) FindMatchesByCustomerID(arg0 string) ([]domain.SanctionMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMatchesByCustomerID", arg0)
+	ret0, _ := ret[0].([]domain.SanctionMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMatchesByCustomerID indicates an expected call of FindMatchesByCustomerID
+func (mr *MockSanctionRepositoryMockRecorder) FindMatchesByCustomerID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMatchesByCustomerID", reflect.TypeOf((*MockSanctionRepository)(nil).FindMatchesByCustomerID), arg0)
+}
+
+// ReplaceList mocks base method
+func (m *MockSanctionRepository) ReplaceList(arg0 string, arg1 []domain.SanctionEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceList", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceList indicates an expected call of ReplaceList
+func (mr *MockSanctionRepositoryMockRecorder) ReplaceList(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceList", reflect.TypeOf((*MockSanctionRepository)(nil).ReplaceList), arg0, arg1)
+}
+
+// ResolveMatches mocks base method
+func (m *MockSanctionRepository) ResolveMatches(arg0 string, arg1 domain.SanctionDecision, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveMatches", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveMatches indicates an expected call of ResolveMatches
+func (mr *MockSanctionRepositoryMockRecorder) ResolveMatches(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveMatches", reflect.TypeOf((*MockSanctionRepository)(nil).ResolveMatches), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: SplitPaymentRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockSplitPaymentRepository is a mock of SplitPaymentRepository interface
+type MockSplitPaymentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSplitPaymentRepositoryMockRecorder
+}
+
+// MockSplitPaymentRepositoryMockRecorder is the mock recorder for MockSplitPaymentRepository
+type MockSplitPaymentRepositoryMockRecorder struct {
+	mock *MockSplitPaymentRepository
+}
+
+// NewMockSplitPaymentRepository creates a new mock instance
+func NewMockSplitPaymentRepository(ctrl *gomock.Controller) *MockSplitPaymentRepository {
+	mock := &MockSplitPaymentRepository{ctrl: ctrl}
+	mock.recorder = &MockSplitPaymentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSplitPaymentRepository) EXPECT() *MockSplitPaymentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockSplitPaymentRepository) Create(arg0 *domain.SplitPayment, arg1 *domain.Debit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockSplitPaymentRepositoryMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSplitPaymentRepository)(nil).Create), arg0, arg1)
+}
+
+// FindByID mocks base method
+func (m *MockSplitPaymentRepository) FindByID(arg0 string) (*domain.SplitPayment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret0, _ := ret[0].(*domain.SplitPayment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockSplitPaymentRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockSplitPaymentRepository)(nil).FindByID), arg0)
+}
+
+// FindReceivedLegs mocks base method
+func (m *MockSplitPaymentRepository) FindReceivedLegs(arg0 string, arg1, arg2 time.Time) ([]*domain.SplitLeg, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindReceivedLegs", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.SplitLeg)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindReceivedLegs indicates an expected call of FindReceivedLegs
+func (mr *MockSplitPaymentRepositoryMockRecorder) FindReceivedLegs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindReceivedLegs", reflect.TypeOf((*MockSplitPaymentRepository)(nil).FindReceivedLegs), arg0, arg1, arg2)
+}
@@ -0,0 +1,197 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: SubscriptionRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+	time "time"
+)
+
+// MockSubscriptionRepository is a mock of SubscriptionRepository interface
+type MockSubscriptionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSubscriptionRepositoryMockRecorder
+}
+
+// MockSubscriptionRepositoryMockRecorder is the mock recorder for MockSubscriptionRepository
+type MockSubscriptionRepositoryMockRecorder struct {
+	mock *MockSubscriptionRepository
+}
+
+// NewMockSubscriptionRepository creates a new mock instance
+func NewMockSubscriptionRepository(ctrl *gomock.Controller) *MockSubscriptionRepository {
+	mock := &MockSubscriptionRepository{ctrl: ctrl}
+	mock.recorder = &MockSubscriptionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockSubscriptionRepository) EXPECT() *MockSubscriptionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDueSubscriptions mocks base method
+func (m *MockSubscriptionRepository) ClaimDueSubscriptions(arg0 time.Time, arg1 int, arg2 func(*domain.Subscription) (*domain.SubscriptionInvoice, error)) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueSubscriptions", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueSubscriptions indicates an expected call of ClaimDueSubscriptions
+func (mr *MockSubscriptionRepositoryMockRecorder) ClaimDueSubscriptions(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueSubscriptions", reflect.TypeOf((*MockSubscriptionRepository)(nil).ClaimDueSubscriptions), arg0, arg1, arg2)
+}
+
+// ClaimInvoices mocks base method
+func (m *MockSubscriptionRepository) ClaimInvoices(arg0, arg1 time.Time, arg2 int) ([]*domain.SubscriptionInvoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimInvoices", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]*domain.SubscriptionInvoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimInvoices indicates an expected call of ClaimInvoices
+func (mr *MockSubscriptionRepositoryMockRecorder) ClaimInvoices(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimInvoices", reflect.TypeOf((*MockSubscriptionRepository)(nil).ClaimInvoices), arg0, arg1, arg2)
+}
+
+// CreatePlan mocks base method
+func (m *MockSubscriptionRepository) CreatePlan(arg0 *domain.Plan) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePlan", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePlan indicates an expected call of CreatePlan
+func (mr *MockSubscriptionRepositoryMockRecorder) CreatePlan(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePlan", reflect.TypeOf((*MockSubscriptionRepository)(nil).CreatePlan), arg0)
+}
+
+// CreateSubscription mocks base method
+func (m *MockSubscriptionRepository) CreateSubscription(arg0 *domain.Subscription, arg1 *domain.SubscriptionInvoice) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscription", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSubscription indicates an expected call of CreateSubscription
+func (mr *MockSubscriptionRepositoryMockRecorder) CreateSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscription", reflect.TypeOf((*MockSubscriptionRepository)(nil).CreateSubscription), arg0, arg1)
+}
+
+// FindInvoices mocks base method
+func (m *MockSubscriptionRepository) FindInvoices(arg0 string) ([]*domain.SubscriptionInvoice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInvoices", arg0)
+	ret0, _ := ret[0].([]*domain.SubscriptionInvoice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInvoices indicates an expected call of FindInvoices
+func (mr *MockSubscriptionRepositoryMockRecorder) FindInvoices(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInvoices", reflect.TypeOf((*MockSubscriptionRepository)(nil).FindInvoices), arg0)
+}
+
+// FindPlanByID mocks base method
+func (m *MockSubscriptionRepository) FindPlanByID(arg0 string) (*domain.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPlanByID", arg0)
+	ret0, _ := ret[0].(*domain.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPlanByID indicates an expected call of FindPlanByID
+func (mr *MockSubscriptionRepositoryMockRecorder) FindPlanByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPlanByID", reflect.TypeOf((*MockSubscriptionRepository)(nil).FindPlanByID), arg0)
+}
+
+// FindPlans mocks base method
+func (m *MockSubscriptionRepository) FindPlans() ([]*domain.Plan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPlans")
+	ret0, _ := ret[0].([]*domain.Plan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPlans indicates an expected call of FindPlans
+func (mr *MockSubscriptionRepositoryMockRecorder) FindPlans() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPlans", reflect.TypeOf((*MockSubscriptionRepository)(nil).FindPlans))
+}
+
+// FindSubscriptionByID mocks base method
+func (m *MockSubscriptionRepository) FindSubscriptionByID(arg0 string) (*domain.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSubscriptionByID", arg0)
+	ret0, _ := ret[0].(*domain.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSubscriptionByID indicates an expected call of FindSubscriptionByID
+func (mr *MockSubscriptionRepositoryMockRecorder) FindSubscriptionByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSubscriptionByID", reflect.TypeOf((*MockSubscriptionRepository)(nil).FindSubscriptionByID), arg0)
+}
+
+// FindSubscriptionsByCustomerID mocks base method
+func (m *MockSubscriptionRepository) FindSubscriptionsByCustomerID(arg0 string) ([]*domain.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSubscriptionsByCustomerID", arg0)
+	ret0, _ := ret[0].([]*domain.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSubscriptionsByCustomerID indicates an expected call of FindSubscriptionsByCustomerID
+func (mr *MockSubscriptionRepositoryMockRecorder) FindSubscriptionsByCustomerID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSubscriptionsByCustomerID", reflect.TypeOf((*MockSubscriptionRepository)(nil).FindSubscriptionsByCustomerID), arg0)
+}
+
+// UpdateInvoice mocks base method
+func (m *MockSubscriptionRepository) UpdateInvoice(arg0 *domain.SubscriptionInvoice, arg1 *domain.Subscription) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateInvoice", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateInvoice indicates an expected call of UpdateInvoice
+func (mr *MockSubscriptionRepositoryMockRecorder) UpdateInvoice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateInvoice", reflect.TypeOf((*MockSubscriptionRepository)(nil).UpdateInvoice), arg0, arg1)
+}
+
+// UpdateSubscription mocks base method
+func (m *MockSubscriptionRepository) UpdateSubscription(arg0 string, arg1 func(*domain.Subscription) error) (*domain.Subscription, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSubscription", arg0, arg1)
+	ret0, _ := ret[0].(*domain.Subscription)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSubscription indicates an expected call of UpdateSubscription
+func (mr *MockSubscriptionRepositoryMockRecorder) UpdateSubscription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscription", reflect.TypeOf((*MockSubscriptionRepository)(nil).UpdateSubscription), arg0, arg1)
+}
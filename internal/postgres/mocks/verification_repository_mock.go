@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/yaroslavnayug/go-payment-system/internal/domain (interfaces: VerificationRepository)
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	domain "github.com/yaroslavnayug/go-payment-system/internal/domain"
+	reflect "reflect"
+)
+
+// MockVerificationRepository is a mock of VerificationRepository interface
+type MockVerificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockVerificationRepositoryMockRecorder
+}
+
+// MockVerificationRepositoryMockRecorder is the mock recorder for MockVerificationRepository
+type MockVerificationRepositoryMockRecorder struct {
+	mock *MockVerificationRepository
+}
+
+// NewMockVerificationRepository creates a new mock instance
+func NewMockVerificationRepository(ctrl *gomock.Controller) *MockVerificationRepository {
+	mock := &MockVerificationRepository{ctrl: ctrl}
+	mock.recorder = &MockVerificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockVerificationRepository) EXPECT() *MockVerificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method
+func (m *MockVerificationRepository) Create(arg0 *domain.Verification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create
+func (mr *MockVerificationRepositoryMockRecorder) Create(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockVerificationRepository)(nil).Create), arg0)
+}
+
+// FindByID mocks base method
+func (m *MockVerificationRepository) FindByID(arg0 string) (*domain.Verification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", arg0)
+	ret0, _ := ret[0].(*domain.Verification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID
+func (mr *MockVerificationRepositoryMockRecorder) FindByID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockVerificationRepository)(nil).FindByID), arg0)
+}
+
+// FindLastByCustomerID mocks base method
+func (m *MockVerificationRepository) FindLastByCustomerID(arg0 string) (*domain.Verification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLastByCustomerID", arg0)
+	ret0, _ := ret[0].(*domain.Verification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLastByCustomerID indicates an expected call of FindLastByCustomerID
+func (mr *MockVerificationRepositoryMockRecorder) FindLastByCustomerID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLastByCustomerID", reflect.TypeOf((*MockVerificationRepository)(nil).FindLastByCustomerID), arg0)
+}
+
+// Update mocks base method
+func (m *MockVerificationRepository) Update(arg0 *domain.Verification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update
+func (mr *MockVerificationRepositoryMockRecorder) Update(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockVerificationRepository)(nil).Update), arg0)
+}
@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	movementTableName = "monitored_movement"
+	alertTableName    = "monitoring_alert"
+)
+
+var movementColumns = []string{
+	"uid",
+	"customeruid",
+	"type",
+	"amount",
+	"currency",
+	"createdat",
+}
+
+var preparedMovementColumns = strings.Join(movementColumns, ", ")
+
+var alertColumns = []string{
+	"uid",
+	"customeruid",
+	"movementuid",
+	"rulename",
+	"ruletype",
+	"severity",
+	"details",
+	"status",
+	"resolution",
+	"assignee",
+	"comment",
+	"createdat",
+	"updatedat",
+}
+
+var preparedAlertColumns = strings.Join(alertColumns, ", ")
+
+type MonitoringRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewMonitoringRepository(pgConn *pgxpool.Pool) *MonitoringRepository {
+	return &MonitoringRepository{pgConn: pgConn}
+}
+
+func (a *MonitoringRepository) CreateMovement(movement *domain.Movement) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		movementTableName,
+		preparedMovementColumns,
+		getSubstitutionVerbsForColumns(movementColumns),
+	)
+	_, err := a.pgConn.Exec(
+		context.Background(),
+		query,
+		movement.GeneratedID,
+		movement.CustomerID,
+		movement.Type,
+		movement.Amount,
+		movement.Currency,
+		movement.CreatedAt,
+	)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *MonitoringRepository) FindMovements(
+	customerID string,
+	since time.Time,
+) (movements []*domain.Movement, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 AND createdat>$2 ORDER BY createdat;`,
+		preparedMovementColumns,
+		movementTableName,
+	)
+
+	rows, err := a.pgConn.Query(
+		context.Background(),
+		query,
+		customerID,
+		since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanMovements(rows)
+}
+
+func (a *MonitoringRepository) CreateAlerts(alerts []*domain.Alert) (err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		alertTableName,
+		preparedAlertColumns,
+		getSubstitutionVerbsForColumns(alertColumns),
+	)
+	for _, alert := range alerts {
+		_, err = tx.Exec(context.Background(), query, alertArgs(alert)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (a *MonitoringRepository) FindAlertByID(alertID string) (alert *domain.Alert, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedAlertColumns,
+		alertTableName,
+	)
+
+	alert, err = scanAlert(a.pgConn.QueryRow(context.Background(), query, alertID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+func (a *MonitoringRepository) FindAlertsByStatus(status domain.AlertStatus) (alerts []*domain.Alert, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE status=$1 ORDER BY createdat;`,
+		preparedAlertColumns,
+		alertTableName,
+	)
+
+	rows, err := a.pgConn.Query(
+		context.Background(),
+		query,
+		status,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var alert *domain.Alert
+		alert, err = scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, alert)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return alerts, nil
+}
+
+func (a *MonitoringRepository) UpdateAlert(alert *domain.Alert) error {
+	query := fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		alertTableName,
+		preparedAlertColumns,
+		getSubstitutionVerbsForColumns(alertColumns),
+	)
+	_, err := a.pgConn.Exec(context.Background(), query, alertArgs(alert)...)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func alertArgs(alert *domain.Alert) []interface{} {
+	return []interface{}{
+		alert.GeneratedID,
+		alert.CustomerID,
+		alert.MovementID,
+		alert.RuleName,
+		alert.RuleType,
+		alert.Severity,
+		alert.Details,
+		alert.Status,
+		alert.Resolution,
+		alert.Assignee,
+		alert.Comment,
+		alert.CreatedAt,
+		alert.UpdatedAt,
+	}
+}
+
+func scanAlert(row pgx.Row) (*domain.Alert, error) {
+	alert := &domain.Alert{}
+	err := row.Scan(
+		&alert.GeneratedID,
+		&alert.CustomerID,
+		&alert.MovementID,
+		&alert.RuleName,
+		&alert.RuleType,
+		&alert.Severity,
+		&alert.Details,
+		&alert.Status,
+		&alert.Resolution,
+		&alert.Assignee,
+		&alert.Comment,
+		&alert.CreatedAt,
+		&alert.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+func scanMovement(row pgx.Row) (*domain.Movement, error) {
+	movement := &domain.Movement{}
+	err := row.Scan(
+		&movement.GeneratedID,
+		&movement.CustomerID,
+		&movement.Type,
+		&movement.Amount,
+		&movement.Currency,
+		&movement.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+func scanMovements(rows pgx.Rows) ([]*domain.Movement, error) {
+	var movements []*domain.Movement
+	for rows.Next() {
+		movement, err := scanMovement(rows)
+		if err != nil {
+			return nil, err
+		}
+		movements = append(movements, movement)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return movements, nil
+}
@@ -0,0 +1,121 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestMonitoring_CreateMovement_FindMovements(t *testing.T) {
+	t.Parallel()
+
+	// clean
+	query := `DELETE FROM monitored_movement WHERE customeruid = $1;`
+	_, err := PostgresConnection.Exec(context.Background(), query, "monitoring_customer")
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewMonitoringRepository(PostgresConnection)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// arrange
+	oldMovement := &domain.Movement{
+		GeneratedID: "monitoring_old_movement",
+		CustomerID:  "monitoring_customer",
+		Type:        domain.MovementTypeDeposit,
+		Amount:      100,
+		Currency:    "RUB",
+		CreatedAt:   now.Add(-48 * time.Hour),
+	}
+	movement := &domain.Movement{
+		GeneratedID: "monitoring_movement",
+		CustomerID:  "monitoring_customer",
+		Type:        domain.MovementTypeTransfer,
+		Amount:      200,
+		Currency:    "RUB",
+		CreatedAt:   now,
+	}
+
+	// act
+	for _, m := range []*domain.Movement{oldMovement, movement} {
+		err = repository.CreateMovement(m)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// assert
+	movements, err := repository.FindMovements("monitoring_customer", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Len(t, movements, 1)
+	assert.Equal(t, movement.GeneratedID, movements[0].GeneratedID)
+	assert.Equal(t, movement.Amount, movements[0].Amount)
+	assert.True(t, movement.CreatedAt.Equal(movements[0].CreatedAt))
+}
+
+func TestMonitoring_CreateAlerts_UpdateAlert(t *testing.T) {
+	t.Parallel()
+
+	// clean
+	query := `DELETE FROM monitoring_alert WHERE uid = $1;`
+	_, err := PostgresConnection.Exec(context.Background(), query, "monitoring_alert")
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewMonitoringRepository(PostgresConnection)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// arrange
+	alert := &domain.Alert{
+		GeneratedID: "monitoring_alert",
+		CustomerID:  "monitoring_customer",
+		MovementID:  "monitoring_movement",
+		RuleName:    "large transfer",
+		RuleType:    "amount_threshold",
+		Severity:    "high",
+		Details:     "amount 200 RUB is not less than 100",
+		Status:      domain.AlertStatusOpen,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	// act
+	err = repository.CreateAlerts([]*domain.Alert{alert})
+	if err != nil {
+		t.Error(err)
+	}
+	alert.Status = domain.AlertStatusClosed
+	alert.Resolution = domain.AlertResolutionFalsePositive
+	alert.Assignee = "alfred"
+	err = repository.UpdateAlert(alert)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	foundAlert, err := repository.FindAlertByID("monitoring_alert")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, domain.AlertStatusClosed, foundAlert.Status)
+	assert.Equal(t, domain.AlertResolutionFalsePositive, foundAlert.Resolution)
+	assert.Equal(t, "alfred", foundAlert.Assignee)
+	assert.Equal(t, alert.Details, foundAlert.Details)
+
+	closedAlerts, err := repository.FindAlertsByStatus(domain.AlertStatusClosed)
+	if err != nil {
+		t.Error(err)
+	}
+	var ids []string
+	for _, closedAlert := range closedAlerts {
+		ids = append(ids, closedAlert.GeneratedID)
+	}
+	assert.Contains(t, ids, "monitoring_alert")
+}
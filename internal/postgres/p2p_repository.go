@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	phoneLookupTableName = "phone_lookup"
+	p2pTransferTableName = "p2p_transfer"
+)
+
+var phoneLookupColumns = []string{
+	"customeruid",
+	"phone",
+	"found",
+	"createdat",
+}
+
+var preparedPhoneLookupColumns = strings.Join(phoneLookupColumns, ", ")
+
+var p2pTransferColumns = []string{
+	"uid",
+	"senderuid",
+	"recipientuid",
+	"recipientphone",
+	"recipientmaskedname",
+	"amount",
+	"currency",
+	"comment",
+	"beneficiaryuid",
+	"riskassessmentuid",
+	"riskdecision",
+	"createdat",
+}
+
+var preparedP2PTransferColumns = strings.Join(p2pTransferColumns, ", ")
+
+type P2PRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewP2PRepository(pgConn *pgxpool.Pool) *P2PRepository {
+	return &P2PRepository{pgConn: pgConn}
+}
+
+func (a *P2PRepository) CreatePhoneLookup(lookup *domain.PhoneLookup) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		phoneLookupTableName,
+		preparedPhoneLookupColumns,
+		getSubstitutionVerbsForColumns(phoneLookupColumns),
+	)
+	_, err := a.pgConn.Exec(
+		context.Background(),
+		query,
+		lookup.CustomerID,
+		lookup.Phone,
+		lookup.Found,
+		lookup.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *P2PRepository) CountLookedUpPhones(customerID string, exceptPhone string, since time.Time) (int, error) {
+	query := fmt.Sprintf(
+		`SELECT COUNT(DISTINCT phone) FROM %s WHERE customeruid=$1 AND phone<>$2 AND createdat>=$3;`,
+		phoneLookupTableName,
+	)
+
+	var count int
+	err := a.pgConn.QueryRow(context.Background(), query, customerID, exceptPhone, since).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (a *P2PRepository) CreateTransfer(transfer *domain.P2PTransfer, debit *domain.Debit) (err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		p2pTransferTableName,
+		preparedP2PTransferColumns,
+		getSubstitutionVerbsForColumns(p2pTransferColumns),
+	)
+	_, err = tx.Exec(
+		context.Background(),
+		query,
+		transfer.GeneratedID,
+		transfer.SenderID,
+		transfer.RecipientID,
+		transfer.RecipientPhone,
+		transfer.RecipientMaskedName,
+		transfer.Amount,
+		transfer.Currency,
+		transfer.Comment,
+		transfer.BeneficiaryID,
+		transfer.RiskAssessmentID,
+		transfer.RiskDecision,
+		transfer.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	err = createDebit(tx, debit)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(context.Background())
+}
+
+func (a *P2PRepository) FindTransferByID(transferID string) (transfer *domain.P2PTransfer, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedP2PTransferColumns,
+		p2pTransferTableName,
+	)
+
+	transfer = &domain.P2PTransfer{}
+	err = a.pgConn.QueryRow(context.Background(), query, transferID).Scan(
+		&transfer.GeneratedID,
+		&transfer.SenderID,
+		&transfer.RecipientID,
+		&transfer.RecipientPhone,
+		&transfer.RecipientMaskedName,
+		&transfer.Amount,
+		&transfer.Currency,
+		&transfer.Comment,
+		&transfer.BeneficiaryID,
+		&transfer.RiskAssessmentID,
+		&transfer.RiskDecision,
+		&transfer.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return transfer, nil
+}
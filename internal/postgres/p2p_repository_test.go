@@ -0,0 +1,150 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestP2P_CountLookedUpPhones(t *testing.T) {
+	// clean
+	_, err := PostgresConnection.Exec(context.Background(), `DELETE FROM phone_lookup;`)
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewP2PRepository(PostgresConnection)
+	now := time.Date(2020, 8, 18, 10, 0, 0, 0, time.UTC)
+
+	// arrange
+	lookups := []*domain.PhoneLookup{
+		{CustomerID: "p2p_sender", Phone: "+79931234567", Found: true, CreatedAt: now.Add(-2 * time.Hour)},
+		{CustomerID: "p2p_sender", Phone: "+79931234568", Found: false, CreatedAt: now.Add(-30 * time.Minute)},
+		{CustomerID: "p2p_sender", Phone: "+79931234568", Found: false, CreatedAt: now.Add(-20 * time.Minute)},
+		{CustomerID: "p2p_sender", Phone: "+79931234569", Found: true, CreatedAt: now.Add(-10 * time.Minute)},
+		{CustomerID: "p2p_other", Phone: "+79931234570", Found: true, CreatedAt: now.Add(-10 * time.Minute)},
+	}
+	for _, lookup := range lookups {
+		err = repository.CreatePhoneLookup(lookup)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// act
+	lastHour, err := repository.CountLookedUpPhones("p2p_sender", "", now.Add(-time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+	lastHourExcept, err := repository.CountLookedUpPhones("p2p_sender", "+79931234569", now.Add(-time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+	lastDay, err := repository.CountLookedUpPhones("p2p_sender", "", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Equal(t, 2, lastHour)
+	assert.Equal(t, 1, lastHourExcept)
+	assert.Equal(t, 3, lastDay)
+}
+
+func TestP2P_CreateTransfer(t *testing.T) {
+	// clean
+	for _, query := range []string{
+		`DELETE FROM p2p_transfer;`,
+		`DELETE FROM posting WHERE customeruid LIKE 'p2p_%';`,
+		`DELETE FROM customer WHERE uid='p2p_sender';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewP2PRepository(PostgresConnection)
+	now := time.Date(2020, 8, 18, 10, 0, 0, 0, time.UTC)
+
+	// arrange
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "p2p_sender",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Bruce",
+		LastName:    "Wayne",
+		Phone:       "+79930000005",
+		CreatedAt:   now,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = NewPostingRepository(PostgresConnection).Create(&domain.Posting{
+		GeneratedID: "p2p_top_up",
+		CustomerID:  "p2p_sender",
+		Amount:      15000,
+		Currency:    "RUB",
+		PostedAt:    now.Add(-time.Hour),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	debit := func(transferID string) *domain.Debit {
+		return &domain.Debit{
+			PayerID:   "p2p_sender",
+			PayeeID:   "p2p_recipient",
+			Amount:    10000,
+			Currency:  "RUB",
+			Reference: transferID,
+			Postings: []*domain.Posting{
+				{GeneratedID: transferID + "_0", CustomerID: "p2p_sender", Amount: -10000, Currency: "RUB", PostedAt: now},
+				{GeneratedID: transferID + "_1", CustomerID: "p2p_recipient", Amount: 10000, Currency: "RUB", PostedAt: now},
+			},
+			PostedAt: now,
+		}
+	}
+	transfer := &domain.P2PTransfer{
+		GeneratedID:         "p2p_transfer",
+		SenderID:            "p2p_sender",
+		RecipientID:         "p2p_recipient",
+		RecipientPhone:      "+79931234567",
+		RecipientMaskedName: "Bruce W.",
+		Amount:              10000,
+		Currency:            "RUB",
+		Comment:             "for lunch",
+		CreatedAt:           now,
+	}
+	overBalanceTransfer := *transfer
+	overBalanceTransfer.GeneratedID = "p2p_over_balance"
+
+	// act
+	err = repository.CreateTransfer(transfer, debit("p2p_transfer"))
+	if err != nil {
+		t.Error(err)
+	}
+	overBalanceErr := repository.CreateTransfer(&overBalanceTransfer, debit("p2p_over_balance"))
+	found, err := repository.FindTransferByID("p2p_transfer")
+	if err != nil {
+		t.Error(err)
+	}
+	notFound, err := repository.FindTransferByID("p2p_over_balance")
+	if err != nil {
+		t.Error(err)
+	}
+	recipientBalance, err := NewPostingRepository(PostgresConnection).
+		FindBalance("p2p_recipient", "RUB", now.Add(time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Equal(t, transfer.RecipientMaskedName, found.RecipientMaskedName)
+	assert.Equal(t, transfer.Amount, found.Amount)
+	assert.True(t, transfer.CreatedAt.Equal(found.CreatedAt))
+	assert.Equal(t, domain.ErrInsufficientFunds, overBalanceErr)
+	assert.Nil(t, notFound)
+	assert.Equal(t, int64(10000), recipientBalance)
+}
@@ -0,0 +1,339 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	paymentScheduleTableName   = "payment_schedule"
+	scheduleExecutionTableName = "schedule_execution"
+)
+
+var paymentScheduleColumns = []string{
+	"uid",
+	"customeruid",
+	"payeeuid",
+	"amount",
+	"currency",
+	"recurrence",
+	"timezone",
+	"startat",
+	"endat",
+	"nextrunat",
+	"status",
+	"createdat",
+	"updatedat",
+}
+
+var preparedPaymentScheduleColumns = strings.Join(paymentScheduleColumns, ", ")
+
+var scheduleExecutionColumns = []string{
+	"uid",
+	"scheduleuid",
+	"dueat",
+	"status",
+	"attempts",
+	"lasterror",
+	"nextattemptat",
+	"createdat",
+	"updatedat",
+}
+
+var preparedScheduleExecutionColumns = strings.Join(scheduleExecutionColumns, ", ")
+
+type PaymentScheduleRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewPaymentScheduleRepository(pgConn *pgxpool.Pool) *PaymentScheduleRepository {
+	return &PaymentScheduleRepository{pgConn: pgConn}
+}
+
+func (a *PaymentScheduleRepository) Create(schedule *domain.PaymentSchedule) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		paymentScheduleTableName,
+		preparedPaymentScheduleColumns,
+		getSubstitutionVerbsForColumns(paymentScheduleColumns),
+	)
+	_, err := a.pgConn.Exec(context.Background(), query, paymentScheduleArgs(schedule)...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *PaymentScheduleRepository) FindByID(scheduleID string) (schedule *domain.PaymentSchedule, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedPaymentScheduleColumns,
+		paymentScheduleTableName,
+	)
+
+	schedule, err = scanPaymentSchedule(a.pgConn.QueryRow(context.Background(), query, scheduleID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (a *PaymentScheduleRepository) FindByCustomerID(
+	customerID string,
+) (schedules []*domain.PaymentSchedule, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 ORDER BY createdat;`,
+		preparedPaymentScheduleColumns,
+		paymentScheduleTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPaymentSchedules(rows)
+}
+
+func (a *PaymentScheduleRepository) Update(schedule *domain.PaymentSchedule) error {
+	_, err := a.pgConn.Exec(context.Background(), updatePaymentScheduleQuery(), paymentScheduleArgs(schedule)...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *PaymentScheduleRepository) ClaimDueSchedules(
+	now time.Time,
+	limit int,
+	plan func(schedule *domain.PaymentSchedule) *domain.ScheduleExecution,
+) (claimed int, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE status=$1 AND nextrunat<=$2 ORDER BY nextrunat LIMIT $3 FOR UPDATE SKIP LOCKED;`,
+		preparedPaymentScheduleColumns,
+		paymentScheduleTableName,
+	)
+	rows, err := tx.Query(context.Background(), query, domain.PaymentScheduleStatusActive, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	schedules, err := scanPaymentSchedules(rows)
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	insertExecutionQuery := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (uid) DO NOTHING;`,
+		scheduleExecutionTableName,
+		preparedScheduleExecutionColumns,
+		getSubstitutionVerbsForColumns(scheduleExecutionColumns),
+	)
+	for _, schedule := range schedules {
+		execution := plan(schedule)
+		if execution != nil {
+			_, err = tx.Exec(context.Background(), insertExecutionQuery, scheduleExecutionArgs(execution)...)
+			if err != nil {
+				return 0, err
+			}
+		}
+		_, err = tx.Exec(context.Background(), updatePaymentScheduleQuery(), paymentScheduleArgs(schedule)...)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return len(schedules), nil
+}
+
+func (a *PaymentScheduleRepository) ClaimExecutions(
+	now time.Time,
+	leaseUntil time.Time,
+	limit int,
+) (executions []*domain.ScheduleExecution, err error) {
+	query := fmt.Sprintf(
+		`UPDATE %[1]s SET nextattemptat=$2 WHERE uid IN (
+			SELECT uid FROM %[1]s WHERE status=$1 AND nextattemptat<=$3
+			ORDER BY nextattemptat LIMIT $4 FOR UPDATE SKIP LOCKED
+		) RETURNING %[2]s;`,
+		scheduleExecutionTableName,
+		preparedScheduleExecutionColumns,
+	)
+
+	rows, err := a.pgConn.Query(
+		context.Background(),
+		query,
+		domain.ScheduleExecutionStatusPending,
+		leaseUntil,
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanScheduleExecutions(rows)
+}
+
+func (a *PaymentScheduleRepository) UpdateExecution(execution *domain.ScheduleExecution) error {
+	query := fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		scheduleExecutionTableName,
+		preparedScheduleExecutionColumns,
+		getSubstitutionVerbsForColumns(scheduleExecutionColumns),
+	)
+	_, err := a.pgConn.Exec(context.Background(), query, scheduleExecutionArgs(execution)...)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *PaymentScheduleRepository) FindExecutions(
+	scheduleID string,
+) (executions []*domain.ScheduleExecution, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE scheduleuid=$1 ORDER BY dueat DESC;`,
+		preparedScheduleExecutionColumns,
+		scheduleExecutionTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanScheduleExecutions(rows)
+}
+
+func updatePaymentScheduleQuery() string {
+	return fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		paymentScheduleTableName,
+		preparedPaymentScheduleColumns,
+		getSubstitutionVerbsForColumns(paymentScheduleColumns),
+	)
+}
+
+func paymentScheduleArgs(schedule *domain.PaymentSchedule) []interface{} {
+	return []interface{}{
+		schedule.GeneratedID,
+		schedule.CustomerID,
+		schedule.PayeeID,
+		schedule.Amount,
+		schedule.Currency,
+		schedule.Recurrence,
+		schedule.Timezone,
+		schedule.StartAt,
+		nullableTime(schedule.EndAt),
+		schedule.NextRunAt,
+		schedule.Status,
+		schedule.CreatedAt,
+		schedule.UpdatedAt,
+	}
+}
+
+func scheduleExecutionArgs(execution *domain.ScheduleExecution) []interface{} {
+	return []interface{}{
+		execution.GeneratedID,
+		execution.ScheduleID,
+		execution.DueAt,
+		execution.Status,
+		execution.Attempts,
+		execution.LastError,
+		execution.NextAttemptAt,
+		execution.CreatedAt,
+		execution.UpdatedAt,
+	}
+}
+
+func scanPaymentSchedule(row pgx.Row) (*domain.PaymentSchedule, error) {
+	schedule := &domain.PaymentSchedule{}
+	var endAt *time.Time
+	err := row.Scan(
+		&schedule.GeneratedID,
+		&schedule.CustomerID,
+		&schedule.PayeeID,
+		&schedule.Amount,
+		&schedule.Currency,
+		&schedule.Recurrence,
+		&schedule.Timezone,
+		&schedule.StartAt,
+		&endAt,
+		&schedule.NextRunAt,
+		&schedule.Status,
+		&schedule.CreatedAt,
+		&schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if endAt != nil {
+		schedule.EndAt = *endAt
+	}
+	return schedule, nil
+}
+
+func scanPaymentSchedules(rows pgx.Rows) ([]*domain.PaymentSchedule, error) {
+	var schedules []*domain.PaymentSchedule
+	for rows.Next() {
+		schedule, err := scanPaymentSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return schedules, nil
+}
+
+func scanScheduleExecutions(rows pgx.Rows) ([]*domain.ScheduleExecution, error) {
+	var executions []*domain.ScheduleExecution
+	for rows.Next() {
+		execution := &domain.ScheduleExecution{}
+		err := rows.Scan(
+			&execution.GeneratedID,
+			&execution.ScheduleID,
+			&execution.DueAt,
+			&execution.Status,
+			&execution.Attempts,
+			&execution.LastError,
+			&execution.NextAttemptAt,
+			&execution.CreatedAt,
+			&execution.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		executions = append(executions, execution)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return executions, nil
+}
@@ -0,0 +1,111 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestPaymentSchedule_ClaimDueSchedules(t *testing.T) {
+	// clean
+	_, err := PostgresConnection.Exec(context.Background(), `DELETE FROM payment_schedule;`)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = PostgresConnection.Exec(context.Background(), `DELETE FROM schedule_execution;`)
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewPaymentScheduleRepository(PostgresConnection)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// arrange
+	for _, id := range []string{"due_schedule_1", "due_schedule_2", "due_schedule_3"} {
+		err = repository.Create(&domain.PaymentSchedule{
+			GeneratedID: id,
+			CustomerID:  "schedule_customer",
+			PayeeID:     "schedule_payee",
+			Amount:      100,
+			Currency:    "RUB",
+			Recurrence:  "FREQ=MONTHLY;BYMONTHDAY=5",
+			Timezone:    "UTC",
+			StartAt:     now.Add(-time.Hour),
+			NextRunAt:   now.Add(-time.Minute),
+			Status:      domain.PaymentScheduleStatusActive,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		})
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// act, schedules are claimed by concurrent workers
+	wg := sync.WaitGroup{}
+	var mu sync.Mutex
+	claimedTotal := 0
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			claimed, claimErr := repository.ClaimDueSchedules(
+				now,
+				10,
+				func(schedule *domain.PaymentSchedule) *domain.ScheduleExecution {
+					dueAt := schedule.NextRunAt
+					schedule.NextRunAt = now.Add(30 * 24 * time.Hour)
+					return &domain.ScheduleExecution{
+						GeneratedID:   schedule.GeneratedID + "_execution",
+						ScheduleID:    schedule.GeneratedID,
+						DueAt:         dueAt,
+						Status:        domain.ScheduleExecutionStatusPending,
+						NextAttemptAt: dueAt,
+						CreatedAt:     now,
+						UpdatedAt:     now,
+					}
+				},
+			)
+			if claimErr != nil {
+				t.Error(claimErr)
+			}
+			mu.Lock()
+			claimedTotal += claimed
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	// assert
+	assert.Equal(t, 3, claimedTotal)
+	schedule, err := repository.FindByID("due_schedule_1")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.True(t, now.Add(30*24*time.Hour).Equal(schedule.NextRunAt))
+
+	executions, err := repository.ClaimExecutions(now, now.Add(time.Minute), 10)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Len(t, executions, 3)
+
+	// claimed executions are leased and not returned again
+	executions, err = repository.ClaimExecutions(now, now.Add(time.Minute), 10)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Len(t, executions, 0)
+
+	executions, err = repository.FindExecutions("due_schedule_1")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Len(t, executions, 1)
+	assert.True(t, now.Add(time.Minute).Equal(executions[0].NextAttemptAt))
+}
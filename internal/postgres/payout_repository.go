@@ -0,0 +1,344 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	payoutTableName      = "payout"
+	payoutBatchTableName = "payout_batch"
+)
+
+var payoutColumns = []string{
+	"uid",
+	"customeruid",
+	"amount",
+	"currency",
+	"creditorname",
+	"creditoriban",
+	"creditorbic",
+	"remittanceinfo",
+	"status",
+	"batchuid",
+	"paymentinfouid",
+	"rejectreason",
+	"beneficiaryuid",
+	"riskassessmentuid",
+	"riskdecision",
+	"createdat",
+	"updatedat",
+}
+
+var preparedPayoutColumns = strings.Join(payoutColumns, ", ")
+
+var payoutBatchColumns = []string{
+	"uid",
+	"numberoftransactions",
+	"controlsum",
+	"document",
+	"createdat",
+}
+
+var preparedPayoutBatchColumns = strings.Join(payoutBatchColumns, ", ")
+
+type PayoutRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewPayoutRepository(pgConn *pgxpool.Pool) *PayoutRepository {
+	return &PayoutRepository{pgConn: pgConn}
+}
+
+func (a *PayoutRepository) Create(payout *domain.Payout, debit *domain.Debit) (err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		payoutTableName,
+		preparedPayoutColumns,
+		getSubstitutionVerbsForColumns(payoutColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, payoutArgs(payout)...)
+	if err != nil {
+		return err
+	}
+	err = createDebit(tx, debit)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(context.Background())
+}
+
+func (a *PayoutRepository) FindByID(payoutID string) (payout *domain.Payout, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedPayoutColumns,
+		payoutTableName,
+	)
+
+	payout, err = scanPayout(a.pgConn.QueryRow(context.Background(), query, payoutID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return payout, nil
+}
+
+func (a *PayoutRepository) FindByCustomerID(customerID string) (payouts []*domain.Payout, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 ORDER BY createdat;`,
+		preparedPayoutColumns,
+		payoutTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanPayouts(rows)
+}
+
+func (a *PayoutRepository) SubmitPending(
+	limit int,
+	build func(payouts []*domain.Payout) (*domain.PayoutBatch, error),
+) (submitted int, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE status=$1 ORDER BY createdat LIMIT $2 FOR UPDATE SKIP LOCKED;`,
+		preparedPayoutColumns,
+		payoutTableName,
+	)
+	rows, err := tx.Query(context.Background(), query, domain.PayoutStatusPending, limit)
+	if err != nil {
+		return 0, err
+	}
+	payouts, err := scanPayouts(rows)
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+	if len(payouts) == 0 {
+		return 0, tx.Rollback(context.Background())
+	}
+
+	batch, err := build(payouts)
+	if err != nil {
+		return 0, err
+	}
+	query = fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		payoutBatchTableName,
+		preparedPayoutBatchColumns,
+		getSubstitutionVerbsForColumns(payoutBatchColumns),
+	)
+	_, err = tx.Exec(
+		context.Background(),
+		query,
+		batch.GeneratedID,
+		batch.NumberOfTransactions,
+		batch.ControlSum,
+		string(batch.Document),
+		batch.CreatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	query = fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		payoutTableName,
+		preparedPayoutColumns,
+		getSubstitutionVerbsForColumns(payoutColumns),
+	)
+	for _, payout := range payouts {
+		_, err = tx.Exec(context.Background(), query, payoutArgs(payout)...)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return len(payouts), nil
+}
+
+func (a *PayoutRepository) FindBatchByID(batchID string) (batch *domain.PayoutBatch, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedPayoutBatchColumns,
+		payoutBatchTableName,
+	)
+
+	batch = &domain.PayoutBatch{}
+	var document string
+	err = a.pgConn.QueryRow(context.Background(), query, batchID).Scan(
+		&batch.GeneratedID,
+		&batch.NumberOfTransactions,
+		&batch.ControlSum,
+		&document,
+		&batch.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	batch.Document = []byte(document)
+	return batch, nil
+}
+
+func (a *PayoutRepository) UpdateSubmitted(
+	update *domain.PayoutStatusUpdate,
+	updatedAt time.Time,
+	refund func(payout *domain.Payout) (*domain.Debit, error),
+) (updated int, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`UPDATE %s SET (status, rejectreason, updatedat) = ROW ($1, $2, $3)
+		WHERE status=$4 AND batchuid=$5 AND ($6='' OR paymentinfouid=$6) AND ($7='' OR uid=$7)
+		RETURNING %s;`,
+		payoutTableName,
+		preparedPayoutColumns,
+	)
+	rows, err := tx.Query(
+		context.Background(),
+		query,
+		update.Status,
+		update.Reason,
+		updatedAt,
+		domain.PayoutStatusSubmitted,
+		update.BatchID,
+		update.PaymentInfoID,
+		update.PayoutID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	payouts, err := scanPayouts(rows)
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	if update.Status == domain.PayoutStatusRejected {
+		for _, payout := range payouts {
+			debit, err := refund(payout)
+			if err != nil {
+				return 0, err
+			}
+			err = createDebit(tx, debit)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return len(payouts), nil
+}
+
+func payoutArgs(payout *domain.Payout) []interface{} {
+	return []interface{}{
+		payout.GeneratedID,
+		payout.CustomerID,
+		payout.Amount,
+		payout.Currency,
+		payout.CreditorName,
+		payout.CreditorIBAN,
+		payout.CreditorBIC,
+		payout.RemittanceInfo,
+		payout.Status,
+		payout.BatchID,
+		payout.PaymentInfoID,
+		payout.RejectReason,
+		payout.BeneficiaryID,
+		payout.RiskAssessmentID,
+		payout.RiskDecision,
+		payout.CreatedAt,
+		payout.UpdatedAt,
+	}
+}
+
+func scanPayout(row pgx.Row) (*domain.Payout, error) {
+	payout := &domain.Payout{}
+	err := row.Scan(
+		&payout.GeneratedID,
+		&payout.CustomerID,
+		&payout.Amount,
+		&payout.Currency,
+		&payout.CreditorName,
+		&payout.CreditorIBAN,
+		&payout.CreditorBIC,
+		&payout.RemittanceInfo,
+		&payout.Status,
+		&payout.BatchID,
+		&payout.PaymentInfoID,
+		&payout.RejectReason,
+		&payout.BeneficiaryID,
+		&payout.RiskAssessmentID,
+		&payout.RiskDecision,
+		&payout.CreatedAt,
+		&payout.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return payout, nil
+}
+
+func scanPayouts(rows pgx.Rows) ([]*domain.Payout, error) {
+	var payouts []*domain.Payout
+	for rows.Next() {
+		payout, err := scanPayout(rows)
+		if err != nil {
+			return nil, err
+		}
+		payouts = append(payouts, payout)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return payouts, nil
+}
@@ -0,0 +1,201 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestPayout_SubmitAndUpdate(t *testing.T) {
+	// clean
+	for _, query := range []string{
+		`DELETE FROM payout;`,
+		`DELETE FROM payout_batch;`,
+		`DELETE FROM posting WHERE reference LIKE 'payout:%' OR customeruid='payout_customer';`,
+		`DELETE FROM customer WHERE uid='payout_customer';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewPayoutRepository(PostgresConnection)
+	postingRepository := NewPostingRepository(PostgresConnection)
+	now := time.Date(2020, 8, 18, 10, 0, 0, 0, time.UTC)
+
+	// arrange
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "payout_customer",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Bruce",
+		LastName:    "Wayne",
+		Phone:       "+79930000007",
+		CreatedAt:   now,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = postingRepository.Create(&domain.Posting{
+		GeneratedID: "payout_top_up",
+		CustomerID:  "payout_customer",
+		Amount:      35000,
+		Currency:    "EUR",
+		PostedAt:    now.Add(-time.Hour),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	debit := func(from string, to string, reference string) *domain.Debit {
+		return &domain.Debit{
+			PayerID:   from,
+			PayeeID:   to,
+			Amount:    10000,
+			Currency:  "EUR",
+			Reference: reference,
+			Postings: []*domain.Posting{
+				{GeneratedID: reference + "_0", CustomerID: from, Amount: -10000, Currency: "EUR",
+					Reference: reference, PostedAt: now},
+				{GeneratedID: reference + "_1", CustomerID: to, Amount: 10000, Currency: "EUR",
+					Reference: reference, PostedAt: now},
+			},
+			PostedAt: now,
+		}
+	}
+	for i, payoutID := range []string{"payout_1", "payout_2", "payout_3"} {
+		err = repository.Create(&domain.Payout{
+			GeneratedID:  payoutID,
+			CustomerID:   "payout_customer",
+			Amount:       10000,
+			Currency:     "EUR",
+			CreditorName: "Ivan Ivanov",
+			CreditorIBAN: "DE89370400440532013000",
+			Status:       domain.PayoutStatusPending,
+			CreatedAt:    now.Add(time.Duration(i) * time.Minute),
+			UpdatedAt:    now.Add(time.Duration(i) * time.Minute),
+		}, debit("payout_customer", domain.LedgerAccountPayouts, "payout:"+payoutID))
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// act
+	submitted, err := repository.SubmitPending(2, func(payouts []*domain.Payout) (*domain.PayoutBatch, error) {
+		for _, payout := range payouts {
+			payout.Status = domain.PayoutStatusSubmitted
+			payout.BatchID = "batch"
+			payout.PaymentInfoID = "batch-EUR"
+		}
+		return &domain.PayoutBatch{
+			GeneratedID:          "batch",
+			NumberOfTransactions: len(payouts),
+			ControlSum:           20000,
+			Document:             []byte("<Document/>"),
+			CreatedAt:            now,
+		}, nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	rejected, err := repository.UpdateSubmitted(&domain.PayoutStatusUpdate{
+		BatchID:  "batch",
+		PayoutID: "payout_1",
+		Status:   domain.PayoutStatusRejected,
+		Reason:   "AC04",
+	}, now.Add(time.Hour), func(payout *domain.Payout) (*domain.Debit, error) {
+		return debit(domain.LedgerAccountPayouts, payout.CustomerID, "payout:"+payout.GeneratedID+":refund"), nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	settled, err := repository.UpdateSubmitted(&domain.PayoutStatusUpdate{
+		BatchID: "batch",
+		Status:  domain.PayoutStatusSettled,
+	}, now.Add(time.Hour), func(payout *domain.Payout) (*domain.Debit, error) {
+		return nil, fmt.Errorf("settled payout %s should not be refunded", payout.GeneratedID)
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	batch, err := repository.FindBatchByID("batch")
+	if err != nil {
+		t.Error(err)
+	}
+	payouts, err := repository.FindByCustomerID("payout_customer")
+	if err != nil {
+		t.Error(err)
+	}
+	balance, err := postingRepository.FindBalance("payout_customer", "EUR", now.Add(2*time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Equal(t, 2, submitted)
+	assert.Equal(t, 1, rejected)
+	assert.Equal(t, 1, settled)
+	assert.Equal(t, "<Document/>", string(batch.Document))
+	assert.Len(t, payouts, 3)
+	assert.Equal(t, domain.PayoutStatusRejected, payouts[0].Status)
+	assert.Equal(t, "AC04", payouts[0].RejectReason)
+	assert.Equal(t, domain.PayoutStatusSettled, payouts[1].Status)
+	assert.Equal(t, domain.PayoutStatusPending, payouts[2].Status)
+	assert.Equal(t, int64(15000), balance)
+}
+
+func TestPayout_CreateOverBalance(t *testing.T) {
+	// clean
+	for _, query := range []string{
+		`DELETE FROM payout WHERE uid='payout_over_balance';`,
+		`DELETE FROM customer WHERE uid='payout_poor_customer';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewPayoutRepository(PostgresConnection)
+	now := time.Date(2020, 8, 18, 10, 0, 0, 0, time.UTC)
+
+	// arrange
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "payout_poor_customer",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Bruce",
+		LastName:    "Wayne",
+		Phone:       "+79930000008",
+		CreatedAt:   now,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	// act
+	err = repository.Create(&domain.Payout{
+		GeneratedID: "payout_over_balance",
+		CustomerID:  "payout_poor_customer",
+		Amount:      10000,
+		Currency:    "EUR",
+		Status:      domain.PayoutStatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, &domain.Debit{
+		PayerID:  "payout_poor_customer",
+		PayeeID:  domain.LedgerAccountPayouts,
+		Amount:   10000,
+		Currency: "EUR",
+	})
+	payout, findErr := repository.FindByID("payout_over_balance")
+	if findErr != nil {
+		t.Error(findErr)
+	}
+
+	// assert
+	assert.Equal(t, domain.ErrInsufficientFunds, err)
+	assert.Nil(t, payout)
+}
@@ -0,0 +1,286 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	postingTableName = "posting"
+	holdTableName    = "hold"
+
+	holdStatusActive   = "active"
+	holdStatusReleased = "released"
+)
+
+var postingColumns = []string{
+	"uid",
+	"customeruid",
+	"amount",
+	"currency",
+	"description",
+	"reference",
+	"feescheduleversion",
+	"postedat",
+}
+
+var preparedPostingColumns = strings.Join(postingColumns, ", ")
+
+type PostingRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewPostingRepository(pgConn *pgxpool.Pool) *PostingRepository {
+	return &PostingRepository{pgConn: pgConn}
+}
+
+func (a *PostingRepository) Create(posting *domain.Posting) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		postingTableName,
+		preparedPostingColumns,
+		getSubstitutionVerbsForColumns(postingColumns),
+	)
+	_, err := a.pgConn.Exec(
+		context.Background(),
+		query,
+		posting.GeneratedID,
+		posting.CustomerID,
+		posting.Amount,
+		posting.Currency,
+		posting.Description,
+		posting.Reference,
+		posting.FeeScheduleVersion,
+		posting.PostedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *PostingRepository) FindBalance(customerID string, currency string, before time.Time) (int64, error) {
+	query := fmt.Sprintf(
+		`SELECT COALESCE(SUM(amount), 0) FROM %s WHERE customeruid=$1 AND currency=$2 AND postedat<$3;`,
+		postingTableName,
+	)
+
+	var balance int64
+	err := a.pgConn.QueryRow(context.Background(), query, customerID, currency, before).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+func (a *PostingRepository) StreamPostings(
+	customerID string,
+	currency string,
+	from, to time.Time,
+	handle func(posting *domain.Posting) error,
+) error {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 AND currency=$2 AND postedat>=$3 AND postedat<$4
+		ORDER BY postedat, uid;`,
+		preparedPostingColumns,
+		postingTableName,
+	)
+
+	// rows are read from connection one by one
+	rows, err := a.pgConn.Query(context.Background(), query, customerID, currency, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		posting, err := scanPosting(rows)
+		if err != nil {
+			return err
+		}
+		err = handle(posting)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (a *PostingRepository) FindByReference(reference string) (postings []*domain.Posting, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE reference=$1 ORDER BY uid;`,
+		preparedPostingColumns,
+		postingTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, reference)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		posting, err := scanPosting(rows)
+		if err != nil {
+			return nil, err
+		}
+		postings = append(postings, posting)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return postings, nil
+}
+
+func scanPosting(row pgx.Row) (*domain.Posting, error) {
+	posting := &domain.Posting{}
+	err := row.Scan(
+		&posting.GeneratedID,
+		&posting.CustomerID,
+		&posting.Amount,
+		&posting.Currency,
+		&posting.Description,
+		&posting.Reference,
+		&posting.FeeScheduleVersion,
+		&posting.PostedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return posting, nil
+}
+
+// createPostings saves postings of operation within transaction of operation
+func createPostings(tx pgx.Tx, postings []*domain.Posting) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		postingTableName,
+		preparedPostingColumns,
+		getSubstitutionVerbsForColumns(postingColumns),
+	)
+	for _, posting := range postings {
+		_, err := tx.Exec(
+			context.Background(),
+			query,
+			posting.GeneratedID,
+			posting.CustomerID,
+			posting.Amount,
+			posting.Currency,
+			posting.Description,
+			posting.Reference,
+			posting.FeeScheduleVersion,
+			posting.PostedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// createForcedPostings saves postings of operation which debits customers regardless of available balance,
+// like chargeback of merchant or capture of held amount. Debited customers are locked before postings are saved,
+// so that forced debits are serialized with other debits of customers.
+func createForcedPostings(tx pgx.Tx, postings []*domain.Posting) error {
+	locked := make(map[string]bool)
+	for _, posting := range postings {
+		if posting.Amount >= 0 || domain.IsLedgerAccount(posting.CustomerID) || locked[posting.CustomerID] {
+			continue
+		}
+		err := lockCustomer(tx, posting.CustomerID)
+		if err != nil {
+			return err
+		}
+		locked[posting.CustomerID] = true
+	}
+	return createPostings(tx, postings)
+}
+
+// placeHold reserves amount of customer balance in currency until hold is released
+func placeHold(
+	tx pgx.Tx,
+	holdID string,
+	customerID string,
+	amount int64,
+	currency string,
+	reference string,
+	createdAt time.Time,
+) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (uid, customeruid, amount, currency, reference, status, createdat)
+		VALUES ($1, $2, $3, $4, $5, '%s', $6);`,
+		holdTableName,
+		holdStatusActive,
+	)
+	_, err := tx.Exec(context.Background(), query, holdID, customerID, amount, currency, reference, createdAt)
+	return err
+}
+
+// releaseHold returns amount of active hold to available balance, released holds are left as they are
+func releaseHold(tx pgx.Tx, holdID string, releasedAt time.Time) error {
+	query := fmt.Sprintf(
+		`UPDATE %s SET status='%s', releasedat=$2 WHERE uid=$1 AND status='%s';`,
+		holdTableName,
+		holdStatusReleased,
+		holdStatusActive,
+	)
+	_, err := tx.Exec(context.Background(), query, holdID, releasedAt)
+	return err
+}
+
+// lockedAvailableBalance locks customer row till the end of transaction, so that concurrent debits of customer
+// could not overdraw available balance, and reads available balance of customer in currency
+func lockedAvailableBalance(tx pgx.Tx, customerID string, currency string) (*domain.AvailableBalance, error) {
+	err := lockCustomer(tx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	return findAvailableBalance(tx, customerID, currency)
+}
+
+// lockCustomer locks customer row till the end of transaction, debits and changes of credit limit
+// of customer are serialized by this lock
+func lockCustomer(tx pgx.Tx, customerID string) error {
+	query := fmt.Sprintf(`SELECT uid FROM %s WHERE uid=$1 FOR UPDATE;`, customerTableName)
+	var lockedID string
+	return tx.QueryRow(context.Background(), query, customerID).Scan(&lockedID)
+}
+
+// balanceQuerier is a pool or a transaction which available balance is read with
+type balanceQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// findAvailableBalance sums postings, credit limit and active holds of customer in currency
+func findAvailableBalance(
+	querier balanceQuerier,
+	customerID string,
+	currency string,
+) (*domain.AvailableBalance, error) {
+	query := fmt.Sprintf(
+		`SELECT
+			(SELECT COALESCE(SUM(amount), 0) FROM %s WHERE customeruid=$1 AND currency=$2),
+			(SELECT COALESCE(SUM(limitamount), 0) FROM %s WHERE customeruid=$1 AND currency=$2),
+			(SELECT COALESCE(SUM(amount), 0) FROM %s WHERE customeruid=$1 AND currency=$2 AND status='%s');`,
+		postingTableName,
+		creditLineTableName,
+		holdTableName,
+		holdStatusActive,
+	)
+	balance := &domain.AvailableBalance{}
+	err := querier.QueryRow(context.Background(), query, customerID, currency).Scan(
+		&balance.Balance,
+		&balance.Limit,
+		&balance.Holds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return balance, nil
+}
@@ -0,0 +1,98 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestPosting_BalanceAndStream(t *testing.T) {
+	// clean
+	_, err := PostgresConnection.Exec(context.Background(), `DELETE FROM posting;`)
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewPostingRepository(PostgresConnection)
+	from := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	// arrange
+	postings := []*domain.Posting{
+		{GeneratedID: "posting_1", Amount: 100000, Currency: "RUB", PostedAt: from.Add(-time.Hour)},
+		{GeneratedID: "posting_2", Amount: -25000, Currency: "RUB", PostedAt: from.Add(-time.Minute)},
+		{GeneratedID: "posting_3", Amount: 5000, Currency: "USD", PostedAt: from.Add(time.Hour)},
+		{GeneratedID: "posting_4", Amount: -1000, Currency: "RUB", PostedAt: from.Add(2 * time.Hour)},
+		{GeneratedID: "posting_5", Amount: 3000, Currency: "RUB", PostedAt: from.Add(time.Hour)},
+		{GeneratedID: "posting_6", Amount: 7000, Currency: "RUB", PostedAt: to},
+	}
+	for _, posting := range postings {
+		posting.CustomerID = "posting_customer"
+		posting.Description = "Transfer"
+		err = repository.Create(posting)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// act
+	balance, err := repository.FindBalance("posting_customer", "RUB", from)
+	if err != nil {
+		t.Error(err)
+	}
+	var streamed []string
+	err = repository.StreamPostings("posting_customer", "RUB", from, to, func(posting *domain.Posting) error {
+		streamed = append(streamed, posting.GeneratedID)
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Equal(t, int64(75000), balance)
+	assert.Equal(t, []string{"posting_5", "posting_4"}, streamed)
+}
+
+func TestPosting_FindByReference(t *testing.T) {
+	// clean
+	_, err := PostgresConnection.Exec(context.Background(), `DELETE FROM posting WHERE reference LIKE 'reference_%';`)
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewPostingRepository(PostgresConnection)
+	now := time.Date(2020, 8, 18, 10, 0, 0, 0, time.UTC)
+
+	// arrange
+	postings := []*domain.Posting{
+		{GeneratedID: "reference_1", CustomerID: "reference_payer", Amount: -1000, Reference: "reference_payment"},
+		{GeneratedID: "reference_2", CustomerID: "reference_payee", Amount: 1000, Reference: "reference_payment"},
+		{GeneratedID: "reference_3", CustomerID: "reference_payer", Amount: -500, Reference: "reference_other"},
+		{GeneratedID: "reference_4", CustomerID: domain.LedgerAccountFees, Amount: 10, Reference: "reference_payment",
+			FeeScheduleVersion: 2},
+	}
+	for _, posting := range postings {
+		posting.Currency = "RUB"
+		posting.PostedAt = now
+		err = repository.Create(posting)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	// act
+	found, err := repository.FindByReference("reference_payment")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Len(t, found, 3)
+	assert.Equal(t, "reference_payer", found[0].CustomerID)
+	assert.Equal(t, int64(1000), found[1].Amount)
+	assert.Equal(t, 2, found[2].FeeScheduleVersion)
+}
@@ -0,0 +1,288 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	qrPaymentRequestTableName = "qr_payment_request"
+	qrPaymentTableName        = "qr_payment"
+)
+
+var qrPaymentRequestColumns = []string{
+	"uid",
+	"merchantuid",
+	"type",
+	"amount",
+	"currency",
+	"purpose",
+	"status",
+	"expiresat",
+	"paidat",
+	"createdat",
+	"updatedat",
+}
+
+var preparedQRPaymentRequestColumns = strings.Join(qrPaymentRequestColumns, ", ")
+
+var qrPaymentColumns = []string{
+	"uid",
+	"requestuid",
+	"merchantuid",
+	"payeruid",
+	"amount",
+	"currency",
+	"riskassessmentuid",
+	"riskdecision",
+	"paidat",
+}
+
+var preparedQRPaymentColumns = strings.Join(qrPaymentColumns, ", ")
+
+type QRPaymentRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewQRPaymentRepository(pgConn *pgxpool.Pool) *QRPaymentRepository {
+	return &QRPaymentRepository{pgConn: pgConn}
+}
+
+func (a *QRPaymentRepository) CreateRequest(request *domain.QRPaymentRequest) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		qrPaymentRequestTableName,
+		preparedQRPaymentRequestColumns,
+		getSubstitutionVerbsForColumns(qrPaymentRequestColumns),
+	)
+	_, err := a.pgConn.Exec(
+		context.Background(),
+		query,
+		request.GeneratedID,
+		request.MerchantID,
+		request.Type,
+		request.Amount,
+		request.Currency,
+		request.Purpose,
+		request.Status,
+		nullableTime(request.ExpiresAt),
+		nullableTime(request.PaidAt),
+		request.CreatedAt,
+		request.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *QRPaymentRepository) FindRequestByID(requestID string) (request *domain.QRPaymentRequest, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedQRPaymentRequestColumns,
+		qrPaymentRequestTableName,
+	)
+
+	request, err = scanQRPaymentRequest(a.pgConn.QueryRow(context.Background(), query, requestID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (a *QRPaymentRepository) FindRequestsByMerchantID(
+	merchantID string,
+) (requests []*domain.QRPaymentRequest, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE merchantuid=$1 ORDER BY createdat;`,
+		preparedQRPaymentRequestColumns,
+		qrPaymentRequestTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		request, err := scanQRPaymentRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return requests, nil
+}
+
+func (a *QRPaymentRepository) AddPayment(
+	payment *domain.QRPayment,
+	requestStatus domain.QRPaymentRequestStatus,
+	debit *domain.Debit,
+) (added bool, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	// request row stays locked till commit, so payments of dynamic request are serialized
+	query := fmt.Sprintf(
+		`UPDATE %s SET (status, paidat, updatedat) = ROW ($2, $3, $3)
+		WHERE uid=$1 AND status=$4 AND (expiresat IS NULL OR expiresat>$3);`,
+		qrPaymentRequestTableName,
+	)
+	result, err := tx.Exec(
+		context.Background(),
+		query,
+		payment.RequestID,
+		requestStatus,
+		payment.PaidAt,
+		domain.QRPaymentRequestStatusActive,
+	)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, tx.Rollback(context.Background())
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		qrPaymentTableName,
+		preparedQRPaymentColumns,
+		getSubstitutionVerbsForColumns(qrPaymentColumns),
+	)
+	_, err = tx.Exec(
+		context.Background(),
+		query,
+		payment.GeneratedID,
+		payment.RequestID,
+		payment.MerchantID,
+		payment.PayerID,
+		payment.Amount,
+		payment.Currency,
+		payment.RiskAssessmentID,
+		payment.RiskDecision,
+		payment.PaidAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	err = createDebit(tx, debit)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *QRPaymentRepository) FindPayments(requestID string) (payments []*domain.QRPayment, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE requestuid=$1 ORDER BY paidat;`,
+		preparedQRPaymentColumns,
+		qrPaymentTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, requestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		payment, err := scanQRPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return payments, nil
+}
+
+func (a *QRPaymentRepository) FindPaymentByID(paymentID string) (payment *domain.QRPayment, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedQRPaymentColumns,
+		qrPaymentTableName,
+	)
+
+	payment, err = scanQRPayment(a.pgConn.QueryRow(context.Background(), query, paymentID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func scanQRPaymentRequest(row pgx.Row) (*domain.QRPaymentRequest, error) {
+	request := &domain.QRPaymentRequest{}
+	var expiresAt, paidAt *time.Time
+	err := row.Scan(
+		&request.GeneratedID,
+		&request.MerchantID,
+		&request.Type,
+		&request.Amount,
+		&request.Currency,
+		&request.Purpose,
+		&request.Status,
+		&expiresAt,
+		&paidAt,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt != nil {
+		request.ExpiresAt = *expiresAt
+	}
+	if paidAt != nil {
+		request.PaidAt = *paidAt
+	}
+	return request, nil
+}
+
+func scanQRPayment(row pgx.Row) (*domain.QRPayment, error) {
+	payment := &domain.QRPayment{}
+	err := row.Scan(
+		&payment.GeneratedID,
+		&payment.RequestID,
+		&payment.MerchantID,
+		&payment.PayerID,
+		&payment.Amount,
+		&payment.Currency,
+		&payment.RiskAssessmentID,
+		&payment.RiskDecision,
+		&payment.PaidAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
@@ -0,0 +1,164 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestQRPayment_AddPayment(t *testing.T) {
+	// clean
+	for _, query := range []string{
+		`DELETE FROM qr_payment_request;`,
+		`DELETE FROM qr_payment;`,
+		`DELETE FROM posting WHERE customeruid LIKE 'qr_%';`,
+		`DELETE FROM customer WHERE uid='qr_payer';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewQRPaymentRepository(PostgresConnection)
+	now := time.Date(2020, 8, 18, 10, 0, 0, 0, time.UTC)
+
+	// arrange
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "qr_payer",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Bruce",
+		LastName:    "Wayne",
+		Phone:       "+79930000004",
+		CreatedAt:   now,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = NewPostingRepository(PostgresConnection).Create(&domain.Posting{
+		GeneratedID: "qr_top_up",
+		CustomerID:  "qr_payer",
+		Amount:      100000,
+		Currency:    "RUB",
+		PostedAt:    now.Add(-time.Hour),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	requests := []*domain.QRPaymentRequest{
+		{
+			GeneratedID: "qr_static",
+			MerchantID:  "qr_merchant",
+			Type:        domain.QRPaymentRequestTypeStatic,
+			Currency:    "RUB",
+			Status:      domain.QRPaymentRequestStatusActive,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		},
+		{
+			GeneratedID: "qr_dynamic",
+			MerchantID:  "qr_merchant",
+			Type:        domain.QRPaymentRequestTypeDynamic,
+			Amount:      10000,
+			Currency:    "RUB",
+			Purpose:     "Order 15",
+			Status:      domain.QRPaymentRequestStatusActive,
+			ExpiresAt:   now.Add(time.Hour),
+			CreatedAt:   now.Add(time.Minute),
+			UpdatedAt:   now.Add(time.Minute),
+		},
+	}
+	for _, request := range requests {
+		err := repository.CreateRequest(request)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	payment := func(paymentID string, requestID string, paidAt time.Time) *domain.QRPayment {
+		return &domain.QRPayment{
+			GeneratedID: paymentID,
+			RequestID:   requestID,
+			MerchantID:  "qr_merchant",
+			PayerID:     "qr_payer",
+			Amount:      10000,
+			Currency:    "RUB",
+			PaidAt:      paidAt,
+		}
+	}
+	debit := func(paymentID string) *domain.Debit {
+		return &domain.Debit{
+			PayerID:   "qr_payer",
+			PayeeID:   "qr_merchant",
+			Amount:    10000,
+			Currency:  "RUB",
+			Reference: paymentID,
+			Postings: []*domain.Posting{
+				{GeneratedID: paymentID + "_0", CustomerID: "qr_payer", Amount: -10000, Currency: "RUB", PostedAt: now},
+				{GeneratedID: paymentID + "_1", CustomerID: "qr_merchant", Amount: 10000, Currency: "RUB", PostedAt: now},
+			},
+			PostedAt: now,
+		}
+	}
+
+	// act
+	var added []bool
+	for _, args := range []struct {
+		payment *domain.QRPayment
+		status  domain.QRPaymentRequestStatus
+	}{
+		{payment("qr_payment_1", "qr_static", now.Add(time.Minute)), domain.QRPaymentRequestStatusActive},
+		{payment("qr_payment_2", "qr_static", now.Add(2*time.Minute)), domain.QRPaymentRequestStatusActive},
+		{payment("qr_payment_3", "qr_dynamic", now.Add(2*time.Hour)), domain.QRPaymentRequestStatusPaid},
+		{payment("qr_payment_4", "qr_dynamic", now.Add(2*time.Minute)), domain.QRPaymentRequestStatusPaid},
+		{payment("qr_payment_5", "qr_dynamic", now.Add(3*time.Minute)), domain.QRPaymentRequestStatusPaid},
+	} {
+		ok, err := repository.AddPayment(args.payment, args.status, debit(args.payment.GeneratedID))
+		if err != nil {
+			t.Error(err)
+		}
+		added = append(added, ok)
+	}
+	merchantRequests, err := repository.FindRequestsByMerchantID("qr_merchant")
+	if err != nil {
+		t.Error(err)
+	}
+	staticPayments, err := repository.FindPayments("qr_static")
+	if err != nil {
+		t.Error(err)
+	}
+	dynamicPayments, err := repository.FindPayments("qr_dynamic")
+	if err != nil {
+		t.Error(err)
+	}
+	foundPayment, err := repository.FindPaymentByID("qr_payment_2")
+	if err != nil {
+		t.Error(err)
+	}
+	rejectedPayment, err := repository.FindPaymentByID("qr_payment_3")
+	if err != nil {
+		t.Error(err)
+	}
+	merchantBalance, err := NewPostingRepository(PostgresConnection).FindBalance("qr_merchant", "RUB", now.Add(time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Equal(t, []bool{true, true, false, true, false}, added)
+	assert.Len(t, merchantRequests, 2)
+	assert.Equal(t, domain.QRPaymentRequestStatusActive, merchantRequests[0].Status)
+	assert.True(t, now.Add(2*time.Minute).Equal(merchantRequests[0].PaidAt))
+	assert.True(t, merchantRequests[0].ExpiresAt.IsZero())
+	assert.Equal(t, domain.QRPaymentRequestStatusPaid, merchantRequests[1].Status)
+	assert.True(t, now.Add(time.Hour).Equal(merchantRequests[1].ExpiresAt))
+	assert.Len(t, staticPayments, 2)
+	assert.Len(t, dynamicPayments, 1)
+	assert.Equal(t, "qr_payment_4", dynamicPayments[0].GeneratedID)
+	assert.Equal(t, "qr_static", foundPayment.RequestID)
+	assert.Nil(t, rejectedPayment)
+	assert.Equal(t, int64(30000), merchantBalance)
+}
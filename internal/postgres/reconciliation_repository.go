@@ -0,0 +1,303 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	bankStatementTableName = "bank_statement"
+	bankEntryTableName     = "bank_entry"
+)
+
+var bankStatementColumns = []string{
+	"uid",
+	"bankstatementuid",
+	"accountiban",
+	"currency",
+	"openingbalance",
+	"closingbalance",
+	"importedat",
+}
+
+var preparedBankStatementColumns = strings.Join(bankStatementColumns, ", ")
+
+var bankEntryColumns = []string{
+	"uid",
+	"statementuid",
+	"direction",
+	"amount",
+	"currency",
+	"bookingdate",
+	"valuedate",
+	"reference",
+	"accountservicerreference",
+	"remittanceinfo",
+	"movementuid",
+	"matchtype",
+	"matchedat",
+	"createdat",
+}
+
+var preparedBankEntryColumns = strings.Join(bankEntryColumns, ", ")
+
+// bankMovementsQuery selects money movements of settlement bank account: payouts sent to bank as withdrawals
+// and postings of card settlement account. Card network is paid for captured authorizations and pays refunds back,
+// so credit postings of card settlement account are withdrawals and debit ones are deposits, customer of movement
+// is the one of counter leg. Its arguments are set by bankMovementsArgs.
+var bankMovementsQuery = fmt.Sprintf(
+	`SELECT uid, customeruid, $1::varchar AS type, amount, currency, createdat FROM %[1]s WHERE status IN ($3, $4)
+	UNION ALL
+	SELECT uid, COALESCE((
+		SELECT leg.customeruid FROM %[2]s leg WHERE leg.reference=posting.reference AND leg.customeruid<>$5 LIMIT 1
+	), customeruid), CASE WHEN amount>0 THEN $1::varchar ELSE $2::varchar END, abs(amount), currency, postedat
+	FROM %[2]s posting WHERE customeruid=$5 AND amount<>0`,
+	payoutTableName,
+	postingTableName,
+)
+
+func bankMovementsArgs(args ...interface{}) []interface{} {
+	return append(
+		[]interface{}{
+			domain.MovementTypeWithdrawal,
+			domain.MovementTypeDeposit,
+			domain.PayoutStatusSubmitted,
+			domain.PayoutStatusSettled,
+			domain.LedgerAccountCardSettlement,
+		},
+		args...,
+	)
+}
+
+type ReconciliationRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewReconciliationRepository(pgConn *pgxpool.Pool) *ReconciliationRepository {
+	return &ReconciliationRepository{pgConn: pgConn}
+}
+
+func (a *ReconciliationRepository) CreateStatement(statement *domain.BankStatement) (created bool, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (uid) DO NOTHING;`,
+		bankStatementTableName,
+		preparedBankStatementColumns,
+		getSubstitutionVerbsForColumns(bankStatementColumns),
+	)
+	result, err := tx.Exec(
+		context.Background(),
+		query,
+		statement.GeneratedID,
+		statement.BankStatementID,
+		statement.AccountIBAN,
+		statement.Currency,
+		statement.OpeningBalance,
+		statement.ClosingBalance,
+		statement.ImportedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	if result.RowsAffected() == 0 {
+		return false, tx.Rollback(context.Background())
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		bankEntryTableName,
+		preparedBankEntryColumns,
+		getSubstitutionVerbsForColumns(bankEntryColumns),
+	)
+	for _, entry := range statement.Entries {
+		_, err = tx.Exec(
+			context.Background(),
+			query,
+			entry.GeneratedID,
+			entry.StatementID,
+			entry.Direction,
+			entry.Amount,
+			entry.Currency,
+			entry.BookingDate,
+			entry.ValueDate,
+			entry.Reference,
+			entry.AccountServicerReference,
+			entry.RemittanceInfo,
+			entry.MovementID,
+			entry.MatchType,
+			nullableTime(entry.MatchedAt),
+			entry.CreatedAt,
+		)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (a *ReconciliationRepository) FindEntryByID(entryID string) (entry *domain.BankEntry, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedBankEntryColumns,
+		bankEntryTableName,
+	)
+
+	entry, err = scanBankEntry(a.pgConn.QueryRow(context.Background(), query, entryID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (a *ReconciliationRepository) FindEntries(
+	account domain.BankAccount,
+	from, to time.Time,
+) (entries []*domain.BankEntry, err error) {
+	return a.findEntries(``, account, from, to)
+}
+
+func (a *ReconciliationRepository) FindUnmatchedEntries(
+	account domain.BankAccount,
+	from, to time.Time,
+) (entries []*domain.BankEntry, err error) {
+	return a.findEntries(`AND movementuid=''`, account, from, to)
+}
+
+func (a *ReconciliationRepository) FindMovementByID(movementID string) (movement *domain.Movement, err error) {
+	query := fmt.Sprintf(`SELECT %s FROM (%s) movement WHERE uid=$6;`, preparedMovementColumns, bankMovementsQuery)
+
+	movement, err = scanMovement(a.pgConn.QueryRow(context.Background(), query, bankMovementsArgs(movementID)...))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+func (a *ReconciliationRepository) FindUnmatchedMovements(
+	currency string,
+	from, to time.Time,
+) (movements []*domain.Movement, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM (%s) movement WHERE currency=$6 AND createdat>=$7 AND createdat<$8
+		AND NOT EXISTS (SELECT 1 FROM %s WHERE movementuid=movement.uid)
+		ORDER BY createdat, uid;`,
+		preparedMovementColumns,
+		bankMovementsQuery,
+		bankEntryTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, bankMovementsArgs(currency, from, to)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanMovements(rows)
+}
+
+func (a *ReconciliationRepository) Match(
+	entryID string,
+	movementID string,
+	matchType domain.BankEntryMatchType,
+	matchedAt time.Time,
+) (bool, error) {
+	query := fmt.Sprintf(
+		`UPDATE %[1]s SET (movementuid, matchtype, matchedat) = ROW ($2, $3, $4)
+		WHERE uid=$1 AND movementuid='' AND NOT EXISTS (SELECT 1 FROM %[1]s WHERE movementuid=$2);`,
+		bankEntryTableName,
+	)
+
+	result, err := a.pgConn.Exec(context.Background(), query, entryID, movementID, matchType, matchedAt)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+// findEntries returns entries of account booked between from and to dates inclusive, filtered by condition
+func (a *ReconciliationRepository) findEntries(
+	condition string,
+	account domain.BankAccount,
+	from, to time.Time,
+) (entries []*domain.BankEntry, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE bookingdate>=$1 AND bookingdate<=$2 AND currency=$4
+		AND statementuid IN (SELECT uid FROM %s WHERE accountiban=$3 AND currency=$4) %s ORDER BY bookingdate, uid;`,
+		preparedBankEntryColumns,
+		bankEntryTableName,
+		bankStatementTableName,
+		condition,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, from, to, account.IBAN, account.Currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry *domain.BankEntry
+		entry, err = scanBankEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return entries, nil
+}
+
+func scanBankEntry(row pgx.Row) (*domain.BankEntry, error) {
+	entry := &domain.BankEntry{}
+	var matchedAt *time.Time
+	err := row.Scan(
+		&entry.GeneratedID,
+		&entry.StatementID,
+		&entry.Direction,
+		&entry.Amount,
+		&entry.Currency,
+		&entry.BookingDate,
+		&entry.ValueDate,
+		&entry.Reference,
+		&entry.AccountServicerReference,
+		&entry.RemittanceInfo,
+		&entry.MovementID,
+		&entry.MatchType,
+		&matchedAt,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if matchedAt != nil {
+		entry.MatchedAt = *matchedAt
+	}
+	return entry, nil
+}
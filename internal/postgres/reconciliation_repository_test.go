@@ -0,0 +1,182 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestReconciliation_ImportAndMatch(t *testing.T) {
+	// clean
+	queries := []string{
+		`DELETE FROM bank_entry;`,
+		`DELETE FROM bank_statement;`,
+		`DELETE FROM payout WHERE uid LIKE 'reconciliation_%';`,
+		`DELETE FROM posting WHERE reference LIKE 'reconciliation:%';`,
+	}
+	for _, query := range queries {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewReconciliationRepository(PostgresConnection)
+	postingRepository := NewPostingRepository(PostgresConnection)
+	day := time.Date(2020, 8, 3, 0, 0, 0, 0, time.UTC)
+
+	// arrange
+	payouts := map[string]domain.PayoutStatus{
+		"reconciliation_payout_1": domain.PayoutStatusSubmitted,
+		"reconciliation_payout_2": domain.PayoutStatusPending,
+		"reconciliation_payout_3": domain.PayoutStatusRejected,
+		"reconciliation_payout_4": domain.PayoutStatusSubmitted,
+	}
+	for payoutID, status := range payouts {
+		currency := "RUB"
+		if payoutID == "reconciliation_payout_4" {
+			currency = "USD"
+		}
+		_, err := PostgresConnection.Exec(
+			context.Background(),
+			`INSERT INTO payout (uid, customeruid, amount, currency, creditorname, creditoriban, status, createdat)
+			VALUES ($1, 'customer', 2500, $2, 'Creditor', 'DE89370400440532013000', $3, $4);`,
+			payoutID,
+			currency,
+			status,
+			day.Add(2*time.Hour),
+		)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	postings := []*domain.Posting{
+		{
+			GeneratedID: "reconciliation_posting_1",
+			CustomerID:  domain.LedgerAccountCardSettlement,
+			Amount:      -10000,
+			Reference:   "reconciliation:1",
+		},
+		{GeneratedID: "reconciliation_posting_4", CustomerID: "customer", Amount: 10000, Reference: "reconciliation:1"},
+		{GeneratedID: "reconciliation_posting_2", CustomerID: "customer", Amount: -500, Reference: "reconciliation:2"},
+		{GeneratedID: "reconciliation_posting_3", CustomerID: "payee", Amount: 500, Reference: "reconciliation:2"},
+	}
+	for _, posting := range postings {
+		posting.Currency = "RUB"
+		posting.PostedAt = day.Add(time.Hour)
+		err := postingRepository.Create(posting)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	statement := &domain.BankStatement{
+		GeneratedID:     "statement_1",
+		BankStatementID: "STMT-1",
+		AccountIBAN:     "DE89370400440532013000",
+		Currency:        "RUB",
+		OpeningBalance:  0,
+		ClosingBalance:  7500,
+		ImportedAt:      day.AddDate(0, 0, 1),
+		Entries: []*domain.BankEntry{
+			{
+				GeneratedID: "entry_1",
+				Direction:   domain.BankEntryDirectionCredit,
+				Amount:      10000,
+				Reference:   "reconciliation_posting_1",
+			},
+			{GeneratedID: "entry_2", Direction: domain.BankEntryDirectionDebit, Amount: 2500},
+		},
+	}
+	otherStatement := &domain.BankStatement{
+		GeneratedID:     "statement_2",
+		BankStatementID: "STMT-1",
+		AccountIBAN:     "RU0204452560040702810412345678901",
+		Currency:        "RUB",
+		ImportedAt:      day.AddDate(0, 0, 1),
+		Entries: []*domain.BankEntry{
+			{GeneratedID: "entry_3", Direction: domain.BankEntryDirectionDebit, Amount: 2500},
+		},
+	}
+	for _, entry := range append(statement.Entries, otherStatement.Entries...) {
+		entry.StatementID = statement.GeneratedID
+		entry.Currency = "RUB"
+		entry.BookingDate = day
+		entry.ValueDate = day
+		entry.CreatedAt = statement.ImportedAt
+	}
+	otherStatement.Entries[0].StatementID = otherStatement.GeneratedID
+
+	// act
+	created, err := repository.CreateStatement(statement)
+	if err != nil {
+		t.Error(err)
+	}
+	duplicateCreated, err := repository.CreateStatement(statement)
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = repository.CreateStatement(otherStatement)
+	if err != nil {
+		t.Error(err)
+	}
+	matched, err := repository.Match(
+		"entry_1",
+		"reconciliation_posting_1",
+		domain.BankEntryMatchTypeReference,
+		day.AddDate(0, 0, 1),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	matchedTwice, err := repository.Match(
+		"entry_2",
+		"reconciliation_posting_1",
+		domain.BankEntryMatchTypeManual,
+		day.AddDate(0, 0, 1),
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	unmatchedEntries, err := repository.FindUnmatchedEntries(
+		domain.BankAccount{IBAN: "DE89370400440532013000", Currency: "RUB"},
+		day,
+		day,
+	)
+	if err != nil {
+		t.Error(err)
+	}
+	unmatchedMovements, err := repository.FindUnmatchedMovements("RUB", day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Error(err)
+	}
+	movement, err := repository.FindMovementByID("reconciliation_posting_1")
+	if err != nil {
+		t.Error(err)
+	}
+	entry, err := repository.FindEntryByID("entry_1")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.True(t, created)
+	assert.False(t, duplicateCreated)
+	assert.True(t, matched)
+	assert.False(t, matchedTwice)
+	assert.Len(t, unmatchedEntries, 1)
+	assert.Equal(t, "entry_2", unmatchedEntries[0].GeneratedID)
+	assert.Len(t, unmatchedMovements, 1)
+	assert.Equal(t, "reconciliation_payout_1", unmatchedMovements[0].GeneratedID)
+	assert.Equal(t, domain.MovementTypeWithdrawal, unmatchedMovements[0].Type)
+	assert.Equal(t, int64(2500), unmatchedMovements[0].Amount)
+	assert.Equal(t, domain.MovementTypeDeposit, movement.Type)
+	assert.Equal(t, "customer", movement.CustomerID)
+	assert.Equal(t, int64(10000), movement.Amount)
+	assert.Equal(t, "reconciliation_posting_1", entry.MovementID)
+	assert.Equal(t, domain.BankEntryMatchTypeReference, entry.MatchType)
+	assert.False(t, entry.MatchedAt.IsZero())
+}
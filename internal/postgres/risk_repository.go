@@ -0,0 +1,237 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	riskAssessmentTableName = "risk_assessment"
+	riskDeviceTableName     = "risk_device"
+)
+
+var riskAssessmentColumns = []string{
+	"uid",
+	"customeruid",
+	"amount",
+	"currency",
+	"deviceid",
+	"ip",
+	"score",
+	"decision",
+	"features",
+	"createdat",
+}
+
+var preparedRiskAssessmentColumns = strings.Join(riskAssessmentColumns, ", ")
+
+var riskDeviceColumns = []string{
+	"customeruid",
+	"deviceid",
+	"ip",
+	"lastseenat",
+}
+
+var preparedRiskDeviceColumns = strings.Join(riskDeviceColumns, ", ")
+
+// riskFeatureRow is a json representation of domain.RiskFeature stored in features column
+type riskFeatureRow struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Score int    `json:"score"`
+}
+
+type RiskRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewRiskRepository(pgConn *pgxpool.Pool) *RiskRepository {
+	return &RiskRepository{pgConn: pgConn}
+}
+
+func (a *RiskRepository) CreateAssessment(assessment *domain.RiskAssessment) error {
+	rows := make([]riskFeatureRow, 0, len(assessment.Features))
+	for _, feature := range assessment.Features {
+		rows = append(rows, riskFeatureRow{Name: feature.Name, Value: feature.Value, Score: feature.Score})
+	}
+	features, err := json.Marshal(rows)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		riskAssessmentTableName,
+		preparedRiskAssessmentColumns,
+		getSubstitutionVerbsForColumns(riskAssessmentColumns),
+	)
+	_, err = a.pgConn.Exec(
+		context.Background(),
+		query,
+		assessment.GeneratedID,
+		assessment.CustomerID,
+		assessment.Amount,
+		assessment.Currency,
+		assessment.DeviceID,
+		assessment.IP,
+		assessment.Score,
+		assessment.Decision,
+		string(features),
+		assessment.CreatedAt,
+	)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *RiskRepository) FindAssessmentByID(assessmentID string) (assessment *domain.RiskAssessment, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedRiskAssessmentColumns,
+		riskAssessmentTableName,
+	)
+
+	assessment, err = scanRiskAssessment(a.pgConn.QueryRow(context.Background(), query, assessmentID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return assessment, nil
+}
+
+func (a *RiskRepository) FindAssessments(
+	customerID string,
+	since time.Time,
+) (assessments []*domain.RiskAssessment, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 AND createdat>$2 ORDER BY createdat;`,
+		preparedRiskAssessmentColumns,
+		riskAssessmentTableName,
+	)
+
+	rows, err := a.pgConn.Query(
+		context.Background(),
+		query,
+		customerID,
+		since,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var assessment *domain.RiskAssessment
+		assessment, err = scanRiskAssessment(rows)
+		if err != nil {
+			return nil, err
+		}
+		assessments = append(assessments, assessment)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return assessments, nil
+}
+
+func (a *RiskRepository) FindDevices(customerID string) (devices []domain.RiskDevice, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1;`,
+		preparedRiskDeviceColumns,
+		riskDeviceTableName,
+	)
+
+	rows, err := a.pgConn.Query(
+		context.Background(),
+		query,
+		customerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		device := domain.RiskDevice{}
+		err = rows.Scan(
+			&device.CustomerID,
+			&device.DeviceID,
+			&device.IP,
+			&device.LastSeenAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return devices, nil
+}
+
+func (a *RiskRepository) SaveDevice(device domain.RiskDevice) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (customeruid, deviceid, ip) DO UPDATE SET lastseenat=$4;`,
+		riskDeviceTableName,
+		preparedRiskDeviceColumns,
+		getSubstitutionVerbsForColumns(riskDeviceColumns),
+	)
+	_, err := a.pgConn.Exec(
+		context.Background(),
+		query,
+		device.CustomerID,
+		device.DeviceID,
+		device.IP,
+		device.LastSeenAt,
+	)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func scanRiskAssessment(row pgx.Row) (*domain.RiskAssessment, error) {
+	assessment := &domain.RiskAssessment{}
+	var features []byte
+	err := row.Scan(
+		&assessment.GeneratedID,
+		&assessment.CustomerID,
+		&assessment.Amount,
+		&assessment.Currency,
+		&assessment.DeviceID,
+		&assessment.IP,
+		&assessment.Score,
+		&assessment.Decision,
+		&features,
+		&assessment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []riskFeatureRow
+	err = json.Unmarshal(features, &rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		assessment.Features = append(
+			assessment.Features,
+			domain.RiskFeature{Name: row.Name, Value: row.Value, Score: row.Score},
+		)
+	}
+	return assessment, nil
+}
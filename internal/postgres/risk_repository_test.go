@@ -0,0 +1,103 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestRisk_CreateAssessment_Find(t *testing.T) {
+	t.Parallel()
+
+	// clean
+	query := `DELETE FROM risk_assessment WHERE customeruid = $1;`
+	_, err := PostgresConnection.Exec(context.Background(), query, "risk_customer")
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewRiskRepository(PostgresConnection)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// arrange
+	assessment := &domain.RiskAssessment{
+		GeneratedID: "risk_assessment",
+		CustomerID:  "risk_customer",
+		Amount:      1000,
+		Currency:    "RUB",
+		DeviceID:    "phone",
+		IP:          "10.0.0.1",
+		Score:       30,
+		Decision:    domain.RiskDecisionApprove,
+		Features: []domain.RiskFeature{
+			{Name: "device", Value: "new", Score: 20},
+			{Name: "ip", Value: "new", Score: 10},
+		},
+		CreatedAt: now,
+	}
+
+	// act
+	err = repository.CreateAssessment(assessment)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	foundAssessment, err := repository.FindAssessmentByID("risk_assessment")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, assessment.Features, foundAssessment.Features)
+	assert.Equal(t, assessment.Decision, foundAssessment.Decision)
+	assert.True(t, assessment.CreatedAt.Equal(foundAssessment.CreatedAt))
+
+	assessments, err := repository.FindAssessments("risk_customer", now.Add(-time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Len(t, assessments, 1)
+
+	assessments, err = repository.FindAssessments("risk_customer", now)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Len(t, assessments, 0)
+}
+
+func TestRisk_SaveDevice(t *testing.T) {
+	t.Parallel()
+
+	// clean
+	query := `DELETE FROM risk_device WHERE customeruid = $1;`
+	_, err := PostgresConnection.Exec(context.Background(), query, "risk_customer")
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewRiskRepository(PostgresConnection)
+	firstSeen := time.Now().UTC().Truncate(time.Microsecond).Add(-time.Hour)
+	lastSeen := firstSeen.Add(time.Hour)
+
+	// act
+	device := domain.RiskDevice{CustomerID: "risk_customer", DeviceID: "phone", IP: "10.0.0.1", LastSeenAt: firstSeen}
+	err = repository.SaveDevice(device)
+	if err != nil {
+		t.Error(err)
+	}
+	device.LastSeenAt = lastSeen
+	err = repository.SaveDevice(device)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	devices, err := repository.FindDevices("risk_customer")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Len(t, devices, 1)
+	assert.True(t, lastSeen.Equal(devices[0].LastSeenAt))
+}
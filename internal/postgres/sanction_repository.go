@@ -0,0 +1,251 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	sanctionEntryTableName = "sanction_entry"
+	sanctionMatchTableName = "sanction_match"
+)
+
+var sanctionEntryColumns = []string{
+	"listname",
+	"listtype",
+	"fullname",
+	"birthdate",
+}
+
+var preparedSanctionEntryColumns = strings.Join(sanctionEntryColumns, ", ")
+
+var sanctionMatchColumns = []string{
+	"customeruid",
+	"listname",
+	"listtype",
+	"entryname",
+	"entrybirthdate",
+	"matchedname",
+	"score",
+	"decision",
+	"reviewer",
+	"comment",
+	"createdat",
+	"reviewedat",
+}
+
+var preparedSanctionMatchColumns = strings.Join(sanctionMatchColumns, ", ")
+
+type SanctionRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewSanctionRepository(pgConn *pgxpool.Pool) *SanctionRepository {
+	return &SanctionRepository{pgConn: pgConn}
+}
+
+func (a *SanctionRepository) ReplaceList(listName string, entries []domain.SanctionEntry) (err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`DELETE FROM %s WHERE listname=$1;`,
+		sanctionEntryTableName,
+	)
+	_, err = tx.Exec(context.Background(), query, listName)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		rows = append(rows, []interface{}{
+			listName,
+			string(entry.ListType),
+			entry.FullName,
+			nullableTime(entry.BirthDate),
+		})
+	}
+	_, err = tx.CopyFrom(
+		context.Background(),
+		pgx.Identifier{sanctionEntryTableName},
+		sanctionEntryColumns,
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (a *SanctionRepository) FindCandidates(birthDate time.Time) (entries []domain.SanctionEntry, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE birthdate=$1 OR birthdate IS NULL;`,
+		preparedSanctionEntryColumns,
+		sanctionEntryTableName,
+	)
+
+	rows, err := a.pgConn.Query(
+		context.Background(),
+		query,
+		birthDate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := domain.SanctionEntry{}
+		var entryBirthDate *time.Time
+		err = rows.Scan(
+			&entry.ListName,
+			&entry.ListType,
+			&entry.FullName,
+			&entryBirthDate,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if entryBirthDate != nil {
+			entry.BirthDate = *entryBirthDate
+		}
+		entries = append(entries, entry)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return entries, nil
+}
+
+func (a *SanctionRepository) CreateMatches(matches []domain.SanctionMatch) (err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		sanctionMatchTableName,
+		preparedSanctionMatchColumns,
+		getSubstitutionVerbsForColumns(sanctionMatchColumns),
+	)
+	for _, match := range matches {
+		_, err = tx.Exec(
+			context.Background(),
+			query,
+			match.CustomerID,
+			match.ListName,
+			match.ListType,
+			match.EntryName,
+			nullableTime(match.EntryBirthDate),
+			match.MatchedName,
+			match.Score,
+			match.Decision,
+			match.Reviewer,
+			match.Comment,
+			match.CreatedAt,
+			nullableTime(match.ReviewedAt),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func (a *SanctionRepository) FindMatchesByCustomerID(customerID string) (matches []domain.SanctionMatch, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 ORDER BY createdat;`,
+		preparedSanctionMatchColumns,
+		sanctionMatchTableName,
+	)
+
+	rows, err := a.pgConn.Query(
+		context.Background(),
+		query,
+		customerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		match := domain.SanctionMatch{}
+		var entryBirthDate, reviewedAt *time.Time
+		err = rows.Scan(
+			&match.CustomerID,
+			&match.ListName,
+			&match.ListType,
+			&match.EntryName,
+			&entryBirthDate,
+			&match.MatchedName,
+			&match.Score,
+			&match.Decision,
+			&match.Reviewer,
+			&match.Comment,
+			&match.CreatedAt,
+			&reviewedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if entryBirthDate != nil {
+			match.EntryBirthDate = *entryBirthDate
+		}
+		if reviewedAt != nil {
+			match.ReviewedAt = *reviewedAt
+		}
+		matches = append(matches, match)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return matches, nil
+}
+
+func (a *SanctionRepository) ResolveMatches(
+	customerID string,
+	decision domain.SanctionDecision,
+	reviewer string,
+	comment string,
+) error {
+	query := fmt.Sprintf(
+		`UPDATE %s SET decision=$1, reviewer=$2, comment=$3, reviewedat=NOW() WHERE customeruid=$4 AND decision='';`,
+		sanctionMatchTableName,
+	)
+	_, err := a.pgConn.Exec(
+		context.Background(),
+		query,
+		decision,
+		reviewer,
+		comment,
+		customerID,
+	)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
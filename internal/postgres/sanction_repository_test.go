@@ -0,0 +1,97 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestSanction_ReplaceList_FindCandidates(t *testing.T) {
+	t.Parallel()
+
+	repository := NewSanctionRepository(PostgresConnection)
+	birthDate, _ := time.Parse(domain.DateFormat, "01-01-1970")
+	otherBirthDate, _ := time.Parse(domain.DateFormat, "02-01-1970")
+
+	// arrange
+	err := repository.ReplaceList("integration_test", []domain.SanctionEntry{
+		{ListType: domain.SanctionListTypeSanctions, FullName: "old entry"},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	// act
+	err = repository.ReplaceList("integration_test", []domain.SanctionEntry{
+		{ListType: domain.SanctionListTypeSanctions, FullName: "with birth date", BirthDate: birthDate},
+		{ListType: domain.SanctionListTypeSanctions, FullName: "with other birth date", BirthDate: otherBirthDate},
+		{ListType: domain.SanctionListTypePEP, FullName: "without birth date"},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	entries, err := repository.FindCandidates(birthDate)
+	if err != nil {
+		t.Error(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.ListName == "integration_test" {
+			names = append(names, entry.FullName)
+		}
+	}
+	assert.ElementsMatch(t, []string{"with birth date", "without birth date"}, names)
+}
+
+func TestSanction_CreateMatches_Resolve(t *testing.T) {
+	t.Parallel()
+
+	// clean
+	query := `DELETE FROM sanction_match WHERE customeruid = $1;`
+	_, err := PostgresConnection.Exec(context.Background(), query, "sanction_customer")
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewSanctionRepository(PostgresConnection)
+
+	// arrange
+	match := domain.SanctionMatch{
+		CustomerID:  "sanction_customer",
+		ListName:    "integration_test",
+		ListType:    domain.SanctionListTypeSanctions,
+		EntryName:   "ИВАНОВ Иван",
+		MatchedName: "Ivan Ivanov",
+		Score:       0.97,
+		CreatedAt:   time.Now(),
+	}
+
+	// act
+	err = repository.CreateMatches([]domain.SanctionMatch{match})
+	if err != nil {
+		t.Error(err)
+	}
+	err = repository.ResolveMatches("sanction_customer", domain.SanctionDecisionCleared, "alfred", "ok")
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	matches, err := repository.FindMatchesByCustomerID("sanction_customer")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Len(t, matches, 1)
+	assert.Equal(t, match.EntryName, matches[0].EntryName)
+	assert.Equal(t, match.Score, matches[0].Score)
+	assert.True(t, matches[0].EntryBirthDate.IsZero())
+	assert.Equal(t, domain.SanctionDecisionCleared, matches[0].Decision)
+	assert.Equal(t, "alfred", matches[0].Reviewer)
+	assert.False(t, matches[0].ReviewedAt.IsZero())
+}
@@ -0,0 +1,205 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	splitPaymentTableName = "split_payment"
+	splitLegTableName     = "split_leg"
+)
+
+var splitPaymentColumns = []string{
+	"uid",
+	"payeruid",
+	"amount",
+	"currency",
+	"description",
+	"riskassessmentuid",
+	"riskdecision",
+	"createdat",
+}
+
+var preparedSplitPaymentColumns = strings.Join(splitPaymentColumns, ", ")
+
+var splitLegColumns = []string{
+	"paymentuid",
+	"position",
+	"recipientuid",
+	"role",
+	"percent",
+	"amount",
+	"currency",
+	"createdat",
+}
+
+var preparedSplitLegColumns = strings.Join(splitLegColumns, ", ")
+
+type SplitPaymentRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewSplitPaymentRepository(pgConn *pgxpool.Pool) *SplitPaymentRepository {
+	return &SplitPaymentRepository{pgConn: pgConn}
+}
+
+func (a *SplitPaymentRepository) Create(payment *domain.SplitPayment, debit *domain.Debit) (err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		splitPaymentTableName,
+		preparedSplitPaymentColumns,
+		getSubstitutionVerbsForColumns(splitPaymentColumns),
+	)
+	_, err = tx.Exec(
+		context.Background(),
+		query,
+		payment.GeneratedID,
+		payment.PayerID,
+		payment.Amount,
+		payment.Currency,
+		payment.Description,
+		payment.RiskAssessmentID,
+		payment.RiskDecision,
+		payment.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	query = fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		splitLegTableName,
+		preparedSplitLegColumns,
+		getSubstitutionVerbsForColumns(splitLegColumns),
+	)
+	for i, leg := range payment.Legs {
+		_, err = tx.Exec(
+			context.Background(),
+			query,
+			payment.GeneratedID,
+			i,
+			leg.RecipientID,
+			leg.Role,
+			leg.Percent,
+			leg.Amount,
+			leg.Currency,
+			leg.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = createDebit(tx, debit)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(context.Background())
+}
+
+func (a *SplitPaymentRepository) FindByID(paymentID string) (payment *domain.SplitPayment, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedSplitPaymentColumns,
+		splitPaymentTableName,
+	)
+
+	payment = &domain.SplitPayment{}
+	err = a.pgConn.QueryRow(context.Background(), query, paymentID).Scan(
+		&payment.GeneratedID,
+		&payment.PayerID,
+		&payment.Amount,
+		&payment.Currency,
+		&payment.Description,
+		&payment.RiskAssessmentID,
+		&payment.RiskDecision,
+		&payment.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query = fmt.Sprintf(
+		`SELECT %s FROM %s WHERE paymentuid=$1 ORDER BY position;`,
+		preparedSplitLegColumns,
+		splitLegTableName,
+	)
+	rows, err := a.pgConn.Query(context.Background(), query, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	legs, err := scanSplitLegs(rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, leg := range legs {
+		payment.Legs = append(payment.Legs, *leg)
+	}
+	return payment, nil
+}
+
+func (a *SplitPaymentRepository) FindReceivedLegs(
+	recipientID string,
+	from, to time.Time,
+) (legs []*domain.SplitLeg, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE recipientuid=$1 AND createdat>=$2 AND createdat<$3
+		ORDER BY createdat DESC, paymentuid, position;`,
+		preparedSplitLegColumns,
+		splitLegTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, recipientID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSplitLegs(rows)
+}
+
+func scanSplitLegs(rows pgx.Rows) ([]*domain.SplitLeg, error) {
+	var legs []*domain.SplitLeg
+	for rows.Next() {
+		leg := &domain.SplitLeg{}
+		var position int
+		err := rows.Scan(
+			&leg.PaymentID,
+			&position,
+			&leg.RecipientID,
+			&leg.Role,
+			&leg.Percent,
+			&leg.Amount,
+			&leg.Currency,
+			&leg.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		legs = append(legs, leg)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return legs, nil
+}
@@ -0,0 +1,126 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestSplitPayment_Create(t *testing.T) {
+	// clean
+	for _, query := range []string{
+		`DELETE FROM split_payment;`,
+		`DELETE FROM split_leg;`,
+		`DELETE FROM posting WHERE customeruid LIKE 'split_%';`,
+		`DELETE FROM customer WHERE uid='split_payer';`,
+		`DELETE FROM limit_usage WHERE customeruid='split_payer';`,
+	} {
+		_, err := PostgresConnection.Exec(context.Background(), query)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewSplitPaymentRepository(PostgresConnection)
+	now := time.Date(2020, 8, 18, 10, 0, 0, 0, time.UTC)
+
+	// arrange
+	err := Repository.Create(&domain.Customer{
+		GeneratedID: "split_payer",
+		Status:      domain.CustomerStatusActive,
+		FirstName:   "Bruce",
+		LastName:    "Wayne",
+		Phone:       "+79930000001",
+		CreatedAt:   now,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = NewPostingRepository(PostgresConnection).Create(&domain.Posting{
+		GeneratedID: "split_top_up",
+		CustomerID:  "split_payer",
+		Amount:      15000,
+		Currency:    "RUB",
+		PostedAt:    now.Add(-time.Hour),
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	payment := func(paymentID string) *domain.SplitPayment {
+		return &domain.SplitPayment{
+			GeneratedID: paymentID,
+			PayerID:     "split_payer",
+			Amount:      10000,
+			Currency:    "RUB",
+			Legs: []domain.SplitLeg{
+				{RecipientID: "split_seller", Role: "seller", Percent: "90", Amount: 9000, Currency: "RUB", CreatedAt: now},
+				{RecipientID: "split_platform", Role: "platform", Amount: 1000, Currency: "RUB", CreatedAt: now},
+			},
+			CreatedAt: now,
+		}
+	}
+	limit := &domain.LimitCharge{
+		CustomerID: "split_payer",
+		Currency:   "RUB",
+		Amount:     10000,
+		Limits: domain.TransactionLimits{
+			PerTransaction: 10000, Daily: 10000, Monthly: 10000, DailyCount: 1, MonthlyCount: 1,
+		},
+		ChargedAt: now,
+	}
+
+	debit := func(paymentID string) *domain.Debit {
+		return &domain.Debit{
+			PayerID:  "split_payer",
+			Amount:   10000,
+			Currency: "RUB",
+			Limit:    limit,
+			Postings: []*domain.Posting{
+				{GeneratedID: paymentID + "_0", CustomerID: "split_payer", Amount: -10000, Currency: "RUB", PostedAt: now},
+				{GeneratedID: paymentID + "_1", CustomerID: "split_seller", Amount: 9000, Currency: "RUB", PostedAt: now},
+				{GeneratedID: paymentID + "_2", CustomerID: "split_platform", Amount: 1000, Currency: "RUB", PostedAt: now},
+			},
+		}
+	}
+
+	// act
+	createErr := repository.Create(payment("split_1"), debit("split_1"))
+	overBalanceErr := repository.Create(payment("split_2"), debit("split_2"))
+	found, err := repository.FindByID("split_1")
+	if err != nil {
+		t.Error(err)
+	}
+	notFound, err := repository.FindByID("split_2")
+	if err != nil {
+		t.Error(err)
+	}
+	received, err := repository.FindReceivedLegs("split_seller", now, now.Add(time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+	sellerBalance, err := NewPostingRepository(PostgresConnection).FindBalance("split_seller", "RUB", now.Add(time.Hour))
+	if err != nil {
+		t.Error(err)
+	}
+	usage, err := NewLimitRepository(PostgresConnection).FindUsage("split_payer", "RUB", now)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.NoError(t, createErr)
+	assert.Equal(t, domain.ErrInsufficientFunds, overBalanceErr)
+	assert.Len(t, found.Legs, 2)
+	assert.Equal(t, "90", found.Legs[0].Percent)
+	assert.Equal(t, "platform", found.Legs[1].Role)
+	assert.Nil(t, notFound)
+	assert.Len(t, received, 1)
+	assert.Equal(t, int64(9000), received[0].Amount)
+	assert.Equal(t, int64(9000), sellerBalance)
+	assert.Equal(t, domain.LimitUsage{DailyAmount: 10000, MonthlyAmount: 10000, DailyCount: 1, MonthlyCount: 1}, usage)
+}
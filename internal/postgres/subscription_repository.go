@@ -0,0 +1,603 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	subscriptionPlanTableName    = "subscription_plan"
+	subscriptionTableName        = "subscription"
+	subscriptionInvoiceTableName = "subscription_invoice"
+)
+
+var subscriptionPlanColumns = []string{
+	"uid",
+	"name",
+	"amount",
+	"currency",
+	"billinginterval",
+	"intervalcount",
+	"trialdays",
+	"createdat",
+}
+
+var preparedSubscriptionPlanColumns = strings.Join(subscriptionPlanColumns, ", ")
+
+var subscriptionColumns = []string{
+	"uid",
+	"customeruid",
+	"planuid",
+	"paymentmethod",
+	"status",
+	"billinganchor",
+	"currentperiodstart",
+	"currentperiodend",
+	"trialend",
+	"cancelatperiodend",
+	"pendingproration",
+	"cancelledat",
+	"createdat",
+	"updatedat",
+}
+
+var preparedSubscriptionColumns = strings.Join(subscriptionColumns, ", ")
+
+var subscriptionInvoiceColumns = []string{
+	"uid",
+	"subscriptionuid",
+	"customeruid",
+	"periodstart",
+	"periodend",
+	"items",
+	"amount",
+	"currency",
+	"status",
+	"attempts",
+	"lasterror",
+	"dueat",
+	"nextattemptat",
+	"createdat",
+	"updatedat",
+}
+
+var preparedSubscriptionInvoiceColumns = strings.Join(subscriptionInvoiceColumns, ", ")
+
+// subscriptionInvoiceItemRow is a json representation of domain.SubscriptionInvoiceItem stored in items column
+type subscriptionInvoiceItemRow struct {
+	Description string `json:"description"`
+	Amount      int64  `json:"amount"`
+}
+
+type SubscriptionRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewSubscriptionRepository(pgConn *pgxpool.Pool) *SubscriptionRepository {
+	return &SubscriptionRepository{pgConn: pgConn}
+}
+
+func (a *SubscriptionRepository) CreatePlan(plan *domain.Plan) error {
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		subscriptionPlanTableName,
+		preparedSubscriptionPlanColumns,
+		getSubstitutionVerbsForColumns(subscriptionPlanColumns),
+	)
+	_, err := a.pgConn.Exec(
+		context.Background(),
+		query,
+		plan.GeneratedID,
+		plan.Name,
+		plan.Amount,
+		plan.Currency,
+		plan.Interval,
+		plan.IntervalCount,
+		plan.TrialDays,
+		plan.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *SubscriptionRepository) FindPlanByID(planID string) (plan *domain.Plan, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedSubscriptionPlanColumns,
+		subscriptionPlanTableName,
+	)
+
+	plan, err = scanPlan(a.pgConn.QueryRow(context.Background(), query, planID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (a *SubscriptionRepository) FindPlans() (plans []*domain.Plan, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s ORDER BY createdat;`,
+		preparedSubscriptionPlanColumns,
+		subscriptionPlanTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var plan *domain.Plan
+		plan, err = scanPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return plans, nil
+}
+
+func (a *SubscriptionRepository) CreateSubscription(
+	subscription *domain.Subscription,
+	invoice *domain.SubscriptionInvoice,
+) (err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		subscriptionTableName,
+		preparedSubscriptionColumns,
+		getSubstitutionVerbsForColumns(subscriptionColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, subscriptionArgs(subscription)...)
+	if err != nil {
+		return err
+	}
+
+	if invoice != nil {
+		err = insertSubscriptionInvoice(tx, invoice)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(context.Background())
+}
+
+func (a *SubscriptionRepository) FindSubscriptionByID(
+	subscriptionID string,
+) (subscription *domain.Subscription, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedSubscriptionColumns,
+		subscriptionTableName,
+	)
+
+	subscription, err = scanSubscription(a.pgConn.QueryRow(context.Background(), query, subscriptionID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (a *SubscriptionRepository) FindSubscriptionsByCustomerID(
+	customerID string,
+) (subscriptions []*domain.Subscription, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 ORDER BY createdat;`,
+		preparedSubscriptionColumns,
+		subscriptionTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSubscriptions(rows)
+}
+
+func (a *SubscriptionRepository) UpdateSubscription(
+	subscriptionID string,
+	update func(subscription *domain.Subscription) error,
+) (subscription *domain.Subscription, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1 FOR UPDATE;`,
+		preparedSubscriptionColumns,
+		subscriptionTableName,
+	)
+	subscription, err = scanSubscription(tx.QueryRow(context.Background(), query, subscriptionID))
+	if err == pgx.ErrNoRows {
+		return nil, tx.Rollback(context.Background())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = update(subscription)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(context.Background(), updateSubscriptionQuery(), subscriptionArgs(subscription)...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (a *SubscriptionRepository) ClaimDueSubscriptions(
+	now time.Time,
+	limit int,
+	renew func(subscription *domain.Subscription) (*domain.SubscriptionInvoice, error),
+) (claimed int, err error) {
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE status<>$1 AND currentperiodend<=$2
+		ORDER BY currentperiodend LIMIT $3 FOR UPDATE SKIP LOCKED;`,
+		preparedSubscriptionColumns,
+		subscriptionTableName,
+	)
+	rows, err := tx.Query(context.Background(), query, domain.SubscriptionStatusCancelled, now, limit)
+	if err != nil {
+		return 0, err
+	}
+	subscriptions, err := scanSubscriptions(rows)
+	rows.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, subscription := range subscriptions {
+		var invoice *domain.SubscriptionInvoice
+		invoice, err = renew(subscription)
+		if err != nil {
+			return 0, err
+		}
+		if invoice != nil {
+			err = insertSubscriptionInvoice(tx, invoice)
+			if err != nil {
+				return 0, err
+			}
+		}
+		_, err = tx.Exec(context.Background(), updateSubscriptionQuery(), subscriptionArgs(subscription)...)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	return len(subscriptions), nil
+}
+
+func (a *SubscriptionRepository) ClaimInvoices(
+	now time.Time,
+	leaseUntil time.Time,
+	limit int,
+) (invoices []*domain.SubscriptionInvoice, err error) {
+	query := fmt.Sprintf(
+		`UPDATE %[1]s SET nextattemptat=$2 WHERE uid IN (
+			SELECT uid FROM %[1]s WHERE status=$1 AND nextattemptat<=$3
+			ORDER BY nextattemptat LIMIT $4 FOR UPDATE SKIP LOCKED
+		) RETURNING %[2]s;`,
+		subscriptionInvoiceTableName,
+		preparedSubscriptionInvoiceColumns,
+	)
+
+	rows, err := a.pgConn.Query(
+		context.Background(),
+		query,
+		domain.SubscriptionInvoiceStatusOpen,
+		leaseUntil,
+		now,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSubscriptionInvoices(rows)
+}
+
+func (a *SubscriptionRepository) UpdateInvoice(
+	invoice *domain.SubscriptionInvoice,
+	subscription *domain.Subscription,
+) (err error) {
+	args, err := subscriptionInvoiceArgs(invoice)
+	if err != nil {
+		return err
+	}
+
+	tx, err := a.pgConn.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(context.Background())
+		}
+	}()
+
+	query := fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		subscriptionInvoiceTableName,
+		preparedSubscriptionInvoiceColumns,
+		getSubstitutionVerbsForColumns(subscriptionInvoiceColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, args...)
+	if err != nil {
+		return err
+	}
+
+	if subscription != nil {
+		// subscription period could be renewed meanwhile, so only status is saved
+		query = fmt.Sprintf(
+			`UPDATE %s SET (status, cancelledat, updatedat) = ROW ($2, $3, $4) WHERE uid=$1 AND status<>$5;`,
+			subscriptionTableName,
+		)
+		_, err = tx.Exec(
+			context.Background(),
+			query,
+			subscription.GeneratedID,
+			subscription.Status,
+			nullableTime(subscription.CancelledAt),
+			subscription.UpdatedAt,
+			domain.SubscriptionStatusCancelled,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit(context.Background())
+}
+
+func (a *SubscriptionRepository) FindInvoices(
+	subscriptionID string,
+) (invoices []*domain.SubscriptionInvoice, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE subscriptionuid=$1 ORDER BY periodstart DESC;`,
+		preparedSubscriptionInvoiceColumns,
+		subscriptionInvoiceTableName,
+	)
+
+	rows, err := a.pgConn.Query(context.Background(), query, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanSubscriptionInvoices(rows)
+}
+
+// insertSubscriptionInvoice skips invoice which already exists, so period is never billed twice
+func insertSubscriptionInvoice(tx pgx.Tx, invoice *domain.SubscriptionInvoice) error {
+	args, err := subscriptionInvoiceArgs(invoice)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (uid) DO NOTHING;`,
+		subscriptionInvoiceTableName,
+		preparedSubscriptionInvoiceColumns,
+		getSubstitutionVerbsForColumns(subscriptionInvoiceColumns),
+	)
+	_, err = tx.Exec(context.Background(), query, args...)
+	return err
+}
+
+func updateSubscriptionQuery() string {
+	return fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		subscriptionTableName,
+		preparedSubscriptionColumns,
+		getSubstitutionVerbsForColumns(subscriptionColumns),
+	)
+}
+
+func subscriptionArgs(subscription *domain.Subscription) []interface{} {
+	return []interface{}{
+		subscription.GeneratedID,
+		subscription.CustomerID,
+		subscription.PlanID,
+		subscription.PaymentMethod,
+		subscription.Status,
+		subscription.BillingAnchor,
+		subscription.CurrentPeriodStart,
+		subscription.CurrentPeriodEnd,
+		nullableTime(subscription.TrialEnd),
+		subscription.CancelAtPeriodEnd,
+		subscription.PendingProration,
+		nullableTime(subscription.CancelledAt),
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	}
+}
+
+func subscriptionInvoiceArgs(invoice *domain.SubscriptionInvoice) ([]interface{}, error) {
+	rows := make([]subscriptionInvoiceItemRow, 0, len(invoice.Items))
+	for _, item := range invoice.Items {
+		rows = append(rows, subscriptionInvoiceItemRow{Description: item.Description, Amount: item.Amount})
+	}
+	items, err := json.Marshal(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return []interface{}{
+		invoice.GeneratedID,
+		invoice.SubscriptionID,
+		invoice.CustomerID,
+		invoice.PeriodStart,
+		invoice.PeriodEnd,
+		string(items),
+		invoice.Amount,
+		invoice.Currency,
+		invoice.Status,
+		invoice.Attempts,
+		invoice.LastError,
+		invoice.DueAt,
+		invoice.NextAttemptAt,
+		invoice.CreatedAt,
+		invoice.UpdatedAt,
+	}, nil
+}
+
+func scanPlan(row pgx.Row) (*domain.Plan, error) {
+	plan := &domain.Plan{}
+	err := row.Scan(
+		&plan.GeneratedID,
+		&plan.Name,
+		&plan.Amount,
+		&plan.Currency,
+		&plan.Interval,
+		&plan.IntervalCount,
+		&plan.TrialDays,
+		&plan.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func scanSubscription(row pgx.Row) (*domain.Subscription, error) {
+	subscription := &domain.Subscription{}
+	var trialEnd, cancelledAt *time.Time
+	err := row.Scan(
+		&subscription.GeneratedID,
+		&subscription.CustomerID,
+		&subscription.PlanID,
+		&subscription.PaymentMethod,
+		&subscription.Status,
+		&subscription.BillingAnchor,
+		&subscription.CurrentPeriodStart,
+		&subscription.CurrentPeriodEnd,
+		&trialEnd,
+		&subscription.CancelAtPeriodEnd,
+		&subscription.PendingProration,
+		&cancelledAt,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if trialEnd != nil {
+		subscription.TrialEnd = *trialEnd
+	}
+	if cancelledAt != nil {
+		subscription.CancelledAt = *cancelledAt
+	}
+	return subscription, nil
+}
+
+func scanSubscriptions(rows pgx.Rows) ([]*domain.Subscription, error) {
+	var subscriptions []*domain.Subscription
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return subscriptions, nil
+}
+
+func scanSubscriptionInvoices(rows pgx.Rows) ([]*domain.SubscriptionInvoice, error) {
+	var invoices []*domain.SubscriptionInvoice
+	for rows.Next() {
+		invoice := &domain.SubscriptionInvoice{}
+		var items []byte
+		err := rows.Scan(
+			&invoice.GeneratedID,
+			&invoice.SubscriptionID,
+			&invoice.CustomerID,
+			&invoice.PeriodStart,
+			&invoice.PeriodEnd,
+			&items,
+			&invoice.Amount,
+			&invoice.Currency,
+			&invoice.Status,
+			&invoice.Attempts,
+			&invoice.LastError,
+			&invoice.DueAt,
+			&invoice.NextAttemptAt,
+			&invoice.CreatedAt,
+			&invoice.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var itemRows []subscriptionInvoiceItemRow
+		err = json.Unmarshal(items, &itemRows)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range itemRows {
+			invoice.Items = append(
+				invoice.Items,
+				domain.SubscriptionInvoiceItem{Description: item.Description, Amount: item.Amount},
+			)
+		}
+		invoices = append(invoices, invoice)
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return invoices, nil
+}
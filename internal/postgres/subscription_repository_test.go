@@ -0,0 +1,130 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestSubscription_RenewAndCharge(t *testing.T) {
+	// clean
+	for _, table := range []string{"subscription_plan", "subscription", "subscription_invoice"} {
+		_, err := PostgresConnection.Exec(context.Background(), `DELETE FROM `+table+`;`)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	repository := NewSubscriptionRepository(PostgresConnection)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// arrange
+	err := repository.CreatePlan(&domain.Plan{
+		GeneratedID:   "plan_basic",
+		Name:          "Basic",
+		Amount:        29900,
+		Currency:      "RUB",
+		Interval:      domain.PlanIntervalMonth,
+		IntervalCount: 1,
+		TrialDays:     14,
+		CreatedAt:     now,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	err = repository.CreateSubscription(&domain.Subscription{
+		GeneratedID:        "subscription_1",
+		CustomerID:         "subscription_customer",
+		PlanID:             "plan_basic",
+		PaymentMethod:      "card_token",
+		Status:             domain.SubscriptionStatusTrialing,
+		BillingAnchor:      now.Add(-time.Minute),
+		CurrentPeriodStart: now.Add(-14 * 24 * time.Hour),
+		CurrentPeriodEnd:   now.Add(-time.Minute),
+		TrialEnd:           now.Add(-time.Minute),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}, nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// act
+	renew := func(subscription *domain.Subscription) (*domain.SubscriptionInvoice, error) {
+		periodStart := subscription.CurrentPeriodEnd
+		subscription.Status = domain.SubscriptionStatusActive
+		subscription.CurrentPeriodStart = periodStart
+		subscription.CurrentPeriodEnd = now.Add(30 * 24 * time.Hour)
+		return &domain.SubscriptionInvoice{
+			GeneratedID:    subscription.GeneratedID + "_invoice",
+			SubscriptionID: subscription.GeneratedID,
+			CustomerID:     subscription.CustomerID,
+			PeriodStart:    periodStart,
+			PeriodEnd:      subscription.CurrentPeriodEnd,
+			Items:          []domain.SubscriptionInvoiceItem{{Description: "Basic", Amount: 29900}},
+			Amount:         29900,
+			Currency:       "RUB",
+			Status:         domain.SubscriptionInvoiceStatusOpen,
+			DueAt:          periodStart,
+			NextAttemptAt:  periodStart,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}, nil
+	}
+	claimed, err := repository.ClaimDueSubscriptions(now, 10, renew)
+	if err != nil {
+		t.Error(err)
+	}
+	claimedAgain, err := repository.ClaimDueSubscriptions(now, 10, renew)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert
+	assert.Equal(t, 1, claimed)
+	assert.Equal(t, 0, claimedAgain)
+
+	invoices, err := repository.ClaimInvoices(now, now.Add(time.Minute), 10)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Len(t, invoices, 1)
+	assert.Equal(t, []domain.SubscriptionInvoiceItem{{Description: "Basic", Amount: 29900}}, invoices[0].Items)
+
+	// status of cancelled subscription is not overwritten by charge result
+	_, err = repository.UpdateSubscription("subscription_1", func(subscription *domain.Subscription) error {
+		subscription.Status = domain.SubscriptionStatusCancelled
+		subscription.CancelledAt = now
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	invoices[0].Status = domain.SubscriptionInvoiceStatusPaid
+	err = repository.UpdateInvoice(invoices[0], &domain.Subscription{
+		GeneratedID: "subscription_1",
+		Status:      domain.SubscriptionStatusActive,
+		UpdatedAt:   now,
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	subscription, err := repository.FindSubscriptionByID("subscription_1")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, domain.SubscriptionStatusCancelled, subscription.Status)
+	assert.True(t, now.Equal(subscription.CancelledAt))
+
+	invoices, err = repository.FindInvoices("subscription_1")
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Len(t, invoices, 1)
+	assert.Equal(t, domain.SubscriptionInvoiceStatusPaid, invoices[0].Status)
+}
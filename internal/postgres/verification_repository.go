@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const verificationTableName = "verification"
+
+var verificationColumns = []string{
+	"uid",
+	"customeruid",
+	"status",
+	"level",
+	"checks",
+	"reviewer",
+	"comment",
+	"createdat",
+	"updatedat",
+}
+
+var preparedVerificationColumns = strings.Join(verificationColumns, ", ")
+
+// verificationCheckRow is a json representation of domain.VerificationCheckResult stored in checks column
+type verificationCheckRow struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason"`
+}
+
+type VerificationRepository struct {
+	pgConn *pgxpool.Pool
+}
+
+func NewVerificationRepository(pgConn *pgxpool.Pool) *VerificationRepository {
+	return &VerificationRepository{pgConn: pgConn}
+}
+
+func (a *VerificationRepository) Create(verification *domain.Verification) error {
+	checks, err := marshalVerificationChecks(verification.Checks)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`INSERT INTO %s (%s) VALUES (%s);`,
+		verificationTableName,
+		preparedVerificationColumns,
+		getSubstitutionVerbsForColumns(verificationColumns),
+	)
+	_, err = a.pgConn.Exec(
+		context.Background(),
+		query,
+		verification.GeneratedID,
+		verification.CustomerID,
+		verification.Status,
+		verification.Level,
+		checks,
+		verification.Reviewer,
+		verification.Comment,
+		verification.CreatedAt,
+		verification.UpdatedAt,
+	)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *VerificationRepository) FindByID(verificationID string) (verification *domain.Verification, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE uid=$1;`,
+		preparedVerificationColumns,
+		verificationTableName,
+	)
+	return a.findOne(query, verificationID)
+}
+
+func (a *VerificationRepository) FindLastByCustomerID(
+	customerID string,
+) (verification *domain.Verification, err error) {
+	query := fmt.Sprintf(
+		`SELECT %s FROM %s WHERE customeruid=$1 ORDER BY createdat DESC LIMIT 1;`,
+		preparedVerificationColumns,
+		verificationTableName,
+	)
+	return a.findOne(query, customerID)
+}
+
+func (a *VerificationRepository) Update(verification *domain.Verification) error {
+	checks, err := marshalVerificationChecks(verification.Checks)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(
+		`UPDATE %s SET (%s) = ROW (%s) WHERE uid=$1;`,
+		verificationTableName,
+		preparedVerificationColumns,
+		getSubstitutionVerbsForColumns(verificationColumns),
+	)
+	_, err = a.pgConn.Exec(
+		context.Background(),
+		query,
+		verification.GeneratedID,
+		verification.CustomerID,
+		verification.Status,
+		verification.Level,
+		checks,
+		verification.Reviewer,
+		verification.Comment,
+		verification.CreatedAt,
+		verification.UpdatedAt,
+	)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *VerificationRepository) findOne(query string, args ...interface{}) (*domain.Verification, error) {
+	verification := &domain.Verification{}
+	var checks []byte
+	queryRow := a.pgConn.QueryRow(
+		context.Background(),
+		query,
+		args...,
+	)
+	err := queryRow.Scan(
+		&verification.GeneratedID,
+		&verification.CustomerID,
+		&verification.Status,
+		&verification.Level,
+		&checks,
+		&verification.Reviewer,
+		&verification.Comment,
+		&verification.CreatedAt,
+		&verification.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	verification.Checks, err = unmarshalVerificationChecks(checks)
+	if err != nil {
+		return nil, err
+	}
+	return verification, nil
+}
+
+func marshalVerificationChecks(checks []domain.VerificationCheckResult) (string, error) {
+	rows := make([]verificationCheckRow, 0, len(checks))
+	for _, check := range checks {
+		rows = append(rows, verificationCheckRow{Name: check.Name, Passed: check.Passed, Reason: check.Reason})
+	}
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func unmarshalVerificationChecks(data []byte) ([]domain.VerificationCheckResult, error) {
+	var rows []verificationCheckRow
+	err := json.Unmarshal(data, &rows)
+	if err != nil {
+		return nil, err
+	}
+	checks := make([]domain.VerificationCheckResult, 0, len(rows))
+	for _, row := range rows {
+		checks = append(checks, domain.VerificationCheckResult{Name: row.Name, Passed: row.Passed, Reason: row.Reason})
+	}
+	return checks, nil
+}
@@ -0,0 +1,76 @@
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestVerification_Create_Find_Update(t *testing.T) {
+	t.Parallel()
+
+	// clean
+	query := `DELETE FROM verification WHERE customeruid = $1;`
+	_, err := PostgresConnection.Exec(context.Background(), query, "verification_customer")
+	if err != nil {
+		t.Error(err)
+	}
+	repository := NewVerificationRepository(PostgresConnection)
+
+	// arrange Create
+	createdAt := time.Now().Truncate(time.Second)
+	verification := &domain.Verification{
+		GeneratedID: "verification123",
+		CustomerID:  "verification_customer",
+		Status:      domain.VerificationStatusSubmitted,
+		Level:       domain.VerificationLevelNone,
+		Checks: []domain.VerificationCheckResult{
+			{Name: "passport_number", Passed: true},
+			{Name: "customer_age", Passed: false, Reason: "customer should be at least 18 years old"},
+		},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+
+	// act Create
+	err = repository.Create(verification)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert Create via FindLastByCustomerID
+	dbVerification, err := repository.FindLastByCustomerID(verification.CustomerID)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, verification.GeneratedID, dbVerification.GeneratedID)
+	assert.Equal(t, verification.Status, dbVerification.Status)
+	assert.Equal(t, verification.Level, dbVerification.Level)
+	assert.Equal(t, verification.Checks, dbVerification.Checks)
+	assert.True(t, verification.CreatedAt.Equal(dbVerification.CreatedAt))
+
+	// act Update
+	verification.Status = domain.VerificationStatusApproved
+	verification.Level = domain.VerificationLevelFull
+	verification.Reviewer = "alfred"
+	verification.Comment = "ok"
+	err = repository.Update(verification)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// assert Update via FindByID
+	updatedVerification, err := repository.FindByID(verification.GeneratedID)
+	if err != nil {
+		t.Error(err)
+	}
+	assert.Equal(t, domain.VerificationStatusApproved, updatedVerification.Status)
+	assert.Equal(t, domain.VerificationLevelFull, updatedVerification.Level)
+	assert.Equal(t, "alfred", updatedVerification.Reviewer)
+	assert.Equal(t, "ok", updatedVerification.Comment)
+}
@@ -0,0 +1,215 @@
+package qrcode
+
+import "fmt"
+
+// Level is an error correction level, code of higher level is larger but is read when more of it is damaged
+type Level int
+
+const (
+	// LevelL restores about 7% of codewords
+	LevelL Level = iota
+	// LevelM restores about 15% of codewords
+	LevelM
+	// LevelQ restores about 25% of codewords
+	LevelQ
+	// LevelH restores about 30% of codewords
+	LevelH
+)
+
+const (
+	minVersion = 1
+	maxVersion = 40
+	// byteMode is a mode indicator of 8-bit byte segment
+	byteMode = 0x4
+)
+
+// eccCodewordsPerBlock and errorCorrectionBlocks are indexed by level and version, version 0 is not used
+var eccCodewordsPerBlock = [4][41]int{
+	{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28,
+		28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26,
+		26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30,
+		28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28,
+		30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var errorCorrectionBlocks = [4][41]int{
+	{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8,
+		8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16,
+		17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20,
+		23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25,
+		25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// Code is a QR code symbol, modules are addressed by column x and row y from the top left corner
+type Code struct {
+	Version int
+	Level   Level
+	Mask    int
+	Size    int
+	modules []bool
+}
+
+// Dark tells whether module is dark, modules outside of symbol are light
+func (c *Code) Dark(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+		return false
+	}
+	return c.modules[y*c.Size+x]
+}
+
+// Encode makes QR code of the smallest version which holds data as a single byte mode segment.
+// Mask is chosen by the lowest penalty score.
+func Encode(data []byte, level Level) (*Code, error) {
+	if level < LevelL || level > LevelH {
+		return nil, fmt.Errorf("unknown error correction level %d", level)
+	}
+	for version := minVersion; version <= maxVersion; version++ {
+		if dataBits(data, version) <= 8*dataCodewords(version, level) {
+			return encode(data, version, level, -1), nil
+		}
+	}
+	return nil, fmt.Errorf("%d bytes do not fit into QR code", len(data))
+}
+
+// encode draws code of version with mask, mask -1 means the mask of the lowest penalty
+func encode(data []byte, version int, level Level, mask int) *Code {
+	codewords := interleave(version, level, dataSegment(data, version, level))
+
+	m := newMatrix(version)
+	m.drawFunctionPatterns()
+	m.drawCodewords(codewords)
+	if mask < 0 {
+		mask = 0
+		minPenalty := -1
+		for candidate := 0; candidate < 8; candidate++ {
+			m.applyMask(candidate)
+			m.drawFormatBits(level, candidate)
+			penalty := m.penalty()
+			if minPenalty < 0 || penalty < minPenalty {
+				mask = candidate
+				minPenalty = penalty
+			}
+			// mask is XOR, applying it again restores modules
+			m.applyMask(candidate)
+		}
+	}
+	m.applyMask(mask)
+	m.drawFormatBits(level, mask)
+
+	return &Code{Version: version, Level: level, Mask: mask, Size: m.size, modules: m.modules}
+}
+
+func characterCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+func dataBits(data []byte, version int) int {
+	return 4 + characterCountBits(version) + 8*len(data)
+}
+
+// rawDataModules is a number of modules left for codewords after function patterns and format information
+func rawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		alignments := version/7 + 2
+		result -= (25*alignments-10)*alignments - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func dataCodewords(version int, level Level) int {
+	return rawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*errorCorrectionBlocks[level][version]
+}
+
+// dataSegment is byte mode segment with terminator and padding up to data capacity of version
+func dataSegment(data []byte, version int, level Level) []byte {
+	capacity := dataCodewords(version, level)
+	buffer := &bitBuffer{}
+	buffer.append(byteMode, 4)
+	buffer.append(len(data), characterCountBits(version))
+	for _, b := range data {
+		buffer.append(int(b), 8)
+	}
+
+	terminator := 8*capacity - buffer.length
+	if terminator > 4 {
+		terminator = 4
+	}
+	buffer.append(0, terminator)
+	if buffer.length%8 != 0 {
+		buffer.append(0, 8-buffer.length%8)
+	}
+	for padding := 0xEC; len(buffer.bytes) < capacity; padding ^= 0xEC ^ 0x11 {
+		buffer.append(padding, 8)
+	}
+	return buffer.bytes
+}
+
+// interleave splits data into blocks, adds error correction codewords to every block
+// and takes codewords of blocks in turn. Blocks of the first group are one data codeword shorter.
+func interleave(version int, level Level, data []byte) []byte {
+	blocks := errorCorrectionBlocks[level][version]
+	eccLength := eccCodewordsPerBlock[level][version]
+	rawCodewords := rawDataModules(version) / 8
+	shortBlocks := blocks - rawCodewords%blocks
+	shortDataLength := rawCodewords/blocks - eccLength
+	generator := generatorPolynomial(eccLength)
+
+	dataBlocks := make([][]byte, blocks)
+	eccBlocks := make([][]byte, blocks)
+	offset := 0
+	for i := range dataBlocks {
+		length := shortDataLength
+		if i >= shortBlocks {
+			length++
+		}
+		dataBlocks[i] = data[offset : offset+length]
+		eccBlocks[i] = errorCorrection(dataBlocks[i], generator)
+		offset += length
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := 0; i <= shortDataLength; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < eccLength; i++ {
+		for _, block := range eccBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+type bitBuffer struct {
+	bytes  []byte
+	length int
+}
+
+// append adds count lowest bits of value starting from the most significant one
+func (b *bitBuffer) append(value int, count int) {
+	for i := count - 1; i >= 0; i-- {
+		if b.length%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		if value>>uint(i)&1 != 0 {
+			b.bytes[len(b.bytes)-1] |= 0x80 >> uint(b.length%8)
+		}
+		b.length++
+	}
+}
@@ -0,0 +1,118 @@
+package qrcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	code, err := Encode([]byte("HELLO"), LevelM)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, code.Version)
+	assert.Equal(t, 4, code.Mask)
+	assert.Equal(t, []string{
+		"#######.##.#..#######",
+		"#.....#..##.#.#.....#",
+		"#.###.#..####.#.###.#",
+		"#.###.#.#..#..#.###.#",
+		"#.###.#.#...#.#.###.#",
+		"#.....#.#.##..#.....#",
+		"#######.#.#.#.#######",
+		"........#####........",
+		"#...#.######.#####..#",
+		"...###..#.###..#.####",
+		"#.##..#.#.##..###..#.",
+		"###..#...#...##.#....",
+		"..#.###..#..###...##.",
+		"........###.###..#.##",
+		"#######.##..##...#.#.",
+		"#.....#....##..#...#.",
+		"#.###.#.#..#..###.#.#",
+		"#.###.#....##....#.##",
+		"#.###.#..###..####...",
+		"#.....#..#...##......",
+		"#######.#...#####.#.#",
+	}, rows(code))
+}
+
+func TestEncode_Version(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		length  int
+		level   Level
+		version int
+	}{
+		{14, LevelM, 1},
+		{15, LevelM, 2},
+		{26, LevelM, 2},
+		{27, LevelM, 3},
+		{17, LevelL, 1},
+		{7, LevelH, 1},
+		{2331, LevelM, 40},
+		{2953, LevelL, 40},
+	}
+
+	for _, test := range testCases {
+		code, err := Encode(make([]byte, test.length), test.level)
+
+		assert.Nil(t, err)
+		assert.Equal(t, test.version, code.Version, "%d bytes", test.length)
+		assert.Equal(t, 4*test.version+17, code.Size)
+	}
+}
+
+func TestEncode_Error(t *testing.T) {
+	t.Parallel()
+
+	_, err := Encode(make([]byte, 2332), LevelM)
+	assert.EqualError(t, err, "2332 bytes do not fit into QR code")
+
+	_, err = Encode([]byte("HELLO"), Level(4))
+	assert.EqualError(t, err, "unknown error correction level 4")
+}
+
+func TestFormatBits(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0x77C4, formatBits(LevelL, 0))
+	assert.Equal(t, 0x5412, formatBits(LevelM, 0))
+	assert.Equal(t, 0x40CE, formatBits(LevelM, 5))
+	assert.Equal(t, 0x1689, formatBits(LevelH, 0))
+}
+
+func TestVersionBits(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0x07C94, versionBits(7))
+	assert.Equal(t, 0x28C69, versionBits(40))
+}
+
+func TestAlignmentPatternPositions(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, alignmentPatternPositions(1))
+	assert.Equal(t, []int{6, 18}, alignmentPatternPositions(2))
+	assert.Equal(t, []int{6, 22, 38}, alignmentPatternPositions(7))
+	assert.Equal(t, []int{6, 34, 60, 86, 112, 138}, alignmentPatternPositions(32))
+	assert.Equal(t, []int{6, 30, 58, 86, 114, 142, 170}, alignmentPatternPositions(40))
+}
+
+func rows(code *Code) []string {
+	result := make([]string, code.Size)
+	for y := range result {
+		row := make([]byte, code.Size)
+		for x := range row {
+			row[x] = '.'
+			if code.Dark(x, y) {
+				row[x] = '#'
+			}
+		}
+		result[y] = string(row)
+	}
+	return result
+}
@@ -0,0 +1,300 @@
+package qrcode
+
+const (
+	// formatPolynomial and versionPolynomial are BCH code generators of format and version information
+	formatPolynomial  = 0x537
+	versionPolynomial = 0x1F25
+	// formatMask keeps format information from being all light
+	formatMask = 0x5412
+)
+
+// formatLevelBits are error correction level indicators of format information
+var formatLevelBits = [4]int{LevelL: 1, LevelM: 0, LevelQ: 3, LevelH: 2}
+
+// matrix is a symbol being drawn, function marks modules of function patterns which are not masked
+type matrix struct {
+	version  int
+	size     int
+	modules  []bool
+	function []bool
+}
+
+func newMatrix(version int) *matrix {
+	size := 4*version + 17
+	return &matrix{
+		version:  version,
+		size:     size,
+		modules:  make([]bool, size*size),
+		function: make([]bool, size*size),
+	}
+}
+
+func (m *matrix) dark(x, y int) bool {
+	return m.modules[y*m.size+x]
+}
+
+func (m *matrix) setFunction(x, y int, dark bool) {
+	m.modules[y*m.size+x] = dark
+	m.function[y*m.size+x] = true
+}
+
+func (m *matrix) drawFunctionPatterns() {
+	for i := 0; i < m.size; i++ {
+		m.setFunction(6, i, i%2 == 0)
+		m.setFunction(i, 6, i%2 == 0)
+	}
+
+	m.drawFinderPattern(3, 3)
+	m.drawFinderPattern(m.size-4, 3)
+	m.drawFinderPattern(3, m.size-4)
+
+	positions := alignmentPatternPositions(m.version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// alignment patterns do not overlap finder patterns
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			m.drawAlignmentPattern(x, y)
+		}
+	}
+
+	// format bits are reserved here and drawn after masking, version bits are not masked
+	m.drawFormatBits(LevelL, 0)
+	m.drawVersionBits()
+}
+
+// drawFinderPattern draws finder pattern with its separator around center x, y
+func (m *matrix) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			if x+dx < 0 || x+dx >= m.size || y+dy < 0 || y+dy >= m.size {
+				continue
+			}
+			distance := maxInt(absInt(dx), absInt(dy))
+			m.setFunction(x+dx, y+dy, distance != 2 && distance != 4)
+		}
+	}
+}
+
+func (m *matrix) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			m.setFunction(x+dx, y+dy, maxInt(absInt(dx), absInt(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of format information and the dark module next to them
+func (m *matrix) drawFormatBits(level Level, mask int) {
+	bits := formatBits(level, mask)
+	bit := func(i int) bool {
+		return bits>>uint(i)&1 != 0
+	}
+
+	for i := 0; i <= 5; i++ {
+		m.setFunction(8, i, bit(i))
+	}
+	m.setFunction(8, 7, bit(6))
+	m.setFunction(8, 8, bit(7))
+	m.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		m.setFunction(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.setFunction(8, m.size-15+i, bit(i))
+	}
+	m.setFunction(8, m.size-8, true)
+}
+
+// drawVersionBits draws both copies of version information, versions below 7 have none
+func (m *matrix) drawVersionBits() {
+	if m.version < 7 {
+		return
+	}
+	bits := versionBits(m.version)
+	for i := 0; i < 18; i++ {
+		dark := bits>>uint(i)&1 != 0
+		a := m.size - 11 + i%3
+		b := i / 3
+		m.setFunction(a, b, dark)
+		m.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords places codewords bit by bit in two module wide columns from the bottom right corner
+// going up and down in turns, vertical timing pattern column is skipped. Remainder bits are light.
+func (m *matrix) drawCodewords(codewords []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vertical := 0; vertical < m.size; vertical++ {
+			y := vertical
+			if upward {
+				y = m.size - 1 - vertical
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if m.function[y*m.size+x] || i >= len(codewords)*8 {
+					continue
+				}
+				m.modules[y*m.size+x] = codewords[i/8]>>uint(7-i%8)&1 != 0
+				i++
+			}
+		}
+	}
+}
+
+// applyMask inverts modules of data which match mask condition
+func (m *matrix) applyMask(mask int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if !m.function[y*m.size+x] && maskCondition(mask, x, y) {
+				m.modules[y*m.size+x] = !m.modules[y*m.size+x]
+			}
+		}
+	}
+}
+
+func maskCondition(mask int, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// penalty scores masked symbol by rules of ISO/IEC 18004: runs of the same color, 2x2 blocks,
+// finder-like patterns and dark to light ratio. Mask of the lowest score is used.
+func (m *matrix) penalty() int {
+	penalty := 0
+	for i := 0; i < m.size; i++ {
+		penalty += m.linePenalty(func(j int) bool { return m.dark(j, i) })
+		penalty += m.linePenalty(func(j int) bool { return m.dark(i, j) })
+	}
+
+	darkModules := 0
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.dark(x, y) {
+				darkModules++
+			}
+			if x+1 < m.size && y+1 < m.size &&
+				m.dark(x, y) == m.dark(x+1, y) && m.dark(x, y) == m.dark(x, y+1) && m.dark(x, y) == m.dark(x+1, y+1) {
+				penalty += 3
+			}
+		}
+	}
+
+	total := m.size * m.size
+	penalty += absInt(darkModules*20-total*10) / total * 10
+	return penalty
+}
+
+// finderLikePattern is 1:1:3:1:1 dark and light modules followed by four light modules
+var finderLikePattern = []bool{true, false, true, true, true, false, true, false, false, false, false}
+
+func (m *matrix) linePenalty(dark func(i int) bool) int {
+	penalty := 0
+	run := 1
+	for i := 1; i <= m.size; i++ {
+		if i < m.size && dark(i) == dark(i-1) {
+			run++
+			continue
+		}
+		if run >= 5 {
+			penalty += run - 2
+		}
+		run = 1
+	}
+
+	for i := 0; i+len(finderLikePattern) <= m.size; i++ {
+		forward, backward := true, true
+		for j, patternDark := range finderLikePattern {
+			if dark(i+j) != patternDark {
+				forward = false
+			}
+			if dark(i+len(finderLikePattern)-1-j) != patternDark {
+				backward = false
+			}
+		}
+		if forward {
+			penalty += 40
+		}
+		if backward {
+			penalty += 40
+		}
+	}
+	return penalty
+}
+
+// alignmentPatternPositions are coordinates of alignment pattern centers on both axes, evenly spaced
+// from the bottom right one with the first one at the timing pattern
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	count := version/7 + 2
+	step := 26
+	if version != 32 {
+		step = (version*8 + count*3 + 5) / (count*4 - 4) * 2
+	}
+	positions := make([]int, count)
+	positions[0] = 6
+	for i, position := count-1, 4*version+10; i >= 1; i, position = i-1, position-step {
+		positions[i] = position
+	}
+	return positions
+}
+
+func formatBits(level Level, mask int) int {
+	data := formatLevelBits[level]<<3 | mask
+	remainder := data
+	for i := 0; i < 10; i++ {
+		remainder = remainder<<1 ^ remainder>>9*formatPolynomial
+	}
+	return (data<<10 | remainder) ^ formatMask
+}
+
+func versionBits(version int) int {
+	remainder := version
+	for i := 0; i < 12; i++ {
+		remainder = remainder<<1 ^ remainder>>11*versionPolynomial
+	}
+	return version<<12 | remainder
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,62 @@
+package qrcode
+
+// QR codes use Reed-Solomon codes over GF(256) with primitive polynomial x^8+x^4+x^3+x^2+1
+const primitivePolynomial = 0x11D
+
+var expTable, logTable = func() ([512]byte, [256]byte) {
+	var exp [512]byte
+	var log [256]byte
+	x := 1
+	for i := 0; i < 255; i++ {
+		exp[i] = byte(x)
+		log[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= primitivePolynomial
+		}
+	}
+	// doubled table saves modulo in multiplication
+	for i := 255; i < 512; i++ {
+		exp[i] = exp[i-255]
+	}
+	return exp, log
+}()
+
+func multiply(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+int(logTable[b])]
+}
+
+// generatorPolynomial is (x-α^0)(x-α^1)...(x-α^(degree-1)) with coefficients from the highest power,
+// the leading coefficient 1 is omitted
+func generatorPolynomial(degree int) []byte {
+	generator := make([]byte, degree)
+	generator[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			generator[j] = multiply(generator[j], root)
+			if j+1 < degree {
+				generator[j] ^= generator[j+1]
+			}
+		}
+		root = multiply(root, 2)
+	}
+	return generator
+}
+
+// errorCorrection is a remainder of division of data shifted by len(generator) by generator polynomial
+func errorCorrection(data []byte, generator []byte) []byte {
+	remainder := make([]byte, len(generator))
+	for _, b := range data {
+		factor := b ^ remainder[0]
+		copy(remainder, remainder[1:])
+		remainder[len(remainder)-1] = 0
+		for i, coefficient := range generator {
+			remainder[i] ^= multiply(coefficient, factor)
+		}
+	}
+	return remainder
+}
@@ -0,0 +1,76 @@
+package qrcode
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// QuietZone is a light border around symbol in modules, readers need it to find the symbol
+const QuietZone = 4
+
+// WritePNG writes black and white image of code with quiet zone, every module is moduleSize pixels wide
+func (c *Code) WritePNG(w io.Writer, moduleSize int) error {
+	if moduleSize < 1 {
+		return fmt.Errorf("module size should be positive")
+	}
+	side := (c.Size + 2*QuietZone) * moduleSize
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			if c.Dark(x/moduleSize-QuietZone, y/moduleSize-QuietZone) {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// WriteSVG writes code with quiet zone as a single path of dark modules, every module is moduleSize units wide.
+// Dark modules in a row are joined into one rectangle to keep the document small.
+func (c *Code) WriteSVG(w io.Writer, moduleSize int) error {
+	if moduleSize < 1 {
+		return fmt.Errorf("module size should be positive")
+	}
+	side := (c.Size + 2*QuietZone) * moduleSize
+	var path strings.Builder
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Dark(x, y) {
+				continue
+			}
+			start := x
+			for x+1 < c.Size && c.Dark(x+1, y) {
+				x++
+			}
+			if path.Len() > 0 {
+				path.WriteByte(' ')
+			}
+			fmt.Fprintf(
+				&path,
+				"M%d %dh%dv%dh-%dz",
+				(start+QuietZone)*moduleSize,
+				(y+QuietZone)*moduleSize,
+				(x-start+1)*moduleSize,
+				moduleSize,
+				(x-start+1)*moduleSize,
+			)
+		}
+	}
+
+	_, err := fmt.Fprintf(
+		w,
+		`<?xml version="1.0" encoding="UTF-8"?>
+<svg xmlns="http://www.w3.org/2000/svg" version="1.1" viewBox="0 0 %d %d" width="%d" height="%d" `+
+			`shape-rendering="crispEdges">
+<rect width="100%%" height="100%%" fill="#FFFFFF"/>
+<path d="%s" fill="#000000"/>
+</svg>
+`,
+		side, side, side, side, path.String(),
+	)
+	return err
+}
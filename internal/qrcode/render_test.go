@@ -0,0 +1,65 @@
+package qrcode
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritePNG(t *testing.T) {
+	t.Parallel()
+
+	code, err := Encode([]byte("HELLO"), LevelM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	err = code.WritePNG(&buffer, 3)
+
+	assert.Nil(t, err)
+	img, err := png.Decode(&buffer)
+	assert.Nil(t, err)
+	assert.Equal(t, 87, img.Bounds().Dx())
+	assert.Equal(t, 87, img.Bounds().Dy())
+	for y := 0; y < 87; y++ {
+		for x := 0; x < 87; x++ {
+			r, _, _, _ := img.At(x, y).RGBA()
+			assert.Equal(t, code.Dark(x/3-QuietZone, y/3-QuietZone), r == 0, "pixel %d,%d", x, y)
+		}
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	t.Parallel()
+
+	code, err := Encode([]byte("HELLO"), LevelM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	err = code.WriteSVG(&buffer, 10)
+
+	assert.Nil(t, err)
+	document := buffer.String()
+	assert.True(t, strings.HasPrefix(document, `<?xml version="1.0" encoding="UTF-8"?>`))
+	assert.Contains(t, document, `viewBox="0 0 290 290" width="290" height="290"`)
+	// the top row of symbol starts with finder pattern of seven dark modules
+	assert.Contains(t, document, `<path d="M40 40h70v10h-70z M120 40h20v10h-20z`)
+}
+
+func TestWrite_WrongModuleSize(t *testing.T) {
+	t.Parallel()
+
+	code, err := Encode([]byte("HELLO"), LevelM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.EqualError(t, code.WritePNG(&bytes.Buffer{}, 0), "module size should be positive")
+	assert.EqualError(t, code.WriteSVG(&bytes.Buffer{}, 0), "module size should be positive")
+}
@@ -0,0 +1,30 @@
+package reconciliation
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/iso20022"
+	"github.com/yaroslavnayug/go-payment-system/internal/swift"
+)
+
+// Format is a format of bank statements file
+type Format string
+
+const (
+	FormatCamt053 Format = "camt053"
+	FormatMT940   Format = "mt940"
+)
+
+// ParseStatements reads bank statements file of format into the same statements model
+func ParseStatements(format Format, reader io.Reader) ([]*domain.BankStatement, error) {
+	switch format {
+	case FormatCamt053:
+		return iso20022.ParseCamt053(reader)
+	case FormatMT940:
+		return swift.ParseMT940(reader)
+	default:
+		return nil, fmt.Errorf("unsupported statements format %s", format)
+	}
+}
@@ -0,0 +1,111 @@
+package reconciliation
+
+import (
+	"strings"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// DefaultDateTolerance covers bank booking movements on the next business days after weekend
+const DefaultDateTolerance = 3 * 24 * time.Hour
+
+// Match pairs bank entry with movement
+type Match struct {
+	EntryID    string
+	MovementID string
+	Type       domain.BankEntryMatchType
+}
+
+// Matcher auto-matches bank entries to movements of the same currency, amount and direction.
+// Entry referencing movement id in end to end id or remittance information is matched first.
+// Other entries are matched by amount and date only when there is the only candidate on both sides,
+// ambiguous ones are left for manual matching.
+type Matcher struct {
+	dateTolerance time.Duration
+}
+
+func NewMatcher(dateTolerance time.Duration) *Matcher {
+	return &Matcher{dateTolerance: dateTolerance}
+}
+
+func (m *Matcher) Match(entries []*domain.BankEntry, movements []*domain.Movement) []Match {
+	var matches []Match
+	matchedEntries := make(map[string]bool)
+	matchedMovements := make(map[string]bool)
+
+	for _, entry := range entries {
+		for _, movement := range movements {
+			if matchedMovements[movement.GeneratedID] || !sameMoney(entry, movement) || !references(entry, movement) {
+				continue
+			}
+			matches = append(matches, Match{
+				EntryID:    entry.GeneratedID,
+				MovementID: movement.GeneratedID,
+				Type:       domain.BankEntryMatchTypeReference,
+			})
+			matchedEntries[entry.GeneratedID] = true
+			matchedMovements[movement.GeneratedID] = true
+			break
+		}
+	}
+
+	entryCandidates := make(map[string][]*domain.Movement)
+	movementCandidates := make(map[string]int)
+	for _, entry := range entries {
+		if matchedEntries[entry.GeneratedID] {
+			continue
+		}
+		for _, movement := range movements {
+			if matchedMovements[movement.GeneratedID] || !sameMoney(entry, movement) || !m.nearDate(entry, movement) {
+				continue
+			}
+			entryCandidates[entry.GeneratedID] = append(entryCandidates[entry.GeneratedID], movement)
+			movementCandidates[movement.GeneratedID]++
+		}
+	}
+	for _, entry := range entries {
+		candidates := entryCandidates[entry.GeneratedID]
+		if len(candidates) != 1 || movementCandidates[candidates[0].GeneratedID] != 1 {
+			continue
+		}
+		matches = append(matches, Match{
+			EntryID:    entry.GeneratedID,
+			MovementID: candidates[0].GeneratedID,
+			Type:       domain.BankEntryMatchTypeAmountDate,
+		})
+	}
+	return matches
+}
+
+// CanMatch tells whether movement could be matched to bank entry manually
+func CanMatch(entry *domain.BankEntry, movement *domain.Movement) bool {
+	return sameMoney(entry, movement)
+}
+
+// sameMoney compares amounts and directions, deposit is paid to settlement account,
+// withdrawal and transfer are paid from it
+func sameMoney(entry *domain.BankEntry, movement *domain.Movement) bool {
+	if entry.Amount != movement.Amount || entry.Currency != movement.Currency {
+		return false
+	}
+	switch movement.Type {
+	case domain.MovementTypeDeposit:
+		return entry.Direction == domain.BankEntryDirectionCredit
+	case domain.MovementTypeWithdrawal, domain.MovementTypeTransfer:
+		return entry.Direction == domain.BankEntryDirectionDebit
+	default:
+		return false
+	}
+}
+
+func references(entry *domain.BankEntry, movement *domain.Movement) bool {
+	return entry.Reference == movement.GeneratedID || strings.Contains(entry.RemittanceInfo, movement.GeneratedID)
+}
+
+// nearDate compares booking date with date of movement, both are dates in UTC
+func (m *Matcher) nearDate(entry *domain.BankEntry, movement *domain.Movement) bool {
+	year, month, day := movement.CreatedAt.UTC().Date()
+	difference := entry.BookingDate.Sub(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+	return difference >= -m.dateTolerance && difference <= m.dateTolerance
+}
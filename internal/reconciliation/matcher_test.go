@@ -0,0 +1,100 @@
+package reconciliation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2020, 8, 18, 0, 0, 0, 0, time.UTC)
+	newEntry := func(id string, direction domain.BankEntryDirection, amount int64, reference string) *domain.BankEntry {
+		return &domain.BankEntry{
+			GeneratedID: id, Direction: direction, Amount: amount, Currency: "RUB", BookingDate: day, Reference: reference,
+		}
+	}
+	newMovement := func(id string, movementType domain.MovementType, amount int64, createdAt time.Time) *domain.Movement {
+		return &domain.Movement{GeneratedID: id, Type: movementType, Amount: amount, Currency: "RUB", CreatedAt: createdAt}
+	}
+
+	tests := []struct {
+		name      string
+		entries   []*domain.BankEntry
+		movements []*domain.Movement
+		matches   []Match
+	}{
+		{
+			name:      "Reference",
+			entries:   []*domain.BankEntry{newEntry("entry", domain.BankEntryDirectionCredit, 500, "deposit")},
+			movements: []*domain.Movement{newMovement("deposit", domain.MovementTypeDeposit, 500, day.AddDate(0, 0, -10))},
+			matches:   []Match{{EntryID: "entry", MovementID: "deposit", Type: domain.BankEntryMatchTypeReference}},
+		},
+		{
+			name: "ReferenceInRemittanceInfo",
+			entries: []*domain.BankEntry{{
+				GeneratedID: "entry", Direction: domain.BankEntryDirectionDebit, Amount: 500, Currency: "RUB",
+				BookingDate: day, RemittanceInfo: "payout withdrawal by request",
+			}},
+			movements: []*domain.Movement{newMovement("withdrawal", domain.MovementTypeWithdrawal, 500, day)},
+			matches:   []Match{{EntryID: "entry", MovementID: "withdrawal", Type: domain.BankEntryMatchTypeReference}},
+		},
+		{
+			name:    "AmountAndDate",
+			entries: []*domain.BankEntry{newEntry("entry", domain.BankEntryDirectionDebit, 500, "")},
+			movements: []*domain.Movement{
+				newMovement("transfer", domain.MovementTypeTransfer, 500, day.Add(-2*24*time.Hour+time.Hour)),
+				newMovement("other amount", domain.MovementTypeTransfer, 501, day),
+				newMovement("deposit", domain.MovementTypeDeposit, 500, day),
+				newMovement("too early", domain.MovementTypeTransfer, 500, day.AddDate(0, 0, -4)),
+			},
+			matches: []Match{{EntryID: "entry", MovementID: "transfer", Type: domain.BankEntryMatchTypeAmountDate}},
+		},
+		{
+			name: "AmbiguousMovements",
+			entries: []*domain.BankEntry{
+				newEntry("entry", domain.BankEntryDirectionDebit, 500, ""),
+			},
+			movements: []*domain.Movement{
+				newMovement("transfer 1", domain.MovementTypeTransfer, 500, day),
+				newMovement("transfer 2", domain.MovementTypeWithdrawal, 500, day),
+			},
+		},
+		{
+			name: "AmbiguousEntries",
+			entries: []*domain.BankEntry{
+				newEntry("entry 1", domain.BankEntryDirectionDebit, 500, ""),
+				newEntry("entry 2", domain.BankEntryDirectionDebit, 500, ""),
+			},
+			movements: []*domain.Movement{newMovement("transfer", domain.MovementTypeTransfer, 500, day)},
+		},
+		{
+			name: "ReferenceResolvesAmbiguity",
+			entries: []*domain.BankEntry{
+				newEntry("entry 1", domain.BankEntryDirectionDebit, 500, "transfer 2"),
+				newEntry("entry 2", domain.BankEntryDirectionDebit, 500, ""),
+			},
+			movements: []*domain.Movement{
+				newMovement("transfer 1", domain.MovementTypeTransfer, 500, day),
+				newMovement("transfer 2", domain.MovementTypeTransfer, 500, day),
+			},
+			matches: []Match{
+				{EntryID: "entry 1", MovementID: "transfer 2", Type: domain.BankEntryMatchTypeReference},
+				{EntryID: "entry 2", MovementID: "transfer 1", Type: domain.BankEntryMatchTypeAmountDate},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			matches := NewMatcher(DefaultDateTolerance).Match(tt.entries, tt.movements)
+
+			assert.Equal(t, tt.matches, matches)
+		})
+	}
+}
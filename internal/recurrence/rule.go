@@ -0,0 +1,155 @@
+package recurrence
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	FrequencyDaily   Frequency = "DAILY"
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+)
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is a subset of RFC 5545 RRULE: FREQ, INTERVAL, BYDAY with a single weekday for weekly rules
+// and BYMONTHDAY for monthly rules. Occurrences happen at the time of day of the schedule start.
+// BYMONTHDAY greater than number of days in month means the last day of month.
+type Rule struct {
+	Frequency Frequency
+	Interval  int
+	Weekday   *time.Weekday
+	MonthDay  int
+}
+
+// Parse reads rule like FREQ=MONTHLY;BYMONTHDAY=5, optionally prefixed with RRULE:
+func Parse(value string) (Rule, error) {
+	rule := Rule{Interval: 1}
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return Rule{}, fmt.Errorf("recurrence is empty")
+	}
+
+	for _, part := range strings.Split(value, ";") {
+		keyValue := strings.SplitN(part, "=", 2)
+		if len(keyValue) != 2 {
+			return Rule{}, fmt.Errorf("wrong recurrence part %s", part)
+		}
+		key, partValue := strings.ToUpper(keyValue[0]), strings.ToUpper(keyValue[1])
+		switch key {
+		case "FREQ":
+			rule.Frequency = Frequency(partValue)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(partValue)
+			if err != nil || interval < 1 {
+				return Rule{}, fmt.Errorf("INTERVAL should be a positive number")
+			}
+			rule.Interval = interval
+		case "BYDAY":
+			weekday, ok := weekdays[partValue]
+			if !ok {
+				return Rule{}, fmt.Errorf("BYDAY should be a single weekday like MO")
+			}
+			rule.Weekday = &weekday
+		case "BYMONTHDAY":
+			monthDay, err := strconv.Atoi(partValue)
+			if err != nil || monthDay < 1 || monthDay > 31 {
+				return Rule{}, fmt.Errorf("BYMONTHDAY should be between 1 and 31")
+			}
+			rule.MonthDay = monthDay
+		default:
+			return Rule{}, fmt.Errorf("%s is not supported", key)
+		}
+	}
+
+	switch rule.Frequency {
+	case FrequencyDaily:
+		if rule.Weekday != nil || rule.MonthDay != 0 {
+			return Rule{}, fmt.Errorf("daily recurrence does not support BYDAY and BYMONTHDAY")
+		}
+	case FrequencyWeekly:
+		if rule.MonthDay != 0 {
+			return Rule{}, fmt.Errorf("weekly recurrence does not support BYMONTHDAY")
+		}
+	case FrequencyMonthly:
+		if rule.Weekday != nil {
+			return Rule{}, fmt.Errorf("monthly recurrence does not support BYDAY")
+		}
+	default:
+		return Rule{}, fmt.Errorf("FREQ should be one of DAILY, WEEKLY, MONTHLY")
+	}
+	return rule, nil
+}
+
+// Next returns the first occurrence after given time for schedule started at start.
+// Occurrences are calculated in location of start, so they keep local time of day across DST changes.
+func (r Rule) Next(start time.Time, after time.Time) time.Time {
+	after = after.In(start.Location())
+	for period := r.firstPeriod(start, after); ; period += r.Interval {
+		occurrence := r.occurrence(start, period)
+		if !occurrence.Before(start) && occurrence.After(after) {
+			return occurrence
+		}
+	}
+}
+
+// firstPeriod skips periods which certainly end before after
+func (r Rule) firstPeriod(start time.Time, after time.Time) int {
+	if !after.After(start) {
+		return 0
+	}
+	var periods int
+	switch r.Frequency {
+	case FrequencyDaily:
+		periods = int(after.Sub(start).Hours() / 24)
+	case FrequencyWeekly:
+		periods = int(after.Sub(start).Hours() / 24 / 7)
+	case FrequencyMonthly:
+		periods = (after.Year()-start.Year())*12 + int(after.Month()) - int(start.Month())
+	}
+	periods = periods/r.Interval*r.Interval - r.Interval
+	if periods < 0 {
+		return 0
+	}
+	return periods
+}
+
+// occurrence returns occurrence in period, periods are days, weeks or months since start
+func (r Rule) occurrence(start time.Time, period int) time.Time {
+	hour, minute, second := start.Clock()
+	switch r.Frequency {
+	case FrequencyWeekly:
+		day := start.Day() + period*7
+		if r.Weekday != nil {
+			day += (int(*r.Weekday) - int(start.Weekday()) + 7) % 7
+		}
+		return time.Date(start.Year(), start.Month(), day, hour, minute, second, 0, start.Location())
+	case FrequencyMonthly:
+		monthDay := r.MonthDay
+		if monthDay == 0 {
+			monthDay = start.Day()
+		}
+		firstOfMonth := time.Date(start.Year(), start.Month()+time.Month(period), 1, 0, 0, 0, 0, start.Location())
+		if daysInMonth := firstOfMonth.AddDate(0, 1, -1).Day(); monthDay > daysInMonth {
+			monthDay = daysInMonth
+		}
+		return time.Date(
+			firstOfMonth.Year(), firstOfMonth.Month(), monthDay, hour, minute, second, 0, start.Location(),
+		)
+	default:
+		return time.Date(start.Year(), start.Month(), start.Day()+period, hour, minute, second, 0, start.Location())
+	}
+}
@@ -0,0 +1,110 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	monday := time.Monday
+	testCases := []struct {
+		name          string
+		input         string
+		expectedRule  Rule
+		expectedError string
+	}{
+		{"Monthly", "FREQ=MONTHLY;BYMONTHDAY=5", Rule{Frequency: FrequencyMonthly, Interval: 1, MonthDay: 5}, ""},
+		{"WeeklyPrefixed", "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO", Rule{FrequencyWeekly, 2, &monday, 0}, ""},
+		{"Daily", "freq=daily", Rule{Frequency: FrequencyDaily, Interval: 1}, ""},
+		{"Empty", "", Rule{}, "recurrence is empty"},
+		{"UnknownFrequency", "FREQ=YEARLY", Rule{}, "FREQ should be one of DAILY, WEEKLY, MONTHLY"},
+		{"Until", "FREQ=DAILY;UNTIL=20201231T000000Z", Rule{}, "UNTIL is not supported"},
+		{"WrongMonthDay", "FREQ=MONTHLY;BYMONTHDAY=32", Rule{}, "BYMONTHDAY should be between 1 and 31"},
+		{"MonthlyByDay", "FREQ=MONTHLY;BYDAY=MO", Rule{}, "monthly recurrence does not support BYDAY"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := Parse(test.input)
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedRule, rule)
+		})
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	t.Parallel()
+
+	moscow := time.FixedZone("MSK", 3*60*60)
+	start := time.Date(2020, 1, 31, 10, 0, 0, 0, moscow)
+
+	testCases := []struct {
+		name     string
+		rule     string
+		after    time.Time
+		expected time.Time
+	}{
+		{
+			"MonthlyFirstOccurrence",
+			"FREQ=MONTHLY;BYMONTHDAY=5",
+			start.Add(-time.Hour),
+			time.Date(2020, 2, 5, 10, 0, 0, 0, moscow),
+		},
+		{
+			"MonthlyAfterOccurrence",
+			"FREQ=MONTHLY;BYMONTHDAY=5",
+			time.Date(2020, 8, 5, 10, 0, 0, 0, moscow),
+			time.Date(2020, 9, 5, 10, 0, 0, 0, moscow),
+		},
+		{
+			"MonthlyLastDayOfShortMonth",
+			"FREQ=MONTHLY",
+			start,
+			time.Date(2020, 2, 29, 10, 0, 0, 0, moscow),
+		},
+		{
+			"QuarterlyFarAfterStart",
+			"FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=1",
+			time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 7, 1, 10, 0, 0, 0, moscow),
+		},
+		{
+			"WeeklyOnMonday",
+			"FREQ=WEEKLY;BYDAY=MO",
+			start,
+			time.Date(2020, 2, 3, 10, 0, 0, 0, moscow),
+		},
+		{
+			"BiWeekly",
+			"FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+			time.Date(2020, 2, 3, 10, 0, 0, 0, moscow),
+			time.Date(2020, 2, 17, 10, 0, 0, 0, moscow),
+		},
+		{
+			"Daily",
+			"FREQ=DAILY",
+			time.Date(2020, 3, 10, 12, 0, 0, 0, moscow),
+			time.Date(2020, 3, 11, 10, 0, 0, 0, moscow),
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := Parse(test.rule)
+			assert.NoError(t, err)
+
+			next := rule.Next(start, test.after)
+
+			assert.True(t, test.expected.Equal(next), "expected %s, got %s", test.expected, next)
+		})
+	}
+}
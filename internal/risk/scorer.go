@@ -0,0 +1,183 @@
+package risk
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/kyc"
+)
+
+const (
+	// HistoryWindow is a period of previous assessments used to score amount and velocity
+	HistoryWindow = 30 * 24 * time.Hour
+
+	velocityWindow = time.Hour
+	maxScore       = 100
+)
+
+const (
+	FeatureCustomerStatus = "customer_status"
+	FeatureCustomerAge    = "customer_age"
+	FeatureAccountAge     = "account_age"
+	FeatureDevice         = "device"
+	FeatureIP             = "ip"
+	FeatureAmount         = "amount_to_average"
+	FeatureVelocity       = "payments_last_hour"
+)
+
+// Thresholds are minimal scores for review and decline decisions
+type Thresholds struct {
+	Review  int
+	Decline int
+}
+
+var DefaultThresholds = Thresholds{Review: 40, Decline: 70}
+
+// Input describes payment to score.
+// History contains previous assessments of the same customer for at least HistoryWindow before Now.
+type Input struct {
+	Customer    *domain.Customer
+	Amount      int64
+	Currency    string
+	DeviceID    string
+	KnownDevice bool
+	KnownIP     bool
+	History     []*domain.RiskAssessment
+	Now         time.Time
+}
+
+type Scorer struct {
+	thresholds Thresholds
+}
+
+func NewScorer(thresholds Thresholds) *Scorer {
+	return &Scorer{thresholds: thresholds}
+}
+
+// Score sums scores of all features, capped at 100, and compares the sum with thresholds.
+// All features are returned, including those which did not contribute to score.
+func (s *Scorer) Score(input Input) (int, domain.RiskDecision, []domain.RiskFeature) {
+	features := []domain.RiskFeature{
+		customerStatusFeature(input),
+		customerAgeFeature(input),
+		accountAgeFeature(input),
+		deviceFeature(input),
+		ipFeature(input),
+		amountFeature(input),
+		velocityFeature(input),
+	}
+
+	score := 0
+	for _, feature := range features {
+		score += feature.Score
+	}
+	if score > maxScore {
+		score = maxScore
+	}
+
+	switch {
+	case score >= s.thresholds.Decline:
+		return score, domain.RiskDecisionDecline, features
+	case score >= s.thresholds.Review:
+		return score, domain.RiskDecisionReview, features
+	default:
+		return score, domain.RiskDecisionApprove, features
+	}
+}
+
+func customerStatusFeature(input Input) domain.RiskFeature {
+	feature := domain.RiskFeature{Name: FeatureCustomerStatus, Value: string(input.Customer.Status)}
+	if input.Customer.Status != domain.CustomerStatusActive {
+		feature.Score = maxScore
+	}
+	return feature
+}
+
+func customerAgeFeature(input Input) domain.RiskFeature {
+	age := kyc.AgeAt(input.Customer.Passport.BirthDate, input.Now)
+	feature := domain.RiskFeature{Name: FeatureCustomerAge, Value: fmt.Sprintf("%d", age)}
+	switch {
+	case age < 21:
+		feature.Score = 15
+	case age >= 75:
+		feature.Score = 10
+	}
+	return feature
+}
+
+func accountAgeFeature(input Input) domain.RiskFeature {
+	accountAge := input.Now.Sub(input.Customer.CreatedAt)
+	feature := domain.RiskFeature{Name: FeatureAccountAge, Value: fmt.Sprintf("%d days", int(accountAge.Hours()/24))}
+	switch {
+	case accountAge < 24*time.Hour:
+		feature.Score = 25
+	case accountAge < 30*24*time.Hour:
+		feature.Score = 10
+	}
+	return feature
+}
+
+func deviceFeature(input Input) domain.RiskFeature {
+	feature := domain.RiskFeature{Name: FeatureDevice}
+	switch {
+	case input.DeviceID == "":
+		feature.Value = "missing"
+		feature.Score = 10
+	case input.KnownDevice:
+		feature.Value = "known"
+	default:
+		feature.Value = "new"
+		feature.Score = 20
+	}
+	return feature
+}
+
+func ipFeature(input Input) domain.RiskFeature {
+	if input.KnownIP {
+		return domain.RiskFeature{Name: FeatureIP, Value: "known"}
+	}
+	return domain.RiskFeature{Name: FeatureIP, Value: "new", Score: 10}
+}
+
+// amountFeature compares amount with average amount of previous payments in the same currency
+func amountFeature(input Input) domain.RiskFeature {
+	var count, sum int64
+	for _, assessment := range input.History {
+		if assessment.Currency == input.Currency {
+			count++
+			sum += assessment.Amount
+		}
+	}
+	if count == 0 || sum == 0 {
+		return domain.RiskFeature{Name: FeatureAmount, Value: "no history", Score: 10}
+	}
+
+	ratio := float64(input.Amount) / (float64(sum) / float64(count))
+	feature := domain.RiskFeature{Name: FeatureAmount, Value: fmt.Sprintf("%.2f", ratio)}
+	switch {
+	case ratio >= 10:
+		feature.Score = 30
+	case ratio >= 3:
+		feature.Score = 15
+	}
+	return feature
+}
+
+func velocityFeature(input Input) domain.RiskFeature {
+	since := input.Now.Add(-velocityWindow)
+	count := 0
+	for _, assessment := range input.History {
+		if assessment.CreatedAt.After(since) {
+			count++
+		}
+	}
+	feature := domain.RiskFeature{Name: FeatureVelocity, Value: fmt.Sprintf("%d", count)}
+	switch {
+	case count >= 10:
+		feature.Score = 35
+	case count >= 5:
+		feature.Score = 20
+	}
+	return feature
+}
@@ -0,0 +1,106 @@
+package risk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestScorer_Score(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2020, 8, 18, 12, 0, 0, 0, time.UTC)
+	regularCustomer := &domain.Customer{
+		Status:    domain.CustomerStatusActive,
+		Passport:  domain.Passport{BirthDate: time.Date(1985, 3, 1, 0, 0, 0, 0, time.UTC)},
+		CreatedAt: now.Add(-365 * 24 * time.Hour),
+	}
+	history := func(count int, amount int64, ago time.Duration) []*domain.RiskAssessment {
+		var assessments []*domain.RiskAssessment
+		for i := 0; i < count; i++ {
+			assessments = append(assessments, &domain.RiskAssessment{
+				Amount: amount, Currency: "RUB", CreatedAt: now.Add(-ago),
+			})
+		}
+		return assessments
+	}
+
+	testCases := []struct {
+		name             string
+		input            Input
+		expectedScore    int
+		expectedDecision domain.RiskDecision
+	}{
+		{
+			"RegularPayment",
+			Input{
+				Customer: regularCustomer, Amount: 1000, Currency: "RUB", DeviceID: "phone",
+				KnownDevice: true, KnownIP: true, History: history(3, 1000, 24*time.Hour), Now: now,
+			},
+			0,
+			domain.RiskDecisionApprove,
+		},
+		{
+			"NewDeviceAndIP",
+			Input{
+				Customer: regularCustomer, Amount: 1000, Currency: "RUB", DeviceID: "phone",
+				History: history(3, 1000, 24*time.Hour), Now: now,
+			},
+			30,
+			domain.RiskDecisionApprove,
+		},
+		{
+			"LargeAmountFromNewDevice",
+			Input{
+				Customer: regularCustomer, Amount: 10000, Currency: "RUB", DeviceID: "phone",
+				KnownIP: true, History: history(3, 1000, 24*time.Hour), Now: now,
+			},
+			50,
+			domain.RiskDecisionReview,
+		},
+		{
+			"YoungNewCustomerWithoutHistory",
+			Input{
+				Customer: &domain.Customer{
+					Status:    domain.CustomerStatusActive,
+					Passport:  domain.Passport{BirthDate: time.Date(2001, 9, 1, 0, 0, 0, 0, time.UTC)},
+					CreatedAt: now.Add(-time.Hour),
+				},
+				Amount: 1000, Currency: "RUB", Now: now,
+			},
+			70,
+			domain.RiskDecisionDecline,
+		},
+		{
+			"Velocity",
+			Input{
+				Customer: regularCustomer, Amount: 1000, Currency: "RUB", DeviceID: "phone",
+				KnownDevice: true, KnownIP: true, History: history(10, 1000, time.Minute), Now: now,
+			},
+			35,
+			domain.RiskDecisionApprove,
+		},
+		{
+			"BlockedCustomer",
+			Input{
+				Customer: &domain.Customer{Status: domain.CustomerStatusBlocked, CreatedAt: now.Add(-time.Hour)},
+				Amount:   1000, Currency: "RUB", Now: now,
+			},
+			100,
+			domain.RiskDecisionDecline,
+		},
+	}
+
+	scorer := NewScorer(DefaultThresholds)
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			score, decision, features := scorer.Score(test.input)
+
+			assert.Equal(t, test.expectedScore, score)
+			assert.Equal(t, test.expectedDecision, decision)
+			assert.Len(t, features, 7)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package sbp
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	payloadHost = "qr.nspk.ru"
+	// type codes of QR payload
+	staticTypeCode  = "01"
+	dynamicTypeCode = "02"
+	// MaxPurposeLength is a length limit of payment purpose in characters
+	MaxPurposeLength = 140
+)
+
+// payloadIDRegexp matches id of QR payload, it is 32 upper case latin letters and digits
+var payloadIDRegexp = regexp.MustCompile(`^[A-Z0-9]{32}$`)
+
+// Payload is a content of SBP QR code. Amount is in minor currency units, zero amount means
+// that payer enters amount.
+type Payload struct {
+	RequestID string
+	Type      domain.QRPaymentRequestType
+	Amount    int64
+	Currency  string
+	Purpose   string
+}
+
+// FormatPayload makes link of QR payment request in SBP format, like
+// https://qr.nspk.ru/AS1000670LSS7DN18SJQDNP4B05KLJL2?type=02&sum=10000&cur=RUB&payment_purpose=...
+// Request id is a lower case hex string, so it is written in upper case.
+func FormatPayload(request *domain.QRPaymentRequest) string {
+	var payload strings.Builder
+	payload.WriteString("https://" + payloadHost + "/" + strings.ToUpper(request.GeneratedID))
+	typeCode := staticTypeCode
+	if request.Type == domain.QRPaymentRequestTypeDynamic {
+		typeCode = dynamicTypeCode
+	}
+	payload.WriteString("?type=" + typeCode)
+	if request.Amount > 0 {
+		payload.WriteString("&sum=" + strconv.FormatInt(request.Amount, 10))
+	}
+	payload.WriteString("&cur=" + request.Currency)
+	if request.Purpose != "" {
+		payload.WriteString("&payment_purpose=" + url.QueryEscape(request.Purpose))
+	}
+	return payload.String()
+}
+
+// ParsePayload reads link made by FormatPayload, unknown parameters are ignored
+func ParsePayload(payload string) (*Payload, error) {
+	link, err := url.Parse(strings.TrimSpace(payload))
+	if err != nil || link.Scheme != "https" || link.Host != payloadHost {
+		return nil, fmt.Errorf("payload is not a link of SBP QR code")
+	}
+	id := strings.TrimPrefix(link.Path, "/")
+	if !payloadIDRegexp.MatchString(id) {
+		return nil, fmt.Errorf("wrong payload id %s", id)
+	}
+
+	query := link.Query()
+	result := &Payload{
+		RequestID: strings.ToLower(id),
+		Currency:  query.Get("cur"),
+		Purpose:   query.Get("payment_purpose"),
+	}
+	switch query.Get("type") {
+	case staticTypeCode:
+		result.Type = domain.QRPaymentRequestTypeStatic
+	case dynamicTypeCode:
+		result.Type = domain.QRPaymentRequestTypeDynamic
+	default:
+		return nil, fmt.Errorf("wrong payload type %s", query.Get("type"))
+	}
+	if sum := query.Get("sum"); sum != "" {
+		result.Amount, err = strconv.ParseInt(sum, 10, 64)
+		if err != nil || result.Amount <= 0 {
+			return nil, fmt.Errorf("wrong payload sum %s", sum)
+		}
+	}
+	return result, nil
+}
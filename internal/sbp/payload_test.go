@@ -0,0 +1,81 @@
+package sbp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestFormatPayload(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		request *domain.QRPaymentRequest
+		payload string
+	}{
+		{
+			"Dynamic",
+			&domain.QRPaymentRequest{
+				GeneratedID: "27771b5def0e30bd2ce5048e17032cab",
+				Type:        domain.QRPaymentRequestTypeDynamic,
+				Amount:      150050,
+				Currency:    "RUB",
+				Purpose:     "Заказ 15 & доставка",
+			},
+			"https://qr.nspk.ru/27771B5DEF0E30BD2CE5048E17032CAB?type=02&sum=150050&cur=RUB" +
+				"&payment_purpose=%D0%97%D0%B0%D0%BA%D0%B0%D0%B7+15+%26+%D0%B4%D0%BE%D1%81%D1%82%D0%B0%D0%B2%D0%BA%D0%B0",
+		},
+		{
+			"StaticWithoutAmount",
+			&domain.QRPaymentRequest{
+				GeneratedID: "27771b5def0e30bd2ce5048e17032cab",
+				Type:        domain.QRPaymentRequestTypeStatic,
+				Currency:    "RUB",
+			},
+			"https://qr.nspk.ru/27771B5DEF0E30BD2CE5048E17032CAB?type=01&cur=RUB",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			payload := FormatPayload(test.request)
+
+			assert.Equal(t, test.payload, payload)
+			parsed, err := ParsePayload(payload)
+			assert.Nil(t, err)
+			assert.Equal(t, &Payload{
+				RequestID: test.request.GeneratedID,
+				Type:      test.request.Type,
+				Amount:    test.request.Amount,
+				Currency:  test.request.Currency,
+				Purpose:   test.request.Purpose,
+			}, parsed)
+		})
+	}
+}
+
+func TestParsePayload_Error(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		payload string
+		result  string
+	}{
+		{"OtherHost", "https://example.com/27771B5DEF0E30BD2CE5048E17032CAB?type=01", "payload is not a link of SBP QR code"},
+		{"NotLink", "%zz", "payload is not a link of SBP QR code"},
+		{"WrongID", "https://qr.nspk.ru/27771b5def0e30bd?type=01", "wrong payload id 27771b5def0e30bd"},
+		{"WrongType", "https://qr.nspk.ru/27771B5DEF0E30BD2CE5048E17032CAB?type=03", "wrong payload type 03"},
+		{"WrongSum", "https://qr.nspk.ru/27771B5DEF0E30BD2CE5048E17032CAB?type=02&sum=-1", "wrong payload sum -1"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParsePayload(test.payload)
+
+			assert.EqualError(t, err, test.result)
+		})
+	}
+}
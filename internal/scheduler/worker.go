@@ -0,0 +1,118 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+	"go.uber.org/zap"
+)
+
+// batchSize limits number of items handled by one job run
+const batchSize = 100
+
+// Job handles up to limit items which are due at now and returns number of handled items
+type Job struct {
+	Name string
+	Run  func(now time.Time, limit int) (int, error)
+}
+
+// Worker runs jobs one after another every interval. Several instances could run at the same time,
+// so jobs claim items they handle.
+type Worker struct {
+	logger   *zap.Logger
+	interval time.Duration
+	jobs     []Job
+}
+
+func NewWorker(logger *zap.Logger, interval time.Duration, jobs ...Job) *Worker {
+	return &Worker{logger: logger, interval: interval, jobs: jobs}
+}
+
+// PaymentScheduleJobs plans and executes scheduled payments
+func PaymentScheduleJobs(useCase *usecase.PaymentScheduleUseCase) []Job {
+	return []Job{
+		{Name: "plan scheduled payments", Run: useCase.PlanDue},
+		{Name: "execute scheduled payments", Run: useCase.ExecuteDue},
+	}
+}
+
+// SubscriptionJobs renews subscriptions which period is over and charges their invoices
+func SubscriptionJobs(useCase *usecase.SubscriptionUseCase) []Job {
+	return []Job{
+		{Name: "renew subscriptions", Run: useCase.RenewDue},
+		{Name: "charge subscription invoices", Run: useCase.ChargeDue},
+	}
+}
+
+// PayoutJobs submits pending payouts to bank in credit transfer initiation files
+func PayoutJobs(useCase *usecase.PayoutUseCase) []Job {
+	return []Job{
+		{Name: "submit pending payouts", Run: useCase.SubmitPending},
+	}
+}
+
+// EscrowJobs releases or refunds escrows which expiry is over
+func EscrowJobs(useCase *usecase.EscrowUseCase) []Job {
+	return []Job{
+		{Name: "settle expired escrows", Run: useCase.SettleExpired},
+	}
+}
+
+// DisputeJobs resolves disputes which merchants did not respond to before deadline as lost
+func DisputeJobs(useCase *usecase.DisputeUseCase) []Job {
+	return []Job{
+		{Name: "lose overdue disputes", Run: useCase.LoseOverdue},
+	}
+}
+
+// DepositJobs accrues and capitalises interest of deposits once a day
+func DepositJobs(useCase *usecase.DepositUseCase) []Job {
+	return []Job{
+		{Name: "accrue deposit interest", Run: useCase.AccrueInterest},
+	}
+}
+
+// LoanJobs collects due installments of loans and accrues penalty on overdue ones
+func LoanJobs(useCase *usecase.LoanUseCase) []Job {
+	return []Job{
+		{Name: "service loans", Run: useCase.ServiceDue},
+	}
+}
+
+// CreditLineJobs accrues interest on negative balances of credit lines once a day and charges it monthly
+func CreditLineJobs(useCase *usecase.CreditLineUseCase) []Job {
+	return []Job{
+		{Name: "accrue credit line interest", Run: useCase.AccrueInterest},
+	}
+}
+
+// Run ticks every interval until stop is closed
+func (w *Worker) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			w.tick()
+		}
+	}
+}
+
+func (w *Worker) tick() {
+	for _, job := range w.jobs {
+		// batches are repeated while they are full, so backlog is handled without waiting for next ticks
+		for {
+			handled, err := job.Run(time.Now(), batchSize)
+			if err != nil {
+				w.logger.Error(fmt.Sprintf("error while %s. error: %s", job.Name, err.Error()))
+				break
+			}
+			if handled < batchSize {
+				break
+			}
+		}
+	}
+}
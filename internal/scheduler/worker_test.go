@@ -0,0 +1,648 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/beneficiary"
+	"github.com/yaroslavnayug/go-payment-system/internal/billing"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/iso20022"
+	"github.com/yaroslavnayug/go-payment-system/internal/monitoring"
+	"go.uber.org/zap"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/postgres/mocks"
+	"github.com/yaroslavnayug/go-payment-system/internal/usecase"
+)
+
+func TestWorker_Tick(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name              string
+		attempts          int
+		executeErr        error
+		expectedStatus    domain.ScheduleExecutionStatus
+		expectedAttempts  int
+		expectedLastError string
+	}{
+		{"Succeeded", 0, nil, domain.ScheduleExecutionStatusSucceeded, 1, ""},
+		{"Retried", 0, errors.New("insufficient funds"), domain.ScheduleExecutionStatusPending, 1, "insufficient funds"},
+		{"Failed", 3, errors.New("insufficient funds"), domain.ScheduleExecutionStatusFailed, 4, "insufficient funds"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			location, _ := time.LoadLocation("UTC")
+			schedule := &domain.PaymentSchedule{
+				GeneratedID: "schedule",
+				CustomerID:  "payer",
+				PayeeID:     "payee",
+				Amount:      10000,
+				Currency:    "RUB",
+				Recurrence:  "FREQ=MONTHLY;BYMONTHDAY=5",
+				Timezone:    "UTC",
+				StartAt:     time.Date(2020, 1, 1, 10, 0, 0, 0, location),
+				EndAt:       time.Date(2020, 3, 1, 0, 0, 0, 0, location),
+				NextRunAt:   time.Date(2020, 2, 5, 10, 0, 0, 0, location),
+				Status:      domain.PaymentScheduleStatusActive,
+			}
+			var plannedExecution *domain.ScheduleExecution
+
+			repositoryMock := mocks.NewMockPaymentScheduleRepository(ctrl)
+			repositoryMock.EXPECT().
+				ClaimDueSchedules(gomock.Any(), batchSize, gomock.Any()).
+				DoAndReturn(func(
+					_ time.Time,
+					_ int,
+					plan func(schedule *domain.PaymentSchedule) *domain.ScheduleExecution,
+				) (int, error) {
+					plannedExecution = plan(schedule)
+					return 1, nil
+				})
+			repositoryMock.EXPECT().
+				ClaimExecutions(gomock.Any(), gomock.Any(), batchSize).
+				DoAndReturn(func(_, _ time.Time, _ int) ([]*domain.ScheduleExecution, error) {
+					plannedExecution.Attempts = test.attempts
+					return []*domain.ScheduleExecution{plannedExecution}, nil
+				})
+			repositoryMock.EXPECT().FindByID("schedule").Return(schedule, nil)
+			repositoryMock.EXPECT().UpdateExecution(gomock.Any()).Return(nil)
+
+			var posted *domain.Debit
+			ledgerRepositoryMock := mocks.NewMockLedgerRepository(ctrl)
+			ledgerRepositoryMock.EXPECT().Post(gomock.Any()).DoAndReturn(func(debit *domain.Debit) (bool, error) {
+				posted = debit
+				return test.executeErr == nil, test.executeErr
+			})
+			var movements []*domain.Movement
+			feeSchedule := &domain.FeeSchedule{
+				Version: 2,
+				Rules:   []domain.FeeRule{{Operation: domain.MovementTypeTransfer, BasisPoints: 100}},
+			}
+
+			useCase := usecase.NewPaymentScheduleUseCase(
+				repositoryMock,
+				mocks.NewMockCustomerRepository(ctrl),
+				newLedgerUseCase(ctrl, ledgerRepositoryMock, feeSchedule, newMonitoringRepository(ctrl, &movements)),
+			)
+			logger, _ := zap.NewDevelopment()
+			worker := NewWorker(logger, time.Minute, PaymentScheduleJobs(useCase)...)
+
+			// act
+			worker.tick()
+
+			// assert
+			assert.Equal(t, time.Date(2020, 2, 5, 10, 0, 0, 0, location), plannedExecution.DueAt)
+			assert.Equal(t, domain.PaymentScheduleStatusFinished, schedule.Status)
+			assert.Equal(t, test.expectedStatus, plannedExecution.Status)
+			assert.Equal(t, test.expectedAttempts, plannedExecution.Attempts)
+			assert.Equal(t, test.expectedLastError, plannedExecution.LastError)
+			assert.Equal(t, int64(100), posted.Fee)
+			assert.Len(t, posted.Postings, 4)
+			assert.Equal(t, domain.LedgerAccountFees, posted.Postings[3].CustomerID)
+			assert.Equal(t, 2, posted.Postings[3].FeeScheduleVersion)
+			var monitored []string
+			for _, movement := range movements {
+				monitored = append(monitored, movement.CustomerID)
+			}
+			if test.executeErr == nil {
+				assert.Equal(t, []string{"payer", "payee"}, monitored)
+			} else {
+				assert.Empty(t, monitored)
+			}
+		})
+	}
+}
+
+func TestWorker_SubscriptionTick(t *testing.T) {
+	t.Parallel()
+
+	day := 24 * time.Hour
+	testCases := []struct {
+		name                       string
+		dueAgo                     time.Duration
+		chargeErr                  error
+		expectedInvoiceStatus      domain.SubscriptionInvoiceStatus
+		expectedSubscriptionStatus domain.SubscriptionStatus
+	}{
+		{"Paid", 0, nil, domain.SubscriptionInvoiceStatusPaid, domain.SubscriptionStatusActive},
+		{
+			"Retried",
+			0,
+			errors.New("card declined"),
+			domain.SubscriptionInvoiceStatusOpen,
+			domain.SubscriptionStatusPastDue,
+		},
+		{
+			"GracePeriodIsOver",
+			20 * day,
+			errors.New("card declined"),
+			domain.SubscriptionInvoiceStatusUncollectible,
+			domain.SubscriptionStatusCancelled,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			trialEnd := time.Date(2020, 1, 31, 10, 0, 0, 0, time.UTC)
+			subscription := &domain.Subscription{
+				GeneratedID:        "subscription",
+				CustomerID:         "foobar",
+				PlanID:             "basic",
+				Status:             domain.SubscriptionStatusTrialing,
+				BillingAnchor:      trialEnd,
+				CurrentPeriodStart: trialEnd.Add(-14 * day),
+				CurrentPeriodEnd:   trialEnd,
+				TrialEnd:           trialEnd,
+				PendingProration:   -500,
+			}
+			var invoice *domain.SubscriptionInvoice
+
+			repositoryMock := mocks.NewMockSubscriptionRepository(ctrl)
+			repositoryMock.EXPECT().
+				FindPlanByID("basic").
+				Return(&domain.Plan{
+					GeneratedID: "basic", Name: "Basic", Amount: 29900, Currency: "RUB",
+					Interval: domain.PlanIntervalMonth, IntervalCount: 1,
+				}, nil)
+			repositoryMock.EXPECT().
+				ClaimDueSubscriptions(gomock.Any(), batchSize, gomock.Any()).
+				DoAndReturn(func(
+					_ time.Time,
+					_ int,
+					renew func(subscription *domain.Subscription) (*domain.SubscriptionInvoice, error),
+				) (int, error) {
+					var err error
+					invoice, err = renew(subscription)
+					return 1, err
+				})
+			repositoryMock.EXPECT().
+				ClaimInvoices(gomock.Any(), gomock.Any(), batchSize).
+				DoAndReturn(func(now, _ time.Time, _ int) ([]*domain.SubscriptionInvoice, error) {
+					invoice.DueAt = now.Add(-test.dueAgo)
+					return []*domain.SubscriptionInvoice{invoice}, nil
+				})
+			repositoryMock.EXPECT().FindSubscriptionByID("subscription").Return(subscription, nil)
+			repositoryMock.EXPECT().UpdateInvoice(gomock.Any(), subscription).Return(nil)
+
+			ledgerRepositoryMock := mocks.NewMockLedgerRepository(ctrl)
+			ledgerRepositoryMock.EXPECT().Post(gomock.Any()).Return(test.chargeErr == nil, test.chargeErr)
+
+			useCase := usecase.NewSubscriptionUseCase(
+				repositoryMock,
+				mocks.NewMockCustomerRepository(ctrl),
+				newLedgerUseCase(ctrl, ledgerRepositoryMock, nil, newMonitoringRepository(ctrl, nil)),
+				billing.DefaultDunningPolicy,
+			)
+			logger, _ := zap.NewDevelopment()
+			worker := NewWorker(logger, time.Minute, SubscriptionJobs(useCase)...)
+
+			// act
+			worker.tick()
+
+			// assert
+			assert.Equal(t, time.Date(2020, 2, 29, 10, 0, 0, 0, time.UTC), subscription.CurrentPeriodEnd)
+			assert.Equal(t, int64(0), subscription.PendingProration)
+			assert.Equal(t, trialEnd, invoice.PeriodStart)
+			assert.Equal(t, int64(29400), invoice.Amount)
+			assert.Len(t, invoice.Items, 2)
+			assert.Equal(t, 1, invoice.Attempts)
+			assert.Equal(t, test.expectedInvoiceStatus, invoice.Status)
+			assert.Equal(t, test.expectedSubscriptionStatus, subscription.Status)
+		})
+	}
+}
+
+func TestWorker_PayoutTick(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payouts := []*domain.Payout{
+		{GeneratedID: "payout_1", Amount: 10000, Currency: "EUR", Status: domain.PayoutStatusPending},
+		{GeneratedID: "payout_2", Amount: 2550, Currency: "EUR", Status: domain.PayoutStatusPending},
+	}
+	var batch *domain.PayoutBatch
+
+	repositoryMock := mocks.NewMockPayoutRepository(ctrl)
+	repositoryMock.EXPECT().
+		SubmitPending(batchSize, gomock.Any()).
+		DoAndReturn(func(_ int, build func(payouts []*domain.Payout) (*domain.PayoutBatch, error)) (int, error) {
+			var err error
+			batch, err = build(payouts)
+			return len(payouts), err
+		})
+
+	debtor := iso20022.Party{Name: "Payment System LLC", IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX"}
+	useCase := usecase.NewPayoutUseCase(
+		repositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		mocks.NewMockBeneficiaryRepository(ctrl),
+		newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl), nil, newMonitoringRepository(ctrl, nil)),
+		nil,
+		beneficiary.DefaultCoolingOffPolicy,
+		debtor,
+	)
+	logger, _ := zap.NewDevelopment()
+	worker := NewWorker(logger, time.Minute, PayoutJobs(useCase)...)
+
+	// act
+	worker.tick()
+
+	// assert
+	assert.Equal(t, 2, batch.NumberOfTransactions)
+	assert.Equal(t, int64(12550), batch.ControlSum)
+	assert.Contains(t, string(batch.Document), "<CtrlSum>125.50</CtrlSum>")
+	for _, payout := range payouts {
+		assert.Equal(t, domain.PayoutStatusSubmitted, payout.Status)
+		assert.Equal(t, batch.GeneratedID, payout.BatchID)
+		assert.Equal(t, iso20022.PaymentInfoID(batch.GeneratedID, "EUR"), payout.PaymentInfoID)
+	}
+}
+
+// paidEscrowAmounts records amounts paid from escrow account by customer
+type paidEscrowAmounts map[string]int64
+
+func TestWorker_EscrowTick(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name           string
+		expiryAction   domain.EscrowExpiryAction
+		expectedStatus domain.EscrowStatus
+		expectedPaid   paidEscrowAmounts
+	}{
+		{"Released", domain.EscrowExpiryActionRelease, domain.EscrowStatusReleased, paidEscrowAmounts{"seller": 10000}},
+		{"Refunded", domain.EscrowExpiryActionRefund, domain.EscrowStatusRefunded, paidEscrowAmounts{"buyer": 10000}},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange deps
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			escrow := &domain.Escrow{
+				GeneratedID:  "escrow",
+				BuyerID:      "buyer",
+				SellerID:     "seller",
+				Amount:       10000,
+				Currency:     "RUB",
+				ExpiryAction: test.expiryAction,
+				Status:       domain.EscrowStatusHeld,
+				ExpiresAt:    time.Date(2020, 2, 5, 10, 0, 0, 0, time.UTC),
+			}
+
+			paid := paidEscrowAmounts{}
+			repositoryMock := mocks.NewMockEscrowRepository(ctrl)
+			repositoryMock.EXPECT().
+				ClaimExpired(gomock.Any(), batchSize, gomock.Any()).
+				DoAndReturn(func(
+					_ time.Time,
+					_ int,
+					settle func(escrow *domain.Escrow) ([]*domain.Debit, error),
+				) (int, error) {
+					debits, err := settle(escrow)
+					for _, debit := range debits {
+						paid[debit.PayeeID] += debit.Amount
+					}
+					return 1, err
+				})
+
+			useCase := usecase.NewEscrowUseCase(
+				repositoryMock,
+				mocks.NewMockCustomerRepository(ctrl),
+				newLedgerUseCase(ctrl, mocks.NewMockLedgerRepository(ctrl), nil, newMonitoringRepository(ctrl, nil)),
+				nil,
+			)
+			logger, _ := zap.NewDevelopment()
+			worker := NewWorker(logger, time.Minute, EscrowJobs(useCase)...)
+
+			// act
+			worker.tick()
+
+			// assert
+			assert.Equal(t, test.expectedStatus, escrow.Status)
+			assert.Equal(t, test.expectedPaid, paid)
+			assert.False(t, escrow.SettledAt.IsZero())
+		})
+	}
+}
+
+func TestWorker_DisputeTick(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dispute := &domain.Dispute{
+		GeneratedID: "dispute",
+		MerchantID:  "merchant",
+		PayerID:     "payer",
+		Amount:      10000,
+		Currency:    "RUB",
+		Status:      domain.DisputeStatusOpen,
+		RespondBy:   time.Date(2020, 2, 5, 10, 0, 0, 0, time.UTC),
+	}
+
+	var postings []*domain.Posting
+	repositoryMock := mocks.NewMockDisputeRepository(ctrl)
+	repositoryMock.EXPECT().
+		ClaimOverdue(gomock.Any(), batchSize, gomock.Any()).
+		DoAndReturn(func(_ time.Time, _ int, settle func(dispute *domain.Dispute) ([]*domain.Posting, error)) (int, error) {
+			var err error
+			postings, err = settle(dispute)
+			return 1, err
+		})
+
+	useCase := usecase.NewDisputeUseCase(
+		repositoryMock,
+		mocks.NewMockQRPaymentRepository(ctrl),
+		mocks.NewMockPostingRepository(ctrl),
+		nil,
+	)
+	logger, _ := zap.NewDevelopment()
+	worker := NewWorker(logger, time.Minute, DisputeJobs(useCase)...)
+
+	// act
+	worker.tick()
+
+	// assert
+	assert.Equal(t, domain.DisputeStatusLost, dispute.Status)
+	assert.False(t, dispute.ResolvedAt.IsZero())
+	if assert.Len(t, postings, 2) {
+		assert.Equal(t, domain.LedgerAccountDisputes, postings[0].CustomerID)
+		assert.Equal(t, int64(-10000), postings[0].Amount)
+		assert.Equal(t, "payer", postings[1].CustomerID)
+		assert.Equal(t, int64(10000), postings[1].Amount)
+		assert.Equal(t, "dispute:dispute", postings[1].Reference)
+	}
+}
+
+func TestWorker_DepositTick(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	deposit := &domain.Deposit{
+		GeneratedID:  "deposit",
+		ProductID:    "product",
+		Principal:    36500000,
+		Currency:     "RUB",
+		Status:       domain.DepositStatusOpen,
+		AccrualStart: yesterday,
+		AccruedUntil: yesterday,
+	}
+
+	var entries []*domain.DepositInterestEntry
+	var postings []*domain.Posting
+	repositoryMock := mocks.NewMockDepositRepository(ctrl)
+	repositoryMock.EXPECT().FindProducts().Return([]*domain.DepositProduct{{
+		GeneratedID:    "product",
+		Type:           domain.DepositTypeOnDemand,
+		AnnualRate:     "10",
+		Capitalisation: domain.DepositCapitalisationMonthly,
+		DayCount:       domain.DayCountACT365,
+	}}, nil)
+	repositoryMock.EXPECT().
+		ClaimDue(gomock.Any(), batchSize, gomock.Any()).
+		DoAndReturn(func(
+			_ time.Time,
+			_ int,
+			accrue func(deposit *domain.Deposit) ([]*domain.DepositInterestEntry, []*domain.Posting, error),
+		) (int, error) {
+			var err error
+			entries, postings, err = accrue(deposit)
+			return 1, err
+		})
+
+	useCase := usecase.NewDepositUseCase(
+		repositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		usecase.NewVerificationUseCase(mocks.NewMockVerificationRepository(ctrl), mocks.NewMockCustomerRepository(ctrl), nil),
+	)
+	logger, _ := zap.NewDevelopment()
+	worker := NewWorker(logger, time.Minute, DepositJobs(useCase)...)
+
+	// act
+	worker.tick()
+
+	// assert
+	assert.Len(t, entries, 1)
+	assert.Equal(t, domain.DepositInterestEntryTypeAccrual, entries[0].Type)
+	assert.Equal(t, int64(10000), entries[0].Amount)
+	assert.Equal(t, int64(10000), deposit.AccruedInterest+deposit.CapitalisedInterest)
+	if assert.Len(t, postings, 2) {
+		assert.Equal(t, domain.LedgerAccountInterestExpense, postings[0].CustomerID)
+		assert.Equal(t, int64(-10000), postings[0].Amount)
+		assert.Equal(t, domain.LedgerAccountAccruedInterest, postings[1].CustomerID)
+		assert.Equal(t, int64(10000), postings[1].Amount)
+	}
+}
+
+func TestWorker_LoanTick(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	today := businessday.Day(time.Now())
+	loan := &domain.Loan{
+		GeneratedID:         "loan",
+		CustomerID:          "customer",
+		Currency:            "RUB",
+		Principal:           100000,
+		Outstanding:         100000,
+		AnnualRate:          "12",
+		PenaltyRate:         "20",
+		Status:              domain.LoanStatusActive,
+		PenaltyAccruedUntil: today.AddDate(0, -1, 0),
+		NextServiceDay:      today,
+		Installments: []*domain.LoanInstallment{
+			{LoanID: "loan", Number: 1, DueDay: today, Principal: 50000, Interest: 1000},
+			{LoanID: "loan", Number: 2, DueDay: today.AddDate(0, 1, 0), Principal: 50000, Interest: 500},
+		},
+		DisbursedAt: today.AddDate(0, -1, 0),
+	}
+	for _, installment := range loan.Installments {
+		installment.Status = domain.LoanInstallmentStatusPending
+	}
+
+	var repayment *domain.LoanRepayment
+	var postings []*domain.Posting
+	repositoryMock := mocks.NewMockLoanRepository(ctrl)
+	repositoryMock.EXPECT().
+		ClaimDue(today, batchSize, gomock.Any()).
+		DoAndReturn(func(
+			_ time.Time,
+			_ int,
+			service func(loan *domain.Loan, balance int64) (*domain.LoanRepayment, []*domain.Posting, error),
+		) (int, error) {
+			var err error
+			repayment, postings, err = service(loan, 100000)
+			return 1, err
+		})
+
+	useCase := usecase.NewLoanUseCase(
+		repositoryMock,
+		mocks.NewMockCustomerRepository(ctrl),
+		usecase.NewVerificationUseCase(mocks.NewMockVerificationRepository(ctrl), mocks.NewMockCustomerRepository(ctrl), nil),
+	)
+	logger, _ := zap.NewDevelopment()
+	worker := NewWorker(logger, time.Minute, LoanJobs(useCase)...)
+
+	// act
+	worker.tick()
+
+	// assert
+	assert.Equal(t, int64(51000), repayment.Amount)
+	if assert.Len(t, postings, 4) {
+		assert.Equal(t, int64(-50000), postings[0].Amount)
+		assert.Equal(t, domain.LedgerAccountLoans, postings[1].CustomerID)
+		assert.Equal(t, int64(50000), postings[1].Amount)
+		assert.Equal(t, int64(-1000), postings[2].Amount)
+		assert.Equal(t, domain.LedgerAccountInterestIncome, postings[3].CustomerID)
+		assert.Equal(t, int64(1000), postings[3].Amount)
+		assert.Equal(t, "loan:loan", postings[3].Reference)
+	}
+	assert.Equal(t, int64(50000), loan.Outstanding)
+	assert.Equal(t, domain.LoanInstallmentStatusPaid, loan.Installments[0].Status)
+	assert.Equal(t, domain.LoanStatusActive, loan.Status)
+	assert.Equal(t, today.AddDate(0, 1, 0), loan.NextServiceDay)
+}
+
+func TestWorker_CreditLineTick(t *testing.T) {
+	t.Parallel()
+
+	// arrange deps
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	today := businessday.Day(time.Now())
+	line := &domain.CreditLine{
+		GeneratedID:  "line",
+		CustomerID:   "customer",
+		Currency:     "RUB",
+		Limit:        1000000,
+		AnnualRate:   "36.5",
+		AccruedUntil: time.Date(2021, time.January, 30, 0, 0, 0, 0, businessday.Location),
+	}
+	closing := []*domain.ClosingBalance{
+		{Day: time.Date(2021, time.January, 30, 0, 0, 0, 0, businessday.Location), Balance: -100000},
+		{Day: time.Date(2021, time.January, 31, 0, 0, 0, 0, businessday.Location), Balance: -300000},
+	}
+
+	var postings []*domain.Posting
+	repositoryMock := mocks.NewMockCreditLineRepository(ctrl)
+	repositoryMock.EXPECT().
+		ClaimDue(today, batchSize, gomock.Any()).
+		DoAndReturn(func(
+			_ time.Time,
+			_ int,
+			accrue func(line *domain.CreditLine, closing []*domain.ClosingBalance) ([]*domain.Posting, error),
+		) (int, error) {
+			var err error
+			postings, err = accrue(line, closing)
+			return 1, err
+		})
+
+	useCase := usecase.NewCreditLineUseCase(repositoryMock, mocks.NewMockCustomerRepository(ctrl))
+	logger, _ := zap.NewDevelopment()
+	worker := NewWorker(logger, time.Minute, CreditLineJobs(useCase)...)
+
+	// act
+	worker.tick()
+
+	// assert
+	if assert.Len(t, postings, 2) {
+		assert.Equal(t, "customer", postings[0].CustomerID)
+		assert.Equal(t, int64(-400), postings[0].Amount)
+		assert.Equal(t, domain.LedgerAccountInterestIncome, postings[1].CustomerID)
+		assert.Equal(t, int64(400), postings[1].Amount)
+		assert.Equal(t, "credit-line:line", postings[1].Reference)
+	}
+	assert.Equal(t, int64(400), line.ChargedInterest)
+	assert.Equal(t, int64(0), line.AccruedInterest)
+	assert.Equal(t, time.Date(2021, time.February, 1, 0, 0, 0, 0, businessday.Location), line.AccruedUntil)
+}
+
+// newLedgerUseCase builds ledger use case where customers are verified, their limits are not reached by debits
+// of tests and fees are charged by schedule, nil schedule means no fees. Debits are monitored by default rules.
+func newLedgerUseCase(
+	ctrl *gomock.Controller,
+	repo domain.LedgerRepository,
+	schedule *domain.FeeSchedule,
+	monitoringRepo domain.MonitoringRepository,
+) *usecase.LedgerUseCase {
+	customerRepositoryMock := mocks.NewMockCustomerRepository(ctrl)
+	customerRepositoryMock.EXPECT().
+		FindByID(gomock.Any()).
+		DoAndReturn(func(customerID string) (*domain.Customer, error) {
+			return &domain.Customer{GeneratedID: customerID, Status: domain.CustomerStatusActive}, nil
+		}).
+		AnyTimes()
+	verificationRepositoryMock := mocks.NewMockVerificationRepository(ctrl)
+	verificationRepositoryMock.EXPECT().
+		FindLastByCustomerID(gomock.Any()).
+		Return(&domain.Verification{Status: domain.VerificationStatusApproved, Level: domain.VerificationLevelFull}, nil).
+		AnyTimes()
+	feeRepositoryMock := mocks.NewMockFeeRepository(ctrl)
+	feeRepositoryMock.EXPECT().FindEffectiveSchedule(gomock.Any()).Return(schedule, nil).AnyTimes()
+	ruleSet, err := monitoring.LoadRuleSet("../../configs/monitoring_rules.yaml")
+	if err != nil {
+		ctrl.T.Fatalf("unable to load monitoring rules: %s", err.Error())
+	}
+	logger, _ := zap.NewDevelopment()
+	limitRepositoryMock := mocks.NewMockLimitRepository(ctrl)
+	limitRepositoryMock.EXPECT().
+		FindCustomerLimits(gomock.Any(), gomock.Any()).
+		Return(&domain.TransactionLimits{
+			PerTransaction: 1 << 40, Daily: 1 << 40, Monthly: 1 << 40, DailyCount: 1 << 20, MonthlyCount: 1 << 20,
+		}, nil).
+		AnyTimes()
+	return usecase.NewLedgerUseCase(
+		repo,
+		usecase.NewVerificationUseCase(verificationRepositoryMock, customerRepositoryMock, nil),
+		usecase.NewLimitUseCase(limitRepositoryMock, verificationRepositoryMock, customerRepositoryMock),
+		usecase.NewFeeUseCase(feeRepositoryMock),
+		usecase.NewMonitoringUseCase(monitoringRepo, customerRepositoryMock, ruleSet, logger),
+	)
+}
+
+// newMonitoringRepository builds monitoring repository without history, created movements are appended
+// to movements unless it is nil
+func newMonitoringRepository(ctrl *gomock.Controller, movements *[]*domain.Movement) domain.MonitoringRepository {
+	monitoringRepositoryMock := mocks.NewMockMonitoringRepository(ctrl)
+	monitoringRepositoryMock.EXPECT().FindMovements(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	monitoringRepositoryMock.EXPECT().
+		CreateMovement(gomock.Any()).
+		DoAndReturn(func(movement *domain.Movement) error {
+			if movements != nil {
+				*movements = append(*movements, movement)
+			}
+			return nil
+		}).
+		AnyTimes()
+	monitoringRepositoryMock.EXPECT().CreateAlerts(gomock.Any()).Return(nil).AnyTimes()
+	return monitoringRepositoryMock
+}
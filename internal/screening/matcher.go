@@ -0,0 +1,117 @@
+package screening
+
+import (
+	"fmt"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const DefaultThreshold = 0.92
+
+// legalForms are tokens of legal forms which are ignored in company names, like OOO or LLC. Tokens are
+// transliterated, so JSC is isc.
+var legalForms = map[string]bool{
+	"ooo": true, "ao": true, "pao": true, "zao": true, "oao": true, "nko": true,
+	"llc": true, "ltd": true, "inc": true, "isc": true, "plc": true, "gmbh": true,
+}
+
+type Matcher struct {
+	threshold float64
+}
+
+func NewMatcher(threshold float64) *Matcher {
+	return &Matcher{threshold: threshold}
+}
+
+// Match compares customer first and last name with names of entries and returns entries scored above threshold.
+// Legal entity is compared by company name. Entries are expected to be already filtered by birth date.
+func (m *Matcher) Match(customer *domain.Customer, entries []domain.SanctionEntry) []domain.SanctionMatch {
+	if customer.Type == domain.CustomerTypeLegalEntity {
+		return m.matchCompany(customer, entries)
+	}
+
+	firstName := Tokens(customer.FirstName)
+	lastName := Tokens(customer.LastName)
+	if len(firstName) == 0 || len(lastName) == 0 {
+		return nil
+	}
+
+	var matches []domain.SanctionMatch
+	for _, entry := range entries {
+		entryName := Tokens(entry.FullName)
+		score := (nameScore(firstName, entryName) + nameScore(lastName, entryName)) / 2
+		if score < m.threshold {
+			continue
+		}
+		matches = append(matches, domain.SanctionMatch{
+			CustomerID:     customer.GeneratedID,
+			ListName:       entry.ListName,
+			ListType:       entry.ListType,
+			EntryName:      entry.FullName,
+			EntryBirthDate: entry.BirthDate,
+			MatchedName:    fmt.Sprintf("%s %s", customer.FirstName, customer.LastName),
+			Score:          score,
+		})
+	}
+	return matches
+}
+
+// matchCompany scores company name against entries in both directions without legal forms, so that extra words
+// of either name lower the score
+func (m *Matcher) matchCompany(customer *domain.Customer, entries []domain.SanctionEntry) []domain.SanctionMatch {
+	companyName := companyTokens(customer.Company.Name)
+	if len(companyName) == 0 {
+		return nil
+	}
+
+	var matches []domain.SanctionMatch
+	for _, entry := range entries {
+		entryName := companyTokens(entry.FullName)
+		if len(entryName) == 0 {
+			continue
+		}
+		score := (nameScore(companyName, entryName) + nameScore(entryName, companyName)) / 2
+		if score < m.threshold {
+			continue
+		}
+		matches = append(matches, domain.SanctionMatch{
+			CustomerID:     customer.GeneratedID,
+			ListName:       entry.ListName,
+			ListType:       entry.ListType,
+			EntryName:      entry.FullName,
+			EntryBirthDate: entry.BirthDate,
+			MatchedName:    customer.Company.Name,
+			Score:          score,
+		})
+	}
+	return matches
+}
+
+func companyTokens(name string) []string {
+	var tokens []string
+	for _, token := range Tokens(name) {
+		if !legalForms[token] {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// nameScore is an average of the best similarity of every name token to any of entry tokens
+func nameScore(name []string, entryName []string) float64 {
+	if len(entryName) == 0 {
+		return 0
+	}
+	var total float64
+	for _, token := range name {
+		var best float64
+		for _, entryToken := range entryName {
+			similarity := JaroWinkler(token, entryToken)
+			if similarity > best {
+				best = similarity
+			}
+		}
+		total += best
+	}
+	return total / float64(len(name))
+}
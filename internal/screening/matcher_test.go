@@ -0,0 +1,121 @@
+package screening
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestTokens(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		result []string
+	}{
+		{"Latin", "Bruce Wayne", []string{"bruce", "vaine"}},
+		{"Cyrillic", "Иванов Иван Иванович", []string{"ivanov", "ivan", "ivanovich"}},
+		{"CyrillicComplexLetters", "Щукин Юрий", []string{"shchukin", "iuri"}},
+		{"LatinVariants", "Yuriy Jurij Iurii", []string{"iuri", "iuri", "iuri"}},
+		{"Punctuation", "O'Neil-Smith, John", []string{"o", "neil", "smith", "iohn"}},
+		{"Empty", " ", nil},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.result, Tokens(test.input))
+		})
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	assert.Equal(t, 1.0, JaroWinkler("martha", "martha"))
+	assert.InDelta(t, 0.961, JaroWinkler("martha", "marhta"), 0.001)
+	assert.InDelta(t, 0.840, JaroWinkler("dwayne", "duane"), 0.001)
+	assert.InDelta(t, 0.813, JaroWinkler("dixon", "dicksonx"), 0.001)
+	assert.Equal(t, 0.0, JaroWinkler("abc", ""))
+}
+
+func TestMatcher_Match(t *testing.T) {
+	birthDate, _ := time.Parse(domain.DateFormat, "01-01-1970")
+	entries := []domain.SanctionEntry{
+		{
+			ListName:  "local",
+			ListType:  domain.SanctionListTypeSanctions,
+			FullName:  "ИВАНОВ Иван Иванович",
+			BirthDate: birthDate,
+		},
+		{ListName: "local", ListType: domain.SanctionListTypePEP, FullName: "Petrov Sergey"},
+	}
+
+	testCases := []struct {
+		name        string
+		firstName   string
+		lastName    string
+		matchedList []string
+	}{
+		{"LatinToCyrillic", "Ivan", "Ivanov", []string{"ИВАНОВ Иван Иванович"}},
+		{"CyrillicToLatin", "Сергей", "Петров", []string{"Petrov Sergey"}},
+		{"Misspelled", "Sergei", "Petrow", []string{"Petrov Sergey"}},
+		{"OnlyLastNameMatched", "Petr", "Ivanov", nil},
+		{"NoMatch", "Bruce", "Wayne", nil},
+	}
+
+	matcher := NewMatcher(DefaultThreshold)
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			customer := &domain.Customer{GeneratedID: "foobar", FirstName: test.firstName, LastName: test.lastName}
+
+			matches := matcher.Match(customer, entries)
+
+			var matchedList []string
+			for _, match := range matches {
+				assert.Equal(t, "foobar", match.CustomerID)
+				assert.True(t, match.Score >= DefaultThreshold)
+				matchedList = append(matchedList, match.EntryName)
+			}
+			assert.Equal(t, test.matchedList, matchedList)
+		})
+	}
+}
+
+func TestMatcher_MatchCompany(t *testing.T) {
+	entries := []domain.SanctionEntry{
+		{ListName: "local", ListType: domain.SanctionListTypeSanctions, FullName: "Рособоронэкспорт"},
+		{ListName: "local", ListType: domain.SanctionListTypeSanctions, FullName: "Wayne Enterprises"},
+	}
+
+	testCases := []struct {
+		name        string
+		companyName string
+		matchedList []string
+	}{
+		{"CyrillicToLatin", "Rosoboroneksport", []string{"Рособоронэкспорт"}},
+		{"SameName", "Wayne Enterprises", []string{"Wayne Enterprises"}},
+		{"LegalForm", "ООО Wayne Enterprises", []string{"Wayne Enterprises"}},
+		{"ExtraWord", "Wayne Enterprises Holding", []string{"Wayne Enterprises"}},
+		{"DifferentWords", "Wayne Foundation", nil},
+		{"NoMatch", "Acme Corporation", nil},
+	}
+
+	matcher := NewMatcher(DefaultThreshold)
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			customer := &domain.Customer{
+				GeneratedID: "foobar",
+				Type:        domain.CustomerTypeLegalEntity,
+				Company:     domain.Company{Name: test.companyName},
+			}
+
+			matches := matcher.Match(customer, entries)
+
+			var matchedList []string
+			for _, match := range matches {
+				assert.Equal(t, test.companyName, match.MatchedName)
+				matchedList = append(matchedList, match.EntryName)
+			}
+			assert.Equal(t, test.matchedList, matchedList)
+		})
+	}
+}
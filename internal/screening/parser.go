@@ -0,0 +1,106 @@
+package screening
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	csvFullNameColumn  = "full_name"
+	csvBirthDateColumn = "birth_date"
+)
+
+type xmlList struct {
+	Entries []struct {
+		FullName  string `xml:"full_name"`
+		BirthDate string `xml:"birth_date"`
+	} `xml:"entry"`
+}
+
+// ParseCSV reads list with header containing full_name and optional birth_date (DD-MM-YYYY) columns
+func ParseCSV(reader io.Reader) ([]domain.SanctionEntry, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read csv header: %s", err.Error())
+	}
+	fullNameIndex, birthDateIndex := -1, -1
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(column)) {
+		case csvFullNameColumn:
+			fullNameIndex = i
+		case csvBirthDateColumn:
+			birthDateIndex = i
+		}
+	}
+	if fullNameIndex == -1 {
+		return nil, fmt.Errorf("csv header should contain %s column", csvFullNameColumn)
+	}
+
+	var entries []domain.SanctionEntry
+	for recordNumber := 1; ; recordNumber++ {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read csv record: %s", err.Error())
+		}
+
+		var birthDate string
+		if birthDateIndex != -1 {
+			birthDate = record[birthDateIndex]
+		}
+		entry, err := newEntry(record[fullNameIndex], birthDate)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %s", recordNumber, err.Error())
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ParseXML reads list of <entry> elements with <full_name> and optional <birth_date> (DD-MM-YYYY)
+func ParseXML(reader io.Reader) ([]domain.SanctionEntry, error) {
+	list := xmlList{}
+	err := xml.NewDecoder(reader).Decode(&list)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode xml: %s", err.Error())
+	}
+
+	entries := make([]domain.SanctionEntry, 0, len(list.Entries))
+	for i, xmlEntry := range list.Entries {
+		entry, err := newEntry(xmlEntry.FullName, xmlEntry.BirthDate)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %s", i+1, err.Error())
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func newEntry(fullName string, birthDate string) (domain.SanctionEntry, error) {
+	entry := domain.SanctionEntry{FullName: strings.TrimSpace(fullName)}
+	if entry.FullName == "" {
+		return entry, fmt.Errorf("%s is mandatory", csvFullNameColumn)
+	}
+
+	birthDate = strings.TrimSpace(birthDate)
+	if birthDate == "" {
+		return entry, nil
+	}
+	date, err := time.Parse(domain.DateFormat, birthDate)
+	if err != nil {
+		return entry, fmt.Errorf("wrong format for %s. DD-MM-YYYY expected", csvBirthDateColumn)
+	}
+	entry.BirthDate = date
+	return entry, nil
+}
@@ -0,0 +1,65 @@
+package screening
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestParseCSV(t *testing.T) {
+	birthDate, _ := time.Parse(domain.DateFormat, "01-02-1970")
+	input := "birth_date,full_name\n01-02-1970,ИВАНОВ Иван Иванович\n,\"Petrov, Sergey\"\n"
+
+	entries, err := ParseCSV(strings.NewReader(input))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.SanctionEntry{
+		{FullName: "ИВАНОВ Иван Иванович", BirthDate: birthDate},
+		{FullName: "Petrov, Sergey"},
+	}, entries)
+}
+
+func TestParseCSV_Error(t *testing.T) {
+	testCases := []struct {
+		name   string
+		input  string
+		result string
+	}{
+		{"NoFullNameColumn", "name,birth_date\nfoo,\n", "csv header should contain full_name column"},
+		{"EmptyFullName", "full_name,birth_date\nfoo,\n,01-01-1970\n", "record 2: full_name is mandatory"},
+		{"WrongDate", "full_name,birth_date\nfoo,1970-01-01\n", "record 1: wrong format for birth_date. DD-MM-YYYY expected"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseCSV(strings.NewReader(test.input))
+
+			assert.EqualError(t, err, test.result)
+		})
+	}
+}
+
+func TestParseXML(t *testing.T) {
+	birthDate, _ := time.Parse(domain.DateFormat, "01-02-1970")
+	input := `<?xml version="1.0" encoding="UTF-8"?>
+<list>
+	<entry>
+		<full_name>ИВАНОВ Иван Иванович</full_name>
+		<birth_date>01-02-1970</birth_date>
+	</entry>
+	<entry>
+		<full_name>Petrov Sergey</full_name>
+	</entry>
+</list>`
+
+	entries, err := ParseXML(strings.NewReader(input))
+
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.SanctionEntry{
+		{FullName: "ИВАНОВ Иван Иванович", BirthDate: birthDate},
+		{FullName: "Petrov Sergey"},
+	}, entries)
+}
@@ -0,0 +1,87 @@
+package screening
+
+const (
+	jaroWinklerPrefixLength = 4
+	jaroWinklerScaling      = 0.1
+)
+
+// JaroWinkler returns similarity of two strings from 0 (different) to 1 (equal)
+func JaroWinkler(a string, b string) float64 {
+	aRunes, bRunes := []rune(a), []rune(b)
+	jaro := jaroSimilarity(aRunes, bRunes)
+
+	prefix := 0
+	for i := 0; i < len(aRunes) && i < len(bRunes) && prefix < jaroWinklerPrefixLength; i++ {
+		if aRunes[i] != bRunes[i] {
+			break
+		}
+		prefix++
+	}
+	return jaro + float64(prefix)*jaroWinklerScaling*(1-jaro)
+}
+
+func jaroSimilarity(a []rune, b []rune) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	matchDistance := max(len(a), len(b))/2 - 1
+	if matchDistance < 0 {
+		matchDistance = 0
+	}
+
+	aMatches := make([]bool, len(a))
+	bMatches := make([]bool, len(b))
+	matches := 0
+	for i := range a {
+		from := max(0, i-matchDistance)
+		to := min(len(b), i+matchDistance+1)
+		for j := from; j < to; j++ {
+			if bMatches[j] || a[i] != b[j] {
+				continue
+			}
+			aMatches[i] = true
+			bMatches[j] = true
+			matches++
+			break
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range a {
+		if !aMatches[i] {
+			continue
+		}
+		for !bMatches[j] {
+			j++
+		}
+		if a[i] != b[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(a)) + m/float64(len(b)) + (m-float64(transpositions)/2)/m) / 3
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,58 @@
+package screening
+
+import (
+	"strings"
+	"unicode"
+)
+
+// cyrillicToLatin follows ICAO 9303 transliteration used in russian international passports
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "ie", 'ы': "y", 'ь': "", 'э': "e", 'ю': "iu",
+	'я': "ia", 'і': "i", 'ї': "i", 'є': "ie", 'ґ': "g", 'ў': "u",
+}
+
+// latinFolds reduces common spelling variants of the same russian name to one form,
+// e.g. Yuriy, Iurii and Jurij all become iuri. Order matters.
+var latinFolds = []struct {
+	from string
+	to   string
+}{
+	{"yu", "iu"},
+	{"ya", "ia"},
+	{"yo", "e"},
+	{"ye", "e"},
+	{"kh", "h"},
+	{"w", "v"},
+	{"x", "ks"},
+	{"j", "i"},
+	{"y", "i"},
+	{"ii", "i"},
+}
+
+// Tokens transliterates name to latin and splits it to lowercase folded words
+func Tokens(name string) []string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if latin, ok := cyrillicToLatin[r]; ok {
+			builder.WriteString(latin)
+			continue
+		}
+		if r <= unicode.MaxASCII && unicode.IsLetter(r) {
+			builder.WriteRune(r)
+			continue
+		}
+		builder.WriteRune(' ')
+	}
+
+	var tokens []string
+	for _, token := range strings.Fields(builder.String()) {
+		for _, fold := range latinFolds {
+			token = strings.Replace(token, fold.from, fold.to, -1)
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
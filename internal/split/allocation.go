@@ -0,0 +1,90 @@
+package split
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// MaxLegs limits number of recipients of one payment
+const MaxLegs = 10
+
+// percentRegexp allows decimal percents with up to 6 fraction digits, like 10, 0.5 or 12.125
+var percentRegexp = regexp.MustCompile(`^\d{1,3}(\.\d{1,6})?$`)
+
+var hundred = big.NewRat(100, 1)
+
+// Allocate validates legs of payment and calculates amounts of percent legs. Fixed amounts and exact
+// percent shares of payment amount should sum to payment amount. Percent shares are rounded down
+// to a minor unit and the units left are given one by one to legs with the largest rounded off fractions,
+// so rounded legs sum to payment amount exactly.
+func Allocate(payment *domain.SplitPayment) error {
+	if payment.Amount <= 0 {
+		return fmt.Errorf("amount should be positive")
+	}
+	if len(payment.Legs) == 0 || len(payment.Legs) > MaxLegs {
+		return fmt.Errorf("payment should have 1 to %d splits", MaxLegs)
+	}
+
+	amount := big.NewRat(payment.Amount, 1)
+	exactSum := new(big.Rat)
+	var allocated int64
+	fractions := make(map[int]*big.Rat)
+	for i := range payment.Legs {
+		leg := &payment.Legs[i]
+		if leg.RecipientID == "" {
+			return fmt.Errorf("split %d: recipient is mandatory", i+1)
+		}
+		if leg.RecipientID == payment.PayerID {
+			return fmt.Errorf("split %d: payer could not be a recipient", i+1)
+		}
+		if leg.Percent == "" {
+			if leg.Amount <= 0 {
+				return fmt.Errorf("split %d: amount or percent should be positive", i+1)
+			}
+			exactSum.Add(exactSum, big.NewRat(leg.Amount, 1))
+			allocated += leg.Amount
+			continue
+		}
+
+		percent, ok := new(big.Rat).SetString(leg.Percent)
+		if !percentRegexp.MatchString(leg.Percent) || !ok || percent.Sign() == 0 || percent.Cmp(hundred) > 0 {
+			return fmt.Errorf("split %d: percent should be a decimal between 0 and 100", i+1)
+		}
+		share := new(big.Rat).Mul(amount, percent)
+		share.Quo(share, hundred)
+		exactSum.Add(exactSum, share)
+
+		rounded := new(big.Int).Quo(share.Num(), share.Denom())
+		leg.Amount = rounded.Int64()
+		allocated += leg.Amount
+		fractions[i] = share.Sub(share, new(big.Rat).SetInt(rounded))
+	}
+	if exactSum.Cmp(amount) != 0 {
+		return fmt.Errorf("splits should sum to payment amount")
+	}
+
+	// exact shares sum to payment amount, so fewer units are left than there are percent legs
+	order := make([]int, 0, len(fractions))
+	for i := range fractions {
+		order = append(order, i)
+	}
+	sort.Slice(order, func(a, b int) bool {
+		if cmp := fractions[order[a]].Cmp(fractions[order[b]]); cmp != 0 {
+			return cmp > 0
+		}
+		return order[a] < order[b]
+	})
+	for _, i := range order[:payment.Amount-allocated] {
+		payment.Legs[i].Amount++
+	}
+	for i, leg := range payment.Legs {
+		if leg.Amount == 0 {
+			return fmt.Errorf("split %d: percent share is less than a minor unit", i+1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package split
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestAllocate(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name            string
+		amount          int64
+		legs            []domain.SplitLeg
+		expectedErr     string
+		expectedAmounts []int64
+	}{
+		{
+			"FixedAmounts",
+			10000,
+			[]domain.SplitLeg{{RecipientID: "seller", Amount: 8500}, {RecipientID: "platform", Amount: 1500}},
+			"",
+			[]int64{8500, 1500},
+		},
+		{
+			"PercentsWithFixedDelivery",
+			10000,
+			[]domain.SplitLeg{
+				{RecipientID: "seller", Percent: "85"},
+				{RecipientID: "platform", Percent: "10"},
+				{RecipientID: "delivery", Amount: 500},
+			},
+			"",
+			[]int64{8500, 1000, 500},
+		},
+		{
+			"LeftUnitGoesToLargestFraction",
+			// 33.33% of 1000 = 333.3, 33.33% = 333.3, 33.34% = 333.4, so the left unit goes to the last leg
+			1000,
+			[]domain.SplitLeg{
+				{RecipientID: "a", Percent: "33.33"},
+				{RecipientID: "b", Percent: "33.33"},
+				{RecipientID: "c", Percent: "33.34"},
+			},
+			"",
+			[]int64{333, 333, 334},
+		},
+		{
+			"TiesGoToEarlierLegs",
+			// 50% of 101 = 50.5 twice
+			101,
+			[]domain.SplitLeg{{RecipientID: "a", Percent: "50"}, {RecipientID: "b", Percent: "50"}},
+			"",
+			[]int64{51, 50},
+		},
+		{
+			"PercentOfTotalWithFixedLeg",
+			// 87.5% of 200 = 175, the rest 25 is fixed
+			200,
+			[]domain.SplitLeg{{RecipientID: "seller", Percent: "87.5"}, {RecipientID: "platform", Amount: 25}},
+			"",
+			[]int64{175, 25},
+		},
+		{
+			"NotSummedToAmount",
+			10000,
+			[]domain.SplitLeg{{RecipientID: "seller", Percent: "90"}, {RecipientID: "platform", Amount: 500}},
+			"splits should sum to payment amount",
+			nil,
+		},
+		{
+			"PercentOverHundred",
+			10000,
+			[]domain.SplitLeg{{RecipientID: "seller", Percent: "100.5"}},
+			"split 1: percent should be a decimal between 0 and 100",
+			nil,
+		},
+		{
+			"PayerIsRecipient",
+			10000,
+			[]domain.SplitLeg{{RecipientID: "payer", Amount: 10000}},
+			"split 1: payer could not be a recipient",
+			nil,
+		},
+		{
+			"ShareLessThanMinorUnit",
+			100,
+			[]domain.SplitLeg{{RecipientID: "seller", Percent: "99.5"}, {RecipientID: "platform", Percent: "0.5"}},
+			"split 2: percent share is less than a minor unit",
+			nil,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			payment := &domain.SplitPayment{PayerID: "payer", Amount: test.amount, Legs: test.legs}
+			err := Allocate(payment)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			var amounts []int64
+			for _, leg := range payment.Legs {
+				amounts = append(amounts, leg.Amount)
+			}
+			assert.Equal(t, test.expectedAmounts, amounts)
+		})
+	}
+}
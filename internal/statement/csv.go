@@ -0,0 +1,84 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// csvWriter writes statement as spreadsheets of Russian locale expect it:
+// values are separated by semicolon and amounts have decimal comma
+type csvWriter struct {
+	writer    *csv.Writer
+	statement *domain.Statement
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+	return &csvWriter{writer: writer}
+}
+
+func (c *csvWriter) WriteHeader(statement *domain.Statement) error {
+	c.statement = statement
+	err := c.writer.Write([]string{"date", "description", "reference", "debit", "credit", "balance"})
+	if err != nil {
+		return err
+	}
+	return c.writer.Write([]string{
+		statement.From.Format(domain.DateFormat),
+		"Opening balance",
+		"",
+		"",
+		"",
+		formatCSVAmount(statement.OpeningBalance),
+	})
+}
+
+func (c *csvWriter) WritePosting(posting *domain.Posting, balance int64) error {
+	var debit, credit string
+	if posting.Amount < 0 {
+		debit = formatCSVAmount(-posting.Amount)
+	} else {
+		credit = formatCSVAmount(posting.Amount)
+	}
+	return c.writer.Write([]string{
+		posting.PostedAt.In(businessday.Location).Format(domain.DateFormat),
+		posting.Description,
+		posting.Reference,
+		debit,
+		credit,
+		formatCSVAmount(balance),
+	})
+}
+
+func (c *csvWriter) WriteFooter(closingBalance int64) error {
+	err := c.writer.Write([]string{
+		c.statement.To.Format(domain.DateFormat),
+		"Closing balance",
+		"",
+		"",
+		"",
+		formatCSVAmount(closingBalance),
+	})
+	if err != nil {
+		return err
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// formatCSVAmount shows amount in minor units as -1234567,89
+func formatCSVAmount(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	fraction := strconv.FormatInt(amount%100, 10)
+	return sign + strconv.FormatInt(amount/100, 10) + "," + strings.Repeat("0", 2-len(fraction)) + fraction
+}
@@ -0,0 +1,79 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+type jsonStatementHeader struct {
+	CustomerID     string `json:"customer_id"`
+	Currency       string `json:"currency"`
+	From           string `json:"from"`
+	To             string `json:"to"`
+	OpeningBalance int64  `json:"opening_balance"`
+}
+
+type jsonPosting struct {
+	PostingID   string `json:"posting_id"`
+	PostedAt    string `json:"posted_at"`
+	Description string `json:"description"`
+	Reference   string `json:"reference"`
+	Amount      int64  `json:"amount"`
+	Balance     int64  `json:"balance"`
+}
+
+// jsonWriter writes statement as a single JSON object with postings array, which is written element by element
+type jsonWriter struct {
+	writer   io.Writer
+	postings int
+}
+
+func newJSONWriter(w io.Writer) *jsonWriter {
+	return &jsonWriter{writer: w}
+}
+
+func (j *jsonWriter) WriteHeader(statement *domain.Statement) error {
+	header, err := json.Marshal(jsonStatementHeader{
+		CustomerID:     statement.CustomerID,
+		Currency:       statement.Currency,
+		From:           statement.From.Format(domain.DateFormat),
+		To:             statement.To.Format(domain.DateFormat),
+		OpeningBalance: statement.OpeningBalance,
+	})
+	if err != nil {
+		return err
+	}
+	// object is left open to continue it with postings
+	_, err = j.writer.Write(append(bytes.TrimSuffix(header, []byte("}")), `,"postings":[`...))
+	return err
+}
+
+func (j *jsonWriter) WritePosting(posting *domain.Posting, balance int64) error {
+	body, err := json.Marshal(jsonPosting{
+		PostingID:   posting.GeneratedID,
+		PostedAt:    posting.PostedAt.In(businessday.Location).Format(domain.DateTimeFormat),
+		Description: posting.Description,
+		Reference:   posting.Reference,
+		Amount:      posting.Amount,
+		Balance:     balance,
+	})
+	if err != nil {
+		return err
+	}
+	if j.postings > 0 {
+		body = append([]byte(","), body...)
+	}
+	j.postings++
+	_, err = j.writer.Write(body)
+	return err
+}
+
+func (j *jsonWriter) WriteFooter(closingBalance int64) error {
+	_, err := fmt.Fprintf(j.writer, `],"closing_balance":%d}`, closingBalance)
+	return err
+}
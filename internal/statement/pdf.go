@@ -0,0 +1,136 @@
+package statement
+
+import (
+	"io"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/pdf"
+)
+
+const (
+	pageMargin = 40.0
+	rowHeight  = 16.0
+	fontSize   = 9.0
+	headerSize = 18.0
+)
+
+// tableColumns are right edges of statement table columns, text columns are left aligned after previous column
+var tableColumns = struct {
+	date, description, reference, debit, credit, balance float64
+}{
+	date:        pageMargin + 55,
+	description: pageMargin + 255,
+	reference:   pageMargin + 335,
+	debit:       pageMargin + 395,
+	credit:      pageMargin + 455,
+	balance:     pdf.PageWidth - pageMargin,
+}
+
+// pdfWriter writes every page of statement as soon as it is filled with postings
+type pdfWriter struct {
+	document *pdf.Writer
+	page     *pdf.Page
+	y        float64
+}
+
+func newPDFWriter(w io.Writer) *pdfWriter {
+	return &pdfWriter{document: pdf.NewWriter(w)}
+}
+
+func (p *pdfWriter) WriteHeader(statement *domain.Statement) error {
+	p.page = &pdf.Page{}
+	p.y = pdf.PageHeight - pageMargin - headerSize
+	p.page.Text(pageMargin, p.y, headerSize, true, "Account statement")
+
+	p.y -= 2 * rowHeight
+	details := [][2]string{
+		{"Customer", statement.CustomerName},
+		{"Customer ID", statement.CustomerID},
+		{"Currency", statement.Currency},
+		{"Period", statement.From.Format(domain.DateFormat) + " - " + statement.To.Format(domain.DateFormat)},
+		{"Opening balance", pdf.FormatAmount(statement.OpeningBalance)},
+	}
+	for _, detail := range details {
+		p.page.Text(pageMargin, p.y, fontSize, true, detail[0])
+		p.page.Text(pageMargin+80, p.y, fontSize, false, pdf.TruncateText(detail[1], fontSize, 300))
+		p.y -= rowHeight
+	}
+
+	p.y -= rowHeight
+	p.writeTableHeader()
+	return nil
+}
+
+func (p *pdfWriter) WritePosting(posting *domain.Posting, balance int64) error {
+	if p.y < pageMargin+rowHeight {
+		err := p.nextPage()
+		if err != nil {
+			return err
+		}
+		p.writeTableHeader()
+	}
+
+	var debit, credit string
+	if posting.Amount < 0 {
+		debit = pdf.FormatAmount(-posting.Amount)
+	} else {
+		credit = pdf.FormatAmount(posting.Amount)
+	}
+	descriptionWidth := tableColumns.description - tableColumns.date - 20
+	referenceWidth := tableColumns.reference - tableColumns.description - 10
+	postedAt := posting.PostedAt.In(businessday.Location).Format(domain.DateFormat)
+	p.page.TextRight(tableColumns.date, p.y, fontSize, false, postedAt)
+	p.page.Text(
+		tableColumns.date+10, p.y, fontSize, false,
+		pdf.TruncateText(posting.Description, fontSize, descriptionWidth),
+	)
+	p.page.Text(
+		tableColumns.description+10, p.y, fontSize, false,
+		pdf.TruncateText(posting.Reference, fontSize, referenceWidth),
+	)
+	p.page.TextRight(tableColumns.debit, p.y, fontSize, false, debit)
+	p.page.TextRight(tableColumns.credit, p.y, fontSize, false, credit)
+	p.page.TextRight(tableColumns.balance, p.y, fontSize, false, pdf.FormatAmount(balance))
+	p.y -= rowHeight
+	return nil
+}
+
+func (p *pdfWriter) WriteFooter(closingBalance int64) error {
+	if p.y < pageMargin {
+		err := p.nextPage()
+		if err != nil {
+			return err
+		}
+	}
+	p.page.Line(tableColumns.credit, p.y+rowHeight-4, tableColumns.balance, p.y+rowHeight-4)
+	p.page.TextRight(tableColumns.credit, p.y, fontSize, true, "Closing balance")
+	p.page.TextRight(tableColumns.balance, p.y, fontSize, true, pdf.FormatAmount(closingBalance))
+
+	err := p.document.WritePage(p.page)
+	if err != nil {
+		return err
+	}
+	return p.document.Close()
+}
+
+func (p *pdfWriter) nextPage() error {
+	err := p.document.WritePage(p.page)
+	if err != nil {
+		return err
+	}
+	p.page = &pdf.Page{}
+	p.y = pdf.PageHeight - pageMargin - rowHeight
+	return nil
+}
+
+func (p *pdfWriter) writeTableHeader() {
+	p.page.TextRight(tableColumns.date, p.y, fontSize, true, "Date")
+	p.page.Text(tableColumns.date+10, p.y, fontSize, true, "Description")
+	p.page.Text(tableColumns.description+10, p.y, fontSize, true, "Reference")
+	p.page.TextRight(tableColumns.debit, p.y, fontSize, true, "Debit")
+	p.page.TextRight(tableColumns.credit, p.y, fontSize, true, "Credit")
+	p.page.TextRight(tableColumns.balance, p.y, fontSize, true, "Balance")
+	p.page.Line(pageMargin, p.y-4, pdf.PageWidth-pageMargin, p.y-4)
+	p.y -= rowHeight
+}
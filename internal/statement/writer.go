@@ -0,0 +1,49 @@
+package statement
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatPDF  Format = "pdf"
+	FormatJSON Format = "json"
+)
+
+// Writer writes statement while postings are streamed: header with opening balance first,
+// then every posting with balance after it, then footer with closing balance
+type Writer interface {
+	WriteHeader(statement *domain.Statement) error
+	WritePosting(posting *domain.Posting, balance int64) error
+	// WriteFooter completes document, writer should not be used afterwards
+	WriteFooter(closingBalance int64) error
+}
+
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatPDF:
+		return newPDFWriter(w), nil
+	case FormatJSON:
+		return newJSONWriter(w), nil
+	default:
+		return nil, fmt.Errorf("statement format %s is not supported", format)
+	}
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "application/json"
+	}
+}
@@ -0,0 +1,147 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+var testStatement = &domain.Statement{
+	CustomerID:     "foobar",
+	CustomerName:   "Миша Иванов",
+	Currency:       "RUB",
+	From:           time.Date(2020, 8, 1, 0, 0, 0, 0, businessday.Location),
+	To:             time.Date(2020, 8, 31, 0, 0, 0, 0, businessday.Location),
+	OpeningBalance: 100000,
+}
+
+var testPostings = []*domain.Posting{
+	{
+		GeneratedID: "posting_1",
+		Amount:      -2550,
+		Description: "Оплата; счёт \"42\"",
+		Reference:   "invoice:42",
+		// the first of August in Moscow
+		PostedAt: time.Date(2020, 7, 31, 22, 30, 0, 0, time.UTC),
+	},
+	{
+		GeneratedID: "posting_2",
+		Amount:      5,
+		Description: "Interest",
+		PostedAt:    time.Date(2020, 8, 15, 12, 0, 0, 0, time.UTC),
+	},
+}
+
+func TestWriter_CSV(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+	writeTestStatement(t, FormatCSV, &output)
+
+	assert.Equal(t, strings.Join([]string{
+		"date;description;reference;debit;credit;balance",
+		"01-08-2020;Opening balance;;;;1000,00",
+		`01-08-2020;"Оплата; счёт ""42""";invoice:42;25,50;;974,50`,
+		"15-08-2020;Interest;;;0,05;974,55",
+		"31-08-2020;Closing balance;;;;974,55",
+		"",
+	}, "\n"), output.String())
+}
+
+func TestWriter_JSON(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+	writeTestStatement(t, FormatJSON, &output)
+
+	body := struct {
+		jsonStatementHeader
+		Postings       []jsonPosting `json:"postings"`
+		ClosingBalance int64         `json:"closing_balance"`
+	}{}
+	err := json.Unmarshal(output.Bytes(), &body)
+	assert.NoError(t, err)
+	assert.Equal(t, "01-08-2020", body.From)
+	assert.Equal(t, "31-08-2020", body.To)
+	assert.Equal(t, int64(100000), body.OpeningBalance)
+	assert.Equal(t, []jsonPosting{
+		{
+			PostingID:   "posting_1",
+			PostedAt:    "01-08-2020 01:30:00",
+			Description: "Оплата; счёт \"42\"",
+			Reference:   "invoice:42",
+			Amount:      -2550,
+			Balance:     97450,
+		},
+		{PostingID: "posting_2", PostedAt: "15-08-2020 15:00:00", Description: "Interest", Amount: 5, Balance: 97455},
+	}, body.Postings)
+	assert.Equal(t, int64(97455), body.ClosingBalance)
+}
+
+func TestWriter_JSONWithoutPostings(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+	writer, _ := NewWriter(FormatJSON, &output)
+	_ = writer.WriteHeader(testStatement)
+	_ = writer.WriteFooter(testStatement.OpeningBalance)
+
+	assert.Equal(
+		t,
+		`{"customer_id":"foobar","currency":"RUB","from":"01-08-2020","to":"31-08-2020",`+
+			`"opening_balance":100000,"postings":[],"closing_balance":100000}`,
+		output.String(),
+	)
+}
+
+func TestWriter_PDF(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+	writer, err := NewWriter(FormatPDF, &output)
+	assert.NoError(t, err)
+	err = writer.WriteHeader(testStatement)
+	assert.NoError(t, err)
+	balance := testStatement.OpeningBalance
+	for i := 0; i < 100; i++ {
+		balance -= 100
+		posting := &domain.Posting{Amount: -100, Description: fmt.Sprintf("Перевод %d", i), PostedAt: time.Now()}
+		err = writer.WritePosting(posting, balance)
+		assert.NoError(t, err)
+	}
+	err = writer.WriteFooter(balance)
+	assert.NoError(t, err)
+
+	assert.True(t, bytes.HasPrefix(output.Bytes(), []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(output.Bytes(), []byte("%%EOF\n")))
+	assert.Contains(t, output.String(), "/Count 3")
+}
+
+func TestNewWriter_UnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewWriter("xlsx", &bytes.Buffer{})
+	assert.EqualError(t, err, "statement format xlsx is not supported")
+}
+
+func writeTestStatement(t *testing.T, format Format, output *bytes.Buffer) {
+	writer, err := NewWriter(format, output)
+	assert.NoError(t, err)
+	err = writer.WriteHeader(testStatement)
+	assert.NoError(t, err)
+	balance := testStatement.OpeningBalance
+	for _, posting := range testPostings {
+		balance += posting.Amount
+		err = writer.WritePosting(posting, balance)
+		assert.NoError(t, err)
+	}
+	err = writer.WriteFooter(balance)
+	assert.NoError(t, err)
+}
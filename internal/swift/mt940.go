@@ -0,0 +1,370 @@
+package swift
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const (
+	dateFormat = "060102"
+	// maxInformationLineLength is a length of :86: line, longer text is wrapped by bank without a space
+	maxInformationLineLength = 65
+	// maxSubfieldLength is a length of remittance subfield of structured :86: field
+	maxSubfieldLength    = 27
+	noReference          = "NONREF"
+	notProvidedReference = "NOTPROVIDED"
+)
+
+var (
+	tagRegexp     = regexp.MustCompile(`^:(\d{2}[A-Z]?):(.*)$`)
+	balanceRegexp = regexp.MustCompile(`^([CD])(\d{6})([A-Z]{3})(\d{1,15},\d{0,2})$`)
+	// statementLineRegexp is the first line of :61: field: value date, optional booking date, debit credit mark
+	// with optional reversal, optional funds code, amount, transaction type, reference for account owner
+	// and optional reference of account servicing institution. References longer than 16 characters
+	// are accepted, since some banks do not cut them.
+	statementLineRegexp = regexp.MustCompile(
+		`^(\d{6})(\d{4})?(R?[CD])([A-Z])?(\d{1,15},\d{0,2})([SNF][A-Z0-9]{3})(.*?)(?://(.*))?$`,
+	)
+	// structuredInformationRegexp is a business transaction code of :86: field with ?NN subfields
+	structuredInformationRegexp = regexp.MustCompile(`^\d{3}\?`)
+	subfieldRegexp              = regexp.MustCompile(`\?(\d{2})`)
+	// sepaKeywordRegexp splits remittance of SEPA transfer like EREF+id SVWZ+text into keywords
+	sepaKeywordRegexp = regexp.MustCompile(`(EREF|KREF|MREF|CRED|DEBT|SVWZ|ABWA|ABWE|IBAN|BIC)\+`)
+)
+
+type field struct {
+	tag   string
+	lines []string
+}
+
+// ParseMT940 reads statements of SWIFT MT940 file. File could contain several statements with or without
+// SWIFT message blocks, statements are split by :20: field. Reversal of credit is a debit entry and
+// reversal of debit is a credit one. Statement balances are checked against entries like in camt.053.
+// Text which is not valid UTF-8 is read as Latin-1.
+func ParseMT940(reader io.Reader) ([]*domain.BankStatement, error) {
+	fields, err := readFields(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []*domain.BankStatement
+	var statementFields []field
+	for _, f := range fields {
+		if f.tag == "20" && len(statementFields) > 0 {
+			statement, err := statementFromFields(statementFields)
+			if err != nil {
+				return nil, fmt.Errorf("statement %d: %s", len(statements)+1, err.Error())
+			}
+			statements = append(statements, statement)
+			statementFields = nil
+		}
+		statementFields = append(statementFields, f)
+	}
+	if len(statementFields) > 0 {
+		statement, err := statementFromFields(statementFields)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %s", len(statements)+1, err.Error())
+		}
+		statements = append(statements, statement)
+	}
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("file has no statements")
+	}
+	return statements, nil
+}
+
+// readFields skips SWIFT message blocks and end of message marks, lines which are not tags continue previous field
+func readFields(reader io.Reader) ([]field, error) {
+	var fields []field
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var text string
+		if utf8.Valid(line) {
+			text = string(line)
+		} else {
+			runes := make([]rune, len(line))
+			for i, char := range line {
+				runes[i] = rune(char)
+			}
+			text = string(runes)
+		}
+		text = strings.TrimRight(strings.TrimPrefix(text, "\uFEFF"), " \r")
+
+		// message text block starts after {4: and ends with -}
+		if index := strings.Index(text, "{4:"); index >= 0 {
+			text = text[index+len("{4:"):]
+		}
+		if text == "" || text == "-" || strings.HasPrefix(text, "-}") || strings.HasPrefix(text, "{") {
+			continue
+		}
+
+		if match := tagRegexp.FindStringSubmatch(text); match != nil {
+			fields = append(fields, field{tag: match[1], lines: []string{match[2]}})
+			continue
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("unexpected text before the first field: %s", text)
+		}
+		last := &fields[len(fields)-1]
+		last.lines = append(last.lines, text)
+	}
+	if scanner.Err() != nil {
+		return nil, scanner.Err()
+	}
+	return fields, nil
+}
+
+func statementFromFields(fields []field) (*domain.BankStatement, error) {
+	statement := &domain.BankStatement{}
+	var reference, number string
+	var openingDate time.Time
+	var hasOpening, hasClosing bool
+	var entry *domain.BankEntry
+	for _, f := range fields {
+		value := strings.TrimSpace(f.lines[0])
+		switch f.tag {
+		case "20":
+			reference = value
+		case "25":
+			statement.AccountIBAN = value
+		case "28", "28C":
+			number = value
+		case "60F", "60M":
+			currency, date, amount, err := parseBalance(value)
+			if err != nil {
+				return nil, fmt.Errorf("opening balance: %s", err.Error())
+			}
+			statement.Currency, openingDate, statement.OpeningBalance, hasOpening = currency, date, amount, true
+		case "62F", "62M":
+			currency, _, amount, err := parseBalance(value)
+			if err != nil {
+				return nil, fmt.Errorf("closing balance: %s", err.Error())
+			}
+			if statement.Currency != "" && currency != statement.Currency {
+				return nil, fmt.Errorf("closing balance currency %s differs from opening one", currency)
+			}
+			statement.ClosingBalance, hasClosing = amount, true
+		case "61":
+			var err error
+			entry, err = entryFromStatementLine(f.lines)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: %s", len(statement.Entries)+1, err.Error())
+			}
+			entry.Currency = statement.Currency
+			statement.Entries = append(statement.Entries, entry)
+		case "86":
+			// information to account owner belongs to preceding statement line,
+			// information to the whole statement goes after closing balance and is skipped
+			if entry != nil && !hasClosing {
+				withInformation(entry, f.lines)
+			}
+		}
+	}
+
+	if reference == "" || statement.AccountIBAN == "" {
+		return nil, fmt.Errorf("reference :20: and account :25: are mandatory")
+	}
+	if !hasOpening || !hasClosing {
+		return nil, fmt.Errorf("opening :60: and closing :62: balances are mandatory")
+	}
+	// :20: is not unique for some banks, so statement is identified with its number and date as well
+	statement.BankStatementID = strings.Join([]string{reference, number, openingDate.Format("2006-01-02")}, "/")
+
+	balance := statement.OpeningBalance
+	for _, entry := range statement.Entries {
+		if entry.Direction == domain.BankEntryDirectionCredit {
+			balance += entry.Amount
+		} else {
+			balance -= entry.Amount
+		}
+	}
+	if balance != statement.ClosingBalance {
+		return nil, fmt.Errorf(
+			"closing balance %d does not match opening balance and entries %d",
+			statement.ClosingBalance,
+			balance,
+		)
+	}
+	return statement, nil
+}
+
+// parseBalance reads balance like C200818EUR1234,56, debit balance is negative
+func parseBalance(value string) (currency string, date time.Time, amount int64, err error) {
+	match := balanceRegexp.FindStringSubmatch(value)
+	if match == nil {
+		return "", time.Time{}, 0, fmt.Errorf("wrong balance %s", value)
+	}
+	date, err = time.Parse(dateFormat, match[2])
+	if err != nil {
+		return "", time.Time{}, 0, fmt.Errorf("wrong date %s", match[2])
+	}
+	amount, err = parseAmount(match[4])
+	if err != nil {
+		return "", time.Time{}, 0, err
+	}
+	if match[1] == "D" {
+		amount = -amount
+	}
+	return match[3], date, amount, nil
+}
+
+func entryFromStatementLine(lines []string) (*domain.BankEntry, error) {
+	match := statementLineRegexp.FindStringSubmatch(strings.TrimSpace(lines[0]))
+	if match == nil {
+		return nil, fmt.Errorf("wrong statement line %s", lines[0])
+	}
+
+	entry := &domain.BankEntry{AccountServicerReference: strings.TrimSpace(match[8])}
+	var err error
+	entry.ValueDate, err = time.Parse(dateFormat, match[1])
+	if err != nil {
+		return nil, fmt.Errorf("wrong value date %s", match[1])
+	}
+	entry.BookingDate = entry.ValueDate
+	if match[2] != "" {
+		entry.BookingDate, err = bookingDate(entry.ValueDate, match[2])
+		if err != nil {
+			return nil, err
+		}
+	}
+	switch match[3] {
+	case "C", "RD":
+		entry.Direction = domain.BankEntryDirectionCredit
+	case "D", "RC":
+		entry.Direction = domain.BankEntryDirectionDebit
+	}
+	entry.Amount, err = parseAmount(match[5])
+	if err != nil {
+		return nil, err
+	}
+	if reference := strings.TrimSpace(match[7]); reference != noReference {
+		entry.Reference = reference
+	}
+	if len(lines) > 1 {
+		entry.RemittanceInfo = strings.TrimSpace(strings.Join(lines[1:], " "))
+	}
+	return entry, nil
+}
+
+// bookingDate reads MMDD booking date which year is the closest to value date, so that entry booked
+// in January for value date in December is booked in the next year
+func bookingDate(valueDate time.Time, monthDay string) (time.Time, error) {
+	month, err := strconv.Atoi(monthDay[:2])
+	if err != nil || month < 1 || month > 12 {
+		return time.Time{}, fmt.Errorf("wrong booking date %s", monthDay)
+	}
+	day, err := strconv.Atoi(monthDay[2:])
+	if err != nil || day < 1 || day > 31 {
+		return time.Time{}, fmt.Errorf("wrong booking date %s", monthDay)
+	}
+
+	closest := time.Date(valueDate.Year(), time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	for _, year := range []int{valueDate.Year() - 1, valueDate.Year() + 1} {
+		date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		if absDuration(date.Sub(valueDate)) < absDuration(closest.Sub(valueDate)) {
+			closest = date
+		}
+	}
+	return closest, nil
+}
+
+// withInformation takes remittance information and end to end reference from :86: field. Structured field
+// like 166?00SEPA-UEBERWEISUNG?20EREF+id?21SVWZ+text has remittance in subfields ?20 - ?29 and ?60 - ?63.
+func withInformation(entry *domain.BankEntry, lines []string) {
+	if !structuredInformationRegexp.MatchString(lines[0]) {
+		entry.RemittanceInfo = strings.TrimSpace(joinInformationLines(lines))
+		return
+	}
+
+	// lines of structured field are wrapped anywhere, subfields are joined with a space unless subfield
+	// is wrapped at full length
+	text := strings.Join(lines, "")
+	var remittance strings.Builder
+	previousLength := maxSubfieldLength
+	indexes := subfieldRegexp.FindAllStringSubmatchIndex(text, -1)
+	for i, index := range indexes {
+		end := len(text)
+		if i+1 < len(indexes) {
+			end = indexes[i+1][0]
+		}
+		code, _ := strconv.Atoi(text[index[2]:index[3]])
+		if (code < 20 || code > 29) && (code < 60 || code > 63) {
+			continue
+		}
+		subfield := text[index[1]:end]
+		if previousLength < maxSubfieldLength {
+			remittance.WriteString(" ")
+		}
+		remittance.WriteString(subfield)
+		previousLength = utf8.RuneCountInString(subfield)
+	}
+
+	keywords := sepaKeywords(remittance.String())
+	if keywords == nil {
+		entry.RemittanceInfo = strings.TrimSpace(remittance.String())
+		return
+	}
+	if reference := keywords["EREF"]; reference != "" && reference != notProvidedReference {
+		entry.Reference = reference
+	}
+	entry.RemittanceInfo = keywords["SVWZ"]
+}
+
+// joinInformationLines joins lines of unstructured :86: field with a space unless line is wrapped at full length
+func joinInformationLines(lines []string) string {
+	var text strings.Builder
+	for i, line := range lines {
+		if i > 0 && utf8.RuneCountInString(lines[i-1]) < maxInformationLineLength {
+			text.WriteString(" ")
+		}
+		text.WriteString(line)
+	}
+	return text.String()
+}
+
+// sepaKeywords returns nil when remittance has no SEPA keywords
+func sepaKeywords(remittance string) map[string]string {
+	indexes := sepaKeywordRegexp.FindAllStringSubmatchIndex(remittance, -1)
+	if len(indexes) == 0 {
+		return nil
+	}
+	keywords := make(map[string]string, len(indexes))
+	for i, index := range indexes {
+		end := len(remittance)
+		if i+1 < len(indexes) {
+			end = indexes[i+1][0]
+		}
+		keywords[remittance[index[2]:index[3]]] = strings.TrimSpace(remittance[index[1]:end])
+	}
+	return keywords
+}
+
+// parseAmount converts amount with decimal comma like 1234,5 into minor currency units
+func parseAmount(value string) (int64, error) {
+	parts := strings.SplitN(value, ",", 2)
+	fraction := "00"
+	if len(parts) == 2 {
+		fraction = (parts[1] + "00")[:2]
+	}
+	amount, err := strconv.ParseInt(parts[0]+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("wrong amount %s", value)
+	}
+	return amount, nil
+}
+
+func absDuration(duration time.Duration) time.Duration {
+	if duration < 0 {
+		return -duration
+	}
+	return duration
+}
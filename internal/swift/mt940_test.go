@@ -0,0 +1,99 @@
+package swift
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "update golden files of MT940 test corpus")
+
+// TestParseMT940_Golden parses every testdata/*.sta file and compares statements with JSON in .golden file
+func TestParseMT940_Golden(t *testing.T) {
+	paths, err := filepath.Glob("testdata/*.sta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		path := path
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			file, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			statements, err := ParseMT940(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			actual, err := json.MarshalIndent(statements, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			goldenPath := strings.TrimSuffix(path, ".sta") + ".golden"
+			if *update {
+				err = ioutil.WriteFile(goldenPath, append(actual, '\n'), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := ioutil.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, string(expected), string(actual)+"\n")
+		})
+	}
+}
+
+func TestParseMT940_Errors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		file          string
+		expectedError string
+	}{
+		{
+			"NoStatements",
+			"{1:F01COBADEFFAXXX0000000000}{4:\n-}",
+			"file has no statements",
+		},
+		{
+			"NoClosingBalance",
+			":20:REF\n:25:DE89370400440532013000\n:60F:C200818EUR0,00\n",
+			"statement 1: opening :60: and closing :62: balances are mandatory",
+		},
+		{
+			"MissedEntry",
+			":20:REF\n:25:DE89370400440532013000\n:60F:C200818EUR0,00\n:62F:C200818EUR10,00\n",
+			"statement 1: closing balance 1000 does not match opening balance and entries 0",
+		},
+		{
+			"WrongStatementLine",
+			":20:REF\n:25:DE89370400440532013000\n:60F:C200818EUR0,00\n:61:200818X10,00NTRFNONREF\n",
+			"statement 1: entry 1: wrong statement line 200818X10,00NTRFNONREF",
+		},
+		{
+			"WrongBookingDate",
+			":20:REF\n:25:DE89370400440532013000\n:60F:C200818EUR0,00\n:61:2008181318C10,00NTRFNONREF\n",
+			"statement 1: entry 1: wrong booking date 1318",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseMT940(strings.NewReader(test.file))
+
+			assert.EqualError(t, err, test.expectedError)
+		})
+	}
+}
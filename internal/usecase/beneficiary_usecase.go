@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/beneficiary"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+)
+
+type BeneficiaryUseCase struct {
+	repo         domain.BeneficiaryRepository
+	customerRepo domain.CustomerRepository
+	policy       beneficiary.CoolingOffPolicy
+}
+
+func NewBeneficiaryUseCase(
+	repo domain.BeneficiaryRepository,
+	customerRepo domain.CustomerRepository,
+	policy beneficiary.CoolingOffPolicy,
+) *BeneficiaryUseCase {
+	return &BeneficiaryUseCase{repo: repo, customerRepo: customerRepo, policy: policy}
+}
+
+// Create saves beneficiary with cooling-off period of policy. Payee details should be normalized,
+// customer could not save the same details twice.
+func (s *BeneficiaryUseCase) Create(payee *domain.Beneficiary) error {
+	customer, err := s.customerRepo.FindByID(payee.CustomerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.NewNotFoundError("customer with such id not found")
+	}
+
+	switch payee.Type {
+	case domain.BeneficiaryTypeCustomer:
+		if payee.RecipientID == customer.GeneratedID {
+			return domain.NewValidationError("customer could not be own beneficiary")
+		}
+		recipient, err := s.customerRepo.FindByID(payee.RecipientID)
+		if err != nil {
+			return err
+		}
+		if recipient == nil {
+			return domain.NewValidationError("recipient customer not found")
+		}
+	case domain.BeneficiaryTypePhone:
+		if payee.Phone == customer.Phone {
+			return domain.NewValidationError("own phone could not be beneficiary")
+		}
+	}
+	payee.PayeeKey, err = beneficiary.PayeeKey(payee)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	now := time.Now()
+	payee.GeneratedID, err = hash.GenerateUniqueBeneficiaryID(payee.CustomerID, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	payee.TrustedAt = s.policy.TrustedAt(now)
+	payee.CreatedAt = now
+	payee.UpdatedAt = now
+
+	created, err := s.repo.Create(payee)
+	if err != nil {
+		return err
+	}
+	if created {
+		return nil
+	}
+	existing, err := s.repo.FindByPayeeKey(payee.CustomerID, payee.PayeeKey)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return fmt.Errorf("beneficiary with payee key %s is neither saved nor found", payee.PayeeKey)
+	}
+	return domain.NewValidationError(
+		fmt.Sprintf("beneficiary with the same details is already saved as %s", existing.GeneratedID),
+	)
+}
+
+// Find returns beneficiary of customer, beneficiaries of other customers are not found
+func (s *BeneficiaryUseCase) Find(customerID string, beneficiaryID string) (*domain.Beneficiary, error) {
+	return findBeneficiary(s.repo, customerID, beneficiaryID)
+}
+
+func (s *BeneficiaryUseCase) FindByCustomer(customerID string) ([]*domain.Beneficiary, error) {
+	beneficiaries, err := s.repo.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return beneficiaries, nil
+}
+
+// Rename changes nickname only, cooling-off period is not restarted as payee is the same
+func (s *BeneficiaryUseCase) Rename(
+	customerID string,
+	beneficiaryID string,
+	nickname string,
+) (*domain.Beneficiary, error) {
+	payee, err := findBeneficiary(s.repo, customerID, beneficiaryID)
+	if err != nil {
+		return nil, err
+	}
+	payee.Nickname = nickname
+	payee.UpdatedAt = time.Now()
+	err = s.repo.UpdateNickname(payee.GeneratedID, payee.Nickname, payee.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return payee, nil
+}
+
+// Delete forgets beneficiary, transfers already paid to it keep its id
+func (s *BeneficiaryUseCase) Delete(customerID string, beneficiaryID string) error {
+	_, err := findBeneficiary(s.repo, customerID, beneficiaryID)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(beneficiaryID)
+}
+
+func findBeneficiary(
+	repo domain.BeneficiaryRepository,
+	customerID string,
+	beneficiaryID string,
+) (*domain.Beneficiary, error) {
+	payee, err := repo.FindByID(beneficiaryID)
+	if err != nil {
+		return nil, err
+	}
+	if payee == nil || payee.CustomerID != customerID {
+		return nil, domain.NewNotFoundError("beneficiary with such id not found")
+	}
+	return payee, nil
+}
+
+// checkPayeeDetails applies cooling-off policy to transfer of customer to payee details passed directly instead
+// of beneficiary id, so that payee added with stolen credentials could not be paid by its details. Large transfer
+// is allowed only when customer saved the details as beneficiary and its cooling-off period is over. Payees are
+// details identifying the same recipient, the earliest trusted of their beneficiaries is taken.
+func checkPayeeDetails(
+	repo domain.BeneficiaryRepository,
+	policy beneficiary.CoolingOffPolicy,
+	customerID string,
+	amount int64,
+	currency string,
+	payees ...*domain.Beneficiary,
+) error {
+	if amount <= policy.Limits[currency] {
+		return nil
+	}
+	var saved *domain.Beneficiary
+	for _, payee := range payees {
+		payeeKey, err := beneficiary.PayeeKey(payee)
+		if err != nil {
+			return err
+		}
+		found, err := repo.FindByPayeeKey(customerID, payeeKey)
+		if err != nil {
+			return err
+		}
+		if found != nil && (saved == nil || found.TrustedAt.Before(saved.TrustedAt)) {
+			saved = found
+		}
+	}
+	err := policy.CheckPayee(saved, amount, currency, time.Now())
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+	return nil
+}
+
+// payableBeneficiary finds beneficiary of customer which could be paid amount by transfer of one of types
+func payableBeneficiary(
+	repo domain.BeneficiaryRepository,
+	policy beneficiary.CoolingOffPolicy,
+	customerID string,
+	beneficiaryID string,
+	amount int64,
+	currency string,
+	types ...domain.BeneficiaryType,
+) (*domain.Beneficiary, error) {
+	payee, err := findBeneficiary(repo, customerID, beneficiaryID)
+	if err != nil {
+		return nil, err
+	}
+	supported := false
+	for _, payeeType := range types {
+		supported = supported || payee.Type == payeeType
+	}
+	if !supported {
+		return nil, domain.NewValidationError(fmt.Sprintf("%s beneficiary could not be paid this way", payee.Type))
+	}
+	err = policy.CheckTransfer(payee, amount, currency, time.Now())
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+	return payee, nil
+}
@@ -0,0 +1,427 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/issuing"
+)
+
+// cardIssueAttempts is a number of PANs generated for card until one is not issued yet
+const cardIssueAttempts = 3
+
+// capture and refund of capture events of card authorization postings
+const (
+	cardCaptureEvent = iota
+	cardRefundEvent
+)
+
+type CardUseCase struct {
+	repo          domain.CardRepository
+	customerRepo  domain.CustomerRepository
+	vault         domain.CardVault
+	verifications *VerificationUseCase
+	limits        *LimitUseCase
+	risks         *RiskUseCase
+	bin           string
+}
+
+func NewCardUseCase(
+	repo domain.CardRepository,
+	customerRepo domain.CustomerRepository,
+	vault domain.CardVault,
+	verifications *VerificationUseCase,
+	limits *LimitUseCase,
+	risks *RiskUseCase,
+	bin string,
+) *CardUseCase {
+	return &CardUseCase{
+		repo:          repo,
+		customerRepo:  customerRepo,
+		vault:         vault,
+		verifications: verifications,
+		limits:        limits,
+		risks:         risks,
+		bin:           bin,
+	}
+}
+
+// Issue generates PAN of configured BIN, CVV and expiry of virtual card of customer. Secrets are put in vault
+// before card is saved and are deleted when card could not be saved.
+func (s *CardUseCase) Issue(card *domain.Card) error {
+	customer, err := s.customerRepo.FindByID(card.CustomerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.NewNotFoundError("customer with such id not found")
+	}
+	if customer.Status != domain.CustomerStatusActive {
+		return domain.NewValidationError(fmt.Sprintf("customer is %s", customer.Status))
+	}
+	err = issuing.ValidateControls(card.Controls)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	for attempt := 0; attempt < cardIssueAttempts; attempt++ {
+		now := time.Now()
+		secrets := &domain.CardSecrets{}
+		secrets.PAN, err = issuing.GeneratePAN(s.bin)
+		if err != nil {
+			return err
+		}
+		secrets.CVV, err = issuing.GenerateCVV()
+		if err != nil {
+			return err
+		}
+		card.GeneratedID, err = hash.GenerateUniqueCardID(card.CustomerID, now.UnixNano())
+		if err != nil {
+			return err
+		}
+		card.MaskedPAN = issuing.Mask(secrets.PAN)
+		card.PANFingerprint = s.vault.Fingerprint(secrets.PAN)
+		card.ExpiryMonth, card.ExpiryYear = issuing.Expiry(now)
+		card.Status = domain.CardStatusActive
+		card.CreatedAt = now
+		card.UpdatedAt = now
+
+		err = s.vault.Put(card.GeneratedID, secrets)
+		if err != nil {
+			return err
+		}
+		var created bool
+		created, err = s.repo.Create(card)
+		if err == nil && created {
+			return nil
+		}
+		deleteErr := s.vault.Delete(card.GeneratedID)
+		if err != nil {
+			return err
+		}
+		if deleteErr != nil {
+			return deleteErr
+		}
+	}
+	return fmt.Errorf("unable to generate PAN which is not issued yet in %d attempts", cardIssueAttempts)
+}
+
+func (s *CardUseCase) Find(cardID string) (*domain.Card, error) {
+	card, err := s.repo.FindByID(cardID)
+	if err != nil {
+		return nil, err
+	}
+	if card == nil {
+		return nil, domain.NewNotFoundError("card with such id not found")
+	}
+	return card, nil
+}
+
+func (s *CardUseCase) FindByCustomer(customerID string) ([]*domain.Card, error) {
+	cards, err := s.repo.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
+
+// FindSecrets reads PAN and CVV of card from vault, secrets of terminated card are deleted
+func (s *CardUseCase) FindSecrets(cardID string) (*domain.Card, *domain.CardSecrets, error) {
+	card, err := s.Find(cardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if card.Status == domain.CardStatusTerminated {
+		return nil, nil, domain.NewValidationError("card is terminated")
+	}
+	secrets, err := s.vault.Get(card.GeneratedID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if secrets == nil {
+		return nil, nil, domain.NewNotFoundError("secrets of card not found")
+	}
+	return card, secrets, nil
+}
+
+// SetStatus freezes active card or activates frozen one. Termination is final, secrets of terminated card
+// are deleted from vault, terminating card again retries deletion.
+func (s *CardUseCase) SetStatus(cardID string, status domain.CardStatus) (*domain.Card, error) {
+	switch status {
+	case domain.CardStatusActive, domain.CardStatusFrozen, domain.CardStatusTerminated:
+	default:
+		return nil, domain.NewValidationError(fmt.Sprintf("unknown card status %s", status))
+	}
+	card, err := s.update(cardID, func(card *domain.Card, now time.Time) error {
+		if card.Status == domain.CardStatusTerminated && status != domain.CardStatusTerminated {
+			return domain.NewValidationError("terminated card could not be reactivated")
+		}
+		card.Status = status
+		card.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if card.Status == domain.CardStatusTerminated {
+		err = s.vault.Delete(card.GeneratedID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return card, nil
+}
+
+// SetControls replaces spending controls of card, they apply to authorizations requested afterwards
+func (s *CardUseCase) SetControls(cardID string, controls domain.CardControls) (*domain.Card, error) {
+	err := issuing.ValidateControls(controls)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+	return s.update(cardID, func(card *domain.Card, now time.Time) error {
+		if card.Status == domain.CardStatusTerminated {
+			return domain.NewValidationError("card is terminated")
+		}
+		card.Controls = controls
+		card.UpdatedAt = now
+		return nil
+	})
+}
+
+func (s *CardUseCase) FindAuthorizations(cardID string) ([]*domain.CardAuthorization, error) {
+	_, err := s.Find(cardID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindAuthorizationsByCardID(cardID)
+}
+
+// Authorize decides on authorization request of card network. Declined authorizations are saved as well,
+// approved authorization holds its amount on customer balance until it is captured or reversed and is charged
+// to limits of customer in card currency. Customers who are not allowed to move money are declined. Request is
+// scored by risk assessment before decision, declined assessment declines authorization. Request repeated
+// with the same network reference gets the decision made before.
+func (s *CardUseCase) Authorize(request *domain.CardAuthorizationRequest) (*domain.CardAuthorization, error) {
+	if request.Amount <= 0 {
+		return nil, domain.NewValidationError("amount should be positive")
+	}
+	if request.NetworkReference != "" {
+		authorization, err := s.repo.FindAuthorizationByNetworkReference(request.NetworkReference)
+		if err != nil {
+			return nil, err
+		}
+		if authorization != nil {
+			return authorization, nil
+		}
+	}
+	card, err := s.repo.FindByFingerprint(s.vault.Fingerprint(request.PAN))
+	if err != nil {
+		return nil, err
+	}
+	if card == nil {
+		return nil, domain.NewNotFoundError("card with such PAN not found")
+	}
+	var secrets *domain.CardSecrets
+	if request.CVV != "" {
+		secrets, err = s.vault.Get(card.GeneratedID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	restricted := false
+	err = s.verifications.EnsureCanMoveMoney(card.CustomerID)
+	if _, ok := err.(*domain.ValidationError); ok {
+		restricted = true
+	} else if err != nil {
+		return nil, err
+	}
+	customerLimits, err := s.limits.limits(card.CustomerID, card.Currency)
+	if err != nil {
+		return nil, err
+	}
+	// card networks do not report device of cardholder, so card is scored as a device it is paid with
+	assessment, err := s.risks.Assess(card.CustomerID, request.Amount, request.Currency, "card:"+card.GeneratedID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	authorizationID, err := hash.GenerateUniqueCardAuthorizationID(card.GeneratedID, now.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	authorization, err := s.repo.Authorize(
+		card.GeneratedID,
+		now,
+		businessday.Day(now),
+		func(
+			card *domain.Card,
+			balance *domain.AvailableBalance,
+			spent int64,
+			used domain.LimitUsage,
+		) (*domain.CardAuthorization, error) {
+			authorization := &domain.CardAuthorization{
+				GeneratedID:      authorizationID,
+				CardID:           card.GeneratedID,
+				CustomerID:       card.CustomerID,
+				Amount:           request.Amount,
+				Currency:         request.Currency,
+				MCC:              request.MCC,
+				MerchantName:     request.MerchantName,
+				Status:           domain.CardAuthorizationStatusApproved,
+				NetworkReference: request.NetworkReference,
+				RiskAssessmentID: assessment.GeneratedID,
+				RiskDecision:     assessment.Decision,
+				CreatedAt:        now,
+				UpdatedAt:        now,
+			}
+			authorization.DeclineReason = issuing.Decide(card, secrets, request, balance, spent, now)
+			switch {
+			case authorization.DeclineReason != "":
+			case restricted:
+				authorization.DeclineReason = domain.CardDeclineReasonCustomerRestricted
+			case assessment.Decision == domain.RiskDecisionDecline:
+				authorization.DeclineReason = domain.CardDeclineReasonRisk
+			case customerLimits.Limits.Check(used, request.Amount) != nil:
+				authorization.DeclineReason = domain.CardDeclineReasonCustomerLimit
+			}
+			if authorization.DeclineReason != "" {
+				authorization.Status = domain.CardAuthorizationStatusDeclined
+			}
+			return authorization, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if authorization == nil {
+		return nil, domain.NewNotFoundError("card with such PAN not found")
+	}
+	return authorization, nil
+}
+
+// Capture debits customer balance with amount of approved authorization and releases its hold
+func (s *CardUseCase) Capture(authorizationID string) (*domain.CardAuthorization, error) {
+	return s.updateAuthorization(
+		authorizationID,
+		func(authorization *domain.CardAuthorization, now time.Time) ([]*domain.Posting, error) {
+			if authorization.Status != domain.CardAuthorizationStatusApproved {
+				return nil, domain.NewValidationError(
+					fmt.Sprintf("%s authorization could not be captured", authorization.Status),
+				)
+			}
+			authorization.Status = domain.CardAuthorizationStatusCaptured
+			authorization.UpdatedAt = now
+			return cardAuthorizationPostings(
+				authorization,
+				cardCaptureEvent,
+				authorization.CustomerID,
+				domain.LedgerAccountCardSettlement,
+				now,
+			)
+		},
+	)
+}
+
+// Reverse releases hold of approved authorization or refunds captured one
+func (s *CardUseCase) Reverse(authorizationID string) (*domain.CardAuthorization, error) {
+	return s.updateAuthorization(
+		authorizationID,
+		func(authorization *domain.CardAuthorization, now time.Time) ([]*domain.Posting, error) {
+			status := authorization.Status
+			if status != domain.CardAuthorizationStatusApproved && status != domain.CardAuthorizationStatusCaptured {
+				return nil, domain.NewValidationError(fmt.Sprintf("%s authorization could not be reversed", status))
+			}
+			authorization.Status = domain.CardAuthorizationStatusReversed
+			authorization.UpdatedAt = now
+			if status == domain.CardAuthorizationStatusApproved {
+				return nil, nil
+			}
+			return cardAuthorizationPostings(
+				authorization,
+				cardRefundEvent,
+				domain.LedgerAccountCardSettlement,
+				authorization.CustomerID,
+				now,
+			)
+		},
+	)
+}
+
+// ReverseByNetworkReference reverses authorization requested with network reference. Reversal is repeated by
+// card network until it is answered, so authorization which is reversed or declined already is left as it is.
+func (s *CardUseCase) ReverseByNetworkReference(reference string) (*domain.CardAuthorization, error) {
+	authorization, err := s.repo.FindAuthorizationByNetworkReference(reference)
+	if err != nil {
+		return nil, err
+	}
+	if authorization == nil {
+		return nil, domain.NewNotFoundError("authorization with such network reference not found")
+	}
+	if authorization.Status == domain.CardAuthorizationStatusReversed ||
+		authorization.Status == domain.CardAuthorizationStatusDeclined {
+		return authorization, nil
+	}
+	return s.Reverse(authorization.GeneratedID)
+}
+
+func (s *CardUseCase) update(
+	cardID string,
+	update func(card *domain.Card, now time.Time) error,
+) (*domain.Card, error) {
+	now := time.Now()
+	card, err := s.repo.Update(cardID, func(card *domain.Card) error {
+		return update(card, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if card == nil {
+		return nil, domain.NewNotFoundError("card with such id not found")
+	}
+	return card, nil
+}
+
+func (s *CardUseCase) updateAuthorization(
+	authorizationID string,
+	update func(authorization *domain.CardAuthorization, now time.Time) ([]*domain.Posting, error),
+) (*domain.CardAuthorization, error) {
+	now := time.Now()
+	authorization, err := s.repo.UpdateAuthorization(
+		authorizationID,
+		func(authorization *domain.CardAuthorization) ([]*domain.Posting, error) {
+			return update(authorization, now)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if authorization == nil {
+		return nil, domain.NewNotFoundError("authorization with such id not found")
+	}
+	return authorization, nil
+}
+
+// cardAuthorizationPostings move amount of authorization between customer and card settlement account, events
+// of capture and refund of capture get ids of their own so that authorization is never captured or refunded twice
+func cardAuthorizationPostings(
+	authorization *domain.CardAuthorization,
+	event int,
+	payerID string,
+	payeeID string,
+	now time.Time,
+) ([]*domain.Posting, error) {
+	return transferPostings(
+		"card-authorization:"+authorization.GeneratedID,
+		event,
+		payerID,
+		payeeID,
+		authorization.Amount,
+		authorization.Currency,
+		"Card authorization "+authorization.GeneratedID,
+		now,
+	)
+}
@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/overdraft"
+)
+
+type CreditLineUseCase struct {
+	repo         domain.CreditLineRepository
+	customerRepo domain.CustomerRepository
+}
+
+func NewCreditLineUseCase(repo domain.CreditLineRepository, customerRepo domain.CustomerRepository) *CreditLineUseCase {
+	return &CreditLineUseCase{repo: repo, customerRepo: customerRepo}
+}
+
+// Approve sets credit limit and rate of customer in currency. Limit lower than the one in use is allowed,
+// customer could not debit anything until balance is back within new limit. Interest accrued already is kept,
+// days which are not accrued yet are accrued at new rate.
+func (s *CreditLineUseCase) Approve(line *domain.CreditLine) error {
+	customer, err := s.customerRepo.FindByID(line.CustomerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.NewNotFoundError("customer with such id not found")
+	}
+	if customer.Status != domain.CustomerStatusActive {
+		return domain.NewValidationError(fmt.Sprintf("customer is %s", customer.Status))
+	}
+	err = overdraft.ValidateLine(line)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	now := time.Now()
+	line.GeneratedID, err = hash.GenerateUniqueCreditLineID(line.CustomerID, line.Currency)
+	if err != nil {
+		return err
+	}
+	line.AccruedInterest = 0
+	line.ChargedInterest = 0
+	line.Charges = 0
+	line.AccruedUntil = businessday.Day(now)
+	line.CreatedAt = now
+	line.UpdatedAt = now
+	return s.repo.Save(line)
+}
+
+// Find reports utilisation of credit line of customer in currency
+func (s *CreditLineUseCase) Find(customerID string, currency string) (*domain.CreditLineUtilisation, error) {
+	line, err := s.repo.Find(customerID, currency)
+	if err != nil {
+		return nil, err
+	}
+	if line == nil {
+		return nil, domain.NewNotFoundError("credit line of customer in such currency not found")
+	}
+	return s.utilise(line)
+}
+
+// FindByCustomer reports utilisation of all credit lines of customer
+func (s *CreditLineUseCase) FindByCustomer(customerID string) ([]*domain.CreditLineUtilisation, error) {
+	lines, err := s.repo.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	utilisations := make([]*domain.CreditLineUtilisation, 0, len(lines))
+	for _, line := range lines {
+		utilisation, err := s.utilise(line)
+		if err != nil {
+			return nil, err
+		}
+		utilisations = append(utilisations, utilisation)
+	}
+	return utilisations, nil
+}
+
+// AccrueInterest accrues interest on negative balances of credit lines for days before today and charges
+// interest of the past month from customer balance to interest income. Charge is debited even beyond limit.
+func (s *CreditLineUseCase) AccrueInterest(now time.Time, limit int) (int, error) {
+	return s.repo.ClaimDue(
+		businessday.Day(now),
+		limit,
+		func(line *domain.CreditLine, closing []*domain.ClosingBalance) ([]*domain.Posting, error) {
+			charge, err := overdraft.Accrue(line, closing)
+			if err != nil {
+				return nil, err
+			}
+			line.UpdatedAt = now
+			if charge == 0 {
+				return nil, nil
+			}
+			return transferPostings(
+				"credit-line:"+line.GeneratedID,
+				line.Charges,
+				line.CustomerID,
+				domain.LedgerAccountInterestIncome,
+				charge,
+				line.Currency,
+				"Credit line "+line.GeneratedID+" interest",
+				now,
+			)
+		},
+	)
+}
+
+func (s *CreditLineUseCase) utilise(line *domain.CreditLine) (*domain.CreditLineUtilisation, error) {
+	balance, err := s.repo.FindAvailableBalance(line.CustomerID, line.Currency)
+	if err != nil {
+		return nil, err
+	}
+	return overdraft.Utilise(line, balance), nil
+}
@@ -1,44 +1,87 @@
 package usecase
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/yaroslavnayug/go-payment-system/internal/domain"
 	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/legalentity"
+	"github.com/yaroslavnayug/go-payment-system/internal/phone"
+	"github.com/yaroslavnayug/go-payment-system/internal/screening"
 )
 
 type CustomerUseCase struct {
-	repo domain.CustomerRepository
+	repo         domain.CustomerRepository
+	sanctionRepo domain.SanctionRepository
+	matcher      *screening.Matcher
 }
 
-func NewCustomerUseCase(repo domain.CustomerRepository) *CustomerUseCase {
-	return &CustomerUseCase{repo: repo}
+func NewCustomerUseCase(
+	repo domain.CustomerRepository,
+	sanctionRepo domain.SanctionRepository,
+	matcher *screening.Matcher,
+) *CustomerUseCase {
+	return &CustomerUseCase{repo: repo, sanctionRepo: sanctionRepo, matcher: matcher}
 }
 
 func (c *CustomerUseCase) Create(customer *domain.Customer) error {
-	customerExist, err := c.repo.FindByPassportNumber(customer.Passport.Number)
+	if customer.Type == "" {
+		customer.Type = domain.CustomerTypeIndividual
+	}
+	var customerExist *domain.Customer
+	var err error
+	if customer.Type == domain.CustomerTypeLegalEntity {
+		customerExist, err = c.repo.FindByOGRN(customer.Company.OGRN)
+		if err != nil {
+			return err
+		}
+		if customerExist != nil {
+			return domain.NewValidationError("customer with such ogrn already exist")
+		}
+	} else {
+		customerExist, err = c.repo.FindByPassportNumber(customer.Passport.Number)
+		if err != nil {
+			return err
+		}
+		if customerExist != nil {
+			return domain.NewValidationError("customer with such passport number already exist")
+		}
+	}
+	customerExist, err = c.repo.FindByPhone(customer.Phone)
 	if err != nil {
 		return err
 	}
 	if customerExist != nil {
-		return domain.NewValidationError("customer with such passport number already exist")
+		return domain.NewValidationError("customer with such phone already exist")
+	}
+	err = c.checkOfficers(customer)
+	if err != nil {
+		return err
 	}
 
-	uniqueCustomerID, err := hash.GenerateUniqueCustomerID(
-		customer.FirstName,
-		customer.Passport.Number,
-		time.Now().Unix(),
-	)
+	idName, idDocument := customer.FirstName, customer.Passport.Number
+	if customer.Type == domain.CustomerTypeLegalEntity {
+		idName, idDocument = customer.Company.Name, customer.Company.OGRN
+	}
+	uniqueCustomerID, err := hash.GenerateUniqueCustomerID(idName, idDocument, time.Now().Unix())
 	if err != nil {
 		return err
 	}
 
 	customer.GeneratedID = uniqueCustomerID
+	customer.Status = domain.CustomerStatusActive
+	customer.CreatedAt = time.Now()
+	matches, err := c.screen(customer)
+	if err != nil {
+		return err
+	}
+
 	err = c.repo.Create(customer)
 	if err != nil {
 		return err
 	}
-	return nil
+	return c.saveMatches(matches)
 }
 
 func (c *CustomerUseCase) Find(customerID string) (*domain.Customer, error) {
@@ -57,11 +100,38 @@ func (c *CustomerUseCase) Update(customer *domain.Customer, customerID string) e
 	if existingCustomer == nil {
 		return domain.NewValidationError("customer with such id not found")
 	}
+	if isLegalEntity(existingCustomer) != isLegalEntity(customer) {
+		return domain.NewValidationError("customer type could not be changed")
+	}
+	customerWithPhone, err := c.repo.FindByPhone(customer.Phone)
+	if err != nil {
+		return err
+	}
+	if customerWithPhone != nil && customerWithPhone.GeneratedID != customerID {
+		return domain.NewValidationError("customer with such phone already exist")
+	}
+	if isLegalEntity(customer) && customer.Company.OGRN != existingCustomer.Company.OGRN {
+		return domain.NewValidationError("ogrn of customer could not be changed")
+	}
+
+	customer.GeneratedID = customerID
+	customer.Type = existingCustomer.Type
+	customer.Status = existingCustomer.Status
+	customer.CreatedAt = existingCustomer.CreatedAt
+	err = c.checkOfficers(customer)
+	if err != nil {
+		return err
+	}
+	matches, err := c.screen(customer)
+	if err != nil {
+		return err
+	}
+
 	err = c.repo.Update(customer)
 	if err != nil {
 		return err
 	}
-	return nil
+	return c.saveMatches(matches)
 }
 
 func (c *CustomerUseCase) Delete(customerID string) error {
@@ -71,3 +141,125 @@ func (c *CustomerUseCase) Delete(customerID string) error {
 	}
 	return nil
 }
+
+// PhoneNormalizationReport lists customers whose phones could not be normalized
+type PhoneNormalizationReport struct {
+	Updated int
+	// Invalid are ids of customers with phones which are not valid numbers
+	Invalid []string
+	// Duplicated are ids of customers with phones which are the same as phone of another customer after normalization
+	Duplicated []string
+}
+
+// NormalizePhones rewrites phones of all customers in E.164 format. Phones which could not be normalized
+// are left as is and reported, they should be fixed manually before unique index on phone is created.
+func (c *CustomerUseCase) NormalizePhones() (*PhoneNormalizationReport, error) {
+	report := &PhoneNormalizationReport{}
+	owners := map[string]bool{}
+	statuses := []domain.CustomerStatus{
+		domain.CustomerStatusActive,
+		domain.CustomerStatusPendingReview,
+		domain.CustomerStatusBlocked,
+	}
+	for _, status := range statuses {
+		customers, err := c.repo.FindByStatus(status)
+		if err != nil {
+			return nil, err
+		}
+		for _, customer := range customers {
+			normalized, err := phone.Normalize(customer.Phone)
+			if err != nil {
+				report.Invalid = append(report.Invalid, customer.GeneratedID)
+				continue
+			}
+			if owners[normalized] {
+				report.Duplicated = append(report.Duplicated, customer.GeneratedID)
+				continue
+			}
+			owners[normalized] = true
+			if normalized == customer.Phone {
+				continue
+			}
+			err = c.repo.UpdatePhone(customer.GeneratedID, normalized)
+			if err != nil {
+				return nil, err
+			}
+			report.Updated++
+		}
+	}
+	return report, nil
+}
+
+// screen matches customer against sanctions and PEP lists and puts active customer on manual review
+// when there are new matches. Matches which were already stored for customer are not reported again.
+func (c *CustomerUseCase) screen(customer *domain.Customer) ([]domain.SanctionMatch, error) {
+	candidates, err := c.sanctionRepo.FindCandidates(customer.Passport.BirthDate)
+	if err != nil {
+		return nil, err
+	}
+	matches := c.matcher.Match(customer, candidates)
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	existingMatches, err := c.sanctionRepo.FindMatchesByCustomerID(customer.GeneratedID)
+	if err != nil {
+		return nil, err
+	}
+	var newMatches []domain.SanctionMatch
+	for _, match := range matches {
+		if !containsMatch(existingMatches, match) {
+			match.CreatedAt = time.Now()
+			newMatches = append(newMatches, match)
+		}
+	}
+
+	if len(newMatches) > 0 && customer.Status == domain.CustomerStatusActive {
+		customer.Status = domain.CustomerStatusPendingReview
+	}
+	return newMatches, nil
+}
+
+// checkOfficers verifies that beneficial owners and representatives of legal entity are existing individual
+// customers, companies could not be officers of another company
+func (c *CustomerUseCase) checkOfficers(customer *domain.Customer) error {
+	if !isLegalEntity(customer) {
+		return nil
+	}
+	for _, officerID := range legalentity.OfficerIDs(customer.Company) {
+		if officerID == customer.GeneratedID {
+			return domain.NewValidationError("company could not be its own officer")
+		}
+		officer, err := c.repo.FindByID(officerID)
+		if err != nil {
+			return err
+		}
+		if officer == nil {
+			return domain.NewValidationError(fmt.Sprintf("officer %s not found", officerID))
+		}
+		if isLegalEntity(officer) {
+			return domain.NewValidationError(fmt.Sprintf("officer %s should be individual customer", officerID))
+		}
+	}
+	return nil
+}
+
+func isLegalEntity(customer *domain.Customer) bool {
+	return customer.Type == domain.CustomerTypeLegalEntity
+}
+
+func (c *CustomerUseCase) saveMatches(matches []domain.SanctionMatch) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	return c.sanctionRepo.CreateMatches(matches)
+}
+
+func containsMatch(matches []domain.SanctionMatch, match domain.SanctionMatch) bool {
+	for _, existingMatch := range matches {
+		if existingMatch.ListName == match.ListName && existingMatch.EntryName == match.EntryName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,400 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/deposit"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+)
+
+// DefaultProjectionMonths is a period of projection of on demand deposit when period is not given
+const DefaultProjectionMonths = 12
+
+// events of deposit postings, principal and interest are paid out and penalty is charged on closing
+const (
+	depositOpeningEvent = iota
+	depositPrincipalEvent
+	depositInterestEvent
+	depositPenaltyEvent
+)
+
+type DepositUseCase struct {
+	repo          domain.DepositRepository
+	customerRepo  domain.CustomerRepository
+	verifications *VerificationUseCase
+}
+
+func NewDepositUseCase(
+	repo domain.DepositRepository,
+	customerRepo domain.CustomerRepository,
+	verifications *VerificationUseCase,
+) *DepositUseCase {
+	return &DepositUseCase{repo: repo, customerRepo: customerRepo, verifications: verifications}
+}
+
+func (s *DepositUseCase) CreateProduct(product *domain.DepositProduct) error {
+	err := deposit.ValidateProduct(product)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	now := time.Now()
+	product.GeneratedID, err = hash.GenerateUniqueDepositProductID(product.Name, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	product.CreatedAt = now
+	return s.repo.CreateProduct(product)
+}
+
+func (s *DepositUseCase) FindProduct(productID string) (*domain.DepositProduct, error) {
+	product, err := s.repo.FindProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, domain.NewNotFoundError("deposit product with such id not found")
+	}
+	return product, nil
+}
+
+func (s *DepositUseCase) FindProducts() ([]*domain.DepositProduct, error) {
+	products, err := s.repo.FindProducts()
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// Open moves principal from customer balance to a new deposit, interest is accrued starting from today
+func (s *DepositUseCase) Open(newDeposit *domain.Deposit) error {
+	customer, err := s.customerRepo.FindByID(newDeposit.CustomerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.NewNotFoundError("customer with such id not found")
+	}
+	if customer.Status != domain.CustomerStatusActive {
+		return domain.NewValidationError(fmt.Sprintf("customer is %s", customer.Status))
+	}
+	err = s.verifications.EnsureCanMoveMoney(newDeposit.CustomerID)
+	if err != nil {
+		return err
+	}
+	product, err := s.FindProduct(newDeposit.ProductID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = deposit.Open(newDeposit, product, now)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+	newDeposit.GeneratedID, err = hash.GenerateUniqueDepositID(newDeposit.CustomerID, product.GeneratedID, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	newDeposit.OpenedAt = now
+	newDeposit.UpdatedAt = now
+
+	postings, err := depositPostings(
+		newDeposit,
+		depositOpeningEvent,
+		newDeposit.CustomerID,
+		domain.LedgerAccountDeposits,
+		newDeposit.Principal,
+		"opening",
+		now,
+	)
+	if err != nil {
+		return err
+	}
+	created, err := s.repo.Create(newDeposit, postings)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return domain.NewValidationError("insufficient funds on customer balance")
+	}
+	return nil
+}
+
+func (s *DepositUseCase) Find(depositID string) (*domain.Deposit, error) {
+	found, err := s.repo.FindByID(depositID)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, domain.NewNotFoundError("deposit with such id not found")
+	}
+	return found, nil
+}
+
+func (s *DepositUseCase) FindByCustomer(customerID string) ([]*domain.Deposit, error) {
+	deposits, err := s.repo.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return deposits, nil
+}
+
+func (s *DepositUseCase) FindInterestEntries(depositID string) ([]*domain.DepositInterestEntry, error) {
+	_, err := s.Find(depositID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindInterestEntries(depositID)
+}
+
+// Close pays principal with interest accrued till today to customer balance. Term deposit closed before
+// maturity forfeits early withdrawal penalty of its product.
+func (s *DepositUseCase) Close(depositID string, customerID string) (*domain.Deposit, error) {
+	found, err := s.Find(depositID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.FindProduct(found.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	closed, err := s.repo.Update(
+		depositID,
+		func(closing *domain.Deposit) ([]*domain.DepositInterestEntry, []*domain.Posting, error) {
+			if customerID != closing.CustomerID {
+				return nil, nil, domain.NewValidationError("deposit is closed by its customer")
+			}
+			if closing.Status == domain.DepositStatusClosed {
+				return nil, nil, domain.NewValidationError("deposit is already closed")
+			}
+			entries, err := deposit.Accrue(closing, product, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			penalty, err := deposit.Penalty(closing, product)
+			if err != nil {
+				return nil, nil, err
+			}
+			if penalty > 0 {
+				entries = append(entries, &domain.DepositInterestEntry{
+					DepositID: closing.GeneratedID,
+					Type:      domain.DepositInterestEntryTypePenalty,
+					Amount:    penalty,
+					Day:       businessday.Day(now),
+				})
+			}
+
+			closing.Status = domain.DepositStatusClosed
+			closing.PaidOut = closing.Principal + closing.AccruedInterest - penalty
+			closing.ClosedAt = now
+			closing.UpdatedAt = now
+			postings, err := depositInterestPostings(closing, entries, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			closingPostings, err := depositClosingPostings(closing, penalty, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			return entries, append(postings, closingPostings...), nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if closed == nil {
+		return nil, domain.NewNotFoundError("deposit with such id not found")
+	}
+	return closed, nil
+}
+
+// AccrueInterest accrues interest of open deposits for days before today and capitalises it, accrued interest
+// is posted to accrued interest account and capitalised interest is moved from there to deposits account
+func (s *DepositUseCase) AccrueInterest(now time.Time, limit int) (int, error) {
+	products, err := s.repo.FindProducts()
+	if err != nil {
+		return 0, err
+	}
+	productsByID := make(map[string]*domain.DepositProduct, len(products))
+	for _, product := range products {
+		productsByID[product.GeneratedID] = product
+	}
+
+	return s.repo.ClaimDue(
+		businessday.Day(now),
+		limit,
+		func(due *domain.Deposit) ([]*domain.DepositInterestEntry, []*domain.Posting, error) {
+			product, ok := productsByID[due.ProductID]
+			if !ok {
+				return nil, nil, fmt.Errorf("deposit %s has unknown product %s", due.GeneratedID, due.ProductID)
+			}
+			due.UpdatedAt = now
+			entries, err := deposit.Accrue(due, product, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			postings, err := depositInterestPostings(due, entries, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			return entries, postings, nil
+		},
+	)
+}
+
+// Project shows interest expected for deposit till maturity of term deposit or for months of on demand deposit
+func (s *DepositUseCase) Project(depositID string, months int) (*domain.DepositProjection, error) {
+	found, err := s.Find(depositID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.FindProduct(found.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	return deposit.Project(*found, product, projectionEnd(found, months))
+}
+
+// ProjectProduct shows interest expected for deposit of product opened today with amount
+func (s *DepositUseCase) ProjectProduct(productID string, amount int64, months int) (*domain.DepositProjection, error) {
+	product, err := s.FindProduct(productID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	quote := domain.Deposit{Principal: amount, OpenedAt: now}
+	err = deposit.Open(&quote, product, now)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+	return deposit.Project(quote, product, projectionEnd(&quote, months))
+}
+
+func projectionEnd(projected *domain.Deposit, months int) time.Time {
+	if !projected.MaturesAt.IsZero() && months == 0 {
+		return projected.MaturesAt
+	}
+	if months == 0 {
+		months = DefaultProjectionMonths
+	}
+	return businessday.Day(projected.OpenedAt).AddDate(0, months, 0)
+}
+
+// depositPostings move amount between customer of deposit and ledger accounts within event of deposit
+func depositPostings(
+	opened *domain.Deposit,
+	event int,
+	payerID string,
+	payeeID string,
+	amount int64,
+	entry string,
+	now time.Time,
+) ([]*domain.Posting, error) {
+	return transferPostings(
+		"deposit:"+opened.GeneratedID,
+		event,
+		payerID,
+		payeeID,
+		amount,
+		opened.Currency,
+		"Deposit "+opened.GeneratedID+" "+entry,
+		now,
+	)
+}
+
+// depositClosingPostings pay principal and accrued interest of closed deposit out to customer
+// and charge early withdrawal penalty back to interest expense
+func depositClosingPostings(closed *domain.Deposit, penalty int64, now time.Time) ([]*domain.Posting, error) {
+	postings, err := depositPostings(
+		closed,
+		depositPrincipalEvent,
+		domain.LedgerAccountDeposits,
+		closed.CustomerID,
+		closed.Principal,
+		"closing",
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if closed.AccruedInterest > 0 {
+		var interestPostings []*domain.Posting
+		interestPostings, err = depositPostings(
+			closed,
+			depositInterestEvent,
+			domain.LedgerAccountAccruedInterest,
+			closed.CustomerID,
+			closed.AccruedInterest,
+			"interest",
+			now,
+		)
+		if err != nil {
+			return nil, err
+		}
+		postings = append(postings, interestPostings...)
+	}
+	if penalty > 0 {
+		var penaltyPostings []*domain.Posting
+		penaltyPostings, err = depositPostings(
+			closed,
+			depositPenaltyEvent,
+			closed.CustomerID,
+			domain.LedgerAccountInterestExpense,
+			penalty,
+			"early withdrawal penalty",
+			now,
+		)
+		if err != nil {
+			return nil, err
+		}
+		postings = append(postings, penaltyPostings...)
+	}
+	return postings, nil
+}
+
+// depositInterestPostings book interest entries of deposit, every entry under its own reference. Accrual moves
+// interest from interest expense to accrued interest account, capitalisation moves it on to deposits account.
+// Penalty is charged from customer on closing.
+func depositInterestPostings(
+	accrued *domain.Deposit,
+	entries []*domain.DepositInterestEntry,
+	now time.Time,
+) ([]*domain.Posting, error) {
+	var postings []*domain.Posting
+	for _, entry := range entries {
+		var payerID, payeeID string
+		switch entry.Type {
+		case domain.DepositInterestEntryTypeAccrual:
+			payerID, payeeID = domain.LedgerAccountInterestExpense, domain.LedgerAccountAccruedInterest
+		case domain.DepositInterestEntryTypeCapitalisation:
+			payerID, payeeID = domain.LedgerAccountAccruedInterest, domain.LedgerAccountDeposits
+		default:
+			continue
+		}
+		if entry.Amount <= 0 {
+			continue
+		}
+		day := entry.Day.Format(domain.DateFormat)
+		entryPostings, err := transferPostings(
+			fmt.Sprintf("deposit:%s:%s:%s", accrued.GeneratedID, entry.Type, day),
+			0,
+			payerID,
+			payeeID,
+			entry.Amount,
+			accrued.Currency,
+			fmt.Sprintf("Deposit %s %s for %s", accrued.GeneratedID, entry.Type, day),
+			now,
+		)
+		if err != nil {
+			return nil, err
+		}
+		postings = append(postings, entryPostings...)
+	}
+	return postings, nil
+}
@@ -0,0 +1,332 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+)
+
+const (
+	// DisputeWindow is a time after payment when payer could open dispute against it
+	DisputeWindow = 120 * 24 * time.Hour
+	// DisputeResponseTime is given to merchant to submit evidence or to accept dispute, dispute is lost after it
+	DisputeResponseTime = 7 * 24 * time.Hour
+	// MaxDisputeEvidenceSize limits uploaded evidence file to 4 MB, the default request body limit of server
+	MaxDisputeEvidenceSize = 4 << 20
+)
+
+// provisional and final events of dispute postings
+const (
+	disputeProvisionalEvent = iota
+	disputeFinalEvent
+)
+
+type DisputeUseCase struct {
+	repo        domain.DisputeRepository
+	paymentRepo domain.QRPaymentRepository
+	postingRepo domain.PostingRepository
+	blobStore   domain.BlobStore
+}
+
+func NewDisputeUseCase(
+	repo domain.DisputeRepository,
+	paymentRepo domain.QRPaymentRepository,
+	postingRepo domain.PostingRepository,
+	blobStore domain.BlobStore,
+) *DisputeUseCase {
+	return &DisputeUseCase{repo: repo, paymentRepo: paymentRepo, postingRepo: postingRepo, blobStore: blobStore}
+}
+
+// Open disputes payment of payer within DisputeWindow and provisionally moves dispute amount from merchant
+// to disputes account till dispute is resolved.
+// Dispute without amount disputes the whole payment, payment could be disputed once. Only payments which
+// credited merchant in ledger are disputed, so that dispute postings reverse the real payment legs.
+func (s *DisputeUseCase) Open(dispute *domain.Dispute) error {
+	payment, err := s.paymentRepo.FindPaymentByID(dispute.PaymentID)
+	if err != nil {
+		return err
+	}
+	if payment == nil {
+		return domain.NewNotFoundError("payment with such id not found")
+	}
+	if payment.PayerID != dispute.PayerID {
+		return domain.NewValidationError("payment is disputed by payer")
+	}
+	now := time.Now()
+	if now.Sub(payment.PaidAt) > DisputeWindow {
+		return domain.NewValidationError("payment is too old to be disputed")
+	}
+	if dispute.Amount == 0 {
+		dispute.Amount = payment.Amount
+	}
+	if dispute.Amount > payment.Amount {
+		return domain.NewValidationError("amount should not exceed payment amount")
+	}
+	credited, err := s.isMerchantCredited(payment)
+	if err != nil {
+		return err
+	}
+	if !credited {
+		return domain.NewValidationError("payment has no ledger postings to dispute")
+	}
+	existingDispute, err := s.repo.FindByPaymentID(payment.GeneratedID)
+	if err != nil {
+		return err
+	}
+	if existingDispute != nil {
+		return domain.NewValidationError("payment is already disputed")
+	}
+
+	dispute.GeneratedID, err = hash.GenerateUniqueDisputeID(payment.GeneratedID, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	dispute.MerchantID = payment.MerchantID
+	dispute.Currency = payment.Currency
+	dispute.Status = domain.DisputeStatusOpen
+	dispute.RespondBy = now.Add(DisputeResponseTime)
+	dispute.ResolvedAt = time.Time{}
+	dispute.CreatedAt = now
+	dispute.UpdatedAt = now
+
+	postings, err := disputePostings(
+		dispute,
+		disputeProvisionalEvent,
+		dispute.MerchantID,
+		domain.LedgerAccountDisputes,
+		"provisional debit",
+		now,
+	)
+	if err != nil {
+		return err
+	}
+	return s.repo.Create(dispute, postings)
+}
+
+// isMerchantCredited tells whether payment is posted to ledger with credit of merchant
+func (s *DisputeUseCase) isMerchantCredited(payment *domain.QRPayment) (bool, error) {
+	postings, err := s.postingRepo.FindByReference(qrPaymentReference(payment.GeneratedID))
+	if err != nil {
+		return false, err
+	}
+	for _, posting := range postings {
+		if posting.CustomerID == payment.MerchantID && posting.Amount > 0 && posting.Currency == payment.Currency {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *DisputeUseCase) Find(disputeID string) (*domain.Dispute, error) {
+	dispute, err := s.repo.FindByID(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	if dispute == nil {
+		return nil, domain.NewNotFoundError("dispute with such id not found")
+	}
+	return dispute, nil
+}
+
+func (s *DisputeUseCase) FindByMerchant(merchantID string) ([]*domain.Dispute, error) {
+	disputes, err := s.repo.FindByMerchantID(merchantID)
+	if err != nil {
+		return nil, err
+	}
+	return disputes, nil
+}
+
+// AddEvidence saves file uploaded by party of dispute to blob store until dispute is resolved.
+// Merchant could not add evidence to open dispute after its deadline.
+func (s *DisputeUseCase) AddEvidence(evidence *domain.DisputeEvidence, content []byte) error {
+	dispute, err := s.Find(evidence.DisputeID)
+	if err != nil {
+		return err
+	}
+	if evidence.CustomerID != dispute.MerchantID && evidence.CustomerID != dispute.PayerID {
+		return domain.NewValidationError("evidence is added by payer or merchant")
+	}
+	now := time.Now()
+	if dispute.IsResolved() {
+		return domain.NewValidationError(fmt.Sprintf("%s dispute does not accept evidence", dispute.Status))
+	}
+	if dispute.Status == domain.DisputeStatusOpen && !now.Before(dispute.RespondBy) {
+		return domain.NewValidationError("dispute response deadline is over")
+	}
+	if len(content) == 0 {
+		return domain.NewValidationError("evidence file is empty")
+	}
+	if len(content) > MaxDisputeEvidenceSize {
+		return domain.NewValidationError("evidence file should be 4 MB at most")
+	}
+
+	evidence.GeneratedID, err = hash.GenerateUniqueDisputeEvidenceID(dispute.GeneratedID, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	evidence.Size = len(content)
+	evidence.BlobKey = "disputes/" + dispute.GeneratedID + "/" + evidence.GeneratedID
+	evidence.CreatedAt = now
+
+	err = s.blobStore.Put(evidence.BlobKey, content)
+	if err != nil {
+		return err
+	}
+	return s.repo.AddEvidence(evidence)
+}
+
+func (s *DisputeUseCase) FindEvidence(disputeID string) ([]*domain.DisputeEvidence, error) {
+	_, err := s.Find(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindEvidence(disputeID)
+}
+
+// DownloadEvidence reads evidence file of dispute from blob store
+func (s *DisputeUseCase) DownloadEvidence(
+	disputeID string,
+	evidenceID string,
+) (*domain.DisputeEvidence, []byte, error) {
+	evidence, err := s.FindEvidence(disputeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, file := range evidence {
+		if file.GeneratedID != evidenceID {
+			continue
+		}
+		content, err := s.blobStore.Get(file.BlobKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		return file, content, nil
+	}
+	return nil, nil, domain.NewNotFoundError("evidence with such id not found")
+}
+
+// Submit sends evidence of merchant for review before dispute deadline
+func (s *DisputeUseCase) Submit(disputeID string, customerID string) (*domain.Dispute, error) {
+	evidence, err := s.FindEvidence(disputeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.update(disputeID, func(dispute *domain.Dispute, now time.Time) ([]*domain.Posting, error) {
+		if customerID != dispute.MerchantID {
+			return nil, domain.NewValidationError("dispute is submitted by merchant")
+		}
+		if dispute.Status != domain.DisputeStatusOpen {
+			return nil, domain.NewValidationError(fmt.Sprintf("%s dispute could not be submitted", dispute.Status))
+		}
+		if !now.Before(dispute.RespondBy) {
+			return nil, domain.NewValidationError("dispute response deadline is over")
+		}
+		merchantEvidence := 0
+		for _, file := range evidence {
+			if file.CustomerID == dispute.MerchantID {
+				merchantEvidence++
+			}
+		}
+		if merchantEvidence == 0 {
+			return nil, domain.NewValidationError("merchant evidence should be added before submission")
+		}
+		dispute.Status = domain.DisputeStatusUnderReview
+		dispute.UpdatedAt = now
+		return nil, nil
+	})
+}
+
+// Accept returns disputed amount to payer on behalf of merchant
+func (s *DisputeUseCase) Accept(disputeID string, customerID string) (*domain.Dispute, error) {
+	return s.update(disputeID, func(dispute *domain.Dispute, now time.Time) ([]*domain.Posting, error) {
+		if customerID != dispute.MerchantID {
+			return nil, domain.NewValidationError("dispute is accepted by merchant")
+		}
+		return resolveDispute(dispute, domain.DisputeStatusAccepted, now)
+	})
+}
+
+// Resolve records outcome of dispute review, it is won or lost
+func (s *DisputeUseCase) Resolve(disputeID string, outcome domain.DisputeStatus) (*domain.Dispute, error) {
+	if outcome != domain.DisputeStatusWon && outcome != domain.DisputeStatusLost {
+		return nil, domain.NewValidationError("outcome should be one of won, lost")
+	}
+	return s.update(disputeID, func(dispute *domain.Dispute, now time.Time) ([]*domain.Posting, error) {
+		return resolveDispute(dispute, outcome, now)
+	})
+}
+
+// LoseOverdue resolves open disputes which merchants did not respond to before deadline as lost
+func (s *DisputeUseCase) LoseOverdue(now time.Time, limit int) (int, error) {
+	return s.repo.ClaimOverdue(now, limit, func(dispute *domain.Dispute) ([]*domain.Posting, error) {
+		return resolveDispute(dispute, domain.DisputeStatusLost, now)
+	})
+}
+
+func (s *DisputeUseCase) update(
+	disputeID string,
+	update func(dispute *domain.Dispute, now time.Time) ([]*domain.Posting, error),
+) (*domain.Dispute, error) {
+	now := time.Now()
+	dispute, err := s.repo.Update(disputeID, func(dispute *domain.Dispute) ([]*domain.Posting, error) {
+		return update(dispute, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if dispute == nil {
+		return nil, domain.NewNotFoundError("dispute with such id not found")
+	}
+	return dispute, nil
+}
+
+// resolveDispute sets outcome of dispute and releases disputed amount from disputes account: won amount
+// is credited back to merchant, otherwise it is credited to payer
+func resolveDispute(dispute *domain.Dispute, outcome domain.DisputeStatus, now time.Time) ([]*domain.Posting, error) {
+	if !dispute.CanTransitionTo(outcome) {
+		return nil, domain.NewValidationError(fmt.Sprintf("%s dispute could not be %s", dispute.Status, outcome))
+	}
+
+	creditedID := dispute.PayerID
+	if outcome == domain.DisputeStatusWon {
+		creditedID = dispute.MerchantID
+	}
+	postings, err := disputePostings(
+		dispute,
+		disputeFinalEvent,
+		domain.LedgerAccountDisputes,
+		creditedID,
+		string(outcome),
+		now,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dispute.Status = outcome
+	dispute.ResolvedAt = now
+	dispute.UpdatedAt = now
+	return postings, nil
+}
+
+func disputePostings(
+	dispute *domain.Dispute,
+	event int,
+	payerID string,
+	payeeID string,
+	entry string,
+	now time.Time,
+) ([]*domain.Posting, error) {
+	return transferPostings(
+		"dispute:"+dispute.GeneratedID,
+		event,
+		payerID,
+		payeeID,
+		dispute.Amount,
+		dispute.Currency,
+		"Dispute "+dispute.GeneratedID+" "+entry,
+		now,
+	)
+}
@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+)
+
+// DefaultEscrowTTL is a lifetime of escrow created without expiry
+const DefaultEscrowTTL = 14 * 24 * time.Hour
+
+type EscrowUseCase struct {
+	repo         domain.EscrowRepository
+	customerRepo domain.CustomerRepository
+	debits       domain.DebitFlow
+	risks        *RiskUseCase
+}
+
+func NewEscrowUseCase(
+	repo domain.EscrowRepository,
+	customerRepo domain.CustomerRepository,
+	debits domain.DebitFlow,
+	risks *RiskUseCase,
+) *EscrowUseCase {
+	return &EscrowUseCase{repo: repo, customerRepo: customerRepo, debits: debits, risks: risks}
+}
+
+// Create moves funds of buyer for order of merchant to escrow account in the same transaction which saves
+// escrow. Escrow without expiry expires after DefaultEscrowTTL. Order could have one escrow only, so that
+// buyer funds are not held twice on repeated request. Funding is scored by risk assessment with device and IP
+// of buyer and escrow is refused when it is declined.
+func (s *EscrowUseCase) Create(escrow *domain.Escrow) error {
+	for _, customerID := range []string{escrow.MerchantID, escrow.BuyerID, escrow.SellerID} {
+		customer, err := s.customerRepo.FindByID(customerID)
+		if err != nil {
+			return err
+		}
+		if customer == nil {
+			return domain.NewNotFoundError(fmt.Sprintf("customer %s not found", customerID))
+		}
+		if customer.Status != domain.CustomerStatusActive {
+			return domain.NewValidationError(fmt.Sprintf("customer %s is %s", customerID, customer.Status))
+		}
+	}
+	existingEscrow, err := s.repo.FindByOrderID(escrow.MerchantID, escrow.OrderID)
+	if err != nil {
+		return err
+	}
+	if existingEscrow != nil {
+		return domain.NewValidationError("escrow for such order already exist")
+	}
+	assessment, err := s.risks.Authorize(escrow.BuyerID, escrow.Amount, escrow.Currency, escrow.DeviceID, escrow.IP)
+	if err != nil {
+		return err
+	}
+	escrow.RiskAssessmentID = assessment.GeneratedID
+	escrow.RiskDecision = assessment.Decision
+
+	now := time.Now()
+	if escrow.ExpiresAt.IsZero() {
+		escrow.ExpiresAt = now.Add(DefaultEscrowTTL)
+	}
+	if !escrow.ExpiresAt.After(now) {
+		return domain.NewValidationError("expiry should be in the future")
+	}
+	escrow.GeneratedID, err = hash.GenerateUniqueEscrowID(escrow.MerchantID, escrow.OrderID, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	escrow.Status = domain.EscrowStatusHeld
+	escrow.SellerAmount = 0
+	escrow.DisputeReason = ""
+	escrow.SettledAt = time.Time{}
+	escrow.CreatedAt = now
+	escrow.UpdatedAt = now
+
+	debit := &domain.Debit{
+		PayerID:     escrow.BuyerID,
+		PayeeID:     domain.LedgerAccountEscrow,
+		Amount:      escrow.Amount,
+		Currency:    escrow.Currency,
+		Description: "Escrow " + escrow.GeneratedID,
+		Operation:   domain.MovementTypeTransfer,
+		Reference:   "escrow:" + escrow.GeneratedID,
+	}
+	err = s.debits.Prepare(debit)
+	if err != nil {
+		return err
+	}
+	err = s.repo.Create(escrow, debit)
+	if err != nil {
+		return debitError(err)
+	}
+	s.debits.Monitor(debit)
+	return nil
+}
+
+func (s *EscrowUseCase) Find(escrowID string) (*domain.Escrow, error) {
+	escrow, err := s.repo.FindByID(escrowID)
+	if err != nil {
+		return nil, err
+	}
+	if escrow == nil {
+		return nil, domain.NewNotFoundError("escrow with such id not found")
+	}
+	return escrow, nil
+}
+
+func (s *EscrowUseCase) FindByMerchant(merchantID string) ([]*domain.Escrow, error) {
+	escrows, err := s.repo.FindByMerchantID(merchantID)
+	if err != nil {
+		return nil, err
+	}
+	return escrows, nil
+}
+
+// Release pays escrow to seller when customer is a party named by release condition of escrow
+func (s *EscrowUseCase) Release(escrowID string, customerID string) (*domain.Escrow, error) {
+	return s.update(escrowID, func(escrow *domain.Escrow, now time.Time) ([]*domain.Debit, error) {
+		confirmedBy := escrow.BuyerID
+		if escrow.ReleaseCondition == domain.EscrowReleaseConditionMerchantConfirmation {
+			confirmedBy = escrow.MerchantID
+		}
+		if customerID != confirmedBy {
+			return nil, domain.NewValidationError(fmt.Sprintf("escrow is released on %s", escrow.ReleaseCondition))
+		}
+		if escrow.Status != domain.EscrowStatusHeld {
+			return nil, domain.NewValidationError(fmt.Sprintf("%s escrow could not be released", escrow.Status))
+		}
+		return s.settle(escrow, escrow.Amount, now)
+	})
+}
+
+// Refund returns escrow to buyer, it is requested by seller or merchant
+func (s *EscrowUseCase) Refund(escrowID string, customerID string) (*domain.Escrow, error) {
+	return s.update(escrowID, func(escrow *domain.Escrow, now time.Time) ([]*domain.Debit, error) {
+		if customerID != escrow.SellerID && customerID != escrow.MerchantID {
+			return nil, domain.NewValidationError("escrow is refunded by seller or merchant")
+		}
+		if escrow.Status != domain.EscrowStatusHeld {
+			return nil, domain.NewValidationError(fmt.Sprintf("%s escrow could not be refunded", escrow.Status))
+		}
+		return s.settle(escrow, 0, now)
+	})
+}
+
+// Dispute is opened by buyer, disputed escrow does not expire until merchant resolves it
+func (s *EscrowUseCase) Dispute(escrowID string, customerID string, reason string) (*domain.Escrow, error) {
+	return s.update(escrowID, func(escrow *domain.Escrow, now time.Time) ([]*domain.Debit, error) {
+		if customerID != escrow.BuyerID {
+			return nil, domain.NewValidationError("escrow is disputed by buyer")
+		}
+		if !escrow.CanTransitionTo(domain.EscrowStatusDisputed) {
+			return nil, domain.NewValidationError(fmt.Sprintf("%s escrow could not be disputed", escrow.Status))
+		}
+		escrow.Status = domain.EscrowStatusDisputed
+		escrow.DisputeReason = reason
+		escrow.UpdatedAt = now
+		return nil, nil
+	})
+}
+
+// Resolve settles held or disputed escrow by merchant, sellerAmount is paid to seller and the rest to buyer
+func (s *EscrowUseCase) Resolve(escrowID string, customerID string, sellerAmount int64) (*domain.Escrow, error) {
+	return s.update(escrowID, func(escrow *domain.Escrow, now time.Time) ([]*domain.Debit, error) {
+		if customerID != escrow.MerchantID {
+			return nil, domain.NewValidationError("escrow is resolved by merchant")
+		}
+		if sellerAmount < 0 || sellerAmount > escrow.Amount {
+			return nil, domain.NewValidationError("seller_amount should be between zero and escrow amount")
+		}
+		return s.settle(escrow, sellerAmount, now)
+	})
+}
+
+// SettleExpired applies expiry action to held escrows which expiry is over
+func (s *EscrowUseCase) SettleExpired(now time.Time, limit int) (int, error) {
+	return s.repo.ClaimExpired(now, limit, func(escrow *domain.Escrow) ([]*domain.Debit, error) {
+		sellerAmount := escrow.Amount
+		if escrow.ExpiryAction == domain.EscrowExpiryActionRefund {
+			sellerAmount = 0
+		}
+		return s.settle(escrow, sellerAmount, now)
+	})
+}
+
+func (s *EscrowUseCase) update(
+	escrowID string,
+	update func(escrow *domain.Escrow, now time.Time) ([]*domain.Debit, error),
+) (*domain.Escrow, error) {
+	now := time.Now()
+	escrow, err := s.repo.Update(escrowID, func(escrow *domain.Escrow) ([]*domain.Debit, error) {
+		return update(escrow, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if escrow == nil {
+		return nil, domain.NewNotFoundError("escrow with such id not found")
+	}
+	return escrow, nil
+}
+
+// settle pays escrow to seller and buyer from escrow account. Debits are saved with escrow while it is locked,
+// so settled escrow is never paid twice.
+func (s *EscrowUseCase) settle(escrow *domain.Escrow, sellerAmount int64, now time.Time) ([]*domain.Debit, error) {
+	status := escrow.SettlementStatus(sellerAmount)
+	if !escrow.CanTransitionTo(status) {
+		return nil, domain.NewValidationError(fmt.Sprintf("%s escrow could not be settled", escrow.Status))
+	}
+
+	payments := []struct {
+		customerID string
+		amount     int64
+		reference  string
+	}{
+		{escrow.SellerID, sellerAmount, "escrow:" + escrow.GeneratedID + ":seller"},
+		{escrow.BuyerID, escrow.Amount - sellerAmount, "escrow:" + escrow.GeneratedID + ":buyer"},
+	}
+	var debits []*domain.Debit
+	for _, payment := range payments {
+		if payment.amount == 0 {
+			continue
+		}
+		debit := &domain.Debit{
+			PayerID:     domain.LedgerAccountEscrow,
+			PayeeID:     payment.customerID,
+			Amount:      payment.amount,
+			Currency:    escrow.Currency,
+			Description: "Escrow " + escrow.GeneratedID,
+			Reference:   payment.reference,
+		}
+		err := s.debits.Prepare(debit)
+		if err != nil {
+			return nil, err
+		}
+		debits = append(debits, debit)
+	}
+
+	escrow.Status = status
+	escrow.SellerAmount = sellerAmount
+	escrow.SettledAt = now
+	escrow.UpdatedAt = now
+	return debits, nil
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/fee"
+)
+
+type FeeUseCase struct {
+	repo domain.FeeRepository
+}
+
+func NewFeeUseCase(repo domain.FeeRepository) *FeeUseCase {
+	return &FeeUseCase{repo: repo}
+}
+
+// CreateSchedule adds next version of fee schedule. Schedule could not become effective in the past
+// or before the latest version, so fees of already charged movements never change.
+func (f *FeeUseCase) CreateSchedule(effectiveFrom time.Time, rules []domain.FeeRule) (*domain.FeeSchedule, error) {
+	err := fee.Validate(rules)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+
+	now := time.Now()
+	if effectiveFrom.IsZero() {
+		effectiveFrom = now
+	}
+	if effectiveFrom.Before(now.Add(-time.Minute)) {
+		return nil, domain.NewValidationError("schedule could not become effective in the past")
+	}
+
+	latest, err := f.repo.FindLatestSchedule()
+	if err != nil {
+		return nil, err
+	}
+	version := 1
+	if latest != nil {
+		if effectiveFrom.Before(latest.EffectiveFrom) {
+			return nil, domain.NewValidationError(
+				fmt.Sprintf("schedule could not become effective before version %d", latest.Version),
+			)
+		}
+		version = latest.Version + 1
+	}
+
+	schedule := &domain.FeeSchedule{
+		Version:       version,
+		EffectiveFrom: effectiveFrom,
+		Rules:         rules,
+		CreatedAt:     now,
+	}
+	err = f.repo.CreateSchedule(schedule)
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (f *FeeUseCase) FindSchedule(version int) (*domain.FeeSchedule, error) {
+	schedule, err := f.repo.FindScheduleByVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// Charge calculates fee of operation by the schedule effective at, fee is zero when no schedule is effective yet.
+// Version of schedule in quote is saved with fee postings, so that fee could be recalculated later.
+func (f *FeeUseCase) Charge(
+	operation domain.MovementType,
+	amount int64,
+	currency string,
+	at time.Time,
+) (domain.FeeQuote, error) {
+	schedule, err := f.repo.FindEffectiveSchedule(at)
+	if err != nil {
+		return domain.FeeQuote{}, err
+	}
+	if schedule == nil {
+		return domain.FeeQuote{Operation: operation, Currency: currency, Amount: amount}, nil
+	}
+	return fee.Quote(schedule, operation, amount, currency), nil
+}
+
+// Quote calculates fee by the schedule effective now or, when version is set,
+// by that version to recalculate fee of a past movement
+func (f *FeeUseCase) Quote(
+	operation domain.MovementType,
+	amount int64,
+	currency string,
+	version int,
+) (*domain.FeeQuote, error) {
+	var schedule *domain.FeeSchedule
+	var err error
+	if version > 0 {
+		schedule, err = f.repo.FindScheduleByVersion(version)
+	} else {
+		schedule, err = f.repo.FindEffectiveSchedule(time.Now())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, domain.NewNotFoundError("fee schedule not found")
+	}
+
+	quote := fee.Quote(schedule, operation, amount, currency)
+	return &quote, nil
+}
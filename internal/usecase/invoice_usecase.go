@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/invoicing"
+)
+
+type InvoiceUseCase struct {
+	repo         domain.InvoiceRepository
+	customerRepo domain.CustomerRepository
+	debits       domain.DebitFlow
+	risks        *RiskUseCase
+}
+
+func NewInvoiceUseCase(
+	repo domain.InvoiceRepository,
+	customerRepo domain.CustomerRepository,
+	debits domain.DebitFlow,
+	risks *RiskUseCase,
+) *InvoiceUseCase {
+	return &InvoiceUseCase{repo: repo, customerRepo: customerRepo, debits: debits, risks: risks}
+}
+
+// Create saves invoice as a draft, number is assigned when draft is finalized
+func (s *InvoiceUseCase) Create(invoice *domain.Invoice) error {
+	customer, err := s.customerRepo.FindByID(invoice.CustomerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.NewNotFoundError("customer with such id not found")
+	}
+	err = invoicing.Calculate(invoice)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	now := time.Now()
+	invoice.GeneratedID, err = hash.GenerateUniqueInvoiceID(invoice.TenantID, invoice.CustomerID, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	invoice.Number = 0
+	invoice.Status = domain.InvoiceStatusDraft
+	invoice.CreatedAt = now
+	invoice.UpdatedAt = now
+	return s.repo.Create(invoice)
+}
+
+func (s *InvoiceUseCase) Find(invoiceID string) (*domain.Invoice, error) {
+	invoice, err := s.repo.FindByID(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice == nil {
+		return nil, domain.NewNotFoundError("invoice with such id not found")
+	}
+	return invoice, nil
+}
+
+func (s *InvoiceUseCase) FindByCustomer(customerID string) ([]*domain.Invoice, error) {
+	invoices, err := s.repo.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// UpdateDraft replaces currency, due date and lines of draft invoice
+func (s *InvoiceUseCase) UpdateDraft(invoiceID string, changes *domain.Invoice) (*domain.Invoice, error) {
+	invoice, err := s.Find(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Status != domain.InvoiceStatusDraft {
+		return nil, domain.NewValidationError("only draft invoice could be changed")
+	}
+
+	invoice.Currency = changes.Currency
+	invoice.DueDate = changes.DueDate
+	invoice.Lines = changes.Lines
+	err = invoicing.Calculate(invoice)
+	if err != nil {
+		return nil, domain.NewValidationError(err.Error())
+	}
+	invoice.UpdatedAt = time.Now()
+
+	updated, err := s.repo.Update(invoice, domain.InvoiceStatusDraft)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, domain.NewValidationError("only draft invoice could be changed")
+	}
+	return invoice, nil
+}
+
+// Finalize issues draft invoice with the next number of its tenant
+func (s *InvoiceUseCase) Finalize(invoiceID string) (*domain.Invoice, error) {
+	invoice, err := s.Find(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !invoice.CanTransitionTo(domain.InvoiceStatusOpen) {
+		return nil, domain.NewValidationError(fmt.Sprintf("%s invoice could not be finalized", invoice.Status))
+	}
+
+	now := time.Now()
+	invoice.Status = domain.InvoiceStatusOpen
+	invoice.IssuedAt = now
+	invoice.UpdatedAt = now
+	finalized, err := s.repo.Finalize(invoice)
+	if err != nil {
+		return nil, err
+	}
+	if !finalized {
+		return nil, domain.NewValidationError("invoice was finalized or voided meanwhile")
+	}
+	return invoice, nil
+}
+
+func (s *InvoiceUseCase) Void(invoiceID string) (*domain.Invoice, error) {
+	invoice, err := s.Find(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !invoice.CanTransitionTo(domain.InvoiceStatusVoid) {
+		return nil, domain.NewValidationError(fmt.Sprintf("%s invoice could not be voided", invoice.Status))
+	}
+
+	previousStatus := invoice.Status
+	now := time.Now()
+	invoice.Status = domain.InvoiceStatusVoid
+	invoice.VoidedAt = now
+	invoice.UpdatedAt = now
+	updated, err := s.repo.Update(invoice, previousStatus)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, domain.NewValidationError("invoice status was changed meanwhile")
+	}
+	return invoice, nil
+}
+
+// Pay transfers invoice total from customer balance to account of tenant. Invoice is marked as paid
+// in the same transaction, so open invoice is never debited twice. Payment is scored by risk assessment
+// with device and IP of customer and is refused when it is declined.
+func (s *InvoiceUseCase) Pay(invoiceID string, deviceID string, ip string) (*domain.Invoice, error) {
+	invoice, err := s.Find(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	if !invoice.CanTransitionTo(domain.InvoiceStatusPaid) {
+		return nil, domain.NewValidationError(fmt.Sprintf("%s invoice could not be paid", invoice.Status))
+	}
+	assessment, err := s.risks.Authorize(invoice.CustomerID, invoice.Total, invoice.Currency, deviceID, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	debit := &domain.Debit{
+		PayerID:     invoice.CustomerID,
+		PayeeID:     domain.TenantLedgerAccount(invoice.TenantID),
+		Amount:      invoice.Total,
+		Currency:    invoice.Currency,
+		Description: fmt.Sprintf("Invoice %d", invoice.Number),
+		Operation:   domain.MovementTypeTransfer,
+		Reference:   "invoice:" + invoice.GeneratedID,
+	}
+	err = s.debits.Prepare(debit)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.Status = domain.InvoiceStatusPaid
+	invoice.RiskAssessmentID = assessment.GeneratedID
+	invoice.RiskDecision = assessment.Decision
+	invoice.PaidAt = debit.PostedAt
+	invoice.UpdatedAt = debit.PostedAt
+	paid, err := s.repo.Pay(invoice, debit)
+	if err != nil {
+		return nil, debitError(err)
+	}
+	if !paid {
+		return nil, domain.NewValidationError("invoice was paid or voided meanwhile")
+	}
+	s.debits.Monitor(debit)
+	return invoice, nil
+}
+
+// RenderPDF renders invoice addressed to its customer
+func (s *InvoiceUseCase) RenderPDF(invoiceID string) ([]byte, error) {
+	invoice, err := s.Find(invoiceID)
+	if err != nil {
+		return nil, err
+	}
+	customer, err := s.customerRepo.FindByID(invoice.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	var customerName string
+	if customer != nil {
+		customerName = customer.Name()
+	}
+
+	var document bytes.Buffer
+	err = invoicing.RenderPDF(&document, invoice, customerName)
+	if err != nil {
+		return nil, err
+	}
+	return document.Bytes(), nil
+}
@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+)
+
+type LedgerUseCase struct {
+	repo          domain.LedgerRepository
+	verifications *VerificationUseCase
+	limits        *LimitUseCase
+	fees          *FeeUseCase
+	monitoring    *MonitoringUseCase
+}
+
+func NewLedgerUseCase(
+	repo domain.LedgerRepository,
+	verifications *VerificationUseCase,
+	limits *LimitUseCase,
+	fees *FeeUseCase,
+	monitoring *MonitoringUseCase,
+) *LedgerUseCase {
+	return &LedgerUseCase{
+		repo:          repo,
+		verifications: verifications,
+		limits:        limits,
+		fees:          fees,
+		monitoring:    monitoring,
+	}
+}
+
+// Prepare builds postings of debit, checks that customer payer is allowed to move money, charges limits of payer
+// in debit currency and prices fee of debit operation by the fee schedule effective now
+func (s *LedgerUseCase) Prepare(debit *domain.Debit) error {
+	debit.PostedAt = time.Now()
+	if !domain.IsLedgerAccount(debit.PayerID) {
+		err := s.verifications.EnsureCanMoveMoney(debit.PayerID)
+		if err != nil {
+			return err
+		}
+		debit.Limit, err = s.limits.Charge(debit.PayerID, debit.Currency, debit.Amount)
+		if err != nil {
+			return err
+		}
+	}
+	if debit.Operation != "" {
+		quote, err := s.fees.Charge(debit.Operation, debit.Amount, debit.Currency, debit.PostedAt)
+		if err != nil {
+			return err
+		}
+		debit.Fee = quote.Fee
+		debit.FeeScheduleVersion = quote.ScheduleVersion
+	}
+	return buildDebitPostings(debit)
+}
+
+// Transfer debits payer and credits payee of debit in one transaction. Postings are identified by reference
+// of debit, so transferring again after a failure does not move money twice. Posted debit is monitored.
+func (s *LedgerUseCase) Transfer(debit *domain.Debit) error {
+	err := s.Prepare(debit)
+	if err != nil {
+		return err
+	}
+	posted, err := s.repo.Post(debit)
+	if err != nil {
+		return debitError(err)
+	}
+	if posted {
+		s.Monitor(debit)
+	}
+	return nil
+}
+
+// Monitor reports committed debit to transaction monitoring as movements of customer payer and customer payees,
+// amounts credited to the same payee are summed up and credits of payer to itself are left out.
+// Debits without operation return money to customers, e.g. refunds and settlements, and are not monitored.
+func (s *LedgerUseCase) Monitor(debit *domain.Debit) {
+	if debit.Operation == "" {
+		return
+	}
+	var movements []*domain.Movement
+	if !domain.IsLedgerAccount(debit.PayerID) {
+		movements = append(movements, &domain.Movement{
+			CustomerID: debit.PayerID,
+			Type:       debit.Operation,
+			Amount:     debit.Amount,
+			Currency:   debit.Currency,
+			CreatedAt:  debit.PostedAt,
+		})
+	}
+	received := make(map[string]*domain.Movement)
+	for _, credit := range debitCredits(debit) {
+		if domain.IsLedgerAccount(credit.PayeeID) || credit.PayeeID == debit.PayerID {
+			continue
+		}
+		movement, ok := received[credit.PayeeID]
+		if !ok {
+			movement = &domain.Movement{
+				CustomerID: credit.PayeeID,
+				Type:       debit.Operation,
+				Currency:   debit.Currency,
+				CreatedAt:  debit.PostedAt,
+			}
+			received[credit.PayeeID] = movement
+			movements = append(movements, movement)
+		}
+		movement.Amount += credit.Amount
+	}
+	s.monitoring.Observe(movements...)
+}
+
+// debitError turns rejection of debit by ledger into validation error, other errors are returned as they are
+func debitError(err error) error {
+	if err == domain.ErrInsufficientFunds {
+		return domain.NewValidationError(err.Error())
+	}
+	return err
+}
+
+// debitCredits returns credits of debit split among payees or a single credit of the whole amount to payee
+func debitCredits(debit *domain.Debit) []domain.DebitCredit {
+	if len(debit.Credits) > 0 {
+		return debit.Credits
+	}
+	return []domain.DebitCredit{{PayeeID: debit.PayeeID, Amount: debit.Amount}}
+}
+
+// buildDebitPostings builds debit leg of payer, credit legs of payees and legs of fee
+func buildDebitPostings(debit *domain.Debit) error {
+	debit.Postings = []*domain.Posting{{
+		CustomerID:  debit.PayerID,
+		Amount:      -debit.Amount,
+		Currency:    debit.Currency,
+		Description: debit.Description,
+		Reference:   debit.Reference,
+		PostedAt:    debit.PostedAt,
+	}}
+	for _, credit := range debitCredits(debit) {
+		debit.Postings = append(debit.Postings, &domain.Posting{
+			CustomerID:  credit.PayeeID,
+			Amount:      credit.Amount,
+			Currency:    debit.Currency,
+			Description: debit.Description,
+			Reference:   debit.Reference,
+			PostedAt:    debit.PostedAt,
+		})
+	}
+	debit.Postings = append(debit.Postings, feePostings(
+		debit.PayerID,
+		domain.FeeQuote{ScheduleVersion: debit.FeeScheduleVersion, Currency: debit.Currency, Fee: debit.Fee},
+		debit.Description,
+		debit.Reference,
+		debit.PostedAt,
+	)...)
+	for i, posting := range debit.Postings {
+		var err error
+		posting.GeneratedID, err = hash.GenerateUniquePostingID(debit.Reference, i)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transferPostings builds debit leg of payer and credit leg of payee which move amount within event of reference.
+// Event is a number of money movement posted under the same reference, like a repayment of loan, so that every
+// event gets ids of its own pair of legs and is posted once.
+func transferPostings(
+	reference string,
+	event int,
+	payerID string,
+	payeeID string,
+	amount int64,
+	currency string,
+	description string,
+	postedAt time.Time,
+) ([]*domain.Posting, error) {
+	postings := []*domain.Posting{
+		{CustomerID: payerID, Amount: -amount},
+		{CustomerID: payeeID, Amount: amount},
+	}
+	for i, posting := range postings {
+		var err error
+		posting.GeneratedID, err = hash.GenerateUniquePostingID(reference, 2*event+i)
+		if err != nil {
+			return nil, err
+		}
+		posting.Currency = currency
+		posting.Description = description
+		posting.Reference = reference
+		posting.PostedAt = postedAt
+	}
+	return postings, nil
+}
+
+// feePostings builds debit leg of payer and credit leg of fee revenue account for fee of quote, there are no legs
+// when operation is free of charge. Ids of postings are generated along with other postings of operation.
+func feePostings(
+	payerID string,
+	quote domain.FeeQuote,
+	description string,
+	reference string,
+	postedAt time.Time,
+) []*domain.Posting {
+	if quote.Fee <= 0 {
+		return nil
+	}
+	description = strings.TrimSuffix("Fee: "+description, ": ")
+	return []*domain.Posting{
+		{
+			CustomerID:         payerID,
+			Amount:             -quote.Fee,
+			Currency:           quote.Currency,
+			Description:        description,
+			Reference:          reference,
+			FeeScheduleVersion: quote.ScheduleVersion,
+			PostedAt:           postedAt,
+		},
+		{
+			CustomerID:         domain.LedgerAccountFees,
+			Amount:             quote.Fee,
+			Currency:           quote.Currency,
+			Description:        description,
+			Reference:          reference,
+			FeeScheduleVersion: quote.ScheduleVersion,
+			PostedAt:           postedAt,
+		},
+	}
+}
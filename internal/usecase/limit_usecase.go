@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+const LimitSourceOverride = "override"
+
+type LimitUseCase struct {
+	repo             domain.LimitRepository
+	verificationRepo domain.VerificationRepository
+	customerRepo     domain.CustomerRepository
+}
+
+func NewLimitUseCase(
+	repo domain.LimitRepository,
+	verificationRepo domain.VerificationRepository,
+	customerRepo domain.CustomerRepository,
+) *LimitUseCase {
+	return &LimitUseCase{repo: repo, verificationRepo: verificationRepo, customerRepo: customerRepo}
+}
+
+// CustomerLimits are limits applied to customer with their usage in current day and month.
+// Source is either verification level of customer tier or override.
+type CustomerLimits struct {
+	Source string
+	Limits domain.TransactionLimits
+	Used   domain.LimitUsage
+}
+
+// Limits returns customer override in currency when it is set, otherwise limits in currency of the tier granted
+// by approved verification, legal entity gets the tier of its representatives. Customers without approved
+// verification are not allowed to move money at all.
+func (l *LimitUseCase) Limits(customerID string, currency string) (*CustomerLimits, error) {
+	customerLimits, err := l.limits(customerID, currency)
+	if err != nil {
+		return nil, err
+	}
+	customerLimits.Used, err = l.repo.FindUsage(customerID, currency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return customerLimits, nil
+}
+
+// Charge resolves limits of customer in currency for debit of amount. Charge is checked and recorded in usage
+// by repository of debit in transaction of debit, so concurrent debits of the same customer could not breach
+// limits together, debit fails with LimitExceededError when it would breach one of limits.
+func (l *LimitUseCase) Charge(customerID string, currency string, amount int64) (*domain.LimitCharge, error) {
+	if amount <= 0 {
+		return nil, domain.NewValidationError("amount should be positive")
+	}
+	customerLimits, err := l.limits(customerID, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.LimitCharge{
+		CustomerID: customerID,
+		Currency:   currency,
+		Amount:     amount,
+		Limits:     customerLimits.Limits,
+		ChargedAt:  time.Now(),
+	}, nil
+}
+
+func (l *LimitUseCase) SetOverride(customerID string, currency string, limits domain.TransactionLimits) error {
+	_, err := l.findCustomer(customerID)
+	if err != nil {
+		return err
+	}
+	return l.repo.SaveCustomerLimits(customerID, currency, limits)
+}
+
+func (l *LimitUseCase) DeleteOverride(customerID string, currency string) error {
+	return l.repo.DeleteCustomerLimits(customerID, currency)
+}
+
+func (l *LimitUseCase) limits(customerID string, currency string) (*CustomerLimits, error) {
+	customer, err := l.findCustomer(customerID)
+	if err != nil {
+		return nil, err
+	}
+	override, err := l.repo.FindCustomerLimits(customerID, currency)
+	if err != nil {
+		return nil, err
+	}
+	if override != nil {
+		return &CustomerLimits{Source: LimitSourceOverride, Limits: *override}, nil
+	}
+
+	level, _, err := approvedVerificationLevel(l.verificationRepo, customer)
+	if err != nil {
+		return nil, err
+	}
+
+	tierLimits, err := l.repo.FindTierLimits(level, currency)
+	if err != nil {
+		return nil, err
+	}
+	if tierLimits == nil {
+		return nil, fmt.Errorf("limits for tier %s in %s are not configured", level, currency)
+	}
+	return &CustomerLimits{Source: string(level), Limits: *tierLimits}, nil
+}
+
+func (l *LimitUseCase) findCustomer(customerID string) (*domain.Customer, error) {
+	customer, err := l.customerRepo.FindByID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, domain.NewNotFoundError("customer with such id not found")
+	}
+	return customer, nil
+}
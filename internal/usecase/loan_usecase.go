@@ -0,0 +1,355 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/businessday"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/lending"
+)
+
+// loanDisbursementEvent is an event of loan postings which moves principal to customer, repayment of number n
+// posts its principal and its interest with penalty as events 2n and 2n+1
+const loanDisbursementEvent = 0
+
+type LoanUseCase struct {
+	repo          domain.LoanRepository
+	customerRepo  domain.CustomerRepository
+	verifications *VerificationUseCase
+}
+
+func NewLoanUseCase(
+	repo domain.LoanRepository,
+	customerRepo domain.CustomerRepository,
+	verifications *VerificationUseCase,
+) *LoanUseCase {
+	return &LoanUseCase{repo: repo, customerRepo: customerRepo, verifications: verifications}
+}
+
+func (s *LoanUseCase) CreateProduct(product *domain.LoanProduct) error {
+	err := lending.ValidateProduct(product)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	now := time.Now()
+	product.GeneratedID, err = hash.GenerateUniqueLoanProductID(product.Name, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	product.CreatedAt = now
+	return s.repo.CreateProduct(product)
+}
+
+func (s *LoanUseCase) FindProduct(productID string) (*domain.LoanProduct, error) {
+	product, err := s.repo.FindProductByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, domain.NewNotFoundError("loan product with such id not found")
+	}
+	return product, nil
+}
+
+func (s *LoanUseCase) FindProducts() ([]*domain.LoanProduct, error) {
+	products, err := s.repo.FindProducts()
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// Apply decides on application of customer for loan of product. Application of customer younger than
+// lending.MinBorrowerAge by passport birth date is rejected, other applications within product limits are approved.
+func (s *LoanUseCase) Apply(application *domain.LoanApplication) error {
+	customer, err := s.customerRepo.FindByID(application.CustomerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.NewNotFoundError("customer with such id not found")
+	}
+	if customer.Status != domain.CustomerStatusActive {
+		return domain.NewValidationError(fmt.Sprintf("customer is %s", customer.Status))
+	}
+	err = s.verifications.EnsureCanMoveMoney(application.CustomerID)
+	if err != nil {
+		return err
+	}
+	product, err := s.FindProduct(application.ProductID)
+	if err != nil {
+		return err
+	}
+	if application.Amortisation == "" {
+		application.Amortisation = domain.AmortisationAnnuity
+	}
+	err = lending.ValidateApplication(application, product)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+
+	now := time.Now()
+	application.GeneratedID, err = hash.GenerateUniqueLoanApplicationID(
+		application.CustomerID,
+		product.GeneratedID,
+		now.UnixNano(),
+	)
+	if err != nil {
+		return err
+	}
+	application.Currency = product.Currency
+	application.Status = domain.LoanApplicationStatusApproved
+	application.RejectionReason = ""
+	application.LoanID = ""
+	switch {
+	case customer.Passport.BirthDate.IsZero():
+		application.Status = domain.LoanApplicationStatusRejected
+		application.RejectionReason = "birth date of customer is unknown"
+	case lending.Age(customer.Passport.BirthDate, now) < lending.MinBorrowerAge:
+		application.Status = domain.LoanApplicationStatusRejected
+		application.RejectionReason = fmt.Sprintf("customer should be %d years old at least", lending.MinBorrowerAge)
+	}
+	application.CreatedAt = now
+	application.UpdatedAt = now
+	return s.repo.CreateApplication(application)
+}
+
+func (s *LoanUseCase) FindApplication(applicationID string) (*domain.LoanApplication, error) {
+	application, err := s.repo.FindApplicationByID(applicationID)
+	if err != nil {
+		return nil, err
+	}
+	if application == nil {
+		return nil, domain.NewNotFoundError("loan application with such id not found")
+	}
+	return application, nil
+}
+
+func (s *LoanUseCase) FindApplicationsByCustomer(customerID string) ([]*domain.LoanApplication, error) {
+	applications, err := s.repo.FindApplicationsByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// Disburse credits amount of approved application to customer balance and starts loan with monthly schedule.
+// Loan is identified by application, so application is never disbursed twice.
+func (s *LoanUseCase) Disburse(applicationID string) (*domain.Loan, error) {
+	found, err := s.FindApplication(applicationID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := s.FindProduct(found.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	loan, err := s.repo.Disburse(
+		applicationID,
+		func(application *domain.LoanApplication) (*domain.Loan, []*domain.Posting, error) {
+			if application.Status != domain.LoanApplicationStatusApproved {
+				return nil, nil, domain.NewValidationError(
+					fmt.Sprintf("%s application could not be disbursed", application.Status),
+				)
+			}
+			loanID, err := hash.GenerateUniqueLoanID(application.GeneratedID)
+			if err != nil {
+				return nil, nil, err
+			}
+			loan := &domain.Loan{
+				GeneratedID:         loanID,
+				ApplicationID:       application.GeneratedID,
+				ProductID:           product.GeneratedID,
+				CustomerID:          application.CustomerID,
+				Currency:            application.Currency,
+				Principal:           application.Amount,
+				Outstanding:         application.Amount,
+				AnnualRate:          product.AnnualRate,
+				PenaltyRate:         product.PenaltyRate,
+				Amortisation:        application.Amortisation,
+				TermMonths:          application.TermMonths,
+				Status:              domain.LoanStatusActive,
+				PenaltyAccruedUntil: businessday.Day(now),
+				DisbursedAt:         now,
+				UpdatedAt:           now,
+			}
+			err = lending.Schedule(loan)
+			if err != nil {
+				return nil, nil, err
+			}
+			loan.NextServiceDay = loan.Installments[0].DueDay
+			postings, err := loanPostings(
+				loan,
+				loanDisbursementEvent,
+				domain.LedgerAccountLoans,
+				loan.CustomerID,
+				loan.Principal,
+				"disbursement",
+				now,
+			)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			application.Status = domain.LoanApplicationStatusDisbursed
+			application.LoanID = loan.GeneratedID
+			application.UpdatedAt = now
+			return loan, postings, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if loan == nil {
+		return nil, domain.NewNotFoundError("loan application with such id not found")
+	}
+	return loan, nil
+}
+
+func (s *LoanUseCase) Find(loanID string) (*domain.Loan, error) {
+	loan, err := s.repo.FindByID(loanID)
+	if err != nil {
+		return nil, err
+	}
+	if loan == nil {
+		return nil, domain.NewNotFoundError("loan with such id not found")
+	}
+	return loan, nil
+}
+
+func (s *LoanUseCase) FindByCustomer(customerID string) ([]*domain.Loan, error) {
+	loans, err := s.repo.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return loans, nil
+}
+
+func (s *LoanUseCase) FindRepayments(loanID string) ([]*domain.LoanRepayment, error) {
+	_, err := s.Find(loanID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindRepayments(loanID)
+}
+
+// Repay repays loan early from customer balance. Amount more than loan costs today repays loan in full
+// and only the cost is debited.
+func (s *LoanUseCase) Repay(loanID string, customerID string, amount int64) (*domain.Loan, error) {
+	err := s.verifications.EnsureCanMoveMoney(customerID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	loan, err := s.repo.Update(
+		loanID,
+		func(loan *domain.Loan, balance int64) (*domain.LoanRepayment, []*domain.Posting, error) {
+			if customerID != loan.CustomerID {
+				return nil, nil, domain.NewValidationError("loan is repaid by its customer")
+			}
+			repayment, err := lending.Repay(loan, now, amount)
+			if err != nil {
+				return nil, nil, domain.NewValidationError(err.Error())
+			}
+			if repayment.Amount > balance {
+				return nil, nil, domain.NewValidationError("insufficient funds on customer balance")
+			}
+			postings, err := loanRepaymentPostings(loan, repayment, "early repayment", now)
+			if err != nil {
+				return nil, nil, err
+			}
+			return repayment, postings, nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if loan == nil {
+		return nil, domain.NewNotFoundError("loan with such id not found")
+	}
+	return loan, nil
+}
+
+// ServiceDue collects due installments and penalty of loans from customer balances and accrues penalty
+// on installments which are not repaid
+func (s *LoanUseCase) ServiceDue(now time.Time, limit int) (int, error) {
+	return s.repo.ClaimDue(
+		businessday.Day(now),
+		limit,
+		func(loan *domain.Loan, balance int64) (*domain.LoanRepayment, []*domain.Posting, error) {
+			repayment, err := lending.Service(loan, now, balance)
+			if err != nil || repayment == nil {
+				return nil, nil, err
+			}
+			postings, err := loanRepaymentPostings(loan, repayment, "repayment", now)
+			if err != nil {
+				return nil, nil, err
+			}
+			return repayment, postings, nil
+		},
+	)
+}
+
+// loanRepaymentPostings move principal part of repayment from customer to loan book and its interest
+// with penalty to interest income
+func loanRepaymentPostings(
+	loan *domain.Loan,
+	repayment *domain.LoanRepayment,
+	entry string,
+	now time.Time,
+) ([]*domain.Posting, error) {
+	parts := []struct {
+		payeeID string
+		amount  int64
+		entry   string
+	}{
+		{domain.LedgerAccountLoans, repayment.Principal, entry},
+		{domain.LedgerAccountInterestIncome, repayment.Interest + repayment.Penalty, entry + " interest"},
+	}
+	var postings []*domain.Posting
+	for i, part := range parts {
+		if part.amount <= 0 {
+			continue
+		}
+		partPostings, err := loanPostings(
+			loan,
+			2*repayment.Number+i,
+			loan.CustomerID,
+			part.payeeID,
+			part.amount,
+			part.entry,
+			now,
+		)
+		if err != nil {
+			return nil, err
+		}
+		postings = append(postings, partPostings...)
+	}
+	return postings, nil
+}
+
+func loanPostings(
+	loan *domain.Loan,
+	event int,
+	payerID string,
+	payeeID string,
+	amount int64,
+	entry string,
+	now time.Time,
+) ([]*domain.Posting, error) {
+	return transferPostings(
+		"loan:"+loan.GeneratedID,
+		event,
+		payerID,
+		payeeID,
+		amount,
+		loan.Currency,
+		"Loan "+loan.GeneratedID+" "+entry,
+		now,
+	)
+}
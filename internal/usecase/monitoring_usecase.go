@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/monitoring"
+	"go.uber.org/zap"
+)
+
+type MonitoringUseCase struct {
+	repo         domain.MonitoringRepository
+	customerRepo domain.CustomerRepository
+	ruleSet      *monitoring.RuleSet
+	logger       *zap.Logger
+}
+
+func NewMonitoringUseCase(
+	repo domain.MonitoringRepository,
+	customerRepo domain.CustomerRepository,
+	ruleSet *monitoring.RuleSet,
+	logger *zap.Logger,
+) *MonitoringUseCase {
+	return &MonitoringUseCase{repo: repo, customerRepo: customerRepo, ruleSet: ruleSet, logger: logger}
+}
+
+// Observe evaluates movements of committed payment. Payment could not be undone at this point,
+// so movement which could not be evaluated is logged rather than failing the payment.
+func (m *MonitoringUseCase) Observe(movements ...*domain.Movement) {
+	for _, movement := range movements {
+		_, err := m.Evaluate(movement)
+		if err != nil {
+			m.logger.Error(fmt.Sprintf(
+				"unable to evaluate %s movement of customer %s: %s",
+				movement.Type,
+				movement.CustomerID,
+				err.Error(),
+			))
+		}
+	}
+}
+
+// Evaluate stores money movement and checks it against current monitoring rules.
+// Alerts raised by the movement are stored in open status and returned.
+func (m *MonitoringUseCase) Evaluate(movement *domain.Movement) ([]*domain.Alert, error) {
+	customer, err := m.customerRepo.FindByID(movement.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, domain.NewNotFoundError("customer with such id not found")
+	}
+
+	if movement.CreatedAt.IsZero() {
+		movement.CreatedAt = time.Now()
+	}
+	movement.GeneratedID, err = hash.GenerateUniqueMovementID(movement.CustomerID, movement.CreatedAt.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+
+	rules := m.ruleSet.Rules()
+	history, err := m.repo.FindMovements(movement.CustomerID, movement.CreatedAt.Add(-rules.MaxWindow()))
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.repo.CreateMovement(movement)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := monitoring.Evaluate(rules, movement, customer, history)
+	if len(alerts) == 0 {
+		return nil, nil
+	}
+	for _, alert := range alerts {
+		alert.GeneratedID, err = hash.GenerateUniqueAlertID(movement.GeneratedID, alert.RuleName)
+		if err != nil {
+			return nil, err
+		}
+		alert.Status = domain.AlertStatusOpen
+		alert.CreatedAt = time.Now()
+		alert.UpdatedAt = alert.CreatedAt
+	}
+
+	err = m.repo.CreateAlerts(alerts)
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+func (m *MonitoringUseCase) FindAlerts(status domain.AlertStatus) ([]*domain.Alert, error) {
+	alerts, err := m.repo.FindAlertsByStatus(status)
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+func (m *MonitoringUseCase) FindAlert(alertID string) (*domain.Alert, error) {
+	alert, err := m.repo.FindAlertByID(alertID)
+	if err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+// UpdateAlert moves alert through investigation. Closed alert should have a resolution.
+func (m *MonitoringUseCase) UpdateAlert(
+	alertID string,
+	status domain.AlertStatus,
+	resolution domain.AlertResolution,
+	assignee string,
+	comment string,
+) error {
+	alert, err := m.repo.FindAlertByID(alertID)
+	if err != nil {
+		return err
+	}
+	if alert == nil {
+		return domain.NewNotFoundError("alert with such id not found")
+	}
+
+	if status != alert.Status && !alert.CanTransitionTo(status) {
+		return domain.NewValidationError(
+			fmt.Sprintf("alert could not be moved from %s to %s", alert.Status, status),
+		)
+	}
+	if status == domain.AlertStatusClosed && resolution == domain.AlertResolutionNone {
+		return domain.NewValidationError("resolution is mandatory for closed alert")
+	}
+	if status != domain.AlertStatusClosed && resolution != domain.AlertResolutionNone {
+		return domain.NewValidationError("resolution could be set only for closed alert")
+	}
+
+	alert.Status = status
+	alert.Resolution = resolution
+	alert.Assignee = assignee
+	alert.Comment = comment
+	alert.UpdatedAt = time.Now()
+	return m.repo.UpdateAlert(alert)
+}
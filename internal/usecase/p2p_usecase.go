@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/beneficiary"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/p2p"
+)
+
+type P2PUseCase struct {
+	repo            domain.P2PRepository
+	customerRepo    domain.CustomerRepository
+	beneficiaryRepo domain.BeneficiaryRepository
+	debits          domain.DebitFlow
+	risks           *RiskUseCase
+	policy          p2p.LookupPolicy
+	coolingOff      beneficiary.CoolingOffPolicy
+}
+
+func NewP2PUseCase(
+	repo domain.P2PRepository,
+	customerRepo domain.CustomerRepository,
+	beneficiaryRepo domain.BeneficiaryRepository,
+	debits domain.DebitFlow,
+	risks *RiskUseCase,
+	policy p2p.LookupPolicy,
+	coolingOff beneficiary.CoolingOffPolicy,
+) *P2PUseCase {
+	return &P2PUseCase{
+		repo:            repo,
+		customerRepo:    customerRepo,
+		beneficiaryRepo: beneficiaryRepo,
+		debits:          debits,
+		risks:           risks,
+		policy:          policy,
+		coolingOff:      coolingOff,
+	}
+}
+
+// FindRecipient finds customer by phone and shows masked name of recipient to sender for confirmation.
+// Phone should be normalized.
+func (s *P2PUseCase) FindRecipient(senderID string, phone string) (*domain.P2PRecipient, error) {
+	_, recipient, err := s.lookUp(senderID, phone)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.P2PRecipient{
+		Phone:      recipient.Phone,
+		MaskedName: maskName(recipient),
+	}, nil
+}
+
+// Transfer sends money to customer with RecipientPhone or to beneficiary with BeneficiaryID. Phone is looked up
+// again, so transfers are limited the same way as lookups. Large transfers are refused within cooling-off period
+// of beneficiary, large transfers by phone are allowed only when recipient is saved as beneficiary after its
+// cooling-off period. Transfer is saved with debit of sender and credit of recipient in one transaction.
+// Transfer is scored by risk assessment and refused when it is declined.
+func (s *P2PUseCase) Transfer(transfer *domain.P2PTransfer) error {
+	if transfer.Amount <= 0 {
+		return domain.NewValidationError("amount should be positive")
+	}
+	if transfer.BeneficiaryID != "" {
+		err := s.resolveBeneficiary(transfer)
+		if err != nil {
+			return err
+		}
+	}
+	sender, recipient, err := s.lookUp(transfer.SenderID, transfer.RecipientPhone)
+	if err != nil {
+		return err
+	}
+	if sender.Status != domain.CustomerStatusActive {
+		return domain.NewValidationError(fmt.Sprintf("%s customer could not send transfers", sender.Status))
+	}
+	if recipient.Status != domain.CustomerStatusActive {
+		return domain.NewValidationError("recipient could not receive transfers")
+	}
+	if transfer.BeneficiaryID == "" {
+		err = checkPayeeDetails(
+			s.beneficiaryRepo,
+			s.coolingOff,
+			transfer.SenderID,
+			transfer.Amount,
+			transfer.Currency,
+			&domain.Beneficiary{Type: domain.BeneficiaryTypePhone, Phone: recipient.Phone},
+			&domain.Beneficiary{Type: domain.BeneficiaryTypeCustomer, RecipientID: recipient.GeneratedID},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	assessment, err := s.risks.Authorize(
+		transfer.SenderID,
+		transfer.Amount,
+		transfer.Currency,
+		transfer.DeviceID,
+		transfer.IP,
+	)
+	if err != nil {
+		return err
+	}
+	transfer.RiskAssessmentID = assessment.GeneratedID
+	transfer.RiskDecision = assessment.Decision
+
+	now := time.Now()
+	transfer.GeneratedID, err = hash.GenerateUniqueP2PTransferID(transfer.SenderID, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	transfer.RecipientID = recipient.GeneratedID
+	transfer.RecipientMaskedName = maskName(recipient)
+	transfer.CreatedAt = now
+
+	debit := &domain.Debit{
+		PayerID:     transfer.SenderID,
+		PayeeID:     transfer.RecipientID,
+		Amount:      transfer.Amount,
+		Currency:    transfer.Currency,
+		Description: "Transfer " + transfer.GeneratedID,
+		Operation:   domain.MovementTypeTransfer,
+		Reference:   "p2p:" + transfer.GeneratedID,
+	}
+	err = s.debits.Prepare(debit)
+	if err != nil {
+		return err
+	}
+	err = s.repo.CreateTransfer(transfer, debit)
+	if err != nil {
+		return debitError(err)
+	}
+	s.debits.Monitor(debit)
+	return nil
+}
+
+func (s *P2PUseCase) FindTransfer(transferID string) (*domain.P2PTransfer, error) {
+	transfer, err := s.repo.FindTransferByID(transferID)
+	if err != nil {
+		return nil, err
+	}
+	if transfer == nil {
+		return nil, domain.NewNotFoundError("p2p transfer with such id not found")
+	}
+	return transfer, nil
+}
+
+// resolveBeneficiary sets phone of recipient saved as beneficiary, customer beneficiary is paid to its current phone
+func (s *P2PUseCase) resolveBeneficiary(transfer *domain.P2PTransfer) error {
+	payee, err := payableBeneficiary(
+		s.beneficiaryRepo,
+		s.coolingOff,
+		transfer.SenderID,
+		transfer.BeneficiaryID,
+		transfer.Amount,
+		transfer.Currency,
+		domain.BeneficiaryTypeCustomer,
+		domain.BeneficiaryTypePhone,
+	)
+	if err != nil {
+		return err
+	}
+	if payee.Type == domain.BeneficiaryTypePhone {
+		transfer.RecipientPhone = payee.Phone
+		return nil
+	}
+	recipient, err := s.customerRepo.FindByID(payee.RecipientID)
+	if err != nil {
+		return err
+	}
+	if recipient == nil {
+		return domain.NewValidationError("recipient could not receive transfers")
+	}
+	transfer.RecipientPhone = recipient.Phone
+	return nil
+}
+
+// lookUp finds sender and recipient with phone. Every lookup is recorded, found or not, and phones which
+// were not looked up within window of policy limit are refused once limit is reached.
+func (s *P2PUseCase) lookUp(senderID string, phone string) (*domain.Customer, *domain.Customer, error) {
+	sender, err := s.customerRepo.FindByID(senderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sender == nil {
+		return nil, nil, domain.NewNotFoundError("customer with such id not found")
+	}
+	if sender.Phone == phone {
+		return nil, nil, domain.NewValidationError("customer could not send transfer to own phone")
+	}
+
+	now := time.Now()
+	for _, limit := range s.policy.Limits {
+		count, err := s.repo.CountLookedUpPhones(senderID, phone, now.Add(-limit.Window))
+		if err != nil {
+			return nil, nil, err
+		}
+		if count >= limit.Phones {
+			return nil, nil, domain.NewTooManyRequestsError("too many phones looked up, try again later", limit.Window)
+		}
+	}
+
+	recipient, err := s.customerRepo.FindByPhone(phone)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = s.repo.CreatePhoneLookup(&domain.PhoneLookup{
+		CustomerID: senderID,
+		Phone:      phone,
+		Found:      recipient != nil,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if recipient == nil {
+		return nil, nil, domain.NewNotFoundError("customer with such phone not found")
+	}
+	return sender, recipient, nil
+}
+
+// maskName masks name of individual recipient, name of company is public and shown as is
+func maskName(recipient *domain.Customer) string {
+	if recipient.Type == domain.CustomerTypeLegalEntity {
+		return recipient.Company.Name
+	}
+	return p2p.MaskName(recipient.FirstName, recipient.LastName)
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/recurrence"
+)
+
+// executionLease is a time given to instance to execute claimed payment before other instance retries it
+const executionLease = 5 * time.Minute
+
+// executionRetryDelays are delays before retries of failed execution, execution fails after the last retry
+var executionRetryDelays = []time.Duration{5 * time.Minute, 30 * time.Minute, 2 * time.Hour}
+
+type PaymentScheduleUseCase struct {
+	repo         domain.PaymentScheduleRepository
+	customerRepo domain.CustomerRepository
+	debits       domain.DebitFlow
+}
+
+func NewPaymentScheduleUseCase(
+	repo domain.PaymentScheduleRepository,
+	customerRepo domain.CustomerRepository,
+	debits domain.DebitFlow,
+) *PaymentScheduleUseCase {
+	return &PaymentScheduleUseCase{repo: repo, customerRepo: customerRepo, debits: debits}
+}
+
+func (p *PaymentScheduleUseCase) Create(schedule *domain.PaymentSchedule) error {
+	customer, err := p.customerRepo.FindByID(schedule.CustomerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.NewNotFoundError("customer with such id not found")
+	}
+	if schedule.PayeeID == schedule.CustomerID {
+		return domain.NewValidationError("payee should differ from customer")
+	}
+	payee, err := p.customerRepo.FindByID(schedule.PayeeID)
+	if err != nil {
+		return err
+	}
+	if payee == nil {
+		return domain.NewValidationError("payee with such id not found")
+	}
+
+	now := time.Now()
+	if schedule.StartAt.IsZero() {
+		schedule.StartAt = now
+	}
+	rule, start, err := scheduleRecurrence(schedule)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+	schedule.NextRunAt = rule.Next(start, start.Add(-time.Nanosecond))
+	if !schedule.EndAt.IsZero() && schedule.NextRunAt.After(schedule.EndAt) {
+		return domain.NewValidationError("schedule has no payments before end date")
+	}
+
+	schedule.GeneratedID, err = hash.GenerateUniqueScheduleID(schedule.CustomerID, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	schedule.Status = domain.PaymentScheduleStatusActive
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+	return p.repo.Create(schedule)
+}
+
+func (p *PaymentScheduleUseCase) FindByCustomer(customerID string) ([]*domain.PaymentSchedule, error) {
+	schedules, err := p.repo.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (p *PaymentScheduleUseCase) Cancel(scheduleID string) error {
+	schedule, err := p.repo.FindByID(scheduleID)
+	if err != nil {
+		return err
+	}
+	if schedule == nil {
+		return domain.NewNotFoundError("schedule with such id not found")
+	}
+	if schedule.Status != domain.PaymentScheduleStatusActive {
+		return domain.NewValidationError(fmt.Sprintf("schedule is already %s", schedule.Status))
+	}
+
+	schedule.Status = domain.PaymentScheduleStatusCancelled
+	schedule.UpdatedAt = time.Now()
+	return p.repo.Update(schedule)
+}
+
+// Executions returns execution history of schedule, the latest first
+func (p *PaymentScheduleUseCase) Executions(scheduleID string) ([]*domain.ScheduleExecution, error) {
+	schedule, err := p.repo.FindByID(scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, domain.NewNotFoundError("schedule with such id not found")
+	}
+	return p.repo.FindExecutions(scheduleID)
+}
+
+// PlanDue creates executions of schedules which are due and moves schedules to the next occurrence.
+// Occurrences missed while no instance was running are planned one by one on next calls.
+func (p *PaymentScheduleUseCase) PlanDue(now time.Time, limit int) (int, error) {
+	return p.repo.ClaimDueSchedules(now, limit, func(schedule *domain.PaymentSchedule) *domain.ScheduleExecution {
+		schedule.UpdatedAt = now
+		rule, start, err := scheduleRecurrence(schedule)
+		if err != nil {
+			// recurrence is validated on creation, so it could only be broken by manual changes
+			schedule.Status = domain.PaymentScheduleStatusCancelled
+			return nil
+		}
+		executionID, err := hash.GenerateUniqueExecutionID(schedule.GeneratedID, schedule.NextRunAt.Unix())
+		if err != nil {
+			return nil
+		}
+
+		execution := &domain.ScheduleExecution{
+			GeneratedID:   executionID,
+			ScheduleID:    schedule.GeneratedID,
+			DueAt:         schedule.NextRunAt,
+			Status:        domain.ScheduleExecutionStatusPending,
+			NextAttemptAt: schedule.NextRunAt,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		schedule.NextRunAt = rule.Next(start, schedule.NextRunAt)
+		if !schedule.EndAt.IsZero() && schedule.NextRunAt.After(schedule.EndAt) {
+			schedule.Status = domain.PaymentScheduleStatusFinished
+		}
+		return execution
+	})
+}
+
+// ExecuteDue runs pending executions. Failed execution is retried after delay and fails after the last retry.
+func (p *PaymentScheduleUseCase) ExecuteDue(now time.Time, limit int) (int, error) {
+	executions, err := p.repo.ClaimExecutions(now, now.Add(executionLease), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, execution := range executions {
+		var schedule *domain.PaymentSchedule
+		schedule, err = p.repo.FindByID(execution.ScheduleID)
+		if err != nil {
+			return 0, err
+		}
+
+		execution.Attempts++
+		execution.UpdatedAt = time.Now()
+		switch {
+		case schedule == nil || schedule.Status == domain.PaymentScheduleStatusCancelled:
+			execution.Status = domain.ScheduleExecutionStatusFailed
+			execution.LastError = "schedule is cancelled"
+		default:
+			executeErr := p.execute(schedule, execution)
+			if executeErr == nil {
+				execution.Status = domain.ScheduleExecutionStatusSucceeded
+				execution.LastError = ""
+				break
+			}
+			execution.LastError = executeErr.Error()
+			if execution.Attempts > len(executionRetryDelays) {
+				execution.Status = domain.ScheduleExecutionStatusFailed
+				break
+			}
+			execution.NextAttemptAt = now.Add(executionRetryDelays[execution.Attempts-1])
+		}
+
+		err = p.repo.UpdateExecution(execution)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(executions), nil
+}
+
+// execute transfers amount of schedule to payee. Transfer is referenced by execution, so that retry
+// of execution which was transferred but not saved does not pay twice.
+func (p *PaymentScheduleUseCase) execute(schedule *domain.PaymentSchedule, execution *domain.ScheduleExecution) error {
+	return p.debits.Transfer(&domain.Debit{
+		PayerID:     schedule.CustomerID,
+		PayeeID:     schedule.PayeeID,
+		Amount:      schedule.Amount,
+		Currency:    schedule.Currency,
+		Description: "Standing order " + schedule.GeneratedID,
+		Operation:   domain.MovementTypeTransfer,
+		Reference:   "schedule_execution:" + execution.GeneratedID,
+	})
+}
+
+// scheduleRecurrence returns recurrence rule of schedule and schedule start in schedule timezone
+func scheduleRecurrence(schedule *domain.PaymentSchedule) (recurrence.Rule, time.Time, error) {
+	rule, err := recurrence.Parse(schedule.Recurrence)
+	if err != nil {
+		return recurrence.Rule{}, time.Time{}, err
+	}
+	location, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return recurrence.Rule{}, time.Time{}, fmt.Errorf("unknown timezone %s", schedule.Timezone)
+	}
+	return rule, schedule.StartAt.In(location), nil
+}
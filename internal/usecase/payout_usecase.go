@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/beneficiary"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/iso20022"
+)
+
+type PayoutUseCase struct {
+	repo            domain.PayoutRepository
+	customerRepo    domain.CustomerRepository
+	beneficiaryRepo domain.BeneficiaryRepository
+	debits          domain.DebitFlow
+	risks           *RiskUseCase
+	coolingOff      beneficiary.CoolingOffPolicy
+	debtor          iso20022.Party
+}
+
+// NewPayoutUseCase takes debtor which is a settlement account payouts are paid from
+func NewPayoutUseCase(
+	repo domain.PayoutRepository,
+	customerRepo domain.CustomerRepository,
+	beneficiaryRepo domain.BeneficiaryRepository,
+	debits domain.DebitFlow,
+	risks *RiskUseCase,
+	coolingOff beneficiary.CoolingOffPolicy,
+	debtor iso20022.Party,
+) *PayoutUseCase {
+	return &PayoutUseCase{
+		repo:            repo,
+		customerRepo:    customerRepo,
+		beneficiaryRepo: beneficiaryRepo,
+		debits:          debits,
+		risks:           risks,
+		coolingOff:      coolingOff,
+		debtor:          debtor,
+	}
+}
+
+// Create saves pending payout, it is submitted to bank with the next batch. Payout amount is withdrawn
+// from customer balance when payout is created and refunded when bank rejects payout. Payout to beneficiary
+// with BeneficiaryID is paid to its bank account. Large payouts are refused within cooling-off period
+// of beneficiary, large payouts by creditor details are allowed only when the IBAN is saved as beneficiary
+// after its cooling-off period. Payout is scored by risk assessment and refused when it is declined.
+func (s *PayoutUseCase) Create(payout *domain.Payout) error {
+	customer, err := s.customerRepo.FindByID(payout.CustomerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.NewNotFoundError("customer with such id not found")
+	}
+	if payout.BeneficiaryID != "" {
+		payee, err := payableBeneficiary(
+			s.beneficiaryRepo,
+			s.coolingOff,
+			payout.CustomerID,
+			payout.BeneficiaryID,
+			payout.Amount,
+			payout.Currency,
+			domain.BeneficiaryTypeBankAccount,
+		)
+		if err != nil {
+			return err
+		}
+		payout.CreditorName = payee.AccountName
+		payout.CreditorIBAN = payee.IBAN
+		payout.CreditorBIC = payee.BIC
+	} else {
+		err = checkPayeeDetails(
+			s.beneficiaryRepo,
+			s.coolingOff,
+			payout.CustomerID,
+			payout.Amount,
+			payout.Currency,
+			&domain.Beneficiary{Type: domain.BeneficiaryTypeBankAccount, IBAN: payout.CreditorIBAN},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	assessment, err := s.risks.Authorize(payout.CustomerID, payout.Amount, payout.Currency, payout.DeviceID, payout.IP)
+	if err != nil {
+		return err
+	}
+	payout.RiskAssessmentID = assessment.GeneratedID
+	payout.RiskDecision = assessment.Decision
+
+	now := time.Now()
+	payout.GeneratedID, err = hash.GenerateUniquePayoutID(payout.CustomerID, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	payout.Status = domain.PayoutStatusPending
+	payout.BatchID = ""
+	payout.PaymentInfoID = ""
+	payout.RejectReason = ""
+	payout.CreatedAt = now
+	payout.UpdatedAt = now
+	debit := &domain.Debit{
+		PayerID:     payout.CustomerID,
+		PayeeID:     domain.LedgerAccountPayouts,
+		Amount:      payout.Amount,
+		Currency:    payout.Currency,
+		Description: "Payout " + payout.GeneratedID,
+		Operation:   domain.MovementTypeWithdrawal,
+		Reference:   "payout:" + payout.GeneratedID,
+	}
+	err = s.debits.Prepare(debit)
+	if err != nil {
+		return err
+	}
+	err = s.repo.Create(payout, debit)
+	if err != nil {
+		return debitError(err)
+	}
+	s.debits.Monitor(debit)
+	return nil
+}
+
+func (s *PayoutUseCase) Find(payoutID string) (*domain.Payout, error) {
+	payout, err := s.repo.FindByID(payoutID)
+	if err != nil {
+		return nil, err
+	}
+	if payout == nil {
+		return nil, domain.NewNotFoundError("payout with such id not found")
+	}
+	return payout, nil
+}
+
+func (s *PayoutUseCase) FindByCustomer(customerID string) ([]*domain.Payout, error) {
+	payouts, err := s.repo.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return payouts, nil
+}
+
+func (s *PayoutUseCase) FindBatch(batchID string) (*domain.PayoutBatch, error) {
+	batch, err := s.repo.FindBatchByID(batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch == nil {
+		return nil, domain.NewNotFoundError("payout batch with such id not found")
+	}
+	return batch, nil
+}
+
+// SubmitPending puts up to limit pending payouts into pain.001 file and marks them submitted
+func (s *PayoutUseCase) SubmitPending(now time.Time, limit int) (int, error) {
+	return s.repo.SubmitPending(limit, func(payouts []*domain.Payout) (*domain.PayoutBatch, error) {
+		batchID, err := hash.GenerateUniquePayoutBatchID(now.UnixNano())
+		if err != nil {
+			return nil, err
+		}
+		batch := &domain.PayoutBatch{GeneratedID: batchID, NumberOfTransactions: len(payouts), CreatedAt: now}
+		for _, payout := range payouts {
+			payout.Status = domain.PayoutStatusSubmitted
+			payout.BatchID = batchID
+			payout.PaymentInfoID = iso20022.PaymentInfoID(batchID, payout.Currency)
+			payout.UpdatedAt = now
+			batch.ControlSum += payout.Amount
+		}
+
+		document := &bytes.Buffer{}
+		err = iso20022.GeneratePain001(document, batchID, now, s.debtor, payouts)
+		if err != nil {
+			return nil, err
+		}
+		batch.Document = document.Bytes()
+		return batch, nil
+	})
+}
+
+// ApplyStatusReport settles or rejects submitted payouts of batch by statuses of pain.002 report.
+// Payouts which status is already final are not changed, so the same report could be applied twice.
+func (s *PayoutUseCase) ApplyStatusReport(
+	batchID string,
+	updates []*domain.PayoutStatusUpdate,
+) (*domain.PayoutStatusReport, error) {
+	_, err := s.FindBatch(batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.PayoutStatusReport{BatchID: batchID}
+	now := time.Now()
+	for _, update := range updates {
+		updated, err := s.repo.UpdateSubmitted(update, now, s.refund)
+		if err != nil {
+			return nil, err
+		}
+		if update.Status == domain.PayoutStatusSettled {
+			report.Settled += updated
+		} else {
+			report.Rejected += updated
+		}
+	}
+	return report, nil
+}
+
+// refund returns withdrawn amount of rejected payout to customer
+func (s *PayoutUseCase) refund(payout *domain.Payout) (*domain.Debit, error) {
+	debit := &domain.Debit{
+		PayerID:     domain.LedgerAccountPayouts,
+		PayeeID:     payout.CustomerID,
+		Amount:      payout.Amount,
+		Currency:    payout.Currency,
+		Description: "Payout " + payout.GeneratedID + " refund",
+		Reference:   "payout:" + payout.GeneratedID + ":refund",
+	}
+	err := s.debits.Prepare(debit)
+	if err != nil {
+		return nil, err
+	}
+	return debit, nil
+}
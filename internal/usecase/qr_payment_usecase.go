@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/qrcode"
+	"github.com/yaroslavnayug/go-payment-system/internal/sbp"
+)
+
+const (
+	// DefaultDynamicQRTTL is a lifetime of dynamic QR payment request created without expiry
+	DefaultDynamicQRTTL = 72 * time.Hour
+	// qrModuleSize is a size of QR code module in pixels of PNG and in units of SVG
+	qrModuleSize = 8
+)
+
+type QRPaymentUseCase struct {
+	repo         domain.QRPaymentRepository
+	customerRepo domain.CustomerRepository
+	debits       domain.DebitFlow
+	risks        *RiskUseCase
+}
+
+func NewQRPaymentUseCase(
+	repo domain.QRPaymentRepository,
+	customerRepo domain.CustomerRepository,
+	debits domain.DebitFlow,
+	risks *RiskUseCase,
+) *QRPaymentUseCase {
+	return &QRPaymentUseCase{repo: repo, customerRepo: customerRepo, debits: debits, risks: risks}
+}
+
+// CreateRequest saves active payment request of merchant, dynamic request without expiry
+// expires after DefaultDynamicQRTTL
+func (s *QRPaymentUseCase) CreateRequest(request *domain.QRPaymentRequest) error {
+	merchant, err := s.customerRepo.FindByID(request.MerchantID)
+	if err != nil {
+		return err
+	}
+	if merchant == nil {
+		return domain.NewNotFoundError("customer with such id not found")
+	}
+
+	now := time.Now()
+	if request.Type == domain.QRPaymentRequestTypeDynamic && request.ExpiresAt.IsZero() {
+		request.ExpiresAt = now.Add(DefaultDynamicQRTTL)
+	}
+	if !request.ExpiresAt.IsZero() && !request.ExpiresAt.After(now) {
+		return domain.NewValidationError("expiry should be in the future")
+	}
+	request.GeneratedID, err = hash.GenerateUniqueQRPaymentRequestID(request.MerchantID, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	request.Status = domain.QRPaymentRequestStatusActive
+	request.PaidAt = time.Time{}
+	request.CreatedAt = now
+	request.UpdatedAt = now
+	return s.repo.CreateRequest(request)
+}
+
+// FindRequest finds payment request with its status at the moment
+func (s *QRPaymentUseCase) FindRequest(requestID string) (*domain.QRPaymentRequest, error) {
+	request, err := s.repo.FindRequestByID(requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, domain.NewNotFoundError("qr payment request with such id not found")
+	}
+	request.Status = request.StatusAt(time.Now())
+	return request, nil
+}
+
+func (s *QRPaymentUseCase) FindRequestsByMerchant(merchantID string) ([]*domain.QRPaymentRequest, error) {
+	requests, err := s.repo.FindRequestsByMerchantID(merchantID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, request := range requests {
+		request.Status = request.StatusAt(now)
+	}
+	return requests, nil
+}
+
+func (s *QRPaymentUseCase) FindPayments(requestID string) ([]*domain.QRPayment, error) {
+	_, err := s.FindRequest(requestID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.repo.FindPayments(requestID)
+	if err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// RenderPNG renders QR code of payment request payload
+func (s *QRPaymentUseCase) RenderPNG(requestID string) ([]byte, error) {
+	return s.render(requestID, (*qrcode.Code).WritePNG)
+}
+
+// RenderSVG renders QR code of payment request payload
+func (s *QRPaymentUseCase) RenderSVG(requestID string) ([]byte, error) {
+	return s.render(requestID, (*qrcode.Code).WriteSVG)
+}
+
+func (s *QRPaymentUseCase) render(
+	requestID string,
+	write func(code *qrcode.Code, w io.Writer, moduleSize int) error,
+) ([]byte, error) {
+	request, err := s.FindRequest(requestID)
+	if err != nil {
+		return nil, err
+	}
+	code, err := qrcode.Encode([]byte(sbp.FormatPayload(request)), qrcode.LevelM)
+	if err != nil {
+		return nil, err
+	}
+
+	var image bytes.Buffer
+	err = write(code, &image, qrModuleSize)
+	if err != nil {
+		return nil, err
+	}
+	return image.Bytes(), nil
+}
+
+// Pay pays request by payload scanned by payer. Amount is taken from request, payer enters amount only
+// for static request without amount. Amount is transferred from payer to merchant in the same transaction
+// which saves payment, so that payment is never saved without debit and credit of merchant.
+// Payment is scored by risk assessment with device and IP of payer and is refused when it is declined.
+func (s *QRPaymentUseCase) Pay(
+	payerID string,
+	payload *sbp.Payload,
+	amount int64,
+	deviceID string,
+	ip string,
+) (*domain.QRPayment, error) {
+	payer, err := s.customerRepo.FindByID(payerID)
+	if err != nil {
+		return nil, err
+	}
+	if payer == nil {
+		return nil, domain.NewNotFoundError("customer with such id not found")
+	}
+	request, err := s.FindRequest(payload.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Type != request.Type || payload.Amount != request.Amount || payload.Currency != request.Currency {
+		return nil, domain.NewValidationError("payload does not match qr payment request")
+	}
+	if request.Status != domain.QRPaymentRequestStatusActive {
+		return nil, domain.NewValidationError(fmt.Sprintf("%s qr payment request could not be paid", request.Status))
+	}
+	if request.MerchantID == payerID {
+		return nil, domain.NewValidationError("merchant could not pay own qr payment request")
+	}
+	if request.Amount > 0 {
+		if amount != 0 && amount != request.Amount {
+			return nil, domain.NewValidationError("amount does not match amount of qr payment request")
+		}
+		amount = request.Amount
+	}
+	if amount <= 0 {
+		return nil, domain.NewValidationError("amount should be positive")
+	}
+	assessment, err := s.risks.Authorize(payerID, amount, request.Currency, deviceID, ip)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	payment := &domain.QRPayment{
+		RequestID:        request.GeneratedID,
+		MerchantID:       request.MerchantID,
+		PayerID:          payerID,
+		Amount:           amount,
+		Currency:         request.Currency,
+		DeviceID:         deviceID,
+		IP:               ip,
+		RiskAssessmentID: assessment.GeneratedID,
+		RiskDecision:     assessment.Decision,
+		PaidAt:           now,
+	}
+	payment.GeneratedID, err = hash.GenerateUniqueQRPaymentID(request.GeneratedID, payerID, now.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+
+	debit := &domain.Debit{
+		PayerID:     payerID,
+		PayeeID:     request.MerchantID,
+		Amount:      amount,
+		Currency:    request.Currency,
+		Description: "QR payment " + payment.GeneratedID,
+		Operation:   domain.MovementTypeTransfer,
+		Reference:   qrPaymentReference(payment.GeneratedID),
+	}
+	err = s.debits.Prepare(debit)
+	if err != nil {
+		return nil, err
+	}
+
+	requestStatus := domain.QRPaymentRequestStatusActive
+	if request.Type == domain.QRPaymentRequestTypeDynamic {
+		requestStatus = domain.QRPaymentRequestStatusPaid
+	}
+	added, err := s.repo.AddPayment(payment, requestStatus, debit)
+	if err != nil {
+		return nil, debitError(err)
+	}
+	if !added {
+		return nil, domain.NewValidationError("qr payment request was paid or expired meanwhile")
+	}
+	s.debits.Monitor(debit)
+	return payment, nil
+}
+
+// qrPaymentReference identifies postings of qr payment
+func qrPaymentReference(paymentID string) string {
+	return "qr_payment:" + paymentID
+}
@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/reconciliation"
+)
+
+type ReconciliationUseCase struct {
+	repo    domain.ReconciliationRepository
+	matcher *reconciliation.Matcher
+	// settlementIBAN is an account payouts are paid from and card network settles with, only its statements
+	// are matched to movements
+	settlementIBAN string
+}
+
+func NewReconciliationUseCase(
+	repo domain.ReconciliationRepository,
+	matcher *reconciliation.Matcher,
+	settlementIBAN string,
+) *ReconciliationUseCase {
+	return &ReconciliationUseCase{repo: repo, matcher: matcher, settlementIBAN: settlementIBAN}
+}
+
+// Import saves parsed bank statements and auto-matches unmatched entries of settlement account per currency
+// of statements and their booking dates. Entries of other accounts are saved, but are never matched.
+// Statement which was imported before is reported as duplicate and its entries are skipped.
+func (s *ReconciliationUseCase) Import(statements []*domain.BankStatement) (*domain.BankStatementImport, error) {
+	result := &domain.BankStatementImport{Statements: statements}
+	var accounts []domain.BankAccount
+	periods := make(map[domain.BankAccount][2]time.Time)
+	now := time.Now()
+	for _, statement := range statements {
+		var err error
+		statement.GeneratedID, err = hash.GenerateUniqueBankStatementID(statement.AccountIBAN, statement.BankStatementID)
+		if err != nil {
+			return nil, err
+		}
+		statement.ImportedAt = now
+		account := domain.BankAccount{IBAN: statement.AccountIBAN, Currency: statement.Currency}
+		period, seen := periods[account]
+		if !seen && account.IBAN == s.settlementIBAN {
+			accounts = append(accounts, account)
+		}
+		for i, entry := range statement.Entries {
+			entry.GeneratedID, err = hash.GenerateUniqueBankEntryID(statement.GeneratedID, i+1)
+			if err != nil {
+				return nil, err
+			}
+			entry.StatementID = statement.GeneratedID
+			entry.CreatedAt = now
+			if period[0].IsZero() || entry.BookingDate.Before(period[0]) {
+				period[0] = entry.BookingDate
+			}
+			if entry.BookingDate.After(period[1]) {
+				period[1] = entry.BookingDate
+			}
+		}
+		periods[account] = period
+
+		created, err := s.repo.CreateStatement(statement)
+		if err != nil {
+			return nil, err
+		}
+		if !created {
+			result.Duplicates = append(result.Duplicates, statement.GeneratedID)
+		}
+	}
+
+	for _, account := range accounts {
+		if periods[account][0].IsZero() {
+			continue
+		}
+		matched, err := s.AutoMatch(account, periods[account][0], periods[account][1])
+		if err != nil {
+			return nil, err
+		}
+		result.Matched += matched
+	}
+	return result, nil
+}
+
+// AutoMatch matches unmatched bank entries of account booked between from and to dates inclusive to movements
+// in currency of account, returns number of matched entries
+func (s *ReconciliationUseCase) AutoMatch(account domain.BankAccount, from, to time.Time) (int, error) {
+	entries, err := s.repo.FindUnmatchedEntries(account, from, to)
+	if err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	movements, err := s.repo.FindUnmatchedMovements(
+		account.Currency,
+		from.Add(-reconciliation.DefaultDateTolerance),
+		to.AddDate(0, 0, 1).Add(reconciliation.DefaultDateTolerance),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	matched := 0
+	now := time.Now()
+	for _, match := range s.matcher.Match(entries, movements) {
+		ok, err := s.repo.Match(match.EntryID, match.MovementID, match.Type, now)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			matched++
+		}
+	}
+	return matched, nil
+}
+
+// Report lists bank entries of settlement account in currency booked between from and to dates inclusive
+// and movements in currency of the same days which are not matched to any bank entry
+func (s *ReconciliationUseCase) Report(currency string, from, to time.Time) (*domain.ReconciliationReport, error) {
+	entries, err := s.repo.FindEntries(domain.BankAccount{IBAN: s.settlementIBAN, Currency: currency}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	movements, err := s.repo.FindUnmatchedMovements(currency, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.ReconciliationReport{From: from, To: to, UnmatchedInternal: movements}
+	for _, entry := range entries {
+		if entry.MovementID == "" {
+			report.UnmatchedBank = append(report.UnmatchedBank, entry)
+		} else {
+			report.Matched = append(report.Matched, entry)
+		}
+	}
+	return report, nil
+}
+
+// MatchManually links bank entry to movement of the same amount, currency and direction
+func (s *ReconciliationUseCase) MatchManually(entryID string, movementID string) (*domain.BankEntry, error) {
+	entry, err := s.repo.FindEntryByID(entryID)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, domain.NewNotFoundError("bank entry with such id not found")
+	}
+	if entry.MovementID != "" {
+		return nil, domain.NewValidationError("bank entry is already matched")
+	}
+	movement, err := s.repo.FindMovementByID(movementID)
+	if err != nil {
+		return nil, err
+	}
+	if movement == nil {
+		return nil, domain.NewNotFoundError("movement with such id not found")
+	}
+	if !reconciliation.CanMatch(entry, movement) {
+		return nil, domain.NewValidationError("movement amount, currency or direction differs from bank entry")
+	}
+
+	now := time.Now()
+	ok, err := s.repo.Match(entryID, movementID, domain.BankEntryMatchTypeManual, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.NewValidationError("bank entry or movement is already matched")
+	}
+	entry.MovementID = movementID
+	entry.MatchType = domain.BankEntryMatchTypeManual
+	entry.MatchedAt = now
+	return entry, nil
+}
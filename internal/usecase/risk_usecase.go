@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/risk"
+)
+
+type RiskUseCase struct {
+	repo         domain.RiskRepository
+	customerRepo domain.CustomerRepository
+	scorer       *risk.Scorer
+}
+
+func NewRiskUseCase(
+	repo domain.RiskRepository,
+	customerRepo domain.CustomerRepository,
+	scorer *risk.Scorer,
+) *RiskUseCase {
+	return &RiskUseCase{repo: repo, customerRepo: customerRepo, scorer: scorer}
+}
+
+// Assess scores payment before authorization and stores the decision with contributing features.
+// Device and IP address of the payment are remembered as known for next assessments.
+func (r *RiskUseCase) Assess(
+	customerID string,
+	amount int64,
+	currency string,
+	deviceID string,
+	ip string,
+) (*domain.RiskAssessment, error) {
+	customer, err := r.customerRepo.FindByID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, domain.NewNotFoundError("customer with such id not found")
+	}
+
+	now := time.Now()
+	history, err := r.repo.FindAssessments(customerID, now.Add(-risk.HistoryWindow))
+	if err != nil {
+		return nil, err
+	}
+	devices, err := r.repo.FindDevices(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	input := risk.Input{
+		Customer: customer,
+		Amount:   amount,
+		Currency: currency,
+		DeviceID: deviceID,
+		History:  history,
+		Now:      now,
+	}
+	for _, device := range devices {
+		input.KnownDevice = input.KnownDevice || (deviceID != "" && device.DeviceID == deviceID)
+		input.KnownIP = input.KnownIP || (ip != "" && device.IP == ip)
+	}
+
+	assessmentID, err := hash.GenerateUniqueRiskAssessmentID(customerID, now.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	assessment := &domain.RiskAssessment{
+		GeneratedID: assessmentID,
+		CustomerID:  customerID,
+		Amount:      amount,
+		Currency:    currency,
+		DeviceID:    deviceID,
+		IP:          ip,
+		CreatedAt:   now,
+	}
+	assessment.Score, assessment.Decision, assessment.Features = r.scorer.Score(input)
+
+	err = r.repo.CreateAssessment(assessment)
+	if err != nil {
+		return nil, err
+	}
+	if deviceID != "" || ip != "" {
+		err = r.repo.SaveDevice(domain.RiskDevice{CustomerID: customerID, DeviceID: deviceID, IP: ip, LastSeenAt: now})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return assessment, nil
+}
+
+// Authorize assesses payment of customer before it is made. Declined payment is refused with validation error,
+// payment under review is made and keeps review decision of assessment for investigation.
+func (r *RiskUseCase) Authorize(
+	customerID string,
+	amount int64,
+	currency string,
+	deviceID string,
+	ip string,
+) (*domain.RiskAssessment, error) {
+	assessment, err := r.Assess(customerID, amount, currency, deviceID, ip)
+	if err != nil {
+		return nil, err
+	}
+	if assessment.Decision == domain.RiskDecisionDecline {
+		return nil, domain.NewValidationError(
+			fmt.Sprintf("payment is declined by risk assessment %s", assessment.GeneratedID),
+		)
+	}
+	return assessment, nil
+}
+
+func (r *RiskUseCase) Find(assessmentID string) (*domain.RiskAssessment, error) {
+	assessment, err := r.repo.FindAssessmentByID(assessmentID)
+	if err != nil {
+		return nil, err
+	}
+	return assessment, nil
+}
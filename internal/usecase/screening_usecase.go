@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+type ScreeningUseCase struct {
+	customerRepo domain.CustomerRepository
+	sanctionRepo domain.SanctionRepository
+}
+
+func NewScreeningUseCase(
+	customerRepo domain.CustomerRepository,
+	sanctionRepo domain.SanctionRepository,
+) *ScreeningUseCase {
+	return &ScreeningUseCase{customerRepo: customerRepo, sanctionRepo: sanctionRepo}
+}
+
+// ImportList replaces all entries of the list with given name
+func (s *ScreeningUseCase) ImportList(
+	listName string,
+	listType domain.SanctionListType,
+	entries []domain.SanctionEntry,
+) error {
+	if listName == "" {
+		return domain.NewValidationError("list name is mandatory")
+	}
+	if listType != domain.SanctionListTypeSanctions && listType != domain.SanctionListTypePEP {
+		return domain.NewValidationError("list type should be one of sanctions, pep")
+	}
+
+	for i := range entries {
+		entries[i].ListName = listName
+		entries[i].ListType = listType
+	}
+	return s.sanctionRepo.ReplaceList(listName, entries)
+}
+
+// Queue returns customers waiting for manual review of sanctions matches
+func (s *ScreeningUseCase) Queue() ([]*domain.Customer, error) {
+	customers, err := s.customerRepo.FindByStatus(domain.CustomerStatusPendingReview)
+	if err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+func (s *ScreeningUseCase) Matches(customerID string) ([]domain.SanctionMatch, error) {
+	matches, err := s.sanctionRepo.FindMatchesByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// Resolve applies reviewer decision to all unresolved matches of customer.
+// Cleared customer becomes active, confirmed one is blocked.
+func (s *ScreeningUseCase) Resolve(
+	customerID string,
+	decision domain.SanctionDecision,
+	reviewer string,
+	comment string,
+) error {
+	customer, err := s.customerRepo.FindByID(customerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.NewNotFoundError("customer with such id not found")
+	}
+	if customer.Status != domain.CustomerStatusPendingReview {
+		return domain.NewValidationError("customer is not waiting for review")
+	}
+
+	err = s.sanctionRepo.ResolveMatches(customerID, decision, reviewer, comment)
+	if err != nil {
+		return err
+	}
+
+	customer.Status = domain.CustomerStatusActive
+	if decision == domain.SanctionDecisionConfirmed {
+		customer.Status = domain.CustomerStatusBlocked
+	}
+	return s.customerRepo.Update(customer)
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+	"github.com/yaroslavnayug/go-payment-system/internal/split"
+)
+
+type SplitPaymentUseCase struct {
+	repo         domain.SplitPaymentRepository
+	customerRepo domain.CustomerRepository
+	debits       domain.DebitFlow
+	risks        *RiskUseCase
+}
+
+func NewSplitPaymentUseCase(
+	repo domain.SplitPaymentRepository,
+	customerRepo domain.CustomerRepository,
+	debits domain.DebitFlow,
+	risks *RiskUseCase,
+) *SplitPaymentUseCase {
+	return &SplitPaymentUseCase{repo: repo, customerRepo: customerRepo, debits: debits, risks: risks}
+}
+
+// Create splits payment among recipients and posts it in one transaction: payer account is debited with
+// payment amount with transfer fee and every recipient account is credited with its leg. Payment is refused
+// when balance of payer is insufficient or when risk assessment of payment declines it, LimitExceededError
+// is returned when payment breaches limits of payer.
+func (s *SplitPaymentUseCase) Create(payment *domain.SplitPayment) error {
+	err := s.checkActive(payment.PayerID)
+	if err != nil {
+		return err
+	}
+	err = split.Allocate(payment)
+	if err != nil {
+		return domain.NewValidationError(err.Error())
+	}
+	checked := map[string]bool{payment.PayerID: true}
+	for _, leg := range payment.Legs {
+		if checked[leg.RecipientID] {
+			continue
+		}
+		err = s.checkActive(leg.RecipientID)
+		if err != nil {
+			return err
+		}
+		checked[leg.RecipientID] = true
+	}
+	assessment, err := s.risks.Authorize(payment.PayerID, payment.Amount, payment.Currency, payment.DeviceID, payment.IP)
+	if err != nil {
+		return err
+	}
+	payment.RiskAssessmentID = assessment.GeneratedID
+	payment.RiskDecision = assessment.Decision
+
+	now := time.Now()
+	payment.GeneratedID, err = hash.GenerateUniqueSplitPaymentID(payment.PayerID, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	payment.CreatedAt = now
+	description := payment.Description
+	if description == "" {
+		description = "Split payment " + payment.GeneratedID
+	}
+	debit := &domain.Debit{
+		PayerID:     payment.PayerID,
+		Amount:      payment.Amount,
+		Currency:    payment.Currency,
+		Description: description,
+		Operation:   domain.MovementTypeTransfer,
+		Reference:   "split_payment:" + payment.GeneratedID,
+	}
+	for i := range payment.Legs {
+		payment.Legs[i].PaymentID = payment.GeneratedID
+		payment.Legs[i].Currency = payment.Currency
+		payment.Legs[i].CreatedAt = now
+		debit.Credits = append(debit.Credits, domain.DebitCredit{
+			PayeeID: payment.Legs[i].RecipientID,
+			Amount:  payment.Legs[i].Amount,
+		})
+	}
+	err = s.debits.Prepare(debit)
+	if err != nil {
+		return err
+	}
+	payment.Fee = debit.Fee
+
+	err = s.repo.Create(payment, debit)
+	if err != nil {
+		return debitError(err)
+	}
+	s.debits.Monitor(debit)
+	return nil
+}
+
+func (s *SplitPaymentUseCase) Find(paymentID string) (*domain.SplitPayment, error) {
+	payment, err := s.repo.FindByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, domain.NewNotFoundError("split payment with such id not found")
+	}
+	return payment, nil
+}
+
+// ReceivedSplits lists legs received by recipient in [from, to) with their totals by currency and role
+func (s *SplitPaymentUseCase) ReceivedSplits(
+	recipientID string,
+	from, to time.Time,
+) ([]*domain.SplitLeg, []domain.SplitTotal, error) {
+	legs, err := s.repo.FindReceivedLegs(recipientID, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var totals []domain.SplitTotal
+	index := make(map[[2]string]int)
+	for _, leg := range legs {
+		key := [2]string{leg.Currency, leg.Role}
+		i, ok := index[key]
+		if !ok {
+			i = len(totals)
+			index[key] = i
+			totals = append(totals, domain.SplitTotal{Currency: leg.Currency, Role: leg.Role})
+		}
+		totals[i].Amount += leg.Amount
+		totals[i].Count++
+	}
+	return legs, totals, nil
+}
+
+func (s *SplitPaymentUseCase) checkActive(customerID string) error {
+	customer, err := s.customerRepo.FindByID(customerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.NewNotFoundError(fmt.Sprintf("customer %s not found", customerID))
+	}
+	if customer.Status != domain.CustomerStatusActive {
+		return domain.NewValidationError(fmt.Sprintf("customer %s is %s", customerID, customer.Status))
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/statement"
+)
+
+type StatementUseCase struct {
+	postingRepo  domain.PostingRepository
+	customerRepo domain.CustomerRepository
+}
+
+func NewStatementUseCase(
+	postingRepo domain.PostingRepository,
+	customerRepo domain.CustomerRepository,
+) *StatementUseCase {
+	return &StatementUseCase{postingRepo: postingRepo, customerRepo: customerRepo}
+}
+
+// Open checks that statement could be written and finds its opening balance, so that errors are returned
+// before statement is streamed. From and To are dates in businessday.Location.
+func (s *StatementUseCase) Open(customerID string, currency string, from, to time.Time) (*domain.Statement, error) {
+	customer, err := s.customerRepo.FindByID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, domain.NewNotFoundError("customer with such id not found")
+	}
+
+	openingBalance, err := s.postingRepo.FindBalance(customerID, currency, from)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Statement{
+		CustomerID:     customerID,
+		CustomerName:   customer.Name(),
+		Currency:       currency,
+		From:           from,
+		To:             to,
+		OpeningBalance: openingBalance,
+	}, nil
+}
+
+// Write streams postings of opened statement to writer with running balance, the last day is included
+func (s *StatementUseCase) Write(accountStatement *domain.Statement, writer statement.Writer) error {
+	err := writer.WriteHeader(accountStatement)
+	if err != nil {
+		return err
+	}
+
+	balance := accountStatement.OpeningBalance
+	err = s.postingRepo.StreamPostings(
+		accountStatement.CustomerID,
+		accountStatement.Currency,
+		accountStatement.From,
+		accountStatement.To.AddDate(0, 0, 1),
+		func(posting *domain.Posting) error {
+			balance += posting.Amount
+			return writer.WritePosting(posting, balance)
+		},
+	)
+	if err != nil {
+		return err
+	}
+	return writer.WriteFooter(balance)
+}
@@ -0,0 +1,374 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/billing"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+)
+
+// chargeLease is a time given to instance to charge claimed invoice before other instance retries it
+const chargeLease = 5 * time.Minute
+
+type SubscriptionUseCase struct {
+	repo         domain.SubscriptionRepository
+	customerRepo domain.CustomerRepository
+	debits       domain.DebitFlow
+	dunning      billing.DunningPolicy
+}
+
+func NewSubscriptionUseCase(
+	repo domain.SubscriptionRepository,
+	customerRepo domain.CustomerRepository,
+	debits domain.DebitFlow,
+	dunning billing.DunningPolicy,
+) *SubscriptionUseCase {
+	return &SubscriptionUseCase{repo: repo, customerRepo: customerRepo, debits: debits, dunning: dunning}
+}
+
+func (s *SubscriptionUseCase) CreatePlan(plan *domain.Plan) error {
+	now := time.Now()
+	planID, err := hash.GenerateUniquePlanID(plan.Name, now.UnixNano())
+	if err != nil {
+		return err
+	}
+	plan.GeneratedID = planID
+	plan.CreatedAt = now
+	return s.repo.CreatePlan(plan)
+}
+
+func (s *SubscriptionUseCase) FindPlan(planID string) (*domain.Plan, error) {
+	plan, err := s.repo.FindPlanByID(planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, domain.NewNotFoundError("plan with such id not found")
+	}
+	return plan, nil
+}
+
+func (s *SubscriptionUseCase) FindPlans() ([]*domain.Plan, error) {
+	plans, err := s.repo.FindPlans()
+	if err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// Subscribe starts subscription with trial of the plan. Plan without trial is invoiced for the first period
+// at once, and the invoice is charged by worker.
+func (s *SubscriptionUseCase) Subscribe(subscription *domain.Subscription) error {
+	customer, err := s.customerRepo.FindByID(subscription.CustomerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return domain.NewNotFoundError("customer with such id not found")
+	}
+	plan, err := s.repo.FindPlanByID(subscription.PlanID)
+	if err != nil {
+		return err
+	}
+	if plan == nil {
+		return domain.NewValidationError("plan with such id not found")
+	}
+
+	now := time.Now()
+	subscription.GeneratedID, err = hash.GenerateUniqueSubscriptionID(
+		subscription.CustomerID,
+		subscription.PlanID,
+		now.UnixNano(),
+	)
+	if err != nil {
+		return err
+	}
+	subscription.CurrentPeriodStart = now
+	subscription.CreatedAt = now
+	subscription.UpdatedAt = now
+
+	if plan.TrialDays > 0 {
+		subscription.Status = domain.SubscriptionStatusTrialing
+		subscription.TrialEnd = now.AddDate(0, 0, plan.TrialDays)
+		subscription.BillingAnchor = subscription.TrialEnd
+		subscription.CurrentPeriodEnd = subscription.TrialEnd
+		return s.repo.CreateSubscription(subscription, nil)
+	}
+
+	subscription.Status = domain.SubscriptionStatusActive
+	subscription.BillingAnchor = now
+	subscription.CurrentPeriodEnd = billing.PeriodEnd(plan, now, now)
+	invoice, err := newSubscriptionInvoice(subscription, plan, now)
+	if err != nil {
+		return err
+	}
+	return s.repo.CreateSubscription(subscription, invoice)
+}
+
+func (s *SubscriptionUseCase) Find(subscriptionID string) (*domain.Subscription, error) {
+	subscription, err := s.repo.FindSubscriptionByID(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, domain.NewNotFoundError("subscription with such id not found")
+	}
+	return subscription, nil
+}
+
+func (s *SubscriptionUseCase) FindByCustomer(customerID string) ([]*domain.Subscription, error) {
+	subscriptions, err := s.repo.FindSubscriptionsByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// ChangePlan moves subscription to another plan at once. Difference between prices for the rest
+// of current period is added to the next invoice, downgrade gives a credit. Plan with another interval
+// starts a new billing cycle from the end of current period.
+func (s *SubscriptionUseCase) ChangePlan(subscriptionID string, planID string) (*domain.Subscription, error) {
+	subscription, err := s.Find(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	oldPlan, err := s.repo.FindPlanByID(subscription.PlanID)
+	if err != nil {
+		return nil, err
+	}
+	newPlan, err := s.repo.FindPlanByID(planID)
+	if err != nil {
+		return nil, err
+	}
+	if oldPlan == nil || newPlan == nil {
+		return nil, domain.NewValidationError("plan with such id not found")
+	}
+	if newPlan.Currency != oldPlan.Currency {
+		return nil, domain.NewValidationError("plan currency should match subscription currency")
+	}
+
+	now := time.Now()
+	subscription, err = s.repo.UpdateSubscription(subscriptionID, func(subscription *domain.Subscription) error {
+		switch {
+		case subscription.Status == domain.SubscriptionStatusCancelled:
+			return domain.NewValidationError("subscription is cancelled")
+		case subscription.Status == domain.SubscriptionStatusPastDue:
+			return domain.NewValidationError("subscription is past due")
+		case subscription.PlanID != oldPlan.GeneratedID:
+			return domain.NewValidationError("subscription plan was changed meanwhile")
+		case subscription.PlanID == newPlan.GeneratedID:
+			return domain.NewValidationError("subscription is already on this plan")
+		}
+
+		if subscription.Status == domain.SubscriptionStatusActive {
+			subscription.PendingProration += billing.Prorate(
+				oldPlan.Amount,
+				newPlan.Amount,
+				subscription.CurrentPeriodStart,
+				subscription.CurrentPeriodEnd,
+				now,
+			)
+		}
+		if newPlan.Interval != oldPlan.Interval || newPlan.IntervalCount != oldPlan.IntervalCount {
+			subscription.BillingAnchor = subscription.CurrentPeriodEnd
+		}
+		subscription.PlanID = newPlan.GeneratedID
+		subscription.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, domain.NewNotFoundError("subscription with such id not found")
+	}
+	return subscription, nil
+}
+
+// Cancel stops subscription at once or at the end of current period.
+// Open invoices of cancelled subscription are voided on their next charge attempt.
+func (s *SubscriptionUseCase) Cancel(subscriptionID string, atPeriodEnd bool) (*domain.Subscription, error) {
+	now := time.Now()
+	subscription, err := s.repo.UpdateSubscription(subscriptionID, func(subscription *domain.Subscription) error {
+		if subscription.Status == domain.SubscriptionStatusCancelled {
+			return domain.NewValidationError("subscription is already cancelled")
+		}
+		if atPeriodEnd {
+			subscription.CancelAtPeriodEnd = true
+		} else {
+			subscription.Status = domain.SubscriptionStatusCancelled
+			subscription.CancelledAt = now
+		}
+		subscription.UpdatedAt = now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, domain.NewNotFoundError("subscription with such id not found")
+	}
+	return subscription, nil
+}
+
+// Invoices returns invoices of subscription, the latest period first
+func (s *SubscriptionUseCase) Invoices(subscriptionID string) ([]*domain.SubscriptionInvoice, error) {
+	_, err := s.Find(subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindInvoices(subscriptionID)
+}
+
+// RenewDue moves subscriptions which current period is over to the next period and invoices it.
+// Trial ends with the first invoice, subscriptions cancelled at period end are cancelled instead.
+func (s *SubscriptionUseCase) RenewDue(now time.Time, limit int) (int, error) {
+	renew := func(subscription *domain.Subscription) (*domain.SubscriptionInvoice, error) {
+		subscription.UpdatedAt = now
+		if subscription.CancelAtPeriodEnd {
+			subscription.Status = domain.SubscriptionStatusCancelled
+			subscription.CancelledAt = subscription.CurrentPeriodEnd
+			return nil, nil
+		}
+
+		plan, err := s.repo.FindPlanByID(subscription.PlanID)
+		if err != nil {
+			return nil, err
+		}
+		if plan == nil {
+			return nil, fmt.Errorf("plan %s of subscription %s not found", subscription.PlanID, subscription.GeneratedID)
+		}
+
+		if subscription.Status == domain.SubscriptionStatusTrialing {
+			subscription.Status = domain.SubscriptionStatusActive
+		}
+		subscription.CurrentPeriodStart = subscription.CurrentPeriodEnd
+		subscription.CurrentPeriodEnd = billing.PeriodEnd(plan, subscription.BillingAnchor, subscription.CurrentPeriodStart)
+		return newSubscriptionInvoice(subscription, plan, now)
+	}
+	return s.repo.ClaimDueSubscriptions(now, limit, renew)
+}
+
+// ChargeDue charges open invoices. Failed charge is retried by dunning policy while subscription is past due,
+// invoice becomes uncollectible and subscription is cancelled when grace period is over.
+func (s *SubscriptionUseCase) ChargeDue(now time.Time, limit int) (int, error) {
+	invoices, err := s.repo.ClaimInvoices(now, now.Add(chargeLease), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, invoice := range invoices {
+		var subscription *domain.Subscription
+		subscription, err = s.repo.FindSubscriptionByID(invoice.SubscriptionID)
+		if err != nil {
+			return 0, err
+		}
+
+		invoice.UpdatedAt = time.Now()
+		if subscription == nil || subscription.Status == domain.SubscriptionStatusCancelled {
+			invoice.Status = domain.SubscriptionInvoiceStatusVoid
+			err = s.repo.UpdateInvoice(invoice, nil)
+			if err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		invoice.Attempts++
+		subscription.UpdatedAt = invoice.UpdatedAt
+		chargeErr := s.charge(invoice)
+		switch {
+		case chargeErr == nil:
+			invoice.Status = domain.SubscriptionInvoiceStatusPaid
+			invoice.LastError = ""
+			if subscription.Status == domain.SubscriptionStatusPastDue {
+				subscription.Status = domain.SubscriptionStatusActive
+			}
+		default:
+			invoice.LastError = chargeErr.Error()
+			nextAttempt, retry := s.dunning.NextAttempt(invoice.DueAt, now, invoice.Attempts)
+			if retry {
+				invoice.NextAttemptAt = nextAttempt
+				subscription.Status = domain.SubscriptionStatusPastDue
+				break
+			}
+			invoice.Status = domain.SubscriptionInvoiceStatusUncollectible
+			subscription.Status = domain.SubscriptionStatusCancelled
+			subscription.CancelledAt = now
+		}
+
+		err = s.repo.UpdateInvoice(invoice, subscription)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(invoices), nil
+}
+
+// newSubscriptionInvoice bills current period of subscription by plan together with pending proration.
+// Credit exceeding plan amount is carried to the next invoice, invoice with nothing to pay is paid at once.
+func newSubscriptionInvoice(
+	subscription *domain.Subscription,
+	plan *domain.Plan,
+	now time.Time,
+) (*domain.SubscriptionInvoice, error) {
+	invoiceID, err := hash.GenerateUniqueSubscriptionInvoiceID(
+		subscription.GeneratedID,
+		subscription.CurrentPeriodStart.Unix(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := &domain.SubscriptionInvoice{
+		GeneratedID:    invoiceID,
+		SubscriptionID: subscription.GeneratedID,
+		CustomerID:     subscription.CustomerID,
+		PeriodStart:    subscription.CurrentPeriodStart,
+		PeriodEnd:      subscription.CurrentPeriodEnd,
+		Items:          []domain.SubscriptionInvoiceItem{{Description: plan.Name, Amount: plan.Amount}},
+		Amount:         plan.Amount,
+		Currency:       plan.Currency,
+		Status:         domain.SubscriptionInvoiceStatusOpen,
+		DueAt:          subscription.CurrentPeriodStart,
+		NextAttemptAt:  subscription.CurrentPeriodStart,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if subscription.PendingProration != 0 {
+		invoice.Items = append(invoice.Items, domain.SubscriptionInvoiceItem{
+			Description: "proration for plan change",
+			Amount:      subscription.PendingProration,
+		})
+		invoice.Amount += subscription.PendingProration
+		subscription.PendingProration = 0
+	}
+	if invoice.Amount < 0 {
+		invoice.Items = append(invoice.Items, domain.SubscriptionInvoiceItem{
+			Description: "credit carried to next invoice",
+			Amount:      -invoice.Amount,
+		})
+		subscription.PendingProration = invoice.Amount
+		invoice.Amount = 0
+	}
+	if invoice.Amount == 0 {
+		invoice.Status = domain.SubscriptionInvoiceStatusPaid
+	}
+	return invoice, nil
+}
+
+// charge transfers invoice amount from customer balance to subscriptions account. Transfer is referenced
+// by invoice, so that retry of invoice which was charged but not saved does not charge twice.
+func (s *SubscriptionUseCase) charge(invoice *domain.SubscriptionInvoice) error {
+	return s.debits.Transfer(&domain.Debit{
+		PayerID:     invoice.CustomerID,
+		PayeeID:     domain.LedgerAccountSubscriptions,
+		Amount:      invoice.Amount,
+		Currency:    invoice.Currency,
+		Description: "Subscription " + invoice.SubscriptionID,
+		Operation:   domain.MovementTypeTransfer,
+		Reference:   "subscription_invoice:" + invoice.GeneratedID,
+	})
+}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+	"github.com/yaroslavnayug/go-payment-system/internal/hash"
+)
+
+type VerificationUseCase struct {
+	repo         domain.VerificationRepository
+	customerRepo domain.CustomerRepository
+	checks       []domain.VerificationCheck
+}
+
+func NewVerificationUseCase(
+	repo domain.VerificationRepository,
+	customerRepo domain.CustomerRepository,
+	checks []domain.VerificationCheck,
+) *VerificationUseCase {
+	return &VerificationUseCase{repo: repo, customerRepo: customerRepo, checks: checks}
+}
+
+// Submit opens verification case for customer or resubmits the case which needs more info.
+// Case which fails automated checks goes to needs_more_info, otherwise it waits for reviewer in submitted status.
+func (v *VerificationUseCase) Submit(customerID string) (*domain.Verification, error) {
+	customer, err := v.customerRepo.FindByID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, domain.NewNotFoundError("customer with such id not found")
+	}
+	if customer.Type == domain.CustomerTypeLegalEntity {
+		return nil, domain.NewValidationError("legal entity is verified through its representatives")
+	}
+
+	verification, err := v.repo.FindLastByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	isNew := verification == nil
+	if isNew {
+		var verificationID string
+		verificationID, err = hash.GenerateUniqueVerificationID(customerID, time.Now().UnixNano())
+		if err != nil {
+			return nil, err
+		}
+		verification = &domain.Verification{
+			GeneratedID: verificationID,
+			CustomerID:  customerID,
+			Level:       domain.VerificationLevelNone,
+			CreatedAt:   time.Now(),
+		}
+	} else if !verification.CanTransitionTo(domain.VerificationStatusSubmitted) {
+		return nil, domain.NewValidationError(
+			fmt.Sprintf("verification could not be submitted in status %s", verification.Status),
+		)
+	}
+
+	verification.Status = domain.VerificationStatusSubmitted
+	verification.Reviewer = ""
+	verification.Comment = ""
+	verification.UpdatedAt = time.Now()
+	v.runChecks(verification, customer)
+
+	if isNew {
+		err = v.repo.Create(verification)
+	} else {
+		err = v.repo.Update(verification)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return verification, nil
+}
+
+func (v *VerificationUseCase) Find(customerID string) (*domain.Verification, error) {
+	verification, err := v.repo.FindLastByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+	return verification, nil
+}
+
+// Review applies reviewer decision to verification case
+func (v *VerificationUseCase) Review(
+	verificationID string,
+	status domain.VerificationStatus,
+	level domain.VerificationLevel,
+	reviewer string,
+	comment string,
+) error {
+	verification, err := v.repo.FindByID(verificationID)
+	if err != nil {
+		return err
+	}
+	if verification == nil {
+		return domain.NewNotFoundError("verification with such id not found")
+	}
+	if status == domain.VerificationStatusSubmitted || !verification.CanTransitionTo(status) {
+		return domain.NewValidationError(
+			fmt.Sprintf("verification could not be moved from %s to %s", verification.Status, status),
+		)
+	}
+
+	verification.Status = status
+	verification.Level = domain.VerificationLevelNone
+	if status == domain.VerificationStatusApproved {
+		verification.Level = level
+	}
+	verification.Reviewer = reviewer
+	verification.Comment = comment
+	verification.UpdatedAt = time.Now()
+
+	err = v.repo.Update(verification)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// EnsureCanMoveMoney should be called by every money movement before touching balances
+func (v *VerificationUseCase) EnsureCanMoveMoney(customerID string) error {
+	customer, err := v.customerRepo.FindByID(customerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil || customer.Status != domain.CustomerStatusActive {
+		return domain.NewValidationError("customer is not active")
+	}
+
+	_, approved, err := approvedVerificationLevel(v.repo, customer)
+	if err != nil {
+		return err
+	}
+	if !approved {
+		return domain.NewValidationError("customer is not verified")
+	}
+	return nil
+}
+
+// approvedVerificationLevel returns level of approved verification of customer. Legal entity is not verified
+// itself, it is approved when one of its representatives is approved and gets the highest level of them.
+func approvedVerificationLevel(
+	repo domain.VerificationRepository,
+	customer *domain.Customer,
+) (level domain.VerificationLevel, approved bool, err error) {
+	level = domain.VerificationLevelNone
+	if customer == nil {
+		return level, false, nil
+	}
+	customerIDs := []string{customer.GeneratedID}
+	if customer.Type == domain.CustomerTypeLegalEntity {
+		customerIDs = customerIDs[:0]
+		for _, representative := range customer.Company.Representatives {
+			customerIDs = append(customerIDs, representative.CustomerID)
+		}
+	}
+
+	for _, customerID := range customerIDs {
+		verification, err := repo.FindLastByCustomerID(customerID)
+		if err != nil {
+			return level, false, err
+		}
+		if verification == nil || verification.Status != domain.VerificationStatusApproved {
+			continue
+		}
+		if !approved || verification.Level.Exceeds(level) {
+			level = verification.Level
+		}
+		approved = true
+	}
+	return level, approved, nil
+}
+
+func (v *VerificationUseCase) runChecks(verification *domain.Verification, customer *domain.Customer) {
+	verification.Checks = make([]domain.VerificationCheckResult, 0, len(v.checks))
+	var failures []string
+	for _, check := range v.checks {
+		result := check.Check(customer)
+		verification.Checks = append(verification.Checks, result)
+		if !result.Passed {
+			failures = append(failures, result.Reason)
+		}
+	}
+
+	if len(failures) > 0 {
+		verification.Status = domain.VerificationStatusNeedsMoreInfo
+		verification.Comment = strings.Join(failures, "; ")
+	}
+}
@@ -0,0 +1,101 @@
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+// KeyLength is a length of AES-256 key vault encrypts secrets with
+const KeyLength = 32
+
+const (
+	blobKeyPrefix = "cards/"
+	// fingerprintContext separates fingerprint key from encryption key derived from the same vault key
+	fingerprintContext = "card-pan-fingerprint"
+)
+
+// ErrInvalidKey is returned for vault keys which are not AES-256 keys
+var ErrInvalidKey = errors.New("vault key should be 32 bytes")
+
+// Vault keeps card secrets in blob store encrypted with AES-256-GCM, ciphertext is bound to card id
+type Vault struct {
+	store          domain.BlobStore
+	aead           cipher.AEAD
+	fingerprintKey []byte
+}
+
+func NewVault(store domain.BlobStore, key []byte) (*Vault, error) {
+	if len(key) != KeyLength {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(fingerprintContext))
+	return &Vault{store: store, aead: aead, fingerprintKey: mac.Sum(nil)}, nil
+}
+
+// Put encrypts secrets with random nonce which is prepended to ciphertext
+func (v *Vault) Put(cardID string, secrets *domain.CardSecrets) error {
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, v.aead.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return err
+	}
+	return v.store.Put(blobKeyPrefix+cardID, v.aead.Seal(nonce, nonce, plaintext, []byte(cardID)))
+}
+
+func (v *Vault) Get(cardID string) (*domain.CardSecrets, error) {
+	sealed, err := v.store.Get(blobKeyPrefix + cardID)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < v.aead.NonceSize() {
+		return nil, errors.New("card secrets are corrupted")
+	}
+	nonce, ciphertext := sealed[:v.aead.NonceSize()], sealed[v.aead.NonceSize():]
+	plaintext, err := v.aead.Open(nil, nonce, ciphertext, []byte(cardID))
+	if err != nil {
+		return nil, err
+	}
+	secrets := &domain.CardSecrets{}
+	err = json.Unmarshal(plaintext, secrets)
+	if err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+func (v *Vault) Delete(cardID string) error {
+	return v.store.Delete(blobKeyPrefix + cardID)
+}
+
+// Fingerprint is HMAC-SHA256 of pan, so that PAN could not be brute forced from card records without vault key
+func (v *Vault) Fingerprint(pan string) string {
+	mac := hmac.New(sha256.New, v.fingerprintKey)
+	_, _ = mac.Write([]byte(pan))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,62 @@
+package vault
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaroslavnayug/go-payment-system/internal/blob"
+	"github.com/yaroslavnayug/go-payment-system/internal/domain"
+)
+
+func TestVault_PutGetDelete(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "vault")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	store, err := blob.NewFileSystemStore(dir)
+	assert.NoError(t, err)
+	vault, err := NewVault(store, bytes.Repeat([]byte{1}, KeyLength))
+	assert.NoError(t, err)
+
+	secrets := &domain.CardSecrets{PAN: "2200120000001234", CVV: "123"}
+	assert.NoError(t, vault.Put("card", secrets))
+
+	sealed, err := store.Get("cards/card")
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(sealed, []byte(secrets.PAN)))
+
+	found, err := vault.Get("card")
+	assert.NoError(t, err)
+	assert.Equal(t, secrets, found)
+
+	// ciphertext of one card could not be read as secrets of another one
+	assert.NoError(t, store.Put("cards/other", sealed))
+	_, err = vault.Get("other")
+	assert.Error(t, err)
+
+	assert.NoError(t, vault.Delete("card"))
+	found, err = vault.Get("card")
+	assert.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestVault_Fingerprint(t *testing.T) {
+	t.Parallel()
+
+	vault, err := NewVault(nil, bytes.Repeat([]byte{1}, KeyLength))
+	assert.NoError(t, err)
+	other, err := NewVault(nil, bytes.Repeat([]byte{2}, KeyLength))
+	assert.NoError(t, err)
+
+	assert.Len(t, vault.Fingerprint("2200120000001234"), 64)
+	assert.Equal(t, vault.Fingerprint("2200120000001234"), vault.Fingerprint("2200120000001234"))
+	assert.NotEqual(t, vault.Fingerprint("2200120000001234"), vault.Fingerprint("2200120000001242"))
+	assert.NotEqual(t, vault.Fingerprint("2200120000001234"), other.Fingerprint("2200120000001234"))
+
+	_, err = NewVault(nil, []byte("short"))
+	assert.Equal(t, ErrInvalidKey, err)
+}
@@ -11,13 +11,736 @@ CREATE TABLE IF NOT EXISTS customer (
     city character varying(64) NOT NULL,
     street character varying(64) NOT NULL,
     building character varying(10) NOT NULL,
-    passportnumber character varying(10) NOT NULL UNIQUE,
+    passportnumber character varying(10) NOT NULL,
 	passportissuedate date NOT NULL,
 	passportissuer character varying(255) NOT NULL,
 	birthdate date NOT NULL default NOW(),
-	birthplace character varying(64) NOT NULL
+	birthplace character varying(64) NOT NULL,
+	type character varying(16) NOT NULL DEFAULT 'individual',
+	companyname character varying(255) NOT NULL DEFAULT '',
+	inn character varying(10) NOT NULL DEFAULT '',
+	kpp character varying(9) NOT NULL DEFAULT '',
+	ogrn character varying(13) NOT NULL DEFAULT '',
+	beneficialowners jsonb NOT NULL DEFAULT '[]',
+	representatives jsonb NOT NULL DEFAULT '[]',
+	status character varying(32) NOT NULL DEFAULT 'active',
+	createdat timestamp with time zone NOT NULL DEFAULT NOW()
 );
 
 CREATE INDEX customer_uid_idx ON customer USING btree (uid);
 
-CREATE INDEX customer_passportnumber_idx ON customer USING btree (passportnumber);
+CREATE UNIQUE INDEX customer_passportnumber_idx ON customer USING btree (passportnumber)
+    WHERE type = 'individual';
+
+CREATE UNIQUE INDEX customer_ogrn_idx ON customer USING btree (ogrn) WHERE type = 'legal_entity';
+
+CREATE UNIQUE INDEX customer_phone_idx ON customer USING btree (phone);
+
+CREATE INDEX customer_status_idx ON customer USING btree (status);
+
+CREATE TABLE IF NOT EXISTS verification (
+    uid character varying(64) NOT NULL UNIQUE,
+    customeruid character varying(64) NOT NULL,
+    status character varying(32) NOT NULL,
+    level character varying(32) NOT NULL,
+    checks jsonb NOT NULL DEFAULT '[]',
+    reviewer character varying(64) NOT NULL DEFAULT '',
+    comment text NOT NULL DEFAULT '',
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX verification_customeruid_idx ON verification USING btree (customeruid, createdat);
+
+CREATE TABLE IF NOT EXISTS sanction_entry (
+    listname character varying(64) NOT NULL,
+    listtype character varying(32) NOT NULL,
+    fullname character varying(255) NOT NULL,
+    birthdate date
+);
+
+CREATE INDEX sanction_entry_listname_idx ON sanction_entry USING btree (listname);
+
+CREATE INDEX sanction_entry_birthdate_idx ON sanction_entry USING btree (birthdate);
+
+CREATE TABLE IF NOT EXISTS sanction_match (
+    customeruid character varying(64) NOT NULL,
+    listname character varying(64) NOT NULL,
+    listtype character varying(32) NOT NULL,
+    entryname character varying(255) NOT NULL,
+    entrybirthdate date,
+    matchedname character varying(130) NOT NULL,
+    score double precision NOT NULL,
+    decision character varying(32) NOT NULL DEFAULT '',
+    reviewer character varying(64) NOT NULL DEFAULT '',
+    comment text NOT NULL DEFAULT '',
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    reviewedat timestamp with time zone
+);
+
+CREATE INDEX sanction_match_customeruid_idx ON sanction_match USING btree (customeruid);
+
+CREATE TABLE IF NOT EXISTS monitored_movement (
+    uid character varying(64) NOT NULL UNIQUE,
+    customeruid character varying(64) NOT NULL,
+    type character varying(32) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX monitored_movement_customeruid_idx ON monitored_movement USING btree (customeruid, createdat);
+
+CREATE TABLE IF NOT EXISTS monitoring_alert (
+    uid character varying(64) NOT NULL UNIQUE,
+    customeruid character varying(64) NOT NULL,
+    movementuid character varying(64) NOT NULL,
+    rulename character varying(128) NOT NULL,
+    ruletype character varying(32) NOT NULL,
+    severity character varying(32) NOT NULL DEFAULT '',
+    details text NOT NULL DEFAULT '',
+    status character varying(32) NOT NULL,
+    resolution character varying(32) NOT NULL DEFAULT '',
+    assignee character varying(64) NOT NULL DEFAULT '',
+    comment text NOT NULL DEFAULT '',
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX monitoring_alert_status_idx ON monitoring_alert USING btree (status, createdat);
+
+CREATE TABLE IF NOT EXISTS risk_assessment (
+    uid character varying(64) NOT NULL UNIQUE,
+    customeruid character varying(64) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    deviceid character varying(128) NOT NULL DEFAULT '',
+    ip character varying(45) NOT NULL DEFAULT '',
+    score integer NOT NULL,
+    decision character varying(32) NOT NULL,
+    features jsonb NOT NULL DEFAULT '[]',
+    createdat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX risk_assessment_customeruid_idx ON risk_assessment USING btree (customeruid, createdat);
+
+CREATE TABLE IF NOT EXISTS risk_device (
+    customeruid character varying(64) NOT NULL,
+    deviceid character varying(128) NOT NULL,
+    ip character varying(45) NOT NULL,
+    lastseenat timestamp with time zone NOT NULL DEFAULT NOW(),
+    UNIQUE (customeruid, deviceid, ip)
+);
+
+CREATE TABLE IF NOT EXISTS limit_tier (
+    level character varying(32) NOT NULL,
+    currency character varying(3) NOT NULL,
+    pertransaction bigint NOT NULL,
+    daily bigint NOT NULL,
+    monthly bigint NOT NULL,
+    dailycount bigint NOT NULL,
+    monthlycount bigint NOT NULL,
+    PRIMARY KEY (level, currency)
+);
+
+INSERT INTO limit_tier (level, currency, pertransaction, daily, monthly, dailycount, monthlycount) VALUES
+    ('none', 'RUB', 0, 0, 0, 0, 0),
+    ('none', 'USD', 0, 0, 0, 0, 0),
+    ('none', 'EUR', 0, 0, 0, 0, 0),
+    ('basic', 'RUB', 1500000, 1500000, 4000000, 50, 300),
+    ('basic', 'USD', 20000, 20000, 55000, 50, 300),
+    ('basic', 'EUR', 20000, 20000, 55000, 50, 300),
+    ('full', 'RUB', 60000000, 60000000, 600000000, 200, 2000),
+    ('full', 'USD', 800000, 800000, 8000000, 200, 2000),
+    ('full', 'EUR', 800000, 800000, 8000000, 200, 2000);
+
+CREATE TABLE IF NOT EXISTS limit_customer (
+    customeruid character varying(64) NOT NULL,
+    currency character varying(3) NOT NULL,
+    pertransaction bigint NOT NULL,
+    daily bigint NOT NULL,
+    monthly bigint NOT NULL,
+    dailycount bigint NOT NULL,
+    monthlycount bigint NOT NULL,
+    PRIMARY KEY (customeruid, currency)
+);
+
+CREATE TABLE IF NOT EXISTS limit_usage (
+    customeruid character varying(64) NOT NULL,
+    currency character varying(3) NOT NULL,
+    period character varying(8) NOT NULL,
+    periodstart date NOT NULL,
+    amount bigint NOT NULL DEFAULT 0,
+    count bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (customeruid, currency, period, periodstart)
+);
+
+CREATE TABLE IF NOT EXISTS fee_schedule (
+    version integer NOT NULL UNIQUE,
+    effectivefrom timestamp with time zone NOT NULL,
+    rules jsonb NOT NULL DEFAULT '[]',
+    createdat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX fee_schedule_effectivefrom_idx ON fee_schedule USING btree (effectivefrom);
+
+CREATE TABLE IF NOT EXISTS payment_schedule (
+    uid character varying(64) NOT NULL UNIQUE,
+    customeruid character varying(64) NOT NULL,
+    payeeuid character varying(64) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    recurrence character varying(255) NOT NULL,
+    timezone character varying(64) NOT NULL,
+    startat timestamp with time zone NOT NULL,
+    endat timestamp with time zone,
+    nextrunat timestamp with time zone NOT NULL,
+    status character varying(32) NOT NULL,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX payment_schedule_customeruid_idx ON payment_schedule USING btree (customeruid);
+
+CREATE INDEX payment_schedule_nextrunat_idx ON payment_schedule USING btree (status, nextrunat);
+
+CREATE TABLE IF NOT EXISTS schedule_execution (
+    uid character varying(64) NOT NULL UNIQUE,
+    scheduleuid character varying(64) NOT NULL,
+    dueat timestamp with time zone NOT NULL,
+    status character varying(32) NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    lasterror text NOT NULL DEFAULT '',
+    nextattemptat timestamp with time zone NOT NULL,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX schedule_execution_scheduleuid_idx ON schedule_execution USING btree (scheduleuid, dueat);
+
+CREATE INDEX schedule_execution_nextattemptat_idx ON schedule_execution USING btree (status, nextattemptat);
+
+CREATE TABLE IF NOT EXISTS subscription_plan (
+    uid character varying(64) NOT NULL UNIQUE,
+    name character varying(255) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    billinginterval character varying(32) NOT NULL,
+    intervalcount integer NOT NULL DEFAULT 1,
+    trialdays integer NOT NULL DEFAULT 0,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS subscription (
+    uid character varying(64) NOT NULL UNIQUE,
+    customeruid character varying(64) NOT NULL,
+    planuid character varying(64) NOT NULL,
+    paymentmethod character varying(255) NOT NULL,
+    status character varying(32) NOT NULL,
+    billinganchor timestamp with time zone NOT NULL,
+    currentperiodstart timestamp with time zone NOT NULL,
+    currentperiodend timestamp with time zone NOT NULL,
+    trialend timestamp with time zone,
+    cancelatperiodend boolean NOT NULL DEFAULT false,
+    pendingproration bigint NOT NULL DEFAULT 0,
+    cancelledat timestamp with time zone,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX subscription_customeruid_idx ON subscription USING btree (customeruid);
+
+CREATE INDEX subscription_currentperiodend_idx ON subscription USING btree (status, currentperiodend);
+
+CREATE TABLE IF NOT EXISTS subscription_invoice (
+    uid character varying(64) NOT NULL UNIQUE,
+    subscriptionuid character varying(64) NOT NULL,
+    customeruid character varying(64) NOT NULL,
+    periodstart timestamp with time zone NOT NULL,
+    periodend timestamp with time zone NOT NULL,
+    items jsonb NOT NULL DEFAULT '[]',
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    status character varying(32) NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    lasterror text NOT NULL DEFAULT '',
+    dueat timestamp with time zone NOT NULL,
+    nextattemptat timestamp with time zone NOT NULL,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX subscription_invoice_subscriptionuid_idx ON subscription_invoice USING btree (subscriptionuid, periodstart);
+
+CREATE INDEX subscription_invoice_nextattemptat_idx ON subscription_invoice USING btree (status, nextattemptat);
+
+CREATE TABLE IF NOT EXISTS invoice (
+    uid character varying(64) NOT NULL UNIQUE,
+    tenantuid character varying(64) NOT NULL,
+    customeruid character varying(64) NOT NULL,
+    number bigint NOT NULL DEFAULT 0,
+    status character varying(32) NOT NULL,
+    currency character varying(3) NOT NULL,
+    lines jsonb NOT NULL DEFAULT '[]',
+    subtotal bigint NOT NULL,
+    vattotal bigint NOT NULL,
+    total bigint NOT NULL,
+    duedate timestamp with time zone NOT NULL,
+    issuedat timestamp with time zone,
+    paidat timestamp with time zone,
+    voidedat timestamp with time zone,
+    riskassessmentuid character varying(64) NOT NULL DEFAULT '',
+    riskdecision character varying(16) NOT NULL DEFAULT '',
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX invoice_tenantuid_number_idx ON invoice USING btree (tenantuid, number) WHERE number > 0;
+
+CREATE INDEX invoice_customeruid_idx ON invoice USING btree (customeruid);
+
+CREATE TABLE IF NOT EXISTS invoice_sequence (
+    tenantuid character varying(64) NOT NULL UNIQUE,
+    lastnumber bigint NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS posting (
+    uid character varying(64) NOT NULL UNIQUE,
+    customeruid character varying(64) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    description text NOT NULL DEFAULT '',
+    reference character varying(255) NOT NULL DEFAULT '',
+    feescheduleversion integer NOT NULL DEFAULT 0,
+    postedat timestamp with time zone NOT NULL
+);
+
+CREATE INDEX posting_customeruid_idx ON posting USING btree (customeruid, currency, postedat);
+
+CREATE INDEX posting_reference_idx ON posting USING btree (reference);
+
+CREATE TABLE IF NOT EXISTS bank_statement (
+    uid character varying(64) NOT NULL UNIQUE,
+    bankstatementuid character varying(255) NOT NULL,
+    accountiban character varying(64) NOT NULL,
+    currency character varying(3) NOT NULL,
+    openingbalance bigint NOT NULL,
+    closingbalance bigint NOT NULL,
+    importedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS bank_entry (
+    uid character varying(64) NOT NULL UNIQUE,
+    statementuid character varying(64) NOT NULL,
+    direction character varying(32) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    bookingdate timestamp with time zone NOT NULL,
+    valuedate timestamp with time zone NOT NULL,
+    reference character varying(255) NOT NULL DEFAULT '',
+    accountservicerreference character varying(255) NOT NULL DEFAULT '',
+    remittanceinfo text NOT NULL DEFAULT '',
+    movementuid character varying(64) NOT NULL DEFAULT '',
+    matchtype character varying(32) NOT NULL DEFAULT '',
+    matchedat timestamp with time zone,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX bank_entry_bookingdate_idx ON bank_entry USING btree (bookingdate);
+
+CREATE UNIQUE INDEX bank_entry_movementuid_idx ON bank_entry USING btree (movementuid) WHERE movementuid <> '';
+
+CREATE INDEX monitored_movement_createdat_idx ON monitored_movement USING btree (createdat);
+
+CREATE TABLE IF NOT EXISTS payout (
+    uid character varying(64) NOT NULL UNIQUE,
+    customeruid character varying(64) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    creditorname character varying(140) NOT NULL,
+    creditoriban character varying(34) NOT NULL,
+    creditorbic character varying(11) NOT NULL DEFAULT '',
+    remittanceinfo character varying(140) NOT NULL DEFAULT '',
+    status character varying(32) NOT NULL,
+    batchuid character varying(64) NOT NULL DEFAULT '',
+    paymentinfouid character varying(35) NOT NULL DEFAULT '',
+    rejectreason text NOT NULL DEFAULT '',
+    beneficiaryuid character varying(64) NOT NULL DEFAULT '',
+    riskassessmentuid character varying(64) NOT NULL DEFAULT '',
+    riskdecision character varying(16) NOT NULL DEFAULT '',
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX payout_customeruid_idx ON payout USING btree (customeruid);
+
+CREATE INDEX payout_status_idx ON payout USING btree (status, createdat);
+
+CREATE INDEX payout_batchuid_idx ON payout USING btree (batchuid);
+
+CREATE TABLE IF NOT EXISTS payout_batch (
+    uid character varying(64) NOT NULL UNIQUE,
+    numberoftransactions integer NOT NULL,
+    controlsum bigint NOT NULL,
+    document text NOT NULL,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS qr_payment_request (
+    uid character varying(64) NOT NULL UNIQUE,
+    merchantuid character varying(64) NOT NULL,
+    type character varying(32) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    purpose character varying(140) NOT NULL DEFAULT '',
+    status character varying(32) NOT NULL,
+    expiresat timestamp with time zone,
+    paidat timestamp with time zone,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX qr_payment_request_merchantuid_idx ON qr_payment_request USING btree (merchantuid);
+
+CREATE TABLE IF NOT EXISTS qr_payment (
+    uid character varying(64) NOT NULL UNIQUE,
+    requestuid character varying(64) NOT NULL,
+    merchantuid character varying(64) NOT NULL,
+    payeruid character varying(64) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    riskassessmentuid character varying(64) NOT NULL DEFAULT '',
+    riskdecision character varying(16) NOT NULL DEFAULT '',
+    paidat timestamp with time zone NOT NULL
+);
+
+CREATE INDEX qr_payment_requestuid_idx ON qr_payment USING btree (requestuid, paidat);
+
+CREATE TABLE IF NOT EXISTS phone_lookup (
+    customeruid character varying(64) NOT NULL,
+    phone character varying(16) NOT NULL,
+    found boolean NOT NULL,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX phone_lookup_customeruid_idx ON phone_lookup USING btree (customeruid, createdat);
+
+CREATE TABLE IF NOT EXISTS p2p_transfer (
+    uid character varying(64) NOT NULL UNIQUE,
+    senderuid character varying(64) NOT NULL,
+    recipientuid character varying(64) NOT NULL,
+    recipientphone character varying(16) NOT NULL,
+    recipientmaskedname character varying(128) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    comment character varying(140) NOT NULL DEFAULT '',
+    beneficiaryuid character varying(64) NOT NULL DEFAULT '',
+    riskassessmentuid character varying(64) NOT NULL DEFAULT '',
+    riskdecision character varying(16) NOT NULL DEFAULT '',
+    createdat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX p2p_transfer_senderuid_idx ON p2p_transfer USING btree (senderuid, createdat);
+
+CREATE INDEX p2p_transfer_recipientuid_idx ON p2p_transfer USING btree (recipientuid, createdat);
+
+CREATE TABLE IF NOT EXISTS escrow (
+    uid character varying(64) NOT NULL UNIQUE,
+    merchantuid character varying(64) NOT NULL,
+    orderid character varying(64) NOT NULL,
+    buyeruid character varying(64) NOT NULL,
+    selleruid character varying(64) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    releasecondition character varying(32) NOT NULL,
+    expiryaction character varying(16) NOT NULL,
+    status character varying(16) NOT NULL,
+    selleramount bigint NOT NULL DEFAULT 0,
+    disputereason character varying(500) NOT NULL DEFAULT '',
+    riskassessmentuid character varying(64) NOT NULL DEFAULT '',
+    riskdecision character varying(16) NOT NULL DEFAULT '',
+    expiresat timestamp with time zone NOT NULL,
+    settledat timestamp with time zone,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX escrow_merchantuid_orderid_idx ON escrow USING btree (merchantuid, orderid);
+
+CREATE INDEX escrow_status_expiresat_idx ON escrow USING btree (status, expiresat);
+
+CREATE TABLE IF NOT EXISTS split_payment (
+    uid character varying(64) NOT NULL UNIQUE,
+    payeruid character varying(64) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    description character varying(255) NOT NULL DEFAULT '',
+    riskassessmentuid character varying(64) NOT NULL DEFAULT '',
+    riskdecision character varying(16) NOT NULL DEFAULT '',
+    createdat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS split_leg (
+    paymentuid character varying(64) NOT NULL,
+    position integer NOT NULL,
+    recipientuid character varying(64) NOT NULL,
+    role character varying(32) NOT NULL DEFAULT '',
+    percent character varying(16) NOT NULL DEFAULT '',
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (paymentuid, position)
+);
+
+CREATE INDEX split_leg_recipientuid_idx ON split_leg USING btree (recipientuid, createdat);
+
+CREATE TABLE IF NOT EXISTS dispute (
+    uid character varying(64) NOT NULL UNIQUE,
+    paymentuid character varying(64) NOT NULL,
+    merchantuid character varying(64) NOT NULL,
+    payeruid character varying(64) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    reasoncode character varying(32) NOT NULL,
+    description character varying(500) NOT NULL DEFAULT '',
+    status character varying(16) NOT NULL,
+    respondby timestamp with time zone NOT NULL,
+    resolvedat timestamp with time zone,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX dispute_paymentuid_idx ON dispute USING btree (paymentuid);
+
+CREATE INDEX dispute_merchantuid_idx ON dispute USING btree (merchantuid, createdat);
+
+CREATE INDEX dispute_status_respondby_idx ON dispute USING btree (status, respondby);
+
+CREATE TABLE IF NOT EXISTS dispute_evidence (
+    uid character varying(64) NOT NULL UNIQUE,
+    disputeuid character varying(64) NOT NULL,
+    customeruid character varying(64) NOT NULL,
+    filename character varying(255) NOT NULL,
+    contenttype character varying(128) NOT NULL,
+    size integer NOT NULL,
+    blobkey character varying(255) NOT NULL,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX dispute_evidence_disputeuid_idx ON dispute_evidence USING btree (disputeuid, createdat);
+
+CREATE TABLE IF NOT EXISTS deposit_product (
+    uid character varying(64) NOT NULL UNIQUE,
+    name character varying(128) NOT NULL,
+    type character varying(16) NOT NULL,
+    currency character varying(3) NOT NULL,
+    annualrate character varying(16) NOT NULL,
+    capitalisation character varying(16) NOT NULL,
+    daycount character varying(8) NOT NULL,
+    termmonths integer NOT NULL DEFAULT 0,
+    earlywithdrawalpenalty character varying(16) NOT NULL DEFAULT '',
+    minamount bigint NOT NULL DEFAULT 0,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS deposit (
+    uid character varying(64) NOT NULL UNIQUE,
+    productuid character varying(64) NOT NULL,
+    customeruid character varying(64) NOT NULL,
+    currency character varying(3) NOT NULL,
+    principal bigint NOT NULL,
+    accruedinterest bigint NOT NULL DEFAULT 0,
+    capitalisedinterest bigint NOT NULL DEFAULT 0,
+    status character varying(16) NOT NULL,
+    accrualstart timestamp with time zone NOT NULL,
+    accrueduntil timestamp with time zone NOT NULL,
+    openedat timestamp with time zone NOT NULL,
+    maturesat timestamp with time zone,
+    paidout bigint NOT NULL DEFAULT 0,
+    closedat timestamp with time zone,
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX deposit_customeruid_idx ON deposit USING btree (customeruid, openedat);
+
+CREATE INDEX deposit_status_accrueduntil_idx ON deposit USING btree (status, accrueduntil);
+
+CREATE TABLE IF NOT EXISTS deposit_interest_entry (
+    deposituid character varying(64) NOT NULL,
+    type character varying(16) NOT NULL,
+    amount bigint NOT NULL,
+    day timestamp with time zone NOT NULL
+);
+
+CREATE INDEX deposit_interest_entry_deposituid_idx ON deposit_interest_entry USING btree (deposituid, day);
+
+CREATE TABLE IF NOT EXISTS loan_product (
+    uid character varying(64) NOT NULL UNIQUE,
+    name character varying(128) NOT NULL,
+    currency character varying(3) NOT NULL,
+    annualrate character varying(16) NOT NULL,
+    penaltyrate character varying(16) NOT NULL,
+    minamount bigint NOT NULL,
+    maxamount bigint NOT NULL,
+    mintermmonths integer NOT NULL,
+    maxtermmonths integer NOT NULL,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS loan_application (
+    uid character varying(64) NOT NULL UNIQUE,
+    productuid character varying(64) NOT NULL,
+    customeruid character varying(64) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    termmonths integer NOT NULL,
+    amortisation character varying(16) NOT NULL,
+    status character varying(16) NOT NULL,
+    rejectionreason character varying(255) NOT NULL DEFAULT '',
+    loanuid character varying(64) NOT NULL DEFAULT '',
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX loan_application_customeruid_idx ON loan_application USING btree (customeruid, createdat);
+
+CREATE TABLE IF NOT EXISTS loan (
+    uid character varying(64) NOT NULL UNIQUE,
+    applicationuid character varying(64) NOT NULL UNIQUE,
+    productuid character varying(64) NOT NULL,
+    customeruid character varying(64) NOT NULL,
+    currency character varying(3) NOT NULL,
+    principal bigint NOT NULL,
+    outstanding bigint NOT NULL,
+    annualrate character varying(16) NOT NULL,
+    penaltyrate character varying(16) NOT NULL,
+    amortisation character varying(16) NOT NULL,
+    termmonths integer NOT NULL,
+    status character varying(16) NOT NULL,
+    penalty bigint NOT NULL DEFAULT 0,
+    penaltyaccrueduntil timestamp with time zone NOT NULL,
+    interestpaiduntil timestamp with time zone,
+    nextserviceday timestamp with time zone NOT NULL,
+    repayments integer NOT NULL DEFAULT 0,
+    disbursedat timestamp with time zone NOT NULL,
+    repaidat timestamp with time zone,
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX loan_customeruid_idx ON loan USING btree (customeruid, disbursedat);
+
+CREATE INDEX loan_status_nextserviceday_idx ON loan USING btree (status, nextserviceday);
+
+CREATE TABLE IF NOT EXISTS loan_installment (
+    loanuid character varying(64) NOT NULL,
+    number integer NOT NULL,
+    dueday timestamp with time zone NOT NULL,
+    principal bigint NOT NULL,
+    interest bigint NOT NULL,
+    principalpaid bigint NOT NULL DEFAULT 0,
+    interestpaid bigint NOT NULL DEFAULT 0,
+    status character varying(16) NOT NULL,
+    paidat timestamp with time zone,
+    PRIMARY KEY (loanuid, number)
+);
+
+CREATE TABLE IF NOT EXISTS loan_repayment (
+    loanuid character varying(64) NOT NULL,
+    number integer NOT NULL,
+    type character varying(16) NOT NULL,
+    amount bigint NOT NULL,
+    penalty bigint NOT NULL,
+    interest bigint NOT NULL,
+    principal bigint NOT NULL,
+    paidat timestamp with time zone NOT NULL,
+    PRIMARY KEY (loanuid, number)
+);
+
+CREATE TABLE IF NOT EXISTS credit_line (
+    uid character varying(64) NOT NULL UNIQUE,
+    customeruid character varying(64) NOT NULL,
+    currency character varying(3) NOT NULL,
+    limitamount bigint NOT NULL,
+    annualrate character varying(16) NOT NULL,
+    accruedinterest bigint NOT NULL DEFAULT 0,
+    chargedinterest bigint NOT NULL DEFAULT 0,
+    charges integer NOT NULL DEFAULT 0,
+    accrueduntil timestamp with time zone NOT NULL,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX credit_line_customeruid_currency_idx ON credit_line USING btree (customeruid, currency);
+
+CREATE INDEX credit_line_accrueduntil_idx ON credit_line USING btree (accrueduntil);
+
+CREATE TABLE IF NOT EXISTS hold (
+    uid character varying(64) NOT NULL UNIQUE,
+    customeruid character varying(64) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    reference character varying(255) NOT NULL DEFAULT '',
+    status character varying(16) NOT NULL,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    releasedat timestamp with time zone
+);
+
+CREATE INDEX hold_customeruid_status_idx ON hold USING btree (customeruid, currency, status);
+
+CREATE TABLE IF NOT EXISTS card (
+    uid character varying(64) NOT NULL UNIQUE,
+    customeruid character varying(64) NOT NULL,
+    currency character varying(3) NOT NULL,
+    maskedpan character varying(19) NOT NULL,
+    panfingerprint character varying(64) NOT NULL UNIQUE,
+    expirymonth smallint NOT NULL,
+    expiryyear smallint NOT NULL,
+    status character varying(16) NOT NULL,
+    blockedmccs jsonb NOT NULL DEFAULT '[]',
+    pertransactionlimit bigint NOT NULL DEFAULT 0,
+    dailylimit bigint NOT NULL DEFAULT 0,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX card_customeruid_idx ON card USING btree (customeruid);
+
+CREATE TABLE IF NOT EXISTS card_authorization (
+    uid character varying(64) NOT NULL UNIQUE,
+    carduid character varying(64) NOT NULL,
+    customeruid character varying(64) NOT NULL,
+    amount bigint NOT NULL,
+    currency character varying(3) NOT NULL,
+    mcc character varying(4) NOT NULL,
+    merchantname character varying(255) NOT NULL DEFAULT '',
+    status character varying(16) NOT NULL,
+    declinereason character varying(32) NOT NULL DEFAULT '',
+    networkreference character varying(64) NOT NULL DEFAULT '',
+    riskassessmentuid character varying(64) NOT NULL DEFAULT '',
+    riskdecision character varying(16) NOT NULL DEFAULT '',
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX card_authorization_carduid_createdat_idx ON card_authorization USING btree (carduid, createdat);
+
+CREATE UNIQUE INDEX card_authorization_networkreference_idx ON card_authorization USING btree (networkreference)
+    WHERE networkreference <> '';
+
+CREATE TABLE IF NOT EXISTS beneficiary (
+    uid character varying(64) NOT NULL UNIQUE,
+    customeruid character varying(64) NOT NULL,
+    type character varying(16) NOT NULL,
+    nickname character varying(64) NOT NULL,
+    recipientuid character varying(64) NOT NULL DEFAULT '',
+    phone character varying(16) NOT NULL DEFAULT '',
+    accountname character varying(140) NOT NULL DEFAULT '',
+    iban character varying(34) NOT NULL DEFAULT '',
+    bic character varying(11) NOT NULL DEFAULT '',
+    payeekey character varying(128) NOT NULL,
+    trustedat timestamp with time zone NOT NULL,
+    createdat timestamp with time zone NOT NULL DEFAULT NOW(),
+    updatedat timestamp with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX beneficiary_customeruid_payeekey_idx ON beneficiary USING btree (customeruid, payeekey);
@@ -32,7 +32,7 @@ func TestCreate(t *testing.T) {
 	var requestBody = []byte(`{
 		"first_name": "foo",
 		"last_name": "too",
-		"phone": "+7993",
+		"phone": "+79931234567",
 		"address": {
 			"country": "R",
 			"region": "R",
@@ -107,7 +107,7 @@ func TestUpdate(t *testing.T) {
 	var requestBody = []byte(`{
 		"first_name": "foo2",
 		"last_name": "too2",
-		"phone": "+79932",
+		"phone": "+79931234568",
 		"address": {
 			"country": "R2",
 			"region": "R2",